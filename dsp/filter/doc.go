@@ -0,0 +1,17 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package filter provides functions for the design and application of
+// digital filters.
+//
+// IIR filters are designed from analog Butterworth, Chebyshev and elliptic
+// prototypes by frequency transformation and the bilinear transform, and
+// are returned as cascades of second-order sections, which are less
+// sensitive to rounding errors than a single high-order transfer function.
+// FIR filters are designed by the window method or by the Parks-McClellan
+// algorithm.
+//
+// Frequencies are specified in the same units as the sample rate, and
+// must lie between zero and the Nyquist frequency, half the sample rate.
+package filter // import "gonum.org/v1/gonum/dsp/filter"
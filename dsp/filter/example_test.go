@@ -0,0 +1,102 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter_test
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/dsp/filter"
+)
+
+func ExampleButterworth() {
+	const fs = 1000.0
+
+	// Design a 4th order lowpass filter with a cutoff at 50 Hz.
+	s := filter.Butterworth(4, filter.Lowpass, fs, 50)
+	for _, f := range []float64{10, 50, 200} {
+		h := s.FrequencyResponse(nil, []float64{f}, fs)[0]
+		gain := 20 * math.Log10(math.Hypot(real(h), imag(h)))
+		fmt.Printf("gain at %3v Hz: %6.2f dB\n", f, gain)
+	}
+
+	// Remove a 200 Hz tone from a 5 Hz signal, processing the
+	// signal in blocks of 100 samples.
+	x := make([]float64, 1000)
+	for i := range x {
+		tm := float64(i) / fs
+		x[i] = math.Sin(2*math.Pi*5*tm) + math.Sin(2*math.Pi*200*tm)
+	}
+	f := filter.NewSOSFilter(s)
+	y := make([]float64, len(x))
+	for i := 0; i < len(x); i += 100 {
+		f.Process(y[i:i+100], x[i:i+100])
+	}
+	var amp float64
+	for _, v := range y[500:] {
+		amp = math.Max(amp, math.Abs(v))
+	}
+	fmt.Printf("output amplitude: %.2f\n", amp)
+
+	// Output:
+	// gain at  10 Hz:  -0.00 dB
+	// gain at  50 Hz:  -3.01 dB
+	// gain at 200 Hz: -52.92 dB
+	// output amplitude: 1.00
+}
+
+func ExampleSOSFiltFilt() {
+	const fs = 100.0
+
+	// Smooth a noisy step without shifting the location of the step.
+	x := make([]float64, 200)
+	for i := range x {
+		if i >= 100 {
+			x[i] = 1
+		}
+		x[i] += 0.1 * math.Sin(float64(i)*2.5)
+	}
+	s := filter.Butterworth(2, filter.Lowpass, fs, 5)
+	y := filter.SOSFiltFilt(nil, x, s)
+	for _, i := range []int{50, 95, 100, 105, 150} {
+		fmt.Printf("y[%d] = %.2f\n", i, y[i])
+	}
+
+	// Output:
+	// y[50] = 0.00
+	// y[95] = 0.10
+	// y[100] = 0.55
+	// y[105] = 0.95
+	// y[150] = 1.00
+}
+
+func ExampleParksMcClellan() {
+	const fs = 48000.0
+
+	// Design a lowpass filter with a passband to 8 kHz and
+	// a stopband from 10 kHz, with the stopband error weighted
+	// ten times more heavily than the passband error.
+	h, err := filter.ParksMcClellan(61,
+		[]float64{0, 8000, 10000, fs / 2},
+		[]float64{1, 0},
+		[]float64{1, 10},
+		fs,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, f := range []float64{0, 8000, 10000, 16000} {
+		r := filter.FrequencyResponse(nil, h, []float64{1}, []float64{f}, fs)[0]
+		gain := 20 * math.Log10(math.Hypot(real(r), imag(r)))
+		fmt.Printf("gain at %5v Hz: %6.1f dB\n", f, gain)
+	}
+
+	// Output:
+	// gain at     0 Hz:    0.0 dB
+	// gain at  8000 Hz:   -0.1 dB
+	// gain at 10000 Hz:  -59.2 dB
+	// gain at 16000 Hz:  -64.5 dB
+}
@@ -0,0 +1,369 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Section is a second-order section (biquad) of a digital filter with the
+// transfer function
+//
+//	H(z) = (B[0] + B[1] z⁻¹ + B[2] z⁻²) / (A[0] + A[1] z⁻¹ + A[2] z⁻²).
+type Section struct {
+	B, A [3]float64
+}
+
+// SOS is a digital filter formed by a cascade of second-order sections.
+type SOS []Section
+
+// TransferFunction returns the numerator and denominator coefficients of
+// the transfer function of the filter in increasing powers of z⁻¹.
+func (s SOS) TransferFunction() (b, a []float64) {
+	b = []float64{1}
+	a = []float64{1}
+	for _, sec := range s {
+		b = polyMul(b, sec.B[:])
+		a = polyMul(a, sec.A[:])
+	}
+	return b, a
+}
+
+// FrequencyResponse computes the complex frequency response of the filter
+// at the frequencies in freqs for the sample rate fs, placing the result in
+// dst and returning it.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of freqs,
+// FrequencyResponse will panic.
+func (s SOS) FrequencyResponse(dst []complex128, freqs []float64, fs float64) []complex128 {
+	dst = useResponse(dst, len(freqs))
+	for i, f := range freqs {
+		zi := cmplx.Exp(complex(0, -2*math.Pi*f/fs))
+		h := complex(1, 0)
+		for _, sec := range s {
+			h *= polyVal(sec.B[:], zi) / polyVal(sec.A[:], zi)
+		}
+		dst[i] = h
+	}
+	return dst
+}
+
+// FrequencyResponse computes the complex frequency response of the filter
+// with the transfer function coefficients b and a, in increasing powers of
+// z⁻¹, at the frequencies in freqs for the sample rate fs, placing the
+// result in dst and returning it. An FIR filter has a single denominator
+// coefficient of 1.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of freqs,
+// FrequencyResponse will panic.
+func FrequencyResponse(dst []complex128, b, a, freqs []float64, fs float64) []complex128 {
+	dst = useResponse(dst, len(freqs))
+	for i, f := range freqs {
+		zi := cmplx.Exp(complex(0, -2*math.Pi*f/fs))
+		dst[i] = polyVal(b, zi) / polyVal(a, zi)
+	}
+	return dst
+}
+
+func useResponse(dst []complex128, n int) []complex128 {
+	if dst == nil {
+		return make([]complex128, n)
+	}
+	if len(dst) != n {
+		panic("filter: destination length mismatch")
+	}
+	return dst
+}
+
+// polyVal evaluates the polynomial with the coefficients c in increasing
+// powers of x.
+func polyVal(c []float64, x complex128) complex128 {
+	var v complex128
+	for i := len(c) - 1; i >= 0; i-- {
+		v = v*x + complex(c[i], 0)
+	}
+	return v
+}
+
+// polyMul returns the product of the polynomials p and q.
+func polyMul(p, q []float64) []float64 {
+	r := make([]float64, len(p)+len(q)-1)
+	for i, a := range p {
+		for j, b := range q {
+			r[i+j] += a * b
+		}
+	}
+	return r
+}
+
+// LFilter is a streaming linear filter with the transfer function
+//
+//	H(z) = (b[0] + b[1] z⁻¹ + ... + b[n] z⁻ⁿ) / (a[0] + a[1] z⁻¹ + ... + a[n] z⁻ⁿ),
+//
+// implemented in transposed direct form II. The state of the filter is
+// retained between calls to Process, so a long signal may be filtered in
+// blocks.
+type LFilter struct {
+	b, a []float64
+	z    []float64
+}
+
+// NewLFilter returns a new LFilter with the numerator coefficients b and
+// the denominator coefficients a in increasing powers of z⁻¹, and with a
+// zero initial state. An FIR filter has a = []float64{1}.
+//
+// NewLFilter panics if b or a are empty, or if a[0] is zero.
+func NewLFilter(b, a []float64) *LFilter {
+	if len(b) == 0 || len(a) == 0 {
+		panic("filter: empty coefficients")
+	}
+	if a[0] == 0 {
+		panic("filter: zero leading denominator coefficient")
+	}
+	n := len(b)
+	if len(a) > n {
+		n = len(a)
+	}
+	f := &LFilter{
+		b: make([]float64, n),
+		a: make([]float64, n),
+		z: make([]float64, n-1),
+	}
+	for i, v := range b {
+		f.b[i] = v / a[0]
+	}
+	for i, v := range a {
+		f.a[i] = v / a[0]
+	}
+	return f
+}
+
+// Process filters the samples in x, placing the result in dst and
+// returning it. dst and x may be the same slice.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of x, Process will
+// panic.
+func (f *LFilter) Process(dst, x []float64) []float64 {
+	dst = useSamples(dst, len(x))
+	b, a, z := f.b, f.a, f.z
+	n := len(z)
+	for i, v := range x {
+		y := b[0] * v
+		if n > 0 {
+			y += z[0]
+		}
+		for k := 0; k < n-1; k++ {
+			z[k] = b[k+1]*v - a[k+1]*y + z[k+1]
+		}
+		if n > 0 {
+			z[n-1] = b[n]*v - a[n]*y
+		}
+		dst[i] = y
+	}
+	return dst
+}
+
+// Reset sets the state of the filter to zero.
+func (f *LFilter) Reset() {
+	for i := range f.z {
+		f.z[i] = 0
+	}
+}
+
+// SetSteadyState sets the state of the filter to the steady state for a
+// constant input with the value x, so that filtering a constant signal
+// with the value x produces no transient.
+//
+// SetSteadyState panics if the filter has infinite gain at zero frequency.
+func (f *LFilter) SetSteadyState(x float64) {
+	var sb, sa float64
+	for i := range f.b {
+		sb += f.b[i]
+		sa += f.a[i]
+	}
+	if sa == 0 {
+		panic("filter: infinite gain at zero frequency")
+	}
+	y := x * sb / sa
+	var acc float64
+	for k := len(f.z) - 1; k >= 0; k-- {
+		acc += f.b[k+1]*x - f.a[k+1]*y
+		f.z[k] = acc
+	}
+}
+
+// SOSFilter is a streaming filter formed by a cascade of second-order
+// sections, each implemented in transposed direct form II. The state of
+// the filter is retained between calls to Process, so a long signal may be
+// filtered in blocks.
+type SOSFilter struct {
+	s SOS
+	z [][2]float64
+}
+
+// NewSOSFilter returns a new SOSFilter for the sections in s with a zero
+// initial state.
+//
+// NewSOSFilter panics if s is empty or if the leading denominator
+// coefficient of a section is zero.
+func NewSOSFilter(s SOS) *SOSFilter {
+	if len(s) == 0 {
+		panic("filter: no sections")
+	}
+	f := &SOSFilter{
+		s: make(SOS, len(s)),
+		z: make([][2]float64, len(s)),
+	}
+	for i, sec := range s {
+		a0 := sec.A[0]
+		if a0 == 0 {
+			panic("filter: zero leading denominator coefficient")
+		}
+		for j := range sec.B {
+			f.s[i].B[j] = sec.B[j] / a0
+			f.s[i].A[j] = sec.A[j] / a0
+		}
+	}
+	return f
+}
+
+// Process filters the samples in x, placing the result in dst and
+// returning it. dst and x may be the same slice.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of x, Process will
+// panic.
+func (f *SOSFilter) Process(dst, x []float64) []float64 {
+	dst = useSamples(dst, len(x))
+	for i, v := range x {
+		for j := range f.s {
+			b, a := &f.s[j].B, &f.s[j].A
+			z := &f.z[j]
+			y := b[0]*v + z[0]
+			z[0] = b[1]*v - a[1]*y + z[1]
+			z[1] = b[2]*v - a[2]*y
+			v = y
+		}
+		dst[i] = v
+	}
+	return dst
+}
+
+// Reset sets the state of the filter to zero.
+func (f *SOSFilter) Reset() {
+	for i := range f.z {
+		f.z[i] = [2]float64{}
+	}
+}
+
+// SetSteadyState sets the state of the filter to the steady state for a
+// constant input with the value x, so that filtering a constant signal
+// with the value x produces no transient.
+//
+// SetSteadyState panics if a section has infinite gain at zero frequency.
+func (f *SOSFilter) SetSteadyState(x float64) {
+	for i, sec := range f.s {
+		b, a := sec.B, sec.A
+		sa := a[0] + a[1] + a[2]
+		if sa == 0 {
+			panic("filter: infinite gain at zero frequency")
+		}
+		y := x * (b[0] + b[1] + b[2]) / sa
+		f.z[i][1] = b[2]*x - a[2]*y
+		f.z[i][0] = b[1]*x - a[1]*y + f.z[i][1]
+		x = y
+	}
+}
+
+func useSamples(dst []float64, n int) []float64 {
+	if dst == nil {
+		return make([]float64, n)
+	}
+	if len(dst) != n {
+		panic("filter: destination length mismatch")
+	}
+	return dst
+}
+
+// steadyStater is a streaming filter that can be initialized to a steady
+// state.
+type steadyStater interface {
+	Process(dst, x []float64) []float64
+	SetSteadyState(x float64)
+}
+
+// FiltFilt applies the filter with the transfer function coefficients b
+// and a to x forwards and then backwards, placing the result in dst and
+// returning it. The result has zero phase distortion and a magnitude
+// response that is the square of the magnitude response of the filter.
+//
+// The signal is extended at both ends by odd reflection about the end
+// points by 3*max(len(b), len(a)) samples, or by len(x)-1 samples if x is
+// shorter, and the filter is started from the steady state for the first
+// sample in each direction to reduce transients.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of x, FiltFilt will
+// panic.
+func FiltFilt(dst, x, b, a []float64) []float64 {
+	n := len(b)
+	if len(a) > n {
+		n = len(a)
+	}
+	return filtfilt(dst, x, NewLFilter(b, a), 3*n)
+}
+
+// SOSFiltFilt applies the filter s to x forwards and then backwards,
+// placing the result in dst and returning it. The result has zero phase
+// distortion and a magnitude response that is the square of the magnitude
+// response of the filter.
+//
+// The signal is extended at both ends by odd reflection about the end
+// points by 3*(2*len(s)+1) samples, or by len(x)-1 samples if x is
+// shorter, and the filter is started from the steady state for the first
+// sample in each direction to reduce transients.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of x, SOSFiltFilt will
+// panic.
+func SOSFiltFilt(dst, x []float64, s SOS) []float64 {
+	return filtfilt(dst, x, NewSOSFilter(s), 3*(2*len(s)+1))
+}
+
+func filtfilt(dst, x []float64, f steadyStater, pad int) []float64 {
+	dst = useSamples(dst, len(x))
+	n := len(x)
+	if n == 0 {
+		return dst
+	}
+	if pad > n-1 {
+		pad = n - 1
+	}
+	ext := make([]float64, n+2*pad)
+	for i := 0; i < pad; i++ {
+		ext[i] = 2*x[0] - x[pad-i]
+		ext[pad+n+i] = 2*x[n-1] - x[n-2-i]
+	}
+	copy(ext[pad:], x)
+
+	f.SetSteadyState(ext[0])
+	f.Process(ext, ext)
+	reverse(ext)
+	f.SetSteadyState(ext[0])
+	f.Process(ext, ext)
+	reverse(ext)
+	copy(dst, ext[pad:pad+n])
+	return dst
+}
+
+func reverse(x []float64) {
+	for i, j := 0, len(x)-1; i < j; i, j = i+1, j-1 {
+		x[i], x[j] = x[j], x[i]
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestLFilter(t *testing.T) {
+	t.Parallel()
+	// The impulse response of an FIR filter is its coefficients.
+	b := []float64{1, -2, 3, 0.5}
+	x := make([]float64, 6)
+	x[0] = 1
+	got := NewLFilter(b, []float64{1}).Process(nil, x)
+	want := []float64{1, -2, 3, 0.5, 0, 0}
+	if !floats.Equal(got, want) {
+		t.Errorf("unexpected FIR impulse response: got %v, want %v", got, want)
+	}
+
+	// y[n] = x[n] + 0.5 y[n-1], with a[0] != 1.
+	got = NewLFilter([]float64{2}, []float64{2, -1}).Process(nil, x)
+	want = []float64{1, 0.5, 0.25, 0.125, 0.0625, 0.03125}
+	if !floats.EqualApprox(got, want, 1e-15) {
+		t.Errorf("unexpected IIR impulse response: got %v, want %v", got, want)
+	}
+}
+
+func TestFilterStreaming(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, 500)
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+	}
+	s := Elliptic(5, 0.5, 50, Bandpass, 1, 0.1, 0.2)
+	b, a := s.TransferFunction()
+
+	whole := NewSOSFilter(s).Process(nil, x)
+	tf := NewLFilter(b, a).Process(nil, x)
+	if !floats.EqualApprox(whole, tf, 1e-8) {
+		t.Errorf("second-order sections and transfer function differ")
+	}
+
+	// Filtering in blocks of varying size gives the same result as
+	// filtering in one call.
+	sf := NewSOSFilter(s)
+	lf := NewLFilter(b, a)
+	blockS := make([]float64, len(x))
+	blockL := make([]float64, len(x))
+	for i := 0; i < len(x); {
+		n := rnd.Intn(50) + 1
+		if i+n > len(x) {
+			n = len(x) - i
+		}
+		sf.Process(blockS[i:i+n], x[i:i+n])
+		copy(blockL[i:i+n], x[i:i+n])
+		lf.Process(blockL[i:i+n], blockL[i:i+n])
+		i += n
+	}
+	if !floats.Equal(blockS, whole) {
+		t.Errorf("block processing with SOSFilter differs")
+	}
+	if !floats.Equal(blockL, tf) {
+		t.Errorf("block processing with LFilter differs")
+	}
+
+	// Reset returns the filters to the initial state.
+	sf.Reset()
+	lf.Reset()
+	if !floats.Equal(sf.Process(nil, x), whole) || !floats.Equal(lf.Process(nil, x), tf) {
+		t.Errorf("filters not reset")
+	}
+}
+
+func TestSetSteadyState(t *testing.T) {
+	t.Parallel()
+	const v = 2.5
+	x := make([]float64, 50)
+	for i := range x {
+		x[i] = v
+	}
+	s := ChebyshevI(4, 1, Lowpass, 1, 0.1)
+	gain := cmplx.Abs(s.FrequencyResponse(nil, []float64{0}, 1)[0])
+	b, a := s.TransferFunction()
+	for _, f := range []interface {
+		Process(dst, x []float64) []float64
+		SetSteadyState(float64)
+	}{
+		NewSOSFilter(s),
+		NewLFilter(b, a),
+	} {
+		f.SetSteadyState(v)
+		y := f.Process(nil, x)
+		for i, got := range y {
+			if !scalar.EqualWithinAbs(got, v*gain, 1e-10) {
+				t.Errorf("%T: unexpected output at %d: got %v, want %v", f, i, got, v*gain)
+				break
+			}
+		}
+	}
+}
+
+func TestFiltFilt(t *testing.T) {
+	t.Parallel()
+	const (
+		fs = 1000.0
+		n  = 2000
+	)
+	// A slow sine in the passband with a fast sine in the stopband.
+	x := make([]float64, n)
+	want := make([]float64, n)
+	for i := range x {
+		tm := float64(i) / fs
+		want[i] = math.Sin(2 * math.Pi * 5 * tm)
+		x[i] = want[i] + 0.5*math.Sin(2*math.Pi*200*tm)
+	}
+	s := Butterworth(4, Lowpass, fs, 50)
+	b, a := s.TransferFunction()
+	h := FIRWindow(101, Lowpass, fs, nil, 50)
+	for _, test := range []struct {
+		name string
+		y    []float64
+	}{
+		{name: "SOSFiltFilt", y: SOSFiltFilt(nil, x, s)},
+		{name: "FiltFilt", y: FiltFilt(nil, x, b, a)},
+		{name: "FIR", y: FiltFilt(nil, x, h, []float64{1})},
+	} {
+		// The result is not delayed with respect to the input.
+		for i := 200; i < n-200; i++ {
+			if !scalar.EqualWithinAbs(test.y[i], want[i], 5e-3) {
+				t.Errorf("%s: unexpected value at %d: got %v, want %v", test.name, i, test.y[i], want[i])
+				break
+			}
+		}
+	}
+
+	// Short signals are padded by fewer samples.
+	y := SOSFiltFilt(nil, []float64{1, 2, 3}, s)
+	if len(y) != 3 {
+		t.Errorf("unexpected length for short signal: %d", len(y))
+	}
+	if len(SOSFiltFilt(nil, nil, s)) != 0 {
+		t.Errorf("unexpected result for empty signal")
+	}
+}
+
+func TestFrequencyResponse(t *testing.T) {
+	t.Parallel()
+	s := Elliptic(6, 1, 40, Bandstop, 100, 10, 20)
+	b, a := s.TransferFunction()
+	freqs := []float64{0, 5, 10, 15, 20, 30, 50}
+	got := FrequencyResponse(nil, b, a, freqs, 100)
+	want := s.FrequencyResponse(nil, freqs, 100)
+	for i := range got {
+		if cmplx.Abs(got[i]-want[i]) > 1e-8 {
+			t.Errorf("unexpected response at %v: got %v, want %v", freqs[i], got[i], want[i])
+		}
+	}
+}
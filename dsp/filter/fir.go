@@ -0,0 +1,412 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/dsp/window"
+)
+
+// ErrNoConvergence is returned by ParksMcClellan when the exchange
+// algorithm fails to converge.
+var ErrNoConvergence = errors.New("filter: Parks-McClellan algorithm did not converge")
+
+// FIRWindow returns the coefficients of a linear phase FIR filter with the
+// given number of taps designed by the window method. The ideal impulse
+// response of the band type is truncated to the number of taps, multiplied
+// by the window function win and scaled to unit gain at zero frequency for
+// Lowpass and Bandstop filters, at the Nyquist frequency for Highpass
+// filters and at the center of the passband for Bandpass filters. If win is
+// nil, window.Hamming is used. The window functions in the dsp/window
+// package can be used for win.
+//
+// The cutoff frequencies are the edges of the passband, specified as
+// described for Butterworth.
+//
+// FIRWindow panics if taps is not positive, if the cutoffs are not valid
+// for the band type, or if taps is even for Highpass and Bandstop filters,
+// which have a zero response at the Nyquist frequency when taps is even.
+func FIRWindow(taps int, band Band, fs float64, win func([]float64) []float64, cutoff ...float64) []float64 {
+	if taps < 1 {
+		panic("filter: number of taps must be positive")
+	}
+	checkCutoff(band, fs, cutoff)
+	if (band == Highpass || band == Bandstop) && taps%2 == 0 {
+		panic("filter: even number of taps for highpass or bandstop filter")
+	}
+	if win == nil {
+		win = window.Hamming
+	}
+
+	m := float64(taps-1) / 2
+	h := make([]float64, taps)
+	// lowpass adds the ideal lowpass response with the cutoff f,
+	// multiplied by sign, to h.
+	lowpass := func(f, sign float64) {
+		fc := 2 * f / fs
+		for i := range h {
+			h[i] += sign * fc * sinc(fc*(float64(i)-m))
+		}
+	}
+	var center float64
+	switch band {
+	case Lowpass:
+		lowpass(cutoff[0], 1)
+	case Highpass:
+		h[taps/2] = 1
+		lowpass(cutoff[0], -1)
+		center = fs / 2
+	case Bandpass:
+		lowpass(cutoff[1], 1)
+		lowpass(cutoff[0], -1)
+		center = (cutoff[0] + cutoff[1]) / 2
+	case Bandstop:
+		h[taps/2] = 1
+		lowpass(cutoff[1], -1)
+		lowpass(cutoff[0], 1)
+	}
+	if taps > 1 {
+		win(h)
+	}
+
+	var gain float64
+	for i, v := range h {
+		gain += v * math.Cos(2*math.Pi*center/fs*(float64(i)-m))
+	}
+	for i := range h {
+		h[i] /= gain
+	}
+	return h
+}
+
+// sinc returns sin(πx)/(πx).
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// ParksMcClellan returns the coefficients of a linear phase FIR filter with
+// the given number of taps that minimizes the maximum weighted deviation
+// from the desired piecewise constant magnitude response, designed by the
+// Parks-McClellan algorithm using the Remez exchange algorithm.
+//
+// The bands are specified as pairs of increasing band edges in the units
+// of the sample rate fs, between 0 and fs/2. The desired response and the
+// weight in band i, between bands[2*i] and bands[2*i+1], are desired[i] and
+// weight[i]. Frequencies outside the bands are transition bands where the
+// response is not constrained. If weight is nil, all bands have unit
+// weight. Filters with an even number of taps have a zero response at
+// fs/2.
+//
+// If the exchange algorithm does not converge, ParksMcClellan returns the
+// last approximation with ErrNoConvergence.
+//
+// ParksMcClellan panics if taps is less than 3, if the lengths of bands,
+// desired and weight do not match, if the band edges are not strictly
+// increasing in [0, fs/2], or if a weight is not positive.
+//
+// References:
+//   - McClellan, J.H., Parks, T.W., Rabiner, L.R. (1973). A computer
+//     program for designing optimum FIR linear phase digital filters.
+//     IEEE Transactions on Audio and Electroacoustics 21(6), 506-526.
+func ParksMcClellan(taps int, bands, desired, weight []float64, fs float64) ([]float64, error) {
+	if taps < 3 {
+		panic("filter: number of taps less than 3")
+	}
+	if len(bands) == 0 || len(bands)%2 != 0 || len(desired) != len(bands)/2 {
+		panic("filter: band specification length mismatch")
+	}
+	if weight != nil && len(weight) != len(desired) {
+		panic("filter: band weight length mismatch")
+	}
+	if !(fs > 0) {
+		panic("filter: sample rate must be positive")
+	}
+	for i, f := range bands {
+		if f < 0 || fs/2 < f || (i > 0 && f <= bands[i-1]) {
+			panic("filter: invalid band edges")
+		}
+	}
+	for _, w := range weight {
+		if !(w > 0) {
+			panic("filter: weight must be positive")
+		}
+	}
+
+	const density = 16
+	odd := taps%2 == 1
+	r := taps / 2
+	if odd {
+		r++
+	}
+
+	// Construct the dense frequency grid in cycles per sample. For an even
+	// number of taps the response is A(f) = cos(πf) P(f), and P is
+	// approximated with a modified desired response and weight.
+	delta := 0.5 / float64(density*r)
+	var grid, des, wt []float64
+	var bandEnd []int
+	for i := 0; i < len(desired); i++ {
+		lo := bands[2*i] / fs
+		hi := bands[2*i+1] / fs
+		if !odd && hi > 0.5-delta {
+			hi = 0.5 - delta
+		}
+		if hi < lo {
+			continue
+		}
+		n := int(math.Ceil((hi-lo)/delta)) + 1
+		w := 1.0
+		if weight != nil {
+			w = weight[i]
+		}
+		for j := 0; j < n; j++ {
+			f := lo
+			if n > 1 {
+				f = lo + (hi-lo)*float64(j)/float64(n-1)
+			}
+			q := 1.0
+			if !odd {
+				q = math.Cos(math.Pi * f)
+			}
+			grid = append(grid, f)
+			des = append(des, desired[i]/q)
+			wt = append(wt, w*q)
+		}
+		bandEnd = append(bandEnd, len(grid))
+	}
+	if len(grid) <= r {
+		panic("filter: bands too narrow for the number of taps")
+	}
+
+	ext := make([]int, r+1)
+	for i := range ext {
+		ext[i] = i * (len(grid) - 1) / r
+	}
+	dev := make([]float64, len(grid))
+	var b remezInterp
+	converged := false
+	for iter := 0; iter < 100; iter++ {
+		b.set(grid, des, wt, ext)
+		for j, f := range grid {
+			dev[j] = wt[j] * (des[j] - b.eval(math.Cos(2*math.Pi*f)))
+		}
+		for l, j := range ext {
+			dev[j] = b.sign(l) * b.delta
+		}
+		next := remezExtrema(dev, bandEnd, r+1, math.Abs(b.delta))
+		if next == nil {
+			break
+		}
+		same := true
+		for i := range ext {
+			if ext[i] != next[i] {
+				same = false
+				break
+			}
+		}
+		ext = next
+		if same {
+			converged = true
+			break
+		}
+	}
+	b.set(grid, des, wt, ext)
+
+	// Compute the impulse response from samples of the amplitude response.
+	m := float64(taps-1) / 2
+	amp := make([]float64, (taps-1)/2+1)
+	for k := range amp {
+		f := float64(k) / float64(taps)
+		amp[k] = b.eval(math.Cos(2 * math.Pi * f))
+		if !odd {
+			amp[k] *= math.Cos(math.Pi * f)
+		}
+	}
+	h := make([]float64, taps)
+	for n := range h {
+		v := amp[0]
+		for k := 1; k < len(amp); k++ {
+			v += 2 * amp[k] * math.Cos(2*math.Pi*float64(k)*(float64(n)-m)/float64(taps))
+		}
+		h[n] = v / float64(taps)
+	}
+	if !converged {
+		return h, ErrNoConvergence
+	}
+	return h, nil
+}
+
+// remezInterp is the polynomial that alternates about the desired
+// response with the deviation delta at the extremal frequencies, in
+// barycentric form in the variable x = cos(2πf).
+type remezInterp struct {
+	x, y, w []float64
+	delta   float64
+}
+
+func (b *remezInterp) sign(i int) float64 {
+	if i%2 == 0 {
+		return 1
+	}
+	return -1
+}
+
+func (b *remezInterp) set(grid, des, wt []float64, ext []int) {
+	n := len(ext)
+	b.x = resize(b.x, n)
+	b.y = resize(b.y, n)
+	b.w = resize(b.w, n)
+	for i, j := range ext {
+		b.x[i] = math.Cos(2 * math.Pi * grid[j])
+	}
+	// The barycentric weights are only needed up to a common factor,
+	// so they are computed from their logarithms to avoid overflow.
+	logs := make([]float64, n)
+	signs := make([]float64, n)
+	maxLog := math.Inf(-1)
+	for i := range b.x {
+		s := 1.0
+		var l float64
+		for j := range b.x {
+			if j == i {
+				continue
+			}
+			d := b.x[i] - b.x[j]
+			if d < 0 {
+				s = -s
+			}
+			l -= math.Log(math.Abs(d))
+		}
+		logs[i] = l
+		signs[i] = s
+		maxLog = math.Max(maxLog, l)
+	}
+	var num, den float64
+	for i, j := range ext {
+		b.w[i] = signs[i] * math.Exp(logs[i]-maxLog)
+		num += b.w[i] * des[j]
+		den += b.w[i] * b.sign(i) / wt[j]
+	}
+	b.delta = num / den
+	for i, j := range ext {
+		b.y[i] = des[j] - b.sign(i)*b.delta/wt[j]
+	}
+}
+
+func (b *remezInterp) eval(x float64) float64 {
+	var num, den float64
+	for i, xi := range b.x {
+		d := x - xi
+		if math.Abs(d) < 1e-15 {
+			return b.y[i]
+		}
+		c := b.w[i] / d
+		num += c * b.y[i]
+		den += c
+	}
+	return num / den
+}
+
+func resize(s []float64, n int) []float64 {
+	if cap(s) < n {
+		return make([]float64, n)
+	}
+	return s[:n]
+}
+
+// remezExtrema returns the indices of n alternating extrema of the error
+// function dev, where the bands of the grid end at the indices in bandEnd.
+// It returns nil if fewer than n alternating extrema are found.
+func remezExtrema(dev []float64, bandEnd []int, n int, delta float64) []int {
+	var cand []int
+	start := 0
+	for _, end := range bandEnd {
+		for j := start; j < end; j++ {
+			e := dev[j]
+			if e == 0 {
+				continue
+			}
+			left := j == start || (e > 0 && e >= dev[j-1]) || (e < 0 && e <= dev[j-1])
+			right := j == end-1 || (e > 0 && e > dev[j+1]) || (e < 0 && e < dev[j+1])
+			if left && right {
+				cand = append(cand, j)
+			}
+		}
+		start = end
+	}
+
+	// Prefer extrema with at least the magnitude of the current
+	// deviation.
+	var large []int
+	for _, j := range cand {
+		if math.Abs(dev[j]) >= delta*(1-1e-9) {
+			large = append(large, j)
+		}
+	}
+	if ext := alternate(large, dev, n); ext != nil {
+		return ext
+	}
+	return alternate(cand, dev, n)
+}
+
+// alternate reduces the candidate extrema to n with alternating signs,
+// keeping the largest errors. It returns nil if fewer than n alternating
+// extrema exist.
+func alternate(cand []int, dev []float64, n int) []int {
+	var ext []int
+	for _, j := range cand {
+		if k := len(ext) - 1; k >= 0 && (dev[j] > 0) == (dev[ext[k]] > 0) {
+			if math.Abs(dev[j]) > math.Abs(dev[ext[k]]) {
+				ext[k] = j
+			}
+			continue
+		}
+		ext = append(ext, j)
+	}
+	for len(ext) > n {
+		if len(ext) == n+1 {
+			// Remove the smaller of the end points.
+			if math.Abs(dev[ext[0]]) < math.Abs(dev[ext[len(ext)-1]]) {
+				ext = ext[1:]
+			} else {
+				ext = ext[:len(ext)-1]
+			}
+			continue
+		}
+		k := 0
+		for i := range ext {
+			if math.Abs(dev[ext[i]]) < math.Abs(dev[ext[k]]) {
+				k = i
+			}
+		}
+		switch k {
+		case 0:
+			ext = ext[1:]
+		case len(ext) - 1:
+			ext = ext[:len(ext)-1]
+		default:
+			// The neighbors of k have the same sign, so the smaller
+			// of them is removed with k.
+			drop := k - 1
+			if math.Abs(dev[ext[k+1]]) < math.Abs(dev[ext[k-1]]) {
+				drop = k + 1
+			}
+			lo, hi := k, drop
+			if hi < lo {
+				lo, hi = hi, lo
+			}
+			ext = append(ext[:lo], ext[hi+1:]...)
+		}
+	}
+	if len(ext) < n {
+		return nil
+	}
+	return ext
+}
@@ -0,0 +1,202 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"gonum.org/v1/gonum/dsp/window"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+// firGain returns the magnitude of the response of the FIR filter h at the
+// frequency f for the sample rate fs.
+func firGain(h []float64, f, fs float64) float64 {
+	return cmplx.Abs(FrequencyResponse(nil, h, []float64{1}, []float64{f}, fs)[0])
+}
+
+func isSymmetric(h []float64) bool {
+	for i := range h {
+		if !scalar.EqualWithinAbs(h[i], h[len(h)-1-i], 1e-14) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFIRWindow(t *testing.T) {
+	t.Parallel()
+	const fs = 1000.0
+	for _, test := range []struct {
+		band   Band
+		cutoff []float64
+		norm   float64
+		pass   []float64
+		stop   []float64
+	}{
+		{band: Lowpass, cutoff: []float64{100}, norm: 0, pass: []float64{0, 50}, stop: []float64{150, 300, 500}},
+		{band: Highpass, cutoff: []float64{200}, norm: 500, pass: []float64{250, 400, 500}, stop: []float64{0, 50, 150}},
+		{band: Bandpass, cutoff: []float64{100, 300}, norm: 200, pass: []float64{150, 200, 250}, stop: []float64{0, 50, 350, 500}},
+		{band: Bandstop, cutoff: []float64{100, 300}, norm: 0, pass: []float64{0, 50, 350, 500}, stop: []float64{150, 200, 250}},
+	} {
+		for _, win := range []struct {
+			name string
+			fn   func([]float64) []float64
+			// atten is the minimum stopband attenuation in dB
+			// of the window away from the transition band.
+			atten float64
+		}{
+			{name: "Hamming", fn: window.Hamming, atten: 50},
+			{name: "Blackman", fn: window.Blackman, atten: 70},
+			{name: "default", fn: nil, atten: 50},
+		} {
+			name := fmt.Sprintf("band %d window %s", test.band, win.name)
+			h := FIRWindow(61, test.band, fs, win.fn, test.cutoff...)
+			if len(h) != 61 {
+				t.Fatalf("%s: unexpected length: %d", name, len(h))
+			}
+			if !isSymmetric(h) {
+				t.Errorf("%s: filter is not symmetric", name)
+			}
+			if got := firGain(h, test.norm, fs); !scalar.EqualWithinAbs(got, 1, 1e-12) {
+				t.Errorf("%s: unexpected gain at %v: got %v, want 1", name, test.norm, got)
+			}
+			for _, f := range test.cutoff {
+				// The gain of a windowed-sinc filter is close to one
+				// half at the cutoff.
+				if got := firGain(h, f, fs); !scalar.EqualWithinAbs(got, 0.5, 0.02) {
+					t.Errorf("%s: unexpected gain at cutoff %v: got %v, want 0.5", name, f, got)
+				}
+			}
+			for _, f := range test.pass {
+				if got := firGain(h, f, fs); !scalar.EqualWithinAbs(got, 1, 0.01) {
+					t.Errorf("%s: unexpected passband gain at %v: %v", name, f, got)
+				}
+			}
+			for _, f := range test.stop {
+				if got := 20 * math.Log10(firGain(h, f, fs)); got > -win.atten {
+					t.Errorf("%s: insufficient attenuation at %v: %v dB", name, f, got)
+				}
+			}
+		}
+	}
+}
+
+func TestParksMcClellan(t *testing.T) {
+	t.Parallel()
+	const fs = 2.0
+	for _, test := range []struct {
+		name    string
+		taps    int
+		bands   []float64
+		desired []float64
+		weight  []float64
+	}{
+		{
+			name:    "lowpass odd",
+			taps:    31,
+			bands:   []float64{0, 0.4, 0.5, 1},
+			desired: []float64{1, 0},
+		},
+		{
+			name:    "lowpass even",
+			taps:    32,
+			bands:   []float64{0, 0.4, 0.5, 1},
+			desired: []float64{1, 0},
+		},
+		{
+			name:    "weighted lowpass",
+			taps:    41,
+			bands:   []float64{0, 0.3, 0.4, 1},
+			desired: []float64{1, 0},
+			weight:  []float64{1, 10},
+		},
+		{
+			name:    "highpass",
+			taps:    35,
+			bands:   []float64{0, 0.5, 0.6, 1},
+			desired: []float64{0, 1},
+		},
+		{
+			name:    "bandpass",
+			taps:    55,
+			bands:   []float64{0, 0.2, 0.3, 0.5, 0.6, 1},
+			desired: []float64{0, 1, 0},
+			weight:  []float64{2, 1, 2},
+		},
+		{
+			name:    "multiband",
+			taps:    73,
+			bands:   []float64{0, 0.1, 0.15, 0.3, 0.35, 0.6, 0.65, 1},
+			desired: []float64{0, 1, 0.5, 0},
+		},
+	} {
+		h, err := ParksMcClellan(test.taps, test.bands, test.desired, test.weight, fs)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if len(h) != test.taps {
+			t.Errorf("%s: unexpected length: got %d, want %d", test.name, len(h), test.taps)
+			continue
+		}
+		if !isSymmetric(h) {
+			t.Errorf("%s: filter is not symmetric", test.name)
+		}
+
+		// The optimal filter has equal maximum weighted error in all
+		// bands.
+		var maxErr []float64
+		for i, d := range test.desired {
+			w := 1.0
+			if test.weight != nil {
+				w = test.weight[i]
+			}
+			lo, hi := test.bands[2*i], test.bands[2*i+1]
+			var e float64
+			for j := 0; j <= 1000; j++ {
+				f := lo + (hi-lo)*float64(j)/1000
+				if test.taps%2 == 0 && f == fs/2 {
+					continue
+				}
+				e = math.Max(e, w*math.Abs(firGain(h, f, fs)-d))
+			}
+			maxErr = append(maxErr, e)
+		}
+		for i, e := range maxErr {
+			if !scalar.EqualWithinRel(e, maxErr[0], 0.02) {
+				t.Errorf("%s: weighted error of band %d not equiripple: got %v, want %v", test.name, i, e, maxErr[0])
+			}
+			if e > 0.1 {
+				t.Errorf("%s: weighted error of band %d too large: %v", test.name, i, e)
+			}
+		}
+	}
+}
+
+func TestParksMcClellanKnown(t *testing.T) {
+	t.Parallel()
+	// A half-band filter has zero coefficients at even offsets from the
+	// center, except for the center coefficient of one half.
+	h, err := ParksMcClellan(19, []float64{0, 0.2, 0.3, 0.5}, []float64{1, 0}, nil, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, v := range h {
+		want := v
+		switch {
+		case i == 9:
+			want = 0.5
+		case (i-9)%2 == 0:
+			want = 0
+		}
+		if !scalar.EqualWithinAbs(v, want, 1e-6) {
+			t.Errorf("unexpected coefficient %d: got %v, want %v", i, v, want)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Band is the type of a frequency selective filter.
+type Band int
+
+const (
+	// Lowpass filters pass frequencies below the cutoff.
+	Lowpass Band = iota
+	// Highpass filters pass frequencies above the cutoff.
+	Highpass
+	// Bandpass filters pass frequencies between two cutoffs.
+	Bandpass
+	// Bandstop filters reject frequencies between two cutoffs.
+	Bandstop
+)
+
+// Butterworth returns a digital Butterworth filter of the given order as
+// second-order sections. The Butterworth filter has a maximally flat
+// magnitude response in the passband and a gain of -3 dB at the cutoff
+// frequencies.
+//
+// The band type determines the number of cutoff frequencies; Lowpass and
+// Highpass filters take one cutoff, and Bandpass and Bandstop filters take
+// two, the lower and the upper edge of the band. Bandpass and Bandstop
+// filters have twice the given order. The cutoffs are in the units of the
+// sample rate fs and must be in (0, fs/2).
+//
+// Butterworth panics if order is not positive or if the cutoffs are not
+// valid for the band type.
+func Butterworth(order int, band Band, fs float64, cutoff ...float64) SOS {
+	checkOrder(order)
+	p := make([]complex128, order)
+	for i := range p {
+		m := float64(2*i - order + 1)
+		p[i] = -cmplx.Exp(complex(0, math.Pi*m/float64(2*order)))
+	}
+	return design(zpk{p: p, k: 1}, band, fs, cutoff)
+}
+
+// ChebyshevI returns a digital Chebyshev type I filter of the given order
+// as second-order sections. The Chebyshev type I filter has an equiripple
+// magnitude response in the passband with the given peak-to-peak ripple in
+// decibels, and a gain of -ripple dB at the cutoff frequencies.
+//
+// The cutoff frequencies are specified as described for Butterworth.
+// ChebyshevI panics if order or ripple are not positive or if the cutoffs
+// are not valid for the band type.
+func ChebyshevI(order int, ripple float64, band Band, fs float64, cutoff ...float64) SOS {
+	checkOrder(order)
+	if !(ripple > 0) {
+		panic("filter: ripple must be positive")
+	}
+	eps := math.Sqrt(math.Pow(10, ripple/10) - 1)
+	mu := math.Asinh(1/eps) / float64(order)
+	p := make([]complex128, order)
+	for i := range p {
+		theta := math.Pi * float64(2*i-order+1) / float64(2*order)
+		p[i] = -cmplx.Sinh(complex(mu, theta))
+	}
+	k := real(prod(neg(p)))
+	if order%2 == 0 {
+		k /= math.Sqrt(1 + eps*eps)
+	}
+	return design(zpk{p: p, k: k}, band, fs, cutoff)
+}
+
+// ChebyshevII returns a digital Chebyshev type II filter of the given order
+// as second-order sections. The Chebyshev type II filter has a flat
+// magnitude response in the passband and an equiripple response in the
+// stopband with at least the given attenuation in decibels. The gain at
+// the cutoff frequencies, which are the edges of the stopband, is
+// -attenuation dB.
+//
+// The cutoff frequencies are specified as described for Butterworth.
+// ChebyshevII panics if order or attenuation are not positive or if the
+// cutoffs are not valid for the band type.
+func ChebyshevII(order int, attenuation float64, band Band, fs float64, cutoff ...float64) SOS {
+	checkOrder(order)
+	if !(attenuation > 0) {
+		panic("filter: attenuation must be positive")
+	}
+	de := 1 / math.Sqrt(math.Pow(10, attenuation/10)-1)
+	mu := math.Asinh(1/de) / float64(order)
+	var z []complex128
+	p := make([]complex128, order)
+	for i := range p {
+		m := 2*i - order + 1
+		if m != 0 {
+			z = append(z, complex(0, 1/math.Sin(math.Pi*float64(m)/float64(2*order))))
+		}
+		v := -cmplx.Exp(complex(0, math.Pi*float64(m)/float64(2*order)))
+		p[i] = 1 / complex(math.Sinh(mu)*real(v), math.Cosh(mu)*imag(v))
+	}
+	k := real(prod(neg(p)) / prod(neg(z)))
+	return design(zpk{z: z, p: p, k: k}, band, fs, cutoff)
+}
+
+// Elliptic returns a digital elliptic (Cauer) filter of the given order as
+// second-order sections. The elliptic filter has an equiripple magnitude
+// response in the passband with the given peak-to-peak ripple in decibels
+// and in the stopband with at least the given attenuation in decibels. It
+// has the narrowest transition band of the filters in this package for a
+// given order. The gain at the cutoff frequencies, which are the edges of
+// the passband, is -ripple dB.
+//
+// The cutoff frequencies are specified as described for Butterworth.
+// Elliptic panics if order or ripple are not positive, if attenuation is
+// not greater than ripple, or if the cutoffs are not valid for the band
+// type.
+//
+// References:
+//   - Orfanidis, S.J. (2006). Lecture notes on elliptic filter design.
+//     Rutgers University.
+func Elliptic(order int, ripple, attenuation float64, band Band, fs float64, cutoff ...float64) SOS {
+	checkOrder(order)
+	if !(ripple > 0) {
+		panic("filter: ripple must be positive")
+	}
+	if !(attenuation > ripple) {
+		panic("filter: attenuation must be greater than ripple")
+	}
+	ep := math.Sqrt(math.Pow(10, ripple/10) - 1)
+	es := math.Sqrt(math.Pow(10, attenuation/10) - 1)
+	k1 := ep / es
+	k := ellipdeg(order, k1)
+
+	// Zeros and poles of the prototype with a passband edge of 1 rad/s.
+	v0 := real(complex(0, -1) * asne(complex(0, 1/ep), k1) / complex(float64(order), 0))
+	var z, p []complex128
+	for i := 1; i <= order/2; i++ {
+		u := float64(2*i-1) / float64(order)
+		zeta := real(cde(complex(u, 0), k))
+		zi := complex(0, 1/(k*zeta))
+		z = append(z, zi, cmplx.Conj(zi))
+		pi := complex(0, 1) * cde(complex(u, -v0), k)
+		p = append(p, pi, cmplx.Conj(pi))
+	}
+	gain := 1.0
+	if order%2 == 1 {
+		p0 := complex(0, 1) * sne(complex(0, v0), k)
+		p = append(p, complex(real(p0), 0))
+	} else {
+		gain = 1 / math.Sqrt(1+ep*ep)
+	}
+	for i, v := range p {
+		if real(v) > 0 {
+			p[i] = complex(-real(v), imag(v))
+		}
+	}
+	gain *= real(prod(neg(p)) / prod(neg(z)))
+	return design(zpk{z: z, p: p, k: gain}, band, fs, cutoff)
+}
+
+func checkOrder(order int) {
+	if order < 1 {
+		panic("filter: order must be positive")
+	}
+}
+
+// checkCutoff panics if the cutoff frequencies are not valid for the band
+// type and the sample rate.
+func checkCutoff(band Band, fs float64, cutoff []float64) {
+	if !(fs > 0) {
+		panic("filter: sample rate must be positive")
+	}
+	switch band {
+	case Lowpass, Highpass:
+		if len(cutoff) != 1 {
+			panic("filter: one cutoff frequency required")
+		}
+	case Bandpass, Bandstop:
+		if len(cutoff) != 2 {
+			panic("filter: two cutoff frequencies required")
+		}
+		if !(cutoff[0] < cutoff[1]) {
+			panic("filter: cutoff frequencies not increasing")
+		}
+	default:
+		panic("filter: unknown band type")
+	}
+	for _, f := range cutoff {
+		if !(0 < f && f < fs/2) {
+			panic("filter: cutoff frequency out of range")
+		}
+	}
+}
+
+// design transforms the analog lowpass prototype with a cutoff of 1 rad/s
+// into a digital filter of the given band type.
+func design(proto zpk, band Band, fs float64, cutoff []float64) SOS {
+	checkCutoff(band, fs, cutoff)
+	// Prewarp the cutoffs to compensate for the bilinear transform.
+	w := make([]float64, len(cutoff))
+	for i, f := range cutoff {
+		w[i] = 2 * fs * math.Tan(math.Pi*f/fs)
+	}
+	var analog zpk
+	switch band {
+	case Lowpass:
+		analog = proto.lowpass(w[0])
+	case Highpass:
+		analog = proto.highpass(w[0])
+	case Bandpass:
+		analog = proto.bandpass(math.Sqrt(w[0]*w[1]), w[1]-w[0])
+	case Bandstop:
+		analog = proto.bandstop(math.Sqrt(w[0]*w[1]), w[1]-w[0])
+	}
+	return analog.bilinear(fs).sos()
+}
+
+// landen returns the descending Landen sequence of elliptic moduli
+// starting from k.
+func landen(k float64) []float64 {
+	var v []float64
+	for k > 1e-15 && len(v) < 20 {
+		kp := math.Sqrt(1 - k*k)
+		k = (k / (1 + kp)) * (k / (1 + kp))
+		v = append(v, k)
+	}
+	return v
+}
+
+// cde returns the Jacobi elliptic function cd(u*K, k) where K is the
+// complete elliptic integral of the first kind with modulus k.
+func cde(u complex128, k float64) complex128 {
+	return ascend(cmplx.Cos(u*math.Pi/2), landen(k))
+}
+
+// sne returns the Jacobi elliptic function sn(u*K, k) where K is the
+// complete elliptic integral of the first kind with modulus k.
+func sne(u complex128, k float64) complex128 {
+	return ascend(cmplx.Sin(u*math.Pi/2), landen(k))
+}
+
+// ascend applies the ascending Landen transformations with the moduli v.
+func ascend(w complex128, v []float64) complex128 {
+	for n := len(v) - 1; n >= 0; n-- {
+		w = complex(1+v[n], 0) * w / (1 + complex(v[n], 0)*w*w)
+	}
+	return w
+}
+
+// asne returns the inverse of sne.
+func asne(w complex128, k float64) complex128 {
+	v := landen(k)
+	v1 := k
+	for _, vn := range v {
+		w = w / (1 + cmplx.Sqrt(1-w*w*complex(v1*v1, 0))) * complex(2/(1+vn), 0)
+		v1 = vn
+	}
+	return 1 - cmplx.Acos(w)*2/math.Pi
+}
+
+// ellipdeg returns the elliptic modulus k satisfying the degree equation
+// for an elliptic filter of order n with discrimination modulus k1.
+func ellipdeg(n int, k1 float64) float64 {
+	k1p := math.Sqrt(1 - k1*k1)
+	kp := math.Pow(k1p, float64(n))
+	for i := 1; i <= n/2; i++ {
+		s := real(sne(complex(float64(2*i-1)/float64(n), 0), k1p))
+		kp *= s * s * s * s
+	}
+	return math.Sqrt(1 - kp*kp)
+}
@@ -0,0 +1,205 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestButterworthCoefficients(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		order  int
+		band   Band
+		cutoff []float64
+		b, a   []float64
+	}{
+		{
+			order:  2,
+			band:   Lowpass,
+			cutoff: []float64{0.25},
+			b:      []float64{0.2928932188134525, 0.585786437626905, 0.2928932188134525},
+			a:      []float64{1, 0, 0.1715728752538099},
+		},
+		{
+			order:  2,
+			band:   Highpass,
+			cutoff: []float64{0.25},
+			b:      []float64{0.2928932188134525, -0.585786437626905, 0.2928932188134525},
+			a:      []float64{1, 0, 0.1715728752538099},
+		},
+		{
+			order:  1,
+			band:   Lowpass,
+			cutoff: []float64{0.125},
+			b:      []float64{0.2928932188134524, 0.2928932188134524},
+			a:      []float64{1, -0.4142135623730952},
+		},
+	} {
+		b, a := Butterworth(test.order, test.band, 1, test.cutoff...).TransferFunction()
+		// The transfer function of a first-order filter has a trailing
+		// zero coefficient from its second-order section.
+		b = b[:len(test.b)]
+		a = a[:len(test.a)]
+		if !floats.EqualApprox(b, test.b, 1e-12) || !floats.EqualApprox(a, test.a, 1e-12) {
+			t.Errorf("unexpected coefficients for order %d band %d: got b=%v a=%v, want b=%v a=%v",
+				test.order, test.band, b, a, test.b, test.a)
+		}
+	}
+}
+
+// gainDB returns the gain of s in decibels at the frequency f for the
+// sample rate fs.
+func gainDB(s SOS, f, fs float64) float64 {
+	h := s.FrequencyResponse(nil, []float64{f}, fs)[0]
+	return 20 * math.Log10(cmplx.Abs(h))
+}
+
+// passes returns whether the frequency f is in the passband of a filter of
+// the given band type whose band edges are cutoff.
+func passes(band Band, cutoff []float64, f float64) bool {
+	switch band {
+	case Lowpass:
+		return f < cutoff[0]
+	case Highpass:
+		return f > cutoff[0]
+	case Bandpass:
+		return cutoff[0] < f && f < cutoff[1]
+	default:
+		return f < cutoff[0] || cutoff[1] < f
+	}
+}
+
+func TestIIRDesign(t *testing.T) {
+	t.Parallel()
+	const (
+		fs     = 1000.0
+		ripple = 0.5
+		atten  = 40.0
+	)
+	designs := []struct {
+		name string
+		fn   func(order int, band Band, cutoff []float64) SOS
+		// edgeDB is the gain at the cutoffs.
+		edgeDB float64
+		// passDB and stopDB bound the gain on the passband and on the
+		// stopband side of the cutoffs, respectively.
+		passDB, stopDB float64
+	}{
+		{
+			name: "Butterworth",
+			fn: func(order int, band Band, cutoff []float64) SOS {
+				return Butterworth(order, band, fs, cutoff...)
+			},
+			edgeDB: -10 * math.Log10(2),
+			passDB: -10 * math.Log10(2),
+			stopDB: -10 * math.Log10(2),
+		},
+		{
+			name: "ChebyshevI",
+			fn: func(order int, band Band, cutoff []float64) SOS {
+				return ChebyshevI(order, ripple, band, fs, cutoff...)
+			},
+			edgeDB: -ripple,
+			passDB: -ripple,
+			stopDB: -ripple,
+		},
+		{
+			name: "ChebyshevII",
+			fn: func(order int, band Band, cutoff []float64) SOS {
+				return ChebyshevII(order, atten, band, fs, cutoff...)
+			},
+			edgeDB: -atten,
+			passDB: math.Inf(-1),
+			stopDB: -atten,
+		},
+		{
+			name: "Elliptic",
+			fn: func(order int, band Band, cutoff []float64) SOS {
+				return Elliptic(order, ripple, atten, band, fs, cutoff...)
+			},
+			edgeDB: -ripple,
+			passDB: -ripple,
+			stopDB: -ripple,
+		},
+	}
+	for _, d := range designs {
+		for _, band := range []Band{Lowpass, Highpass, Bandpass, Bandstop} {
+			cutoff := []float64{150}
+			if band == Bandpass || band == Bandstop {
+				cutoff = []float64{150, 300}
+			}
+			for order := 1; order <= 8; order++ {
+				name := fmt.Sprintf("%s order %d band %d", d.name, order, band)
+				s := d.fn(order, band, cutoff)
+				wantSections := (order + 1) / 2
+				if band == Bandpass || band == Bandstop {
+					wantSections = order
+				}
+				if len(s) != wantSections {
+					t.Errorf("%s: unexpected number of sections: got %d, want %d", name, len(s), wantSections)
+				}
+				for i, sec := range s {
+					// The roots of 1 + a1 z⁻¹ + a2 z⁻² are inside the
+					// unit circle if and only if |a2| < 1 and |a1| < 1+a2.
+					a1, a2 := sec.A[1]/sec.A[0], sec.A[2]/sec.A[0]
+					if !(math.Abs(a2) < 1 && math.Abs(a1) < 1+a2) {
+						t.Errorf("%s: section %d is unstable: %v", name, i, sec.A)
+					}
+				}
+				for _, f := range cutoff {
+					got := gainDB(s, f, fs)
+					if !scalar.EqualWithinAbs(got, d.edgeDB, 1e-8) {
+						t.Errorf("%s: unexpected gain at %v: got %v dB, want %v dB", name, f, got, d.edgeDB)
+					}
+				}
+				for f := 1.0; f < fs/2; f += 1 {
+					if scalar.EqualWithinAbs(f, cutoff[0], 1) || scalar.EqualWithinAbs(f, cutoff[len(cutoff)-1], 1) {
+						continue
+					}
+					got := gainDB(s, f, fs)
+					if got > 1e-8 {
+						t.Errorf("%s: gain above 0 dB at %v: %v dB", name, f, got)
+						break
+					}
+					if passes(band, cutoff, f) {
+						if got < d.passDB-1e-8 {
+							t.Errorf("%s: passband gain below %v dB at %v: %v dB", name, d.passDB, f, got)
+							break
+						}
+					} else if got > d.stopDB+1e-8 {
+						t.Errorf("%s: stopband gain above %v dB at %v: %v dB", name, d.stopDB, f, got)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestEllipticStopband(t *testing.T) {
+	t.Parallel()
+	// The minimum order for an elliptic lowpass filter with a passband
+	// edge of 0.1 and a stopband edge of 0.15 times the sample rate, 1 dB
+	// ripple and 60 dB attenuation is 6.
+	s := Elliptic(6, 1, 60, Lowpass, 1, 0.1)
+	for f := 0.15; f < 0.5; f += 0.001 {
+		if got := gainDB(s, f, 1); got > -60+1e-8 {
+			t.Errorf("stopband gain above -60 dB at %v: %v dB", f, got)
+			break
+		}
+	}
+	// A lower order does not meet the specification.
+	s = Elliptic(5, 1, 60, Lowpass, 1, 0.1)
+	if got := gainDB(s, 0.15, 1); got < -60 {
+		t.Errorf("unexpected gain for order 5 at stopband edge: %v dB", got)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filter
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// zpk is a transfer function in zero-pole-gain form,
+//
+//	H(s) = k * Π(s - z_i) / Π(s - p_i).
+//
+// Complex zeros and poles must occur in conjugate pairs.
+type zpk struct {
+	z, p []complex128
+	k    float64
+}
+
+// prod returns the product of the elements of v.
+func prod(v []complex128) complex128 {
+	r := complex(1, 0)
+	for _, x := range v {
+		r *= x
+	}
+	return r
+}
+
+// neg returns the negated elements of v in a new slice.
+func neg(v []complex128) []complex128 {
+	r := make([]complex128, len(v))
+	for i, x := range v {
+		r[i] = -x
+	}
+	return r
+}
+
+// lowpass transforms an analog lowpass prototype with a cutoff of 1 rad/s
+// into a lowpass filter with the cutoff wo.
+func (f zpk) lowpass(wo float64) zpk {
+	z := make([]complex128, len(f.z))
+	p := make([]complex128, len(f.p))
+	for i, v := range f.z {
+		z[i] = v * complex(wo, 0)
+	}
+	for i, v := range f.p {
+		p[i] = v * complex(wo, 0)
+	}
+	return zpk{z: z, p: p, k: f.k * math.Pow(wo, float64(len(p)-len(z)))}
+}
+
+// highpass transforms an analog lowpass prototype with a cutoff of 1 rad/s
+// into a highpass filter with the cutoff wo.
+func (f zpk) highpass(wo float64) zpk {
+	degree := len(f.p) - len(f.z)
+	z := make([]complex128, 0, len(f.p))
+	p := make([]complex128, len(f.p))
+	for _, v := range f.z {
+		z = append(z, complex(wo, 0)/v)
+	}
+	for i, v := range f.p {
+		p[i] = complex(wo, 0) / v
+	}
+	for i := 0; i < degree; i++ {
+		z = append(z, 0)
+	}
+	k := f.k * real(prod(neg(f.z))/prod(neg(f.p)))
+	return zpk{z: z, p: p, k: k}
+}
+
+// bandpass transforms an analog lowpass prototype with a cutoff of 1 rad/s
+// into a bandpass filter with the center frequency wo and the bandwidth bw.
+func (f zpk) bandpass(wo, bw float64) zpk {
+	degree := len(f.p) - len(f.z)
+	shift := func(v complex128) (complex128, complex128) {
+		v *= complex(bw/2, 0)
+		d := cmplx.Sqrt(v*v - complex(wo*wo, 0))
+		return v + d, v - d
+	}
+	z := make([]complex128, 0, 2*len(f.p))
+	p := make([]complex128, 0, 2*len(f.p))
+	for _, v := range f.z {
+		a, b := shift(v)
+		z = append(z, a, b)
+	}
+	for _, v := range f.p {
+		a, b := shift(v)
+		p = append(p, a, b)
+	}
+	for i := 0; i < degree; i++ {
+		z = append(z, 0)
+	}
+	return zpk{z: z, p: p, k: f.k * math.Pow(bw, float64(degree))}
+}
+
+// bandstop transforms an analog lowpass prototype with a cutoff of 1 rad/s
+// into a bandstop filter with the center frequency wo and the bandwidth bw.
+func (f zpk) bandstop(wo, bw float64) zpk {
+	degree := len(f.p) - len(f.z)
+	shift := func(v complex128) (complex128, complex128) {
+		v = complex(bw/2, 0) / v
+		d := cmplx.Sqrt(v*v - complex(wo*wo, 0))
+		return v + d, v - d
+	}
+	z := make([]complex128, 0, 2*len(f.p))
+	p := make([]complex128, 0, 2*len(f.p))
+	for _, v := range f.z {
+		a, b := shift(v)
+		z = append(z, a, b)
+	}
+	for _, v := range f.p {
+		a, b := shift(v)
+		p = append(p, a, b)
+	}
+	for i := 0; i < degree; i++ {
+		z = append(z, complex(0, wo), complex(0, -wo))
+	}
+	k := f.k * real(prod(neg(f.z))/prod(neg(f.p)))
+	return zpk{z: z, p: p, k: k}
+}
+
+// bilinear transforms an analog filter into a digital filter with the
+// sample rate fs using the bilinear transform.
+func (f zpk) bilinear(fs float64) zpk {
+	degree := len(f.p) - len(f.z)
+	fs2 := complex(2*fs, 0)
+	z := make([]complex128, 0, len(f.p))
+	p := make([]complex128, len(f.p))
+	num := complex(1, 0)
+	den := complex(1, 0)
+	for _, v := range f.z {
+		z = append(z, (fs2+v)/(fs2-v))
+		num *= fs2 - v
+	}
+	for i, v := range f.p {
+		p[i] = (fs2 + v) / (fs2 - v)
+		den *= fs2 - v
+	}
+	for i := 0; i < degree; i++ {
+		z = append(z, -1)
+	}
+	return zpk{z: z, p: p, k: f.k * real(num/den)}
+}
+
+// conjTol is the relative tolerance for treating a root as real.
+const conjTol = 1e-10
+
+// isReal returns whether the root v is real to within conjTol.
+func isReal(v complex128) bool {
+	return math.Abs(imag(v)) <= conjTol*math.Max(1, cmplx.Abs(v))
+}
+
+// sos returns the digital filter as a cascade of second-order sections.
+// Poles are paired with the nearest zeros, and the sections are ordered
+// so that the poles closest to the unit circle are in the last section.
+func (f zpk) sos() SOS {
+	var (
+		cplxP, realP []complex128
+		cplxZ, realZ []complex128
+	)
+	split := func(v []complex128, cplxDst, realDst *[]complex128) {
+		for _, x := range v {
+			switch {
+			case isReal(x):
+				*realDst = append(*realDst, complex(real(x), 0))
+			case imag(x) > 0:
+				*cplxDst = append(*cplxDst, x)
+			}
+		}
+	}
+	split(f.p, &cplxP, &realP)
+	split(f.z, &cplxZ, &realZ)
+
+	// Group the poles into sections, with pairs of real poles
+	// taken in order of their magnitude.
+	type group struct{ p1, p2 complex128 }
+	var groups []group
+	for _, p := range cplxP {
+		groups = append(groups, group{p, cmplx.Conj(p)})
+	}
+	sort.Slice(realP, func(i, j int) bool { return cmplx.Abs(realP[i]) > cmplx.Abs(realP[j]) })
+	for i := 0; i < len(realP); i += 2 {
+		if i+1 < len(realP) {
+			groups = append(groups, group{realP[i], realP[i+1]})
+		} else {
+			groups = append(groups, group{realP[i], cmplx.NaN()})
+		}
+	}
+	single := func(g group) bool { return cmplx.IsNaN(g.p2) }
+	sort.SliceStable(groups, func(i, j int) bool {
+		return cmplx.Abs(groups[i].p1) > cmplx.Abs(groups[j].p1)
+	})
+
+	// nearest removes and returns the root in v nearest to p.
+	nearest := func(v *[]complex128, p complex128) (complex128, bool) {
+		if len(*v) == 0 {
+			return 0, false
+		}
+		best := 0
+		for i, x := range *v {
+			if cmplx.Abs(x-p) < cmplx.Abs((*v)[best]-p) {
+				best = i
+			}
+		}
+		x := (*v)[best]
+		*v = append((*v)[:best], (*v)[best+1:]...)
+		return x, true
+	}
+	dist := func(v []complex128, p complex128) float64 {
+		d := math.Inf(1)
+		for _, x := range v {
+			d = math.Min(d, cmplx.Abs(x-p))
+		}
+		return d
+	}
+
+	// Assign zeros to the groups, starting with the poles closest to
+	// the unit circle.
+	sections := make(SOS, len(groups))
+	for i, g := range groups {
+		s := &sections[len(groups)-1-i]
+		s.A = quadratic(g.p1, g.p2, !single(g))
+		var zeros []complex128
+		useComplex := len(cplxZ) != 0 && dist(cplxZ, g.p1) < dist(realZ, g.p1)
+		if single(g) {
+			useComplex = len(realZ) == 0 && len(cplxZ) != 0
+		} else if len(realZ) < 2 && len(cplxZ) != 0 {
+			useComplex = true
+		}
+		if useComplex {
+			z, _ := nearest(&cplxZ, g.p1)
+			zeros = append(zeros, z, cmplx.Conj(z))
+		} else {
+			n := 2
+			if single(g) {
+				n = 1
+			}
+			for j := 0; j < n; j++ {
+				z, ok := nearest(&realZ, g.p1)
+				if !ok {
+					break
+				}
+				zeros = append(zeros, z)
+			}
+		}
+		switch len(zeros) {
+		case 0:
+			s.B = [3]float64{1, 0, 0}
+		case 1:
+			s.B = [3]float64{1, -real(zeros[0]), 0}
+		default:
+			s.B = quadratic(zeros[0], zeros[1], true)
+		}
+	}
+	if len(sections) == 0 {
+		sections = SOS{{B: [3]float64{1, 0, 0}, A: [3]float64{1, 0, 0}}}
+	}
+	for j := range sections[0].B {
+		sections[0].B[j] *= f.k
+	}
+	return sections
+}
+
+// quadratic returns the coefficients of the polynomial with the roots r1
+// and, if two is true, r2 in increasing powers of z⁻¹.
+func quadratic(r1, r2 complex128, two bool) [3]float64 {
+	if !two {
+		return [3]float64{1, -real(r1), 0}
+	}
+	return [3]float64{1, -real(r1 + r2), real(r1 * r2)}
+}
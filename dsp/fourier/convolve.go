@@ -0,0 +1,463 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// ConvolutionMode specifies the part of a convolution or correlation of
+// sequences of lengths n and m that is computed.
+type ConvolutionMode int
+
+const (
+	// Full is the complete result of length n+m-1.
+	Full ConvolutionMode = iota
+	// Same is the central part of the Full result with the length n of
+	// the first sequence.
+	Same
+	// Valid is the part of the Full result that does not depend on
+	// zero padding, of length max(n, m)-min(n, m)+1.
+	Valid
+)
+
+// Convolve computes the convolution of the sequences a and b,
+//
+//	dst[k] = \sum_j a[j] * b[k-j],
+//
+// for the part of the result specified by mode, placing the result in dst
+// and returning it. The convolution is computed directly or by fast Fourier
+// transforms, depending on which is expected to be faster for the lengths
+// of the sequences.
+//
+// If a or b is empty, Convolve will panic. If dst is nil, a new slice is
+// allocated and returned. If dst is not nil and the length of dst does not
+// equal the length of the result for the mode, Convolve will panic.
+func Convolve(dst, a, b []float64, mode ConvolutionMode) []float64 {
+	lo, hi := convRange(len(a), len(b), mode)
+	dst = useReal(dst, hi-lo)
+	if useFFT(len(a), len(b), hi-lo) {
+		fftConvolve(dst, a, b, lo)
+	} else {
+		directConvolve(dst, a, b, lo)
+	}
+	return dst
+}
+
+// CmplxConvolve computes the convolution of the complex sequences a and b
+// as described for Convolve, placing the result in dst and returning it.
+//
+// If a or b is empty, CmplxConvolve will panic. If dst is nil, a new slice
+// is allocated and returned. If dst is not nil and the length of dst does
+// not equal the length of the result for the mode, CmplxConvolve will
+// panic.
+func CmplxConvolve(dst, a, b []complex128, mode ConvolutionMode) []complex128 {
+	lo, hi := convRange(len(a), len(b), mode)
+	dst = useCmplx(dst, hi-lo)
+	if useFFT(len(a), len(b), hi-lo) {
+		fftCmplxConvolve(dst, a, b, lo)
+	} else {
+		directCmplxConvolve(dst, a, b, lo)
+	}
+	return dst
+}
+
+// Correlate computes the cross-correlation of the sequences a and b,
+//
+//	dst[k] = \sum_j a[j+k-len(b)+1] * b[j],
+//
+// for the part of the result specified by mode, placing the result in dst
+// and returning it. For the Full mode, dst[len(b)-1] is the correlation at
+// zero lag. The correlation of a and b is the convolution of a with b
+// reversed.
+//
+// If a or b is empty, Correlate will panic. If dst is nil, a new slice is
+// allocated and returned. If dst is not nil and the length of dst does not
+// equal the length of the result for the mode, Correlate will panic.
+func Correlate(dst, a, b []float64, mode ConvolutionMode) []float64 {
+	r := make([]float64, len(b))
+	for i, v := range b {
+		r[len(b)-1-i] = v
+	}
+	return Convolve(dst, a, r, mode)
+}
+
+// CmplxCorrelate computes the cross-correlation of the complex sequences a
+// and b,
+//
+//	dst[k] = \sum_j a[j+k-len(b)+1] * conj(b[j]),
+//
+// for the part of the result specified by mode, placing the result in dst
+// and returning it. For the Full mode, dst[len(b)-1] is the correlation at
+// zero lag.
+//
+// If a or b is empty, CmplxCorrelate will panic. If dst is nil, a new slice
+// is allocated and returned. If dst is not nil and the length of dst does
+// not equal the length of the result for the mode, CmplxCorrelate will
+// panic.
+func CmplxCorrelate(dst, a, b []complex128, mode ConvolutionMode) []complex128 {
+	r := make([]complex128, len(b))
+	for i, v := range b {
+		r[len(b)-1-i] = cmplx.Conj(v)
+	}
+	return CmplxConvolve(dst, a, r, mode)
+}
+
+// convRange returns the range [lo, hi) of the full convolution of sequences
+// of lengths n and m that is computed for the mode.
+func convRange(n, m int, mode ConvolutionMode) (lo, hi int) {
+	if n == 0 || m == 0 {
+		panic("fourier: empty sequence")
+	}
+	switch mode {
+	case Full:
+		return 0, n + m - 1
+	case Same:
+		lo = (m - 1) / 2
+		return lo, lo + n
+	case Valid:
+		if n < m {
+			return n - 1, m
+		}
+		return m - 1, n
+	default:
+		panic("fourier: invalid convolution mode")
+	}
+}
+
+func useReal(dst []float64, n int) []float64 {
+	if dst == nil {
+		return make([]float64, n)
+	}
+	if len(dst) != n {
+		panic("fourier: destination length mismatch")
+	}
+	return dst
+}
+
+func useCmplx(dst []complex128, n int) []complex128 {
+	if dst == nil {
+		return make([]complex128, n)
+	}
+	if len(dst) != n {
+		panic("fourier: destination length mismatch")
+	}
+	return dst
+}
+
+// useFFT returns whether a convolution of sequences of lengths n and m
+// producing out elements is expected to be faster using FFTs.
+func useFFT(n, m, out int) bool {
+	short := n
+	if m < short {
+		short = m
+	}
+	if short < 32 {
+		return false
+	}
+	l := float64(fastLen(n + m - 1))
+	return float64(out)*float64(short) > 4*l*math.Log2(l)
+}
+
+// fastLen returns the smallest integer that is at least n and has no prime
+// factors other than 2, 3 and 5, for which the FFTs are fastest.
+func fastLen(n int) int {
+	if n <= 6 {
+		return n
+	}
+	best := math.MaxInt
+	for p5 := 1; p5 < best; p5 *= 5 {
+		for p35 := p5; p35 < best; p35 *= 3 {
+			v := p35
+			for v < n {
+				v *= 2
+			}
+			if v < best {
+				best = v
+			}
+			if v == n {
+				return n
+			}
+		}
+	}
+	return best
+}
+
+// directConvolve computes elements lo to lo+len(dst) of the full
+// convolution of a and b directly.
+func directConvolve(dst, a, b []float64, lo int) {
+	for i := range dst {
+		k := lo + i
+		j0 := k - len(b) + 1
+		if j0 < 0 {
+			j0 = 0
+		}
+		j1 := k
+		if j1 > len(a)-1 {
+			j1 = len(a) - 1
+		}
+		var s float64
+		for j := j0; j <= j1; j++ {
+			s += a[j] * b[k-j]
+		}
+		dst[i] = s
+	}
+}
+
+// directCmplxConvolve computes elements lo to lo+len(dst) of the full
+// convolution of a and b directly.
+func directCmplxConvolve(dst, a, b []complex128, lo int) {
+	for i := range dst {
+		k := lo + i
+		j0 := k - len(b) + 1
+		if j0 < 0 {
+			j0 = 0
+		}
+		j1 := k
+		if j1 > len(a)-1 {
+			j1 = len(a) - 1
+		}
+		var s complex128
+		for j := j0; j <= j1; j++ {
+			s += a[j] * b[k-j]
+		}
+		dst[i] = s
+	}
+}
+
+// fftConvolve computes elements lo to lo+len(dst) of the full convolution
+// of a and b using FFTs.
+func fftConvolve(dst, a, b []float64, lo int) {
+	n := fastLen(len(a) + len(b) - 1)
+	fft := NewFFT(n)
+	buf := make([]float64, n)
+	copy(buf, a)
+	ca := fft.Coefficients(nil, buf)
+	for i := range buf {
+		buf[i] = 0
+	}
+	copy(buf, b)
+	cb := fft.Coefficients(nil, buf)
+	f := complex(1/float64(n), 0)
+	for i := range ca {
+		ca[i] *= cb[i] * f
+	}
+	fft.Sequence(buf, ca)
+	copy(dst, buf[lo:])
+}
+
+// fftCmplxConvolve computes elements lo to lo+len(dst) of the full
+// convolution of a and b using FFTs.
+func fftCmplxConvolve(dst, a, b []complex128, lo int) {
+	n := fastLen(len(a) + len(b) - 1)
+	fft := NewCmplxFFT(n)
+	ca := make([]complex128, n)
+	copy(ca, a)
+	fft.Coefficients(ca, ca)
+	cb := make([]complex128, n)
+	copy(cb, b)
+	fft.Coefficients(cb, cb)
+	f := complex(1/float64(n), 0)
+	for i := range ca {
+		ca[i] *= cb[i] * f
+	}
+	fft.Sequence(ca, ca)
+	copy(dst, ca[lo:])
+}
+
+// OverlapAdd performs the convolution of a stream of samples with a fixed
+// kernel using the overlap-add method. Each block of input is convolved
+// with the kernel using FFTs, and the tails of the block convolutions are
+// added to the output of the following blocks. The FFT plan and the
+// transform of the kernel are computed once and reused for all blocks.
+type OverlapAdd struct {
+	block int
+	fft   *FFT
+	h     []complex128
+
+	buf   []float64
+	coeff []complex128
+	tail  []float64
+}
+
+// NewOverlapAdd returns a new OverlapAdd for the given kernel that
+// processes input in blocks of at least the given length. The block length
+// is increased to make the best use of the FFT length.
+//
+// NewOverlapAdd will panic if kernel is empty or block is not positive.
+func NewOverlapAdd(kernel []float64, block int) *OverlapAdd {
+	fft, h, block := blockPlan(kernel, block)
+	return &OverlapAdd{
+		block: block,
+		fft:   fft,
+		h:     h,
+		buf:   make([]float64, fft.Len()),
+		coeff: make([]complex128, len(h)),
+		tail:  make([]float64, len(kernel)-1),
+	}
+}
+
+// BlockLen returns the block length of the convolver. Process is most
+// efficient when the length of its input is a multiple of the block length.
+func (c *OverlapAdd) BlockLen() int { return c.block }
+
+// Process convolves the samples in x with the kernel, continuing the
+// convolution of the samples passed in previous calls, placing the result
+// in dst and returning it. The output is the causal convolution
+//
+//	y[n] = \sum_k kernel[k] * x[n-k],
+//
+// where n counts samples since the start of the stream. The remaining
+// len(kernel)-1 samples of the full convolution of a finite stream are
+// obtained by processing that number of zeros. dst and x may be the same
+// slice.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of x, Process will panic.
+func (c *OverlapAdd) Process(dst, x []float64) []float64 {
+	dst = useReal(dst, len(x))
+	out := dst
+	m := len(c.tail)
+	for len(x) > 0 {
+		n := c.block
+		if len(x) < n {
+			n = len(x)
+		}
+		for i := range c.buf {
+			c.buf[i] = 0
+		}
+		copy(c.buf, x[:n])
+		c.fft.Coefficients(c.coeff, c.buf)
+		for i, v := range c.h {
+			c.coeff[i] *= v
+		}
+		c.fft.Sequence(c.buf, c.coeff)
+		y := c.buf[:n+m]
+
+		// Add the tail of the previous blocks and retain the
+		// tail of this block.
+		for i := 0; i < m; i++ {
+			y[i] += c.tail[i]
+		}
+		copy(out, y[:n])
+		copy(c.tail, y[n:])
+		x = x[n:]
+		out = out[n:]
+	}
+	return dst
+}
+
+// Reset clears the state of the convolver to the start of a new stream.
+func (c *OverlapAdd) Reset() {
+	for i := range c.tail {
+		c.tail[i] = 0
+	}
+}
+
+// OverlapSave performs the convolution of a stream of samples with a fixed
+// kernel using the overlap-save method. Each block of input is convolved
+// circularly with the kernel together with the preceding len(kernel)-1
+// input samples using FFTs, and the part of the result that is not
+// affected by the circular wrap-around is retained. The FFT plan and the
+// transform of the kernel are computed once and reused for all blocks.
+type OverlapSave struct {
+	block int
+	fft   *FFT
+	h     []complex128
+
+	buf   []float64
+	coeff []complex128
+	hist  []float64
+}
+
+// NewOverlapSave returns a new OverlapSave for the given kernel that
+// processes input in blocks of at least the given length. The block length
+// is increased to make the best use of the FFT length.
+//
+// NewOverlapSave will panic if kernel is empty or block is not positive.
+func NewOverlapSave(kernel []float64, block int) *OverlapSave {
+	fft, h, block := blockPlan(kernel, block)
+	return &OverlapSave{
+		block: block,
+		fft:   fft,
+		h:     h,
+		buf:   make([]float64, fft.Len()),
+		coeff: make([]complex128, len(h)),
+		hist:  make([]float64, len(kernel)-1),
+	}
+}
+
+// BlockLen returns the block length of the convolver. Process is most
+// efficient when the length of its input is a multiple of the block length.
+func (c *OverlapSave) BlockLen() int { return c.block }
+
+// Process convolves the samples in x with the kernel, continuing the
+// convolution of the samples passed in previous calls, placing the result
+// in dst and returning it. The output is the causal convolution described
+// for OverlapAdd.Process. dst and x may be the same slice.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of x, Process will panic.
+func (c *OverlapSave) Process(dst, x []float64) []float64 {
+	dst = useReal(dst, len(x))
+	out := dst
+	m := len(c.hist)
+	for len(x) > 0 {
+		n := c.block
+		if len(x) < n {
+			n = len(x)
+		}
+		copy(c.buf, c.hist)
+		copy(c.buf[m:], x[:n])
+		for i := m + n; i < len(c.buf); i++ {
+			c.buf[i] = 0
+		}
+		// Retain the last m input samples before x is overwritten
+		// in case dst and x are the same slice.
+		if n >= m {
+			copy(c.hist, x[n-m:n])
+		} else {
+			copy(c.hist, c.buf[n:n+m])
+		}
+		c.fft.Coefficients(c.coeff, c.buf)
+		for i, v := range c.h {
+			c.coeff[i] *= v
+		}
+		c.fft.Sequence(c.buf, c.coeff)
+		copy(out, c.buf[m:m+n])
+		x = x[n:]
+		out = out[n:]
+	}
+	return dst
+}
+
+// Reset clears the state of the convolver to the start of a new stream.
+func (c *OverlapSave) Reset() {
+	for i := range c.hist {
+		c.hist[i] = 0
+	}
+}
+
+// blockPlan returns the FFT, the scaled transform of the kernel and the
+// block length for block convolution with the kernel.
+func blockPlan(kernel []float64, block int) (*FFT, []complex128, int) {
+	if len(kernel) == 0 {
+		panic("fourier: empty kernel")
+	}
+	if block < 1 {
+		panic("fourier: block length must be positive")
+	}
+	n := fastLen(block + len(kernel) - 1)
+	fft := NewFFT(n)
+	buf := make([]float64, n)
+	copy(buf, kernel)
+	h := fft.Coefficients(nil, buf)
+	f := complex(1/float64(n), 0)
+	for i := range h {
+		h[i] *= f
+	}
+	return fft, h, n - len(kernel) + 1
+}
@@ -0,0 +1,59 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier_test
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+)
+
+func ExampleConvolve() {
+	// Smooth a sequence with a three point moving average.
+	x := []float64{0, 0, 3, 3, 3, 0, 0, 6}
+	h := []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}
+
+	fmt.Printf("full:  %.2f\n", fourier.Convolve(nil, x, h, fourier.Full))
+	fmt.Printf("same:  %.2f\n", fourier.Convolve(nil, x, h, fourier.Same))
+	fmt.Printf("valid: %.2f\n", fourier.Convolve(nil, x, h, fourier.Valid))
+
+	// Output:
+	// full:  [0.00 0.00 1.00 2.00 3.00 2.00 1.00 2.00 2.00 2.00]
+	// same:  [0.00 1.00 2.00 3.00 2.00 1.00 2.00 2.00]
+	// valid: [1.00 2.00 3.00 2.00 1.00 2.00]
+}
+
+func ExampleCorrelate() {
+	// Find the delay of a pulse in a signal.
+	pulse := []float64{1, 2, 1}
+	signal := []float64{0, 0, 0, 0, 0.5, 1, 0.5, 0, 0, 0}
+
+	c := fourier.Correlate(nil, signal, pulse, fourier.Full)
+	var lag int
+	for i, v := range c {
+		if v > c[lag] {
+			lag = i
+		}
+	}
+	// The zero lag is at index len(pulse)-1 of the full correlation.
+	fmt.Println("delay:", lag-(len(pulse)-1))
+
+	// Output:
+	// delay: 4
+}
+
+func ExampleOverlapSave() {
+	// Filter a stream that arrives in chunks of different lengths
+	// with a difference filter.
+	c := fourier.NewOverlapSave([]float64{1, -1}, 4)
+	for _, chunk := range [][]float64{{1, 2, 4}, {7, 11, 16, 22}, {29}} {
+		fmt.Printf("%.3g\n", c.Process(nil, chunk))
+	}
+
+	// Output:
+	// [1 1 2]
+	// [3 4 5 6]
+	// [7]
+}
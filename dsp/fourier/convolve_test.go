@@ -0,0 +1,267 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"fmt"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// naiveConvolve returns the full convolution of a and b.
+func naiveConvolve(a, b []complex128) []complex128 {
+	c := make([]complex128, len(a)+len(b)-1)
+	for i, x := range a {
+		for j, y := range b {
+			c[i+j] += x * y
+		}
+	}
+	return c
+}
+
+func randCmplx(rnd *rand.Rand, n int, real bool) []complex128 {
+	s := make([]complex128, n)
+	for i := range s {
+		if real {
+			s[i] = complex(rnd.NormFloat64(), 0)
+		} else {
+			s[i] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+		}
+	}
+	return s
+}
+
+func realPart(s []complex128) []float64 {
+	r := make([]float64, len(s))
+	for i, v := range s {
+		r[i] = real(v)
+	}
+	return r
+}
+
+func cmplxEqualApprox(a, b []complex128, tol float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if cmplx.Abs(a[i]-b[i]) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+func TestConvolve(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-9
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 17, 64, 100, 257} {
+		for _, m := range []int{1, 3, 8, 33, 120, 300} {
+			for _, real := range []bool{true, false} {
+				a := randCmplx(rnd, n, real)
+				b := randCmplx(rnd, m, real)
+				full := naiveConvolve(a, b)
+				for _, mode := range []ConvolutionMode{Full, Same, Valid} {
+					lo, hi := convRange(n, m, mode)
+					want := full[lo:hi]
+					name := fmt.Sprintf("n=%d m=%d mode=%d real=%t", n, m, mode, real)
+					switch mode {
+					case Same:
+						if len(want) != n {
+							t.Errorf("%s: unexpected length for same mode: %d", name, len(want))
+						}
+					case Valid:
+						if n >= m && len(want) != n-m+1 || n < m && len(want) != m-n+1 {
+							t.Errorf("%s: unexpected length for valid mode: %d", name, len(want))
+						}
+					}
+
+					// Check both methods regardless of the automatic choice.
+					got := make([]complex128, len(want))
+					directCmplxConvolve(got, a, b, lo)
+					if !cmplxEqualApprox(got, want, tol) {
+						t.Errorf("%s: unexpected direct complex convolution", name)
+					}
+					fftCmplxConvolve(got, a, b, lo)
+					if !cmplxEqualApprox(got, want, tol) {
+						t.Errorf("%s: unexpected FFT complex convolution", name)
+					}
+					got = CmplxConvolve(nil, a, b, mode)
+					if !cmplxEqualApprox(got, want, tol) {
+						t.Errorf("%s: unexpected complex convolution", name)
+					}
+					if !real {
+						continue
+					}
+
+					ra, rb, rwant := realPart(a), realPart(b), realPart(want)
+					rgot := make([]float64, len(want))
+					directConvolve(rgot, ra, rb, lo)
+					if !floats.EqualApprox(rgot, rwant, tol) {
+						t.Errorf("%s: unexpected direct convolution", name)
+					}
+					fftConvolve(rgot, ra, rb, lo)
+					if !floats.EqualApprox(rgot, rwant, tol) {
+						t.Errorf("%s: unexpected FFT convolution", name)
+					}
+					rgot = Convolve(nil, ra, rb, mode)
+					if !floats.EqualApprox(rgot, rwant, tol) {
+						t.Errorf("%s: unexpected convolution", name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCorrelate(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-9
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 7, 100, 300} {
+		for _, m := range []int{1, 4, 90} {
+			a := randCmplx(rnd, n, false)
+			b := randCmplx(rnd, m, false)
+			got := CmplxCorrelate(nil, a, b, Full)
+			for k := range got {
+				lag := k - m + 1
+				var want complex128
+				for j := range b {
+					if i := j + lag; 0 <= i && i < n {
+						want += a[i] * cmplx.Conj(b[j])
+					}
+				}
+				if cmplx.Abs(got[k]-want) > tol {
+					t.Errorf("n=%d m=%d: unexpected complex correlation at lag %d: got %v, want %v", n, m, lag, got[k], want)
+				}
+			}
+
+			ra, rb := realPart(a), realPart(b)
+			rgot := Correlate(nil, ra, rb, Full)
+			for k := range rgot {
+				lag := k - m + 1
+				var want float64
+				for j := range rb {
+					if i := j + lag; 0 <= i && i < n {
+						want += ra[i] * rb[j]
+					}
+				}
+				if diff := rgot[k] - want; diff > tol || diff < -tol {
+					t.Errorf("n=%d m=%d: unexpected correlation at lag %d: got %v, want %v", n, m, lag, rgot[k], want)
+				}
+			}
+		}
+	}
+}
+
+func TestFastLen(t *testing.T) {
+	t.Parallel()
+	for n := 1; n <= 5000; n++ {
+		got := fastLen(n)
+		var want int
+		for want = n; ; want++ {
+			v := want
+			for _, p := range []int{2, 3, 5} {
+				for v%p == 0 {
+					v /= p
+				}
+			}
+			if v == 1 {
+				break
+			}
+		}
+		if got != want {
+			t.Errorf("unexpected fast length for %d: got %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestBlockConvolvers(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, 2000)
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+	}
+	for _, m := range []int{1, 2, 31, 200} {
+		kernel := make([]float64, m)
+		for i := range kernel {
+			kernel[i] = rnd.NormFloat64()
+		}
+		full := Convolve(nil, x, kernel, Full)
+		for _, block := range []int{1, 16, 100, 512} {
+			for _, c := range []interface {
+				Process(dst, x []float64) []float64
+				Reset()
+				BlockLen() int
+			}{
+				NewOverlapAdd(kernel, block),
+				NewOverlapSave(kernel, block),
+			} {
+				name := fmt.Sprintf("%T m=%d block=%d", c, m, block)
+				if c.BlockLen() < block {
+					t.Errorf("%s: block length %d less than requested", name, c.BlockLen())
+				}
+				got := c.Process(nil, x)
+				if !floats.EqualApprox(got, full[:len(x)], tol) {
+					t.Errorf("%s: unexpected result", name)
+				}
+				// Processing zeros flushes the tail of the convolution.
+				tail := c.Process(nil, make([]float64, m-1))
+				if !floats.EqualApprox(tail, full[len(x):], tol) {
+					t.Errorf("%s: unexpected tail", name)
+				}
+
+				// Processing in place in chunks of random length
+				// gives the same result.
+				c.Reset()
+				got = append([]float64(nil), x...)
+				for i := 0; i < len(got); {
+					n := rnd.Intn(300) + 1
+					if i+n > len(got) {
+						n = len(got) - i
+					}
+					c.Process(got[i:i+n], got[i:i+n])
+					i += n
+				}
+				if !floats.EqualApprox(got, full[:len(x)], tol) {
+					t.Errorf("%s: unexpected result for chunked processing", name)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkConvolve(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{100, 1000, 10000} {
+		for _, m := range []int{8, 32, 128, 1024} {
+			x := make([]float64, n)
+			for i := range x {
+				x[i] = rnd.NormFloat64()
+			}
+			h := make([]float64, m)
+			for i := range h {
+				h[i] = rnd.NormFloat64()
+			}
+			dst := make([]float64, n+m-1)
+			b.Run(fmt.Sprintf("direct n=%d m=%d", n, m), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					directConvolve(dst, x, h, 0)
+				}
+			})
+			b.Run(fmt.Sprintf("fft n=%d m=%d", n, m), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					fftConvolve(dst, x, h, 0)
+				}
+			})
+		}
+	}
+}
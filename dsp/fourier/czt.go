@@ -0,0 +1,217 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// CZT implements the chirp z-transform, which evaluates the z-transform of
+// a finite sequence of length n,
+//
+//	X[k] = \sum_{j=0}^{n-1} x[j] * z_k^{-j},
+//
+// at the m points z_k = A * W^{-k} for k = 0, ..., m-1, which lie on a
+// spiral or circular arc in the complex plane starting at A. For
+// A = 1 and W = exp(-2πi/n) with m = n, the chirp z-transform is the
+// discrete Fourier transform. The transform is computed with FFTs using
+// Bluestein's algorithm in O((n+m) log(n+m)) operations.
+//
+// References:
+//   - Rabiner, L. R., Schafer, R. W. and Rader, C. M. (1969). The chirp
+//     z-transform algorithm. IEEE Transactions on Audio and
+//     Electroacoustics, 17(2), 86-92.
+//   - Bluestein, L. I. (1970). A linear filtering approach to the
+//     computation of discrete Fourier transform. IEEE Transactions on
+//     Audio and Electroacoustics, 18(4), 451-455.
+type CZT struct {
+	a, w complex128
+
+	// pre holds A^{-j} W^{j²/2} for the n input samples and post holds
+	// W^{k²/2} for the m outputs.
+	pre, post []complex128
+	// filt holds the scaled Fourier coefficients of the chirp filter
+	// W^{-j²/2} for j = -(n-1), ..., m-1.
+	filt []complex128
+	fft  *CmplxFFT
+	buf  []complex128
+}
+
+// NewCZT returns a CZT for sequences of length n evaluating the
+// z-transform at the m points A * W^{-k}, k = 0, ..., m-1.
+//
+// NewCZT will panic if n or m is not positive, or if a or w is zero.
+func NewCZT(n, m int, w, a complex128) *CZT {
+	if w == 0 || a == 0 {
+		panic("fourier: zero chirp parameter")
+	}
+	logW := cmplx.Log(w)
+	return newCZT(n, m, a, w, func(j int) complex128 {
+		// W^{j²/2} computed from the logarithm of W to
+		// avoid the branch cut of the half power.
+		return cmplx.Exp(logW * complex(float64(j)*float64(j)/2, 0))
+	})
+}
+
+// NewZoomCZT returns a CZT for sequences of length n evaluating the
+// discrete-time Fourier transform at the m equally spaced relative
+// frequencies f0 + k*(f1-f0)/m cycles per sample, k = 0, ..., m-1, on the
+// unit circle. The frequencies may be scaled by a sample rate to give the
+// frequencies in the units of the sample rate. The spectrum of a band can
+// be computed with a finer frequency resolution than the DFT of the
+// sequence provides.
+//
+// NewZoomCZT will panic if n or m is not positive.
+func NewZoomCZT(n, m int, f0, f1 float64) *CZT {
+	step := (f1 - f0) / float64(m)
+	w := cmplx.Rect(1, -2*math.Pi*step)
+	a := cmplx.Rect(1, 2*math.Pi*f0)
+	return newCZT(n, m, a, w, func(j int) complex128 {
+		// Compute the chirp from its phase so that it lies
+		// exactly on the unit circle.
+		return cmplx.Rect(1, -math.Pi*step*float64(j)*float64(j))
+	})
+}
+
+// newDFTCZT returns a CZT computing the unnormalized DFT of sequences of
+// length n.
+func newDFTCZT(n int) *CZT {
+	w := cmplx.Rect(1, -2*math.Pi/float64(n))
+	return newCZT(n, n, 1, w, func(j int) complex128 {
+		// exp(-iπj²/n) has period 2n in j², so reduce j² modulo 2n
+		// for an exact phase argument.
+		if j < 0 {
+			j = -j
+		}
+		j2 := (j * j) % (2 * n)
+		return cmplx.Rect(1, -math.Pi*float64(j2)/float64(n))
+	})
+}
+
+// newCZT returns a CZT for the points A * W^{-k}, where chirp returns
+// W^{j²/2} for the integer j.
+func newCZT(n, m int, a, w complex128, chirp func(j int) complex128) *CZT {
+	if n < 1 || m < 1 {
+		panic("fourier: length must be positive")
+	}
+	l := fastLen(n + m - 1)
+	t := &CZT{
+		a:    a,
+		w:    w,
+		pre:  make([]complex128, n),
+		post: make([]complex128, m),
+		filt: make([]complex128, l),
+		fft:  NewCmplxFFT(l),
+		buf:  make([]complex128, l),
+	}
+	// The powers of A are computed directly rather than
+	// accumulated to avoid the growth of rounding errors.
+	logA := cmplx.Log(a)
+	for j := range t.pre {
+		t.pre[j] = cmplx.Exp(-logA*complex(float64(j), 0)) * chirp(j)
+	}
+	for k := range t.post {
+		t.post[k] = chirp(k)
+	}
+	for j := 0; j < m; j++ {
+		t.filt[j] = 1 / chirp(j)
+	}
+	for j := 1; j < n; j++ {
+		t.filt[l-j] = 1 / chirp(j)
+	}
+	t.fft.Coefficients(t.filt, t.filt)
+	f := complex(1/float64(l), 0)
+	for i := range t.filt {
+		t.filt[i] *= f
+	}
+	return t
+}
+
+// Len returns the length of the acceptable input.
+func (t *CZT) Len() int { return len(t.pre) }
+
+// Points returns the number of points at which the z-transform is
+// evaluated.
+func (t *CZT) Points() int { return len(t.post) }
+
+// Point returns the point z_k = A * W^{-k} at which the coefficient k is
+// evaluated. Point will panic if k is negative or greater than or equal to
+// t.Points().
+func (t *CZT) Point(k int) complex128 {
+	if k < 0 || t.Points() <= k {
+		panic("fourier: index out of range")
+	}
+	return t.a * cmplx.Pow(t.w, complex(-float64(k), 0))
+}
+
+// Transform computes the chirp z-transform of seq, placing the result in
+// dst and returning it.
+//
+// If the length of seq is not t.Len(), Transform will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal t.Points(), Transform will panic.
+// It is safe to use the same slice for dst and seq if they have the same
+// length.
+func (t *CZT) Transform(dst, seq []complex128) []complex128 {
+	if len(seq) != t.Len() {
+		panic("fourier: sequence length mismatch")
+	}
+	if dst == nil {
+		dst = make([]complex128, t.Points())
+	} else if len(dst) != t.Points() {
+		panic("fourier: destination length mismatch")
+	}
+	for j, v := range seq {
+		t.buf[j] = v * t.pre[j]
+	}
+	for j := len(seq); j < len(t.buf); j++ {
+		t.buf[j] = 0
+	}
+	t.fft.Coefficients(t.buf, t.buf)
+	for i, v := range t.filt {
+		t.buf[i] *= v
+	}
+	t.fft.Sequence(t.buf, t.buf)
+	for k, v := range t.post {
+		dst[k] = v * t.buf[k]
+	}
+	return dst
+}
+
+// transformInverse computes the unnormalized inverse DFT of seq for a CZT
+// returned by newDFTCZT, placing the result in dst.
+func (t *CZT) transformInverse(dst, seq []complex128) {
+	for j, v := range seq {
+		dst[j] = cmplx.Conj(v)
+	}
+	t.Transform(dst, dst)
+	for j, v := range dst {
+		dst[j] = cmplx.Conj(v)
+	}
+}
+
+// useBluestein returns whether the DFT of length n is computed using
+// Bluestein's algorithm. The cost of the mixed radix FFT is proportional
+// to n times the largest prime factor of n, while the cost of Bluestein's
+// algorithm is proportional to n log n with a larger constant factor.
+func useBluestein(n int) bool {
+	return float64(largestPrimeFactor(n)) > 10*math.Log2(float64(2*n))
+}
+
+// largestPrimeFactor returns the largest prime factor of n, or 1 if n is 1.
+func largestPrimeFactor(n int) int {
+	p := 1
+	for f := 2; f*f <= n; f++ {
+		for n%f == 0 {
+			p = f
+			n /= f
+		}
+	}
+	if n > 1 {
+		p = n
+	}
+	return p
+}
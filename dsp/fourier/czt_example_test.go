@@ -0,0 +1,43 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier_test
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+)
+
+func ExampleNewZoomCZT() {
+	// A 1 s record sampled at 1 kHz of a tone at 100.3 Hz.
+	const (
+		fs = 1000.0
+		n  = 1000
+	)
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(math.Sin(2*math.Pi*100.3*float64(i)/fs), 0)
+	}
+
+	// The DFT bins are spaced by 1 Hz. Zoom into the
+	// band from 99 to 102 Hz with a spacing of 0.1 Hz.
+	const m = 30
+	f0, f1 := 99/fs, 102/fs
+	czt := fourier.NewZoomCZT(n, m, f0, f1)
+	coeff := czt.Transform(nil, x)
+
+	var peak int
+	for k, c := range coeff {
+		if cmplx.Abs(c) > cmplx.Abs(coeff[peak]) {
+			peak = k
+		}
+	}
+	fmt.Printf("peak at %.1f Hz\n", (f0+float64(peak)*(f1-f0)/m)*fs)
+
+	// Output:
+	// peak at 100.3 Hz
+}
@@ -0,0 +1,180 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// naiveDFT returns the unnormalized DFT of x computed by direct summation.
+func naiveDFT(x []complex128, inverse bool) []complex128 {
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	n := len(x)
+	y := make([]complex128, n)
+	for k := range y {
+		var s complex128
+		for j, v := range x {
+			s += v * cmplx.Rect(1, sign*2*math.Pi*float64((j*k)%n)/float64(n))
+		}
+		y[k] = s
+	}
+	return y
+}
+
+func TestCZT(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-9
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, m int
+		w, a complex128
+	}{
+		{n: 1, m: 1, w: cmplx.Rect(1, -0.3), a: 1},
+		{n: 8, m: 8, w: cmplx.Rect(1, -2*math.Pi/8), a: 1},
+		{n: 10, m: 25, w: cmplx.Rect(1, -0.1), a: cmplx.Rect(1, 0.5)},
+		{n: 33, m: 7, w: cmplx.Rect(1.01, 0.2), a: cmplx.Rect(0.9, -1)},
+		{n: 20, m: 20, w: cmplx.Rect(0.995, -0.2), a: 1.1},
+	} {
+		name := fmt.Sprintf("n=%d m=%d", test.n, test.m)
+		x := make([]complex128, test.n)
+		for i := range x {
+			x[i] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+		}
+		czt := NewCZT(test.n, test.m, test.w, test.a)
+		if czt.Len() != test.n || czt.Points() != test.m {
+			t.Errorf("%s: unexpected dimensions: %d %d", name, czt.Len(), czt.Points())
+		}
+		got := czt.Transform(nil, x)
+		for k, v := range got {
+			z := test.a * cmplx.Pow(test.w, complex(-float64(k), 0))
+			if cmplx.Abs(czt.Point(k)-z) > tol {
+				t.Errorf("%s: unexpected point %d: got %v, want %v", name, k, czt.Point(k), z)
+			}
+			var want complex128
+			for j, xj := range x {
+				want += xj * cmplx.Pow(z, complex(-float64(j), 0))
+			}
+			if cmplx.Abs(v-want) > tol*math.Max(1, cmplx.Abs(want)) {
+				t.Errorf("%s: unexpected coefficient %d: got %v, want %v", name, k, v, want)
+			}
+		}
+	}
+}
+
+func TestZoomCZT(t *testing.T) {
+	t.Parallel()
+	const (
+		tol = 1e-9
+		n   = 200
+		m   = 64
+	)
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(rnd.NormFloat64(), 0)
+	}
+	for _, band := range [][2]float64{{0.1, 0.15}, {-0.2, 0.3}, {0.45, 0.4}} {
+		f0, f1 := band[0], band[1]
+		got := NewZoomCZT(n, m, f0, f1).Transform(nil, x)
+		for k, v := range got {
+			f := f0 + float64(k)*(f1-f0)/m
+			var want complex128
+			for j, xj := range x {
+				want += xj * cmplx.Rect(1, -2*math.Pi*f*float64(j))
+			}
+			if cmplx.Abs(v-want) > tol {
+				t.Errorf("band %v: unexpected coefficient at %v: got %v, want %v", band, f, v, want)
+			}
+		}
+	}
+
+	// A zoom over the whole circle is the DFT.
+	got := NewZoomCZT(n, n, 0, 1).Transform(nil, x)
+	if !cmplxEqualApprox(got, naiveDFT(x, false), tol) {
+		t.Errorf("unexpected result for whole circle zoom")
+	}
+}
+
+func TestBluestein(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		n    int
+		want bool
+	}{
+		{n: 1, want: false},
+		{n: 61, want: false},
+		{n: 83, want: true},
+		{n: 1024, want: false},
+		{n: 997, want: true},
+		{n: 2 * 997, want: true},
+		{n: 16 * 101, want: false},
+	} {
+		if got := useBluestein(test.n); got != test.want {
+			t.Errorf("unexpected Bluestein use for length %d: got %t, want %t", test.n, got, test.want)
+		}
+	}
+
+	const tol = 1e-9
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{83, 997, 1994, 1009, 2 * 1009 * 3} {
+		x := make([]complex128, n)
+		r := make([]float64, n)
+		for i := range x {
+			x[i] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+			r[i] = real(x[i])
+		}
+
+		cfft := NewCmplxFFT(n)
+		if cfft.czt == nil {
+			t.Errorf("Bluestein not used for length %d", n)
+		}
+		want := naiveDFT(x, false)
+		got := cfft.Coefficients(nil, x)
+		if !cmplxEqualApprox(got, want, tol*math.Sqrt(float64(n))) {
+			t.Errorf("unexpected complex coefficients for length %d", n)
+		}
+		seq := cfft.Sequence(nil, got)
+		for i := range seq {
+			seq[i] /= complex(float64(n), 0)
+		}
+		if !cmplxEqualApprox(seq, x, tol) {
+			t.Errorf("unexpected complex round trip for length %d", n)
+		}
+
+		fft := NewFFT(n)
+		rwant := make([]complex128, n)
+		for i, v := range r {
+			rwant[i] = complex(v, 0)
+		}
+		rwant = naiveDFT(rwant, false)[:n/2+1]
+		rgot := fft.Coefficients(nil, r)
+		if !cmplxEqualApprox(rgot, rwant, tol*math.Sqrt(float64(n))) {
+			t.Errorf("unexpected real coefficients for length %d", n)
+		}
+		rseq := fft.Sequence(nil, rgot)
+		floats.Scale(1/float64(n), rseq)
+		if !floats.EqualApprox(rseq, r, tol) {
+			t.Errorf("unexpected real round trip for length %d", n)
+		}
+
+		// Reset to a length without large prime factors returns
+		// to the mixed radix FFT.
+		cfft.Reset(64)
+		fft.Reset(64)
+		if cfft.czt != nil || fft.czt != nil {
+			t.Errorf("Bluestein used after reset from length %d", n)
+		}
+	}
+}
@@ -3,4 +3,10 @@
 // license that can be found in the LICENSE file.
 
 // Package fourier provides functions to perform Discrete Fourier Transforms.
+//
+// One-dimensional transforms of real and complex sequences are provided by
+// FFT and CmplxFFT, and multidimensional transforms of arrays stored in
+// row-major order by FFTN and CmplxFFTN. AnalyticSignal computes the
+// analytic signal of a real sequence using the Hilbert transform, from
+// which the instantaneous amplitude, phase and frequency are obtained.
 package fourier // import "gonum.org/v1/gonum/dsp/fourier"
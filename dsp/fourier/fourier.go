@@ -4,13 +4,26 @@
 
 package fourier
 
-import "gonum.org/v1/gonum/dsp/fourier/internal/fftpack"
+import (
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/dsp/fourier/internal/fftpack"
+)
 
 // FFT implements Fast Fourier Transform and its inverse for real sequences.
+//
+// Transforms of lengths with large prime factors are computed using
+// Bluestein's algorithm, so the cost of a transform is O(n log n) for any
+// length n.
 type FFT struct {
 	work []float64
 	ifac [15]int
 
+	// czt and cbuf are used for lengths that are
+	// transformed with Bluestein's algorithm.
+	czt  *CZT
+	cbuf []complex128
+
 	// real temporarily store complex data as
 	// pairs of real values to allow passing to
 	// the backing code. The length of real
@@ -37,6 +50,13 @@ func (t *FFT) Reset(n int) {
 		t.work = make([]float64, 2*n)
 		t.real = make([]float64, n)
 	}
+	if useBluestein(n) {
+		t.czt = newDFTCZT(n)
+		t.cbuf = make([]complex128, n)
+		return
+	}
+	t.czt = nil
+	t.cbuf = nil
 	fftpack.Rffti(n, t.work, t.ifac[:])
 }
 
@@ -58,6 +78,14 @@ func (t *FFT) Coefficients(dst []complex128, seq []float64) []complex128 {
 	} else if len(dst) != t.Len()/2+1 {
 		panic("fourier: destination length mismatch")
 	}
+	if t.czt != nil {
+		for i, v := range seq {
+			t.cbuf[i] = complex(v, 0)
+		}
+		t.czt.Transform(t.cbuf, t.cbuf)
+		copy(dst, t.cbuf)
+		return dst
+	}
 	copy(t.real, seq)
 	fftpack.Rfftf(len(t.real), t.real, t.work, t.ifac[:])
 	dst[0] = complex(t.real[0], 0)
@@ -93,6 +121,27 @@ func (t *FFT) Sequence(dst []float64, coeff []complex128) []float64 {
 	} else if len(dst) != t.Len() {
 		panic("fourier: destination length mismatch")
 	}
+	if t.czt != nil {
+		// Construct the Hermitian spectrum. The imaginary
+		// parts of the zero and Nyquist frequency coefficients
+		// are ignored as they are by the mixed radix FFT.
+		n := len(dst)
+		for i, cv := range coeff {
+			t.cbuf[i] = cv
+			if i != 0 {
+				t.cbuf[n-i] = cmplx.Conj(cv)
+			}
+		}
+		t.cbuf[0] = complex(real(coeff[0]), 0)
+		if n%2 == 0 {
+			t.cbuf[n/2] = complex(real(coeff[n/2]), 0)
+		}
+		t.czt.transformInverse(t.cbuf, t.cbuf)
+		for i, cv := range t.cbuf {
+			dst[i] = real(cv)
+		}
+		return dst
+	}
 	dst[0] = real(coeff[0])
 	if len(dst) < 2 {
 		return dst
@@ -124,10 +173,18 @@ func (t *FFT) Freq(i int) float64 {
 }
 
 // CmplxFFT implements Fast Fourier Transform and its inverse for complex sequences.
+//
+// Transforms of lengths with large prime factors are computed using
+// Bluestein's algorithm, so the cost of a transform is O(n log n) for any
+// length n.
 type CmplxFFT struct {
 	work []float64
 	ifac [15]int
 
+	// czt is used for lengths that are transformed
+	// with Bluestein's algorithm.
+	czt *CZT
+
 	// real temporarily store complex data as
 	// pairs of real values to allow passing to
 	// the backing code. The length of real
@@ -154,6 +211,11 @@ func (t *CmplxFFT) Reset(n int) {
 		t.work = make([]float64, 4*n)
 		t.real = make([]float64, 2*n)
 	}
+	if useBluestein(n) {
+		t.czt = newDFTCZT(n)
+		return
+	}
+	t.czt = nil
 	fftpack.Cffti(n, t.work, t.ifac[:])
 }
 
@@ -176,6 +238,9 @@ func (t *CmplxFFT) Coefficients(dst, seq []complex128) []complex128 {
 	} else if len(dst) != len(seq) {
 		panic("fourier: destination length mismatch")
 	}
+	if t.czt != nil {
+		return t.czt.Transform(dst, seq)
+	}
 	for i, cv := range seq {
 		t.real[2*i] = real(cv)
 		t.real[2*i+1] = imag(cv)
@@ -206,6 +271,10 @@ func (t *CmplxFFT) Sequence(dst, coeff []complex128) []complex128 {
 	} else if len(dst) != len(coeff) {
 		panic("fourier: destination length mismatch")
 	}
+	if t.czt != nil {
+		t.czt.transformInverse(dst, coeff)
+		return dst
+	}
 	for i, cv := range coeff {
 		t.real[2*i] = real(cv)
 		t.real[2*i+1] = imag(cv)
@@ -0,0 +1,104 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// AnalyticSignal computes the analytic signal of the real sequence x,
+// placing the result into dst and returning it. The real part of the
+// analytic signal is x and its imaginary part is the Hilbert transform of
+// x. The analytic signal is computed by removing the negative frequency
+// components of the discrete Fourier transform of x and doubling the
+// positive frequency components, so x is treated as periodic.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of x, AnalyticSignal
+// will panic.
+func AnalyticSignal(dst []complex128, x []float64) []complex128 {
+	n := len(x)
+	dst = useCmplx(dst, n)
+	if n == 0 {
+		return dst
+	}
+	coeff := NewFFT(n).Coefficients(nil, x)
+	for i := range dst {
+		dst[i] = 0
+	}
+	scale := 1 / float64(n)
+	dst[0] = coeff[0] * complex(scale, 0)
+	for k := 1; k < len(coeff); k++ {
+		if 2*k == n {
+			// The Nyquist frequency component is its own
+			// negative frequency component.
+			dst[k] = coeff[k] * complex(scale, 0)
+		} else {
+			dst[k] = coeff[k] * complex(2*scale, 0)
+		}
+	}
+	NewCmplxFFT(n).Sequence(dst, dst)
+	// The real part of the analytic signal is x.
+	for i, v := range x {
+		dst[i] = complex(v, imag(dst[i]))
+	}
+	return dst
+}
+
+// InstantaneousAmplitude computes the instantaneous amplitude, or
+// envelope, of the analytic signal z, |z|, placing the result into dst and
+// returning it.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of z,
+// InstantaneousAmplitude will panic.
+func InstantaneousAmplitude(dst []float64, z []complex128) []float64 {
+	dst = useReal(dst, len(z))
+	for i, v := range z {
+		dst[i] = cmplx.Abs(v)
+	}
+	return dst
+}
+
+// InstantaneousPhase computes the unwrapped instantaneous phase of the
+// analytic signal z in radians, placing the result into dst and returning
+// it. The phase is unwrapped by choosing the difference between consecutive
+// phases in (-π, π].
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of z, InstantaneousPhase
+// will panic.
+func InstantaneousPhase(dst []float64, z []complex128) []float64 {
+	dst = useReal(dst, len(z))
+	if len(z) == 0 {
+		return dst
+	}
+	dst[0] = cmplx.Phase(z[0])
+	for i := 1; i < len(z); i++ {
+		dst[i] = dst[i-1] + cmplx.Phase(z[i]*cmplx.Conj(z[i-1]))
+	}
+	return dst
+}
+
+// InstantaneousFrequency computes the instantaneous frequency of the
+// analytic signal z sampled at the rate fs, placing the result into dst and
+// returning it. Element i of the result is the rate of change of the phase
+// between samples i and i+1, in the units of fs.
+//
+// If dst is nil, a new slice of length len(z)-1 is allocated and returned.
+// If dst is not nil and the length of dst does not equal len(z)-1,
+// InstantaneousFrequency will panic. InstantaneousFrequency will panic if
+// z is empty.
+func InstantaneousFrequency(dst []float64, z []complex128, fs float64) []float64 {
+	if len(z) == 0 {
+		panic("fourier: empty signal")
+	}
+	dst = useReal(dst, len(z)-1)
+	for i := range dst {
+		dst[i] = cmplx.Phase(z[i+1]*cmplx.Conj(z[i])) * fs / (2 * math.Pi)
+	}
+	return dst
+}
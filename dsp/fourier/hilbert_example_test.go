@@ -0,0 +1,39 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier_test
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+)
+
+func ExampleAnalyticSignal() {
+	// A 1 s record sampled at 1 kHz of a chirp whose
+	// frequency increases linearly from 50 Hz to 150 Hz,
+	// with an amplitude that decays exponentially.
+	const (
+		fs = 1000.0
+		n  = 1000
+	)
+	x := make([]float64, n)
+	for i := range x {
+		t := float64(i) / fs
+		x[i] = math.Exp(-t) * math.Cos(2*math.Pi*(50*t+50*t*t))
+	}
+
+	z := fourier.AnalyticSignal(nil, x)
+	amp := fourier.InstantaneousAmplitude(nil, z)
+	freq := fourier.InstantaneousFrequency(nil, z, fs)
+	for _, i := range []int{250, 500, 750} {
+		fmt.Printf("t=%.2fs amplitude=%.2f frequency=%.0f Hz\n", float64(i)/fs, amp[i], freq[i])
+	}
+
+	// Output:
+	// t=0.25s amplitude=0.78 frequency=75 Hz
+	// t=0.50s amplitude=0.61 frequency=100 Hz
+	// t=0.75s amplitude=0.47 frequency=125 Hz
+}
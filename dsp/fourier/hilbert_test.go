@@ -0,0 +1,112 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestAnalyticSignal(t *testing.T) {
+	t.Parallel()
+	for _, n := range []int{1, 2, 7, 16, 97, 128} {
+		for k := 1; 2*k < n; k++ {
+			// The analytic signal of a sinusoid with an
+			// integer number of periods is a complex
+			// exponential.
+			const phase = 0.3
+			x := make([]float64, n)
+			want := make([]complex128, n)
+			for i := range x {
+				theta := 2*math.Pi*float64(k*i)/float64(n) + phase
+				x[i] = 2 * math.Cos(theta)
+				want[i] = cmplx.Rect(2, theta)
+			}
+			got := AnalyticSignal(nil, x)
+			if !cmplxEqualApprox(got, want, 1e-12) {
+				t.Errorf("unexpected analytic signal for n=%d k=%d:\ngot: %v\nwant:%v", n, k, got, want)
+			}
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 7, 16, 97, 128} {
+		x := realPart(randCmplx(rnd, n, true))
+		z := AnalyticSignal(nil, x)
+		if !floats.EqualApprox(realPart(z), x, 1e-14) {
+			t.Errorf("real part of analytic signal does not match sequence for n=%d", n)
+		}
+		// The Hilbert transform has no zero or Nyquist frequency
+		// components and applying it twice negates the remaining
+		// components.
+		h := make([]float64, n)
+		for i, v := range z {
+			h[i] = imag(v)
+		}
+		if sum := floats.Sum(h); math.Abs(sum) > 1e-12 {
+			t.Errorf("Hilbert transform has non-zero mean for n=%d: %v", n, sum)
+		}
+		hh := AnalyticSignal(nil, h)
+		coeff := NewFFT(n).Coefficients(nil, x)
+		coeff[0] = 0
+		if n%2 == 0 {
+			coeff[n/2] = 0
+		}
+		want := NewFFT(n).Sequence(nil, coeff)
+		floats.Scale(-1/float64(n), want)
+		for i, v := range hh {
+			if math.Abs(imag(v)-want[i]) > 1e-12 {
+				t.Errorf("unexpected repeated Hilbert transform for n=%d: got:%v want:%v", n, imag(v), want[i])
+				break
+			}
+		}
+	}
+}
+
+func TestInstantaneous(t *testing.T) {
+	t.Parallel()
+	const (
+		n  = 1000
+		fs = 500.0
+		f0 = 50.0
+	)
+	// An amplitude modulated tone.
+	x := make([]float64, n)
+	env := make([]float64, n)
+	for i := range x {
+		ti := float64(i) / fs
+		env[i] = 1 + 0.5*math.Cos(2*math.Pi*2*ti)
+		x[i] = env[i] * math.Cos(2*math.Pi*f0*ti)
+	}
+	z := AnalyticSignal(nil, x)
+
+	amp := InstantaneousAmplitude(nil, z)
+	if !floats.EqualApprox(amp, env, 1e-12) {
+		t.Errorf("unexpected instantaneous amplitude")
+	}
+	phase := InstantaneousPhase(nil, z)
+	for i, p := range phase {
+		want := 2 * math.Pi * f0 * float64(i) / fs
+		if math.Abs(p-want) > 1e-10 {
+			t.Errorf("unexpected instantaneous phase at %d: got:%v want:%v", i, p, want)
+			break
+		}
+	}
+	freq := InstantaneousFrequency(nil, z, fs)
+	if len(freq) != n-1 {
+		t.Fatalf("unexpected length of instantaneous frequency: got:%d want:%d", len(freq), n-1)
+	}
+	for i, f := range freq {
+		if math.Abs(f-f0) > 1e-10 {
+			t.Errorf("unexpected instantaneous frequency at %d: got:%v want:%v", i, f, f0)
+			break
+		}
+	}
+}
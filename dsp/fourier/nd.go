@@ -0,0 +1,674 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"runtime"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// minParallelLen is the minimum number of elements in an array for
+// the lines of a multidimensional transform to be computed concurrently.
+const minParallelLen = 1 << 14
+
+// CmplxFFTN implements Fast Fourier Transforms and their inverses for
+// multidimensional arrays of complex values stored in row-major order,
+// with the last dimension varying fastest. The transform along each axis
+// is computed by one-dimensional transforms of the lines along that axis,
+// which are distributed over goroutines for large arrays.
+type CmplxFFTN struct {
+	dims  []int
+	lines lines
+}
+
+// NewCmplxFFTN returns a CmplxFFTN initialized for work on arrays with the
+// given dimensions. NewCmplxFFTN will panic if no dimensions are given or
+// any dimension is not positive.
+func NewCmplxFFTN(dims ...int) *CmplxFFTN {
+	checkDims(dims)
+	t := &CmplxFFTN{dims: append([]int(nil), dims...)}
+	t.SetWorkers(0)
+	return t
+}
+
+// SetWorkers sets the maximum number of goroutines used to compute a
+// transform. If n is less than one, the number of goroutines is
+// runtime.GOMAXPROCS(0). Transforms of small arrays are computed by the
+// calling goroutine.
+func (t *CmplxFFTN) SetWorkers(n int) { t.lines.setWorkers(n) }
+
+// Dims returns the dimensions of the arrays transformed by t.
+func (t *CmplxFFTN) Dims() []int { return append([]int(nil), t.dims...) }
+
+// Len returns the number of elements of the arrays transformed by t.
+func (t *CmplxFFTN) Len() int { return product(t.dims) }
+
+// Coefficients computes the Fourier coefficients of the complex array in
+// seq along the given axes, placing the result in dst and returning it. If
+// no axes are given, the transform is computed along all axes. This
+// transform is unnormalized; a call to Coefficients followed by a call of
+// Sequence along the same axes will multiply the input array by the
+// product of the dimensions of the axes.
+//
+// If the length of seq is not t.Len(), Coefficients will panic. If an axis
+// is out of range or repeated, Coefficients will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of seq, Coefficients will panic.
+// It is safe to use the same slice for dst and seq.
+func (t *CmplxFFTN) Coefficients(dst, seq []complex128, axes ...int) []complex128 {
+	return t.transform(dst, seq, axes, false)
+}
+
+// Sequence computes the complex array from the Fourier coefficients in
+// coeff along the given axes, placing the result in dst and returning it.
+// If no axes are given, the transform is computed along all axes. This
+// transform is unnormalized; a call to Coefficients followed by a call of
+// Sequence along the same axes will multiply the input array by the
+// product of the dimensions of the axes.
+//
+// If the length of coeff is not t.Len(), Sequence will panic. If an axis
+// is out of range or repeated, Sequence will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of coeff, Sequence will panic.
+// It is safe to use the same slice for dst and coeff.
+func (t *CmplxFFTN) Sequence(dst, coeff []complex128, axes ...int) []complex128 {
+	return t.transform(dst, coeff, axes, true)
+}
+
+func (t *CmplxFFTN) transform(dst, src []complex128, axes []int, inverse bool) []complex128 {
+	if len(src) != t.Len() {
+		panic("fourier: sequence length mismatch")
+	}
+	if dst == nil {
+		dst = make([]complex128, len(src))
+	} else if len(dst) != len(src) {
+		panic("fourier: destination length mismatch")
+	}
+	axes = checkAxes(axes, len(t.dims))
+	copy(dst, src)
+	for _, axis := range axes {
+		t.lines.cmplxAxis(dst, t.dims, axis, inverse)
+	}
+	return dst
+}
+
+// CoefficientsDense computes the two-dimensional Fourier coefficients of
+// the matrix m along the given axes as described for Coefficients, with
+// the rows along axis 0 and the columns along axis 1, and places the
+// result in dst.
+//
+// CoefficientsDense will panic if t is not two-dimensional or the
+// dimensions of m are not those of t. The dst matrix must either be empty
+// or have the dimensions of t, otherwise CoefficientsDense will panic.
+func (t *CmplxFFTN) CoefficientsDense(dst *mat.CDense, m mat.CMatrix, axes ...int) {
+	t.transformDense(dst, m, axes, false)
+}
+
+// SequenceDense computes the two-dimensional complex matrix from the
+// Fourier coefficients in coeff along the given axes as described for
+// Sequence, with the rows along axis 0 and the columns along axis 1, and
+// places the result in dst.
+//
+// SequenceDense will panic if t is not two-dimensional or the dimensions
+// of coeff are not those of t. The dst matrix must either be empty or have
+// the dimensions of t, otherwise SequenceDense will panic.
+func (t *CmplxFFTN) SequenceDense(dst *mat.CDense, coeff mat.CMatrix, axes ...int) {
+	t.transformDense(dst, coeff, axes, true)
+}
+
+func (t *CmplxFFTN) transformDense(dst *mat.CDense, m mat.CMatrix, axes []int, inverse bool) {
+	if len(t.dims) != 2 {
+		panic("fourier: transform is not two-dimensional")
+	}
+	r, c := t.dims[0], t.dims[1]
+	if mr, mc := m.Dims(); mr != r || mc != c {
+		panic(mat.ErrShape)
+	}
+	reuseCDense(dst, r, c)
+	buf := make([]complex128, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			buf[i*c+j] = m.At(i, j)
+		}
+	}
+	t.transform(buf, buf, axes, inverse)
+	raw := dst.RawCMatrix()
+	for i := 0; i < r; i++ {
+		copy(raw.Data[i*raw.Stride:i*raw.Stride+c], buf[i*c:(i+1)*c])
+	}
+}
+
+// FFTN implements Fast Fourier Transforms and their inverses for
+// multidimensional arrays of real values stored in row-major order, with
+// the last dimension varying fastest.
+//
+// The coefficients of a real array are computed by a real transform along
+// the last of the transformed axes, the real axis, followed by complex
+// transforms along the other transformed axes. Only the coefficients of
+// the non-negative frequencies are returned along the real axis, so the
+// dimension of the coefficients along an axis of length n is n/2+1, and
+// the coefficients are stored in row-major order with the dimensions
+// returned by CoefficientDims.
+type FFTN struct {
+	dims  []int
+	lines lines
+}
+
+// NewFFTN returns an FFTN initialized for work on arrays with the given
+// dimensions. NewFFTN will panic if no dimensions are given or any
+// dimension is not positive.
+func NewFFTN(dims ...int) *FFTN {
+	checkDims(dims)
+	t := &FFTN{dims: append([]int(nil), dims...)}
+	t.SetWorkers(0)
+	return t
+}
+
+// SetWorkers sets the maximum number of goroutines used to compute a
+// transform. If n is less than one, the number of goroutines is
+// runtime.GOMAXPROCS(0). Transforms of small arrays are computed by the
+// calling goroutine.
+func (t *FFTN) SetWorkers(n int) { t.lines.setWorkers(n) }
+
+// Dims returns the dimensions of the arrays transformed by t.
+func (t *FFTN) Dims() []int { return append([]int(nil), t.dims...) }
+
+// Len returns the number of elements of the arrays transformed by t.
+func (t *FFTN) Len() int { return product(t.dims) }
+
+// CoefficientDims returns the dimensions of the coefficients of a
+// transform along the given axes. If no axes are given, the dimensions
+// for a transform along all axes are returned. CoefficientDims will panic
+// if an axis is out of range or repeated.
+func (t *FFTN) CoefficientDims(axes ...int) []int {
+	axes = checkAxes(axes, len(t.dims))
+	d := t.Dims()
+	ax := axes[len(axes)-1]
+	d[ax] = d[ax]/2 + 1
+	return d
+}
+
+// Coefficients computes the Fourier coefficients of the real array in seq
+// along the given axes, placing the result in dst and returning it. If no
+// axes are given, the transform is computed along all axes. The last of
+// the axes is the real axis. This transform is unnormalized; a call to
+// Coefficients followed by a call of Sequence along the same axes will
+// multiply the input array by the product of the dimensions of the axes.
+//
+// If the length of seq is not t.Len(), Coefficients will panic. If an axis
+// is out of range or repeated, Coefficients will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the product of the coefficient
+// dimensions, Coefficients will panic.
+func (t *FFTN) Coefficients(dst []complex128, seq []float64, axes ...int) []complex128 {
+	if len(seq) != t.Len() {
+		panic("fourier: sequence length mismatch")
+	}
+	axes = checkAxes(axes, len(t.dims))
+	cdims := t.CoefficientDims(axes...)
+	if dst == nil {
+		dst = make([]complex128, product(cdims))
+	} else if len(dst) != product(cdims) {
+		panic("fourier: destination length mismatch")
+	}
+	last := len(axes) - 1
+	t.lines.realForward(dst, seq, t.dims, axes[last])
+	for _, axis := range axes[:last] {
+		t.lines.cmplxAxis(dst, cdims, axis, false)
+	}
+	return dst
+}
+
+// Sequence computes the real array from the Fourier coefficients in coeff
+// along the given axes, placing the result in dst and returning it. If no
+// axes are given, the transform is computed along all axes. The last of
+// the axes is the real axis. This transform is unnormalized; a call to
+// Coefficients followed by a call of Sequence along the same axes will
+// multiply the input array by the product of the dimensions of the axes.
+//
+// If the length of coeff is not the product of the coefficient dimensions,
+// Sequence will panic. If an axis is out of range or repeated, Sequence
+// will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal t.Len(), Sequence will panic.
+func (t *FFTN) Sequence(dst []float64, coeff []complex128, axes ...int) []float64 {
+	axes = checkAxes(axes, len(t.dims))
+	cdims := t.CoefficientDims(axes...)
+	if len(coeff) != product(cdims) {
+		panic("fourier: coefficients length mismatch")
+	}
+	if dst == nil {
+		dst = make([]float64, t.Len())
+	} else if len(dst) != t.Len() {
+		panic("fourier: destination length mismatch")
+	}
+	last := len(axes) - 1
+	work := append([]complex128(nil), coeff...)
+	for _, axis := range axes[:last] {
+		t.lines.cmplxAxis(work, cdims, axis, true)
+	}
+	t.lines.realInverse(dst, work, t.dims, axes[last])
+	return dst
+}
+
+// CoefficientsDense computes the two-dimensional Fourier coefficients of
+// the matrix m along the given axes as described for Coefficients, with
+// the rows along axis 0 and the columns along axis 1, and places the
+// result in dst.
+//
+// CoefficientsDense will panic if t is not two-dimensional or the
+// dimensions of m are not those of t. The dst matrix must either be empty
+// or have the coefficient dimensions for the axes, otherwise
+// CoefficientsDense will panic.
+func (t *FFTN) CoefficientsDense(dst *mat.CDense, m mat.Matrix, axes ...int) {
+	if len(t.dims) != 2 {
+		panic("fourier: transform is not two-dimensional")
+	}
+	r, c := t.dims[0], t.dims[1]
+	if mr, mc := m.Dims(); mr != r || mc != c {
+		panic(mat.ErrShape)
+	}
+	cdims := t.CoefficientDims(axes...)
+	reuseCDense(dst, cdims[0], cdims[1])
+	seq := make([]float64, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			seq[i*c+j] = m.At(i, j)
+		}
+	}
+	coeff := t.Coefficients(nil, seq, axes...)
+	raw := dst.RawCMatrix()
+	for i := 0; i < cdims[0]; i++ {
+		copy(raw.Data[i*raw.Stride:i*raw.Stride+cdims[1]], coeff[i*cdims[1]:(i+1)*cdims[1]])
+	}
+}
+
+// SequenceDense computes the two-dimensional real matrix from the Fourier
+// coefficients in coeff along the given axes as described for Sequence,
+// with the rows along axis 0 and the columns along axis 1, and places the
+// result in dst.
+//
+// SequenceDense will panic if t is not two-dimensional or the dimensions
+// of coeff are not the coefficient dimensions for the axes. The dst matrix
+// must either be empty or have the dimensions of t, otherwise
+// SequenceDense will panic.
+func (t *FFTN) SequenceDense(dst *mat.Dense, coeff mat.CMatrix, axes ...int) {
+	if len(t.dims) != 2 {
+		panic("fourier: transform is not two-dimensional")
+	}
+	cdims := t.CoefficientDims(axes...)
+	if cr, cc := coeff.Dims(); cr != cdims[0] || cc != cdims[1] {
+		panic(mat.ErrShape)
+	}
+	r, c := t.dims[0], t.dims[1]
+	if dst.IsEmpty() {
+		dst.ReuseAs(r, c)
+	} else if dr, dc := dst.Dims(); dr != r || dc != c {
+		panic(mat.ErrShape)
+	}
+	buf := make([]complex128, cdims[0]*cdims[1])
+	for i := 0; i < cdims[0]; i++ {
+		for j := 0; j < cdims[1]; j++ {
+			buf[i*cdims[1]+j] = coeff.At(i, j)
+		}
+	}
+	seq := t.Sequence(nil, buf, axes...)
+	for i := 0; i < r; i++ {
+		dst.SetRow(i, seq[i*c:(i+1)*c])
+	}
+}
+
+// lines computes one-dimensional transforms along the lines of an axis
+// of a multidimensional array, distributing the lines over workers.
+type lines struct {
+	workers []*lineWorker
+}
+
+// lineWorker holds the transforms and buffers of a worker.
+type lineWorker struct {
+	cmplx map[int]*CmplxFFT
+	real  map[int]*FFT
+	buf   []complex128
+	rbuf  []float64
+}
+
+// setWorkers sets the number of workers to n, or to runtime.GOMAXPROCS(0)
+// if n is less than one.
+func (l *lines) setWorkers(n int) {
+	if n < 1 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	l.workers = make([]*lineWorker, n)
+	for i := range l.workers {
+		l.workers[i] = &lineWorker{
+			cmplx: make(map[int]*CmplxFFT),
+			real:  make(map[int]*FFT),
+		}
+	}
+}
+
+func (w *lineWorker) cmplxFFT(n int) *CmplxFFT {
+	t, ok := w.cmplx[n]
+	if !ok {
+		t = NewCmplxFFT(n)
+		w.cmplx[n] = t
+	}
+	if cap(w.buf) < n {
+		w.buf = make([]complex128, n)
+	}
+	w.buf = w.buf[:n]
+	return t
+}
+
+func (w *lineWorker) realFFT(n int) *FFT {
+	t, ok := w.real[n]
+	if !ok {
+		t = NewFFT(n)
+		w.real[n] = t
+	}
+	if cap(w.buf) < n/2+1 {
+		w.buf = make([]complex128, n/2+1)
+	}
+	w.buf = w.buf[:n/2+1]
+	if cap(w.rbuf) < n {
+		w.rbuf = make([]float64, n)
+	}
+	w.rbuf = w.rbuf[:n]
+	return t
+}
+
+// forLines calls fn for each line along axis of an array with the given
+// dimensions, with the offset of the first element of the line and the
+// stride between its elements, possibly concurrently. The worker passed
+// to fn is used only by one goroutine. prepare is called for each worker
+// that is used before any call to fn.
+func (l *lines) forLines(dims []int, axis int, prepare func(w *lineWorker), fn func(w *lineWorker, base, stride int)) {
+	n := dims[axis]
+	stride := product(dims[axis+1:])
+	count := product(dims) / n
+	line := func(w *lineWorker, k int) {
+		fn(w, (k/stride)*n*stride+k%stride, stride)
+	}
+
+	workers := len(l.workers)
+	if product(dims) < minParallelLen || count < 2 {
+		workers = 1
+	}
+	if workers > count {
+		workers = count
+	}
+	if workers == 1 {
+		w := l.workers[0]
+		prepare(w)
+		for k := 0; k < count; k++ {
+			line(w, k)
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		w := l.workers[i]
+		prepare(w)
+		lo := i * count / workers
+		hi := (i + 1) * count / workers
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := lo; k < hi; k++ {
+				line(w, k)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// cmplxAxis computes complex transforms in place along axis of the array
+// data with the given dimensions.
+func (l *lines) cmplxAxis(data []complex128, dims []int, axis int, inverse bool) {
+	n := dims[axis]
+	if n == 1 {
+		return
+	}
+	l.forLines(dims, axis, func(w *lineWorker) { w.cmplxFFT(n) }, func(w *lineWorker, base, stride int) {
+		fft := w.cmplx[n]
+		buf := w.buf[:n]
+		for i := range buf {
+			buf[i] = data[base+i*stride]
+		}
+		if inverse {
+			fft.Sequence(buf, buf)
+		} else {
+			fft.Coefficients(buf, buf)
+		}
+		for i, v := range buf {
+			data[base+i*stride] = v
+		}
+	})
+}
+
+// realForward computes real transforms along axis of the array seq with
+// the given dimensions, placing the coefficients in dst.
+func (l *lines) realForward(dst []complex128, seq []float64, dims []int, axis int) {
+	n := dims[axis]
+	m := n/2 + 1
+	l.forLines(dims, axis, func(w *lineWorker) { w.realFFT(n) }, func(w *lineWorker, base, stride int) {
+		fft := w.real[n]
+		rbuf := w.rbuf[:n]
+		buf := w.buf[:m]
+		for i := range rbuf {
+			rbuf[i] = seq[base+i*stride]
+		}
+		fft.Coefficients(buf, rbuf)
+		// The offset of the line in the coefficient array.
+		cbase := (base/(n*stride))*m*stride + base%stride
+		for i, v := range buf {
+			dst[cbase+i*stride] = v
+		}
+	})
+}
+
+// realInverse computes inverse real transforms along axis of the
+// coefficients in coeff, placing the real array with the given dimensions
+// in dst.
+func (l *lines) realInverse(dst []float64, coeff []complex128, dims []int, axis int) {
+	n := dims[axis]
+	m := n/2 + 1
+	l.forLines(dims, axis, func(w *lineWorker) { w.realFFT(n) }, func(w *lineWorker, base, stride int) {
+		fft := w.real[n]
+		rbuf := w.rbuf[:n]
+		buf := w.buf[:m]
+		cbase := (base/(n*stride))*m*stride + base%stride
+		for i := range buf {
+			buf[i] = coeff[cbase+i*stride]
+		}
+		fft.Sequence(rbuf, buf)
+		for i, v := range rbuf {
+			dst[base+i*stride] = v
+		}
+	})
+}
+
+// Shift rearranges the array src with the given dimensions so that the
+// zero frequency component of coefficients along each of the given axes
+// is at the center of the axis, placing the result in dst and returning
+// it. If no axes are given, all axes are shifted. Along an axis of length
+// n, the element at index i is moved to index (i+n/2) mod n, corresponding
+// to the indexing of CmplxFFT.ShiftIdx.
+//
+// If the length of src is not the product of dims, Shift will panic. If
+// dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of src, Shift will panic.
+// dst and src must not overlap unless they are the same slice.
+func Shift(dst, src []float64, dims []int, axes ...int) []float64 {
+	dst = useReal(dst, len(src))
+	if sameSlice(dst, src) {
+		src = append([]float64(nil), src...)
+	}
+	perm := shiftPerm(dims, axes, len(src), false)
+	for i, j := range perm {
+		dst[j] = src[i]
+	}
+	return dst
+}
+
+// Unshift is the inverse of Shift, placing the result in dst and returning
+// it. Along an axis of length n, the element at index i is moved to index
+// (i+(n+1)/2) mod n, corresponding to the indexing of
+// CmplxFFT.UnshiftIdx.
+//
+// If the length of src is not the product of dims, Unshift will panic. If
+// dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of src, Unshift will panic.
+// dst and src must not overlap unless they are the same slice.
+func Unshift(dst, src []float64, dims []int, axes ...int) []float64 {
+	dst = useReal(dst, len(src))
+	if sameSlice(dst, src) {
+		src = append([]float64(nil), src...)
+	}
+	perm := shiftPerm(dims, axes, len(src), true)
+	for i, j := range perm {
+		dst[j] = src[i]
+	}
+	return dst
+}
+
+// CmplxShift performs the rearrangement described for Shift on the
+// complex array src, placing the result in dst and returning it.
+//
+// If the length of src is not the product of dims, CmplxShift will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of src, CmplxShift will
+// panic. dst and src must not overlap unless they are the same slice.
+func CmplxShift(dst, src []complex128, dims []int, axes ...int) []complex128 {
+	dst = useCmplx(dst, len(src))
+	if sameCmplxSlice(dst, src) {
+		src = append([]complex128(nil), src...)
+	}
+	perm := shiftPerm(dims, axes, len(src), false)
+	for i, j := range perm {
+		dst[j] = src[i]
+	}
+	return dst
+}
+
+// CmplxUnshift is the inverse of CmplxShift, placing the result in dst and
+// returning it.
+//
+// If the length of src is not the product of dims, CmplxUnshift will
+// panic. If dst is nil, a new slice is allocated and returned. If dst is
+// not nil and the length of dst does not equal the length of src,
+// CmplxUnshift will panic. dst and src must not overlap unless they are
+// the same slice.
+func CmplxUnshift(dst, src []complex128, dims []int, axes ...int) []complex128 {
+	dst = useCmplx(dst, len(src))
+	if sameCmplxSlice(dst, src) {
+		src = append([]complex128(nil), src...)
+	}
+	perm := shiftPerm(dims, axes, len(src), true)
+	for i, j := range perm {
+		dst[j] = src[i]
+	}
+	return dst
+}
+
+// shiftPerm returns the destination index of each element of an array of
+// length n with the given dimensions after shifting along axes.
+func shiftPerm(dims, axes []int, n int, inverse bool) []int {
+	checkDims(dims)
+	if product(dims) != n {
+		panic("fourier: sequence length mismatch")
+	}
+	axes = checkAxes(axes, len(dims))
+	shift := make([]int, len(dims))
+	for _, ax := range axes {
+		if inverse {
+			shift[ax] = (dims[ax] + 1) / 2
+		} else {
+			shift[ax] = dims[ax] / 2
+		}
+	}
+	perm := make([]int, n)
+	idx := make([]int, len(dims))
+	for i := range perm {
+		var j int
+		for k, d := range dims {
+			j = j*d + (idx[k]+shift[k])%d
+		}
+		perm[i] = j
+		// Increment the multi-index in row-major order.
+		for k := len(dims) - 1; k >= 0; k-- {
+			idx[k]++
+			if idx[k] < dims[k] {
+				break
+			}
+			idx[k] = 0
+		}
+	}
+	return perm
+}
+
+func sameSlice(a, b []float64) bool {
+	return len(a) > 0 && len(b) > 0 && &a[0] == &b[0]
+}
+
+func sameCmplxSlice(a, b []complex128) bool {
+	return len(a) > 0 && len(b) > 0 && &a[0] == &b[0]
+}
+
+// checkDims panics if dims is empty or contains a non-positive dimension.
+func checkDims(dims []int) {
+	if len(dims) == 0 {
+		panic("fourier: no dimensions")
+	}
+	for _, d := range dims {
+		if d < 1 {
+			panic("fourier: dimension must be positive")
+		}
+	}
+}
+
+// checkAxes returns the axes to transform for an array with n dimensions,
+// all axes if axes is empty, and panics if an axis is out of range or
+// repeated.
+func checkAxes(axes []int, n int) []int {
+	if len(axes) == 0 {
+		axes = make([]int, n)
+		for i := range axes {
+			axes[i] = i
+		}
+		return axes
+	}
+	seen := make([]bool, n)
+	for _, ax := range axes {
+		if ax < 0 || n <= ax {
+			panic("fourier: axis out of range")
+		}
+		if seen[ax] {
+			panic("fourier: repeated axis")
+		}
+		seen[ax] = true
+	}
+	return axes
+}
+
+func product(dims []int) int {
+	p := 1
+	for _, d := range dims {
+		p *= d
+	}
+	return p
+}
+
+// reuseCDense sizes an empty dst to r×c, or panics if dst is not empty and
+// is not r×c.
+func reuseCDense(dst *mat.CDense, r, c int) {
+	if dst.IsEmpty() {
+		dst.ReuseAs(r, c)
+	} else if dr, dc := dst.Dims(); dr != r || dc != c {
+		panic(mat.ErrShape)
+	}
+}
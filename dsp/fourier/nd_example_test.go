@@ -0,0 +1,72 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier_test
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleFFTN_CoefficientsDense() {
+	// Image is a plane wave with 1 cycle along the rows
+	// and 2 cycles along the columns.
+	const r, c = 4, 8
+	image := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			image.Set(i, j, math.Cos(2*math.Pi*(float64(i)/r+2*float64(j)/c)))
+		}
+	}
+
+	fft := fourier.NewFFTN(r, c)
+	var coeff mat.CDense
+	fft.CoefficientsDense(&coeff, image)
+
+	// Only the c/2+1 non-negative column frequencies are
+	// returned. The wave appears at row frequency 1 and
+	// column frequency 2.
+	cr, cc := coeff.Dims()
+	mag := mat.NewDense(cr, cc, nil)
+	for i := 0; i < cr; i++ {
+		for j := 0; j < cc; j++ {
+			mag.Set(i, j, math.Round(cmplx.Abs(coeff.At(i, j))))
+		}
+	}
+	fmt.Printf("%v\n", mat.Formatted(mag))
+
+	// Output:
+	//
+	// ⎡ 0   0   0   0   0⎤
+	// ⎢ 0   0  16   0   0⎥
+	// ⎢ 0   0   0   0   0⎥
+	// ⎣ 0   0   0   0   0⎦
+}
+
+func ExampleCmplxShift() {
+	// Center the zero frequency of the coefficients of a
+	// 3×4 array, the transform of a constant.
+	dims := []int{3, 4}
+	x := make([]complex128, 12)
+	for i := range x {
+		x[i] = 1
+	}
+	coeff := fourier.NewCmplxFFTN(dims...).Coefficients(nil, x)
+	shifted := fourier.CmplxShift(nil, coeff, dims)
+	for i := 0; i < dims[0]; i++ {
+		for _, v := range shifted[i*dims[1] : (i+1)*dims[1]] {
+			fmt.Printf("%3.0f", cmplx.Abs(v))
+		}
+		fmt.Println()
+	}
+
+	// Output:
+	//   0  0  0  0
+	//   0  0 12  0
+	//   0  0  0  0
+}
@@ -0,0 +1,317 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// naiveDFTN returns the unnormalized DFT of the row-major array x with the
+// given dimensions along axes, computed by direct summation.
+func naiveDFTN(x []complex128, dims, axes []int, inverse bool) []complex128 {
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	y := append([]complex128(nil), x...)
+	for _, ax := range axes {
+		n := dims[ax]
+		stride := product(dims[ax+1:])
+		out := make([]complex128, len(y))
+		for i := range y {
+			k := (i / stride) % n
+			base := i - k*stride
+			var s complex128
+			for j := 0; j < n; j++ {
+				s += y[base+j*stride] * cmplx.Rect(1, sign*2*math.Pi*float64(j*k)/float64(n))
+			}
+			out[i] = s
+		}
+		y = out
+	}
+	return y
+}
+
+var ndTests = []struct {
+	dims []int
+	axes [][]int
+}{
+	{dims: []int{7}, axes: [][]int{nil, {0}}},
+	{dims: []int{1, 4}, axes: [][]int{nil, {0}, {1}}},
+	{dims: []int{2, 6}, axes: [][]int{nil, {0}, {1}, {1, 0}}},
+	{dims: []int{3, 4, 5}, axes: [][]int{nil, {0}, {1}, {2}, {0, 2}, {2, 0}, {1, 2, 0}}},
+	{dims: []int{2, 3, 2, 3}, axes: [][]int{nil, {3, 1}, {0, 1, 2}}},
+}
+
+func TestCmplxFFTN(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range ndTests {
+		fft := NewCmplxFFTN(test.dims...)
+		x := make([]complex128, fft.Len())
+		for i := range x {
+			x[i] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+		}
+		for _, axes := range test.axes {
+			name := fmt.Sprintf("dims=%v axes=%v", test.dims, axes)
+			want := naiveDFTN(x, test.dims, checkAxes(axes, len(test.dims)), false)
+			got := fft.Coefficients(nil, x, axes...)
+			if !cmplxEqualApprox(got, want, tol) {
+				t.Errorf("%s: unexpected coefficients", name)
+			}
+			seq := fft.Sequence(nil, got, axes...)
+			want = naiveDFTN(got, test.dims, checkAxes(axes, len(test.dims)), true)
+			if !cmplxEqualApprox(seq, want, tol) {
+				t.Errorf("%s: unexpected sequence", name)
+			}
+
+			// In place transforms give the same result.
+			buf := append([]complex128(nil), x...)
+			fft.Coefficients(buf, buf, axes...)
+			if !cmplxEqualApprox(buf, got, tol) {
+				t.Errorf("%s: unexpected in place coefficients", name)
+			}
+		}
+	}
+}
+
+func TestFFTN(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range ndTests {
+		fft := NewFFTN(test.dims...)
+		x := make([]float64, fft.Len())
+		cx := make([]complex128, fft.Len())
+		for i := range x {
+			x[i] = rnd.NormFloat64()
+			cx[i] = complex(x[i], 0)
+		}
+		for _, axes := range test.axes {
+			name := fmt.Sprintf("dims=%v axes=%v", test.dims, axes)
+			all := checkAxes(axes, len(test.dims))
+			full := naiveDFTN(cx, test.dims, all, false)
+
+			// The coefficients are the non-negative frequency half of
+			// the complex coefficients along the real axis.
+			cdims := fft.CoefficientDims(axes...)
+			real := all[len(all)-1]
+			for i, d := range cdims {
+				want := test.dims[i]
+				if i == real {
+					want = want/2 + 1
+				}
+				if d != want {
+					t.Errorf("%s: unexpected coefficient dimensions: %v", name, cdims)
+				}
+			}
+			got := fft.Coefficients(nil, x, axes...)
+			if len(got) != product(cdims) {
+				t.Fatalf("%s: unexpected coefficients length: %d", name, len(got))
+			}
+			stride := product(test.dims[real+1:])
+			n, m := test.dims[real], cdims[real]
+			for i, v := range got {
+				k := (i / stride) % m
+				j := (i/(m*stride))*n*stride + k*stride + i%stride
+				if cmplx.Abs(v-full[j]) > tol {
+					t.Errorf("%s: unexpected coefficient %d: got %v, want %v", name, i, v, full[j])
+					break
+				}
+			}
+
+			seq := fft.Sequence(nil, got, axes...)
+			scale := 1.0
+			for _, ax := range all {
+				scale *= float64(test.dims[ax])
+			}
+			floats.Scale(1/scale, seq)
+			if !floats.EqualApprox(seq, x, tol) {
+				t.Errorf("%s: unexpected round trip", name)
+			}
+		}
+	}
+}
+
+func TestFFTNParallel(t *testing.T) {
+	t.Parallel()
+	dims := []int{16, 30, 40}
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, product(dims))
+	cx := make([]complex128, len(x))
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+		cx[i] = complex(x[i], rnd.NormFloat64())
+	}
+	if len(x) < minParallelLen {
+		t.Fatalf("array too small for parallel transform")
+	}
+
+	serial := NewFFTN(dims...)
+	serial.SetWorkers(1)
+	cserial := NewCmplxFFTN(dims...)
+	cserial.SetWorkers(1)
+	for _, workers := range []int{0, 2, 3, 7} {
+		fft := NewFFTN(dims...)
+		fft.SetWorkers(workers)
+		cfft := NewCmplxFFTN(dims...)
+		cfft.SetWorkers(workers)
+		for _, axes := range [][]int{nil, {1}, {2, 0}} {
+			// The same transforms on the same lines are computed, so
+			// the results are identical.
+			want := serial.Coefficients(nil, x, axes...)
+			got := fft.Coefficients(nil, x, axes...)
+			if !cmplxEqualApprox(got, want, 0) {
+				t.Errorf("workers=%d axes=%v: unexpected real coefficients", workers, axes)
+			}
+			if !floats.Equal(fft.Sequence(nil, got, axes...), serial.Sequence(nil, want, axes...)) {
+				t.Errorf("workers=%d axes=%v: unexpected real sequence", workers, axes)
+			}
+			cwant := cserial.Coefficients(nil, cx, axes...)
+			cgot := cfft.Coefficients(nil, cx, axes...)
+			if !cmplxEqualApprox(cgot, cwant, 0) {
+				t.Errorf("workers=%d axes=%v: unexpected complex coefficients", workers, axes)
+			}
+		}
+	}
+}
+
+func TestFFTNDense(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	rnd := rand.New(rand.NewSource(1))
+	const r, c = 5, 8
+	m := mat.NewDense(r, c, nil)
+	cm := mat.NewCDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.Set(i, j, rnd.NormFloat64())
+			cm.Set(i, j, complex(rnd.NormFloat64(), rnd.NormFloat64()))
+		}
+	}
+	// Use a view so the matrices are not contiguous.
+	big := mat.NewDense(r+2, c+3, nil)
+	view := big.Slice(1, r+1, 2, c+2).(*mat.Dense)
+	view.Copy(m)
+
+	fft := NewFFTN(r, c)
+	cfft := NewCmplxFFTN(r, c)
+	for _, axes := range [][]int{nil, {0}, {1}, {1, 0}} {
+		want := fft.Coefficients(nil, mat.DenseCopyOf(m).RawMatrix().Data, axes...)
+		cdims := fft.CoefficientDims(axes...)
+		var coeff mat.CDense
+		fft.CoefficientsDense(&coeff, view, axes...)
+		if cr, cc := coeff.Dims(); cr != cdims[0] || cc != cdims[1] {
+			t.Fatalf("axes=%v: unexpected dimensions %d×%d", axes, cr, cc)
+		}
+		for i := 0; i < cdims[0]; i++ {
+			for j := 0; j < cdims[1]; j++ {
+				if cmplx.Abs(coeff.At(i, j)-want[i*cdims[1]+j]) > tol {
+					t.Errorf("axes=%v: unexpected coefficient at %d,%d", axes, i, j)
+				}
+			}
+		}
+		var seq mat.Dense
+		fft.SequenceDense(&seq, &coeff, axes...)
+		scale := 1.0
+		for _, ax := range checkAxes(axes, 2) {
+			scale *= float64([]int{r, c}[ax])
+		}
+		seq.Scale(1/scale, &seq)
+		if !mat.EqualApprox(&seq, m, tol) {
+			t.Errorf("axes=%v: unexpected real round trip", axes)
+		}
+
+		data := make([]complex128, r*c)
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				data[i*c+j] = cm.At(i, j)
+			}
+		}
+		cwant := cfft.Coefficients(nil, data, axes...)
+		var ccoeff mat.CDense
+		cfft.CoefficientsDense(&ccoeff, cm, axes...)
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				if cmplx.Abs(ccoeff.At(i, j)-cwant[i*c+j]) > tol {
+					t.Errorf("axes=%v: unexpected complex coefficient at %d,%d", axes, i, j)
+				}
+			}
+		}
+		var cseq mat.CDense
+		cfft.SequenceDense(&cseq, &ccoeff, axes...)
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				if cmplx.Abs(cseq.At(i, j)/complex(scale, 0)-cm.At(i, j)) > tol {
+					t.Errorf("axes=%v: unexpected complex round trip at %d,%d", axes, i, j)
+				}
+			}
+		}
+	}
+}
+
+func TestShift(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		dims []int
+		axes []int
+	}{
+		{dims: []int{5}},
+		{dims: []int{6}},
+		{dims: []int{4, 5}},
+		{dims: []int{4, 5}, axes: []int{1}},
+		{dims: []int{3, 2, 7}},
+		{dims: []int{3, 2, 7}, axes: []int{2, 0}},
+	} {
+		n := product(test.dims)
+		x := make([]float64, n)
+		cx := make([]complex128, n)
+		for i := range x {
+			x[i] = float64(i)
+			cx[i] = complex(float64(i), -float64(i))
+		}
+		shifted := Shift(nil, x, test.dims, test.axes...)
+		cshifted := CmplxShift(nil, cx, test.dims, test.axes...)
+
+		// The shift along each axis corresponds to CmplxFFT.ShiftIdx.
+		all := checkAxes(test.axes, len(test.dims))
+		idx := make([]int, len(test.dims))
+		for i := range shifted {
+			rem := i
+			for k := len(test.dims) - 1; k >= 0; k-- {
+				idx[k] = rem % test.dims[k]
+				rem /= test.dims[k]
+			}
+			for _, ax := range all {
+				idx[ax] = NewCmplxFFT(test.dims[ax]).ShiftIdx(idx[ax])
+			}
+			var j int
+			for k, d := range test.dims {
+				j = j*d + idx[k]
+			}
+			if shifted[i] != x[j] || cshifted[i] != cx[j] {
+				t.Errorf("dims=%v axes=%v: unexpected shifted value at %d: got %v, want %v", test.dims, test.axes, i, shifted[i], x[j])
+			}
+		}
+
+		// Unshift is the inverse of Shift, also in place.
+		Unshift(shifted, shifted, test.dims, test.axes...)
+		if !floats.Equal(shifted, x) {
+			t.Errorf("dims=%v axes=%v: unshift is not the inverse of shift", test.dims, test.axes)
+		}
+		if !cmplxEqualApprox(CmplxUnshift(nil, cshifted, test.dims, test.axes...), cx, 0) {
+			t.Errorf("dims=%v axes=%v: complex unshift is not the inverse of shift", test.dims, test.axes)
+		}
+	}
+}
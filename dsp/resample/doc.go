@@ -0,0 +1,13 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package resample provides functions to change the sample rate of
+// sequences.
+//
+// Poly resamples by a rational factor with a polyphase implementation of
+// upsampling, anti-alias FIR filtering and downsampling, and Decimate and
+// Interpolate change the sample rate by an integer factor. Fourier
+// resamples a sequence to any length by truncating or zero-padding its
+// discrete Fourier transform, treating the sequence as periodic.
+package resample // import "gonum.org/v1/gonum/dsp/resample"
@@ -0,0 +1,59 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample_test
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/dsp/resample"
+)
+
+func ExamplePoly() {
+	// Convert 10 ms of a 1 kHz tone sampled at
+	// 48 kHz to a sample rate of 44.1 kHz.
+	const (
+		in  = 48000.0
+		out = 44100.0
+		f   = 1000.0
+	)
+	x := make([]float64, 480)
+	for i := range x {
+		x[i] = math.Sin(2 * math.Pi * f * float64(i) / in)
+	}
+	y := resample.Poly(nil, x, 147, 160, nil)
+	fmt.Printf("%d samples resampled to %d samples\n", len(x), len(y))
+
+	// Compare the middle of the resampled signal with
+	// the tone sampled at the new rate.
+	var maxErr float64
+	for i := 100; i < len(y)-100; i++ {
+		want := math.Sin(2 * math.Pi * f * float64(i) / out)
+		maxErr = math.Max(maxErr, math.Abs(y[i]-want))
+	}
+	fmt.Printf("maximum error: %.1e\n", maxErr)
+
+	// Output:
+	// 480 samples resampled to 441 samples
+	// maximum error: 1.5e-03
+}
+
+func ExampleFourier() {
+	// Sample one period of a cosine at 4 points and
+	// interpolate it at 8 points.
+	x := []float64{1, 0, -1, 0}
+	y := resample.Fourier(nil, x, 8)
+	for _, v := range y {
+		if math.Abs(v) < 1e-12 {
+			// Avoid printing negative zero.
+			v = 0
+		}
+		fmt.Printf("%.4f ", v)
+	}
+	fmt.Println()
+
+	// Output:
+	// 1.0000 0.7071 0.0000 -0.7071 -1.0000 -0.7071 0.0000 0.7071
+}
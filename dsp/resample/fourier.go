@@ -0,0 +1,55 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import "gonum.org/v1/gonum/dsp/fourier"
+
+// Fourier resamples x to n samples using the discrete Fourier transform,
+// placing the result into dst and returning it. The Fourier coefficients
+// of x are truncated or padded with zeros to the length n, so the result
+// samples the band-limited periodic interpolant of x at n equally spaced
+// points over the same interval. When downsampling, the frequencies above
+// the new Nyquist frequency are discarded, and the coefficient at an even
+// n's Nyquist frequency collects both of the aliased coefficients.
+//
+// Since the sequence is treated as periodic, discontinuities between its
+// ends cause ringing in the result.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal n, Fourier will panic. Fourier will
+// panic if n is less than one or x is empty.
+func Fourier(dst, x []float64, n int) []float64 {
+	if n < 1 {
+		panic("resample: length less than one")
+	}
+	if len(x) == 0 {
+		panic("resample: empty sequence")
+	}
+	dst = useSlice(dst, n)
+	nx := len(x)
+	coeff := fourier.NewFFT(nx).Coefficients(nil, x)
+	out := make([]complex128, n/2+1)
+	m := nx
+	if n < m {
+		m = n
+	}
+	copy(out, coeff[:m/2+1])
+	if m%2 == 0 {
+		switch {
+		case n < nx:
+			// Fold the coefficients at ±n/2 into the output
+			// Nyquist coefficient.
+			out[m/2] = complex(2*real(coeff[m/2]), 0)
+		case nx < n:
+			// Split the input Nyquist coefficient between ±nx/2.
+			out[m/2] = coeff[m/2] / 2
+		}
+	}
+	scale := complex(1/float64(nx), 0)
+	for i, v := range out {
+		out[i] = v * scale
+	}
+	return fourier.NewFFT(n).Sequence(dst, out)
+}
@@ -0,0 +1,78 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// trig returns samples at n points over a unit interval of a
+// trigonometric polynomial with the given cosine and sine
+// amplitudes at integer frequencies.
+func trig(n int, cos, sin []float64) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		t := float64(i) / float64(n)
+		for k, a := range cos {
+			x[i] += a * math.Cos(2*math.Pi*float64(k)*t)
+		}
+		for k, b := range sin {
+			x[i] += b * math.Sin(2*math.Pi*float64(k)*t)
+		}
+	}
+	return x
+}
+
+func TestFourier(t *testing.T) {
+	t.Parallel()
+	cos := []float64{1, 0.5, -0.25, 0.125}
+	sin := []float64{0, -1, 0.75, 0.5}
+	// Trigonometric polynomials with frequencies below both
+	// Nyquist frequencies are resampled exactly.
+	for _, nx := range []int{8, 9, 16, 31} {
+		for _, n := range []int{8, 9, 10, 17, 64, 101} {
+			x := trig(nx, cos, sin)
+			got := Fourier(nil, x, n)
+			want := trig(n, cos, sin)
+			if !floats.EqualApprox(got, want, 1e-12) {
+				t.Errorf("unexpected result for %d to %d:\ngot: %v\nwant:%v", nx, n, got, want)
+			}
+		}
+	}
+
+	// The Nyquist frequency component of a sequence of even
+	// length is split between positive and negative frequencies
+	// when upsampling.
+	x := trig(8, []float64{0, 0, 0, 0, 1}, nil)
+	got := Fourier(nil, x, 32)
+	want := trig(32, []float64{0, 0, 0, 0, 1}, nil)
+	if !floats.EqualApprox(got, want, 1e-12) {
+		t.Errorf("unexpected result for upsampled Nyquist component:\ngot: %v\nwant:%v", got, want)
+	}
+	// A component at the output Nyquist frequency is kept
+	// when downsampling to an even length.
+	x = trig(32, []float64{0, 0, 0, 0, 1}, nil)
+	got = Fourier(nil, x, 8)
+	want = trig(8, []float64{0, 0, 0, 0, 1}, nil)
+	if !floats.EqualApprox(got, want, 1e-12) {
+		t.Errorf("unexpected result for downsampled Nyquist component:\ngot: %v\nwant:%v", got, want)
+	}
+	// Components above the output Nyquist frequency are removed.
+	x = trig(32, []float64{1, 0, 0, 0, 0, 0, 1}, nil)
+	got = Fourier(nil, x, 8)
+	want = trig(8, []float64{1}, nil)
+	if !floats.EqualApprox(got, want, 1e-12) {
+		t.Errorf("unexpected result for removed component:\ngot: %v\nwant:%v", got, want)
+	}
+	// Resampling a single sample gives a constant.
+	got = Fourier(nil, []float64{3}, 5)
+	want = []float64{3, 3, 3, 3, 3}
+	if !floats.EqualApprox(got, want, 1e-12) {
+		t.Errorf("unexpected result for single sample: got:%v want:%v", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import "gonum.org/v1/gonum/dsp/filter"
+
+// AntiAlias returns a lowpass FIR filter for resampling by the factor
+// up/down, designed by filter.FIRWindow with the window win. The filter
+// operates at the upsampled rate, has 2*halfLen*max(up, down)+1 taps and a
+// cutoff at the lower of the input and output Nyquist frequencies, and its
+// gain is up to compensate for the zeros inserted by upsampling. If win is
+// nil, window.Hamming is used.
+//
+// AntiAlias will panic if up, down or halfLen is less than one.
+func AntiAlias(up, down, halfLen int, win func([]float64) []float64) []float64 {
+	if up < 1 || down < 1 {
+		panic("resample: factor less than one")
+	}
+	if halfLen < 1 {
+		panic("resample: half length less than one")
+	}
+	up, down = reduce(up, down)
+	m := up
+	if down > m {
+		m = down
+	}
+	if m == 1 {
+		return []float64{1}
+	}
+	h := filter.FIRWindow(2*halfLen*m+1, filter.Lowpass, 1, win, 0.5/float64(m))
+	for i := range h {
+		h[i] *= float64(up)
+	}
+	return h
+}
+
+// defaultHalfLen is the half length of the anti-alias filter used
+// when no filter is provided, in units of the larger factor.
+const defaultHalfLen = 10
+
+// Len returns the length of the sequence obtained by resampling a sequence
+// of length n by the factor up/down, ceil(n*up/down).
+func Len(n, up, down int) int {
+	if up < 1 || down < 1 {
+		panic("resample: factor less than one")
+	}
+	up, down = reduce(up, down)
+	return (n*up + down - 1) / down
+}
+
+// Poly resamples x by the rational factor up/down, placing the result into
+// dst and returning it. The factor is first reduced to lowest terms. The
+// sequence is upsampled by inserting up-1 zeros between samples, filtered
+// by the FIR filter h, and downsampled by keeping every down-th sample.
+// The polyphase implementation only evaluates the filter at the retained
+// samples and skips the inserted zeros.
+//
+// The output is shifted to compensate for the delay of a linear phase
+// filter, (len(h)-1)/2 samples at the upsampled rate, and the sequence is
+// treated as zero outside its ends. If h is nil, the filter returned by
+// AntiAlias(up, down, 10, nil) is used.
+//
+// If dst is nil, a new slice of length Len(len(x), up, down) is allocated
+// and returned. If dst is not nil and its length does not equal
+// Len(len(x), up, down), Poly will panic. Poly will panic if up or down is
+// less than one.
+func Poly(dst, x []float64, up, down int, h []float64) []float64 {
+	dst = useSlice(dst, Len(len(x), up, down))
+	up, down = reduce(up, down)
+	if h == nil {
+		h = AntiAlias(up, down, defaultHalfLen, nil)
+	}
+	delay := (len(h) - 1) / 2
+	for m := range dst {
+		// Output sample m is at index t of the upsampled
+		// sequence convolved with h. Only the taps of h that
+		// are aligned with samples of x, those with k ≡ t
+		// modulo up, contribute.
+		t := m*down + delay
+		k := t % up
+		j := t / up
+		if j >= len(x) {
+			skip := j - len(x) + 1
+			k += skip * up
+			j -= skip
+		}
+		var sum float64
+		for ; k < len(h) && j >= 0; k, j = k+up, j-1 {
+			sum += h[k] * x[j]
+		}
+		dst[m] = sum
+	}
+	return dst
+}
+
+// Decimate reduces the sample rate of x by the integer factor q, placing
+// the result into dst and returning it. Decimate is equivalent to
+// Poly(dst, x, 1, q, h).
+func Decimate(dst, x []float64, q int, h []float64) []float64 {
+	return Poly(dst, x, 1, q, h)
+}
+
+// Interpolate increases the sample rate of x by the integer factor p,
+// placing the result into dst and returning it. Interpolate is equivalent
+// to Poly(dst, x, p, 1, h).
+func Interpolate(dst, x []float64, p int, h []float64) []float64 {
+	return Poly(dst, x, p, 1, h)
+}
+
+// reduce returns up/down reduced to lowest terms.
+func reduce(up, down int) (int, int) {
+	a, b := up, down
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return up / a, down / a
+}
+
+func useSlice(dst []float64, n int) []float64 {
+	if dst == nil {
+		return make([]float64, n)
+	}
+	if len(dst) != n {
+		panic("resample: destination length mismatch")
+	}
+	return dst
+}
@@ -0,0 +1,185 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+// naivePoly resamples x by upsampling, filtering with h and
+// downsampling in separate steps.
+func naivePoly(x []float64, up, down int, h []float64) []float64 {
+	u := make([]float64, len(x)*up)
+	for i, v := range x {
+		u[i*up] = v
+	}
+	f := make([]float64, len(u)+len(h)-1)
+	for i, v := range u {
+		for j, w := range h {
+			f[i+j] += v * w
+		}
+	}
+	delay := (len(h) - 1) / 2
+	var y []float64
+	for t := delay; len(y) < (len(x)*up+down-1)/down; t += down {
+		if t < len(f) {
+			y = append(y, f[t])
+		} else {
+			y = append(y, 0)
+		}
+	}
+	return y
+}
+
+func TestLen(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		n, up, down int
+		want        int
+	}{
+		{n: 10, up: 1, down: 1, want: 10},
+		{n: 10, up: 3, down: 2, want: 15},
+		{n: 11, up: 3, down: 2, want: 17},
+		{n: 10, up: 2, down: 3, want: 7},
+		{n: 10, up: 4, down: 6, want: 7},
+		{n: 0, up: 2, down: 3, want: 0},
+	} {
+		if got := Len(test.n, test.up, test.down); got != test.want {
+			t.Errorf("unexpected length for n=%d up=%d down=%d: got:%d want:%d", test.n, test.up, test.down, got, test.want)
+		}
+	}
+}
+
+func TestPoly(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 5, 64} {
+		for _, f := range [][2]int{{1, 1}, {1, 3}, {3, 1}, {2, 3}, {3, 2}, {5, 7}, {160, 147}} {
+			for _, taps := range []int{1, 4, 7, 31} {
+				name := fmt.Sprintf("n=%d up=%d down=%d taps=%d", n, f[0], f[1], taps)
+				x := randSeq(rnd, n)
+				h := randSeq(rnd, taps)
+				got := Poly(nil, x, f[0], f[1], h)
+				want := naivePoly(x, f[0], f[1], h)
+				if !floats.EqualApprox(got, want, 1e-12) {
+					t.Errorf("unexpected result for %s:\ngot: %v\nwant:%v", name, got, want)
+				}
+			}
+		}
+	}
+}
+
+func randSeq(rnd *rand.Rand, n int) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+	}
+	return x
+}
+
+func TestPolySinusoid(t *testing.T) {
+	t.Parallel()
+	const n = 400
+	for _, f := range [][2]int{{1, 1}, {3, 2}, {2, 3}, {1, 4}, {5, 1}, {147, 160}} {
+		up, down := f[0], f[1]
+		// A tone at a tenth of the lower Nyquist frequency
+		// is resampled with an error within the passband
+		// ripple of the filter away from the ends.
+		freq := 0.05 * math.Min(1, float64(up)/float64(down))
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = math.Sin(2 * math.Pi * freq * float64(i))
+		}
+		got := Poly(nil, x, up, down, nil)
+		ratio := float64(up) / float64(down)
+		edge := int(float64(defaultHalfLen) * math.Max(ratio, 1) * 2)
+		for i := edge; i < len(got)-edge; i++ {
+			want := math.Sin(2 * math.Pi * freq * float64(i) / ratio)
+			if !scalar.EqualWithinAbs(got[i], want, 5e-3) {
+				t.Errorf("unexpected resampled value for up=%d down=%d at %d: got:%v want:%v", up, down, i, got[i], want)
+				break
+			}
+		}
+	}
+}
+
+func TestDecimateAntiAlias(t *testing.T) {
+	t.Parallel()
+	const (
+		n = 1000
+		q = 4
+	)
+	// A tone above the output Nyquist frequency is removed.
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = math.Sin(2 * math.Pi * 0.3 * float64(i))
+	}
+	got := Decimate(nil, x, q, nil)
+	if len(got) != n/q {
+		t.Fatalf("unexpected length: got:%d want:%d", len(got), n/q)
+	}
+	for i := 2 * defaultHalfLen; i < len(got)-2*defaultHalfLen; i++ {
+		if math.Abs(got[i]) > 1e-2 {
+			t.Errorf("aliased tone not attenuated at %d: %v", i, got[i])
+			break
+		}
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x := randSeq(rnd, 50)
+	for _, p := range []int{2, 3, 4} {
+		// The taps of the filter are zero at multiples of p
+		// away from its center, so the original samples are
+		// kept up to the scaling of the center tap.
+		h := AntiAlias(p, 1, 8, nil)
+		got := Interpolate(nil, x, p, h)
+		for i, v := range x {
+			if !scalar.EqualWithinRel(got[i*p], v, 5e-3) {
+				t.Errorf("sample %d not preserved for p=%d: got:%v want:%v", i, p, got[i*p], v)
+				break
+			}
+		}
+	}
+}
+
+func TestAntiAlias(t *testing.T) {
+	t.Parallel()
+	for _, f := range [][2]int{{1, 1}, {1, 3}, {3, 1}, {3, 2}, {4, 6}} {
+		for _, halfLen := range []int{1, 5, 10} {
+			h := AntiAlias(f[0], f[1], halfLen, nil)
+			up, down := reduce(f[0], f[1])
+			m := up
+			if down > m {
+				m = down
+			}
+			wantLen := 2*halfLen*m + 1
+			if m == 1 {
+				wantLen = 1
+			}
+			if len(h) != wantLen {
+				t.Errorf("unexpected filter length for up=%d down=%d: got:%d want:%d", f[0], f[1], len(h), wantLen)
+			}
+			if got := floats.Sum(h); !scalar.EqualWithinRel(got, float64(up), 1e-12) {
+				t.Errorf("unexpected filter gain for up=%d down=%d: got:%v want:%v", f[0], f[1], got, up)
+			}
+			for i := range h {
+				if !scalar.EqualWithinAbs(h[i], h[len(h)-1-i], 1e-15) {
+					t.Errorf("filter is not symmetric for up=%d down=%d", f[0], f[1])
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package spectral provides short-time Fourier transforms and spectral
+// density estimators for real sequences.
+//
+// The estimators divide a sequence into overlapping segments, multiply each
+// segment by a window, such as those in the window package, and transform
+// the windowed segments with the FFTs of the fourier package. Spectra are
+// one-sided: for a transform of length n, the n/2+1 non-negative
+// frequencies are returned, and the power at frequencies other than zero
+// and the Nyquist frequency is doubled to account for the negative
+// frequencies.
+package spectral // import "gonum.org/v1/gonum/dsp/spectral"
@@ -0,0 +1,79 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spectral_test
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/dsp/spectral"
+	"gonum.org/v1/gonum/dsp/window"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleWelch() {
+	// A 50 Hz sinusoid with an amplitude of 2, sampled at 1 kHz, in
+	// noise with a variance of 1.
+	const fs = 1000.0
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, 10000)
+	for i := range x {
+		x[i] = 2*math.Sin(2*math.Pi*50*float64(i)/fs) + rnd.NormFloat64()
+	}
+
+	// Estimate the power spectrum with segments of 200 samples
+	// overlapping by half.
+	win := window.NewValues(window.Hann, 200)
+	p := spectral.Welch(nil, x, fs, win, 100, 0, spectral.Spectrum)
+	freqs := spectral.Frequencies(nil, 200, fs)
+
+	peak := floats.MaxIdx(p)
+	fmt.Printf("peak at %v Hz with mean square %.1f\n", freqs[peak], p[peak])
+
+	// Output:
+	// peak at 50 Hz with mean square 2.0
+}
+
+func ExampleSTFT() {
+	// A sequence whose frequency changes half way through.
+	x := make([]float64, 64)
+	for i := range x {
+		f := 0.125
+		if i >= 32 {
+			f = 0.375
+		}
+		x[i] = math.Cos(2 * math.Pi * f * float64(i))
+	}
+
+	s := spectral.NewSTFT(window.NewValues(window.Hann, 16), 8, 0, spectral.NoPadding)
+	var spec mat.Dense
+	s.Spectrogram(&spec, x, 1, spectral.Spectrum)
+	frames, _ := spec.Dims()
+	fmt.Printf("time  %.3f  %.3f\n", s.Freq(2), s.Freq(6))
+	for t := 0; t < frames; t++ {
+		fmt.Printf("%4d  %.3f  %.3f\n", s.Time(t), spec.At(t, 2), spec.At(t, 6))
+	}
+
+	// The transform is inverted exactly, except for the first and last
+	// samples which are only multiplied by zero window weights.
+	var z mat.CDense
+	s.Transform(&z, x)
+	y := s.Inverse(nil, &z)
+	fmt.Println("inverse equal:", floats.EqualApprox(x[1:63], y[1:63], 1e-12))
+
+	// Output:
+	// time  0.125  0.375
+	//    8  0.503  0.000
+	//   16  0.503  0.000
+	//   24  0.503  0.000
+	//   32  0.173  0.105
+	//   40  0.000  0.503
+	//   48  0.000  0.503
+	//   56  0.000  0.503
+	// inverse equal: true
+}
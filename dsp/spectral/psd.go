@@ -0,0 +1,286 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spectral
+
+import "gonum.org/v1/gonum/dsp/fourier"
+
+// Scaling specifies the scaling of a power spectrum.
+type Scaling int
+
+const (
+	// Density scales the power spectrum to a power spectral density, in
+	// units of squared sequence units per unit of the sample rate. The
+	// integral of the density over frequency is the mean square of the
+	// sequence.
+	Density Scaling = iota
+	// Spectrum scales the power spectrum so that the value at the
+	// frequency of a sinusoid in the sequence is its mean square, in
+	// squared sequence units.
+	Spectrum
+)
+
+// scaleFactor returns the factor that scales the squared magnitude of the
+// coefficients of a segment windowed by win to the scaling for the sample
+// rate fs.
+func scaleFactor(win []float64, fs float64, scaling Scaling) float64 {
+	if !(fs > 0) {
+		panic("spectral: sample rate must be positive")
+	}
+	var sum, sumSq float64
+	for _, w := range win {
+		sum += w
+		sumSq += w * w
+	}
+	switch scaling {
+	case Density:
+		return 1 / (fs * sumSq)
+	case Spectrum:
+		return 1 / (sum * sum)
+	default:
+		panic("spectral: invalid scaling")
+	}
+}
+
+// oneSided doubles the values of the power spectrum s for a transform of
+// length n at the frequencies that have a negative frequency counterpart.
+func oneSided(s []float64, n int) {
+	for i := 1; i < lastPaired(len(s), n); i++ {
+		s[i] *= 2
+	}
+}
+
+// cmplxOneSided doubles the values of the cross power spectrum s for a
+// transform of length n at the frequencies that have a negative frequency
+// counterpart.
+func cmplxOneSided(s []complex128, n int) {
+	for i := 1; i < lastPaired(len(s), n); i++ {
+		s[i] *= 2
+	}
+}
+
+// lastPaired returns the end of the range of the bins of the one-sided
+// spectrum of length l for a transform of length n that have a negative
+// frequency counterpart.
+func lastPaired(l, n int) int {
+	if n%2 == 0 {
+		return l - 1
+	}
+	return l
+}
+
+// Frequencies returns the frequencies of the one-sided spectrum computed
+// with an FFT of length n for the sample rate fs, placing them in dst and
+// returning it.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal n/2+1, Frequencies will panic.
+func Frequencies(dst []float64, n int, fs float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, n/2+1)
+	}
+	if len(dst) != n/2+1 {
+		panic("spectral: destination length mismatch")
+	}
+	for i := range dst {
+		dst[i] = float64(i) * fs / float64(n)
+	}
+	return dst
+}
+
+// Periodogram computes the one-sided power spectrum of x windowed by win
+// with an FFT of length fftLen, scaled according to scaling for the sample
+// rate fs, placing the result in dst and returning it. If win is nil, a
+// rectangular window is used. If fftLen is zero, the FFT length is len(x).
+// Periodogram will panic if win is not nil and its length is not len(x),
+// if fftLen is non-zero and less than len(x), or if fs is not positive.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal fftLen/2+1, Periodogram will panic.
+func Periodogram(dst, x []float64, fs float64, win []float64, fftLen int, scaling Scaling) []float64 {
+	if win == nil {
+		win = make([]float64, len(x))
+		for i := range win {
+			win[i] = 1
+		}
+	}
+	if len(win) != len(x) {
+		panic("spectral: window length mismatch")
+	}
+	return Welch(dst, x, fs, win, len(x), fftLen, scaling)
+}
+
+// Welch computes an estimate of the one-sided power spectrum of x by
+// Welch's method, scaled according to scaling for the sample rate fs,
+// placing the result in dst and returning it. The estimate is the mean of
+// the periodograms of the segments of x of the length of win starting at
+// multiples of hop, multiplied by win and transformed with an FFT of length
+// fftLen. Samples after the last complete segment are not used. If fftLen
+// is zero, the FFT length is len(win).
+//
+// Welch will panic if win is empty or longer than x, if hop is not
+// positive, if fftLen is non-zero and less than len(win), or if fs is not
+// positive.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal fftLen/2+1, Welch will panic.
+//
+// References:
+//   - Welch, P. D. (1967). The use of fast Fourier transform for the
+//     estimation of power spectra: A method based on time averaging over
+//     short, modified periodograms. IEEE Transactions on Audio and
+//     Electroacoustics, 15(2), 70-73.
+func Welch(dst, x []float64, fs float64, win []float64, hop, fftLen int, scaling Scaling) []float64 {
+	fftLen = checkSegments(len(x), win, hop, fftLen)
+	scale := scaleFactor(win, fs, scaling)
+	if dst == nil {
+		dst = make([]float64, fftLen/2+1)
+	}
+	if len(dst) != fftLen/2+1 {
+		panic("spectral: destination length mismatch")
+	}
+	pxx, _, _ := averageSpectra(x, nil, win, hop, fftLen)
+	for i, v := range pxx {
+		dst[i] = v * scale
+	}
+	oneSided(dst, fftLen)
+	return dst
+}
+
+// CSD computes an estimate of the one-sided cross power spectrum of x and
+// y by Welch's method, scaled according to scaling for the sample rate fs,
+// placing the result in dst and returning it. The estimate is the mean of
+// conj(X)*Y over the segments, where X and Y are the transforms of the
+// segments of x and y as described for Welch, so the cross spectrum of x
+// with itself is its power spectrum.
+//
+// CSD will panic if x and y have different lengths, and under the
+// conditions described for Welch.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal fftLen/2+1, CSD will panic.
+func CSD(dst []complex128, x, y []float64, fs float64, win []float64, hop, fftLen int, scaling Scaling) []complex128 {
+	if len(x) != len(y) {
+		panic("spectral: sequence length mismatch")
+	}
+	fftLen = checkSegments(len(x), win, hop, fftLen)
+	scale := complex(scaleFactor(win, fs, scaling), 0)
+	if dst == nil {
+		dst = make([]complex128, fftLen/2+1)
+	}
+	if len(dst) != fftLen/2+1 {
+		panic("spectral: destination length mismatch")
+	}
+	_, _, pxy := averageSpectra(x, y, win, hop, fftLen)
+	for i, v := range pxy {
+		dst[i] = v * scale
+	}
+	cmplxOneSided(dst, fftLen)
+	return dst
+}
+
+// Coherence computes an estimate of the magnitude squared coherence of x
+// and y,
+//
+//	C_xy(f) = |P_xy(f)|^2 / (P_xx(f) * P_yy(f)),
+//
+// where P_xx and P_yy are the power spectra of x and y and P_xy is their
+// cross power spectrum estimated by Welch's method as described for CSD,
+// placing the result in dst and returning it. The coherence is between
+// zero and one. It is NaN at frequencies where either power spectrum is
+// zero.
+//
+// Coherence will panic if x and y have different lengths, and under the
+// conditions described for Welch.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal fftLen/2+1, Coherence will panic.
+func Coherence(dst, x, y []float64, win []float64, hop, fftLen int) []float64 {
+	if len(x) != len(y) {
+		panic("spectral: sequence length mismatch")
+	}
+	fftLen = checkSegments(len(x), win, hop, fftLen)
+	if dst == nil {
+		dst = make([]float64, fftLen/2+1)
+	}
+	if len(dst) != fftLen/2+1 {
+		panic("spectral: destination length mismatch")
+	}
+	pxx, pyy, pxy := averageSpectra(x, y, win, hop, fftLen)
+	for i, v := range pxy {
+		dst[i] = (real(v)*real(v) + imag(v)*imag(v)) / (pxx[i] * pyy[i])
+	}
+	return dst
+}
+
+// checkSegments checks the parameters of a segmented spectral estimate for
+// a sequence of length n, returning the FFT length.
+func checkSegments(n int, win []float64, hop, fftLen int) int {
+	if len(win) == 0 {
+		panic("spectral: empty window")
+	}
+	if len(win) > n {
+		panic("spectral: window longer than sequence")
+	}
+	if hop < 1 {
+		panic("spectral: hop must be positive")
+	}
+	if fftLen == 0 {
+		fftLen = len(win)
+	}
+	if fftLen < len(win) {
+		panic("spectral: FFT length less than window length")
+	}
+	return fftLen
+}
+
+// averageSpectra returns the unscaled means over the segments of x and y
+// of |X|^2, |Y|^2 and conj(X)*Y. If y is nil, only the mean of |X|^2 is
+// returned.
+func averageSpectra(x, y []float64, win []float64, hop, fftLen int) (pxx, pyy []float64, pxy []complex128) {
+	fft := fourier.NewFFT(fftLen)
+	seg := make([]float64, fftLen)
+	cx := make([]complex128, fftLen/2+1)
+	pxx = make([]float64, len(cx))
+	var cy []complex128
+	if y != nil {
+		cy = make([]complex128, len(cx))
+		pyy = make([]float64, len(cx))
+		pxy = make([]complex128, len(cx))
+	}
+
+	var segments int
+	for start := 0; start+len(win) <= len(x); start += hop {
+		segments++
+		for k, w := range win {
+			seg[k] = w * x[start+k]
+		}
+		fft.Coefficients(cx, seg)
+		for i, v := range cx {
+			pxx[i] += real(v)*real(v) + imag(v)*imag(v)
+		}
+		if y == nil {
+			continue
+		}
+		for k, w := range win {
+			seg[k] = w * y[start+k]
+		}
+		fft.Coefficients(cy, seg)
+		for i, v := range cy {
+			pyy[i] += real(v)*real(v) + imag(v)*imag(v)
+			u := cx[i]
+			pxy[i] += complex(real(u), -imag(u)) * v
+		}
+	}
+
+	f := 1 / float64(segments)
+	for i := range pxx {
+		pxx[i] *= f
+		if y != nil {
+			pyy[i] *= f
+			pxy[i] *= complex(f, 0)
+		}
+	}
+	return pxx, pyy, pxy
+}
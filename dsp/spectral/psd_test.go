@@ -0,0 +1,161 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spectral
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/dsp/window"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestPeriodogramParseval(t *testing.T) {
+	t.Parallel()
+	const fs = 250.0
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 7, 64, 101} {
+		x := randSeq(rnd, n)
+		for _, fftLen := range []int{0, 2 * n} {
+			p := Periodogram(nil, x, fs, nil, fftLen, Density)
+			if fftLen == 0 {
+				fftLen = n
+			}
+			// The integral of the density is the mean square when the
+			// sequence is not zero padded, and is otherwise reduced
+			// by the padding ratio.
+			df := fs / float64(fftLen)
+			got := floats.Sum(p) * df
+			var want float64
+			for _, v := range x {
+				want += v * v
+			}
+			want /= float64(n)
+			if fftLen == n && !scalar.EqualWithinRel(got, want, 1e-12) {
+				t.Errorf("n=%d: integral of density does not equal mean square: got %v, want %v", n, got, want)
+			}
+		}
+	}
+}
+
+func TestPeriodogramSinusoid(t *testing.T) {
+	t.Parallel()
+	const (
+		fs  = 1000.0
+		n   = 1000
+		amp = 3.0
+	)
+	for _, f := range []float64{0, 100, 500} {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = amp * math.Cos(2*math.Pi*f*float64(i)/fs)
+		}
+		ms := amp * amp / 2
+		if f == 0 || f == fs/2 {
+			ms = amp * amp
+		}
+		freqs := Frequencies(nil, n, fs)
+		k := int(f * n / fs)
+		if freqs[k] != f {
+			t.Fatalf("unexpected frequency for bin %d: %v", k, freqs[k])
+		}
+		for _, test := range []struct {
+			name string
+			win  []float64
+			tol  float64
+		}{
+			{name: "rectangular", tol: 1e-10},
+			{name: "Hann", win: window.NewValues(window.Hann, n), tol: 1e-2},
+		} {
+			p := Periodogram(nil, x, fs, test.win, 0, Spectrum)
+			if !scalar.EqualWithinRel(p[k], ms, test.tol) {
+				t.Errorf("%s: unexpected power at %v: got %v, want %v", test.name, f, p[k], ms)
+			}
+		}
+	}
+}
+
+func TestWelchWhiteNoise(t *testing.T) {
+	t.Parallel()
+	const (
+		fs    = 10.0
+		sigma = 2.0
+	)
+	rnd := rand.New(rand.NewSource(1))
+	x := randSeq(rnd, 100000)
+	floats.Scale(sigma, x)
+	win := window.NewValues(window.Hann, 128)
+	p := Welch(nil, x, fs, win, 64, 0, Density)
+	if len(p) != 65 {
+		t.Fatalf("unexpected length: %d", len(p))
+	}
+	// The one-sided density of white noise is 2σ²/fs except at zero and
+	// the Nyquist frequency.
+	want := 2 * sigma * sigma / fs
+	if got := stat.Mean(p[1:len(p)-1], nil); !scalar.EqualWithinRel(got, want, 0.02) {
+		t.Errorf("unexpected mean density: got %v, want %v", got, want)
+	}
+	for i, v := range p[1 : len(p)-1] {
+		if !scalar.EqualWithinRel(v, want, 0.2) {
+			t.Errorf("unexpected density at bin %d: got %v, want %v", i+1, v, want)
+		}
+	}
+}
+
+func TestCSDCoherence(t *testing.T) {
+	t.Parallel()
+	const fs = 1.0
+	rnd := rand.New(rand.NewSource(1))
+	n := 20000
+	x := randSeq(rnd, n)
+	noise := randSeq(rnd, n)
+	win := window.NewValues(window.Hann, 64)
+
+	// The cross spectrum of a sequence with itself is its power spectrum.
+	pxx := Welch(nil, x, fs, win, 32, 0, Density)
+	cxx := CSD(nil, x, x, fs, win, 32, 0, Density)
+	for i := range pxx {
+		if cmplx.Abs(cxx[i]-complex(pxx[i], 0)) > 1e-12 {
+			t.Errorf("unexpected cross spectrum at bin %d: got %v, want %v", i, cxx[i], pxx[i])
+		}
+	}
+
+	// A sequence delayed by one sample and scaled is fully coherent with
+	// the original, with a cross spectrum whose phase is the delay.
+	y := make([]float64, n)
+	for i := 1; i < n; i++ {
+		y[i] = -3 * x[i-1]
+	}
+	c := Coherence(nil, x, y, win, 32, 0)
+	for i, v := range c {
+		if !scalar.EqualWithinAbs(v, 1, 1e-2) {
+			t.Errorf("unexpected coherence at bin %d: %v", i, v)
+		}
+	}
+	pxy := CSD(nil, x, y, fs, win, 32, 0, Density)
+	for i := 1; i < len(pxy)-1; i++ {
+		f := float64(i) / 64
+		want := math.Remainder(math.Pi-2*math.Pi*f, 2*math.Pi)
+		if got := cmplx.Phase(pxy[i]); math.Abs(math.Remainder(got-want, 2*math.Pi)) > 0.05 {
+			t.Errorf("unexpected cross spectrum phase at bin %d: got %v, want %v", i, got, want)
+		}
+	}
+
+	// Independent sequences have low coherence.
+	c = Coherence(nil, x, noise, win, 32, 0)
+	if m := stat.Mean(c, nil); m > 0.05 {
+		t.Errorf("unexpected mean coherence of independent sequences: %v", m)
+	}
+	for i, v := range c {
+		if v < 0 || v > 1 {
+			t.Errorf("coherence out of range at bin %d: %v", i, v)
+		}
+	}
+}
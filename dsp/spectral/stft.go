@@ -0,0 +1,256 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spectral
+
+import (
+	"gonum.org/v1/gonum/dsp/fourier"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Padding specifies how a sequence is extended at its boundaries before it
+// is divided into segments by an STFT.
+type Padding int
+
+const (
+	// NoPadding does not extend the sequence at its start, so the first
+	// segment starts at the first sample.
+	NoPadding Padding = iota
+	// ZeroPadding extends the sequence by half the window length of
+	// zeros at both ends, so that segments are centered on multiples of
+	// the hop.
+	ZeroPadding
+	// EvenPadding extends the sequence by half the window length at both
+	// ends by reflection about the first and last samples, so that
+	// segments are centered on multiples of the hop.
+	EvenPadding
+)
+
+// STFT performs short-time Fourier transforms of real sequences.
+//
+// The sequence, extended according to the padding, is divided into
+// segments of the window length starting at multiples of the hop. The last
+// segment is completed with zeros, so every sample is in at least one
+// segment. Each segment is multiplied by the window, zero padded to the
+// FFT length and transformed, giving the coefficients of one frame.
+type STFT struct {
+	win []float64
+	hop int
+	pad Padding
+
+	fft   *fourier.FFT
+	seg   []float64
+	coeff []complex128
+}
+
+// NewSTFT returns a new STFT with the given window weights, hop and FFT
+// length. The segment length is the length of win. If fftLen is zero, the
+// FFT length is the segment length.
+//
+// NewSTFT will panic if win is empty, hop is not positive, fftLen is
+// non-zero and less than len(win), or pad is not a valid padding.
+func NewSTFT(win []float64, hop, fftLen int, pad Padding) *STFT {
+	if len(win) == 0 {
+		panic("spectral: empty window")
+	}
+	if hop < 1 {
+		panic("spectral: hop must be positive")
+	}
+	if fftLen == 0 {
+		fftLen = len(win)
+	}
+	if fftLen < len(win) {
+		panic("spectral: FFT length less than window length")
+	}
+	if pad < NoPadding || EvenPadding < pad {
+		panic("spectral: invalid padding")
+	}
+	return &STFT{
+		win:   append([]float64(nil), win...),
+		hop:   hop,
+		pad:   pad,
+		fft:   fourier.NewFFT(fftLen),
+		seg:   make([]float64, fftLen),
+		coeff: make([]complex128, fftLen/2+1),
+	}
+}
+
+// Frames returns the number of frames in the transform of a sequence of
+// length n.
+func (s *STFT) Frames(n int) int {
+	l := n + 2*s.padLen() - len(s.win)
+	if l <= 0 {
+		return 1
+	}
+	return 1 + (l+s.hop-1)/s.hop
+}
+
+// Bins returns the number of frequency bins in each frame, fftLen/2+1.
+func (s *STFT) Bins() int {
+	return len(s.coeff)
+}
+
+// Freq returns the relative frequency of the bin i in cycles per sample.
+// Multiplying by the sample rate gives the frequency in the units of the
+// sample rate.
+func (s *STFT) Freq(i int) float64 {
+	return s.fft.Freq(i)
+}
+
+// Time returns the index of the sample of the sequence at the center of
+// the segment of frame t. The index is negative for frames centered in the
+// padding at the start of the sequence.
+func (s *STFT) Time(t int) int {
+	return t*s.hop + len(s.win)/2 - s.padLen()
+}
+
+func (s *STFT) padLen() int {
+	if s.pad == NoPadding {
+		return 0
+	}
+	return len(s.win) / 2
+}
+
+// Transform computes the short-time Fourier transform of x and places the
+// result in dst. Row t of dst holds the coefficients of frame t, and
+// column i the coefficients of the frequency bin i.
+//
+// Transform will panic if x is empty. The dst matrix must either be empty
+// or have dimensions Frames(len(x))×Bins(), otherwise Transform will panic.
+func (s *STFT) Transform(dst *mat.CDense, x []float64) {
+	if len(x) == 0 {
+		panic("spectral: empty sequence")
+	}
+	frames := s.Frames(len(x))
+	if dst.IsEmpty() {
+		dst.ReuseAs(frames, s.Bins())
+	} else if r, c := dst.Dims(); r != frames || c != s.Bins() {
+		panic(mat.ErrShape)
+	}
+	raw := dst.RawCMatrix()
+	for t := 0; t < frames; t++ {
+		s.segment(x, t)
+		s.fft.Coefficients(raw.Data[t*raw.Stride:t*raw.Stride+s.Bins()], s.seg)
+	}
+}
+
+// segment fills the segment buffer with the windowed and zero padded
+// segment of frame t of x.
+func (s *STFT) segment(x []float64, t int) {
+	start := t*s.hop - s.padLen()
+	for k, w := range s.win {
+		s.seg[k] = w * s.sample(x, start+k)
+	}
+	for k := len(s.win); k < len(s.seg); k++ {
+		s.seg[k] = 0
+	}
+}
+
+// sample returns the value of the extended sequence at index i of x.
+func (s *STFT) sample(x []float64, i int) float64 {
+	n := len(x)
+	if 0 <= i && i < n {
+		return x[i]
+	}
+	if s.pad != EvenPadding || i < -s.padLen() || n+s.padLen() <= i {
+		return 0
+	}
+	if n == 1 {
+		return x[0]
+	}
+	// Reflect repeatedly for sequences shorter than the padding.
+	period := 2 * (n - 1)
+	i %= period
+	if i < 0 {
+		i += period
+	}
+	if i >= n {
+		i = period - i
+	}
+	return x[i]
+}
+
+// Inverse computes the inverse short-time Fourier transform of the frames
+// in z by weighted overlap-add and places the result in dst, returning it.
+// The result is the sequence whose transform is closest to z in the least
+// squares sense, so the transform of a sequence is inverted exactly.
+// Samples that are not covered by a non-zero window weight are set to zero.
+//
+// If dst is nil, a new slice is allocated and returned with the length of
+// the sequence covered by the frames excluding the padding, which is at
+// least the length of the transformed sequence. If dst is not nil and its
+// length is greater than this, Inverse will panic. Inverse will panic if
+// the number of columns of z is not Bins().
+func (s *STFT) Inverse(dst []float64, z *mat.CDense) []float64 {
+	frames, c := z.Dims()
+	if c != s.Bins() {
+		panic(mat.ErrShape)
+	}
+	p := s.padLen()
+	total := (frames-1)*s.hop + len(s.win)
+	n := total - 2*p
+	if dst == nil {
+		dst = make([]float64, n)
+	}
+	if len(dst) > n {
+		panic("spectral: destination too long")
+	}
+
+	num := make([]float64, total)
+	den := make([]float64, total)
+	scale := 1 / float64(len(s.seg))
+	var maxW float64
+	for t := 0; t < frames; t++ {
+		for i := range s.coeff {
+			s.coeff[i] = z.At(t, i)
+		}
+		s.fft.Sequence(s.seg, s.coeff)
+		start := t * s.hop
+		for k, w := range s.win {
+			num[start+k] += w * s.seg[k] * scale
+			den[start+k] += w * w
+			if w*w > maxW {
+				maxW = w * w
+			}
+		}
+	}
+	for i := range dst {
+		if den[i+p] > 1e-10*maxW {
+			dst[i] = num[i+p] / den[i+p]
+		} else {
+			dst[i] = 0
+		}
+	}
+	return dst
+}
+
+// Spectrogram computes the spectrogram of x, the squared magnitude of the
+// short-time Fourier transform, scaled according to scaling for the sample
+// rate fs, and places the result in dst. Row t of dst holds the one-sided
+// spectrum of frame t, and column i the power in the frequency bin i.
+//
+// Spectrogram will panic if x is empty or fs is not positive. The dst
+// matrix must either be empty or have dimensions Frames(len(x))×Bins(),
+// otherwise Spectrogram will panic.
+func (s *STFT) Spectrogram(dst *mat.Dense, x []float64, fs float64, scaling Scaling) {
+	if len(x) == 0 {
+		panic("spectral: empty sequence")
+	}
+	frames := s.Frames(len(x))
+	if dst.IsEmpty() {
+		dst.ReuseAs(frames, s.Bins())
+	} else if r, c := dst.Dims(); r != frames || c != s.Bins() {
+		panic(mat.ErrShape)
+	}
+	scale := scaleFactor(s.win, fs, scaling)
+	for t := 0; t < frames; t++ {
+		s.segment(x, t)
+		s.fft.Coefficients(s.coeff, s.seg)
+		row := dst.RawRowView(t)
+		for i, v := range s.coeff {
+			row[i] = (real(v)*real(v) + imag(v)*imag(v)) * scale
+		}
+		oneSided(row, len(s.seg))
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spectral
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/dsp/window"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+func randSeq(rnd *rand.Rand, n int) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+	}
+	return x
+}
+
+func TestSTFTInverse(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 5, 64, 100, 1001} {
+		x := randSeq(rnd, n)
+		for _, winLen := range []int{2, 16, 33} {
+			win := window.NewValues(window.Hamming, winLen)
+			for _, hop := range []int{1, winLen/2 + 1, winLen} {
+				for _, fftLen := range []int{0, 2 * winLen} {
+					for _, pad := range []Padding{NoPadding, ZeroPadding, EvenPadding} {
+						name := fmt.Sprintf("n=%d win=%d hop=%d fft=%d pad=%d", n, winLen, hop, fftLen, pad)
+						s := NewSTFT(win, hop, fftLen, pad)
+						var z mat.CDense
+						s.Transform(&z, x)
+						if r, c := z.Dims(); r != s.Frames(n) || c != s.Bins() {
+							t.Errorf("%s: unexpected dimensions: %d×%d", name, r, c)
+							continue
+						}
+						got := s.Inverse(nil, &z)
+						if len(got) < n {
+							t.Errorf("%s: inverse too short: %d", name, len(got))
+							continue
+						}
+						if !floats.EqualApprox(got[:n], x, tol) {
+							t.Errorf("%s: unexpected inverse", name)
+						}
+						if pad == EvenPadding {
+							continue
+						}
+						// Samples past the end of the sequence are
+						// the zeros completing the last segment.
+						for i, v := range got[n:] {
+							if math.Abs(v) > tol {
+								t.Errorf("%s: unexpected non-zero sample at %d: %v", name, n+i, v)
+								break
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSTFTTransform(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	rnd := rand.New(rand.NewSource(1))
+	x := randSeq(rnd, 50)
+	win := window.NewValues(window.Hann, 10)
+	for _, pad := range []Padding{NoPadding, ZeroPadding, EvenPadding} {
+		s := NewSTFT(win, 4, 12, pad)
+		var z mat.CDense
+		s.Transform(&z, x)
+		p := 0
+		if pad != NoPadding {
+			p = len(win) / 2
+		}
+		frames, bins := z.Dims()
+		for tm := 0; tm < frames; tm++ {
+			if got, want := s.Time(tm), tm*4-p+len(win)/2; got != want {
+				t.Errorf("pad=%d: unexpected time for frame %d: got %d, want %d", pad, tm, got, want)
+			}
+			for k := 0; k < bins; k++ {
+				var want complex128
+				for j, w := range win {
+					i := tm*4 - p + j
+					var v float64
+					switch {
+					case 0 <= i && i < len(x):
+						v = x[i]
+					case pad == EvenPadding && i < 0:
+						v = x[-i]
+					case pad == EvenPadding && i < len(x)+p:
+						v = x[2*(len(x)-1)-i]
+					}
+					want += complex(w*v, 0) * cmplx.Rect(1, -2*math.Pi*float64(j*k)/12)
+				}
+				if got := z.At(tm, k); cmplx.Abs(got-want) > tol {
+					t.Errorf("pad=%d: unexpected coefficient at frame %d bin %d: got %v, want %v", pad, tm, k, got, want)
+				}
+			}
+		}
+		// The last frame covers the last sample.
+		if last := (frames-1)*4 - p + len(win); last < len(x) {
+			t.Errorf("pad=%d: last frame ends before the end of the sequence", pad)
+		}
+		if last := (frames-2)*4 - p + len(win); last >= len(x)+p {
+			t.Errorf("pad=%d: unnecessary frame", pad)
+		}
+	}
+}
+
+func TestEvenPaddingShort(t *testing.T) {
+	t.Parallel()
+	// Padding longer than the sequence reflects repeatedly.
+	x := []float64{1, 2, 3}
+	s := NewSTFT(make([]float64, 11), 1, 0, EvenPadding)
+	var got []float64
+	for i := -5; i < 8; i++ {
+		got = append(got, s.sample(x, i))
+	}
+	want := []float64{2, 1, 2, 3, 2, 1, 2, 3, 2, 1, 2, 3, 2}
+	if !floats.Equal(got, want) {
+		t.Errorf("unexpected padded sequence: got %v, want %v", got, want)
+	}
+}
+
+func TestSpectrogram(t *testing.T) {
+	t.Parallel()
+	const (
+		fs  = 100.0
+		tol = 1e-12
+	)
+	rnd := rand.New(rand.NewSource(1))
+	// The sequence length is a whole number of hops past the window length
+	// so the frames without padding are the segments used by Welch.
+	x := randSeq(rnd, 32+20*16)
+	win := window.NewValues(window.Hann, 32)
+	for _, scaling := range []Scaling{Density, Spectrum} {
+		s := NewSTFT(win, 16, 64, NoPadding)
+		var spec mat.Dense
+		s.Spectrogram(&spec, x, fs, scaling)
+		frames, bins := spec.Dims()
+		if frames != 21 || bins != 33 {
+			t.Fatalf("unexpected dimensions: %d×%d", frames, bins)
+		}
+		got := make([]float64, bins)
+		for i := 0; i < frames; i++ {
+			floats.Add(got, spec.RawRowView(i))
+		}
+		floats.Scale(1/float64(frames), got)
+		want := Welch(nil, x, fs, win, 16, 64, scaling)
+		if !floats.EqualApprox(got, want, tol) {
+			t.Errorf("scaling %d: mean of spectrogram differs from Welch estimate", scaling)
+		}
+		for i := range got {
+			if f := s.Freq(i) * fs; !scalar.EqualWithinAbs(f, float64(i)*fs/64, tol) {
+				t.Errorf("unexpected frequency for bin %d: %v", i, f)
+			}
+		}
+	}
+}
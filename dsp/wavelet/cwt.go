@@ -0,0 +1,167 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wavelet
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ContinuousWavelet is a mother wavelet of the continuous wavelet
+// transform, normalized to unit energy.
+type ContinuousWavelet interface {
+	// FourierTransform returns the Fourier transform of the
+	// wavelet, ∫ψ(t) exp(-iωt) dt, at the angular frequency omega.
+	FourierTransform(omega float64) complex128
+
+	// FourierPeriod returns the period of the sinusoid whose
+	// continuous wavelet transform peaks at the given scale,
+	// in units of the sample spacing.
+	FourierPeriod(scale float64) float64
+}
+
+// Morlet is the complex Morlet wavelet,
+//
+//	ψ(t) = π^(-1/4) exp(iω₀t) exp(-t²/2),
+//
+// where ω₀ is the central angular frequency Omega0. The wavelet is
+// approximately admissible for ω₀ of about 5 or greater. If Omega0 is zero,
+// ω₀ = 6 is used.
+//
+// References:
+//   - Torrence, C. and Compo, G. P. (1998). A practical guide to wavelet
+//     analysis. Bulletin of the American Meteorological Society, 79(1),
+//     61-78.
+type Morlet struct {
+	Omega0 float64
+}
+
+func (m Morlet) omega0() float64 {
+	if m.Omega0 == 0 {
+		return 6
+	}
+	return m.Omega0
+}
+
+// FourierTransform returns the Fourier transform of the Morlet wavelet.
+func (m Morlet) FourierTransform(omega float64) complex128 {
+	d := omega - m.omega0()
+	return complex(math.Pow(math.Pi, -0.25)*math.Sqrt(2*math.Pi)*math.Exp(-d*d/2), 0)
+}
+
+// FourierPeriod returns the Fourier period of the Morlet wavelet at the
+// given scale.
+func (m Morlet) FourierPeriod(scale float64) float64 {
+	w := m.omega0()
+	return 4 * math.Pi * scale / (w + math.Sqrt(2+w*w))
+}
+
+// MexicanHat is the Mexican hat wavelet, the negative normalized second
+// derivative of a Gaussian,
+//
+//	ψ(t) = 2/(√3 π^(1/4)) (1-t²) exp(-t²/2).
+type MexicanHat struct{}
+
+// FourierTransform returns the Fourier transform of the Mexican hat
+// wavelet.
+func (MexicanHat) FourierTransform(omega float64) complex128 {
+	w2 := omega * omega
+	return complex(2/(math.Sqrt(3)*math.Pow(math.Pi, 0.25))*math.Sqrt(2*math.Pi)*w2*math.Exp(-w2/2), 0)
+}
+
+// FourierPeriod returns the Fourier period of the Mexican hat wavelet at
+// the given scale.
+func (MexicanHat) FourierPeriod(scale float64) float64 {
+	return 2 * math.Pi * scale / math.Sqrt(2.5)
+}
+
+// CWT computes the continuous wavelet transform of x with the wavelet w at
+// the given scales, placing the result into dst. Row i of dst holds the
+// coefficients at scales[i] and column j the coefficients at sample j,
+//
+//	W(s, b) = \sum_n x[n] s^(-1/2) ψ*((n-b)/s).
+//
+// The transform is computed by FFT, with x padded with zeros to avoid
+// circular wrap-around, and the wavelet sampled in the frequency domain.
+//
+// The dst matrix must either be empty or have len(scales) rows and len(x)
+// columns. CWT will panic if a scale is not positive or the length of x is
+// zero.
+func CWT(dst *mat.CDense, x, scales []float64, w ContinuousWavelet) {
+	reuseCDense(dst, len(scales), len(x))
+	cwt(x, scales, w, func(i int, row []complex128) {
+		for j, v := range row {
+			dst.Set(i, j, v)
+		}
+	})
+}
+
+// Scalogram computes the scalogram of x with the wavelet w at the given
+// scales, the squared magnitude of the continuous wavelet transform
+// computed by CWT, placing the result into dst. Row i of dst holds the
+// power at scales[i] and column j the power at sample j.
+//
+// The dst matrix must either be empty or have len(scales) rows and len(x)
+// columns. Scalogram will panic if a scale is not positive or the length
+// of x is zero.
+func Scalogram(dst *mat.Dense, x, scales []float64, w ContinuousWavelet) {
+	if dst.IsEmpty() {
+		dst.ReuseAs(len(scales), len(x))
+	} else if r, c := dst.Dims(); r != len(scales) || c != len(x) {
+		panic(mat.ErrShape)
+	}
+	cwt(x, scales, w, func(i int, row []complex128) {
+		for j, v := range row {
+			re, im := real(v), imag(v)
+			dst.Set(i, j, re*re+im*im)
+		}
+	})
+}
+
+// cwt calls fn with the continuous wavelet transform of x at each scale.
+func cwt(x, scales []float64, w ContinuousWavelet, fn func(i int, row []complex128)) {
+	if len(x) == 0 {
+		panic("wavelet: empty sequence")
+	}
+	for _, s := range scales {
+		if !(s > 0) {
+			panic("wavelet: scale not positive")
+		}
+	}
+	n := 1
+	for n < 2*len(x) {
+		n <<= 1
+	}
+	fft := fourier.NewCmplxFFT(n)
+	seq := make([]complex128, n)
+	for i, v := range x {
+		seq[i] = complex(v, 0)
+	}
+	coeff := fft.Coefficients(nil, seq)
+	work := make([]complex128, n)
+	for i, s := range scales {
+		norm := math.Sqrt(s) / float64(n)
+		for k, c := range coeff {
+			omega := 2 * math.Pi * fft.Freq(k)
+			work[k] = c * cmplx.Conj(w.FourierTransform(s*omega)) * complex(norm, 0)
+		}
+		fn(i, fft.Sequence(seq, work)[:len(x)])
+	}
+}
+
+// reuseCDense resizes an empty dst to r×c, or panics if a non-empty dst
+// does not have those dimensions.
+func reuseCDense(dst *mat.CDense, r, c int) {
+	if dst.IsEmpty() {
+		dst.ReuseAs(r, c)
+		return
+	}
+	if dr, dc := dst.Dims(); dr != r || dc != c {
+		panic(mat.ErrShape)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wavelet
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// morlet and mexicanHat are the time domain continuous wavelets.
+func morlet(t float64) complex128 {
+	return complex(math.Pow(math.Pi, -0.25)*math.Exp(-t*t/2), 0) * cmplx.Rect(1, 6*t)
+}
+
+func mexicanHat(t float64) complex128 {
+	return complex(2/(math.Sqrt(3)*math.Pow(math.Pi, 0.25))*(1-t*t)*math.Exp(-t*t/2), 0)
+}
+
+func TestCWT(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 256
+	x := randSeq(rnd, n)
+	scales := []float64{4, 6.5, 16}
+	for _, test := range []struct {
+		name string
+		w    ContinuousWavelet
+		psi  func(float64) complex128
+	}{
+		{name: "morlet", w: Morlet{}, psi: morlet},
+		{name: "mexican hat", w: MexicanHat{}, psi: mexicanHat},
+	} {
+		var got mat.CDense
+		CWT(&got, x, scales, test.w)
+		if r, c := got.Dims(); r != len(scales) || c != n {
+			t.Fatalf("unexpected dimensions for %s: got:%d×%d want:%d×%d", test.name, r, c, len(scales), n)
+		}
+		var power mat.Dense
+		Scalogram(&power, x, scales, test.w)
+		for i, s := range scales {
+			for b := 0; b < n; b++ {
+				var want complex128
+				for j, v := range x {
+					want += complex(v/math.Sqrt(s), 0) * cmplx.Conj(test.psi(float64(j-b)/s))
+				}
+				if cmplx.Abs(got.At(i, b)-want) > 1e-8 {
+					t.Errorf("unexpected coefficient for %s at scale %v, sample %d: got:%v want:%v",
+						test.name, s, b, got.At(i, b), want)
+				}
+				a := cmplx.Abs(want)
+				if math.Abs(power.At(i, b)-a*a) > 1e-8*(1+a*a) {
+					t.Errorf("unexpected power for %s at scale %v, sample %d: got:%v want:%v",
+						test.name, s, b, power.At(i, b), a*a)
+				}
+			}
+		}
+	}
+}
+
+func TestScalogramPeak(t *testing.T) {
+	t.Parallel()
+	const (
+		n      = 1024
+		period = 40.0
+	)
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = math.Cos(2 * math.Pi * float64(i) / period)
+	}
+	scales := make([]float64, 200)
+	for i := range scales {
+		scales[i] = 1 + float64(i)*0.5
+	}
+	for _, w := range []ContinuousWavelet{Morlet{}, Morlet{Omega0: 10}, MexicanHat{}} {
+		var power mat.Dense
+		Scalogram(&power, x, scales, w)
+		best := -1
+		var max float64
+		for i := range scales {
+			// Average away from the ends of the sequence.
+			var p float64
+			for b := n / 4; b < 3*n/4; b++ {
+				p += power.At(i, b)
+			}
+			if p > max {
+				max = p
+				best = i
+			}
+		}
+		got := w.FourierPeriod(scales[best])
+		if math.Abs(got-period) > 0.02*period {
+			t.Errorf("unexpected peak period for %T: got:%v want:%v", w, got, period)
+		}
+	}
+}
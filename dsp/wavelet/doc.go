@@ -0,0 +1,19 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:generate go run generate_filters.go
+
+// Package wavelet provides discrete and continuous wavelet transforms.
+//
+// The discrete wavelet transform decomposes a sequence into approximation
+// and detail coefficients with the two-channel filter bank of a Wavelet,
+// optionally over multiple levels. The stationary wavelet transform is an
+// undecimated variant that is invariant to shifts of the sequence.
+// Thresholding the detail coefficients and reconstructing the sequence
+// removes noise.
+//
+// The continuous wavelet transform correlates a sequence with scaled
+// copies of a ContinuousWavelet, giving a time-scale representation of the
+// sequence.
+package wavelet // import "gonum.org/v1/gonum/dsp/wavelet"
@@ -0,0 +1,320 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wavelet
+
+// Mode specifies how a sequence is extended beyond its ends by the
+// discrete wavelet transform.
+type Mode int
+
+const (
+	// Zero extends the sequence with zeros.
+	Zero Mode = iota
+	// Constant extends the sequence by repeating its first and
+	// last values.
+	Constant
+	// Symmetric extends the sequence by reflecting it about its ends,
+	// repeating the end values, so that x[-1] = x[0].
+	Symmetric
+	// Reflect extends the sequence by reflecting it about its end
+	// values, so that x[-1] = x[1].
+	Reflect
+	// Periodic extends the sequence by repeating it.
+	Periodic
+	// Periodization treats the sequence as periodic and produces
+	// the minimum number of coefficients, ceil(n/2) for a sequence
+	// of length n. A sequence of odd length is first extended by
+	// repeating its last value.
+	Periodization
+)
+
+// ext returns the value of the extension of x at index i
+// according to the mode.
+func (m Mode) ext(x []float64, i int) float64 {
+	n := len(x)
+	if 0 <= i && i < n {
+		return x[i]
+	}
+	switch m {
+	case Zero:
+		return 0
+	case Constant:
+		if i < 0 {
+			return x[0]
+		}
+		return x[n-1]
+	case Symmetric:
+		i = mod(i, 2*n)
+		if i >= n {
+			i = 2*n - 1 - i
+		}
+		return x[i]
+	case Reflect:
+		if n == 1 {
+			return x[0]
+		}
+		i = mod(i, 2*n-2)
+		if i >= n {
+			i = 2*n - 2 - i
+		}
+		return x[i]
+	case Periodic, Periodization:
+		return x[mod(i, n)]
+	default:
+		panic("wavelet: invalid mode")
+	}
+}
+
+func mod(i, n int) int {
+	i %= n
+	if i < 0 {
+		i += n
+	}
+	return i
+}
+
+// CoeffLen returns the number of approximation and detail coefficients
+// returned by DWT for a sequence of length n.
+func CoeffLen(n int, w Wavelet, mode Mode) int {
+	if n < 1 {
+		panic("wavelet: sequence length less than one")
+	}
+	if mode == Periodization {
+		return (n + 1) / 2
+	}
+	return (n + w.Len() - 1) / 2
+}
+
+// MaxLevel returns the maximum level of decomposition of a sequence of
+// length n that is useful with the wavelet w, the largest level at which
+// the approximation coefficients are no shorter than the filters.
+func MaxLevel(n int, w Wavelet) int {
+	l := w.Len() - 1
+	if l < 1 {
+		return 0
+	}
+	var level int
+	for l<<(level+1) <= n {
+		level++
+	}
+	return level
+}
+
+// DWT computes the single level discrete wavelet transform of x, placing
+// the approximation coefficients into approx and the detail coefficients
+// into detail, and returning them. The number of coefficients is given by
+// CoeffLen.
+//
+// If approx or detail is nil, a new slice is allocated and returned. If
+// approx or detail is not nil and its length does not equal the number of
+// coefficients, DWT will panic.
+func DWT(approx, detail, x []float64, w Wavelet, mode Mode) (a, d []float64) {
+	n := CoeffLen(len(x), w, mode)
+	approx = useSlice(approx, n)
+	detail = useSlice(detail, n)
+	l := w.Len()
+	if mode == Periodization {
+		if len(x)%2 == 1 {
+			x = append(x[:len(x):len(x)], x[len(x)-1])
+		}
+		for o := range approx {
+			var sa, sd float64
+			for j := 0; j < l; j++ {
+				v := x[mod(2*o+l/2-j, len(x))]
+				sa += w.DecLo[j] * v
+				sd += w.DecHi[j] * v
+			}
+			approx[o] = sa
+			detail[o] = sd
+		}
+		return approx, detail
+	}
+	for o := range approx {
+		var sa, sd float64
+		i := 2*o + 1
+		if i-l+1 >= 0 && i < len(x) {
+			for j, v := range x[i-l+1 : i+1] {
+				sa += w.DecLo[l-1-j] * v
+				sd += w.DecHi[l-1-j] * v
+			}
+		} else {
+			for j := 0; j < l; j++ {
+				v := mode.ext(x, i-j)
+				sa += w.DecLo[j] * v
+				sd += w.DecHi[j] * v
+			}
+		}
+		approx[o] = sa
+		detail[o] = sd
+	}
+	return approx, detail
+}
+
+// IDWT computes the single level inverse discrete wavelet transform of the
+// approximation and detail coefficients, placing the reconstructed sequence
+// into dst and returning it. Either approx or detail may be nil, in which
+// case those coefficients are treated as zero.
+//
+// If dst is nil, a new slice of length 2*n-w.Len()+2 is allocated, or of
+// length 2*n when mode is Periodization, where n is the number of
+// coefficients. If dst is not nil, CoeffLen(len(dst), w, mode) must equal
+// the number of coefficients, and dst receives the first len(dst) values of
+// the reconstruction. IDWT will panic if both approx and detail are nil, if
+// they have different lengths, or if the length of dst is not valid.
+func IDWT(dst, approx, detail []float64, w Wavelet, mode Mode) []float64 {
+	n := len(approx)
+	switch {
+	case approx == nil && detail == nil:
+		panic("wavelet: no coefficients")
+	case approx == nil:
+		n = len(detail)
+	case detail != nil && len(detail) != n:
+		panic("wavelet: coefficient length mismatch")
+	}
+	l := w.Len()
+	if dst == nil {
+		m := 2*n - l + 2
+		if mode == Periodization {
+			m = 2 * n
+		}
+		if m < 1 {
+			panic("wavelet: too few coefficients")
+		}
+		dst = make([]float64, m)
+	} else {
+		if len(dst) == 0 || CoeffLen(len(dst), w, mode) != n {
+			panic("wavelet: destination length mismatch")
+		}
+		for i := range dst {
+			dst[i] = 0
+		}
+	}
+
+	if mode == Periodization {
+		p := len(dst) + len(dst)%2
+		for o := 0; o < n; o++ {
+			var a, d float64
+			if approx != nil {
+				a = approx[o]
+			}
+			if detail != nil {
+				d = detail[o]
+			}
+			for j := 0; j < l; j++ {
+				k := mod(2*o+j-l/2+1, p)
+				if k < len(dst) {
+					dst[k] += a*w.RecLo[j] + d*w.RecHi[j]
+				}
+			}
+		}
+		return dst
+	}
+	for o := 0; o < n; o++ {
+		var a, d float64
+		if approx != nil {
+			a = approx[o]
+		}
+		if detail != nil {
+			d = detail[o]
+		}
+		for j := 0; j < l; j++ {
+			k := 2*o + j - l + 2
+			if 0 <= k && k < len(dst) {
+				dst[k] += a*w.RecLo[j] + d*w.RecHi[j]
+			}
+		}
+	}
+	return dst
+}
+
+// Decomposition is a multilevel discrete wavelet decomposition of a
+// sequence. The coefficients may be modified before reconstruction, for
+// example by thresholding the detail coefficients.
+type Decomposition struct {
+	// Wavelet and Mode are the wavelet and extension mode
+	// of the decomposition.
+	Wavelet Wavelet
+	Mode    Mode
+
+	// Approx holds the approximation coefficients at the
+	// coarsest level of the decomposition.
+	Approx []float64
+	// Detail holds the detail coefficients at each level,
+	// with Detail[0] holding the finest level.
+	Detail [][]float64
+
+	// lens holds the length of the sequence that
+	// is decomposed at each level.
+	lens []int
+}
+
+// Decompose returns the multilevel discrete wavelet decomposition of x with
+// the wavelet w, repeatedly applying DWT to the approximation coefficients
+// to obtain the given number of levels. If level is zero, the decomposition
+// has MaxLevel(len(x), w) levels. Decompose will panic if level is negative
+// or the length of x is zero.
+func Decompose(x []float64, w Wavelet, mode Mode, level int) *Decomposition {
+	if level < 0 {
+		panic("wavelet: negative level")
+	}
+	if len(x) == 0 {
+		panic("wavelet: empty sequence")
+	}
+	if level == 0 {
+		level = MaxLevel(len(x), w)
+	}
+	d := &Decomposition{
+		Wavelet: w,
+		Mode:    mode,
+		Approx:  append([]float64(nil), x...),
+		Detail:  make([][]float64, level),
+		lens:    make([]int, level),
+	}
+	for j := 0; j < level; j++ {
+		d.lens[j] = len(d.Approx)
+		d.Approx, d.Detail[j] = DWT(nil, nil, d.Approx, w, mode)
+	}
+	return d
+}
+
+// Levels returns the number of levels of the decomposition.
+func (d *Decomposition) Levels() int { return len(d.Detail) }
+
+// Len returns the length of the decomposed sequence.
+func (d *Decomposition) Len() int {
+	if len(d.lens) == 0 {
+		return len(d.Approx)
+	}
+	return d.lens[0]
+}
+
+// Reconstruct places the sequence reconstructed from the coefficients of
+// the decomposition into dst and returns it.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of the decomposed
+// sequence, Reconstruct will panic.
+func (d *Decomposition) Reconstruct(dst []float64) []float64 {
+	dst = useSlice(dst, d.Len())
+	if len(d.Detail) == 0 {
+		copy(dst, d.Approx)
+		return dst
+	}
+	a := d.Approx
+	for j := len(d.Detail) - 1; j > 0; j-- {
+		a = IDWT(make([]float64, d.lens[j]), a, d.Detail[j], d.Wavelet, d.Mode)
+	}
+	return IDWT(dst, a, d.Detail[0], d.Wavelet, d.Mode)
+}
+
+// useSlice returns a slice of length n, allocating it if dst is nil.
+func useSlice(dst []float64, n int) []float64 {
+	if dst == nil {
+		return make([]float64, n)
+	}
+	if len(dst) != n {
+		panic("wavelet: destination length mismatch")
+	}
+	return dst
+}
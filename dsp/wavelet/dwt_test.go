@@ -0,0 +1,170 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wavelet
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+var modes = []Mode{Zero, Constant, Symmetric, Reflect, Periodic, Periodization}
+
+func randSeq(rnd *rand.Rand, n int) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+	}
+	return x
+}
+
+func TestDWTHaar(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 3, 2, 6, 5}
+	a, d := DWT(nil, nil, x, Haar(), Symmetric)
+	s := math.Sqrt2
+	wantA := []float64{4 / s, 8 / s, 10 / s}
+	wantD := []float64{-2 / s, -4 / s, 0}
+	if !floats.EqualApprox(a, wantA, 1e-14) {
+		t.Errorf("unexpected approximation coefficients: got:%v want:%v", a, wantA)
+	}
+	if !floats.EqualApprox(d, wantD, 1e-14) {
+		t.Errorf("unexpected detail coefficients: got:%v want:%v", d, wantD)
+	}
+	got := IDWT(nil, a, d, Haar(), Symmetric)
+	if len(got) != 6 {
+		t.Errorf("unexpected reconstruction length: got:%d want:6", len(got))
+	}
+	if !floats.EqualApprox(got[:5], x, 1e-14) {
+		t.Errorf("unexpected reconstruction: got:%v want:%v", got[:5], x)
+	}
+}
+
+func TestDWTReconstruction(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, w := range allWavelets() {
+		for _, mode := range modes {
+			for _, n := range []int{1, 2, 5, 16, 33, 64} {
+				x := randSeq(rnd, n)
+				a, d := DWT(nil, nil, x, w, mode)
+				if len(a) != CoeffLen(n, w, mode) || len(d) != len(a) {
+					t.Errorf("unexpected coefficient length for %s mode %d n=%d: got:%d,%d want:%d",
+						w.Name, mode, n, len(a), len(d), CoeffLen(n, w, mode))
+				}
+				got := IDWT(make([]float64, n), a, d, w, mode)
+				if !floats.EqualApprox(got, x, 1e-10) {
+					t.Errorf("unexpected reconstruction for %s mode %d n=%d:\ngot: %v\nwant:%v", w.Name, mode, n, got, x)
+				}
+
+				// The approximation and detail parts sum
+				// to the sequence.
+				lo := IDWT(make([]float64, n), a, nil, w, mode)
+				hi := IDWT(make([]float64, n), nil, d, w, mode)
+				floats.Add(lo, hi)
+				if !floats.EqualApprox(lo, x, 1e-10) {
+					t.Errorf("unexpected sum of partial reconstructions for %s mode %d n=%d", w.Name, mode, n)
+				}
+			}
+		}
+	}
+}
+
+func TestDWTPeriodizationEnergy(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, w := range allWavelets() {
+		if strings.HasPrefix(w.Name, "bior") {
+			continue
+		}
+		for _, n := range []int{2, 8, 64} {
+			x := randSeq(rnd, n)
+			a, d := DWT(nil, nil, x, w, Periodization)
+			got := floats.Dot(a, a) + floats.Dot(d, d)
+			want := floats.Dot(x, x)
+			if !scalar.EqualWithinRel(got, want, 1e-12) {
+				t.Errorf("energy not preserved for %s n=%d: got:%v want:%v", w.Name, n, got, want)
+			}
+		}
+	}
+}
+
+func TestDWTVanishingMoments(t *testing.T) {
+	t.Parallel()
+	for n := 1; n <= 8; n++ {
+		w := Daubechies(n)
+		// Polynomials of degree less than n have zero
+		// detail coefficients away from the ends.
+		x := make([]float64, 64)
+		for i := range x {
+			u := float64(i) / float64(len(x))
+			x[i] = math.Pow(u-0.5, float64(n-1)) + 1
+		}
+		_, d := DWT(nil, nil, x, w, Zero)
+		for o := w.Len() / 2; o < len(x)/2; o++ {
+			if !scalar.EqualWithinAbs(d[o], 0, 1e-10) {
+				t.Errorf("unexpected detail coefficient %d for %s: got:%v want:0", o, w.Name, d[o])
+			}
+		}
+	}
+}
+
+func TestDecompose(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, w := range []Wavelet{Haar(), Daubechies(4), Symlet(8), Coiflet(2), Biorthogonal(3, 5)} {
+		for _, mode := range modes {
+			for _, n := range []int{7, 100, 256} {
+				x := randSeq(rnd, n)
+				for _, level := range []int{0, 1, 3} {
+					name := fmt.Sprintf("%s mode %d n=%d level=%d", w.Name, mode, n, level)
+					d := Decompose(x, w, mode, level)
+					want := level
+					if level == 0 {
+						want = MaxLevel(n, w)
+					}
+					if d.Levels() != want {
+						t.Errorf("unexpected number of levels for %s: got:%d want:%d", name, d.Levels(), want)
+					}
+					if d.Len() != n {
+						t.Errorf("unexpected length for %s: got:%d want:%d", name, d.Len(), n)
+					}
+					got := d.Reconstruct(nil)
+					if !floats.EqualApprox(got, x, 1e-10) {
+						t.Errorf("unexpected reconstruction for %s", name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMaxLevel(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		n    int
+		w    Wavelet
+		want int
+	}{
+		{n: 1, w: Haar(), want: 0},
+		{n: 2, w: Haar(), want: 1},
+		{n: 1024, w: Haar(), want: 10},
+		{n: 1000, w: Daubechies(4), want: 7},
+		{n: 6, w: Daubechies(4), want: 0},
+		{n: 7, w: Daubechies(4), want: 0},
+		{n: 14, w: Daubechies(4), want: 1},
+	} {
+		got := MaxLevel(test.n, test.w)
+		if got != test.want {
+			t.Errorf("unexpected max level for n=%d %s: got:%d want:%d", test.n, test.w.Name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wavelet_test
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/dsp/wavelet"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleDecompose() {
+	// Make a noisy piecewise smooth signal.
+	rnd := rand.New(rand.NewSource(1))
+	const n = 1024
+	signal := make([]float64, n)
+	noisy := make([]float64, n)
+	for i := range signal {
+		t := float64(i) / n
+		signal[i] = math.Sin(4 * math.Pi * t)
+		if t > 0.5 {
+			signal[i] += 1
+		}
+		noisy[i] = signal[i] + 0.2*rnd.NormFloat64()
+	}
+
+	// Decompose the signal, estimate the noise level from the
+	// finest detail coefficients and hard threshold the detail
+	// coefficients at the universal threshold.
+	d := wavelet.Decompose(noisy, wavelet.Symlet(6), wavelet.Periodization, 5)
+	sigma := wavelet.NoiseSigma(d.Detail[0])
+	thresh := wavelet.UniversalThreshold(sigma, n)
+	for _, c := range d.Detail {
+		wavelet.Hard(c, thresh)
+	}
+	denoised := d.Reconstruct(nil)
+
+	fmt.Printf("estimated noise level: %.2f\n", sigma)
+	fmt.Printf("RMS error before: %.3f\n", floats.Distance(noisy, signal, 2)/math.Sqrt(n))
+	fmt.Printf("RMS error after:  %.3f\n", floats.Distance(denoised, signal, 2)/math.Sqrt(n))
+
+	// Output:
+	// estimated noise level: 0.19
+	// RMS error before: 0.200
+	// RMS error after:  0.067
+}
+
+func ExampleScalogram() {
+	// Make a signal whose period changes from 16 to 64
+	// samples half way through.
+	const n = 512
+	x := make([]float64, n)
+	for i := range x {
+		period := 16.0
+		if i >= n/2 {
+			period = 64
+		}
+		x[i] = math.Sin(2 * math.Pi * float64(i) / period)
+	}
+
+	// Compute the scalogram over a range of scales.
+	w := wavelet.Morlet{}
+	scales := make([]float64, 160)
+	for i := range scales {
+		scales[i] = math.Pow(2, 2+float64(i)/32)
+	}
+	var power mat.Dense
+	wavelet.Scalogram(&power, x, scales, w)
+
+	// Find the period of the strongest component at
+	// a sample in each half of the signal.
+	for _, b := range []int{n / 4, 3 * n / 4} {
+		col := mat.Col(nil, b, &power)
+		fmt.Printf("period at sample %d: %.0f\n", b, w.FourierPeriod(scales[floats.MaxIdx(col)]))
+	}
+
+	// Output:
+	// period at sample 128: 16
+	// period at sample 384: 63
+}
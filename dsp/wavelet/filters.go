@@ -0,0 +1,1039 @@
+// Code generated by "go generate gonum.org/v1/gonum/dsp/wavelet"; DO NOT EDIT.
+
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wavelet
+
+// daubechies holds the scaling filters of the Daubechies wavelets with 1 to 20 vanishing moments.
+var daubechies = [...][]float64{
+	0: {
+		0.7071067811865476,
+		0.7071067811865476,
+	},
+	1: {
+		0.48296291314453416,
+		0.8365163037378078,
+		0.2241438680420134,
+		-0.12940952255126037,
+	},
+	2: {
+		0.3326705529500826,
+		0.8068915093110924,
+		0.4598775021184916,
+		-0.13501102001025458,
+		-0.08544127388202666,
+		0.035226291885709526,
+	},
+	3: {
+		0.2303778133088965,
+		0.7148465705529158,
+		0.6308807679298588,
+		-0.027983769416859802,
+		-0.18703481171909309,
+		0.03084138183556075,
+		0.0328830116668852,
+		-0.010597401785069032,
+	},
+	4: {
+		0.1601023979741929,
+		0.6038292697971895,
+		0.724308528437773,
+		0.13842814590132058,
+		-0.2422948870663819,
+		-0.0322448695846384,
+		0.07757149384004568,
+		-0.0062414902127982605,
+		-0.012580751999081997,
+		0.0033357252854737695,
+	},
+	5: {
+		0.11154074335010952,
+		0.4946238903984533,
+		0.7511339080210956,
+		0.31525035170919774,
+		-0.2262646939654402,
+		-0.12976686756726172,
+		0.09750160558732306,
+		0.02752286553030577,
+		-0.03158203931748607,
+		0.0005538422011615073,
+		0.004777257510945513,
+		-0.0010773010853084807,
+	},
+	6: {
+		0.07785205408500921,
+		0.39653931948191745,
+		0.7291320908462354,
+		0.46978228740519345,
+		-0.14390600392856556,
+		-0.22403618499387454,
+		0.07130921926683004,
+		0.08061260915108319,
+		-0.03802993693501448,
+		-0.016574541630666857,
+		0.012550998556099834,
+		0.0004295779729213684,
+		-0.001801640704047492,
+		0.00035371379997452046,
+	},
+	7: {
+		0.05441584224310403,
+		0.31287159091430017,
+		0.67563073629729,
+		0.585354683654207,
+		-0.015829105256349806,
+		-0.2840155429615461,
+		0.00047248457391206887,
+		0.12874742662047936,
+		-0.0173693010018079,
+		-0.044088253930794706,
+		0.013981027917398301,
+		0.008746094047405763,
+		-0.00487035299345157,
+		-0.00039174037337694824,
+		0.0006754494064505695,
+		-0.00011747678412476956,
+	},
+	8: {
+		0.03807794736387839,
+		0.24383467461259062,
+		0.6048231236901116,
+		0.6572880780513005,
+		0.13319738582500745,
+		-0.293273783279175,
+		-0.09684078322297739,
+		0.14854074933810757,
+		0.03072568147933299,
+		-0.06763282906133017,
+		0.00025094711483162414,
+		0.022361662123679068,
+		-0.004723204757751401,
+		-0.004281503682463434,
+		0.0018476468830562296,
+		0.00023038576352319635,
+		-0.00025196318894271067,
+		3.9347320316271704e-05,
+	},
+	9: {
+		0.026670057900555558,
+		0.1881768000776916,
+		0.5272011889317253,
+		0.6884590394536044,
+		0.28117234366057614,
+		-0.249846424327315,
+		-0.19594627437737688,
+		0.12736934033579375,
+		0.09305736460357092,
+		-0.0713941471663954,
+		-0.02945753682187707,
+		0.03321267405934167,
+		0.003606553566955904,
+		-0.010733175483330486,
+		0.0013953517470528694,
+		0.001992405295185068,
+		-0.0006858566949597147,
+		-0.00011646685512928507,
+		9.35886703200696e-05,
+		-1.3264202894521253e-05,
+	},
+	10: {
+		0.01869429776147109,
+		0.14406702115062453,
+		0.4498997643560454,
+		0.6856867749162006,
+		0.411964368947909,
+		-0.1622752450274946,
+		-0.27423084681794113,
+		0.06604358819667788,
+		0.14981201246638132,
+		-0.046479955116684554,
+		-0.06643878569502575,
+		0.03133509021904659,
+		0.020840904360180684,
+		-0.015364820906201311,
+		-0.003340858873014632,
+		0.004928417656059129,
+		-0.0003085928588151708,
+		-0.0008930232506662596,
+		0.0002491525235528232,
+		5.443907469936849e-05,
+		-3.463498418698502e-05,
+		4.494274277236515e-06,
+	},
+	11: {
+		0.013112257957229517,
+		0.10956627282118514,
+		0.3773551352142126,
+		0.657198722579307,
+		0.5158864784278159,
+		-0.04476388565377547,
+		-0.31617845375278253,
+		-0.023779257256076446,
+		0.1824786059275896,
+		0.0053595696743420004,
+		-0.09643212009649964,
+		0.010849130255817957,
+		0.04154627749508642,
+		-0.01221864906974895,
+		-0.012840825198300706,
+		0.00671149900879574,
+		0.002248607240995063,
+		-0.002179503618627686,
+		6.545128212489525e-06,
+		0.0003886530628209348,
+		-8.850410920820459e-05,
+		-2.4241545757030802e-05,
+		1.2776952219379772e-05,
+		-1.5290717580685116e-06,
+	},
+	12: {
+		0.009202133538962372,
+		0.0828612438729028,
+		0.3119963221604381,
+		0.6110558511587886,
+		0.5888895704312169,
+		0.0869857261796497,
+		-0.3149729077113886,
+		-0.12457673075082268,
+		0.17947607942935315,
+		0.07294893365676487,
+		-0.10580761818792822,
+		-0.026488406475344135,
+		0.05613947710028159,
+		0.002379972254060884,
+		-0.023831420710324926,
+		0.003923941448798297,
+		0.007255589401616997,
+		-0.0027619112346565578,
+		-0.0013156739118924251,
+		0.0009323261308673025,
+		4.9251525126280655e-05,
+		-0.00016512898855650408,
+		3.0678537579325334e-05,
+		1.0441930571408158e-05,
+		-4.7004164793608725e-06,
+		5.22003509845487e-07,
+	},
+	13: {
+		0.006461153460087948,
+		0.06236475884939891,
+		0.25485026779262127,
+		0.5543056179408941,
+		0.6311878491048571,
+		0.2186706877589057,
+		-0.27168855227874683,
+		-0.2180335299932802,
+		0.13839521386481943,
+		0.13998901658443483,
+		-0.08674841156813178,
+		-0.07154895550408955,
+		0.05523712625925639,
+		0.026981408307882277,
+		-0.030185351540371712,
+		-0.005615049530366435,
+		0.012789493266337226,
+		-0.0007462189892695761,
+		-0.0038496388680219363,
+		0.0010616910856067483,
+		0.0007080211542355143,
+		-0.00038683194731294854,
+		-4.1777245770373965e-05,
+		6.87550425269753e-05,
+		-1.0337209184570796e-05,
+		-4.389704901781396e-06,
+		1.7249946753678137e-06,
+		-1.7871399683113605e-07,
+	},
+	14: {
+		0.0045385373615789044,
+		0.0467433948927663,
+		0.20602386398699607,
+		0.49263177170814,
+		0.6458131403574243,
+		0.3390025354547353,
+		-0.19320413960915966,
+		-0.28888259656693227,
+		0.0652829528487143,
+		0.19014671400719707,
+		-0.03966617655585849,
+		-0.11112093603718884,
+		0.033877143923491206,
+		0.05478055058450857,
+		-0.02576700732843673,
+		-0.020810050169695178,
+		0.015083918027836409,
+		0.005101000360407556,
+		-0.006487734560315613,
+		-0.00024175649076190874,
+		0.0019433239803824571,
+		-0.0003734823541377455,
+		-0.0003595652443624238,
+		0.00015589648992058897,
+		2.5792699155320654e-05,
+		-2.813329626604797e-05,
+		3.3629871817375766e-06,
+		1.8112704079405835e-06,
+		-6.316882325881679e-07,
+		6.133359913305765e-08,
+	},
+	15: {
+		0.0031892209253477446,
+		0.034907714323673414,
+		0.1650642834888534,
+		0.4303127228460048,
+		0.6373563320837891,
+		0.44029025688635826,
+		-0.08975108940249107,
+		-0.3270633105279209,
+		-0.027918208133033758,
+		0.21119069394713866,
+		0.027340263752648062,
+		-0.13238830556372763,
+		-0.00623972275254711,
+		0.07592423604432258,
+		-0.0075889743688748726,
+		-0.036888397691736644,
+		0.010297659640975699,
+		0.013993768859806592,
+		-0.006990014563396467,
+		-0.003644279621509081,
+		0.0031280233812115282,
+		0.00040789698084760207,
+		-0.0009410217493588661,
+		0.00011424152003852531,
+		0.00017478724522538585,
+		-6.103596621411913e-05,
+		-1.3945668988207336e-05,
+		1.1336608661276095e-05,
+		-1.0435713423115955e-06,
+		-7.363656785451233e-07,
+		2.3087840868575534e-07,
+		-2.1093396301007514e-08,
+	},
+	16: {
+		0.002241807001037308,
+		0.02598539370360599,
+		0.1312149033078242,
+		0.37035072415264075,
+		0.61099661568462,
+		0.5183157640569457,
+		0.027314970403282336,
+		-0.3283207483639437,
+		-0.1265997522159226,
+		0.19731058956510333,
+		0.10113548917732249,
+		-0.12681569177812033,
+		-0.05709141963181411,
+		0.0811059866542403,
+		0.0223123361780866,
+		-0.046922438389298736,
+		-0.0032709555357707623,
+		0.02273367658390152,
+		-0.003042989981324368,
+		-0.008602921520339233,
+		0.002967996691533578,
+		0.00230120524215055,
+		-0.001436845304801917,
+		-0.00032813251941016133,
+		0.0004394654277687268,
+		-2.5610109566566978e-05,
+		-8.204803202452993e-05,
+		2.3186813798745126e-05,
+		6.9906009850768624e-06,
+		-4.505942477222988e-06,
+		3.016549609994555e-07,
+		2.957700933316843e-07,
+		-8.423948446002637e-08,
+		7.267492968561567e-09,
+	},
+	17: {
+		0.0015763102184407649,
+		0.01928853172414644,
+		0.1035884658224238,
+		0.3146789413370323,
+		0.5718268077666087,
+		0.5718016548886503,
+		0.1472231119699294,
+		-0.2936540407365742,
+		-0.2164809340050835,
+		0.14953397556522582,
+		0.1670813127635222,
+		-0.09233188415118604,
+		-0.10675224665947683,
+		0.06488721621158482,
+		0.057051247738807875,
+		-0.044526141903187946,
+		-0.023733210395733863,
+		0.02667070592641846,
+		0.006262167954311648,
+		-0.0130514809466029,
+		0.00011863003385146224,
+		0.004943343605467817,
+		-0.0011187326669909049,
+		-0.001340596298337772,
+		0.0006284656829660835,
+		0.00021358156190996527,
+		-0.00019864855231163124,
+		-1.5359171238346204e-07,
+		3.741237880740708e-05,
+		-8.520602537447956e-06,
+		-3.332634478885663e-06,
+		1.768712983627612e-06,
+		-7.691632689885267e-08,
+		-1.176098767028239e-07,
+		3.0688358630452014e-08,
+		-2.5079344549486244e-09,
+	},
+	18: {
+		0.0011086697631817125,
+		0.014281098450764421,
+		0.08127811326545963,
+		0.26438843174089766,
+		0.5244363774646554,
+		0.6017045491275361,
+		0.2608949526510424,
+		-0.22809139421547833,
+		-0.2858386317558447,
+		0.07465226970809992,
+		0.2123497433063575,
+		-0.0335185419024781,
+		-0.14278569503847785,
+		0.02758435062529166,
+		0.08690675555622439,
+		-0.026501236250557416,
+		-0.04567422627688398,
+		0.021623767409418397,
+		0.019375549889160737,
+		-0.013988388678418825,
+		-0.0058669222811366445,
+		0.007040747367188911,
+		0.0007689543592172819,
+		-0.0026875518006875547,
+		0.0003418086534553386,
+		0.0007358025205057212,
+		-0.0002606761356784896,
+		-0.00012460079173423836,
+		8.711270467222175e-05,
+		5.105950487070308e-06,
+		-1.6640176297154958e-05,
+		3.0109643162967082e-06,
+		1.5319314766911396e-06,
+		-6.862755657769054e-07,
+		1.4470882987977383e-08,
+		4.6369377757826165e-08,
+		-1.1164020670358269e-08,
+		8.666848838997622e-10,
+	},
+	19: {
+		0.000779953613666846,
+		0.010549394624950394,
+		0.0634237804590815,
+		0.21994211355139706,
+		0.47269618531090174,
+		0.6104932389385905,
+		0.3615022987393432,
+		-0.1392120880115068,
+		-0.3267868004340055,
+		-0.016727088309107033,
+		0.22829105081995169,
+		0.03985024645773715,
+		-0.15545875070729953,
+		-0.024716827338413214,
+		0.1022917191740301,
+		0.005632246857843499,
+		-0.0617228996251658,
+		0.005874681812104432,
+		0.03229429953070061,
+		-0.008789324923987759,
+		-0.013810526137010615,
+		0.006721627302135078,
+		0.004420542387126877,
+		-0.003581494259652046,
+		-0.0008315621728041909,
+		0.0013925596193164822,
+		-5.349759843794714e-05,
+		-0.00038510474869975274,
+		0.00010153288973683753,
+		6.774280828373869e-05,
+		-3.710586183393403e-05,
+		-4.37614386218837e-06,
+		7.2412482876748554e-06,
+		-1.0119940100191632e-06,
+		-6.847079597000086e-07,
+		2.633924226269947e-07,
+		2.0143220235533793e-10,
+		-1.814843248299694e-08,
+		4.0561270555518295e-09,
+		-2.998836489619319e-10,
+	},
+}
+
+// symlets holds the scaling filters of the symlets with 1 to 20 vanishing moments.
+var symlets = [...][]float64{
+	0: {
+		0.7071067811865476,
+		0.7071067811865476,
+	},
+	1: {
+		0.48296291314453416,
+		0.8365163037378078,
+		0.2241438680420134,
+		-0.12940952255126037,
+	},
+	2: {
+		0.3326705529500826,
+		0.8068915093110924,
+		0.4598775021184916,
+		-0.13501102001025458,
+		-0.08544127388202666,
+		0.035226291885709526,
+	},
+	3: {
+		0.032223100604051466,
+		-0.012603967262031314,
+		-0.09921954357663351,
+		0.2978577956053061,
+		0.8037387518051322,
+		0.497618667632775,
+		-0.029635527646002482,
+		-0.07576571478950223,
+	},
+	4: {
+		0.027333068344998757,
+		0.029519490925706236,
+		-0.03913424930231378,
+		0.19939753397685564,
+		0.7234076904040406,
+		0.6339789634567922,
+		0.016602105764510825,
+		-0.17532808990805615,
+		-0.02110183402468904,
+		0.01953888273524982,
+	},
+	5: {
+		0.015404109327044833,
+		0.003490712084222166,
+		-0.11799011114852002,
+		-0.048311742585698154,
+		0.49105594192797375,
+		0.7876411410286513,
+		0.3379294217281657,
+		-0.07263752278637649,
+		-0.021060292512370862,
+		0.044724901770781436,
+		0.001767711864254006,
+		-0.007800708325032386,
+	},
+	6: {
+		0.010268176708464815,
+		0.004010244871522389,
+		-0.1078082377032897,
+		-0.14004724044293368,
+		0.2886296317506479,
+		0.7677643170048835,
+		0.5361019170905686,
+		0.01744125508683598,
+		-0.0495528349370429,
+		0.06789269350122064,
+		0.03051551316587785,
+		-0.012636303403240555,
+		-0.0010473848886797383,
+		0.002681814568260147,
+	},
+	7: {
+		-0.0033824159510050036,
+		-0.0005421323318000116,
+		0.03169508781152599,
+		0.007607487324976671,
+		-0.14329423835127283,
+		-0.06127335906781099,
+		0.48135965125905394,
+		0.7771857516996276,
+		0.3644418948361793,
+		-0.051945838107882156,
+		-0.02721902991710342,
+		0.04913717967373031,
+		0.0038087520138944597,
+		-0.014952258337062188,
+		-0.00030292051472413433,
+		0.00188995033276769,
+	},
+	8: {
+		0.0014009155259146584,
+		0.0006197808889855089,
+		-0.013271967781817143,
+		-0.011528210207679222,
+		0.03022487885827521,
+		0.0005834627461250159,
+		-0.05456895843083319,
+		0.23876091460730406,
+		0.7178970827644138,
+		0.6173384491409339,
+		0.03527248803527068,
+		-0.19155083129728437,
+		-0.01823377077939535,
+		0.06207778930288569,
+		0.00885926749340032,
+		-0.010264064027633142,
+		-0.0004731544986800413,
+		0.0010694900329086129,
+	},
+	9: {
+		-0.00041011591580439864,
+		0.0003401492663148095,
+		0.005071649198531805,
+		-0.0011404297952173261,
+		-0.02300546135349752,
+		-0.000868752109689297,
+		0.03384235466357505,
+		-0.06708990780838085,
+		-0.08787871151197613,
+		0.3402160130234606,
+		0.7669548365606146,
+		0.5137098733480213,
+		-0.01501923883913513,
+		-0.12155210554854945,
+		0.026240365058448657,
+		0.04968612664694314,
+		0.0005956827837424547,
+		-0.007056764062587301,
+		0.00071542054205434,
+		0.0008625782262259725,
+	},
+	10: {
+		0.00014750986910037453,
+		-0.0001285193778706455,
+		-0.00159484408501435,
+		0.0018159127773339034,
+		0.008864269595098721,
+		-0.008084071064789092,
+		-0.023798758058574402,
+		0.0332111330022658,
+		0.04688147383549936,
+		-0.08483533635977718,
+		0.00997082898663676,
+		0.519053394140989,
+		0.7619116588766467,
+		0.3334375981441146,
+		-0.11660996765137448,
+		-0.11057583868964328,
+		0.028260865505261003,
+		0.029272544116471268,
+		-0.007422499594650306,
+		-0.00662960621719275,
+		0.000496243907920719,
+		0.0005695707146428915,
+	},
+	11: {
+		9.767610247723161e-05,
+		-8.418262000974782e-05,
+		-0.0013865502623702437,
+		0.0006610376737514613,
+		0.008634230791720541,
+		-0.0005948327807241176,
+		-0.02549302508934048,
+		0.0018619254598854094,
+		0.030686743515093668,
+		-0.08017578174217728,
+		-0.0892710009683524,
+		0.3434515016095055,
+		0.7608721850415973,
+		0.5166743899411665,
+		-0.007517992473063436,
+		-0.12359121292130207,
+		0.03125685988359436,
+		0.06005859623424376,
+		-0.0012870333171526587,
+		-0.013053840998593656,
+		0.0006915974586788365,
+		0.0021044473356296705,
+		-0.000176909496291933,
+		-0.0002052660048713794,
+	},
+	12: {
+		7.042986690696275e-05,
+		3.69053734232388e-05,
+		-0.0007213643851363758,
+		0.0004132611988416792,
+		0.005674853760123321,
+		-0.0014924472742585866,
+		-0.02074968632552125,
+		0.01761829688064644,
+		0.0929260308991422,
+		0.008819757670429985,
+		-0.14049009311367153,
+		0.11023022302127967,
+		0.6445643839011612,
+		0.6957391505615744,
+		0.19770481877125412,
+		-0.12436246075149246,
+		-0.059750627717964314,
+		0.013862497435843269,
+		-0.017211642726307045,
+		-0.020216768133394313,
+		0.005296359738721529,
+		0.0075262253899682306,
+		-0.0001709428585295761,
+		-0.001136063438927971,
+		-3.573862364871565e-05,
+		6.820325263074362e-05,
+	},
+	13: {
+		1.322197383114466e-05,
+		-1.7388210806180973e-05,
+		-0.00018970382468382085,
+		0.00022237462450026507,
+		0.001169220685731637,
+		-0.0014315233348946674,
+		-0.0037572372210134706,
+		0.007574364577134086,
+		0.008535152539741372,
+		-0.02936001787972382,
+		-0.024518096603046713,
+		0.05561965649060496,
+		0.026592809784019376,
+		-0.06514590677596385,
+		0.19211295803097225,
+		0.6739094201280608,
+		0.6574517983202561,
+		0.11689920264201403,
+		-0.20356771278965707,
+		-0.08075042572082658,
+		0.062360557885291226,
+		0.03672842653612849,
+		-0.010304879597910288,
+		-0.008196622728983352,
+		0.0013235419857191195,
+		0.0011425526229958708,
+		-0.00011484998267434794,
+		-8.7331783721408e-05,
+	},
+	14: {
+		3.2124828539898974e-05,
+		3.177370822243839e-05,
+		-0.00047260129824786836,
+		-0.00048393606704104104,
+		0.0031610409865699333,
+		0.00294718507867936,
+		-0.014588212746530174,
+		-0.014053890458935426,
+		0.045317499414660886,
+		0.04423600333744359,
+		-0.11637150074366535,
+		-0.11663250934103986,
+		0.3387410125952516,
+		0.7530921587669012,
+		0.5227135306167734,
+		0.022653078989382253,
+		-0.09682172965433201,
+		0.03254877185776422,
+		0.03465549864364634,
+		-0.027445536398262,
+		-0.01270855785150767,
+		0.013765680000031132,
+		0.004257569056153134,
+		-0.004419866917506225,
+		-0.0009372581790847843,
+		0.000993152844145909,
+		0.00013693590905008082,
+		-0.0001339493124089283,
+		-8.570390917598713e-06,
+		8.66509935888862e-06,
+	},
+	15: {
+		2.2648150768806298e-05,
+		4.3356325884892825e-05,
+		-0.00026336804634833364,
+		-0.00033033358422634643,
+		0.0024146001932550893,
+		0.0032623634588647035,
+		-0.01056103454520891,
+		-0.013718107029052386,
+		0.04510159066819727,
+		0.08155442444376566,
+		-0.05937149174244077,
+		-0.17254486173595948,
+		0.15348315530037923,
+		0.6725108131278075,
+		0.6630054840660347,
+		0.1691603798587393,
+		-0.09844272949968823,
+		-0.006857925207700099,
+		0.037333792861576155,
+		-0.03321461403321801,
+		-0.03468970127745071,
+		0.009957043039608768,
+		0.010922734448352367,
+		-0.004101076616594854,
+		-0.00225840393941498,
+		0.001754525331065776,
+		0.00048248571849448504,
+		-0.0004192393193178032,
+		-7.86673171998806e-05,
+		5.30034136684485e-05,
+		5.686147146692291e-06,
+		-2.970286693891144e-06,
+	},
+	16: {
+		4.133108370020838e-06,
+		-9.773939076850705e-07,
+		-7.938180323175425e-05,
+		-2.501640165206018e-06,
+		0.0006925449091538075,
+		0.000189904104962746,
+		-0.003567715946791798,
+		-0.001799495807778386,
+		0.011428170160635326,
+		0.007672938573166852,
+		-0.02132675165165293,
+		-0.01010343491748341,
+		0.020286911677353203,
+		-0.06347699011733125,
+		-0.11090901863114074,
+		0.22728804217763712,
+		0.7001541383594322,
+		0.6286046824800359,
+		0.09819125292336982,
+		-0.15517447203138993,
+		0.0007353918382703898,
+		0.10047277994678017,
+		0.015678538598940212,
+		-0.03540361570771467,
+		-0.005310604136500546,
+		0.011102075333166133,
+		0.0012720489310462507,
+		-0.0027120467435134536,
+		-0.00014930827601422234,
+		0.0005084963877322551,
+		5.4989475586362335e-06,
+		-6.254536202275203e-05,
+		9.321780744208972e-07,
+		3.941904048557182e-06,
+	},
+	17: {
+		-1.3829534251189254e-06,
+		1.463787382646715e-06,
+		2.9318049613097426e-05,
+		-1.7321825660854993e-05,
+		-0.0002762918195158516,
+		6.952313804425217e-05,
+		0.0014833808230491307,
+		-0.00020873940922720894,
+		-0.005312536778509503,
+		0.0013397324968287144,
+		0.015667149664463104,
+		-0.0031184530131994144,
+		-0.03340308512788228,
+		0.0006090566460299338,
+		0.013467696269012328,
+		-0.10423935777204112,
+		-0.07385143649527813,
+		0.36909896290329997,
+		0.7512965133174729,
+		0.5030014601182555,
+		-0.011897841226488704,
+		-0.13026743264603638,
+		0.048029047295040206,
+		0.0902040769049707,
+		-0.0007790523685669054,
+		-0.027251004973992785,
+		0.002808875158354078,
+		0.009666322454580031,
+		-0.00028084691703010524,
+		-0.002139004006730927,
+		0.00014069395425105074,
+		0.0003969485072358124,
+		-1.6445321823548824e-05,
+		-4.231070362557968e-05,
+		3.0256644581594155e-06,
+		2.8585797878010468e-06,
+	},
+	18: {
+		1.7173942669481289e-06,
+		-3.744286300570042e-07,
+		-4.298556421930787e-05,
+		-2.074001616155093e-05,
+		0.0004628861505931086,
+		0.0005382097714225653,
+		-0.002506495795481229,
+		-0.004813514368738736,
+		0.0054542938773012905,
+		0.018572965173188506,
+		0.00470180949201216,
+		-0.015641741834278137,
+		-0.009830093708986637,
+		-0.06373971062773345,
+		-0.19029222243979166,
+		-0.07563854630623779,
+		0.42026089975009395,
+		0.7432423359554857,
+		0.4355666801403704,
+		-0.02466887555652628,
+		-0.04757357996415859,
+		0.13561833770526557,
+		0.10733247139637765,
+		-0.020605395492115153,
+		-0.027668911244633096,
+		0.017284918038078977,
+		0.013584702582250188,
+		-0.004438342272906486,
+		-0.0029912501006839833,
+		0.0017215176636736823,
+		0.0007229645517520171,
+		-0.00037062074283577217,
+		-8.432483194903207e-05,
+		7.3652742882484e-05,
+		8.097519217758823e-06,
+		-7.853707547183064e-06,
+		1.2198107501944877e-07,
+		5.594914012923855e-07,
+	},
+	19: {
+		-2.2886779515096898e-06,
+		-5.487562784334553e-06,
+		3.618317060826681e-05,
+		8.381612805856794e-05,
+		-0.000334581892552859,
+		-0.0008021025470738661,
+		0.0019178331324007007,
+		0.0053401776895990365,
+		-0.006762377448599558,
+		-0.02516698400740636,
+		0.01341683701575392,
+		0.093387679375348,
+		0.02895391034714144,
+		-0.19495688321752921,
+		-0.16289421300696372,
+		0.3356800785911747,
+		0.7329387634751898,
+		0.5098420100771175,
+		0.07021496114746878,
+		-0.021825149994742485,
+		0.07128565763749184,
+		0.022512526604577428,
+		-0.0562344809622386,
+		-0.020637625477235935,
+		0.02163061556668279,
+		0.0036540264141934928,
+		-0.010185966226040703,
+		0.0002562734242069381,
+		0.004064310911634348,
+		-0.00044372210402211695,
+		-0.0011513719564304312,
+		0.0002612673408616579,
+		0.0002505450513289501,
+		-8.893527883128079e-05,
+		-4.1970121565911776e-05,
+		1.771142874755489e-05,
+		4.659060726649782e-06,
+		-1.9978949351099156e-06,
+		-2.450370141683701e-07,
+		1.021967007342267e-07,
+	},
+}
+
+// coiflets holds the scaling filters of the coiflets of orders 1 to 5.
+var coiflets = [...][]float64{
+	0: {
+		-0.07273261951252645,
+		0.3378976624574816,
+		0.8525720202116004,
+		0.38486484686485795,
+		-0.07273261951252645,
+		-0.015655728135792114,
+	},
+	1: {
+		0.016387336463203676,
+		-0.04146493678687182,
+		-0.06737255472372569,
+		0.38611006682276305,
+		0.8127236354494135,
+		0.4170051844232387,
+		-0.0764885990782807,
+		-0.05943441864643087,
+		0.02368017194684768,
+		0.005611434819368775,
+		-0.0018232088709110009,
+		-0.0007205494455203444,
+	},
+	2: {
+		-0.0037935128643588065,
+		0.007782596425636066,
+		0.023452696141966503,
+		-0.0657719112812511,
+		-0.06112339000276706,
+		0.4051769024085829,
+		0.7937772226259538,
+		0.4284834763780626,
+		-0.07179982161922932,
+		-0.08230192710679557,
+		0.03455502757346638,
+		0.01588054486385332,
+		-0.009007976136827632,
+		-0.002574517688164286,
+		0.0011175187708500813,
+		0.0004662169598219157,
+		-7.098330250626961e-05,
+		-3.459977319816851e-05,
+	},
+	3: {
+		0.0008923139006768397,
+		-0.0016294924220963732,
+		-0.0073461679230831375,
+		0.016068947106858352,
+		0.02668230463107039,
+		-0.08126671016408503,
+		-0.05607731954677898,
+		0.41530842683394453,
+		0.7822389343888686,
+		0.43438603331732994,
+		-0.06662747238154854,
+		-0.09622042469292846,
+		0.039334422649600104,
+		0.02508225341339181,
+		-0.015211728222217833,
+		-0.005658283821325773,
+		0.0037514347106251964,
+		0.001266561082411457,
+		-0.0005890202272878879,
+		-0.0002599743378109693,
+		6.233885459987437e-05,
+		3.122986178556095e-05,
+		-3.2596479770059614e-06,
+		-1.784990927419158e-06,
+	},
+	4: {
+		-0.00021208187530279458,
+		0.0003585777635788325,
+		0.002178294498193231,
+		-0.00415931284882556,
+		-0.010131585322526926,
+		0.023408323099250086,
+		0.028169745319200867,
+		-0.09192159062640924,
+		-0.05204667159375604,
+		0.42157127112265125,
+		0.7742936236723306,
+		0.4379823015300085,
+		-0.062037751310584865,
+		-0.1055631471700356,
+		0.041287529491908,
+		0.03267479718633633,
+		-0.019758390685200244,
+		-0.00915950650268714,
+		0.006761519743761614,
+		0.0024315752401675647,
+		-0.0016616271520720801,
+		-0.0006375588856806883,
+		0.0003018579103254048,
+		0.00014035631720314424,
+		-4.1219856806660106e-05,
+		-2.1270218995896915e-05,
+		3.700726994846783e-06,
+		2.061220061768559e-06,
+		-1.6237991746075987e-07,
+		-9.604007588831675e-08,
+	},
+}
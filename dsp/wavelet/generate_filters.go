@@ -0,0 +1,410 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+// +build ignore
+
+// generate_filters computes the scaling filters of the Daubechies, symlet
+// and coiflet wavelets and writes them to filters.go.
+//
+// The Daubechies and symlet filters are obtained by spectral factorization
+// of the Daubechies polynomial, choosing the roots inside the unit circle
+// for the minimum phase Daubechies filters and the combination of roots
+// with the least phase nonlinearity for the symlets. The coiflet filters
+// are the solutions of the coiflet conditions found by the Gauss-Newton
+// method.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"math"
+	"math/cmplx"
+	"os"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	maxDaubechies = 20
+	maxSymlet     = 20
+	maxCoiflet    = 5
+)
+
+func main() {
+	var buf bytes.Buffer
+	buf.WriteString(`// Code generated by "go generate gonum.org/v1/gonum/dsp/wavelet"; DO NOT EDIT.
+
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wavelet
+
+`)
+	writeTable(&buf, "daubechies", "the Daubechies wavelets with 1 to 20 vanishing moments", 1, maxDaubechies, func(n int) []float64 {
+		return checkOrthogonality(spectral(n, minimumPhase))
+	})
+	writeTable(&buf, "symlets", "the symlets with 1 to 20 vanishing moments", 1, maxSymlet, func(n int) []float64 {
+		return checkOrthogonality(spectral(n, leastAsymmetric))
+	})
+	var prev []float64
+	writeTable(&buf, "coiflets", "the coiflets of orders 1 to 5", 1, maxCoiflet, func(k int) []float64 {
+		prev = coiflet(k, prev)
+		return prev
+	})
+
+	b, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = os.WriteFile("filters.go", b, 0o664)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeTable(buf *bytes.Buffer, name, desc string, lo, hi int, filter func(int) []float64) {
+	fmt.Fprintf(buf, "// %s holds the scaling filters of %s.\n", name, desc)
+	fmt.Fprintf(buf, "var %s = [...][]float64{\n", name)
+	for n := lo; n <= hi; n++ {
+		h := filter(n)
+		fmt.Fprintf(buf, "\t%d: {\n", n-lo)
+		for _, v := range h {
+			fmt.Fprintf(buf, "\t\t%v,\n", v)
+		}
+		buf.WriteString("\t},\n")
+	}
+	buf.WriteString("}\n\n")
+}
+
+func binomial(n, k int) float64 {
+	r := 1.0
+	for i := 1; i <= k; i++ {
+		r = r * float64(n-k+i) / float64(i)
+	}
+	return r
+}
+
+// roots returns the roots of the polynomial with coefficients c in order
+// of increasing degree.
+func roots(c []float64) []complex128 {
+	n := len(c) - 1
+	if n == 0 {
+		return nil
+	}
+	a := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		a.Set(0, i, -c[n-1-i]/c[n])
+		if i > 0 {
+			a.Set(i, i-1, 1)
+		}
+	}
+	var eig mat.Eigen
+	if !eig.Factorize(a, mat.EigenNone) {
+		log.Fatal("eigendecomposition failed")
+	}
+	r := eig.Values(nil)
+	for i := range r {
+		for iter := 0; iter < 10; iter++ {
+			var p, dp complex128
+			for k := n; k >= 0; k-- {
+				dp = dp*r[i] + p
+				p = p*r[i] + complex(c[k], 0)
+			}
+			if dp == 0 {
+				break
+			}
+			r[i] -= p / dp
+		}
+	}
+	return r
+}
+
+// rootGroups returns the roots inside the unit circle of the spectral
+// factors of the Daubechies polynomial for n vanishing moments, grouped
+// into real roots and complex conjugate pairs.
+func rootGroups(n int) [][]complex128 {
+	c := make([]float64, n)
+	for k := range c {
+		c[k] = binomial(n-1+k, k)
+	}
+	// Each root y of the polynomial in y = (2-z-1/z)/4 gives a
+	// reciprocal pair of roots in z.
+	var zs []complex128
+	for _, y := range roots(c) {
+		b := 1 - 2*y
+		s := cmplx.Sqrt(b*b - 1)
+		big := b + s
+		if cmplx.Abs(b-s) > cmplx.Abs(big) {
+			big = b - s
+		}
+		zs = append(zs, 1/big)
+	}
+	sort.Slice(zs, func(i, j int) bool {
+		if real(zs[i]) != real(zs[j]) {
+			return real(zs[i]) < real(zs[j])
+		}
+		return imag(zs[i]) < imag(zs[j])
+	})
+	var groups [][]complex128
+	used := make([]bool, len(zs))
+	for i, z := range zs {
+		if used[i] {
+			continue
+		}
+		used[i] = true
+		if math.Abs(imag(z)) < 1e-10 {
+			groups = append(groups, []complex128{complex(real(z), 0)})
+			continue
+		}
+		for j := i + 1; j < len(zs); j++ {
+			if !used[j] && cmplx.Abs(zs[j]-cmplx.Conj(z)) < 1e-6 {
+				used[j] = true
+				break
+			}
+		}
+		groups = append(groups, []complex128{z, cmplx.Conj(z)})
+	}
+	return groups
+}
+
+// filter returns the scaling filter with n zeros at z = -1 and the given
+// other zeros, normalized to sum to sqrt(2).
+func filter(n int, zs []complex128) []float64 {
+	p := []complex128{1}
+	for i := 0; i < n; i++ {
+		p = polyMul(p, []complex128{1, 1})
+	}
+	for _, z := range zs {
+		p = polyMul(p, []complex128{1, -z})
+	}
+	h := make([]float64, len(p))
+	var sum float64
+	for i, v := range p {
+		h[i] = real(v)
+		sum += h[i]
+	}
+	for i := range h {
+		h[i] *= math.Sqrt2 / sum
+	}
+	return h
+}
+
+func polyMul(a, b []complex128) []complex128 {
+	c := make([]complex128, len(a)+len(b)-1)
+	for i, x := range a {
+		for j, y := range b {
+			c[i+j] += x * y
+		}
+	}
+	return c
+}
+
+func spectral(n int, sel func(n int, groups [][]complex128) []float64) []float64 {
+	return sel(n, rootGroups(n))
+}
+
+func selectRoots(groups [][]complex128, mask int) []complex128 {
+	var zs []complex128
+	for g, grp := range groups {
+		for _, z := range grp {
+			if mask&(1<<g) != 0 {
+				zs = append(zs, 1/z)
+			} else {
+				zs = append(zs, z)
+			}
+		}
+	}
+	return zs
+}
+
+func minimumPhase(n int, groups [][]complex128) []float64 {
+	return filter(n, selectRoots(groups, 0))
+}
+
+func leastAsymmetric(n int, groups [][]complex128) []float64 {
+	best := math.Inf(1)
+	var h []float64
+	for mask := 0; mask < 1<<len(groups); mask++ {
+		f := filter(n, selectRoots(groups, mask))
+		if e := phaseNonlinearity(f); e < best-1e-9 {
+			best = e
+			h = f
+		}
+	}
+	return h
+}
+
+// phaseNonlinearity returns the squared deviation of the unwrapped phase
+// of the frequency response of h from its best linear fit.
+func phaseNonlinearity(h []float64) float64 {
+	const m = 200
+	phase := make([]float64, m)
+	var off float64
+	for i := range phase {
+		w := math.Pi * float64(i) / m
+		var s complex128
+		for n, v := range h {
+			s += complex(v, 0) * cmplx.Rect(1, -w*float64(n))
+		}
+		a := cmplx.Phase(s)
+		if i > 0 {
+			for a+off-phase[i-1] > math.Pi {
+				off -= 2 * math.Pi
+			}
+			for a+off-phase[i-1] < -math.Pi {
+				off += 2 * math.Pi
+			}
+		}
+		phase[i] = a + off
+	}
+	var sxx, sxy float64
+	for i, p := range phase {
+		w := math.Pi * float64(i) / m
+		sxx += w * w
+		sxy += w * p
+	}
+	k := sxy / sxx
+	var e float64
+	for i, p := range phase {
+		d := p - k*math.Pi*float64(i)/m
+		e += d * d
+	}
+	return e
+}
+
+// orthogonality appends the residuals of the double-shift orthonormality
+// conditions of h to r.
+func orthogonality(r, h []float64) []float64 {
+	for k := 0; 2*k < len(h); k++ {
+		var s float64
+		for n := 0; n+2*k < len(h); n++ {
+			s += h[n] * h[n+2*k]
+		}
+		if k == 0 {
+			s--
+		}
+		r = append(r, s)
+	}
+	return r
+}
+
+// waveletMoments appends the residuals of the conditions for n vanishing
+// moments of the wavelet of h about c to r.
+func waveletMoments(r, h []float64, n int, c float64) []float64 {
+	for m := 0; m < n; m++ {
+		var s float64
+		for i, v := range h {
+			sign := 1.0
+			if i%2 == 1 {
+				sign = -1
+			}
+			s += sign * math.Pow((float64(i)-c)/float64(len(h)), float64(m)) * v
+		}
+		r = append(r, s)
+	}
+	return r
+}
+
+// checkOrthogonality terminates the generator if the filter h does not
+// satisfy the orthonormality conditions to near machine precision.
+func checkOrthogonality(h []float64) []float64 {
+	var norm float64
+	for _, v := range orthogonality(nil, h) {
+		norm = math.Max(norm, math.Abs(v))
+	}
+	if norm > 1e-13 {
+		log.Fatalf("filter of length %d is not orthonormal: residual %v", len(h), norm)
+	}
+	return h
+}
+
+func coifletResidual(k int, h []float64) []float64 {
+	c := float64(2 * k)
+	r := orthogonality(nil, h)
+	r = waveletMoments(r, h, 2*k, c)
+	var sum float64
+	for _, v := range h {
+		sum += v
+	}
+	r = append(r, sum-math.Sqrt2)
+	for m := 1; m < 2*k; m++ {
+		var s float64
+		for i, v := range h {
+			s += math.Pow((float64(i)-c)/float64(len(h)), float64(m)) * v
+		}
+		r = append(r, s)
+	}
+	return r
+}
+
+// coiflet returns the scaling filter of the coiflet of order k, starting
+// from the filter of order k-1, or from a windowed half-band filter if
+// prev is nil.
+func coiflet(k int, prev []float64) []float64 {
+	h := make([]float64, 6*k)
+	if prev != nil {
+		copy(h[2:], prev)
+	} else {
+		c := 2 * k
+		for i := range h {
+			x := float64(i - c)
+			s := 1.0
+			if x != 0 {
+				s = math.Sin(math.Pi*x/2) / (math.Pi * x / 2)
+			}
+			if math.Abs(x) <= float64(c+1) {
+				h[i] = s * (0.5 + 0.5*math.Cos(math.Pi*x/float64(c+1))) / math.Sqrt2
+			}
+		}
+	}
+	return polish(h, func(h []float64) []float64 { return coifletResidual(k, h) })
+}
+
+// polish refines h by the Gauss-Newton method to minimize the norm of
+// the residuals returned by res.
+func polish(h []float64, res func([]float64) []float64) []float64 {
+	h = append([]float64(nil), h...)
+	for iter := 0; iter < 100; iter++ {
+		r := res(h)
+		jac := mat.NewDense(len(r), len(h), nil)
+		for j := range h {
+			const d = 1e-7
+			hp := append([]float64(nil), h...)
+			hp[j] += d
+			hm := append([]float64(nil), h...)
+			hm[j] -= d
+			rp, rm := res(hp), res(hm)
+			for i := range r {
+				jac.Set(i, j, (rp[i]-rm[i])/(2*d))
+			}
+		}
+		var svd mat.SVD
+		if !svd.Factorize(jac, mat.SVDThin) {
+			log.Fatal("SVD failed")
+		}
+		var dx mat.VecDense
+		svd.SolveVecTo(&dx, mat.NewVecDense(len(r), r), svd.Rank(1e-12))
+		var step float64
+		for j := range h {
+			h[j] -= dx.AtVec(j)
+			step = math.Max(step, math.Abs(dx.AtVec(j)))
+		}
+		if step < 1e-15 {
+			break
+		}
+	}
+	var norm float64
+	for _, v := range res(h) {
+		norm = math.Max(norm, math.Abs(v))
+	}
+	if norm > 1e-12 {
+		log.Fatalf("filter of length %d did not converge: residual %v", len(h), norm)
+	}
+	return h
+}
@@ -0,0 +1,98 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wavelet
+
+// Stationary is a multilevel stationary wavelet decomposition of a
+// sequence, also known as the undecimated or à trous wavelet transform.
+// The sequence is treated as periodic, and the coefficients at every level
+// have the same length as the sequence. Unlike the discrete wavelet
+// transform, the stationary wavelet transform of a circularly shifted
+// sequence is the circularly shifted transform of the sequence.
+//
+// The coefficients may be modified before reconstruction, for example by
+// thresholding the detail coefficients.
+type Stationary struct {
+	// Wavelet is the wavelet of the decomposition.
+	Wavelet Wavelet
+
+	// Approx holds the approximation coefficients at each level,
+	// with Approx[0] holding the finest level.
+	Approx [][]float64
+	// Detail holds the detail coefficients at each level,
+	// with Detail[0] holding the finest level.
+	Detail [][]float64
+}
+
+// SWT returns the stationary wavelet decomposition of x with the wavelet w
+// and the given number of levels. At level j, the filters of w are
+// upsampled by 2^(j-1) instead of decimating the coefficients. SWT will
+// panic if level is less than one or the length of x is zero.
+func SWT(x []float64, w Wavelet, level int) *Stationary {
+	if level < 1 {
+		panic("wavelet: level less than one")
+	}
+	if len(x) == 0 {
+		panic("wavelet: empty sequence")
+	}
+	n := len(x)
+	l := w.Len()
+	s := &Stationary{
+		Wavelet: w,
+		Approx:  make([][]float64, level),
+		Detail:  make([][]float64, level),
+	}
+	for j, step := 0, 1; j < level; j, step = j+1, 2*step {
+		a := make([]float64, n)
+		d := make([]float64, n)
+		for i := range a {
+			var sa, sd float64
+			for k := 0; k < l; k++ {
+				v := x[mod(i+step*(l/2-k), n)]
+				sa += w.DecLo[k] * v
+				sd += w.DecHi[k] * v
+			}
+			a[i] = sa
+			d[i] = sd
+		}
+		s.Approx[j] = a
+		s.Detail[j] = d
+		x = a
+	}
+	return s
+}
+
+// Levels returns the number of levels of the decomposition.
+func (s *Stationary) Levels() int { return len(s.Detail) }
+
+// Reconstruct places the sequence reconstructed from the approximation
+// coefficients at the coarsest level and the detail coefficients at all
+// levels into dst and returns it.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of the decomposed
+// sequence, Reconstruct will panic.
+func (s *Stationary) Reconstruct(dst []float64) []float64 {
+	level := len(s.Detail)
+	n := len(s.Approx[level-1])
+	dst = useSlice(dst, n)
+	l := s.Wavelet.Len()
+	a := append([]float64(nil), s.Approx[level-1]...)
+	buf := make([]float64, n)
+	for j := level - 1; j >= 0; j-- {
+		step := 1 << j
+		d := s.Detail[j]
+		for i := range buf {
+			var v float64
+			for k := 0; k < l; k++ {
+				m := mod(i+step*(l/2-1-k), n)
+				v += s.Wavelet.RecLo[k]*a[m] + s.Wavelet.RecHi[k]*d[m]
+			}
+			buf[i] = v / 2
+		}
+		a, buf = buf, a
+	}
+	copy(dst, a)
+	return dst
+}
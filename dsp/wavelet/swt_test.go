@@ -0,0 +1,88 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wavelet
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestSWTReconstruction(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, w := range allWavelets() {
+		for _, n := range []int{1, 7, 16, 64} {
+			for _, level := range []int{1, 2, 4} {
+				x := randSeq(rnd, n)
+				s := SWT(x, w, level)
+				if s.Levels() != level {
+					t.Errorf("unexpected number of levels: got:%d want:%d", s.Levels(), level)
+				}
+				for j := range s.Detail {
+					if len(s.Approx[j]) != n || len(s.Detail[j]) != n {
+						t.Errorf("unexpected coefficient length for %s level %d: got:%d,%d want:%d",
+							w.Name, j+1, len(s.Approx[j]), len(s.Detail[j]), n)
+					}
+				}
+				got := s.Reconstruct(nil)
+				if !floats.EqualApprox(got, x, 1e-10) {
+					t.Errorf("unexpected reconstruction for %s n=%d level=%d:\ngot: %v\nwant:%v", w.Name, n, level, got, x)
+				}
+			}
+		}
+	}
+}
+
+func TestSWTShiftInvariance(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 32
+	for _, w := range []Wavelet{Haar(), Daubechies(3), Symlet(5), Coiflet(1), Biorthogonal(2, 4)} {
+		x := randSeq(rnd, n)
+		for _, shift := range []int{1, 3, 8} {
+			y := make([]float64, n)
+			for i := range y {
+				y[i] = x[mod(i-shift, n)]
+			}
+			sx := SWT(x, w, 3)
+			sy := SWT(y, w, 3)
+			for j := range sx.Detail {
+				for i := range sx.Detail[j] {
+					k := mod(i-shift, n)
+					if !scalar.EqualWithinAbsOrRel(sy.Detail[j][i], sx.Detail[j][k], 1e-12, 1e-12) ||
+						!scalar.EqualWithinAbsOrRel(sy.Approx[j][i], sx.Approx[j][k], 1e-12, 1e-12) {
+						t.Errorf("coefficients not shift invariant for %s shift=%d level=%d", w.Name, shift, j+1)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSWTMatchesDWT(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 32
+	// The first level of the stationary wavelet transform
+	// interleaves the periodized discrete wavelet transforms
+	// of the sequence and the sequence advanced by one.
+	for _, w := range []Wavelet{Haar(), Daubechies(4), Biorthogonal(3, 3)} {
+		x := randSeq(rnd, n)
+		a, d := DWT(nil, nil, x, w, Periodization)
+		s := SWT(x, w, 1)
+		for o := range a {
+			if !scalar.EqualWithinAbsOrRel(s.Approx[0][2*o], a[o], 1e-12, 1e-12) ||
+				!scalar.EqualWithinAbsOrRel(s.Detail[0][2*o], d[o], 1e-12, 1e-12) {
+				t.Errorf("stationary coefficients do not match discrete coefficients for %s", w.Name)
+				break
+			}
+		}
+	}
+}
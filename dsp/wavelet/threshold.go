@@ -0,0 +1,87 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wavelet
+
+import (
+	"math"
+	"sort"
+)
+
+// Soft applies soft thresholding to the values of x in place and returns
+// the result. Values with a magnitude not greater than t are set to zero
+// and the magnitude of the other values is reduced by t.
+func Soft(x []float64, t float64) []float64 {
+	for i, v := range x {
+		switch {
+		case v > t:
+			x[i] = v - t
+		case v < -t:
+			x[i] = v + t
+		default:
+			x[i] = 0
+		}
+	}
+	return x
+}
+
+// Hard applies hard thresholding to the values of x in place and returns
+// the result. Values with a magnitude not greater than t are set to zero
+// and the other values are unchanged.
+func Hard(x []float64, t float64) []float64 {
+	for i, v := range x {
+		if math.Abs(v) <= t {
+			x[i] = 0
+		}
+	}
+	return x
+}
+
+// Garrote applies non-negative garrote thresholding to the values of x in
+// place and returns the result. Values with a magnitude not greater than t
+// are set to zero and the other values v are replaced by v - t²/v.
+// Garrote thresholding is a compromise between soft and hard thresholding.
+func Garrote(x []float64, t float64) []float64 {
+	for i, v := range x {
+		if math.Abs(v) <= t {
+			x[i] = 0
+		} else {
+			x[i] = v - t*t/v
+		}
+	}
+	return x
+}
+
+// NoiseSigma returns an estimate of the standard deviation of additive
+// Gaussian noise from the finest level detail coefficients of a sequence,
+// the median absolute deviation of the coefficients divided by 0.6745.
+// The estimate is robust to the few large coefficients that represent the
+// signal.
+func NoiseSigma(detail []float64) float64 {
+	if len(detail) == 0 {
+		panic("wavelet: no coefficients")
+	}
+	abs := make([]float64, len(detail))
+	for i, v := range detail {
+		abs[i] = math.Abs(v)
+	}
+	sort.Float64s(abs)
+	n := len(abs)
+	med := abs[n/2]
+	if n%2 == 0 {
+		med = (abs[n/2-1] + abs[n/2]) / 2
+	}
+	return med / 0.6744897501960817
+}
+
+// UniversalThreshold returns the universal threshold of Donoho and
+// Johnstone, sigma*sqrt(2*log(n)), for a sequence of length n with additive
+// Gaussian noise of standard deviation sigma.
+//
+// References:
+//   - Donoho, D. L. and Johnstone, I. M. (1994). Ideal spatial adaptation
+//     by wavelet shrinkage. Biometrika, 81(3), 425-455.
+func UniversalThreshold(sigma float64, n int) float64 {
+	return sigma * math.Sqrt(2*math.Log(float64(n)))
+}
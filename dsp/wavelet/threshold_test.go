@@ -0,0 +1,81 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wavelet
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestThreshold(t *testing.T) {
+	t.Parallel()
+	x := []float64{-3, -1, -0.5, 0, 0.5, 1, 2, 4}
+	for _, test := range []struct {
+		name string
+		fn   func([]float64, float64) []float64
+		want []float64
+	}{
+		{name: "soft", fn: Soft, want: []float64{-2, 0, 0, 0, 0, 0, 1, 3}},
+		{name: "hard", fn: Hard, want: []float64{-3, 0, 0, 0, 0, 0, 2, 4}},
+		{name: "garrote", fn: Garrote, want: []float64{-3 + 1.0/3, 0, 0, 0, 0, 0, 1.5, 3.75}},
+	} {
+		got := test.fn(append([]float64(nil), x...), 1)
+		if !floats.EqualApprox(got, test.want, 1e-15) {
+			t.Errorf("unexpected %s threshold: got:%v want:%v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestNoiseSigma(t *testing.T) {
+	t.Parallel()
+	if got := NoiseSigma([]float64{-3, 1, 2, -4}); !scalar.EqualWithinAbs(got, 2.5/0.6744897501960817, 1e-14) {
+		t.Errorf("unexpected noise estimate: got:%v want:%v", got, 2.5/0.6744897501960817)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n     = 1 << 14
+		sigma = 0.3
+	)
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = 5*math.Sin(2*math.Pi*float64(i)/1024) + sigma*rnd.NormFloat64()
+	}
+	_, d := DWT(nil, nil, x, Daubechies(4), Periodization)
+	got := NoiseSigma(d)
+	if !scalar.EqualWithinRel(got, sigma, 0.05) {
+		t.Errorf("unexpected noise estimate: got:%v want:%v", got, sigma)
+	}
+}
+
+func TestDenoise(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 1024
+	signal := make([]float64, n)
+	noisy := make([]float64, n)
+	for i := range signal {
+		u := float64(i) / n
+		signal[i] = math.Sin(4*math.Pi*u) + math.Copysign(1, u-0.5)
+		noisy[i] = signal[i] + 0.2*rnd.NormFloat64()
+	}
+	for _, thresh := range []func([]float64, float64) []float64{Soft, Hard, Garrote} {
+		d := Decompose(noisy, Symlet(6), Periodization, 5)
+		sigma := NoiseSigma(d.Detail[0])
+		th := UniversalThreshold(sigma, n)
+		for _, c := range d.Detail {
+			thresh(c, th)
+		}
+		got := d.Reconstruct(nil)
+		if before, after := floats.Distance(noisy, signal, 2), floats.Distance(got, signal, 2); after > 0.6*before {
+			t.Errorf("denoising did not reduce error sufficiently: before:%v after:%v", before, after)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wavelet
+
+import (
+	"fmt"
+	"math"
+)
+
+// Wavelet is a discrete wavelet defined by the filters of its two-channel
+// filter bank. The decomposition filters are used by the forward
+// transforms and the reconstruction filters by the inverse transforms. All
+// four filters have the same even length.
+//
+// For an orthogonal wavelet, the decomposition filters are the reversed
+// reconstruction filters. The reconstruction low-pass filter is the
+// scaling filter of the wavelet, and sums to sqrt(2).
+type Wavelet struct {
+	// Name is the conventional short name of the wavelet,
+	// such as "db4" or "bior2.2".
+	Name string
+
+	// DecLo and DecHi are the decomposition low-pass and
+	// high-pass filters.
+	DecLo, DecHi []float64
+	// RecLo and RecHi are the reconstruction low-pass and
+	// high-pass filters.
+	RecLo, RecHi []float64
+}
+
+// Len returns the length of the filters of the wavelet.
+func (w Wavelet) Len() int { return len(w.DecLo) }
+
+// orthogonal returns the orthogonal wavelet with the scaling filter h.
+func orthogonal(name string, h []float64) Wavelet {
+	n := len(h)
+	w := Wavelet{
+		Name:  name,
+		DecLo: make([]float64, n),
+		DecHi: make([]float64, n),
+		RecLo: append([]float64(nil), h...),
+		RecHi: make([]float64, n),
+	}
+	for i := range h {
+		w.DecLo[i] = h[n-1-i]
+	}
+	w.setHighPass()
+	return w
+}
+
+// setHighPass sets the high-pass filters of w from its low-pass filters.
+func (w *Wavelet) setHighPass() {
+	for i := range w.DecLo {
+		sign := 1.0
+		if i%2 == 1 {
+			sign = -1
+		}
+		w.RecHi[i] = sign * w.DecLo[i]
+		w.DecHi[i] = -sign * w.RecLo[i]
+	}
+}
+
+// Haar returns the Haar wavelet, which is the Daubechies wavelet with one
+// vanishing moment.
+func Haar() Wavelet {
+	w := Daubechies(1)
+	w.Name = "haar"
+	return w
+}
+
+// Daubechies returns the Daubechies wavelet with n vanishing moments,
+// which has the minimum phase scaling filter of length 2n among orthogonal
+// wavelets with n vanishing moments. Daubechies will panic if n is less
+// than 1 or greater than 20.
+//
+// References:
+//   - Daubechies, I. (1992). Ten Lectures on Wavelets. SIAM.
+func Daubechies(n int) Wavelet {
+	if n < 1 || len(daubechies) < n {
+		panic("wavelet: Daubechies order out of range")
+	}
+	return orthogonal(fmt.Sprintf("db%d", n), daubechies[n-1])
+}
+
+// Symlet returns the symlet with n vanishing moments, which is the least
+// asymmetric orthogonal wavelet with a scaling filter of length 2n and n
+// vanishing moments. Symlet will panic if n is less than 1 or greater than
+// 20.
+func Symlet(n int) Wavelet {
+	if n < 1 || len(symlets) < n {
+		panic("wavelet: symlet order out of range")
+	}
+	return orthogonal(fmt.Sprintf("sym%d", n), symlets[n-1])
+}
+
+// Coiflet returns the coiflet of order n, the orthogonal wavelet with a
+// scaling filter of length 6n whose wavelet has 2n vanishing moments and
+// whose scaling function has 2n-1 vanishing moments other than the zeroth.
+// Coiflet will panic if n is less than 1 or greater than 5.
+func Coiflet(n int) Wavelet {
+	if n < 1 || len(coiflets) < n {
+		panic("wavelet: coiflet order out of range")
+	}
+	return orthogonal(fmt.Sprintf("coif%d", n), coiflets[n-1])
+}
+
+// Biorthogonal returns the biorthogonal spline wavelet of Cohen, Daubechies
+// and Feauveau with a reconstruction scaling function that is a B-spline of
+// order nr, a decomposition wavelet with nr vanishing moments and a
+// reconstruction wavelet with nd vanishing moments. The filters are
+// symmetric, so the transforms have linear phase. The valid orders are
+// nr = 1 with nd = 1, 3 or 5, nr = 2 with nd = 2, 4, 6 or 8, and nr = 3
+// with nd = 1, 3, 5, 7 or 9. Biorthogonal will panic for other orders.
+//
+// References:
+//   - Cohen, A., Daubechies, I. and Feauveau, J.-C. (1992). Biorthogonal
+//     bases of compactly supported wavelets. Communications on Pure and
+//     Applied Mathematics, 45(5), 485-560.
+func Biorthogonal(nr, nd int) Wavelet {
+	switch {
+	case nr == 1 && (nd == 1 || nd == 3 || nd == 5):
+	case nr == 2 && (nd == 2 || nd == 4 || nd == 6 || nd == 8):
+	case nr == 3 && (nd == 1 || nd == 3 || nd == 5 || nd == 7 || nd == 9):
+	default:
+		panic("wavelet: invalid biorthogonal wavelet order")
+	}
+
+	// The reconstruction scaling filter is the binomial filter
+	// sqrt(2)*((1+z⁻¹)/2)^nr.
+	rec := []float64{math.Sqrt2}
+	for i := 0; i < nr; i++ {
+		rec = polyMul(rec, []float64{0.5, 0.5})
+	}
+	// The decomposition scaling filter is
+	//  sqrt(2)*((1+z⁻¹)/2)^nd * \sum_{k=0}^{l-1} C(l-1+k, k) y^k,
+	// with y = (2-z-z⁻¹)/4 = sin²(ξ/2) and l = (nr+nd)/2.
+	l := (nr + nd) / 2
+	dec := []float64{math.Sqrt2}
+	for i := 0; i < nd; i++ {
+		dec = polyMul(dec, []float64{0.5, 0.5})
+	}
+	sum := []float64{0}
+	y := []float64{1}
+	c := 1.0
+	for k := 0; k < l; k++ {
+		// Add c*y^k, centered in the symmetric polynomial sum.
+		off := (len(y) - len(sum)) / 2
+		if off > 0 {
+			sum = pad(sum, off)
+		}
+		off = (len(sum) - len(y)) / 2
+		for i, v := range y {
+			sum[off+i] += c * v
+		}
+		y = polyMul(y, []float64{-0.25, 0.5, -0.25})
+		c = c * float64(l+k) / float64(k+1)
+	}
+	dec = polyMul(dec, sum)
+
+	// Pad the filters to a common even length so that the center of
+	// the decomposition filter is at index n/2 for odd length filters
+	// and (n-1)/2 for even length filters, and the center of the
+	// reconstruction filter is reflected.
+	n := len(dec)
+	if n%2 == 1 {
+		n++
+	}
+	decCenter := float64(n) / 2
+	if len(dec)%2 == 0 {
+		decCenter = float64(n-1) / 2
+	}
+	recCenter := float64(n-1) - decCenter
+	w := Wavelet{
+		Name:  fmt.Sprintf("bior%d.%d", nr, nd),
+		DecLo: make([]float64, n),
+		DecHi: make([]float64, n),
+		RecLo: make([]float64, n),
+		RecHi: make([]float64, n),
+	}
+	copy(w.DecLo[int(decCenter-float64(len(dec)-1)/2):], dec)
+	copy(w.RecLo[int(recCenter-float64(len(rec)-1)/2):], rec)
+	w.setHighPass()
+	return w
+}
+
+// pad returns p with n zeros added at each end.
+func pad(p []float64, n int) []float64 {
+	q := make([]float64, len(p)+2*n)
+	copy(q[n:], p)
+	return q
+}
+
+func polyMul(a, b []float64) []float64 {
+	c := make([]float64, len(a)+len(b)-1)
+	for i, x := range a {
+		for j, y := range b {
+			c[i+j] += x * y
+		}
+	}
+	return c
+}
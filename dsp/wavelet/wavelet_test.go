@@ -0,0 +1,213 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wavelet
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+// allWavelets returns a wavelet of every family and order.
+func allWavelets() []Wavelet {
+	w := []Wavelet{Haar()}
+	for n := 1; n <= 20; n++ {
+		w = append(w, Daubechies(n), Symlet(n))
+	}
+	for n := 1; n <= 5; n++ {
+		w = append(w, Coiflet(n))
+	}
+	for _, p := range [][2]int{
+		{1, 1}, {1, 3}, {1, 5},
+		{2, 2}, {2, 4}, {2, 6}, {2, 8},
+		{3, 1}, {3, 3}, {3, 5}, {3, 7}, {3, 9},
+	} {
+		w = append(w, Biorthogonal(p[0], p[1]))
+	}
+	return w
+}
+
+func TestOrthogonalFilters(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-13
+	type family struct {
+		name    string
+		orders  int
+		wavelet func(int) Wavelet
+		// moments returns the number of vanishing
+		// moments of the wavelet of order n.
+		moments func(n int) int
+		len     func(n int) int
+	}
+	for _, f := range []family{
+		{name: "db", orders: 20, wavelet: Daubechies, moments: func(n int) int { return n }, len: func(n int) int { return 2 * n }},
+		{name: "sym", orders: 20, wavelet: Symlet, moments: func(n int) int { return n }, len: func(n int) int { return 2 * n }},
+		{name: "coif", orders: 5, wavelet: Coiflet, moments: func(n int) int { return 2 * n }, len: func(n int) int { return 6 * n }},
+	} {
+		for n := 1; n <= f.orders; n++ {
+			w := f.wavelet(n)
+			name := fmt.Sprintf("%s%d", f.name, n)
+			if w.Name != name {
+				t.Errorf("unexpected name: got:%s want:%s", w.Name, name)
+			}
+			if w.Len() != f.len(n) {
+				t.Errorf("unexpected length for %s: got:%d want:%d", name, w.Len(), f.len(n))
+			}
+			h := w.RecLo
+			if got := floats.Sum(h); !scalar.EqualWithinAbs(got, math.Sqrt2, tol) {
+				t.Errorf("unexpected scaling filter sum for %s: got:%v want:%v", name, got, math.Sqrt2)
+			}
+			for k := 0; 2*k < len(h); k++ {
+				var got float64
+				for i := 0; i+2*k < len(h); i++ {
+					got += h[i] * h[i+2*k]
+				}
+				var want float64
+				if k == 0 {
+					want = 1
+				}
+				if !scalar.EqualWithinAbs(got, want, tol) {
+					t.Errorf("unexpected autocorrelation at lag %d for %s: got:%v want:%v", 2*k, name, got, want)
+				}
+			}
+			// The moments of the wavelet filter are evaluated
+			// about the center of the filter, scaled by its
+			// length, to limit cancellation.
+			c := float64(len(h)-1) / 2
+			for m := 0; m < f.moments(n); m++ {
+				var got float64
+				for i, v := range w.RecHi {
+					got += math.Pow((float64(i)-c)/float64(len(h)), float64(m)) * v
+				}
+				if !scalar.EqualWithinAbs(got, 0, 1e-11) {
+					t.Errorf("unexpected wavelet moment %d for %s: got:%v want:0", m, name, got)
+				}
+			}
+			for i := range h {
+				if w.DecLo[i] != h[len(h)-1-i] || w.DecHi[i] != w.RecHi[len(h)-1-i] {
+					t.Errorf("decomposition filters of %s are not reversed reconstruction filters", name)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestFilterValues(t *testing.T) {
+	t.Parallel()
+	s3 := math.Sqrt(3)
+	for _, test := range []struct {
+		w     Wavelet
+		tol   float64
+		recLo []float64
+		decLo []float64
+	}{
+		{
+			w:     Daubechies(2),
+			recLo: []float64{(1 + s3) / (4 * math.Sqrt2), (3 + s3) / (4 * math.Sqrt2), (3 - s3) / (4 * math.Sqrt2), (1 - s3) / (4 * math.Sqrt2)},
+		},
+		{
+			w: Symlet(4),
+			recLo: []float64{
+				0.032223100604042702, -0.012603967262037833, -0.099219543576847216, 0.29785779560527736,
+				0.80373875180591614, 0.49761866763201545, -0.02963552764599851, -0.075765714789273325,
+			},
+		},
+		{
+			// Published coiflet coefficients are accurate
+			// to about twelve digits.
+			w:   Coiflet(1),
+			tol: 1e-11,
+			decLo: []float64{
+				-0.01565572813546454, -0.0727326195128539, 0.38486484686420286,
+				0.8525720202122554, 0.3378976624578092, -0.0727326195128539,
+			},
+		},
+		{
+			w:     Biorthogonal(2, 2),
+			recLo: []float64{0, 0.3535533905932738, 0.7071067811865476, 0.3535533905932738, 0, 0},
+			decLo: []float64{0, -0.1767766952966369, 0.3535533905932738, 1.0606601717798212, 0.3535533905932738, -0.1767766952966369},
+		},
+		{
+			w:     Biorthogonal(1, 3),
+			recLo: []float64{0, 0, 0.7071067811865476, 0.7071067811865476, 0, 0},
+			decLo: []float64{-0.08838834764831845, 0.08838834764831845, 0.7071067811865476, 0.7071067811865476, 0.08838834764831845, -0.08838834764831845},
+		},
+		{
+			w:     Biorthogonal(3, 1),
+			recLo: []float64{0.1767766952966369, 0.5303300858899107, 0.5303300858899107, 0.1767766952966369},
+			decLo: []float64{-0.3535533905932738, 1.0606601717798214, 1.0606601717798214, -0.3535533905932738},
+		},
+	} {
+		tol := test.tol
+		if tol == 0 {
+			tol = 1e-12
+		}
+		if test.recLo != nil && !floats.EqualApprox(test.w.RecLo, test.recLo, tol) {
+			t.Errorf("unexpected reconstruction filter for %s:\ngot: %v\nwant:%v", test.w.Name, test.w.RecLo, test.recLo)
+		}
+		if test.decLo != nil && !floats.EqualApprox(test.w.DecLo, test.decLo, tol) {
+			t.Errorf("unexpected decomposition filter for %s:\ngot: %v\nwant:%v", test.w.Name, test.w.DecLo, test.decLo)
+		}
+	}
+}
+
+func TestBiorthogonalFilters(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-13
+	for _, w := range allWavelets() {
+		if w.Len()%2 != 0 {
+			t.Errorf("odd filter length for %s: %d", w.Name, w.Len())
+		}
+		// The decomposition and reconstruction low-pass filters
+		// are biorthogonal under even shifts.
+		for k := -w.Len() / 2; k <= w.Len()/2; k++ {
+			var got float64
+			for i := range w.RecLo {
+				j := len(w.DecLo) - 1 - i + 2*k
+				if 0 <= j && j < len(w.DecLo) {
+					got += w.RecLo[i] * w.DecLo[j]
+				}
+			}
+			var want float64
+			if k == 0 {
+				want = 1
+			}
+			if !scalar.EqualWithinAbs(got, want, tol) {
+				t.Errorf("unexpected cross-correlation at lag %d for %s: got:%v want:%v", 2*k, w.Name, got, want)
+			}
+		}
+	}
+}
+
+func TestWaveletPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "db0", fn: func() { Daubechies(0) }},
+		{name: "db21", fn: func() { Daubechies(21) }},
+		{name: "sym0", fn: func() { Symlet(0) }},
+		{name: "coif6", fn: func() { Coiflet(6) }},
+		{name: "bior2.3", fn: func() { Biorthogonal(2, 3) }},
+		{name: "bior4.4", fn: func() { Biorthogonal(4, 4) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
@@ -2,5 +2,5 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package flow provides control flow analysis functions.
+// Package flow provides control flow analysis and network flow functions.
 package flow // import "gonum.org/v1/gonum/graph/flow"
@@ -0,0 +1,64 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flow
+
+import (
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// WeightedBuilder is a type that can add nodes and weighted edges.
+type WeightedBuilder interface {
+	AddNode(graph.Node)
+	SetWeightedEdge(graph.WeightedEdge)
+}
+
+// GomoryHu constructs a Gomory-Hu tree of the undirected graph g, placing
+// the result in the destination, dst. Edge weights of g are used as
+// capacities and must be non-negative. The destination is not cleared
+// first.
+//
+// The Gomory-Hu tree has the nodes of g and represents the minimum cuts
+// between all pairs of nodes of g: the value of the minimum cut between two
+// nodes is the minimum weight of the edges on the path between them in the
+// tree, and removing that edge from the tree partitions the nodes into the
+// two sides of a minimum cut. If g is not connected, tree edges with zero
+// weight join the connected components.
+//
+// GomoryHu uses Gusfield's algorithm which performs |V|-1 maximum flow
+// computations on g.
+//
+// If dst has nodes that exist in g, GomoryHu will panic.
+func GomoryHu(dst WeightedBuilder, g graph.WeightedUndirected) {
+	nodes := newResidualNodes(g).nodes
+	for _, n := range nodes {
+		dst.AddNode(n)
+	}
+	if len(nodes) < 2 {
+		return
+	}
+
+	parent := make([]int, len(nodes))
+	weight := make([]float64, len(nodes))
+	for s := 1; s < len(nodes); s++ {
+		t := parent[s]
+		r := newUndirectedResidual(g)
+		weight[s] = r.dinic(s, t)
+		side := r.reachable(s)
+		for i := range nodes {
+			if i != s && side[i] && parent[i] == t {
+				parent[i] = s
+			}
+		}
+		if side[parent[t]] {
+			parent[s] = parent[t]
+			parent[t] = s
+			weight[s], weight[t] = weight[t], weight[s]
+		}
+	}
+	for i := 1; i < len(nodes); i++ {
+		dst.SetWeightedEdge(simple.WeightedEdge{F: nodes[i], T: nodes[parent[i]], W: weight[i]})
+	}
+}
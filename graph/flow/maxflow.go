@@ -0,0 +1,452 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flow
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Flow is a flow in a network. The flow assigned to each edge is at most
+// the edge's capacity and flow is conserved at all nodes other than the
+// source and the target.
+type Flow struct {
+	source, target graph.Node
+
+	value float64
+	res   *residual
+}
+
+// Source returns the source node of the flow.
+func (f Flow) Source() graph.Node { return f.source }
+
+// Target returns the target node of the flow.
+func (f Flow) Target() graph.Node { return f.target }
+
+// Value returns the total flow from the source to the target.
+func (f Flow) Value() float64 { return f.value }
+
+// EdgeFlow returns the flow assigned to the edge from the node with ID uid
+// to the node with ID vid. If the edge does not exist, EdgeFlow returns zero.
+func (f Flow) EdgeFlow(uid, vid int64) float64 {
+	u, ok := f.res.indexOf[uid]
+	if !ok {
+		return 0
+	}
+	v, ok := f.res.indexOf[vid]
+	if !ok {
+		return 0
+	}
+	for _, k := range f.res.adj[u] {
+		if f.res.head[k] == v && f.res.isEdge(k) {
+			return f.res.flow(k)
+		}
+	}
+	return 0
+}
+
+// Edges returns the edges of the network that carry a positive flow, with
+// the flow as the edge weight, ordered by the IDs of their end nodes.
+func (f Flow) Edges() []graph.WeightedEdge {
+	var edges []graph.WeightedEdge
+	for u, arcs := range f.res.adj {
+		for _, k := range arcs {
+			if !f.res.isEdge(k) {
+				continue
+			}
+			if fl := f.res.flow(k); fl > 0 {
+				edges = append(edges, simple.WeightedEdge{
+					F: f.res.nodes[u],
+					T: f.res.nodes[f.res.head[k]],
+					W: fl,
+				})
+			}
+		}
+	}
+	return edges
+}
+
+// MinCut returns the source side of the minimum cut corresponding to the
+// flow, the set of nodes reachable from the source in the residual network,
+// and the edges crossing the cut. If the flow is a maximum flow, the sum
+// of the capacities of the cut edges equals the flow value. The nodes are
+// ordered by ID.
+func (f Flow) MinCut() (sourceSide []graph.Node, cut []graph.WeightedEdge) {
+	reached := f.res.reachable(f.res.indexOf[f.source.ID()])
+	for u, ok := range reached {
+		if !ok {
+			continue
+		}
+		sourceSide = append(sourceSide, f.res.nodes[u])
+		for _, k := range f.res.adj[u] {
+			if f.res.isEdge(k) && !reached[f.res.head[k]] {
+				cut = append(cut, simple.WeightedEdge{
+					F: f.res.nodes[u],
+					T: f.res.nodes[f.res.head[k]],
+					W: f.res.capacity[k],
+				})
+			}
+		}
+	}
+	return sourceSide, cut
+}
+
+// residual is a residual network. Arcs are stored in pairs so that arc k^1
+// is the reverse of arc k.
+type residual struct {
+	nodes   []graph.Node
+	indexOf map[int64]int
+
+	// adj holds the indices of the arcs leaving each node.
+	adj [][]int
+	// head holds the node index each arc points to.
+	head []int
+	// capacity holds the capacity of each arc of the network, and
+	// is zero for reverse arcs of directed edges.
+	capacity []float64
+	// cost holds the cost per unit flow of each arc.
+	cost []float64
+	// rem holds the remaining capacity of each arc.
+	rem []float64
+	// edge indicates whether the arc corresponds to an edge of the
+	// network.
+	edge []bool
+}
+
+// newResidual returns the residual network of the directed graph g with
+// edge weights as capacities. newResidual panics if a capacity is negative.
+func newResidual(g graph.WeightedDirected) *residual {
+	r := newResidualNodes(g)
+	for u, n := range r.nodes {
+		uid := n.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			w, ok := g.Weight(uid, vid)
+			if !ok {
+				panic("flow: unexpected invalid weight")
+			}
+			if w < 0 {
+				panic("flow: negative capacity")
+			}
+			r.addArcs(u, r.indexOf[vid], w, 0, true, false)
+		}
+	}
+	return r
+}
+
+// newUndirectedResidual returns the residual network of the undirected
+// graph g with edge weights as capacities.
+func newUndirectedResidual(g graph.WeightedUndirected) *residual {
+	r := newResidualNodes(g)
+	for u, n := range r.nodes {
+		uid := n.ID()
+		to := g.From(uid)
+		for to.Next() {
+			v := r.indexOf[to.Node().ID()]
+			if v <= u {
+				// Each edge is visited from both ends. Self
+				// loops carry no flow between distinct nodes.
+				continue
+			}
+			w, ok := g.Weight(uid, r.nodes[v].ID())
+			if !ok {
+				panic("flow: unexpected invalid weight")
+			}
+			if w < 0 {
+				panic("flow: negative capacity")
+			}
+			r.addArcs(u, v, w, w, true, true)
+		}
+	}
+	return r
+}
+
+func newResidualNodes(g graph.Graph) *residual {
+	nodes := graph.NodesOf(g.Nodes())
+	ordered.ByID(nodes)
+	r := &residual{
+		nodes:   nodes,
+		indexOf: make(map[int64]int, len(nodes)),
+		adj:     make([][]int, len(nodes)),
+	}
+	for i, n := range nodes {
+		r.indexOf[n.ID()] = i
+	}
+	return r
+}
+
+// addArcs adds an arc from u to v with capacity c and its reverse with
+// capacity rc.
+func (r *residual) addArcs(u, v int, c, rc float64, edge, revEdge bool) {
+	r.adj[u] = append(r.adj[u], len(r.head))
+	r.head = append(r.head, v)
+	r.capacity = append(r.capacity, c)
+	r.rem = append(r.rem, c)
+	r.edge = append(r.edge, edge)
+	r.cost = append(r.cost, 0)
+
+	r.adj[v] = append(r.adj[v], len(r.head))
+	r.head = append(r.head, u)
+	r.capacity = append(r.capacity, rc)
+	r.rem = append(r.rem, rc)
+	r.edge = append(r.edge, revEdge)
+	r.cost = append(r.cost, 0)
+}
+
+func (r *residual) isEdge(k int) bool { return r.edge[k] }
+
+// flow returns the net flow along arc k.
+func (r *residual) flow(k int) float64 {
+	return math.Max(0, r.capacity[k]-r.rem[k])
+}
+
+// push sends d units of flow along arc k.
+func (r *residual) push(k int, d float64) {
+	r.rem[k] -= d
+	r.rem[k^1] += d
+}
+
+// reachable returns the nodes reachable from s along arcs with remaining
+// capacity.
+func (r *residual) reachable(s int) []bool {
+	seen := make([]bool, len(r.nodes))
+	seen[s] = true
+	queue := []int{s}
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, k := range r.adj[u] {
+			v := r.head[k]
+			if r.rem[k] > 0 && !seen[v] {
+				seen[v] = true
+				queue = append(queue, v)
+			}
+		}
+	}
+	return seen
+}
+
+// terminals returns the indices of s and t in r, panicking if either
+// is not in the graph or if they are the same node.
+func (r *residual) terminals(s, t graph.Node) (int, int) {
+	si, ok := r.indexOf[s.ID()]
+	if !ok {
+		panic("flow: source not in graph")
+	}
+	ti, ok := r.indexOf[t.ID()]
+	if !ok {
+		panic("flow: target not in graph")
+	}
+	if si == ti {
+		panic("flow: source and target are the same node")
+	}
+	return si, ti
+}
+
+// EdmondsKarp returns a maximum flow from s to t in g using the
+// Edmonds-Karp algorithm. Edge weights of g are used as capacities and
+// must be non-negative. EdmondsKarp will panic if s or t is not in g or if
+// s and t are the same node.
+//
+// The time complexity of EdmondsKarp is O(|V|⋅|E|²).
+func EdmondsKarp(g graph.WeightedDirected, s, t graph.Node) Flow {
+	r := newResidual(g)
+	si, ti := r.terminals(s, t)
+	return Flow{source: s, target: t, value: r.edmondsKarp(si, ti), res: r}
+}
+
+func (r *residual) edmondsKarp(s, t int) float64 {
+	var value float64
+	via := make([]int, len(r.nodes))
+	for {
+		// Find a shortest augmenting path by breadth-first search.
+		for i := range via {
+			via[i] = -1
+		}
+		queue := []int{s}
+		for len(queue) != 0 && via[t] < 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, k := range r.adj[u] {
+				v := r.head[k]
+				if r.rem[k] > 0 && v != s && via[v] < 0 {
+					via[v] = k
+					queue = append(queue, v)
+				}
+			}
+		}
+		if via[t] < 0 {
+			return value
+		}
+
+		d := math.Inf(1)
+		for v := t; v != s; v = r.head[via[v]^1] {
+			d = math.Min(d, r.rem[via[v]])
+		}
+		if math.IsInf(d, 1) {
+			panic("flow: infinite capacity path")
+		}
+		for v := t; v != s; v = r.head[via[v]^1] {
+			r.push(via[v], d)
+		}
+		value += d
+	}
+}
+
+// Dinic returns a maximum flow from s to t in g using Dinic's blocking
+// flow algorithm. Edge weights of g are used as capacities and must be
+// non-negative. Dinic will panic if s or t is not in g or if s and t are
+// the same node.
+//
+// The time complexity of Dinic is O(|V|²⋅|E|).
+func Dinic(g graph.WeightedDirected, s, t graph.Node) Flow {
+	r := newResidual(g)
+	si, ti := r.terminals(s, t)
+	return Flow{source: s, target: t, value: r.dinic(si, ti), res: r}
+}
+
+func (r *residual) dinic(s, t int) float64 {
+	var value float64
+	level := make([]int, len(r.nodes))
+	next := make([]int, len(r.nodes))
+	for r.levels(s, t, level) {
+		for i := range next {
+			next[i] = 0
+		}
+		for {
+			d := r.blockingPath(s, t, math.Inf(1), level, next)
+			if d == 0 {
+				break
+			}
+			if math.IsInf(d, 1) {
+				panic("flow: infinite capacity path")
+			}
+			value += d
+		}
+	}
+	return value
+}
+
+// levels computes the breadth-first distances from s in the residual
+// network and returns whether t is reachable.
+func (r *residual) levels(s, t int, level []int) bool {
+	for i := range level {
+		level[i] = -1
+	}
+	level[s] = 0
+	queue := []int{s}
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, k := range r.adj[u] {
+			v := r.head[k]
+			if r.rem[k] > 0 && level[v] < 0 {
+				level[v] = level[u] + 1
+				queue = append(queue, v)
+			}
+		}
+	}
+	return level[t] >= 0
+}
+
+// blockingPath finds an augmenting path from u to t in the level graph,
+// pushing at most limit units of flow along it, and returns the amount
+// pushed.
+func (r *residual) blockingPath(u, t int, limit float64, level, next []int) float64 {
+	if u == t {
+		return limit
+	}
+	for ; next[u] < len(r.adj[u]); next[u]++ {
+		k := r.adj[u][next[u]]
+		v := r.head[k]
+		if r.rem[k] <= 0 || level[v] != level[u]+1 {
+			continue
+		}
+		d := r.blockingPath(v, t, math.Min(limit, r.rem[k]), level, next)
+		if d > 0 {
+			r.push(k, d)
+			return d
+		}
+	}
+	return 0
+}
+
+// PushRelabel returns a maximum flow from s to t in g using the
+// Goldberg-Tarjan push-relabel algorithm with first-in first-out vertex
+// selection. Edge weights of g are used as capacities and must be
+// non-negative and finite. PushRelabel will panic if s or t is not in g
+// or if s and t are the same node.
+//
+// The time complexity of PushRelabel is O(|V|³).
+func PushRelabel(g graph.WeightedDirected, s, t graph.Node) Flow {
+	r := newResidual(g)
+	si, ti := r.terminals(s, t)
+	return Flow{source: s, target: t, value: r.pushRelabel(si, ti), res: r}
+}
+
+func (r *residual) pushRelabel(s, t int) float64 {
+	n := len(r.nodes)
+	height := make([]int, n)
+	excess := make([]float64, n)
+	next := make([]int, n)
+	var queue []int
+
+	height[s] = n
+	for _, k := range r.adj[s] {
+		d := r.rem[k]
+		if d <= 0 {
+			continue
+		}
+		if math.IsInf(d, 1) {
+			panic("flow: infinite capacity")
+		}
+		v := r.head[k]
+		r.push(k, d)
+		if v != t && v != s && excess[v] == 0 {
+			queue = append(queue, v)
+		}
+		excess[v] += d
+		excess[s] -= d
+	}
+
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+
+		// Discharge u.
+		for excess[u] > 0 {
+			if next[u] == len(r.adj[u]) {
+				// Relabel u.
+				minHeight := math.MaxInt32
+				for _, k := range r.adj[u] {
+					if r.rem[k] > 0 && height[r.head[k]] < minHeight {
+						minHeight = height[r.head[k]]
+					}
+				}
+				height[u] = minHeight + 1
+				next[u] = 0
+				continue
+			}
+			k := r.adj[u][next[u]]
+			v := r.head[k]
+			if r.rem[k] > 0 && height[u] == height[v]+1 {
+				d := math.Min(excess[u], r.rem[k])
+				r.push(k, d)
+				if v != t && v != s && excess[v] == 0 {
+					queue = append(queue, v)
+				}
+				excess[u] -= d
+				excess[v] += d
+			} else {
+				next[u]++
+			}
+		}
+	}
+	return excess[t]
+}
@@ -0,0 +1,293 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flow
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var maxFlowFuncs = []struct {
+	name string
+	fn   func(g graph.WeightedDirected, s, t graph.Node) Flow
+}{
+	{name: "EdmondsKarp", fn: EdmondsKarp},
+	{name: "Dinic", fn: Dinic},
+	{name: "PushRelabel", fn: PushRelabel},
+}
+
+func weightedDirected(edges []simple.WeightedEdge) *simple.WeightedDirectedGraph {
+	g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	for _, e := range edges {
+		g.SetWeightedEdge(e)
+	}
+	return g
+}
+
+func wedge(u, v int64, w float64) simple.WeightedEdge {
+	return simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: w}
+}
+
+var maxFlowTests = []struct {
+	name  string
+	edges []simple.WeightedEdge
+	s, t  int64
+
+	want     float64
+	wantSide []int64
+}{
+	{
+		// Example from Cormen et al. Introduction to Algorithms (3rd ed.) fig 26.1.
+		name: "CLRS",
+		edges: []simple.WeightedEdge{
+			wedge(0, 1, 16), wedge(0, 2, 13), wedge(2, 1, 4), wedge(1, 3, 12),
+			wedge(3, 2, 9), wedge(2, 4, 14), wedge(4, 3, 7), wedge(3, 5, 20),
+			wedge(4, 5, 4),
+		},
+		s: 0, t: 5,
+		want:     23,
+		wantSide: []int64{0, 1, 2, 4},
+	},
+	{
+		name: "disconnected",
+		edges: []simple.WeightedEdge{
+			wedge(0, 1, 3), wedge(2, 3, 5),
+		},
+		s: 0, t: 3,
+		want:     0,
+		wantSide: []int64{0, 1},
+	},
+	{
+		name: "antiparallel",
+		edges: []simple.WeightedEdge{
+			wedge(0, 1, 2), wedge(1, 0, 5), wedge(1, 2, 7), wedge(0, 2, 1),
+		},
+		s: 0, t: 2,
+		want:     3,
+		wantSide: []int64{0},
+	},
+}
+
+func TestMaxFlow(t *testing.T) {
+	t.Parallel()
+	for _, test := range maxFlowTests {
+		g := weightedDirected(test.edges)
+		for _, alg := range maxFlowFuncs {
+			f := alg.fn(g, simple.Node(test.s), simple.Node(test.t))
+			name := fmt.Sprintf("%s %s", test.name, alg.name)
+			if f.Value() != test.want {
+				t.Errorf("%s: unexpected flow value: got:%v want:%v", name, f.Value(), test.want)
+			}
+			checkFlow(t, name, g, f)
+			side, _ := f.MinCut()
+			var ids []int64
+			for _, n := range side {
+				ids = append(ids, n.ID())
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(test.wantSide) {
+				t.Errorf("%s: unexpected source side: got:%v want:%v", name, ids, test.wantSide)
+			}
+		}
+	}
+}
+
+func TestMaxFlowRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		n := 2 + rnd.Intn(20)
+		g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+		for u := 0; u < n; u++ {
+			g.AddNode(simple.Node(u))
+		}
+		for u := 0; u < n; u++ {
+			for v := 0; v < n; v++ {
+				if u != v && rnd.Float64() < 0.3 {
+					g.SetWeightedEdge(wedge(int64(u), int64(v), float64(rnd.Intn(20))))
+				}
+			}
+		}
+		var want float64
+		for j, alg := range maxFlowFuncs {
+			f := alg.fn(g, simple.Node(0), simple.Node(n-1))
+			name := fmt.Sprintf("random %d %s", i, alg.name)
+			if j == 0 {
+				want = f.Value()
+			} else if f.Value() != want {
+				t.Errorf("%s: mismatched flow value: got:%v want:%v", name, f.Value(), want)
+			}
+			checkFlow(t, name, g, f)
+		}
+	}
+}
+
+// checkFlow checks the capacity and conservation constraints of f, and
+// that the minimum cut has the capacity of the flow value.
+func checkFlow(t *testing.T, name string, g graph.WeightedDirected, f Flow) {
+	t.Helper()
+	net := make(map[int64]float64)
+	for _, e := range f.Edges() {
+		uid, vid := e.From().ID(), e.To().ID()
+		c, ok := g.Weight(uid, vid)
+		if !ok || !g.HasEdgeFromTo(uid, vid) {
+			t.Errorf("%s: flow on non-existent edge %d→%d", name, uid, vid)
+		}
+		if e.Weight() > c {
+			t.Errorf("%s: capacity exceeded on edge %d→%d: %v > %v", name, uid, vid, e.Weight(), c)
+		}
+		if got := f.EdgeFlow(uid, vid); got != e.Weight() {
+			t.Errorf("%s: mismatched edge flow %d→%d: got:%v want:%v", name, uid, vid, got, e.Weight())
+		}
+		net[uid] -= e.Weight()
+		net[vid] += e.Weight()
+	}
+	sid, tid := f.Source().ID(), f.Target().ID()
+	for id, v := range net {
+		switch id {
+		case sid:
+			v = -v
+			fallthrough
+		case tid:
+			if !scalar.EqualWithinAbs(v, f.Value(), 1e-12) {
+				t.Errorf("%s: net flow at terminal %d does not match value: got:%v want:%v", name, id, v, f.Value())
+			}
+		default:
+			if !scalar.EqualWithinAbs(v, 0, 1e-12) {
+				t.Errorf("%s: flow not conserved at %d: %v", name, id, v)
+			}
+		}
+	}
+	_, cut := f.MinCut()
+	var capacity float64
+	for _, e := range cut {
+		capacity += e.Weight()
+	}
+	if !scalar.EqualWithinAbs(capacity, f.Value(), 1e-12) {
+		t.Errorf("%s: cut capacity does not match flow value: got:%v want:%v", name, capacity, f.Value())
+	}
+}
+
+func TestMinCostFlow(t *testing.T) {
+	t.Parallel()
+	g := weightedDirected([]simple.WeightedEdge{
+		wedge(0, 1, 2), wedge(0, 2, 1), wedge(1, 2, 1), wedge(1, 3, 1), wedge(2, 3, 2),
+	})
+	costs := map[[2]int64]float64{
+		{0, 1}: 1, {0, 2}: 2, {1, 2}: 1, {1, 3}: 3, {2, 3}: 1,
+	}
+	cost := func(uid, vid int64) float64 { return costs[[2]int64{uid, vid}] }
+	for _, test := range []struct {
+		amount float64
+		value  float64
+		cost   float64
+		ok     bool
+	}{
+		{amount: 0, value: 0, cost: 0, ok: true},
+		{amount: 2, value: 2, cost: 6, ok: true},
+		{amount: 3, value: 3, cost: 10, ok: true},
+		{amount: 4, value: 3, cost: 10, ok: false},
+		{amount: math.Inf(1), value: 3, cost: 10, ok: true},
+	} {
+		f, c, ok := MinCostFlow(g, simple.Node(0), simple.Node(3), test.amount, cost)
+		if f.Value() != test.value || c != test.cost || ok != test.ok {
+			t.Errorf("unexpected result for amount %v: got:(%v, %v, %t) want:(%v, %v, %t)",
+				test.amount, f.Value(), c, ok, test.value, test.cost, test.ok)
+		}
+		name := fmt.Sprintf("min cost %v", test.amount)
+		net := make(map[int64]float64)
+		var total float64
+		for _, e := range f.Edges() {
+			net[e.From().ID()] -= e.Weight()
+			net[e.To().ID()] += e.Weight()
+			total += e.Weight() * cost(e.From().ID(), e.To().ID())
+		}
+		if total != c {
+			t.Errorf("%s: edge costs do not sum to total: got:%v want:%v", name, total, c)
+		}
+		if net[1] != 0 || net[2] != 0 || net[3] != f.Value() {
+			t.Errorf("%s: invalid flow: %v", name, net)
+		}
+	}
+
+	neg := weightedDirected([]simple.WeightedEdge{
+		wedge(0, 1, 1), wedge(1, 2, 1), wedge(2, 1, 1), wedge(2, 3, 1),
+	})
+	panicked := func() (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		MinCostFlow(neg, simple.Node(0), simple.Node(3), 1, func(uid, vid int64) float64 { return -1 })
+		return false
+	}()
+	if !panicked {
+		t.Error("expected panic for negative cost cycle")
+	}
+}
+
+func TestGomoryHu(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		n := 2 + rnd.Intn(12)
+		g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+		d := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+		for u := 0; u < n; u++ {
+			g.AddNode(simple.Node(u))
+			d.AddNode(simple.Node(u))
+		}
+		for u := 0; u < n; u++ {
+			for v := u + 1; v < n; v++ {
+				if rnd.Float64() < 0.4 {
+					w := float64(1 + rnd.Intn(10))
+					g.SetWeightedEdge(wedge(int64(u), int64(v), w))
+					d.SetWeightedEdge(wedge(int64(u), int64(v), w))
+					d.SetWeightedEdge(wedge(int64(v), int64(u), w))
+				}
+			}
+		}
+
+		tree := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+		GomoryHu(tree, g)
+		if got := tree.Edges().Len(); got != n-1 {
+			t.Fatalf("random %d: unexpected number of tree edges: got:%d want:%d", i, got, n-1)
+		}
+		for u := 0; u < n; u++ {
+			for v := u + 1; v < n; v++ {
+				want := Dinic(d, simple.Node(u), simple.Node(v)).Value()
+				got := minOnPath(tree, int64(u), int64(v))
+				if got != want {
+					t.Errorf("random %d: unexpected minimum cut between %d and %d: got:%v want:%v", i, u, v, got, want)
+				}
+			}
+		}
+	}
+}
+
+// minOnPath returns the minimum edge weight on the path between u and v
+// in the tree.
+func minOnPath(tree *simple.WeightedUndirectedGraph, u, v int64) float64 {
+	best := map[int64]float64{u: math.Inf(1)}
+	queue := []int64{u}
+	for len(queue) != 0 {
+		x := queue[0]
+		queue = queue[1:]
+		to := tree.From(x)
+		for to.Next() {
+			y := to.Node().ID()
+			if _, ok := best[y]; ok {
+				continue
+			}
+			w, _ := tree.Weight(x, y)
+			best[y] = math.Min(best[x], w)
+			queue = append(queue, y)
+		}
+	}
+	return best[v]
+}
@@ -0,0 +1,106 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flow
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// MinCostFlow returns a flow of the given amount from s to t in g that has
+// minimum total cost, and the cost of the flow. Edge weights of g are used
+// as capacities and must be non-negative. The cost per unit of flow along
+// the edge from the node with ID uid to the node with ID vid is given by
+// cost(uid, vid). If amount is +Inf, the minimum cost maximum flow is
+// returned.
+//
+// If the amount exceeds the maximum flow from s to t, the returned flow is
+// a minimum cost maximum flow and ok is false. MinCostFlow will panic if g
+// contains a cycle of negative total cost, if s or t is not in g or if s and
+// t are the same node.
+//
+// MinCostFlow uses the successive shortest path algorithm with the
+// Bellman-Ford algorithm for finding shortest paths in the residual network.
+func MinCostFlow(g graph.WeightedDirected, s, t graph.Node, amount float64, cost func(uid, vid int64) float64) (f Flow, total float64, ok bool) {
+	if amount < 0 || math.IsNaN(amount) {
+		panic("flow: invalid flow amount")
+	}
+	r := newResidual(g)
+	si, ti := r.terminals(s, t)
+	for u, arcs := range r.adj {
+		uid := r.nodes[u].ID()
+		for _, k := range arcs {
+			if r.isEdge(k) {
+				c := cost(uid, r.nodes[r.head[k]].ID())
+				r.cost[k] = c
+				r.cost[k^1] = -c
+			}
+		}
+	}
+
+	n := len(r.nodes)
+	dist := make([]float64, n)
+	via := make([]int, n)
+	var value float64
+	for value < amount {
+		if !r.shortestPath(si, dist, via) {
+			panic("flow: negative cost cycle")
+		}
+		if via[ti] < 0 {
+			break
+		}
+		d := amount - value
+		for v := ti; v != si; v = r.head[via[v]^1] {
+			d = math.Min(d, r.rem[via[v]])
+		}
+		if math.IsInf(d, 1) {
+			panic("flow: infinite capacity path")
+		}
+		for v := ti; v != si; v = r.head[via[v]^1] {
+			r.push(via[v], d)
+		}
+		value += d
+		total += d * dist[ti]
+	}
+	ok = value == amount || math.IsInf(amount, 1)
+	return Flow{source: s, target: t, value: value, res: r}, total, ok
+}
+
+// shortestPath computes the least cost paths from s along arcs with
+// remaining capacity, storing the path costs in dist and the arc used to
+// reach each node in via, or -1 if the node is not reachable. It returns
+// false if a negative cost cycle is reachable from s.
+func (r *residual) shortestPath(s int, dist []float64, via []int) bool {
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		via[i] = -1
+	}
+	dist[s] = 0
+	n := len(r.nodes)
+	for i := 0; i < n; i++ {
+		changed := false
+		for u, arcs := range r.adj {
+			if math.IsInf(dist[u], 1) {
+				continue
+			}
+			for _, k := range arcs {
+				if r.rem[k] <= 0 {
+					continue
+				}
+				v := r.head[k]
+				if d := dist[u] + r.cost[k]; d < dist[v] {
+					dist[v] = d
+					via[v] = k
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			return true
+		}
+	}
+	return false
+}
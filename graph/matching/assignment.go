@@ -0,0 +1,125 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Assignment solves the linear assignment problem for the given cost
+// matrix, returning the assignment of rows to columns that minimizes the
+// total cost and that total cost. The element cost.At(i, j) is the cost of
+// assigning row i to column j and must be finite.
+//
+// If the cost matrix has at least as many columns as rows, every row is
+// assigned to a distinct column and assign[i] holds the column assigned
+// to row i. Otherwise every column is assigned to a distinct row, and
+// assign[i] is -1 for rows that are not assigned.
+//
+// To find an assignment that maximizes the total, negate the cost matrix.
+//
+// Assignment uses the O(r²c) shortest augmenting path algorithm of Jonker
+// and Volgenant for r rows and c columns with r ≤ c, a refinement of the
+// Hungarian method.
+func Assignment(cost mat.Matrix) (assign []int, total float64) {
+	r, c := cost.Dims()
+	if r == 0 || c == 0 {
+		return make([]int, r), 0
+	}
+	transposed := r > c
+	a := cost
+	if transposed {
+		a = cost.T()
+		r, c = c, r
+	}
+
+	// Row and column dual variables, the row assigned to each
+	// column and the column that precedes each column in the
+	// current alternating path. Column c is a virtual column
+	// that holds the row being inserted.
+	u := make([]float64, r)
+	v := make([]float64, c+1)
+	rowOf := make([]int, c+1)
+	for j := range rowOf {
+		rowOf[j] = -1
+	}
+	way := make([]int, c+1)
+	minv := make([]float64, c+1)
+	used := make([]bool, c+1)
+	for i := 0; i < r; i++ {
+		rowOf[c] = i
+		j0 := c
+		for j := range minv {
+			minv[j] = math.Inf(1)
+			used[j] = false
+		}
+		for {
+			used[j0] = true
+			i0 := rowOf[j0]
+			delta := math.Inf(1)
+			j1 := -1
+			for j := 0; j < c; j++ {
+				if used[j] {
+					continue
+				}
+				cur := a.At(i0, j) - u[i0] - v[j]
+				if cur < minv[j] {
+					minv[j] = cur
+					way[j] = j0
+				}
+				if minv[j] < delta {
+					delta = minv[j]
+					j1 = j
+				}
+			}
+			if j1 < 0 {
+				panic("matching: invalid cost")
+			}
+			for j := 0; j <= c; j++ {
+				if used[j] {
+					u[rowOf[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+			if rowOf[j0] < 0 {
+				break
+			}
+		}
+		// Augment along the alternating path.
+		for j0 != c {
+			j1 := way[j0]
+			rowOf[j0] = rowOf[j1]
+			j0 = j1
+		}
+	}
+
+	if transposed {
+		// Rows of a are columns of cost.
+		assign = make([]int, c)
+		for i := range assign {
+			assign[i] = -1
+		}
+		for j := 0; j < c; j++ {
+			if i := rowOf[j]; i >= 0 {
+				assign[j] = i
+				total += a.At(i, j)
+			}
+		}
+		return assign, total
+	}
+	assign = make([]int, r)
+	for j := 0; j < c; j++ {
+		if i := rowOf[j]; i >= 0 {
+			assign[i] = j
+			total += a.At(i, j)
+		}
+	}
+	return assign, total
+}
@@ -0,0 +1,156 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import "gonum.org/v1/gonum/graph"
+
+// Blossom returns a maximum cardinality matching of the undirected
+// graph g.
+//
+// Blossom uses Edmonds' blossom algorithm, finding augmenting paths from
+// each unmatched node and contracting odd cycles, which runs in O(|V|³)
+// time.
+func Blossom(g graph.Undirected) []graph.Edge {
+	x := newIndexed(g)
+	n := len(x.nodes)
+	b := blossom{
+		adj:     x.adj,
+		mate:    make([]int, n),
+		parent:  make([]int, n),
+		base:    make([]int, n),
+		used:    make([]bool, n),
+		inPath:  make([]bool, n),
+		blossom: make([]bool, n),
+	}
+	for i := range b.mate {
+		b.mate[i] = -1
+	}
+
+	// Start from a greedy matching.
+	for u := range b.adj {
+		if b.mate[u] >= 0 {
+			continue
+		}
+		for _, v := range b.adj[u] {
+			if b.mate[v] < 0 {
+				b.mate[u] = v
+				b.mate[v] = u
+				break
+			}
+		}
+	}
+
+	for root := range b.mate {
+		if b.mate[root] >= 0 {
+			continue
+		}
+		// Augment along the path found, if any.
+		for v := b.findPath(root); v >= 0; {
+			pv := b.parent[v]
+			ppv := b.mate[pv]
+			b.mate[v] = pv
+			b.mate[pv] = v
+			v = ppv
+		}
+	}
+	return x.edges(b.mate)
+}
+
+// blossom holds the state for Edmonds' blossom algorithm.
+type blossom struct {
+	adj [][]int
+
+	mate    []int
+	parent  []int
+	base    []int
+	used    []bool
+	inPath  []bool
+	blossom []bool
+	queue   []int
+}
+
+// findPath searches for an augmenting path from the unmatched node root,
+// returning the unmatched node at the end of the path, or -1 if there is
+// no augmenting path. The path is recorded in parent.
+func (b *blossom) findPath(root int) int {
+	for i := range b.used {
+		b.used[i] = false
+		b.parent[i] = -1
+		b.base[i] = i
+	}
+	b.used[root] = true
+	b.queue = append(b.queue[:0], root)
+	for len(b.queue) != 0 {
+		v := b.queue[0]
+		b.queue = b.queue[1:]
+		for _, to := range b.adj[v] {
+			if b.base[v] == b.base[to] || b.mate[v] == to {
+				continue
+			}
+			if to == root || (b.mate[to] >= 0 && b.parent[b.mate[to]] >= 0) {
+				// Found an odd cycle, so contract the blossom.
+				cur := b.lca(v, to)
+				for i := range b.blossom {
+					b.blossom[i] = false
+				}
+				b.markPath(v, cur, to)
+				b.markPath(to, cur, v)
+				for i := range b.base {
+					if b.blossom[b.base[i]] {
+						b.base[i] = cur
+						if !b.used[i] {
+							b.used[i] = true
+							b.queue = append(b.queue, i)
+						}
+					}
+				}
+			} else if b.parent[to] < 0 {
+				b.parent[to] = v
+				if b.mate[to] < 0 {
+					return to
+				}
+				b.used[b.mate[to]] = true
+				b.queue = append(b.queue, b.mate[to])
+			}
+		}
+	}
+	return -1
+}
+
+// lca returns the base of the lowest common ancestor of u and v in the
+// alternating tree.
+func (b *blossom) lca(u, v int) int {
+	for i := range b.inPath {
+		b.inPath[i] = false
+	}
+	for {
+		u = b.base[u]
+		b.inPath[u] = true
+		if b.mate[u] < 0 {
+			break
+		}
+		u = b.parent[b.mate[u]]
+	}
+	for {
+		v = b.base[v]
+		if b.inPath[v] {
+			return v
+		}
+		v = b.parent[b.mate[v]]
+	}
+}
+
+// markPath marks the blossom bases on the path from v to the blossom
+// base and sets the parents along the path to make the blossom
+// traversable in both directions.
+func (b *blossom) markPath(v, base, child int) {
+	for b.base[v] != base {
+		b.blossom[b.base[v]] = true
+		b.blossom[b.base[b.mate[v]]] = true
+		b.parent[v] = child
+		child = b.mate[v]
+		v = b.parent[b.mate[v]]
+	}
+}
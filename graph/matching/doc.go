@@ -0,0 +1,10 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package matching provides graph matching and assignment functions.
+//
+// A matching of a graph is a set of edges without common nodes. The
+// functions in this package return matchings as edge lists with the
+// lower ID node of each edge as the From node, ordered by From node ID.
+package matching // import "gonum.org/v1/gonum/graph/matching"
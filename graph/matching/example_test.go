@@ -0,0 +1,63 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching_test
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/graph/matching"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleAssignment() {
+	// The cost of each of three workers performing each of three jobs.
+	cost := mat.NewDense(3, 3, []float64{
+		4, 1, 3,
+		2, 0, 5,
+		3, 2, 2,
+	})
+	assign, total := matching.Assignment(cost)
+	for worker, job := range assign {
+		fmt.Printf("worker %d does job %d\n", worker, job)
+	}
+	fmt.Println("total cost:", total)
+
+	// Output:
+	// worker 0 does job 1
+	// worker 1 does job 0
+	// worker 2 does job 2
+	// total cost: 5
+}
+
+func ExampleMaxWeight() {
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(0), T: simple.Node(1), W: 5},
+		{F: simple.Node(1), T: simple.Node(2), W: 8},
+		{F: simple.Node(2), T: simple.Node(3), W: 5},
+	} {
+		g.SetWeightedEdge(e)
+	}
+
+	m, w := matching.MaxWeight(g, false)
+	fmt.Println("maximum weight:", w)
+	for _, e := range m {
+		fmt.Printf("%d--%d\n", e.From().ID(), e.To().ID())
+	}
+
+	// The matching can be placed into a new graph.
+	dst := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range m {
+		dst.SetWeightedEdge(e)
+	}
+	fmt.Println("matched nodes:", dst.Nodes().Len())
+
+	// Output:
+	// maximum weight: 10
+	// 0--1
+	// 2--3
+	// matched nodes: 4
+}
@@ -0,0 +1,163 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import (
+	"errors"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// ErrNotBipartite is returned when a bipartite graph is required but
+// the graph is not bipartite.
+var ErrNotBipartite = errors.New("matching: graph is not bipartite")
+
+// indexed is a dense index representation of a graph's nodes and
+// adjacency. Self loops are excluded.
+type indexed struct {
+	nodes   []graph.Node
+	indexOf map[int64]int
+	adj     [][]int
+}
+
+func newIndexed(g graph.Graph) indexed {
+	nodes := graph.NodesOf(g.Nodes())
+	ordered.ByID(nodes)
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	adj := make([][]int, len(nodes))
+	for i, n := range nodes {
+		to := g.From(n.ID())
+		for to.Next() {
+			j := indexOf[to.Node().ID()]
+			if j != i {
+				adj[i] = append(adj[i], j)
+			}
+		}
+	}
+	return indexed{nodes: nodes, indexOf: indexOf, adj: adj}
+}
+
+// edges returns the matching described by mate, where mate[i] is the
+// index of the node matched to node i, or -1 if node i is unmatched.
+func (x indexed) edges(mate []int) []graph.Edge {
+	var m []graph.Edge
+	for i, j := range mate {
+		if i < j {
+			m = append(m, simple.Edge{F: x.nodes[i], T: x.nodes[j]})
+		}
+	}
+	return m
+}
+
+// HopcroftKarp returns a maximum cardinality matching of the bipartite
+// graph g. If g is not bipartite, HopcroftKarp returns ErrNotBipartite.
+//
+// HopcroftKarp uses the Hopcroft–Karp algorithm which runs in
+// O(|E|√|V|) time.
+func HopcroftKarp(g graph.Undirected) ([]graph.Edge, error) {
+	x := newIndexed(g)
+	n := len(x.nodes)
+	side, ok := bipartition(x.adj)
+	if !ok {
+		return nil, ErrNotBipartite
+	}
+
+	const inf = int(^uint(0) >> 1)
+	mate := make([]int, n)
+	for i := range mate {
+		mate[i] = -1
+	}
+	dist := make([]int, n)
+	var queue []int
+
+	// bfs layers the free left nodes and the left nodes reachable
+	// from them by alternating paths, returning whether a free
+	// right node is reachable.
+	bfs := func() bool {
+		queue = queue[:0]
+		for u := range dist {
+			if side[u] && mate[u] < 0 {
+				dist[u] = 0
+				queue = append(queue, u)
+			} else {
+				dist[u] = inf
+			}
+		}
+		found := false
+		for len(queue) != 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, v := range x.adj[u] {
+				w := mate[v]
+				if w < 0 {
+					found = true
+				} else if dist[w] == inf {
+					dist[w] = dist[u] + 1
+					queue = append(queue, w)
+				}
+			}
+		}
+		return found
+	}
+	// dfs finds an augmenting path from the left node u along
+	// the layering and augments the matching along it.
+	var dfs func(u int) bool
+	dfs = func(u int) bool {
+		for _, v := range x.adj[u] {
+			w := mate[v]
+			if w < 0 || (dist[w] == dist[u]+1 && dfs(w)) {
+				mate[u] = v
+				mate[v] = u
+				return true
+			}
+		}
+		dist[u] = inf
+		return false
+	}
+
+	for bfs() {
+		for u := range mate {
+			if side[u] && mate[u] < 0 {
+				dfs(u)
+			}
+		}
+	}
+	return x.edges(mate), nil
+}
+
+// bipartition returns a two-coloring of the graph with the given
+// adjacency and whether the graph is bipartite.
+func bipartition(adj [][]int) (side []bool, ok bool) {
+	side = make([]bool, len(adj))
+	seen := make([]bool, len(adj))
+	var stack []int
+	for s := range adj {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		side[s] = true
+		stack = append(stack[:0], s)
+		for len(stack) != 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, v := range adj[u] {
+				if !seen[v] {
+					seen[v] = true
+					side[v] = !side[u]
+					stack = append(stack, v)
+				} else if side[v] == side[u] {
+					return nil, false
+				}
+			}
+		}
+	}
+	return side, true
+}
@@ -0,0 +1,279 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/mat"
+)
+
+// randomGraph returns a random undirected graph with n nodes and the
+// given edge probability. If bipartite is true, edges only join nodes of
+// different parity.
+func randomGraph(rnd *rand.Rand, n int, p float64, bipartite bool, weight func() float64) *simple.WeightedUndirectedGraph {
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if bipartite && (i+j)%2 == 0 {
+				continue
+			}
+			if rnd.Float64() < p {
+				g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(i), T: simple.Node(j), W: weight()})
+			}
+		}
+	}
+	return g
+}
+
+// bruteMatching returns the maximum cardinality and the maximum weight of
+// a matching in g, and the maximum weight of a maximum cardinality
+// matching.
+func bruteMatching(g *simple.WeightedUndirectedGraph) (card int, weight, cardWeight float64) {
+	nodes := graph.NodesOf(g.Nodes())
+	n := len(nodes)
+	used := make([]bool, n)
+	cardWeight = math.Inf(-1)
+	var search func(i, c int, w float64)
+	search = func(i, c int, w float64) {
+		for i < n && used[i] {
+			i++
+		}
+		if i == n {
+			weight = math.Max(weight, w)
+			if c > card {
+				card = c
+				cardWeight = w
+			} else if c == card {
+				cardWeight = math.Max(cardWeight, w)
+			}
+			return
+		}
+		used[i] = true
+		search(i+1, c, w)
+		for j := i + 1; j < n; j++ {
+			if used[j] {
+				continue
+			}
+			if ew, ok := g.Weight(int64(i), int64(j)); ok && g.HasEdgeBetween(int64(i), int64(j)) {
+				used[j] = true
+				search(i+1, c+1, w+ew)
+				used[j] = false
+			}
+		}
+		used[i] = false
+	}
+	search(0, 0, 0)
+	return card, weight, cardWeight
+}
+
+// checkMatching checks that m is a valid matching of g with edges ordered
+// as documented.
+func checkMatching(t *testing.T, name string, g graph.Undirected, m []graph.Edge) {
+	t.Helper()
+	seen := make(map[int64]bool)
+	for i, e := range m {
+		uid, vid := e.From().ID(), e.To().ID()
+		if !g.HasEdgeBetween(uid, vid) {
+			t.Errorf("%s: matching contains non-existent edge %d--%d", name, uid, vid)
+		}
+		if uid >= vid {
+			t.Errorf("%s: edge not ordered: %d--%d", name, uid, vid)
+		}
+		if i > 0 && m[i-1].From().ID() >= uid {
+			t.Errorf("%s: edges not ordered by From ID", name)
+		}
+		if seen[uid] || seen[vid] {
+			t.Errorf("%s: node matched more than once in edge %d--%d", name, uid, vid)
+		}
+		seen[uid] = true
+		seen[vid] = true
+	}
+}
+
+func TestMaxCardinality(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	unit := func() float64 { return 1 }
+	for i := 0; i < 200; i++ {
+		n := rnd.Intn(13)
+		bipartite := i%2 == 0
+		g := randomGraph(rnd, n, 0.1+0.4*rnd.Float64(), bipartite, unit)
+		want, _, _ := bruteMatching(g)
+
+		m := Blossom(g)
+		checkMatching(t, "Blossom", g, m)
+		if len(m) != want {
+			t.Errorf("Blossom: unexpected matching size for graph %d: got:%d want:%d", i, len(m), want)
+		}
+
+		// Graphs constructed without the bipartite constraint
+		// may still be bipartite.
+		_, isBipartite := bipartition(newIndexed(g).adj)
+		if bipartite && !isBipartite {
+			t.Fatalf("graph %d constructed as bipartite is not bipartite", i)
+		}
+		m, err := HopcroftKarp(g)
+		if !isBipartite {
+			if err != ErrNotBipartite {
+				t.Errorf("HopcroftKarp: unexpected error for graph %d: got:%v want:%v", i, err, ErrNotBipartite)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("HopcroftKarp: unexpected error for graph %d: %v", i, err)
+		}
+		checkMatching(t, "HopcroftKarp", g, m)
+		if len(m) != want {
+			t.Errorf("HopcroftKarp: unexpected matching size for graph %d: got:%d want:%d", i, len(m), want)
+		}
+	}
+}
+
+func TestHopcroftKarpNotBipartite(t *testing.T) {
+	t.Parallel()
+	g := simple.NewUndirectedGraph()
+	for _, e := range [][2]int64{{0, 1}, {1, 2}, {2, 0}, {2, 3}} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	_, err := HopcroftKarp(g)
+	if err != ErrNotBipartite {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrNotBipartite)
+	}
+}
+
+func TestMaxWeight(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		n := rnd.Intn(11)
+		var weight func() float64
+		switch i % 3 {
+		case 0:
+			// Small integer weights produce many ties.
+			weight = func() float64 { return float64(1 + rnd.Intn(4)) }
+		case 1:
+			weight = func() float64 { return float64(rnd.Intn(40) - 10) }
+		case 2:
+			weight = func() float64 { return rnd.Float64() * 10 }
+		}
+		g := randomGraph(rnd, n, 0.2+0.6*rnd.Float64(), false, weight)
+		card, want, wantCard := bruteMatching(g)
+
+		for _, maxCard := range []bool{false, true} {
+			m, w := MaxWeight(g, maxCard)
+			edges := make([]graph.Edge, len(m))
+			var sum float64
+			for k, e := range m {
+				edges[k] = e
+				ew, _ := g.Weight(e.From().ID(), e.To().ID())
+				if e.Weight() != ew {
+					t.Errorf("unexpected edge weight for graph %d: got:%v want:%v", i, e.Weight(), ew)
+				}
+				sum += e.Weight()
+			}
+			checkMatching(t, "MaxWeight", g, edges)
+			if sum != w {
+				t.Errorf("returned weight does not match edge weights for graph %d: got:%v want:%v", i, w, sum)
+			}
+			if maxCard {
+				if len(m) != card {
+					t.Errorf("unexpected matching size for graph %d: got:%d want:%d", i, len(m), card)
+				}
+				if !scalar.EqualWithinAbs(w, wantCard, 1e-10) {
+					t.Errorf("unexpected maximum cardinality matching weight for graph %d: got:%v want:%v", i, w, wantCard)
+				}
+			} else if !scalar.EqualWithinAbs(w, want, 1e-10) {
+				t.Errorf("unexpected maximum weight matching weight for graph %d: got:%v want:%v", i, w, want)
+			}
+		}
+	}
+}
+
+func TestAssignment(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		r := 1 + rnd.Intn(6)
+		c := 1 + rnd.Intn(6)
+		cost := mat.NewDense(r, c, nil)
+		for j := 0; j < r; j++ {
+			for k := 0; k < c; k++ {
+				cost.Set(j, k, float64(rnd.Intn(20)-5))
+			}
+		}
+		assign, total := Assignment(cost)
+		if len(assign) != r {
+			t.Fatalf("unexpected assignment length: got:%d want:%d", len(assign), r)
+		}
+		used := make(map[int]bool)
+		var sum float64
+		var assigned int
+		for j, k := range assign {
+			if k < 0 {
+				continue
+			}
+			if used[k] {
+				t.Errorf("column %d assigned more than once", k)
+			}
+			used[k] = true
+			assigned++
+			sum += cost.At(j, k)
+		}
+		if assigned != min(r, c) {
+			t.Errorf("unexpected number of assignments: got:%d want:%d", assigned, min(r, c))
+		}
+		if sum != total {
+			t.Errorf("returned total does not match assignment: got:%v want:%v", total, sum)
+		}
+		if want := bruteAssignment(cost); total != want {
+			t.Errorf("unexpected total for %d×%d cost matrix: got:%v want:%v", r, c, total, want)
+		}
+	}
+}
+
+// bruteAssignment returns the minimum cost of an assignment for the cost
+// matrix by exhaustive search.
+func bruteAssignment(cost mat.Matrix) float64 {
+	r, c := cost.Dims()
+	if r > c {
+		cost = cost.T()
+		r, c = c, r
+	}
+	used := make([]bool, c)
+	best := math.Inf(1)
+	var search func(i int, sum float64)
+	search = func(i int, sum float64) {
+		if i == r {
+			best = math.Min(best, sum)
+			return
+		}
+		for j := 0; j < c; j++ {
+			if !used[j] {
+				used[j] = true
+				search(i+1, sum+cost.At(i, j))
+				used[j] = false
+			}
+		}
+	}
+	search(0, 0)
+	return best
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,724 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// MaxWeight returns a maximum weight matching of the undirected graph g
+// and the total weight of the matching. If maxCardinality is true, the
+// returned matching is the matching with maximum weight among all maximum
+// cardinality matchings.
+//
+// Edges with non-positive weight are only included in the matching when
+// maxCardinality is true and they are required to obtain a maximum
+// cardinality matching.
+//
+// MaxWeight uses the primal-dual blossom algorithm of Edmonds with the
+// O(|V|³) implementation described by Galil.
+//
+// References:
+//   - Galil, Z. (1986). Efficient algorithms for finding maximum matching
+//     in graphs. ACM Computing Surveys 18(1):23-38.
+func MaxWeight(g graph.WeightedUndirected, maxCardinality bool) (matching []graph.WeightedEdge, weight float64) {
+	x := newIndexed(g)
+	m := newWeightedMatcher(x, g, maxCardinality)
+	m.solve()
+	for v, p := range m.mate {
+		if p < 0 {
+			continue
+		}
+		k := p / 2
+		e := m.edges[k]
+		if v != e.i {
+			continue
+		}
+		// Nodes are ordered by ID, so e.i has the lower ID.
+		matching = append(matching, simple.WeightedEdge{F: x.nodes[e.i], T: x.nodes[e.j], W: e.w})
+		weight += e.w
+	}
+	return matching, weight
+}
+
+type weightedEdge struct {
+	i, j int
+	w    float64
+}
+
+// weightedMatcher holds the state of the maximum weight matching
+// algorithm. Nodes are indexed in [0, n) and non-trivial blossoms in
+// [n, 2n). Each edge k has two endpoints, 2k and 2k+1, referring to
+// edges[k].i and edges[k].j respectively.
+type weightedMatcher struct {
+	n              int
+	edges          []weightedEdge
+	maxCardinality bool
+
+	// endpoint[p] is the node of endpoint p.
+	endpoint []int
+	// neighbend[v] holds the remote endpoints of edges incident to v.
+	neighbend [][]int
+
+	// mate[v] is the remote endpoint of the matched edge of v,
+	// or -1 if v is single.
+	mate []int
+
+	// label is the label of a top-level blossom or node: 0 if
+	// unlabeled, 1 for S-blossoms and 2 for T-blossoms. Bit 4 is
+	// used temporarily while scanning blossoms.
+	label []int
+	// labelend is the remote endpoint of the edge through which a
+	// blossom obtained its label.
+	labelend []int
+
+	inblossom        []int
+	blossomparent    []int
+	blossomchilds    [][]int
+	blossombase      []int
+	blossomendps     [][]int
+	bestedge         []int
+	blossombestedges [][]int
+	hasBestEdges     []bool
+	unusedblossoms   []int
+	dualvar          []float64
+	allowedge        []bool
+	queue            []int
+}
+
+func newWeightedMatcher(x indexed, g graph.Weighted, maxCardinality bool) *weightedMatcher {
+	n := len(x.nodes)
+	m := &weightedMatcher{n: n, maxCardinality: maxCardinality}
+	var maxWeight float64
+	for i, adj := range x.adj {
+		for _, j := range adj {
+			if j <= i {
+				continue
+			}
+			w, _ := g.Weight(x.nodes[i].ID(), x.nodes[j].ID())
+			m.edges = append(m.edges, weightedEdge{i: i, j: j, w: w})
+			maxWeight = math.Max(maxWeight, w)
+		}
+	}
+	m.endpoint = make([]int, 2*len(m.edges))
+	m.neighbend = make([][]int, n)
+	for k, e := range m.edges {
+		m.endpoint[2*k] = e.i
+		m.endpoint[2*k+1] = e.j
+		m.neighbend[e.i] = append(m.neighbend[e.i], 2*k+1)
+		m.neighbend[e.j] = append(m.neighbend[e.j], 2*k)
+	}
+
+	m.mate = make([]int, n)
+	for i := range m.mate {
+		m.mate[i] = -1
+	}
+	m.label = make([]int, 2*n)
+	m.labelend = make([]int, 2*n)
+	m.inblossom = make([]int, n)
+	m.blossomparent = make([]int, 2*n)
+	m.blossomchilds = make([][]int, 2*n)
+	m.blossombase = make([]int, 2*n)
+	m.blossomendps = make([][]int, 2*n)
+	m.bestedge = make([]int, 2*n)
+	m.blossombestedges = make([][]int, 2*n)
+	m.hasBestEdges = make([]bool, 2*n)
+	m.dualvar = make([]float64, 2*n)
+	for i := 0; i < 2*n; i++ {
+		m.labelend[i] = -1
+		m.blossomparent[i] = -1
+		m.bestedge[i] = -1
+		if i < n {
+			m.inblossom[i] = i
+			m.blossombase[i] = i
+			m.dualvar[i] = maxWeight
+		} else {
+			m.blossombase[i] = -1
+			m.unusedblossoms = append(m.unusedblossoms, i)
+		}
+	}
+	m.allowedge = make([]bool, len(m.edges))
+	return m
+}
+
+// slack returns the reduced cost of edge k.
+func (m *weightedMatcher) slack(k int) float64 {
+	e := m.edges[k]
+	return m.dualvar[e.i] + m.dualvar[e.j] - 2*e.w
+}
+
+// leaves returns the nodes contained in blossom b, appended to dst.
+func (m *weightedMatcher) leaves(dst []int, b int) []int {
+	if b < m.n {
+		return append(dst, b)
+	}
+	for _, t := range m.blossomchilds[b] {
+		dst = m.leaves(dst, t)
+	}
+	return dst
+}
+
+// assignLabel assigns label t to the top-level blossom containing node w,
+// reached through the endpoint p.
+func (m *weightedMatcher) assignLabel(w, t, p int) {
+	for {
+		b := m.inblossom[w]
+		m.label[w] = t
+		m.label[b] = t
+		m.labelend[w] = p
+		m.labelend[b] = p
+		m.bestedge[w] = -1
+		m.bestedge[b] = -1
+		if t == 1 {
+			// b became an S-blossom so add its leaves to the queue.
+			m.queue = m.leaves(m.queue, b)
+			return
+		}
+		// b became a T-blossom so label its mate as an S-blossom.
+		base := m.blossombase[b]
+		w, t, p = m.endpoint[m.mate[base]], 1, m.mate[base]^1
+	}
+}
+
+// scanBlossom traces back from nodes v and w to discover either a new
+// blossom, returning its base, or an augmenting path, returning -1.
+func (m *weightedMatcher) scanBlossom(v, w int) int {
+	var path []int
+	base := -1
+	for v != -1 || w != -1 {
+		b := m.inblossom[v]
+		if m.label[b]&4 != 0 {
+			base = m.blossombase[b]
+			break
+		}
+		path = append(path, b)
+		m.label[b] = 5
+		if m.labelend[b] == -1 {
+			// The base of blossom b is single so stop tracing this path.
+			v = -1
+		} else {
+			v = m.endpoint[m.labelend[b]]
+			b = m.inblossom[v]
+			// b is a T-blossom so trace one more step back.
+			v = m.endpoint[m.labelend[b]]
+		}
+		if w != -1 {
+			v, w = w, v
+		}
+	}
+	for _, b := range path {
+		m.label[b] = 1
+	}
+	return base
+}
+
+// addBlossom constructs a new blossom with the given base, through the
+// S-nodes connected by edge k.
+func (m *weightedMatcher) addBlossom(base, k int) {
+	v, w := m.edges[k].i, m.edges[k].j
+	bb := m.inblossom[base]
+	bv := m.inblossom[v]
+	bw := m.inblossom[w]
+
+	b := m.unusedblossoms[len(m.unusedblossoms)-1]
+	m.unusedblossoms = m.unusedblossoms[:len(m.unusedblossoms)-1]
+	m.blossombase[b] = base
+	m.blossomparent[b] = -1
+	m.blossomparent[bb] = b
+
+	// Trace back from v to the base.
+	var path, endps []int
+	for bv != bb {
+		m.blossomparent[bv] = b
+		path = append(path, bv)
+		endps = append(endps, m.labelend[bv])
+		v = m.endpoint[m.labelend[bv]]
+		bv = m.inblossom[v]
+	}
+	path = append(path, bb)
+	reverse(path)
+	reverse(endps)
+	endps = append(endps, 2*k)
+	// Trace back from w to the base.
+	for bw != bb {
+		m.blossomparent[bw] = b
+		path = append(path, bw)
+		endps = append(endps, m.labelend[bw]^1)
+		w = m.endpoint[m.labelend[bw]]
+		bw = m.inblossom[w]
+	}
+	m.blossomchilds[b] = path
+	m.blossomendps[b] = endps
+
+	m.label[b] = 1
+	m.labelend[b] = m.labelend[bb]
+	m.dualvar[b] = 0
+	for _, v := range m.leaves(nil, b) {
+		if m.label[m.inblossom[v]] == 2 {
+			// This T-node became an S-node.
+			m.queue = append(m.queue, v)
+		}
+		m.inblossom[v] = b
+	}
+
+	// Compute the least-slack edges to neighboring S-blossoms.
+	bestedgeto := make([]int, 2*m.n)
+	for i := range bestedgeto {
+		bestedgeto[i] = -1
+	}
+	for _, bv := range path {
+		var nblists [][]int
+		if !m.hasBestEdges[bv] {
+			for _, v := range m.leaves(nil, bv) {
+				ks := make([]int, len(m.neighbend[v]))
+				for i, p := range m.neighbend[v] {
+					ks[i] = p / 2
+				}
+				nblists = append(nblists, ks)
+			}
+		} else {
+			nblists = [][]int{m.blossombestedges[bv]}
+		}
+		for _, nblist := range nblists {
+			for _, k := range nblist {
+				j := m.edges[k].j
+				if m.inblossom[j] == b {
+					j = m.edges[k].i
+				}
+				bj := m.inblossom[j]
+				if bj != b && m.label[bj] == 1 && (bestedgeto[bj] == -1 || m.slack(k) < m.slack(bestedgeto[bj])) {
+					bestedgeto[bj] = k
+				}
+			}
+		}
+		m.blossombestedges[bv] = nil
+		m.hasBestEdges[bv] = false
+		m.bestedge[bv] = -1
+	}
+	var best []int
+	for _, k := range bestedgeto {
+		if k != -1 {
+			best = append(best, k)
+		}
+	}
+	m.blossombestedges[b] = best
+	m.hasBestEdges[b] = true
+	m.bestedge[b] = -1
+	for _, k := range best {
+		if m.bestedge[b] == -1 || m.slack(k) < m.slack(m.bestedge[b]) {
+			m.bestedge[b] = k
+		}
+	}
+}
+
+// expandBlossom expands the top-level blossom b. If endStage is true,
+// sub-blossoms with zero dual are expanded recursively.
+func (m *weightedMatcher) expandBlossom(b int, endStage bool) {
+	for _, s := range m.blossomchilds[b] {
+		m.blossomparent[s] = -1
+		switch {
+		case s < m.n:
+			m.inblossom[s] = s
+		case endStage && m.dualvar[s] == 0:
+			m.expandBlossom(s, endStage)
+		default:
+			for _, v := range m.leaves(nil, s) {
+				m.inblossom[v] = s
+			}
+		}
+	}
+
+	if !endStage && m.label[b] == 2 {
+		// The expanded blossom was a T-blossom so relabel its
+		// sub-blossoms to keep the alternating tree consistent.
+		childs := m.blossomchilds[b]
+		endps := m.blossomendps[b]
+		entrychild := m.inblossom[m.endpoint[m.labelend[b]^1]]
+		j := index(childs, entrychild)
+		var jstep, endptrick int
+		if j&1 != 0 {
+			j -= len(childs)
+			jstep = 1
+			endptrick = 0
+		} else {
+			jstep = -1
+			endptrick = 1
+		}
+		p := m.labelend[b]
+		for j != 0 {
+			// Relabel the T-sub-blossom.
+			m.label[m.endpoint[p^1]] = 0
+			m.label[m.endpoint[at(endps, j-endptrick)^endptrick^1]] = 0
+			m.assignLabel(m.endpoint[p^1], 2, p)
+			// Step to the next S-sub-blossom.
+			m.allowedge[at(endps, j-endptrick)/2] = true
+			j += jstep
+			p = at(endps, j-endptrick) ^ endptrick
+			// Step to the next T-sub-blossom.
+			m.allowedge[p/2] = true
+			j += jstep
+		}
+		// Relabel the base T-sub-blossom without stepping through
+		// to its mate.
+		bv := at(childs, j)
+		m.label[m.endpoint[p^1]] = 2
+		m.label[bv] = 2
+		m.labelend[m.endpoint[p^1]] = p
+		m.labelend[bv] = p
+		m.bestedge[bv] = -1
+		// Continue along the blossom until returning to the
+		// entry child.
+		j += jstep
+		for at(childs, j) != entrychild {
+			bv := at(childs, j)
+			if m.label[bv] == 1 {
+				// This sub-blossom just got label S through one
+				// of its neighbors.
+				j += jstep
+				continue
+			}
+			v := -1
+			for _, l := range m.leaves(nil, bv) {
+				if m.label[l] != 0 {
+					v = l
+					break
+				}
+			}
+			// If the sub-blossom contains a reachable node, assign
+			// label T to the sub-blossom.
+			if v != -1 {
+				m.label[v] = 0
+				m.label[m.endpoint[m.mate[m.blossombase[bv]]]] = 0
+				m.assignLabel(v, 2, m.labelend[v])
+			}
+			j += jstep
+		}
+	}
+
+	// Recycle the blossom number.
+	m.label[b] = -1
+	m.labelend[b] = -1
+	m.blossomchilds[b] = nil
+	m.blossomendps[b] = nil
+	m.blossombase[b] = -1
+	m.blossombestedges[b] = nil
+	m.hasBestEdges[b] = false
+	m.bestedge[b] = -1
+	m.unusedblossoms = append(m.unusedblossoms, b)
+}
+
+// augmentBlossom swaps matched and unmatched edges over an alternating
+// path through blossom b between node v and the base node.
+func (m *weightedMatcher) augmentBlossom(b, v int) {
+	// Find the immediate sub-blossom of b that contains v.
+	t := v
+	for m.blossomparent[t] != b {
+		t = m.blossomparent[t]
+	}
+	if t >= m.n {
+		m.augmentBlossom(t, v)
+	}
+	childs := m.blossomchilds[b]
+	endps := m.blossomendps[b]
+	i := index(childs, t)
+	j := i
+	var jstep, endptrick int
+	if i&1 != 0 {
+		j -= len(childs)
+		jstep = 1
+		endptrick = 0
+	} else {
+		jstep = -1
+		endptrick = 1
+	}
+	for j != 0 {
+		// Step to the next sub-blossom and augment it recursively.
+		j += jstep
+		t = at(childs, j)
+		p := at(endps, j-endptrick) ^ endptrick
+		if t >= m.n {
+			m.augmentBlossom(t, m.endpoint[p])
+		}
+		// Step to the next sub-blossom and augment it recursively.
+		j += jstep
+		t = at(childs, j)
+		if t >= m.n {
+			m.augmentBlossom(t, m.endpoint[p^1])
+		}
+		// Match the edge connecting those sub-blossoms.
+		m.mate[m.endpoint[p]] = p ^ 1
+		m.mate[m.endpoint[p^1]] = p
+	}
+	// Rotate the sub-blossoms so that v's sub-blossom is the base.
+	m.blossomchilds[b] = append(append([]int(nil), childs[i:]...), childs[:i]...)
+	m.blossomendps[b] = append(append([]int(nil), endps[i:]...), endps[:i]...)
+	m.blossombase[b] = m.blossombase[m.blossomchilds[b][0]]
+}
+
+// augmentMatching swaps matched and unmatched edges over an alternating
+// path between two single nodes through edge k.
+func (m *weightedMatcher) augmentMatching(k int) {
+	v, w := m.edges[k].i, m.edges[k].j
+	for _, sp := range [2][2]int{{v, 2*k + 1}, {w, 2 * k}} {
+		s, p := sp[0], sp[1]
+		for {
+			bs := m.inblossom[s]
+			if bs >= m.n {
+				m.augmentBlossom(bs, s)
+			}
+			m.mate[s] = p
+			if m.labelend[bs] == -1 {
+				// Reached a single node.
+				break
+			}
+			t := m.endpoint[m.labelend[bs]]
+			bt := m.inblossom[t]
+			s = m.endpoint[m.labelend[bt]]
+			j := m.endpoint[m.labelend[bt]^1]
+			if bt >= m.n {
+				m.augmentBlossom(bt, j)
+			}
+			m.mate[j] = m.labelend[bt]
+			p = m.labelend[bt] ^ 1
+		}
+	}
+}
+
+func (m *weightedMatcher) solve() {
+	n := m.n
+	for stage := 0; stage < n; stage++ {
+		for i := range m.label {
+			m.label[i] = 0
+			m.bestedge[i] = -1
+		}
+		for i := n; i < 2*n; i++ {
+			m.blossombestedges[i] = nil
+			m.hasBestEdges[i] = false
+		}
+		for i := range m.allowedge {
+			m.allowedge[i] = false
+		}
+		m.queue = m.queue[:0]
+
+		// Label single nodes with S.
+		for v := 0; v < n; v++ {
+			if m.mate[v] == -1 && m.label[m.inblossom[v]] == 0 {
+				m.assignLabel(v, 1, -1)
+			}
+		}
+
+		augmented := false
+		for {
+			// Grow the alternating trees from S-nodes until an
+			// augmenting path is found or no progress is possible.
+			for len(m.queue) != 0 && !augmented {
+				v := m.queue[len(m.queue)-1]
+				m.queue = m.queue[:len(m.queue)-1]
+				for _, p := range m.neighbend[v] {
+					k := p / 2
+					w := m.endpoint[p]
+					if m.inblossom[v] == m.inblossom[w] {
+						// Ignore internal edges of a blossom.
+						continue
+					}
+					var kslack float64
+					if !m.allowedge[k] {
+						kslack = m.slack(k)
+						if kslack <= 0 {
+							m.allowedge[k] = true
+						}
+					}
+					switch {
+					case m.allowedge[k]:
+						switch {
+						case m.label[m.inblossom[w]] == 0:
+							// w is a free node so label it with T
+							// and its mate with S.
+							m.assignLabel(w, 2, p^1)
+						case m.label[m.inblossom[w]] == 1:
+							// w is an S-node so there is either a
+							// new blossom or an augmenting path.
+							base := m.scanBlossom(v, w)
+							if base >= 0 {
+								m.addBlossom(base, k)
+							} else {
+								m.augmentMatching(k)
+								augmented = true
+							}
+						case m.label[w] == 0:
+							// w is inside a T-blossom but has not
+							// been reached from outside the blossom.
+							m.label[w] = 2
+							m.labelend[w] = p ^ 1
+						}
+					case m.label[m.inblossom[w]] == 1:
+						// Keep track of the least-slack non-allowable
+						// edge to a different S-blossom.
+						b := m.inblossom[v]
+						if m.bestedge[b] == -1 || kslack < m.slack(m.bestedge[b]) {
+							m.bestedge[b] = k
+						}
+					case m.label[w] == 0:
+						// w is a free node or an unreached node in a
+						// T-blossom, so keep track of the least-slack
+						// edge that reaches w.
+						if m.bestedge[w] == -1 || kslack < m.slack(m.bestedge[w]) {
+							m.bestedge[w] = k
+						}
+					}
+					if augmented {
+						break
+					}
+				}
+			}
+			if augmented {
+				break
+			}
+
+			// No augmenting path was found so compute the dual
+			// variable update that allows progress.
+			deltatype := -1
+			var delta float64
+			deltaedge, deltablossom := -1, -1
+			if !m.maxCardinality {
+				// The minimum dual variable of a node, which stops
+				// the search when it becomes zero.
+				deltatype = 1
+				delta = math.Inf(1)
+				for _, d := range m.dualvar[:n] {
+					delta = math.Min(delta, d)
+				}
+			}
+			// The minimum slack of edges between S-nodes and free
+			// nodes.
+			for v := 0; v < n; v++ {
+				if m.label[m.inblossom[v]] == 0 && m.bestedge[v] != -1 {
+					d := m.slack(m.bestedge[v])
+					if deltatype == -1 || d < delta {
+						delta = d
+						deltatype = 2
+						deltaedge = m.bestedge[v]
+					}
+				}
+			}
+			// Half the minimum slack of edges between S-blossoms.
+			for b := 0; b < 2*n; b++ {
+				if m.blossomparent[b] == -1 && m.label[b] == 1 && m.bestedge[b] != -1 {
+					d := m.slack(m.bestedge[b]) / 2
+					if deltatype == -1 || d < delta {
+						delta = d
+						deltatype = 3
+						deltaedge = m.bestedge[b]
+					}
+				}
+			}
+			// The minimum dual variable of T-blossoms.
+			for b := n; b < 2*n; b++ {
+				if m.blossombase[b] >= 0 && m.blossomparent[b] == -1 && m.label[b] == 2 &&
+					(deltatype == -1 || m.dualvar[b] < delta) {
+					delta = m.dualvar[b]
+					deltatype = 4
+					deltablossom = b
+				}
+			}
+			if deltatype == -1 {
+				// No further improvement is possible with maximum
+				// cardinality, so do a final delta update to make
+				// the optimum verifiable.
+				deltatype = 1
+				delta = math.Inf(1)
+				for _, d := range m.dualvar[:n] {
+					delta = math.Min(delta, d)
+				}
+				delta = math.Max(0, delta)
+			}
+
+			// Update the dual variables.
+			for v := 0; v < n; v++ {
+				switch m.label[m.inblossom[v]] {
+				case 1:
+					m.dualvar[v] -= delta
+				case 2:
+					m.dualvar[v] += delta
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				if m.blossombase[b] >= 0 && m.blossomparent[b] == -1 {
+					switch m.label[b] {
+					case 1:
+						m.dualvar[b] += delta
+					case 2:
+						m.dualvar[b] -= delta
+					}
+				}
+			}
+
+			// Take action at the point where the minimum delta
+			// occurred.
+			switch deltatype {
+			case 1:
+				// No further improvement is possible.
+			case 2:
+				// Use the least-slack edge to continue the search.
+				m.allowedge[deltaedge] = true
+				i, j := m.edges[deltaedge].i, m.edges[deltaedge].j
+				if m.label[m.inblossom[i]] == 0 {
+					i = j
+				}
+				m.queue = append(m.queue, i)
+				continue
+			case 3:
+				// Use the least-slack edge to continue the search.
+				m.allowedge[deltaedge] = true
+				m.queue = append(m.queue, m.edges[deltaedge].i)
+				continue
+			case 4:
+				// Expand the least-dual blossom.
+				m.expandBlossom(deltablossom, false)
+				continue
+			}
+			break
+		}
+
+		if !augmented {
+			// No further improvement is possible.
+			break
+		}
+
+		// Expand all S-blossoms with zero dual at the end of the
+		// stage.
+		for b := n; b < 2*n; b++ {
+			if m.blossomparent[b] == -1 && m.blossombase[b] >= 0 && m.label[b] == 1 && m.dualvar[b] == 0 {
+				m.expandBlossom(b, true)
+			}
+		}
+	}
+}
+
+// at returns s[i] with negative i indexing from the end of s.
+func at(s []int, i int) int {
+	if i < 0 {
+		i += len(s)
+	}
+	return s[i]
+}
+
+func index(s []int, v int) int {
+	for i, e := range s {
+		if e == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,396 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	bdfMaxOrder      = 5
+	bdfNewtonMaxIter = 4
+
+	// eps is the machine epsilon.
+	eps = 0x1p-52
+)
+
+var (
+	// bdfKappa holds the coefficients of the numerical differentiation
+	// formulas for each order.
+	bdfKappa = [bdfMaxOrder + 1]float64{0, -0.1850, -1.0 / 9, -0.0823, -0.0415, 0}
+
+	bdfGamma, bdfAlpha, bdfErrorConst [bdfMaxOrder + 2]float64
+)
+
+func init() {
+	for k := 1; k <= bdfMaxOrder+1; k++ {
+		bdfGamma[k] = bdfGamma[k-1] + 1/float64(k)
+	}
+	for k := 0; k <= bdfMaxOrder; k++ {
+		bdfAlpha[k] = (1 - bdfKappa[k]) * bdfGamma[k]
+		bdfErrorConst[k] = bdfKappa[k]*bdfGamma[k] + 1/float64(k+1)
+	}
+	bdfErrorConst[bdfMaxOrder+1] = 1 / float64(bdfMaxOrder+2)
+}
+
+// BDF implements an implicit variable order, variable step method based on
+// the backward differentiation formulas of orders 1 to 5, in the
+// quasi-constant step size numerical differentiation formula (NDF)
+// variant. BDF is suitable for stiff problems.
+//
+// The nonlinear system of each step is solved by a simplified Newton
+// iteration using an LU factorization of I - c⋅J where J is the Jacobian
+// of the problem. The Jacobian is taken from Problem.Jac if it is provided
+// and is otherwise approximated using fd.Jacobian. It is only re-evaluated
+// when the Newton iteration fails to converge.
+//
+// References:
+//   - Shampine, L. F., and Reichelt, M. W. (1997). The MATLAB ODE Suite.
+//     SIAM Journal on Scientific Computing, 18(1), 1-22.
+//   - Byrne, G. D., and Hindmarsh, A. C. (1975). A polyalgorithm for the
+//     numerical solution of ordinary differential equations. ACM
+//     Transactions on Mathematical Software, 1(1), 71-96.
+type BDF struct {
+	p           Problem
+	t, tEnd     float64
+	dir         float64
+	h           float64
+	rtol, atol  float64
+	maxStep     float64
+	newtonTol   float64
+	order       int
+	nEqual      int
+	y           []float64
+	d           [bdfMaxOrder + 3][]float64
+	jac         *mat.Dense
+	jacCurrent  bool
+	lu          *mat.LU
+	iter        *mat.Dense
+	initialized bool
+
+	// Workspace.
+	yPredict, yNew, psi, dy, dd, f, scale []float64
+}
+
+// Init implements the Method interface.
+func (m *BDF) Init(p Problem, t0 float64, y0 []float64, tEnd float64, settings *Settings) error {
+	n := len(y0)
+	m.p = p
+	m.t = t0
+	m.tEnd = tEnd
+	m.dir = math.Copysign(1, tEnd-t0)
+	m.rtol = settings.RelTol
+	m.atol = settings.AbsTol
+	m.maxStep = settings.MaxStep
+	m.newtonTol = math.Max(10*eps/m.rtol, math.Min(0.03, math.Sqrt(m.rtol)))
+	m.y = append(m.y[:0], y0...)
+	for i := range m.d {
+		m.d[i] = make([]float64, n)
+	}
+	m.yPredict = make([]float64, n)
+	m.yNew = make([]float64, n)
+	m.psi = make([]float64, n)
+	m.dy = make([]float64, n)
+	m.dd = make([]float64, n)
+	m.f = make([]float64, n)
+	m.scale = make([]float64, n)
+	m.jac = mat.NewDense(n, n, nil)
+	m.iter = mat.NewDense(n, n, nil)
+	m.lu = nil
+
+	p.Func(m.f, t0, m.y)
+	m.h = settings.InitStep
+	if m.h == 0 {
+		m.h = initialStep(p.Func, t0, m.y, m.f, m.dir, 1, m.rtol, m.atol)
+	}
+	m.evalJac(t0, m.y, m.f)
+
+	copy(m.d[0], m.y)
+	floats.ScaleTo(m.d[1], m.h*m.dir, m.f)
+	m.order = 1
+	m.nEqual = 0
+	m.initialized = true
+	return nil
+}
+
+// evalJac evaluates the Jacobian at (t, y) where f holds f(t, y).
+func (m *BDF) evalJac(t float64, y, f []float64) {
+	if m.p.Jac != nil {
+		m.p.Jac(m.jac, t, y)
+	} else {
+		fd.Jacobian(m.jac, func(dy, x []float64) {
+			m.p.Func(dy, t, x)
+		}, y, &fd.JacobianSettings{OriginValue: f})
+	}
+	m.jacCurrent = true
+}
+
+// Step implements the Method interface.
+func (m *BDF) Step() (t float64, y []float64, dense Interpolant, err error) {
+	if !m.initialized {
+		panic("ode: method not initialized")
+	}
+	d := m.d[:]
+	min := minStep(m.t, m.dir)
+	h := m.h
+	switch {
+	case h > m.maxStep:
+		changeD(d, m.order, m.maxStep/h)
+		h = m.maxStep
+		m.nEqual = 0
+		m.lu = nil
+	case h < min:
+		changeD(d, m.order, min/h)
+		h = min
+		m.nEqual = 0
+		m.lu = nil
+	}
+
+	order := m.order
+	var (
+		tNew    float64
+		nIter   int
+		errNorm float64
+	)
+	for {
+		if h < min {
+			return m.t, m.y, nil, ErrStepTooSmall
+		}
+		tNew = m.t + m.dir*h
+		if m.dir*(tNew-m.tEnd) > 0 {
+			tNew = m.tEnd
+			changeD(d, order, math.Abs(tNew-m.t)/h)
+			m.nEqual = 0
+			m.lu = nil
+		}
+		step := tNew - m.t
+		h = math.Abs(step)
+
+		// Predict the solution and set up the corrector.
+		for i := range m.yPredict {
+			var v, psi float64
+			for k := 0; k <= order; k++ {
+				v += d[k][i]
+			}
+			for k := 1; k <= order; k++ {
+				psi += bdfGamma[k] * d[k][i]
+			}
+			m.yPredict[i] = v
+			m.scale[i] = m.atol + m.rtol*math.Abs(v)
+			m.psi[i] = psi / bdfAlpha[order]
+		}
+		c := step / bdfAlpha[order]
+
+		var converged bool
+		for {
+			if m.lu == nil {
+				m.iter.Scale(-c, m.jac)
+				for i := 0; i < len(m.y); i++ {
+					m.iter.Set(i, i, 1+m.iter.At(i, i))
+				}
+				m.lu = &mat.LU{}
+				m.lu.Factorize(m.iter)
+			}
+			converged, nIter = m.newton(tNew, c)
+			if converged || m.jacCurrent {
+				break
+			}
+			m.p.Func(m.f, tNew, m.yPredict)
+			m.evalJac(tNew, m.yPredict, m.f)
+			m.lu = nil
+		}
+		if !converged {
+			h *= 0.5
+			changeD(d, order, 0.5)
+			m.nEqual = 0
+			m.lu = nil
+			continue
+		}
+
+		for i, v := range m.yNew {
+			m.scale[i] = m.atol + m.rtol*math.Abs(v)
+			m.dy[i] = bdfErrorConst[order] * m.dd[i]
+		}
+		errNorm = rmsNorm(m.dy, m.scale)
+		if errNorm <= 1 {
+			break
+		}
+		sf := safety * (2*bdfNewtonMaxIter + 1) / float64(2*bdfNewtonMaxIter+nIter)
+		factor := math.Max(minFactor, sf*math.Pow(errNorm, -1/float64(order+1)))
+		h *= factor
+		changeD(d, order, factor)
+		m.nEqual = 0
+	}
+
+	// The step is accepted.
+	m.nEqual++
+	m.t = tNew
+	m.y, m.yNew = m.yNew, m.y
+	m.h = h
+	m.jacCurrent = false
+
+	// Update the differences. The backward difference of order k+1 of the
+	// new solution is held in m.dd.
+	for i, v := range m.dd {
+		d[order+2][i] = v - d[order+1][i]
+		d[order+1][i] = v
+	}
+	for k := order; k >= 0; k-- {
+		floats.Add(d[k], d[k+1])
+	}
+
+	dense = newBDFInterpolant(m.t, m.dir*h, order, d[:order+1])
+
+	if m.nEqual < order+1 {
+		return m.t, m.y, dense, nil
+	}
+
+	// Select the order and step size for the next step.
+	errM, errP := math.Inf(1), math.Inf(1)
+	if order > 1 {
+		for i := range m.dy {
+			m.dy[i] = bdfErrorConst[order-1] * d[order][i]
+		}
+		errM = rmsNorm(m.dy, m.scale)
+	}
+	if order < bdfMaxOrder {
+		for i := range m.dy {
+			m.dy[i] = bdfErrorConst[order+1] * d[order+2][i]
+		}
+		errP = rmsNorm(m.dy, m.scale)
+	}
+	factors := [3]float64{
+		math.Pow(errM, -1/float64(order)),
+		math.Pow(errNorm, -1/float64(order+1)),
+		math.Pow(errP, -1/float64(order+2)),
+	}
+	best := floats.MaxIdx(factors[:])
+	m.order = order + best - 1
+	sf := safety * (2*bdfNewtonMaxIter + 1) / float64(2*bdfNewtonMaxIter+nIter)
+	factor := math.Min(maxFactor, sf*factors[best])
+	m.h *= factor
+	changeD(d, m.order, factor)
+	m.nEqual = 0
+	m.lu = nil
+	return m.t, m.y, dense, nil
+}
+
+// newton solves the corrector equations of the step to tNew by simplified
+// Newton iteration, storing the solution in m.yNew and its difference from
+// the prediction in m.dd. It returns whether the iteration converged and the
+// number of iterations performed.
+func (m *BDF) newton(tNew, c float64) (converged bool, iter int) {
+	copy(m.yNew, m.yPredict)
+	for i := range m.dd {
+		m.dd[i] = 0
+	}
+	rhs := mat.NewVecDense(len(m.y), nil)
+	dy := mat.NewVecDense(len(m.y), m.dy)
+	var dyNormOld float64
+	for k := 0; k < bdfNewtonMaxIter; k++ {
+		iter = k + 1
+		m.p.Func(m.f, tNew, m.yNew)
+		for i, v := range m.f {
+			if math.IsInf(v, 0) || math.IsNaN(v) {
+				return false, iter
+			}
+			rhs.SetVec(i, c*v-m.psi[i]-m.dd[i])
+		}
+		if err := m.lu.SolveVecTo(dy, false, rhs); err != nil {
+			return false, iter
+		}
+		dyNorm := rmsNorm(m.dy, m.scale)
+		var rate float64
+		if k > 0 {
+			rate = dyNorm / dyNormOld
+			if rate >= 1 || math.Pow(rate, float64(bdfNewtonMaxIter-k))/(1-rate)*dyNorm > m.newtonTol {
+				return false, iter
+			}
+		}
+		floats.Add(m.yNew, m.dy)
+		floats.Add(m.dd, m.dy)
+		if dyNorm == 0 || (k > 0 && rate/(1-rate)*dyNorm < m.newtonTol) {
+			return true, iter
+		}
+		dyNormOld = dyNorm
+	}
+	return false, iter
+}
+
+// changeD rescales the backward differences in d for a change of the step
+// size by factor.
+func changeD(d [][]float64, order int, factor float64) {
+	r := computeR(order, factor)
+	u := computeR(order, 1)
+	var ru mat.Dense
+	ru.Mul(r, u)
+	n := len(d[0])
+	tmp := make([][]float64, order+1)
+	for j := range tmp {
+		tmp[j] = make([]float64, n)
+		for k := 0; k <= order; k++ {
+			floats.AddScaled(tmp[j], ru.At(k, j), d[k])
+		}
+	}
+	for j := range tmp {
+		copy(d[j], tmp[j])
+	}
+}
+
+// computeR returns the matrix used to transform backward differences for
+// a change of step size.
+func computeR(order int, factor float64) *mat.Dense {
+	r := mat.NewDense(order+1, order+1, nil)
+	for j := 0; j <= order; j++ {
+		r.Set(0, j, 1)
+	}
+	for i := 1; i <= order; i++ {
+		for j := 0; j <= order; j++ {
+			var v float64
+			if j > 0 {
+				v = (float64(i) - 1 - factor*float64(j)) / float64(i)
+			}
+			r.Set(i, j, r.At(i-1, j)*v)
+		}
+	}
+	return r
+}
+
+// bdfInterpolant evaluates the polynomial interpolating the solution at
+// the last order+1 steps, represented by its backward differences.
+type bdfInterpolant struct {
+	shift []float64
+	denom []float64
+	d     [][]float64
+}
+
+func newBDFInterpolant(t, h float64, order int, d [][]float64) *bdfInterpolant {
+	b := &bdfInterpolant{
+		shift: make([]float64, order),
+		denom: make([]float64, order),
+		d:     make([][]float64, order+1),
+	}
+	for k := 0; k < order; k++ {
+		b.shift[k] = t - h*float64(k)
+		b.denom[k] = h * float64(k+1)
+	}
+	for k := range b.d {
+		b.d[k] = append([]float64(nil), d[k]...)
+	}
+	return b
+}
+
+func (b *bdfInterpolant) Interpolate(dst []float64, t float64) {
+	copy(dst, b.d[0])
+	p := 1.0
+	for k := range b.shift {
+		p *= (t - b.shift[k]) / b.denom[k]
+		floats.AddScaled(dst, p, b.d[k+1])
+	}
+}
@@ -0,0 +1,13 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ode provides numerical solution of initial value problems for
+// systems of ordinary differential equations.
+//
+// The package provides the explicit adaptive Runge-Kutta method
+// DormandPrince for non-stiff problems and the implicit variable order
+// BDF method for stiff problems. Both methods provide dense output for
+// evaluating the solution between steps and support the detection of
+// events, zero crossings of user-supplied functions of the solution.
+package ode // import "gonum.org/v1/gonum/integrate/ode"
@@ -0,0 +1,206 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// Step size control parameters shared by the methods.
+const (
+	safety    = 0.9
+	minFactor = 0.2
+	maxFactor = 10
+)
+
+// Dormand-Prince 5(4) coefficients.
+var (
+	dopriC = [...]float64{0, 1.0 / 5, 3.0 / 10, 4.0 / 5, 8.0 / 9, 1}
+	dopriA = [...][]float64{
+		{},
+		{1.0 / 5},
+		{3.0 / 40, 9.0 / 40},
+		{44.0 / 45, -56.0 / 15, 32.0 / 9},
+		{19372.0 / 6561, -25360.0 / 2187, 64448.0 / 6561, -212.0 / 729},
+		{9017.0 / 3168, -355.0 / 33, 46732.0 / 5247, 49.0 / 176, -5103.0 / 18656},
+	}
+	dopriB = [...]float64{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84}
+	// dopriE holds the differences between the fifth and fourth order
+	// weights, including the weight of the last (FSAL) stage.
+	dopriE = [...]float64{-71.0 / 57600, 0, 71.0 / 16695, -71.0 / 1920, 17253.0 / 339200, -22.0 / 525, 1.0 / 40}
+	// dopriP holds the coefficients of the fourth order continuous
+	// extension in increasing powers of the normalized step position.
+	dopriP = [...][4]float64{
+		{1, -8048581381.0 / 2820520608, 8663915743.0 / 2820520608, -12715105075.0 / 11282082432},
+		{0, 0, 0, 0},
+		{0, 131558114200.0 / 32700410799, -68118460800.0 / 10900136933, 87487479700.0 / 32700410799},
+		{0, -1754552775.0 / 470086768, 14199869525.0 / 1410260304, -10690763975.0 / 1880347072},
+		{0, 127303824393.0 / 49829197408, -318862633887.0 / 49829197408, 701980252875.0 / 199316789632},
+		{0, -282668133.0 / 205662961, 2019193451.0 / 616988883, -1453857185.0 / 822651844},
+		{0, 40617522.0 / 29380423, -110615467.0 / 29380423, 69997945.0 / 29380423},
+	}
+)
+
+// DormandPrince implements the explicit adaptive Runge-Kutta method of
+// Dormand and Prince of order 5 with an embedded order 4 error estimate,
+// RK45. The dense output is of order 4. DormandPrince is suitable for
+// non-stiff problems.
+//
+// References:
+//   - Dormand, J. R., and Prince, P. J. (1980). A family of embedded
+//     Runge-Kutta formulae. Journal of Computational and Applied
+//     Mathematics, 6(1), 19-26.
+//   - Shampine, L. F. (1986). Some practical Runge-Kutta formulas.
+//     Mathematics of Computation, 46(173), 135-150.
+type DormandPrince struct {
+	f           func(dy []float64, t float64, y []float64)
+	t, tEnd     float64
+	dir         float64
+	h           float64
+	y, yNew     []float64
+	k           [7][]float64
+	work, err   []float64
+	rtol, atol  float64
+	maxStep     float64
+	initialized bool
+}
+
+// Init implements the Method interface.
+func (m *DormandPrince) Init(p Problem, t0 float64, y0 []float64, tEnd float64, settings *Settings) error {
+	n := len(y0)
+	m.f = p.Func
+	m.t = t0
+	m.tEnd = tEnd
+	m.dir = math.Copysign(1, tEnd-t0)
+	m.rtol = settings.RelTol
+	m.atol = settings.AbsTol
+	m.maxStep = settings.MaxStep
+	m.y = append(m.y[:0], y0...)
+	m.yNew = make([]float64, n)
+	for i := range m.k {
+		m.k[i] = make([]float64, n)
+	}
+	m.work = make([]float64, n)
+	m.err = make([]float64, n)
+	m.f(m.k[0], t0, m.y)
+	m.h = settings.InitStep
+	if m.h == 0 {
+		m.h = initialStep(m.f, t0, m.y, m.k[0], m.dir, 4, m.rtol, m.atol)
+	}
+	m.initialized = true
+	return nil
+}
+
+// Step implements the Method interface.
+func (m *DormandPrince) Step() (t float64, y []float64, dense Interpolant, err error) {
+	if !m.initialized {
+		panic("ode: method not initialized")
+	}
+	const exponent = -1.0 / 5
+
+	h := math.Min(m.h, m.maxStep)
+	rejected := false
+	for {
+		if h < minStep(m.t, m.dir) {
+			return m.t, m.y, nil, ErrStepTooSmall
+		}
+		tNew := m.t + m.dir*h
+		if m.dir*(tNew-m.tEnd) > 0 {
+			tNew = m.tEnd
+		}
+		step := tNew - m.t
+		h = math.Abs(step)
+
+		m.stages(step, tNew)
+
+		// Estimate the local error.
+		for i := range m.err {
+			var e float64
+			for s, es := range dopriE {
+				e += es * m.k[s][i]
+			}
+			m.err[i] = step * e
+			m.work[i] = m.atol + m.rtol*math.Max(math.Abs(m.y[i]), math.Abs(m.yNew[i]))
+		}
+		errNorm := rmsNorm(m.err, m.work)
+
+		if errNorm < 1 {
+			factor := float64(maxFactor)
+			if errNorm != 0 {
+				factor = math.Min(maxFactor, safety*math.Pow(errNorm, exponent))
+			}
+			if rejected {
+				factor = math.Min(1, factor)
+			}
+			dense := m.interpolant(step)
+			m.h = h * factor
+			m.t = tNew
+			m.y, m.yNew = m.yNew, m.y
+			m.k[0], m.k[6] = m.k[6], m.k[0]
+			return m.t, m.y, dense, nil
+		}
+		h *= math.Max(minFactor, safety*math.Pow(errNorm, exponent))
+		rejected = true
+	}
+}
+
+// stages computes the Runge-Kutta stages for a step of size h, storing the
+// new state in m.yNew and its derivative in m.k[6].
+func (m *DormandPrince) stages(h, tNew float64) {
+	for s := 1; s < len(dopriC); s++ {
+		copy(m.work, m.y)
+		for j, a := range dopriA[s] {
+			floats.AddScaled(m.work, h*a, m.k[j])
+		}
+		m.f(m.k[s], m.t+dopriC[s]*h, m.work)
+	}
+	copy(m.yNew, m.y)
+	for j, b := range dopriB {
+		if b != 0 {
+			floats.AddScaled(m.yNew, h*b, m.k[j])
+		}
+	}
+	m.f(m.k[6], tNew, m.yNew)
+}
+
+// interpolant returns the continuous extension for the step of size h
+// from m.t.
+func (m *DormandPrince) interpolant(h float64) *dopriInterpolant {
+	n := len(m.y)
+	q := make([][4]float64, n)
+	for i := range q {
+		for s, p := range dopriP {
+			ks := m.k[s][i]
+			if ks == 0 {
+				continue
+			}
+			for j, c := range p {
+				q[i][j] += ks * c
+			}
+		}
+	}
+	return &dopriInterpolant{
+		t: m.t,
+		h: h,
+		y: append([]float64(nil), m.y...),
+		q: q,
+	}
+}
+
+type dopriInterpolant struct {
+	t, h float64
+	y    []float64
+	q    [][4]float64
+}
+
+func (d *dopriInterpolant) Interpolate(dst []float64, t float64) {
+	x := (t - d.t) / d.h
+	for i, q := range d.q {
+		p := x * (q[0] + x*(q[1]+x*(q[2]+x*q[3])))
+		dst[i] = d.y[i] + d.h*p
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode_test
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"gonum.org/v1/gonum/integrate/ode"
+)
+
+func ExampleSolve() {
+	// Exponential decay y' = -y, y(0) = 1.
+	p := ode.Problem{
+		Func: func(dy []float64, _ float64, y []float64) {
+			dy[0] = -y[0]
+		},
+	}
+	res, err := ode.Solve(p, 0, []float64{1}, 2, &ode.DormandPrince{}, &ode.Settings{DenseOutput: true})
+	if err != nil {
+		log.Fatal(err)
+	}
+	y := res.Solution.At(nil, 1)
+	fmt.Printf("y(1) = %.6f, exp(-1) = %.6f\n", y[0], math.Exp(-1))
+
+	// Output:
+	// y(1) = 0.367879, exp(-1) = 0.367879
+}
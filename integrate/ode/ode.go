@@ -0,0 +1,422 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultRelTol   = 1e-6
+	defaultAbsTol   = 1e-9
+	defaultMaxSteps = 100000
+)
+
+var (
+	// ErrStepTooSmall is returned when the step size required to
+	// satisfy the tolerances falls below the resolution of the time
+	// variable.
+	ErrStepTooSmall = errors.New("ode: step size too small")
+
+	// ErrMaxSteps is returned when the integration does not reach the
+	// end of the interval within the maximum number of steps.
+	ErrMaxSteps = errors.New("ode: maximum number of steps reached")
+)
+
+// Problem describes an initial value problem
+//
+//	dy/dt = f(t, y),  y(t₀) = y₀.
+type Problem struct {
+	// Func evaluates f(t, y) and stores the result in dy.
+	Func func(dy []float64, t float64, y []float64)
+
+	// Jac evaluates the Jacobian matrix ∂f/∂y at (t, y) and stores
+	// the result in the len(y)×len(y) matrix dst. Jac is only used by
+	// implicit methods. If Jac is nil, the Jacobian is approximated by
+	// finite differences with fd.Jacobian.
+	Jac func(dst *mat.Dense, t float64, y []float64)
+
+	// Events holds the events to be detected during the integration.
+	Events []Event
+}
+
+// Event describes a zero crossing of a function of the solution.
+type Event struct {
+	// Func returns the value of the event function at (t, y). An event
+	// occurs when Func changes sign.
+	Func func(t float64, y []float64) float64
+
+	// Direction restricts the detected crossings. If Direction is
+	// positive only crossings from negative to positive values are
+	// detected, if it is negative only crossings from positive to
+	// negative values are detected, and if it is zero both are.
+	Direction int
+
+	// Terminal specifies whether the integration stops at the event.
+	Terminal bool
+}
+
+// Settings holds settings for solving an initial value problem.
+type Settings struct {
+	// RelTol and AbsTol are the relative and absolute tolerances for
+	// the local error estimate. A step is accepted when the root mean
+	// square of the error components, each scaled by
+	// AbsTol + RelTol⋅|y_i|, is at most one. If they are zero, defaults
+	// of 1e-6 and 1e-9 are used.
+	RelTol float64
+	AbsTol float64
+
+	// InitStep is the initial step size. If it is zero, the initial
+	// step size is chosen automatically.
+	InitStep float64
+
+	// MaxStep is the maximum allowed step size. If it is zero, the
+	// step size is not limited.
+	MaxStep float64
+
+	// MaxSteps is the maximum number of accepted steps. If it is
+	// zero, a default of 100000 is used.
+	MaxSteps int
+
+	// DenseOutput specifies whether the Result should hold a Solution
+	// for evaluating the solution at any time in the interval.
+	DenseOutput bool
+}
+
+// Method is a step-wise method for solving initial value problems.
+type Method interface {
+	// Init prepares the method for integrating p from the state y0 at t0
+	// towards tEnd. The settings will have been filled with defaults and
+	// the slice y0 must not be retained.
+	Init(p Problem, t0 float64, y0 []float64, tEnd float64, settings *Settings) error
+
+	// Step takes a single accepted step, returning the new time and
+	// state and an Interpolant that is valid between the previous and
+	// the new time. Step must not step beyond tEnd. The returned state
+	// will not be modified by the caller.
+	Step() (t float64, y []float64, dense Interpolant, err error)
+}
+
+// Interpolant evaluates the solution within a single step.
+type Interpolant interface {
+	// Interpolate stores the solution at time t into dst.
+	Interpolate(dst []float64, t float64)
+}
+
+// Stats holds statistics about a solution.
+type Stats struct {
+	// Steps is the number of accepted steps.
+	Steps int
+	// FuncEvaluations is the number of evaluations of Problem.Func,
+	// including those used for finite difference Jacobians.
+	FuncEvaluations int
+	// JacEvaluations is the number of Jacobian evaluations.
+	JacEvaluations int
+}
+
+// EventOccurrence records the detection of an event.
+type EventOccurrence struct {
+	// Index is the index of the event in Problem.Events.
+	Index int
+	// T and Y are the time and state at the event.
+	T float64
+	Y []float64
+}
+
+// Result holds the solution of an initial value problem.
+type Result struct {
+	// T and Y hold the times and states at the initial time and at
+	// the end of each accepted step.
+	T []float64
+	Y [][]float64
+
+	// Events holds the detected events in the order they occurred.
+	Events []EventOccurrence
+
+	// Terminated reports whether the integration was stopped by
+	// a terminal event before reaching the end of the interval.
+	Terminated bool
+
+	// Solution holds the dense output if it was requested
+	// in the Settings.
+	Solution *Solution
+
+	Stats Stats
+}
+
+// Solution is a continuous representation of the solution of an initial
+// value problem, composed of the interpolants of the individual steps.
+type Solution struct {
+	// t holds the step boundaries in increasing order of
+	// integration time.
+	t      []float64
+	interp []Interpolant
+	dir    float64
+	n      int
+}
+
+// Span returns the interval over which the solution is defined.
+func (s *Solution) Span() (t0, t1 float64) {
+	return s.t[0], s.t[len(s.t)-1]
+}
+
+// At stores the solution at time t into dst, allocating a new slice if dst
+// is nil, and returns it. At will panic if t is outside the span of the
+// solution.
+func (s *Solution) At(dst []float64, t float64) []float64 {
+	t0, t1 := s.Span()
+	if s.dir*(t-t0) < 0 || s.dir*(t-t1) > 0 {
+		panic("ode: time outside solution span")
+	}
+	// Find the first step ending at or after t.
+	i := sort.Search(len(s.interp), func(i int) bool {
+		return s.dir*(s.t[i+1]-t) >= 0
+	})
+	if i == len(s.interp) {
+		i--
+	}
+	if dst == nil {
+		dst = make([]float64, s.n)
+	}
+	if len(dst) != s.n {
+		panic("ode: mismatched slice length")
+	}
+	s.interp[i].Interpolate(dst, t)
+	return dst
+}
+
+// Solve integrates the initial value problem p from the state y0 at t0 to
+// tEnd using the given method. If method is nil, DormandPrince is used.
+// Integration backward in time is performed if tEnd is less than t0.
+//
+// Solve returns the solution obtained so far and a non-nil error if the
+// method fails, or ErrMaxSteps if the maximum number of steps is reached.
+func Solve(p Problem, t0 float64, y0 []float64, tEnd float64, method Method, settings *Settings) (*Result, error) {
+	n := len(y0)
+	if n == 0 {
+		panic("ode: zero-length initial state")
+	}
+	if p.Func == nil {
+		panic("ode: nil Func")
+	}
+	if t0 == tEnd {
+		panic("ode: empty integration interval")
+	}
+	if method == nil {
+		method = &DormandPrince{}
+	}
+	var s Settings
+	if settings != nil {
+		s = *settings
+	}
+	if s.RelTol == 0 {
+		s.RelTol = defaultRelTol
+	}
+	if s.AbsTol == 0 {
+		s.AbsTol = defaultAbsTol
+	}
+	if s.MaxStep == 0 {
+		s.MaxStep = math.Inf(1)
+	}
+	if s.MaxSteps == 0 {
+		s.MaxSteps = defaultMaxSteps
+	}
+	if s.RelTol < 0 || s.AbsTol < 0 || s.MaxStep < 0 || s.InitStep < 0 {
+		panic("ode: negative setting")
+	}
+
+	res := &Result{
+		T: []float64{t0},
+		Y: [][]float64{append([]float64(nil), y0...)},
+	}
+	// Wrap the problem functions to count evaluations.
+	counted := p
+	counted.Func = func(dy []float64, t float64, y []float64) {
+		res.Stats.FuncEvaluations++
+		p.Func(dy, t, y)
+	}
+	if p.Jac != nil {
+		counted.Jac = func(dst *mat.Dense, t float64, y []float64) {
+			res.Stats.JacEvaluations++
+			p.Jac(dst, t, y)
+		}
+	}
+	dir := 1.0
+	if tEnd < t0 {
+		dir = -1
+	}
+	if s.DenseOutput {
+		res.Solution = &Solution{t: []float64{t0}, dir: dir, n: n}
+	}
+
+	err := method.Init(counted, t0, y0, tEnd, &s)
+	if err != nil {
+		return res, err
+	}
+
+	g := make([]float64, len(p.Events))
+	for i, e := range p.Events {
+		g[i] = e.Func(t0, y0)
+	}
+	gNew := make([]float64, len(p.Events))
+
+	tOld := t0
+	yEvent := make([]float64, n)
+	for res.Stats.Steps < s.MaxSteps {
+		t, y, dense, err := method.Step()
+		if err != nil {
+			return res, err
+		}
+		res.Stats.Steps++
+
+		if len(p.Events) != 0 {
+			for i, e := range p.Events {
+				gNew[i] = e.Func(t, y)
+			}
+			events := findEvents(p.Events, g, gNew, tOld, t, dense, yEvent)
+			g, gNew = gNew, g
+			for _, ev := range events {
+				res.Events = append(res.Events, ev)
+				if p.Events[ev.Index].Terminal {
+					res.Terminated = true
+					t, y = ev.T, ev.Y
+					break
+				}
+			}
+		}
+
+		res.T = append(res.T, t)
+		res.Y = append(res.Y, append([]float64(nil), y...))
+		if res.Solution != nil {
+			res.Solution.t = append(res.Solution.t, t)
+			res.Solution.interp = append(res.Solution.interp, dense)
+		}
+		if res.Terminated || t == tEnd {
+			return res, nil
+		}
+		tOld = t
+	}
+	return res, ErrMaxSteps
+}
+
+// findEvents returns the events occurring within the step from t0 to t1
+// in order of occurrence, given the values g0 and g1 of the event
+// functions at the ends of the step.
+func findEvents(events []Event, g0, g1 []float64, t0, t1 float64, dense Interpolant, work []float64) []EventOccurrence {
+	var found []EventOccurrence
+	for i, e := range events {
+		up := g0[i] < 0 && g1[i] >= 0
+		down := g0[i] > 0 && g1[i] <= 0
+		if !(up && e.Direction >= 0 || down && e.Direction <= 0) {
+			continue
+		}
+		f := func(t float64) float64 {
+			dense.Interpolate(work, t)
+			return e.Func(t, work)
+		}
+		t := findRoot(f, t0, t1, g0[i], g1[i])
+		y := make([]float64, len(work))
+		dense.Interpolate(y, t)
+		found = append(found, EventOccurrence{Index: i, T: t, Y: y})
+	}
+	dir := math.Copysign(1, t1-t0)
+	sort.SliceStable(found, func(i, j int) bool {
+		return dir*found[i].T < dir*found[j].T
+	})
+	return found
+}
+
+// findRoot returns a root of f in the interval between a and b, where f(a)
+// and f(b) have different signs, using the Illinois variant of the regula
+// falsi method.
+func findRoot(f func(float64) float64, a, b, fa, fb float64) float64 {
+	if fb == 0 {
+		return b
+	}
+	const maxIter = 100
+	tol := 4 * math.Max(math.Abs(a), math.Abs(b)) * 0x1p-53
+	side := 0
+	for i := 0; i < maxIter && math.Abs(b-a) > tol; i++ {
+		c := (a*fb - b*fa) / (fb - fa)
+		if c == a || c == b || math.IsNaN(c) {
+			c = a + (b-a)/2
+		}
+		fc := f(c)
+		switch {
+		case fc == 0:
+			return c
+		case math.Signbit(fc) == math.Signbit(fb):
+			b, fb = c, fc
+			if side == -1 {
+				fa /= 2
+			}
+			side = -1
+		default:
+			a, fa = c, fc
+			if side == 1 {
+				fb /= 2
+			}
+			side = 1
+		}
+	}
+	return b
+}
+
+// rmsNorm returns the root mean square of x_i/scale_i.
+func rmsNorm(x, scale []float64) float64 {
+	var sum float64
+	for i, v := range x {
+		v /= scale[i]
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(len(x)))
+}
+
+// initialStep returns an initial step size for a method of the given
+// order, following Hairer, Nørsett and Wanner.
+//
+// References:
+//   - Hairer, E., Nørsett, S. P., and Wanner, G. (1993). Solving Ordinary
+//     Differential Equations I: Nonstiff Problems (2nd ed.). Springer.
+//     Section II.4, p. 169.
+func initialStep(f func(dy []float64, t float64, y []float64), t0 float64, y0, f0 []float64, dir float64, order int, rtol, atol float64) float64 {
+	n := len(y0)
+	scale := make([]float64, n)
+	for i, v := range y0 {
+		scale[i] = atol + rtol*math.Abs(v)
+	}
+	d0 := rmsNorm(y0, scale)
+	d1 := rmsNorm(f0, scale)
+	var h0 float64
+	if d0 < 1e-5 || d1 < 1e-5 {
+		h0 = 1e-6
+	} else {
+		h0 = 0.01 * d0 / d1
+	}
+	y1 := make([]float64, n)
+	floats.AddScaledTo(y1, y0, h0*dir, f0)
+	f1 := make([]float64, n)
+	f(f1, t0+h0*dir, y1)
+	floats.Sub(f1, f0)
+	d2 := rmsNorm(f1, scale) / h0
+
+	var h1 float64
+	if d1 <= 1e-15 && d2 <= 1e-15 {
+		h1 = math.Max(1e-6, h0*1e-3)
+	} else {
+		h1 = math.Pow(0.01/math.Max(d1, d2), 1/float64(order+1))
+	}
+	return math.Min(100*h0, h1)
+}
+
+// minStep returns the smallest allowed step size at t.
+func minStep(t, dir float64) float64 {
+	return 10 * math.Abs(math.Nextafter(t, dir*math.Inf(1))-t)
+}
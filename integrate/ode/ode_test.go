@@ -0,0 +1,239 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+// oscillator is the harmonic oscillator d²y/dt² = -y with y(0) = 0 and
+// dy/dt(0) = 1, with solution y = sin(t).
+var oscillator = Problem{
+	Func: func(dy []float64, _ float64, y []float64) {
+		dy[0] = y[1]
+		dy[1] = -y[0]
+	},
+	Jac: func(dst *mat.Dense, _ float64, _ []float64) {
+		dst.Set(0, 0, 0)
+		dst.Set(0, 1, 1)
+		dst.Set(1, 0, -1)
+		dst.Set(1, 1, 0)
+	},
+}
+
+// stiffLinear returns the stiff linear problem y' = -λ(y - cos(t)) with
+// y(0) = 0.
+func stiffLinear(lambda float64) (p Problem, exact func(float64) float64) {
+	p = Problem{
+		Func: func(dy []float64, t float64, y []float64) {
+			dy[0] = -lambda * (y[0] - math.Cos(t))
+		},
+	}
+	exact = func(t float64) float64 {
+		l2 := lambda * lambda
+		return l2/(1+l2)*math.Cos(t) + lambda/(1+l2)*math.Sin(t) - l2/(1+l2)*math.Exp(-lambda*t)
+	}
+	return p, exact
+}
+
+func methods() []struct {
+	name string
+	new  func() Method
+} {
+	return []struct {
+		name string
+		new  func() Method
+	}{
+		{name: "DormandPrince", new: func() Method { return &DormandPrince{} }},
+		{name: "BDF", new: func() Method { return &BDF{} }},
+	}
+}
+
+func TestSolveOscillator(t *testing.T) {
+	t.Parallel()
+	for _, m := range methods() {
+		for _, tEnd := range []float64{10, -10} {
+			settings := &Settings{RelTol: 1e-8, AbsTol: 1e-10, DenseOutput: true}
+			res, err := Solve(oscillator, 0, []float64{0, 1}, tEnd, m.new(), settings)
+			if err != nil {
+				t.Fatalf("%s to %v: unexpected error: %v", m.name, tEnd, err)
+			}
+			if res.T[len(res.T)-1] != tEnd {
+				t.Errorf("%s to %v: did not reach end: got:%v", m.name, tEnd, res.T[len(res.T)-1])
+			}
+			if len(res.T) != res.Stats.Steps+1 || len(res.Y) != len(res.T) {
+				t.Errorf("%s to %v: mismatched output lengths", m.name, tEnd)
+			}
+			tol := 1e-6
+			for i, ti := range res.T {
+				if !scalar.EqualWithinAbs(res.Y[i][0], math.Sin(ti), tol) || !scalar.EqualWithinAbs(res.Y[i][1], math.Cos(ti), tol) {
+					t.Errorf("%s to %v: unexpected solution at t=%v: got:%v want:[%v %v]",
+						m.name, tEnd, ti, res.Y[i], math.Sin(ti), math.Cos(ti))
+					break
+				}
+			}
+
+			// Check dense output away from the step boundaries.
+			y := make([]float64, 2)
+			for k := 0; k <= 100; k++ {
+				ti := tEnd * float64(k) / 100
+				res.Solution.At(y, ti)
+				if !scalar.EqualWithinAbs(y[0], math.Sin(ti), 10*tol) {
+					t.Errorf("%s to %v: unexpected dense output at t=%v: got:%v want:%v", m.name, tEnd, ti, y[0], math.Sin(ti))
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestSolveStiff(t *testing.T) {
+	t.Parallel()
+	const lambda = 1e4
+	p, exact := stiffLinear(lambda)
+	var steps [2]int
+	for i, m := range methods() {
+		res, err := Solve(p, 0, []float64{0}, 2, m.new(), &Settings{RelTol: 1e-6, AbsTol: 1e-8})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", m.name, err)
+		}
+		got := res.Y[len(res.Y)-1][0]
+		if want := exact(2); !scalar.EqualWithinAbs(got, want, 1e-5) {
+			t.Errorf("%s: unexpected solution: got:%v want:%v", m.name, got, want)
+		}
+		steps[i] = res.Stats.Steps
+	}
+	if steps[1] >= steps[0]/10 {
+		t.Errorf("BDF not efficient on stiff problem: BDF steps=%d DormandPrince steps=%d", steps[1], steps[0])
+	}
+}
+
+func TestBDFJacobian(t *testing.T) {
+	t.Parallel()
+	// Robertson's chemical kinetics problem.
+	p := Problem{
+		Func: func(dy []float64, _ float64, y []float64) {
+			dy[0] = -0.04*y[0] + 1e4*y[1]*y[2]
+			dy[1] = 0.04*y[0] - 1e4*y[1]*y[2] - 3e7*y[1]*y[1]
+			dy[2] = 3e7 * y[1] * y[1]
+		},
+		Jac: func(dst *mat.Dense, _ float64, y []float64) {
+			dst.Set(0, 0, -0.04)
+			dst.Set(0, 1, 1e4*y[2])
+			dst.Set(0, 2, 1e4*y[1])
+			dst.Set(1, 0, 0.04)
+			dst.Set(1, 1, -1e4*y[2]-6e7*y[1])
+			dst.Set(1, 2, -1e4*y[1])
+			dst.Set(2, 0, 0)
+			dst.Set(2, 1, 6e7*y[1])
+			dst.Set(2, 2, 0)
+		},
+	}
+	y0 := []float64{1, 0, 0}
+	settings := &Settings{RelTol: 1e-6, AbsTol: 1e-10}
+	withJac, err := Solve(p, 0, y0, 40, &BDF{}, settings)
+	if err != nil {
+		t.Fatalf("unexpected error with Jacobian: %v", err)
+	}
+	p.Jac = nil
+	withoutJac, err := Solve(p, 0, y0, 40, &BDF{}, settings)
+	if err != nil {
+		t.Fatalf("unexpected error without Jacobian: %v", err)
+	}
+	if withJac.Stats.JacEvaluations == 0 {
+		t.Error("Jacobian function not used")
+	}
+	if withoutJac.Stats.JacEvaluations != 0 {
+		t.Error("unexpected Jacobian evaluations")
+	}
+	// Reference values at t = 40 from Hairer and Wanner.
+	want := []float64{0.7158270687, 0.9185534764e-5, 0.2841637457}
+	for _, res := range []*Result{withJac, withoutJac} {
+		y := res.Y[len(res.Y)-1]
+		for i := range y {
+			if !scalar.EqualWithinRel(y[i], want[i], 1e-3) {
+				t.Errorf("unexpected solution: got:%v want:%v", y, want)
+				break
+			}
+		}
+		if sum := y[0] + y[1] + y[2]; !scalar.EqualWithinAbs(sum, 1, 1e-8) {
+			t.Errorf("mass not conserved: got:%v", sum)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	t.Parallel()
+	for _, m := range methods() {
+		// Falling body stopped when reaching the ground.
+		const g = 9.81
+		ball := Problem{
+			Func: func(dy []float64, _ float64, y []float64) {
+				dy[0] = y[1]
+				dy[1] = -g
+			},
+			Events: []Event{{
+				Func:      func(_ float64, y []float64) float64 { return y[0] },
+				Direction: -1,
+				Terminal:  true,
+			}},
+		}
+		res, err := Solve(ball, 0, []float64{10, 0}, 100, m.new(), &Settings{RelTol: 1e-8, AbsTol: 1e-10})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", m.name, err)
+		}
+		if !res.Terminated || len(res.Events) != 1 {
+			t.Fatalf("%s: terminal event not detected", m.name)
+		}
+		want := math.Sqrt(2 * 10 / g)
+		if got := res.Events[0].T; !scalar.EqualWithinAbs(got, want, 1e-6) {
+			t.Errorf("%s: unexpected event time: got:%v want:%v", m.name, got, want)
+		}
+		if last := res.T[len(res.T)-1]; last != res.Events[0].T {
+			t.Errorf("%s: integration not stopped at event: got:%v want:%v", m.name, last, res.Events[0].T)
+		}
+
+		// Zero crossings of sin(t) in (0, 10).
+		p := oscillator
+		p.Events = []Event{
+			{Func: func(_ float64, y []float64) float64 { return y[0] }},
+			{Func: func(_ float64, y []float64) float64 { return y[0] }, Direction: 1},
+		}
+		res, err = Solve(p, 0, []float64{0, 1}, 10, m.new(), &Settings{RelTol: 1e-8, AbsTol: 1e-10})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", m.name, err)
+		}
+		var got [2][]float64
+		for _, e := range res.Events {
+			got[e.Index] = append(got[e.Index], e.T)
+		}
+		for i, want := range [][]float64{{math.Pi, 2 * math.Pi, 3 * math.Pi}, {2 * math.Pi}} {
+			if len(got[i]) != len(want) {
+				t.Errorf("%s: unexpected number of events for event %d: got:%v want:%v", m.name, i, got[i], want)
+				continue
+			}
+			for k := range want {
+				if !scalar.EqualWithinAbs(got[i][k], want[k], 1e-6) {
+					t.Errorf("%s: unexpected event time: got:%v want:%v", m.name, got[i][k], want[k])
+				}
+			}
+		}
+	}
+}
+
+func TestMaxSteps(t *testing.T) {
+	t.Parallel()
+	res, err := Solve(oscillator, 0, []float64{0, 1}, 100, nil, &Settings{MaxSteps: 5})
+	if err != ErrMaxSteps {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrMaxSteps)
+	}
+	if res.Stats.Steps != 5 {
+		t.Errorf("unexpected number of steps: got:%d want:5", res.Stats.Steps)
+	}
+}
@@ -0,0 +1,781 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import (
+	"errors"
+	"math"
+	"sync"
+)
+
+// Errors returned by Adaptive. When one of these errors is returned, the
+// AdaptiveResult holds the best estimate of the integral found.
+var (
+	// ErrSubdivisionLimit is returned when the maximum number of
+	// subintervals is reached before the requested tolerance.
+	ErrSubdivisionLimit = errors.New("quad: maximum number of subintervals reached")
+
+	// ErrRoundoff is returned when roundoff error prevents the
+	// requested tolerance from being achieved.
+	ErrRoundoff = errors.New("quad: roundoff error prevents reaching tolerance")
+
+	// ErrBadIntegrand is returned when the integrand behaves so badly
+	// that subintervals become too small to be subdivided.
+	ErrBadIntegrand = errors.New("quad: bad integrand behavior")
+
+	// ErrNoConvergence is returned when the extrapolation does not
+	// converge due to roundoff error in the extrapolation table.
+	ErrNoConvergence = errors.New("quad: extrapolation does not converge")
+
+	// ErrDivergent is returned when the integral is probably divergent
+	// or converges too slowly to be integrated.
+	ErrDivergent = errors.New("quad: integral is divergent or slowly convergent")
+)
+
+// AdaptiveSettings holds the parameters for adaptive integration.
+type AdaptiveSettings struct {
+	// AbsTol and RelTol are the absolute and relative tolerances.
+	// Integration stops when the estimated absolute error is at most
+	// max(AbsTol, RelTol*|integral|). If both are zero, they default
+	// to 1.49e-8. RelTol is increased to 50 times the machine epsilon
+	// if it is positive and smaller.
+	AbsTol, RelTol float64
+
+	// Points is the number of points of the Gauss–Kronrod rule used
+	// on each subinterval, either 15 for the 7-point Gauss and 15-point
+	// Kronrod rule or 21 for the 10-point Gauss and 21-point Kronrod
+	// rule. If Points is zero, 21 is used for finite intervals and 15
+	// for infinite intervals.
+	Points int
+
+	// Limit is the maximum number of subintervals. If Limit is zero,
+	// it defaults to 50.
+	Limit int
+}
+
+// AdaptiveResult holds the result of an adaptive integration.
+type AdaptiveResult struct {
+	// Value is the estimate of the integral.
+	Value float64
+
+	// AbsError is the estimated bound on the absolute error
+	// of Value.
+	AbsError float64
+
+	// Evaluations is the number of evaluations of the integrand.
+	Evaluations int
+
+	// Subintervals is the number of subintervals used.
+	Subintervals int
+}
+
+// Adaptive approximates the integral of the function f from min to max
+// using globally adaptive Gauss–Kronrod quadrature, returning the estimate
+// with a bound on its absolute error. The interval with the largest error
+// estimate is bisected until the error tolerance in settings is met, and
+// the sequence of estimates is accelerated by the epsilon algorithm of
+// Wynn, allowing integrable singularities at the end points and within
+// the interval to be handled.
+//
+// Infinite bounds are handled by transforming the integral onto (0, 1]
+// with x = a + (1-t)/t, so min and max may be infinite. If settings is
+// nil, default settings are used.
+//
+// If concurrent <= 0, f is evaluated serially, while if concurrent > 0, f
+// may be evaluated with at most concurrent simultaneous evaluations.
+//
+// If the tolerance can not be achieved, Adaptive returns the best estimate
+// found and one of ErrSubdivisionLimit, ErrRoundoff, ErrBadIntegrand,
+// ErrNoConvergence or ErrDivergent.
+//
+// min must be less than or equal to max, otherwise Adaptive will panic.
+//
+// Adaptive is a translation of the QAGS and QAGI routines of QUADPACK.
+//
+// References:
+//   - Piessens, R., de Doncker-Kapenga, E., Überhuber, C. W. and Kahaner, D. K.
+//     (1983). QUADPACK: A Subroutine Package for Automatic Integration.
+//     Springer-Verlag.
+func Adaptive(f func(float64) float64, min, max float64, settings *AdaptiveSettings, concurrent int) (AdaptiveResult, error) {
+	if min > max {
+		panic("quad: min > max")
+	}
+	var s AdaptiveSettings
+	if settings != nil {
+		s = *settings
+	}
+	if s.AbsTol < 0 || s.RelTol < 0 {
+		panic("quad: negative tolerance")
+	}
+	if s.AbsTol == 0 && s.RelTol == 0 {
+		s.AbsTol = 1.49e-8
+		s.RelTol = 1.49e-8
+	}
+	if s.RelTol > 0 {
+		s.RelTol = math.Max(s.RelTol, 50*epmach)
+	}
+	if s.Limit == 0 {
+		s.Limit = 50
+	}
+	if s.Limit < 0 {
+		panic("quad: negative subinterval limit")
+	}
+	infinite := math.IsInf(min, 0) || math.IsInf(max, 0)
+	var rule kronrod
+	switch s.Points {
+	case 0:
+		if infinite {
+			rule = gk15
+		} else {
+			rule = gk21
+		}
+	case 15:
+		rule = gk15
+	case 21:
+		rule = gk21
+	default:
+		panic("quad: invalid number of points")
+	}
+	if min == max {
+		return AdaptiveResult{}, nil
+	}
+
+	// Each evaluation of the transformed integrand costs perEval
+	// evaluations of f.
+	intfunc := f
+	perEval := 1
+	if infinite {
+		min, max, intfunc, perEval = infiniteTransform(f, min, max)
+	}
+
+	a := &adaptive{
+		f:          intfunc,
+		rule:       rule,
+		concurrent: concurrent,
+		absTol:     s.AbsTol,
+		relTol:     s.RelTol,
+		limit:      s.Limit,
+	}
+	value, abserr, err := a.integrate(min, max)
+	return AdaptiveResult{
+		Value:        value,
+		AbsError:     abserr,
+		Evaluations:  a.evals * perEval,
+		Subintervals: len(a.alist),
+	}, err
+}
+
+// infiniteTransform returns the transformation of the integral of f over
+// an infinite interval onto (0, 1] and the number of evaluations of f
+// per evaluation of the transformed integrand.
+func infiniteTransform(f func(float64) float64, min, max float64) (a, b float64, g func(float64) float64, perEval int) {
+	switch {
+	case math.IsInf(min, -1) && math.IsInf(max, 1):
+		// int_-∞^∞ f(x) dx = int_0^1 (f(x)+f(-x))/t² dt with x = (1-t)/t.
+		return 0, 1, func(t float64) float64 {
+			x := (1 - t) / t
+			return (f(x) + f(-x)) / t / t
+		}, 2
+	case math.IsInf(max, 1):
+		// int_a^∞ f(x) dx = int_0^1 f(a+(1-t)/t)/t² dt.
+		return 0, 1, func(t float64) float64 {
+			return f(min+(1-t)/t) / t / t
+		}, 1
+	case math.IsInf(min, -1):
+		// int_-∞^b f(x) dx = int_0^1 f(b-(1-t)/t)/t² dt.
+		return 0, 1, func(t float64) float64 {
+			return f(max-(1-t)/t) / t / t
+		}, 1
+	default:
+		panic("quad: invalid infinite interval")
+	}
+}
+
+const (
+	epmach = 0x1p-52
+	uflow  = 0x1p-1022
+	oflow  = math.MaxFloat64
+)
+
+// adaptive holds the state of the globally adaptive integration.
+type adaptive struct {
+	f          func(float64) float64
+	rule       kronrod
+	concurrent int
+	absTol     float64
+	relTol     float64
+	limit      int
+
+	// alist, blist, rlist and elist hold the end points, integral
+	// estimates and error estimates of the subintervals.
+	alist, blist, rlist, elist []float64
+
+	// iord holds the subinterval indices ordered by decreasing
+	// error estimate.
+	iord []int
+
+	// maxerr is the index of the subinterval with the nrmax-th
+	// largest error estimate and errmax is its error estimate.
+	maxerr int
+	errmax float64
+	nrmax  int
+
+	evals int
+	x, fv []float64
+}
+
+// evaluate returns the Kronrod estimates for each of the given intervals,
+// evaluating the integrand concurrently if requested.
+func (a *adaptive) evaluate(bounds ...[2]float64) (result, abserr, resabs, resasc []float64) {
+	n := a.rule.points()
+	m := n * len(bounds)
+	if cap(a.x) < m {
+		a.x = make([]float64, m)
+		a.fv = make([]float64, m)
+	}
+	x := a.x[:m]
+	fv := a.fv[:m]
+	for i, b := range bounds {
+		a.rule.locations(x[i*n:(i+1)*n], b[0], b[1])
+	}
+	evaluate(a.f, fv, x, a.concurrent)
+	a.evals += m
+
+	result = make([]float64, len(bounds))
+	abserr = make([]float64, len(bounds))
+	resabs = make([]float64, len(bounds))
+	resasc = make([]float64, len(bounds))
+	for i, b := range bounds {
+		result[i], abserr[i], resabs[i], resasc[i] = a.rule.estimate(fv[i*n:(i+1)*n], b[0], b[1])
+	}
+	return result, abserr, resabs, resasc
+}
+
+// evaluate stores f(x[i]) into dst[i], with at most concurrent
+// simultaneous evaluations if concurrent > 0.
+func evaluate(f func(float64) float64, dst, x []float64, concurrent int) {
+	if concurrent <= 0 {
+		for i, v := range x {
+			dst[i] = f(v)
+		}
+		return
+	}
+	if concurrent > len(x) {
+		concurrent = len(x)
+	}
+	tasks := make(chan int)
+	go func() {
+		for i := range x {
+			tasks <- i
+		}
+		close(tasks)
+	}()
+	var wg sync.WaitGroup
+	wg.Add(concurrent)
+	for i := 0; i < concurrent; i++ {
+		go func() {
+			defer wg.Done()
+			for k := range tasks {
+				dst[k] = f(x[k])
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// integrate is a translation of the DQAGSE routine of QUADPACK.
+func (a *adaptive) integrate(lower, upper float64) (result, abserr float64, err error) {
+	var ier int
+
+	// First approximation to the integral.
+	r, e, ra, rc := a.evaluate([2]float64{lower, upper})
+	result, abserr = r[0], e[0]
+	defabs, resabs := ra[0], rc[0]
+	a.alist = append(a.alist[:0], lower)
+	a.blist = append(a.blist[:0], upper)
+	a.rlist = append(a.rlist[:0], result)
+	a.elist = append(a.elist[:0], abserr)
+	a.iord = append(a.iord[:0], 0)
+
+	// Test on accuracy.
+	dres := math.Abs(result)
+	errbnd := math.Max(a.absTol, a.relTol*dres)
+	if abserr <= 100*epmach*defabs && abserr > errbnd {
+		ier = 2
+	}
+	if a.limit == 1 {
+		ier = 1
+	}
+	if ier != 0 || (abserr <= errbnd && abserr != resabs) || abserr == 0 {
+		return result, abserr, quadpackError(ier)
+	}
+
+	// Initialization.
+	var (
+		eps epsilonTable
+
+		area   = result
+		errsum = abserr
+		ktmin  int
+		extrap bool
+		noext  bool
+		ierro  int
+		iroff1 int
+		iroff2 int
+		iroff3 int
+		ksgn   = -1
+
+		small  float64
+		erlarg float64
+		ertest float64
+		correc float64
+	)
+	eps.append(result)
+	a.errmax = abserr
+	a.maxerr = 0
+	a.nrmax = 0
+	abserr = oflow
+	if dres >= (1-50*epmach)*defabs {
+		ksgn = 1
+	}
+
+	// Main loop.
+	for last := 2; last <= a.limit; last++ {
+		// Bisect the subinterval with the nrmax-th largest error
+		// estimate.
+		maxerr := a.maxerr
+		a1 := a.alist[maxerr]
+		b1 := 0.5 * (a.alist[maxerr] + a.blist[maxerr])
+		a2 := b1
+		b2 := a.blist[maxerr]
+		erlast := a.errmax
+		r, e, _, rc := a.evaluate([2]float64{a1, b1}, [2]float64{a2, b2})
+		area1, area2 := r[0], r[1]
+		error1, error2 := e[0], e[1]
+		defab1, defab2 := rc[0], rc[1]
+
+		// Improve previous approximations to the integral and error
+		// and test for accuracy.
+		area12 := area1 + area2
+		erro12 := error1 + error2
+		errsum += erro12 - a.errmax
+		area += area12 - a.rlist[maxerr]
+		if defab1 != error1 && defab2 != error2 {
+			if math.Abs(a.rlist[maxerr]-area12) <= 1e-5*math.Abs(area12) && erro12 >= 0.99*a.errmax {
+				if extrap {
+					iroff2++
+				} else {
+					iroff1++
+				}
+			}
+			if last > 10 && erro12 > a.errmax {
+				iroff3++
+			}
+		}
+		errbnd = math.Max(a.absTol, a.relTol*math.Abs(area))
+
+		// Test for roundoff error and eventually set error flag.
+		if iroff1+iroff2 >= 10 || iroff3 >= 20 {
+			ier = 2
+		}
+		if iroff2 >= 5 {
+			ierro = 3
+		}
+		// Set error flag in the case that the number of subintervals
+		// equals the limit.
+		if last == a.limit {
+			ier = 1
+		}
+		// Set error flag in the case of bad integrand behavior at a
+		// point of the integration range.
+		if math.Max(math.Abs(a1), math.Abs(b2)) <= (1+100*epmach)*(math.Abs(a2)+1000*uflow) {
+			ier = 4
+		}
+
+		// Append the newly created intervals to the list.
+		if error2 > error1 {
+			a.alist[maxerr] = a2
+			a.alist = append(a.alist, a1)
+			a.blist = append(a.blist, b1)
+			a.rlist[maxerr] = area2
+			a.rlist = append(a.rlist, area1)
+			a.elist[maxerr] = error2
+			a.elist = append(a.elist, error1)
+		} else {
+			a.alist = append(a.alist, a2)
+			a.blist[maxerr] = b1
+			a.blist = append(a.blist, b2)
+			a.rlist[maxerr] = area1
+			a.rlist = append(a.rlist, area2)
+			a.elist[maxerr] = error1
+			a.elist = append(a.elist, error2)
+		}
+
+		// Maintain the descending ordering of error estimates and
+		// select the subinterval with the nrmax-th largest error
+		// estimate to be bisected next.
+		a.sort()
+
+		if errsum <= errbnd {
+			// The tolerance is met by the sum of the subintervals.
+			return a.sum(), errsum, quadpackError(ier)
+		}
+		if ier != 0 {
+			break
+		}
+		if last == 2 {
+			small = math.Abs(upper-lower) * 0.375
+			erlarg = errsum
+			ertest = errbnd
+			eps.append(area)
+			continue
+		}
+		if noext {
+			continue
+		}
+		erlarg -= erlast
+		if math.Abs(b1-a1) > small {
+			erlarg += erro12
+		}
+		if !extrap {
+			// Test whether the interval to be bisected next is the
+			// smallest interval.
+			if math.Abs(a.blist[a.maxerr]-a.alist[a.maxerr]) > small {
+				continue
+			}
+			extrap = true
+			a.nrmax = 1
+		}
+		if ierro != 3 && erlarg > ertest {
+			// The smallest interval has the largest error. Before
+			// bisecting decrease the sum of the errors over the
+			// larger intervals (erlarg) and perform extrapolation.
+			jupbnd := last
+			if last > 2+a.limit/2 {
+				jupbnd = a.limit + 3 - last
+			}
+			large := false
+			for k := a.nrmax; k < jupbnd; k++ {
+				a.maxerr = a.iord[a.nrmax]
+				a.errmax = a.elist[a.maxerr]
+				if math.Abs(a.blist[a.maxerr]-a.alist[a.maxerr]) > small {
+					large = true
+					break
+				}
+				a.nrmax++
+			}
+			if large {
+				continue
+			}
+		}
+
+		// Perform extrapolation.
+		eps.append(area)
+		reseps, abseps := eps.extrapolate()
+		ktmin++
+		if ktmin > 5 && abserr < 1e-3*errsum {
+			ier = 5
+		}
+		if abseps < abserr {
+			ktmin = 0
+			abserr = abseps
+			result = reseps
+			correc = erlarg
+			ertest = math.Max(a.absTol, a.relTol*math.Abs(reseps))
+			if abserr <= ertest {
+				break
+			}
+		}
+
+		// Prepare bisection of the smallest interval.
+		if eps.n == 1 {
+			noext = true
+		}
+		if ier == 5 {
+			break
+		}
+		a.maxerr = a.iord[0]
+		a.errmax = a.elist[a.maxerr]
+		a.nrmax = 0
+		extrap = false
+		small *= 0.5
+		erlarg = errsum
+	}
+
+	// Set the final result.
+	useSum := false
+	switch {
+	case abserr == oflow:
+		useSum = true
+	case ier+ierro != 0:
+		if ierro == 3 {
+			abserr += correc
+		}
+		if ier == 0 {
+			ier = 3
+		}
+		switch {
+		case result != 0 && area != 0:
+			useSum = abserr/math.Abs(result) > errsum/math.Abs(area)
+		case abserr > errsum:
+			useSum = true
+		case area == 0:
+			return result, abserr, quadpackError(ier)
+		}
+	}
+	if useSum {
+		return a.sum(), errsum, quadpackError(ier)
+	}
+
+	// Test on divergence.
+	if ksgn == -1 && math.Max(math.Abs(result), math.Abs(area)) <= defabs*0.01 {
+		return result, abserr, quadpackError(ier)
+	}
+	if 0.01 > result/area || result/area > 100 || errsum > math.Abs(area) {
+		ier = 6
+	}
+	return result, abserr, quadpackError(ier)
+}
+
+// sum returns the sum of the integral estimates over the subintervals.
+func (a *adaptive) sum() float64 {
+	var sum float64
+	for _, r := range a.rlist {
+		sum += r
+	}
+	return sum
+}
+
+// sort maintains the descending ordering of the list of error estimates
+// after the subinterval maxerr has been bisected and the new subinterval
+// appended, and selects the subinterval with the nrmax-th largest error
+// estimate. Only the subintervals that may still be bisected within the
+// subinterval limit are kept ordered.
+//
+// sort is a translation of the DQPSRT routine of QUADPACK.
+func (a *adaptive) sort() {
+	last := len(a.elist)
+	newest := last - 1
+	if len(a.iord) < last {
+		a.iord = append(a.iord, make([]int, last-len(a.iord))...)
+	}
+	iord := a.iord
+	if last <= 2 {
+		iord[0] = 0
+		iord[1] = 1
+		a.maxerr = iord[a.nrmax]
+		a.errmax = a.elist[a.maxerr]
+		return
+	}
+
+	// This part of the routine is only executed if, due to a difficult
+	// integrand, subdivision increased the error estimate. In the
+	// normal case the insert procedure should start after the nrmax-th
+	// largest error estimate.
+	errmax := a.elist[a.maxerr]
+	for n := a.nrmax; n > 0; n-- {
+		isucc := iord[a.nrmax-1]
+		if errmax <= a.elist[isucc] {
+			break
+		}
+		iord[a.nrmax] = isucc
+		a.nrmax--
+	}
+
+	// Compute the number of elements in the list to be maintained in
+	// descending order. This number depends on the number of
+	// subdivisions still allowed.
+	jupbn := last - 1
+	if last > a.limit/2+2 {
+		jupbn = a.limit + 2 - last
+	}
+	errmin := a.elist[newest]
+
+	// Insert errmax by traversing the list top-down.
+	jbnd := jupbn - 1
+	i := a.nrmax + 1
+	for ; i <= jbnd; i++ {
+		isucc := iord[i]
+		if errmax >= a.elist[isucc] {
+			break
+		}
+		iord[i-1] = isucc
+	}
+	if i > jbnd {
+		iord[jbnd] = a.maxerr
+		iord[jupbn] = newest
+	} else {
+		// Insert errmin by traversing the list bottom-up.
+		iord[i-1] = a.maxerr
+		k := jbnd
+		inserted := false
+		for j := i; j <= jbnd; j++ {
+			isucc := iord[k]
+			if errmin < a.elist[isucc] {
+				iord[k+1] = newest
+				inserted = true
+				break
+			}
+			iord[k+1] = isucc
+			k--
+		}
+		if !inserted {
+			iord[i] = newest
+		}
+	}
+
+	a.maxerr = iord[a.nrmax]
+	a.errmax = a.elist[a.maxerr]
+}
+
+// quadpackError returns the error corresponding to the QUADPACK error
+// flag before its final adjustment in DQAGSE.
+func quadpackError(ier int) error {
+	switch ier {
+	case 0:
+		return nil
+	case 1:
+		return ErrSubdivisionLimit
+	case 2, 3:
+		return ErrRoundoff
+	case 4:
+		return ErrBadIntegrand
+	case 5:
+		return ErrNoConvergence
+	case 6:
+		return ErrDivergent
+	default:
+		panic("quad: invalid error flag")
+	}
+}
+
+// epsilonTable holds the table of the epsilon algorithm of Wynn used
+// to extrapolate the sequence of integral estimates.
+type epsilonTable struct {
+	// tab holds the elements of the table. Indices follow
+	// QUADPACK so tab[0] is unused.
+	tab [53]float64
+	// n is the number of elements in the current diagonal.
+	n int
+	// res3la holds the last three extrapolated results.
+	res3la [3]float64
+	// nres is the number of calls to extrapolate.
+	nres int
+}
+
+// append adds a new element to the sequence being extrapolated.
+func (t *epsilonTable) append(v float64) {
+	if t.n+3 > len(t.tab) {
+		// Drop the oldest element to make room.
+		copy(t.tab[1:], t.tab[2:t.n+1])
+		t.n--
+	}
+	t.n++
+	t.tab[t.n] = v
+}
+
+// extrapolate determines the limit of the sequence by means of the
+// epsilon algorithm, returning the limit and an error estimate.
+//
+// extrapolate is a translation of the DQELG routine of QUADPACK.
+func (t *epsilonTable) extrapolate() (result, abserr float64) {
+	const limexp = 50
+
+	epstab := &t.tab
+	n := t.n
+	t.nres++
+	abserr = oflow
+	result = epstab[n]
+	if n < 3 {
+		return result, math.Max(abserr, 5*epmach*math.Abs(result))
+	}
+	epstab[n+2] = epstab[n]
+	newelm := (n - 1) / 2
+	epstab[n] = oflow
+	num := n
+	k1 := n
+	for i := 1; i <= newelm; i++ {
+		k2 := k1 - 1
+		k3 := k1 - 2
+		res := epstab[k1+2]
+		e0 := epstab[k3]
+		e1 := epstab[k2]
+		e2 := res
+		e1abs := math.Abs(e1)
+		delta2 := e2 - e1
+		err2 := math.Abs(delta2)
+		tol2 := math.Max(math.Abs(e2), e1abs) * epmach
+		delta3 := e1 - e0
+		err3 := math.Abs(delta3)
+		tol3 := math.Max(e1abs, math.Abs(e0)) * epmach
+		if err2 <= tol2 && err3 <= tol3 {
+			// e0, e1 and e2 are equal to within machine accuracy,
+			// so convergence is assumed.
+			t.n = n
+			return res, math.Max(err2+err3, 5*epmach*math.Abs(res))
+		}
+		e3 := epstab[k1]
+		epstab[k1] = e1
+		delta1 := e1 - e3
+		err1 := math.Abs(delta1)
+		tol1 := math.Max(e1abs, math.Abs(e3)) * epmach
+		// If two elements are very close to each other, or the table
+		// behaves irregularly, omit a part of the table by adjusting
+		// the value of n.
+		if err1 <= tol1 || err2 <= tol2 || err3 <= tol3 {
+			n = i + i - 1
+			break
+		}
+		ss := 1/delta1 + 1/delta2 - 1/delta3
+		if math.Abs(ss*e1) <= 1e-4 {
+			n = i + i - 1
+			break
+		}
+		// Compute a new element and eventually adjust the value of
+		// result.
+		res = e1 + 1/ss
+		epstab[k1] = res
+		k1 -= 2
+		errA := err2 + math.Abs(res-e2) + err3
+		if errA <= abserr {
+			abserr = errA
+			result = res
+		}
+	}
+
+	// Shift the table.
+	if n == limexp {
+		n = 2*(limexp/2) - 1
+	}
+	ib := 1
+	if num%2 == 0 {
+		ib = 2
+	}
+	ie := newelm + 1
+	for i := 1; i <= ie; i++ {
+		epstab[ib] = epstab[ib+2]
+		ib += 2
+	}
+	if num != n {
+		indx := num - n + 1
+		for i := 1; i <= n; i++ {
+			epstab[i] = epstab[indx]
+			indx++
+		}
+	}
+	t.n = n
+
+	if t.nres < 4 {
+		t.res3la[t.nres-1] = result
+		abserr = oflow
+	} else {
+		// Compute the error estimate.
+		abserr = math.Abs(result-t.res3la[2]) + math.Abs(result-t.res3la[1]) + math.Abs(result-t.res3la[0])
+		t.res3la[0] = t.res3la[1]
+		t.res3la[1] = t.res3la[2]
+		t.res3la[2] = result
+	}
+	return result, math.Max(abserr, 5*epmach*math.Abs(result))
+}
@@ -0,0 +1,185 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestKronrodRules(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name   string
+		rule   kronrod
+		gauss  int
+		degree int
+	}{
+		{name: "G7K15", rule: gk15, gauss: 7, degree: 22},
+		{name: "G10K21", rule: gk21, gauss: 10, degree: 31},
+	} {
+		// Check the Gauss nodes and weights against Legendre.
+		x := make([]float64, test.gauss)
+		w := make([]float64, test.gauss)
+		Legendre{}.FixedLocations(x, w, -1, 1)
+		for i := 0; i < test.gauss/2; i++ {
+			if !scalar.EqualWithinAbs(test.rule.x[2*i+1], x[i], 1e-14) {
+				t.Errorf("%s: unexpected Gauss node %d: got:%v want:%v", test.name, i, test.rule.x[2*i+1], x[i])
+			}
+			if !scalar.EqualWithinAbs(test.rule.wg[i], w[i], 1e-14) {
+				t.Errorf("%s: unexpected Gauss weight %d: got:%v want:%v", test.name, i, test.rule.wg[i], w[i])
+			}
+		}
+
+		// Check the Kronrod rule is exact for polynomials of its degree.
+		loc := make([]float64, test.rule.points())
+		fv := make([]float64, len(loc))
+		test.rule.locations(loc, -1, 2)
+		for k := 0; k <= test.degree; k++ {
+			for i, v := range loc {
+				fv[i] = math.Pow(v, float64(k))
+			}
+			got, _, _, _ := test.rule.estimate(fv, -1, 2)
+			want := (math.Pow(2, float64(k+1)) - math.Pow(-1, float64(k+1))) / float64(k+1)
+			if !scalar.EqualWithinRel(got, want, 1e-13) {
+				t.Errorf("%s: not exact for degree %d: got:%v want:%v", test.name, k, got, want)
+			}
+		}
+	}
+}
+
+func TestAdaptive(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name     string
+		f        func(float64) float64
+		min, max float64
+		want     float64
+	}{
+		{
+			name: "exp",
+			f:    math.Exp,
+			min:  -3, max: 5,
+			want: math.Exp(5) - math.Exp(-3),
+		},
+		{
+			name: "inverse sqrt",
+			f:    func(x float64) float64 { return 1 / math.Sqrt(x) },
+			min:  0, max: 1,
+			want: 2,
+		},
+		{
+			name: "log inverse sqrt",
+			f:    func(x float64) float64 { return math.Log(x) / math.Sqrt(x) },
+			min:  0, max: 1,
+			want: -4,
+		},
+		{
+			name: "interior singularity",
+			f:    func(x float64) float64 { return 1 / math.Sqrt(math.Abs(x-1.0/3)) },
+			min:  0, max: 1,
+			want: 2 * (math.Sqrt(1.0/3) + math.Sqrt(2.0/3)),
+		},
+		{
+			name: "oscillatory",
+			f:    func(x float64) float64 { return math.Cos(100 * math.Sin(x)) },
+			min:  0, max: math.Pi,
+			// π J₀(100)
+			want: math.Pi * math.J0(100),
+		},
+		{
+			name: "upper infinite",
+			f:    func(x float64) float64 { return math.Exp(-x) },
+			min:  5, max: math.Inf(1),
+			want: math.Exp(-5),
+		},
+		{
+			name: "lower infinite",
+			f:    math.Exp,
+			min:  math.Inf(-1), max: -5,
+			want: math.Exp(-5),
+		},
+		{
+			name: "normal",
+			f:    distuv.UnitNormal.Prob,
+			min:  math.Inf(-1), max: math.Inf(1),
+			want: 1,
+		},
+		{
+			name: "infinite singular",
+			f:    func(x float64) float64 { return math.Exp(-x) / math.Sqrt(x) },
+			min:  0, max: math.Inf(1),
+			want: math.Sqrt(math.Pi),
+		},
+		{
+			name: "empty",
+			f:    math.Exp,
+			min:  3, max: 3,
+			want: 0,
+		},
+	} {
+		for _, points := range []int{0, 15, 21} {
+			const tol = 1e-10
+			settings := &AdaptiveSettings{AbsTol: tol, RelTol: tol, Points: points, Limit: 200}
+			res, err := Adaptive(test.f, test.min, test.max, settings, 0)
+			if err != nil {
+				t.Errorf("%s with %d points: unexpected error: %v", test.name, points, err)
+			}
+			diff := math.Abs(res.Value - test.want)
+			if diff > math.Max(tol, tol*math.Abs(test.want)) {
+				t.Errorf("%s with %d points: unexpected value: got:%v want:%v", test.name, points, res.Value, test.want)
+			}
+			if diff > res.AbsError && diff > 1e-15 {
+				t.Errorf("%s with %d points: error bound too small: got:%v actual error:%v", test.name, points, res.AbsError, diff)
+			}
+			if test.min == test.max {
+				continue
+			}
+
+			n := points
+			if n == 0 {
+				n = 21
+				if math.IsInf(test.min, 0) || math.IsInf(test.max, 0) {
+					n = 15
+				}
+			}
+			wantEvals := n * (2*res.Subintervals - 1)
+			if math.IsInf(test.min, -1) && math.IsInf(test.max, 1) {
+				wantEvals *= 2
+			}
+			if res.Evaluations != wantEvals {
+				t.Errorf("%s with %d points: unexpected number of evaluations: got:%d want:%d", test.name, points, res.Evaluations, wantEvals)
+			}
+
+			conc, err := Adaptive(test.f, test.min, test.max, settings, 3)
+			if err != nil {
+				t.Errorf("%s with %d points: unexpected error for concurrent evaluation: %v", test.name, points, err)
+			}
+			if conc != res {
+				t.Errorf("%s with %d points: concurrent evaluation mismatch: got:%+v want:%+v", test.name, points, conc, res)
+			}
+		}
+	}
+}
+
+func TestAdaptiveErrors(t *testing.T) {
+	t.Parallel()
+	hard := func(x float64) float64 { return math.Log(x) / math.Sqrt(x) }
+	res, err := Adaptive(hard, 0, 1, &AdaptiveSettings{Limit: 3}, 0)
+	if err != ErrSubdivisionLimit {
+		t.Errorf("unexpected error for subdivision limit: got:%v want:%v", err, ErrSubdivisionLimit)
+	}
+	if res.Subintervals != 3 {
+		t.Errorf("unexpected number of subintervals: got:%d want:3", res.Subintervals)
+	}
+
+	_, err = Adaptive(func(x float64) float64 { return 1 / x }, 0, 1, nil, 0)
+	if err == nil {
+		t.Error("expected error for divergent integral")
+	}
+}
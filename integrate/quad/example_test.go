@@ -45,3 +45,22 @@ func Example() {
 	// Estimate using parallel evaluations of f.
 	// EV = 4.19064
 }
+
+func ExampleAdaptive() {
+	// Integrate log(x)/sqrt(x) over [0, 1], which has an integrable
+	// singularity at zero.
+	f := func(x float64) float64 { return math.Log(x) / math.Sqrt(x) }
+	res, err := quad.Adaptive(f, 0, 1, &quad.AdaptiveSettings{AbsTol: 1e-10, RelTol: 1e-10}, 0)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("integral = %.10f\n", res.Value)
+	fmt.Printf("error bound < 1e-10: %t\n", res.AbsError < 1e-10)
+	fmt.Printf("evaluations = %d\n", res.Evaluations)
+
+	// Output:
+	// integral = -4.0000000000
+	// error bound < 1e-10: true
+	// evaluations = 315
+}
@@ -0,0 +1,151 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import "math"
+
+// kronrod is a Gauss–Kronrod rule on [-1, 1] with an embedded Gauss rule.
+type kronrod struct {
+	// x holds the non-negative Kronrod nodes in descending order,
+	// ending with the center node. The Gauss nodes are x[1], x[3], ...
+	x []float64
+	// wk holds the Kronrod weights for the nodes in x.
+	wk []float64
+	// wg holds the Gauss weights for the Gauss nodes, followed by the
+	// weight of the center node if the Gauss rule has odd order.
+	wg []float64
+}
+
+// Gauss–Kronrod rules from QUADPACK.
+var (
+	// gk15 is the 7-point Gauss and 15-point Kronrod rule.
+	gk15 = kronrod{
+		x: []float64{
+			0.991455371120812639206854697526329,
+			0.949107912342758524526189684047851,
+			0.864864423359769072789712788640926,
+			0.741531185599394439863864773280788,
+			0.586087235467691130294144845693013,
+			0.405845151377397166906606412076961,
+			0.207784955007898467600689403773245,
+			0,
+		},
+		wk: []float64{
+			0.022935322010529224963732008058970,
+			0.063092092629978553290700663189204,
+			0.104790010322250183839876322541518,
+			0.140653259715525918745189590510238,
+			0.169004726639267902826583426598550,
+			0.190350578064785409913256402421014,
+			0.204432940075298892414161999234649,
+			0.209482141084727828012999174891714,
+		},
+		wg: []float64{
+			0.129484966168869693270611432679082,
+			0.279705391489276667901467771423780,
+			0.381830050505118944950369775488975,
+			0.417959183673469387755102040816327,
+		},
+	}
+
+	// gk21 is the 10-point Gauss and 21-point Kronrod rule.
+	gk21 = kronrod{
+		x: []float64{
+			0.995657163025808080735527280689003,
+			0.973906528517171720077964012084452,
+			0.930157491355708226001207180059508,
+			0.865063366688984510732096688423493,
+			0.780817726586416897063717578345042,
+			0.679409568299024406234327365114874,
+			0.562757134668604683339000099272694,
+			0.433395394129247190799265943165784,
+			0.294392862701460198131126603103866,
+			0.148874338981631210884826001129720,
+			0,
+		},
+		wk: []float64{
+			0.011694638867371874278064396062192,
+			0.032558162307964727478818972459390,
+			0.054755896574351996031381300244580,
+			0.075039674810919952767043140916190,
+			0.093125454583697605535065465083366,
+			0.109387158802297641899210590325805,
+			0.123491976262065851077408463818818,
+			0.134709217311473325928054001771707,
+			0.142775938577060080797094273138717,
+			0.147739104901338491374841515972068,
+			0.149445554002916905664936468389821,
+		},
+		wg: []float64{
+			0.066671344308688137593568809893332,
+			0.149451349150580593145776339657697,
+			0.219086362515982043995534934228163,
+			0.269266719309996355091226921569469,
+			0.295524224714752870173892994651338,
+		},
+	}
+)
+
+// points returns the number of points in the Kronrod rule.
+func (r kronrod) points() int {
+	return 2*len(r.x) - 1
+}
+
+// locations stores the locations of the rule on [a, b] in dst. The
+// center is stored first, followed by the pairs of locations placed
+// symmetrically about the center.
+func (r kronrod) locations(dst []float64, a, b float64) {
+	c := 0.5 * (a + b)
+	h := 0.5 * (b - a)
+	dst[0] = c
+	for i, x := range r.x[:len(r.x)-1] {
+		dst[2*i+1] = c - h*x
+		dst[2*i+2] = c + h*x
+	}
+}
+
+// estimate returns the Kronrod estimate of the integral over [a, b] from
+// the function values at the locations returned by the locations method,
+// with the estimate of the absolute error, the integral of |f| and the
+// integral of |f - mean(f)|.
+func (r kronrod) estimate(fv []float64, a, b float64) (result, abserr, resabs, resasc float64) {
+	h := 0.5 * (b - a)
+	n := len(r.x) - 1
+
+	fc := fv[0]
+	resk := r.wk[n] * fc
+	resabs = math.Abs(resk)
+	var resg float64
+	if len(r.wg) > n/2 {
+		// The Gauss rule includes the center node.
+		resg = r.wg[len(r.wg)-1] * fc
+	}
+	for i := 0; i < n; i++ {
+		f1, f2 := fv[2*i+1], fv[2*i+2]
+		sum := f1 + f2
+		resk += r.wk[i] * sum
+		resabs += r.wk[i] * (math.Abs(f1) + math.Abs(f2))
+		if i%2 == 1 {
+			resg += r.wg[i/2] * sum
+		}
+	}
+	reskh := 0.5 * resk
+	resasc = r.wk[n] * math.Abs(fc-reskh)
+	for i := 0; i < n; i++ {
+		resasc += r.wk[i] * (math.Abs(fv[2*i+1]-reskh) + math.Abs(fv[2*i+2]-reskh))
+	}
+
+	result = resk * h
+	resabs *= math.Abs(h)
+	resasc *= math.Abs(h)
+	abserr = math.Abs((resk - resg) * h)
+	if resasc != 0 && abserr != 0 {
+		abserr = resasc * math.Min(1, math.Pow(200*abserr/resasc, 1.5))
+	}
+	if resabs > uflow/(50*epmach) {
+		abserr = math.Max(50*epmach*resabs, abserr)
+	}
+	return result, abserr, resabs, resasc
+}
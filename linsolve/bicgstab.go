@@ -0,0 +1,91 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// BiCGStab implements the right-preconditioned BiConjugate Gradient
+// Stabilized method for solving systems with a general non-symmetric
+// matrix.
+//
+// References:
+//   - van der Vorst, H. A. (1992). Bi-CGSTAB: A fast and smoothly converging
+//     variant of Bi-CG for the solution of nonsymmetric linear systems.
+//     SIAM Journal on Scientific and Statistical Computing, 13(2), 631-644.
+//   - Barrett, R. et al. (1994). Section 2.3.8 BiConjugate Gradient
+//     Stabilized (Bi-CGSTAB). In Templates for the Solution of Linear
+//     Systems: Building Blocks for Iterative Methods (2nd ed.) (pp. 24-25).
+//     Philadelphia, PA: SIAM.
+type BiCGStab struct{}
+
+// Solve implements the Method interface. Solve returns ErrBreakdown if one
+// of the scalar recurrences has a zero divisor.
+func (BiCGStab) Solve(ctx *Context) error {
+	n := ctx.X.Len()
+	r := ctx.Residual
+	rt := mat.VecDenseCopyOf(r)
+	p := mat.NewVecDense(n, nil)
+	v := mat.NewVecDense(n, nil)
+	ph := mat.NewVecDense(n, nil)
+	s := mat.NewVecDense(n, nil)
+	sh := mat.NewVecDense(n, nil)
+	t := mat.NewVecDense(n, nil)
+
+	rho, alpha, omega := 1.0, 1.0, 1.0
+	for first := true; ; first = false {
+		rhoNew := mat.Dot(rt, r)
+		if rhoNew == 0 {
+			return ErrBreakdown
+		}
+		if first {
+			p.CopyVec(r)
+		} else {
+			beta := (rhoNew / rho) * (alpha / omega)
+			p.AddScaledVec(p, -omega, v)
+			p.AddScaledVec(r, beta, p)
+		}
+		rho = rhoNew
+
+		if err := ctx.PreconSolve(ph, p); err != nil {
+			return err
+		}
+		ctx.MulVec(v, ph)
+		rtv := mat.Dot(rt, v)
+		if rtv == 0 {
+			return ErrBreakdown
+		}
+		alpha = rho / rtv
+		s.AddScaledVec(r, -alpha, v)
+		snorm := mat.Norm(s, 2)
+		if ctx.converged(snorm) {
+			ctx.X.AddScaledVec(ctx.X, alpha, ph)
+			ctx.Iterate(snorm)
+			return nil
+		}
+
+		if err := ctx.PreconSolve(sh, s); err != nil {
+			return err
+		}
+		ctx.MulVec(t, sh)
+		tt := mat.Dot(t, t)
+		if tt == 0 {
+			return ErrBreakdown
+		}
+		omega = mat.Dot(t, s) / tt
+		ctx.X.AddScaledVec(ctx.X, alpha, ph)
+		ctx.X.AddScaledVec(ctx.X, omega, sh)
+		r.AddScaledVec(s, -omega, t)
+		if ctx.Iterate(mat.Norm(r, 2)) {
+			return nil
+		}
+		if omega == 0 || math.IsNaN(omega) {
+			return ErrBreakdown
+		}
+	}
+}
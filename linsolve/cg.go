@@ -0,0 +1,54 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import "gonum.org/v1/gonum/mat"
+
+// CG implements the preconditioned Conjugate Gradient method for solving
+// systems with a symmetric positive definite matrix. The preconditioner
+// must also be symmetric positive definite.
+//
+// References:
+//   - Barrett, R. et al. (1994). Section 2.3.1 Conjugate Gradient Method (CG).
+//     In Templates for the Solution of Linear Systems: Building Blocks for
+//     Iterative Methods (2nd ed.) (pp. 12-15). Philadelphia, PA: SIAM.
+type CG struct{}
+
+// Solve implements the Method interface. Solve returns ErrBreakdown if
+// the matrix or the preconditioner is found not to be positive definite.
+func (CG) Solve(ctx *Context) error {
+	n := ctx.X.Len()
+	r := ctx.Residual
+	z := mat.NewVecDense(n, nil)
+	p := mat.NewVecDense(n, nil)
+	ap := mat.NewVecDense(n, nil)
+
+	if err := ctx.PreconSolve(z, r); err != nil {
+		return err
+	}
+	p.CopyVec(z)
+	rz := mat.Dot(r, z)
+	for {
+		ctx.MulVec(ap, p)
+		pap := mat.Dot(p, ap)
+		if pap <= 0 || rz <= 0 {
+			return ErrBreakdown
+		}
+		alpha := rz / pap
+		ctx.X.AddScaledVec(ctx.X, alpha, p)
+		r.AddScaledVec(r, -alpha, ap)
+		if ctx.Iterate(mat.Norm(r, 2)) {
+			return nil
+		}
+
+		if err := ctx.PreconSolve(z, r); err != nil {
+			return err
+		}
+		rzNew := mat.Dot(r, z)
+		beta := rzNew / rz
+		rz = rzNew
+		p.AddScaledVec(z, beta, p)
+	}
+}
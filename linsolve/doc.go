@@ -0,0 +1,12 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package linsolve provides iterative methods for solving linear systems.
+//
+// The methods in this package only access the system matrix A through
+// matrix-vector products, so they are suited to large sparse systems, for
+// example those stored in mat.CSR, and to systems where A is available only
+// as an operator. The rate of convergence can be improved by supplying a
+// Preconditioner.
+package linsolve // import "gonum.org/v1/gonum/linsolve"
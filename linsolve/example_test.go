@@ -0,0 +1,51 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/linsolve"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleIterative() {
+	// Assemble the 1D Poisson matrix in sparse form.
+	const n = 50
+	coo := mat.NewCOO(n, n, nil, nil, nil)
+	for i := 0; i < n; i++ {
+		coo.Append(i, i, 2)
+		if i > 0 {
+			coo.Append(i, i-1, -1)
+			coo.Append(i-1, i, -1)
+		}
+	}
+	var a mat.CSR
+	a.CloneFrom(coo)
+
+	b := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		b.SetVec(i, 1)
+	}
+
+	// Solve with the conjugate gradient method preconditioned by an
+	// incomplete Cholesky factorization.
+	ic, err := linsolve.NewIncompleteCholesky(&a)
+	if err != nil {
+		log.Fatal(err)
+	}
+	res, err := linsolve.Iterative(&a, b, linsolve.CG{}, &linsolve.Settings{
+		Tolerance:      1e-10,
+		Preconditioner: ic,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("x[0] = %.4f, x[%d] = %.4f\n", res.X.AtVec(0), n/2, res.X.AtVec(n/2))
+
+	// Output:
+	// x[0] = 25.0000, x[25] = 325.0000
+}
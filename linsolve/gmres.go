@@ -0,0 +1,143 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const defaultRestart = 30
+
+// GMRES implements the right-preconditioned restarted Generalized Minimal
+// Residual method, GMRES(m), for solving systems with a general
+// non-symmetric matrix. Each iteration performs one step of the Arnoldi
+// process and the residual norm reported to the Context is the norm of the
+// true residual, up to rounding errors.
+//
+// References:
+//   - Saad, Y., and Schultz, M. (1986). GMRES: A generalized minimal residual
+//     algorithm for solving nonsymmetric linear systems. SIAM J. Sci. Stat.
+//     Comput., 7(3), 856-869.
+//   - Barrett, R. et al. (1994). Section 2.3.4 Generalized Minimal Residual
+//     (GMRES). In Templates for the Solution of Linear Systems: Building
+//     Blocks for Iterative Methods (2nd ed.) (pp. 17-20). Philadelphia, PA:
+//     SIAM.
+type GMRES struct {
+	// Restart is the number of iterations between restarts, equal to
+	// the dimension of the Krylov subspace. If Restart is zero, the
+	// smaller of 30 and the dimension of the system is used.
+	Restart int
+}
+
+// Solve implements the Method interface.
+func (g *GMRES) Solve(ctx *Context) error {
+	n := ctx.X.Len()
+	m := g.Restart
+	if m == 0 {
+		m = defaultRestart
+		if n < m {
+			m = n
+		}
+	}
+	if m < 0 {
+		panic("linsolve: negative GMRES restart")
+	}
+
+	// v holds the orthonormal basis of the Krylov subspace.
+	v := make([]*mat.VecDense, m+1)
+	for i := range v {
+		v[i] = mat.NewVecDense(n, nil)
+	}
+	// h is the upper Hessenberg matrix, stored column-wise and reduced
+	// to upper triangular form by Givens rotations.
+	h := make([][]float64, m)
+	for j := range h {
+		h[j] = make([]float64, m+1)
+	}
+	cs := make([]float64, m)
+	sn := make([]float64, m)
+	gamma := make([]float64, m+1)
+	z := mat.NewVecDense(n, nil)
+	w := mat.NewVecDense(n, nil)
+
+	r := ctx.Residual
+	for {
+		beta := mat.Norm(r, 2)
+		v[0].ScaleVec(1/beta, r)
+		for i := range gamma {
+			gamma[i] = 0
+		}
+		gamma[0] = beta
+
+		var (
+			k    int
+			stop bool
+		)
+		for k < m && !stop {
+			j := k
+			if err := ctx.PreconSolve(z, v[j]); err != nil {
+				return err
+			}
+			ctx.MulVec(w, z)
+			// Modified Gram-Schmidt orthogonalization.
+			hj := h[j]
+			for i := 0; i <= j; i++ {
+				hj[i] = mat.Dot(w, v[i])
+				w.AddScaledVec(w, -hj[i], v[i])
+			}
+			hj[j+1] = mat.Norm(w, 2)
+			lucky := hj[j+1] == 0
+			if !lucky {
+				v[j+1].ScaleVec(1/hj[j+1], w)
+			}
+
+			// Apply the previous rotations to the new column and
+			// compute the rotation that annihilates h[j+1,j].
+			for i := 0; i < j; i++ {
+				hj[i], hj[i+1] = cs[i]*hj[i]+sn[i]*hj[i+1], -sn[i]*hj[i]+cs[i]*hj[i+1]
+			}
+			rho := math.Hypot(hj[j], hj[j+1])
+			cs[j], sn[j] = hj[j]/rho, hj[j+1]/rho
+			hj[j], hj[j+1] = rho, 0
+			gamma[j], gamma[j+1] = cs[j]*gamma[j], -sn[j]*gamma[j]
+
+			k++
+			stop = ctx.Iterate(math.Abs(gamma[j+1])) || lucky
+		}
+
+		// Solve the k×k triangular system for the coefficients of the
+		// update and form x += M⁻¹⋅V⋅y.
+		y := make([]float64, k)
+		copy(y, gamma[:k])
+		for i := k - 1; i >= 0; i-- {
+			for l := i + 1; l < k; l++ {
+				y[i] -= h[l][i] * y[l]
+			}
+			if h[i][i] == 0 {
+				return ErrBreakdown
+			}
+			y[i] /= h[i][i]
+		}
+		w.Zero()
+		for i, yi := range y {
+			w.AddScaledVec(w, yi, v[i])
+		}
+		if err := ctx.PreconSolve(z, w); err != nil {
+			return err
+		}
+		ctx.X.AddVec(ctx.X, z)
+
+		last := ctx.history[len(ctx.history)-1]
+		if ctx.converged(last) || len(ctx.history)-1 >= ctx.maxIter {
+			return nil
+		}
+
+		// Restart with the true residual.
+		ctx.MulVec(r, ctx.X)
+		r.SubVec(ctx.B, r)
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"errors"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const defaultTolerance = 1e-8
+
+var (
+	// ErrIterationLimit is returned when a method does not reach the
+	// requested tolerance within the iteration limit.
+	ErrIterationLimit = errors.New("linsolve: iteration limit reached")
+
+	// ErrBreakdown is returned when a method cannot continue due to
+	// a zero divisor in its recurrences.
+	ErrBreakdown = errors.New("linsolve: method breakdown")
+
+	// ErrNotPositiveDefinite is returned when a matrix or preconditioner
+	// required to be positive definite is found not to be.
+	ErrNotPositiveDefinite = errors.New("linsolve: matrix not positive definite")
+)
+
+// MulVecToer represents a linear operator A.
+type MulVecToer interface {
+	// MulVecTo computes A⋅x or Aᵀ⋅x and stores the result into dst.
+	MulVecTo(dst *mat.VecDense, trans bool, x mat.Vector)
+}
+
+// MulVecFunc is a function type that implements MulVecToer.
+type MulVecFunc func(dst *mat.VecDense, trans bool, x mat.Vector)
+
+// MulVecTo calls fn(dst, trans, x).
+func (fn MulVecFunc) MulVecTo(dst *mat.VecDense, trans bool, x mat.Vector) {
+	fn(dst, trans, x)
+}
+
+// Operator returns a MulVecToer for the matrix a. If a already implements
+// MulVecToer it is returned unchanged, otherwise products are computed with
+// mat.VecDense.MulVec.
+func Operator(a mat.Matrix) MulVecToer {
+	if op, ok := a.(MulVecToer); ok {
+		return op
+	}
+	return matrixOperator{a}
+}
+
+type matrixOperator struct {
+	a mat.Matrix
+}
+
+func (m matrixOperator) MulVecTo(dst *mat.VecDense, trans bool, x mat.Vector) {
+	if trans {
+		dst.MulVec(m.a.T(), x)
+		return
+	}
+	dst.MulVec(m.a, x)
+}
+
+// Settings holds settings for solving a linear system.
+type Settings struct {
+	// InitX holds the initial guess. If it is nil, the zero vector
+	// is used.
+	InitX mat.Vector
+
+	// Tolerance specifies the convergence criterion. The iteration
+	// stops when the residual norm is at most Tolerance times the norm of
+	// the right-hand side. If Tolerance is zero, a default of 1e-8 is used.
+	Tolerance float64
+
+	// MaxIterations is the limit on the number of iterations. If it is
+	// zero, a default of 4 times the dimension of the system is used.
+	MaxIterations int
+
+	// Preconditioner is used to precondition the system. If it is nil,
+	// no preconditioning is performed.
+	Preconditioner Preconditioner
+}
+
+// Result holds the result of solving a linear system.
+type Result struct {
+	// X is the approximate solution.
+	X *mat.VecDense
+
+	// ResidualNorm is the residual norm of X as estimated by
+	// the method.
+	ResidualNorm float64
+
+	// Iterations is the number of iterations performed.
+	Iterations int
+
+	// MulVec is the number of products with A.
+	MulVec int
+
+	// History holds the residual norm estimate of the initial
+	// guess followed by the estimate after each iteration.
+	History []float64
+}
+
+// Method is an iterative method for solving linear systems.
+type Method interface {
+	// Solve iteratively improves the solution estimate held in ctx.X,
+	// starting from the initial residual held in ctx.Residual. After each
+	// iteration Solve must call ctx.Iterate with the residual norm of the
+	// current estimate and return nil when ctx.Iterate returns true.
+	Solve(ctx *Context) error
+}
+
+// Context holds the state of a linear system being solved by a Method.
+type Context struct {
+	// A is the system operator.
+	A MulVecToer
+	// B is the right-hand side.
+	B mat.Vector
+	// X is the current solution estimate.
+	X *mat.VecDense
+	// Residual holds B - A⋅X for the initial estimate of X. Methods
+	// may use it as workspace.
+	Residual *mat.VecDense
+
+	precon  Preconditioner
+	tol     float64
+	maxIter int
+	mulVec  int
+	history []float64
+}
+
+// MulVec computes dst = A⋅x.
+func (ctx *Context) MulVec(dst *mat.VecDense, x mat.Vector) {
+	ctx.mulVec++
+	ctx.A.MulVecTo(dst, false, x)
+}
+
+// PreconSolve solves M⋅dst = rhs where M is the preconditioner. If no
+// preconditioner was set, rhs is copied into dst.
+func (ctx *Context) PreconSolve(dst *mat.VecDense, rhs mat.Vector) error {
+	return ctx.precon.PreconSolve(dst, rhs)
+}
+
+// Iterate records the completion of an iteration with the given residual
+// norm and returns whether the method should stop, either because the
+// tolerance has been reached or because of the iteration limit.
+func (ctx *Context) Iterate(rnorm float64) (stop bool) {
+	ctx.history = append(ctx.history, rnorm)
+	return ctx.converged(rnorm) || len(ctx.history)-1 >= ctx.maxIter
+}
+
+func (ctx *Context) converged(rnorm float64) bool {
+	return rnorm <= ctx.tol
+}
+
+// Iterative finds an approximate solution of the system of linear equations
+//
+//	A⋅x = b
+//
+// using the given method. If method is nil, GMRES is used. The returned
+// error is ErrIterationLimit if the tolerance has not been reached, in which
+// case the Result holds the last estimate, or a method-specific error. The
+// length of b must equal the dimension of the square operator a.
+func Iterative(a MulVecToer, b mat.Vector, method Method, settings *Settings) (*Result, error) {
+	n := b.Len()
+	if n == 0 {
+		panic("linsolve: zero-length right-hand side")
+	}
+	if settings == nil {
+		settings = &Settings{}
+	}
+	if method == nil {
+		method = &GMRES{}
+	}
+	tol := settings.Tolerance
+	if tol == 0 {
+		tol = defaultTolerance
+	}
+	maxIter := settings.MaxIterations
+	if maxIter == 0 {
+		maxIter = 4 * n
+	}
+	precon := settings.Preconditioner
+	if precon == nil {
+		precon = identity{}
+	}
+
+	x := mat.NewVecDense(n, nil)
+	if settings.InitX != nil {
+		if settings.InitX.Len() != n {
+			panic("linsolve: mismatched initial guess length")
+		}
+		x.CopyVec(settings.InitX)
+	}
+	bnorm := mat.Norm(b, 2)
+	if bnorm == 0 {
+		// The solution of a homogeneous system is zero.
+		return &Result{X: mat.NewVecDense(n, nil), History: []float64{0}}, nil
+	}
+	ctx := &Context{
+		A:        a,
+		B:        b,
+		X:        x,
+		Residual: mat.NewVecDense(n, nil),
+		precon:   precon,
+		tol:      tol * bnorm,
+		maxIter:  maxIter,
+	}
+	ctx.MulVec(ctx.Residual, x)
+	ctx.Residual.SubVec(b, ctx.Residual)
+	rnorm := mat.Norm(ctx.Residual, 2)
+	ctx.history = []float64{rnorm}
+
+	var err error
+	if !ctx.converged(rnorm) {
+		err = method.Solve(ctx)
+	}
+	res := &Result{
+		X:            x,
+		ResidualNorm: ctx.history[len(ctx.history)-1],
+		Iterations:   len(ctx.history) - 1,
+		MulVec:       ctx.mulVec,
+		History:      ctx.history,
+	}
+	if err == nil && !ctx.converged(res.ResidualNorm) {
+		err = ErrIterationLimit
+	}
+	return res, err
+}
+
+// reuseAsVec resizes an empty dst to length n, or checks that a non-empty
+// dst has length n.
+func reuseAsVec(dst *mat.VecDense, n int) {
+	if dst.IsEmpty() {
+		dst.ReuseAsVec(n)
+		return
+	}
+	if dst.Len() != n {
+		panic(mat.ErrShape)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// laplacian2D returns the k²×k² matrix of the five-point finite difference
+// discretization of -Δu + shift⋅u on a k×k grid with Dirichlet boundary
+// conditions. If conv is non-zero, a first-order upwind convection term in
+// the x direction is added making the matrix non-symmetric.
+func laplacian2D(k int, shift, conv float64) *mat.CSR {
+	n := k * k
+	coo := mat.NewCOO(n, n, nil, nil, nil)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			row := i*k + j
+			coo.Append(row, row, 4+shift+conv)
+			if i > 0 {
+				coo.Append(row, row-k, -1)
+			}
+			if i < k-1 {
+				coo.Append(row, row+k, -1)
+			}
+			if j > 0 {
+				coo.Append(row, row-1, -1-conv)
+			}
+			if j < k-1 {
+				coo.Append(row, row+1, -1)
+			}
+		}
+	}
+	var a mat.CSR
+	a.CloneFrom(coo)
+	return &a
+}
+
+func randVec(n int, rnd *rand.Rand) *mat.VecDense {
+	v := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		v.SetVec(i, rnd.NormFloat64())
+	}
+	return v
+}
+
+func TestIterative(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+
+	type precon struct {
+		name string
+		new  func(a mat.Matrix) (Preconditioner, error)
+	}
+	none := precon{"none", func(mat.Matrix) (Preconditioner, error) { return nil, nil }}
+	jacobi := precon{"Jacobi", func(a mat.Matrix) (Preconditioner, error) { return NewJacobi(a) }}
+	ic := precon{"IC(0)", func(a mat.Matrix) (Preconditioner, error) { return NewIncompleteCholesky(a) }}
+	ilu := precon{"ILU(0)", func(a mat.Matrix) (Preconditioner, error) { return NewIncompleteLU(a) }}
+
+	spd := laplacian2D(8, 0, 0)
+	indef := laplacian2D(8, -1.5, 0)
+	nonsym := laplacian2D(8, 0, 1.5)
+
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		name    string
+		a       *mat.CSR
+		method  Method
+		precons []precon
+
+		// noReduction indicates that the preconditioners are not
+		// expected to reduce the number of iterations.
+		noReduction bool
+	}{
+		{name: "CG", a: spd, method: CG{}, precons: []precon{none, jacobi, ic}},
+		{name: "MINRES SPD", a: spd, method: MINRES{}, precons: []precon{none, jacobi, ic}},
+		{name: "MINRES indefinite", a: indef, method: MINRES{}, precons: []precon{none, jacobi}, noReduction: true},
+		{name: "GMRES", a: nonsym, method: &GMRES{}, precons: []precon{none, jacobi, ilu}},
+		{name: "GMRES(5)", a: nonsym, method: &GMRES{Restart: 5}, precons: []precon{none, ilu}},
+		{name: "GMRES indefinite", a: indef, method: &GMRES{}, precons: []precon{none, ilu}, noReduction: true},
+		{name: "BiCGStab", a: nonsym, method: BiCGStab{}, precons: []precon{none, jacobi, ilu}},
+	} {
+		n, _ := test.a.Dims()
+		want := randVec(n, rnd)
+		var b mat.VecDense
+		test.a.MulVecTo(&b, false, want)
+
+		var iters []int
+		for _, p := range test.precons {
+			name := fmt.Sprintf("%s with %s", test.name, p.name)
+			m, err := p.new(test.a)
+			if err != nil {
+				t.Fatalf("%s: unexpected preconditioner error: %v", name, err)
+			}
+			res, err := Iterative(test.a, &b, test.method, &Settings{
+				Tolerance:      tol,
+				MaxIterations:  10 * n,
+				Preconditioner: m,
+			})
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", name, err)
+				continue
+			}
+			if len(res.History) != res.Iterations+1 {
+				t.Errorf("%s: history length mismatch: got:%d want:%d", name, len(res.History), res.Iterations+1)
+			}
+			var r mat.VecDense
+			test.a.MulVecTo(&r, false, res.X)
+			r.SubVec(&b, &r)
+			if rnorm := mat.Norm(&r, 2); rnorm > 100*tol*mat.Norm(&b, 2) {
+				t.Errorf("%s: residual too large: got:%g", name, rnorm)
+			}
+			if !mat.EqualApprox(res.X, want, 1e-7) {
+				t.Errorf("%s: unexpected solution", name)
+			}
+			iters = append(iters, res.Iterations)
+		}
+		if !test.noReduction && iters[len(iters)-1] >= iters[0] {
+			t.Errorf("%s: preconditioning did not reduce iterations: %v", test.name, iters)
+		}
+	}
+}
+
+func TestIterativeIterationLimit(t *testing.T) {
+	t.Parallel()
+	a := laplacian2D(10, 0, 0)
+	b := mat.NewVecDense(100, nil)
+	for i := 0; i < 100; i++ {
+		b.SetVec(i, 1)
+	}
+	res, err := Iterative(a, b, CG{}, &Settings{MaxIterations: 3})
+	if err != ErrIterationLimit {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrIterationLimit)
+	}
+	if res.Iterations != 3 {
+		t.Errorf("unexpected number of iterations: got:%d want:3", res.Iterations)
+	}
+}
+
+func TestIterativeInitX(t *testing.T) {
+	t.Parallel()
+	a := laplacian2D(4, 0, 0)
+	rnd := rand.New(rand.NewSource(1))
+	want := randVec(16, rnd)
+	var b mat.VecDense
+	a.MulVecTo(&b, false, want)
+	res, err := Iterative(a, &b, nil, &Settings{InitX: want})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Iterations != 0 || res.MulVec != 1 {
+		t.Errorf("unexpected work for exact initial guess: iterations=%d mulvec=%d", res.Iterations, res.MulVec)
+	}
+}
+
+func TestIncompleteFactorizationsExact(t *testing.T) {
+	t.Parallel()
+	// Incomplete factorizations of a tridiagonal matrix have no fill-in
+	// and so are exact.
+	const n = 10
+	coo := mat.NewCOO(n, n, nil, nil, nil)
+	for i := 0; i < n; i++ {
+		coo.Append(i, i, 3)
+		if i > 0 {
+			coo.Append(i, i-1, -1)
+			coo.Append(i-1, i, -1)
+		}
+	}
+	a := mat.DenseCopyOf(coo)
+
+	rnd := rand.New(rand.NewSource(1))
+	want := randVec(n, rnd)
+	var b mat.VecDense
+	b.MulVec(a, want)
+
+	ic, err := NewIncompleteCholesky(a)
+	if err != nil {
+		t.Fatalf("unexpected IC(0) error: %v", err)
+	}
+	ilu, err := NewIncompleteLU(a)
+	if err != nil {
+		t.Fatalf("unexpected ILU(0) error: %v", err)
+	}
+	for _, p := range []Preconditioner{ic, ilu} {
+		var got mat.VecDense
+		err := p.PreconSolve(&got, &b)
+		if err != nil {
+			t.Errorf("unexpected error for %T: %v", p, err)
+		}
+		if !mat.EqualApprox(&got, want, 1e-12) {
+			t.Errorf("unexpected solution for %T", p)
+		}
+	}
+
+	_, err = NewIncompleteCholesky(laplacian2D(3, -10, 0))
+	if err != ErrNotPositiveDefinite {
+		t.Errorf("unexpected error for indefinite matrix: got:%v want:%v", err, ErrNotPositiveDefinite)
+	}
+}
+
+func TestOperator(t *testing.T) {
+	t.Parallel()
+	a := mat.NewDense(2, 2, []float64{4, 1, 2, 3})
+	b := mat.NewVecDense(2, []float64{1, 2})
+	res, err := Iterative(Operator(a), b, BiCGStab{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var want mat.VecDense
+	if err := want.SolveVec(a, b); err != nil {
+		t.Fatalf("unexpected solve error: %v", err)
+	}
+	if !mat.EqualApprox(res.X, &want, 1e-10) {
+		t.Errorf("unexpected solution: got:%v want:%v", mat.Formatted(res.X.T()), mat.Formatted(want.T()))
+	}
+
+	// A matrix-free operator for the same system.
+	var calls int
+	op := MulVecFunc(func(dst *mat.VecDense, trans bool, x mat.Vector) {
+		calls++
+		dst.MulVec(a, x)
+	})
+	res, err = Iterative(op, b, &GMRES{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != res.MulVec {
+		t.Errorf("mismatched product count: got:%d want:%d", res.MulVec, calls)
+	}
+}
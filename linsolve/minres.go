@@ -0,0 +1,108 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// MINRES implements the preconditioned Minimal Residual method for solving
+// systems with a symmetric, possibly indefinite, matrix. The preconditioner
+// must be symmetric positive definite.
+//
+// The residual norm reported to the Context is the estimate computed by the
+// MINRES recurrences. Without a preconditioner it is the Euclidean norm of
+// the residual; with a preconditioner M it is the norm of the residual
+// measured in the M⁻¹-norm.
+//
+// References:
+//   - Paige, C. C., and Saunders, M. A. (1975). Solution of sparse indefinite
+//     systems of linear equations. SIAM Journal on Numerical Analysis,
+//     12(4), 617-629.
+type MINRES struct{}
+
+// Solve implements the Method interface. Solve returns
+// ErrNotPositiveDefinite if the preconditioner is found not to be positive
+// definite.
+func (MINRES) Solve(ctx *Context) error {
+	n := ctx.X.Len()
+	r1 := mat.VecDenseCopyOf(ctx.Residual)
+	r2 := mat.VecDenseCopyOf(ctx.Residual)
+	y := mat.NewVecDense(n, nil)
+	v := mat.NewVecDense(n, nil)
+	w := mat.NewVecDense(n, nil)
+	w1 := mat.NewVecDense(n, nil)
+	w2 := mat.NewVecDense(n, nil)
+
+	if err := ctx.PreconSolve(y, r1); err != nil {
+		return err
+	}
+	beta1 := mat.Dot(r1, y)
+	if beta1 <= 0 {
+		return ErrNotPositiveDefinite
+	}
+	beta1 = math.Sqrt(beta1)
+
+	var (
+		oldb, dbar, epsln float64
+		beta              = beta1
+		phibar            = beta1
+		cs, sn            = -1.0, 0.0
+	)
+	for first := true; ; first = false {
+		// Lanczos step.
+		v.ScaleVec(1/beta, y)
+		ctx.MulVec(y, v)
+		if !first {
+			y.AddScaledVec(y, -beta/oldb, r1)
+		}
+		alpha := mat.Dot(v, y)
+		y.AddScaledVec(y, -alpha/beta, r2)
+		r1.CopyVec(r2)
+		r2.CopyVec(y)
+		if err := ctx.PreconSolve(y, r2); err != nil {
+			return err
+		}
+		oldb = beta
+		beta = mat.Dot(r2, y)
+		if beta < 0 {
+			return ErrNotPositiveDefinite
+		}
+		beta = math.Sqrt(beta)
+
+		// Apply the previous rotation and compute the next.
+		oldeps := epsln
+		delta := cs*dbar + sn*alpha
+		gbar := sn*dbar - cs*alpha
+		epsln = sn * beta
+		dbar = -cs * beta
+		gamma := math.Hypot(gbar, beta)
+		if gamma == 0 {
+			return ErrBreakdown
+		}
+		cs = gbar / gamma
+		sn = beta / gamma
+		phi := cs * phibar
+		phibar *= sn
+
+		// Update the search direction and the solution.
+		w1.CopyVec(w2)
+		w2.CopyVec(w)
+		w.AddScaledVec(v, -oldeps, w1)
+		w.AddScaledVec(w, -delta, w2)
+		w.ScaleVec(1/gamma, w)
+		ctx.X.AddScaledVec(ctx.X, phi, w)
+
+		if ctx.Iterate(phibar) {
+			return nil
+		}
+		if beta == 0 {
+			// The Krylov subspace is invariant so x is exact.
+			return nil
+		}
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Preconditioner represents a preconditioner M, an approximation to the
+// system matrix A for which systems can be solved cheaply.
+type Preconditioner interface {
+	// PreconSolve solves M⋅dst = rhs.
+	PreconSolve(dst *mat.VecDense, rhs mat.Vector) error
+}
+
+// PreconFunc is a function type that implements Preconditioner.
+type PreconFunc func(dst *mat.VecDense, rhs mat.Vector) error
+
+// PreconSolve returns fn(dst, rhs).
+func (fn PreconFunc) PreconSolve(dst *mat.VecDense, rhs mat.Vector) error {
+	return fn(dst, rhs)
+}
+
+type identity struct{}
+
+func (identity) PreconSolve(dst *mat.VecDense, rhs mat.Vector) error {
+	reuseAsVec(dst, rhs.Len())
+	dst.CopyVec(rhs)
+	return nil
+}
+
+// Jacobi is a diagonal preconditioner holding the diagonal of a matrix.
+type Jacobi struct {
+	diag []float64
+}
+
+// NewJacobi returns a Jacobi preconditioner for the square matrix a. It
+// returns ErrBreakdown if a has a zero diagonal element.
+func NewJacobi(a mat.Matrix) (*Jacobi, error) {
+	r, c := a.Dims()
+	if r != c {
+		panic(mat.ErrSquare)
+	}
+	diag := make([]float64, r)
+	for i := range diag {
+		diag[i] = a.At(i, i)
+		if diag[i] == 0 {
+			return nil, ErrBreakdown
+		}
+	}
+	return &Jacobi{diag: diag}, nil
+}
+
+// PreconSolve solves D⋅dst = rhs where D is the diagonal of the matrix.
+func (p *Jacobi) PreconSolve(dst *mat.VecDense, rhs mat.Vector) error {
+	if rhs.Len() != len(p.diag) {
+		panic(mat.ErrShape)
+	}
+	reuseAsVec(dst, len(p.diag))
+	for i, d := range p.diag {
+		dst.SetVec(i, rhs.AtVec(i)/d)
+	}
+	return nil
+}
+
+// sparseRow holds the sorted column indices and values of the non-zero
+// elements of a matrix row.
+type sparseRow struct {
+	ind []int
+	val []float64
+}
+
+// find returns the index into r.ind of column j, or -1 if j is not stored.
+func (r sparseRow) find(j int) int {
+	k := sort.SearchInts(r.ind, j)
+	if k < len(r.ind) && r.ind[k] == j {
+		return k
+	}
+	return -1
+}
+
+// sparseRows returns the rows of the square matrix a, restricted to the
+// lower triangle if lower is true. The elements of a are read with
+// DoRowNonZero when a is a mat.RowNonZeroDoer.
+func sparseRows(a mat.Matrix, lower bool) []sparseRow {
+	r, c := a.Dims()
+	if r != c {
+		panic(mat.ErrSquare)
+	}
+	rows := make([]sparseRow, r)
+	keep := func(i, j int) bool { return !lower || j <= i }
+	if nz, ok := a.(mat.RowNonZeroDoer); ok {
+		for i := range rows {
+			row := &rows[i]
+			nz.DoRowNonZero(i, func(i, j int, v float64) {
+				if keep(i, j) {
+					row.ind = append(row.ind, j)
+					row.val = append(row.val, v)
+				}
+			})
+			sort.Sort(byIndex(*row))
+		}
+		return rows
+	}
+	for i := range rows {
+		for j := 0; j < c; j++ {
+			if v := a.At(i, j); v != 0 && keep(i, j) {
+				rows[i].ind = append(rows[i].ind, j)
+				rows[i].val = append(rows[i].val, v)
+			}
+		}
+	}
+	return rows
+}
+
+type byIndex sparseRow
+
+func (r byIndex) Len() int           { return len(r.ind) }
+func (r byIndex) Less(i, j int) bool { return r.ind[i] < r.ind[j] }
+func (r byIndex) Swap(i, j int) {
+	r.ind[i], r.ind[j] = r.ind[j], r.ind[i]
+	r.val[i], r.val[j] = r.val[j], r.val[i]
+}
+
+// IncompleteCholesky is a zero fill-in incomplete Cholesky preconditioner,
+// IC(0), for symmetric positive definite matrices. It holds a lower
+// triangular factor L with the sparsity pattern of the lower triangle of
+// the matrix such that L⋅Lᵀ approximates the matrix.
+type IncompleteCholesky struct {
+	// rows holds the rows of L with the diagonal element last.
+	rows []sparseRow
+}
+
+// NewIncompleteCholesky returns the IC(0) factorization of the symmetric
+// matrix a. Only the lower triangle of a is used. NewIncompleteCholesky
+// returns ErrNotPositiveDefinite if a non-positive pivot is encountered.
+func NewIncompleteCholesky(a mat.Matrix) (*IncompleteCholesky, error) {
+	rows := sparseRows(a, true)
+	for i, row := range rows {
+		n := len(row.ind)
+		if n == 0 || row.ind[n-1] != i {
+			return nil, ErrNotPositiveDefinite
+		}
+		var sum float64
+		for p, j := range row.ind[:n-1] {
+			// L_ij = (a_ij - Σ_{k<j} L_ik L_jk) / L_jj
+			rj := rows[j]
+			v := row.val[p] - sparseDot(row.ind[:p], row.val[:p], rj.ind[:len(rj.ind)-1], rj.val[:len(rj.val)-1])
+			v /= rj.val[len(rj.val)-1]
+			row.val[p] = v
+			sum += v * v
+		}
+		d := row.val[n-1] - sum
+		if d <= 0 {
+			return nil, ErrNotPositiveDefinite
+		}
+		row.val[n-1] = math.Sqrt(d)
+	}
+	return &IncompleteCholesky{rows: rows}, nil
+}
+
+// PreconSolve solves L⋅Lᵀ⋅dst = rhs.
+func (p *IncompleteCholesky) PreconSolve(dst *mat.VecDense, rhs mat.Vector) error {
+	n := len(p.rows)
+	if rhs.Len() != n {
+		panic(mat.ErrShape)
+	}
+	y := make([]float64, n)
+	for i, row := range p.rows {
+		last := len(row.ind) - 1
+		v := rhs.AtVec(i)
+		for k, j := range row.ind[:last] {
+			v -= row.val[k] * y[j]
+		}
+		y[i] = v / row.val[last]
+	}
+	for i := n - 1; i >= 0; i-- {
+		row := p.rows[i]
+		last := len(row.ind) - 1
+		y[i] /= row.val[last]
+		for k, j := range row.ind[:last] {
+			y[j] -= row.val[k] * y[i]
+		}
+	}
+	reuseAsVec(dst, n)
+	for i, v := range y {
+		dst.SetVec(i, v)
+	}
+	return nil
+}
+
+// sparseDot returns the dot product of two sparse vectors with sorted
+// indices.
+func sparseDot(ia []int, a []float64, ib []int, b []float64) float64 {
+	var sum float64
+	for p, q := 0, 0; p < len(ia) && q < len(ib); {
+		switch {
+		case ia[p] < ib[q]:
+			p++
+		case ia[p] > ib[q]:
+			q++
+		default:
+			sum += a[p] * b[q]
+			p++
+			q++
+		}
+	}
+	return sum
+}
+
+// IncompleteLU is a zero fill-in incomplete LU preconditioner, ILU(0). It
+// holds a unit lower triangular factor L and an upper triangular factor U,
+// both with the sparsity pattern of the matrix, such that L⋅U approximates
+// the matrix.
+type IncompleteLU struct {
+	// rows holds the strictly lower elements of L and the upper
+	// elements of U.
+	rows []sparseRow
+	// diag holds the index of the diagonal element in each row.
+	diag []int
+}
+
+// NewIncompleteLU returns the ILU(0) factorization of the square matrix a.
+// NewIncompleteLU returns ErrBreakdown if a zero pivot is encountered.
+func NewIncompleteLU(a mat.Matrix) (*IncompleteLU, error) {
+	rows := sparseRows(a, false)
+	diag := make([]int, len(rows))
+	for i, row := range rows {
+		diag[i] = row.find(i)
+		if diag[i] < 0 {
+			return nil, ErrBreakdown
+		}
+	}
+	for i, row := range rows {
+		for p := 0; p < diag[i]; p++ {
+			k := row.ind[p]
+			rk := rows[k]
+			piv := rk.val[diag[k]]
+			if piv == 0 {
+				return nil, ErrBreakdown
+			}
+			row.val[p] /= piv
+			lik := row.val[p]
+			for q := p + 1; q < len(row.ind); q++ {
+				if r := rk.find(row.ind[q]); r >= 0 {
+					row.val[q] -= lik * rk.val[r]
+				}
+			}
+		}
+		if row.val[diag[i]] == 0 {
+			return nil, ErrBreakdown
+		}
+	}
+	return &IncompleteLU{rows: rows, diag: diag}, nil
+}
+
+// PreconSolve solves L⋅U⋅dst = rhs.
+func (p *IncompleteLU) PreconSolve(dst *mat.VecDense, rhs mat.Vector) error {
+	n := len(p.rows)
+	if rhs.Len() != n {
+		panic(mat.ErrShape)
+	}
+	y := make([]float64, n)
+	for i, row := range p.rows {
+		v := rhs.AtVec(i)
+		for k, j := range row.ind[:p.diag[i]] {
+			v -= row.val[k] * y[j]
+		}
+		y[i] = v
+	}
+	for i := n - 1; i >= 0; i-- {
+		row := p.rows[i]
+		d := p.diag[i]
+		v := y[i]
+		for k := d + 1; k < len(row.ind); k++ {
+			v -= row.val[k] * y[row.ind[k]]
+		}
+		y[i] = v / row.val[d]
+	}
+	reuseAsVec(dst, n)
+	for i, v := range y {
+		dst.SetVec(i, v)
+	}
+	return nil
+}
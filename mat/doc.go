@@ -38,6 +38,16 @@
 //	}
 //	a := mat.NewDense(6, 6, data)
 //
+// Large matrices with few non-zero elements may instead be stored in the
+// sparse COO, CSR and CSC types. A COO matrix is convenient for assembling
+// elements, and CSR and CSC matrices provide sparse-aware arithmetic.
+//
+//	// Assemble a sparse matrix and convert it for arithmetic.
+//	coo := mat.NewCOO(1000, 1000, nil, nil, nil)
+//	coo.Append(0, 0, 1)
+//	var s mat.CSR
+//	s.CloneFrom(coo)
+//
 // Operations involving matrix data are implemented as functions when the values
 // of the matrix remain unchanged
 //
@@ -0,0 +1,912 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "sort"
+
+var (
+	cooDense *COO
+	_        Matrix      = cooDense
+	_        allMatrix   = cooDense
+	_        Mutable     = cooDense
+	_        NonZeroDoer = cooDense
+
+	csrDense *CSR
+	_        Matrix         = csrDense
+	_        allMatrix      = csrDense
+	_        ClonerFrom     = csrDense
+	_        NonZeroDoer    = csrDense
+	_        RowNonZeroDoer = csrDense
+
+	cscDense *CSC
+	_        Matrix         = cscDense
+	_        allMatrix      = cscDense
+	_        ClonerFrom     = cscDense
+	_        NonZeroDoer    = cscDense
+	_        ColNonZeroDoer = cscDense
+)
+
+// COO represents a sparse matrix in coordinate (triplet) format. A COO
+// matrix is convenient for incremental construction of a sparse matrix that
+// is then converted to CSR or CSC format for arithmetic using CloneFrom.
+//
+// Entries with the same row and column indices are allowed, and their values
+// are summed when the matrix is read.
+type COO struct {
+	r, c int
+	rows []int
+	cols []int
+	data []float64
+}
+
+// NewCOO creates a new r×c sparse matrix in coordinate format with the
+// element data[k] at row rows[k] and column cols[k]. If rows, cols and data
+// are all nil, an empty coordinate list is created. The slices are used as
+// backing data; elements added with Append may be stored in them if capacity
+// allows. NewCOO will panic if r or c is not positive, if the three slices are
+// not of equal length or if an index is out of range.
+func NewCOO(r, c int, rows, cols []int, data []float64) *COO {
+	checkSparseDims(r, c)
+	if len(rows) != len(data) || len(cols) != len(data) {
+		panic(ErrSliceLengthMismatch)
+	}
+	for k := range data {
+		if uint(rows[k]) >= uint(r) {
+			panic(ErrRowAccess)
+		}
+		if uint(cols[k]) >= uint(c) {
+			panic(ErrColAccess)
+		}
+	}
+	return &COO{r: r, c: c, rows: rows, cols: cols, data: data}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *COO) Dims() (r, c int) {
+	return m.r, m.c
+}
+
+// At returns the element at row i, column j. At is a linear-time operation
+// in the number of stored entries.
+func (m *COO) At(i, j int) float64 {
+	if uint(i) >= uint(m.r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.c) {
+		panic(ErrColAccess)
+	}
+	var v float64
+	for k, r := range m.rows {
+		if r == i && m.cols[k] == j {
+			v += m.data[k]
+		}
+	}
+	return v
+}
+
+// Set sets the element at row i, column j to the value v, removing any
+// other entries stored at that position. Set is a linear-time operation in
+// the number of stored entries; use Append for constructing matrices.
+func (m *COO) Set(i, j int, v float64) {
+	if uint(i) >= uint(m.r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.c) {
+		panic(ErrColAccess)
+	}
+	var n int
+	for k, r := range m.rows {
+		if r == i && m.cols[k] == j {
+			continue
+		}
+		m.rows[n] = r
+		m.cols[n] = m.cols[k]
+		m.data[n] = m.data[k]
+		n++
+	}
+	m.rows = m.rows[:n]
+	m.cols = m.cols[:n]
+	m.data = m.data[:n]
+	m.Append(i, j, v)
+}
+
+// Append adds an entry with value v at row i, column j. If an entry at that
+// position already exists, the values are summed.
+func (m *COO) Append(i, j int, v float64) {
+	if uint(i) >= uint(m.r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.c) {
+		panic(ErrColAccess)
+	}
+	m.rows = append(m.rows, i)
+	m.cols = append(m.cols, j)
+	m.data = append(m.data, v)
+}
+
+// NNZ returns the number of stored entries in the matrix, including any
+// duplicate and explicitly stored zero entries.
+func (m *COO) NNZ() int {
+	return len(m.data)
+}
+
+// T returns the transpose of the receiver as a COO matrix. The returned
+// matrix shares backing data with the receiver.
+func (m *COO) T() Matrix {
+	return &COO{r: m.c, c: m.r, rows: m.cols, cols: m.rows, data: m.data}
+}
+
+// IsEmpty returns whether the receiver is empty. Empty matrices can be the
+// receiver for size-restricted operations. The receiver can be emptied using
+// Reset.
+func (m *COO) IsEmpty() bool {
+	return m.r == 0
+}
+
+// Reset empties the matrix so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+//
+// Reset should not be used when the matrix shares backing data. See the Reseter
+// interface for more information.
+func (m *COO) Reset() {
+	m.r, m.c = 0, 0
+	m.rows = m.rows[:0]
+	m.cols = m.cols[:0]
+	m.data = m.data[:0]
+}
+
+// Zero removes all stored entries from the matrix, retaining its dimensions.
+func (m *COO) Zero() {
+	m.rows = m.rows[:0]
+	m.cols = m.cols[:0]
+	m.data = m.data[:0]
+}
+
+// DoNonZero calls the function fn for each of the non-zero stored entries of
+// the receiver. Duplicate entries are passed to fn individually.
+func (m *COO) DoNonZero(fn func(i, j int, v float64)) {
+	for k, v := range m.data {
+		if v != 0 {
+			fn(m.rows[k], m.cols[k], v)
+		}
+	}
+}
+
+// ToDense stores the receiver into dst. If dst is empty it is resized to
+// the dimensions of the receiver, otherwise it must have the same shape.
+func (m *COO) ToDense(dst *Dense) {
+	dst.reuseAsZeroed(m.r, m.c)
+	for k, v := range m.data {
+		dst.set(m.rows[k], m.cols[k], dst.at(m.rows[k], m.cols[k])+v)
+	}
+}
+
+// CSR represents a sparse matrix in compressed sparse row format. The column
+// indices and values of the elements of row i are held in
+// ind[indptr[i]:indptr[i+1]] and data[indptr[i]:indptr[i+1]] respectively,
+// with column indices in strictly increasing order.
+type CSR struct {
+	mat compressed
+}
+
+// NewCSR creates a new r×c sparse matrix in compressed sparse row format. If
+// indptr, ind and data are all nil, a matrix with no stored elements is
+// created. Otherwise indptr must have length r+1, start at zero and be
+// non-decreasing, ind and data must have length indptr[r], and the column
+// indices of each row must be strictly increasing and less than c. The slices
+// are used as backing data so changes to elements of the returned matrix will
+// be reflected in data. NewCSR will panic if these conditions are not met.
+func NewCSR(r, c int, indptr, ind []int, data []float64) *CSR {
+	checkSparseDims(r, c)
+	return &CSR{mat: newCompressed(r, c, indptr, ind, data)}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *CSR) Dims() (r, c int) {
+	return m.mat.major, m.mat.minor
+}
+
+// At returns the element at row i, column j.
+func (m *CSR) At(i, j int) float64 {
+	if uint(i) >= uint(m.mat.major) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.mat.minor) {
+		panic(ErrColAccess)
+	}
+	return m.mat.at(i, j)
+}
+
+// NNZ returns the number of stored elements in the matrix.
+func (m *CSR) NNZ() int {
+	return len(m.mat.data)
+}
+
+// T returns the transpose of the receiver as a CSC matrix. The returned
+// matrix shares backing data with the receiver.
+func (m *CSR) T() Matrix {
+	return &CSC{mat: m.mat}
+}
+
+// IsEmpty returns whether the receiver is empty. Empty matrices can be the
+// receiver for size-restricted operations. The receiver can be emptied using
+// Reset.
+func (m *CSR) IsEmpty() bool {
+	return m.mat.major == 0
+}
+
+// Reset empties the matrix so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+//
+// Reset should not be used when the matrix shares backing data. See the Reseter
+// interface for more information.
+func (m *CSR) Reset() {
+	m.mat.reset()
+}
+
+// Zero sets all stored elements of the receiver to zero, retaining the
+// sparsity pattern.
+func (m *CSR) Zero() {
+	for k := range m.mat.data {
+		m.mat.data[k] = 0
+	}
+}
+
+// CloneFrom makes a copy of a into the receiver in compressed sparse row
+// format, overwriting the previous value of the receiver. CloneFrom does not
+// place any restrictions on receiver shape. Zero elements of a are not
+// stored.
+func (m *CSR) CloneFrom(a Matrix) {
+	if a == m {
+		return
+	}
+	aU, trans := untranspose(a)
+	switch aU := aU.(type) {
+	case *CSR:
+		if !trans {
+			m.mat = aU.mat.clone()
+			return
+		}
+	case *CSC:
+		// The column-major storage of a CSC matrix is the
+		// row-major storage of its transpose.
+		if trans {
+			m.mat = aU.mat.clone()
+			return
+		}
+	}
+	m.mat = compressedOf(a, true)
+}
+
+// ToDense stores the receiver into dst. If dst is empty it is resized to
+// the dimensions of the receiver, otherwise it must have the same shape.
+func (m *CSR) ToDense(dst *Dense) {
+	dst.reuseAsZeroed(m.mat.major, m.mat.minor)
+	m.mat.doNonZero(func(i, j int, v float64) {
+		dst.set(i, j, v)
+	})
+}
+
+// Mul takes the matrix product of a and b, placing the result in the receiver.
+// Operands that are not *CSR are converted to compressed sparse row format
+// before multiplication; a *CSC operand is converted in linear time. If the
+// number of columns in a does not equal the number of rows in b, Mul will
+// panic. If the receiver is not empty it must have the shape of the result.
+func (m *CSR) Mul(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(ErrShape)
+	}
+	m.checkReuse(ar, bc)
+	m.mat = mulCompressed(compressedOf(a, true), compressedOf(b, true))
+}
+
+// Add adds a and b element-wise, placing the result in the receiver. Add will
+// panic if the two matrices do not have the same shape. If the receiver is
+// not empty it must have the shape of the result.
+func (m *CSR) Add(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		panic(ErrShape)
+	}
+	m.checkReuse(ar, ac)
+	m.mat = addCompressed(compressedOf(a, true), compressedOf(b, true))
+}
+
+// Scale multiplies the elements of a by f, placing the result in the receiver.
+// If the receiver is not empty it must have the shape of a.
+func (m *CSR) Scale(f float64, a Matrix) {
+	r, c := a.Dims()
+	m.checkReuse(r, c)
+	m.mat = compressedOf(a, true).scale(f)
+}
+
+// MulVecTo computes A⋅x or Aᵀ⋅x storing the result into dst.
+func (m *CSR) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	r, c := m.Dims()
+	if trans {
+		if x.Len() != r {
+			panic(ErrShape)
+		}
+		dst.reuseAsNonZeroed(c)
+		m.mat.mulVecMinor(dst, x)
+		return
+	}
+	if x.Len() != c {
+		panic(ErrShape)
+	}
+	dst.reuseAsNonZeroed(r)
+	m.mat.mulVecMajor(dst, x)
+}
+
+// DoNonZero calls the function fn for each of the non-zero elements of the
+// receiver. The function fn takes a row/column index and the element value
+// of the receiver at (i, j).
+func (m *CSR) DoNonZero(fn func(i, j int, v float64)) {
+	m.mat.doNonZero(fn)
+}
+
+// DoRowNonZero calls the function fn for each of the non-zero elements of row
+// i of the receiver. The function fn takes a row/column index and the element
+// value of the receiver at (i, j).
+func (m *CSR) DoRowNonZero(i int, fn func(i, j int, v float64)) {
+	if uint(i) >= uint(m.mat.major) {
+		panic(ErrRowAccess)
+	}
+	m.mat.doLineNonZero(i, fn)
+}
+
+func (m *CSR) checkReuse(r, c int) {
+	if !m.IsEmpty() && (r != m.mat.major || c != m.mat.minor) {
+		panic(ErrShape)
+	}
+}
+
+// CSC represents a sparse matrix in compressed sparse column format. The row
+// indices and values of the elements of column j are held in
+// ind[indptr[j]:indptr[j+1]] and data[indptr[j]:indptr[j+1]] respectively,
+// with row indices in strictly increasing order.
+type CSC struct {
+	// mat holds the receiver's transpose in row-major order.
+	mat compressed
+}
+
+// NewCSC creates a new r×c sparse matrix in compressed sparse column format.
+// If indptr, ind and data are all nil, a matrix with no stored elements is
+// created. Otherwise indptr must have length c+1, start at zero and be
+// non-decreasing, ind and data must have length indptr[c], and the row
+// indices of each column must be strictly increasing and less than r. The
+// slices are used as backing data so changes to elements of the returned
+// matrix will be reflected in data. NewCSC will panic if these conditions are
+// not met.
+func NewCSC(r, c int, indptr, ind []int, data []float64) *CSC {
+	checkSparseDims(r, c)
+	return &CSC{mat: newCompressed(c, r, indptr, ind, data)}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *CSC) Dims() (r, c int) {
+	return m.mat.minor, m.mat.major
+}
+
+// At returns the element at row i, column j.
+func (m *CSC) At(i, j int) float64 {
+	if uint(i) >= uint(m.mat.minor) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.mat.major) {
+		panic(ErrColAccess)
+	}
+	return m.mat.at(j, i)
+}
+
+// NNZ returns the number of stored elements in the matrix.
+func (m *CSC) NNZ() int {
+	return len(m.mat.data)
+}
+
+// T returns the transpose of the receiver as a CSR matrix. The returned
+// matrix shares backing data with the receiver.
+func (m *CSC) T() Matrix {
+	return &CSR{mat: m.mat}
+}
+
+// IsEmpty returns whether the receiver is empty. Empty matrices can be the
+// receiver for size-restricted operations. The receiver can be emptied using
+// Reset.
+func (m *CSC) IsEmpty() bool {
+	return m.mat.major == 0
+}
+
+// Reset empties the matrix so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+//
+// Reset should not be used when the matrix shares backing data. See the Reseter
+// interface for more information.
+func (m *CSC) Reset() {
+	m.mat.reset()
+}
+
+// Zero sets all stored elements of the receiver to zero, retaining the
+// sparsity pattern.
+func (m *CSC) Zero() {
+	for k := range m.mat.data {
+		m.mat.data[k] = 0
+	}
+}
+
+// CloneFrom makes a copy of a into the receiver in compressed sparse column
+// format, overwriting the previous value of the receiver. CloneFrom does not
+// place any restrictions on receiver shape. Zero elements of a are not
+// stored.
+func (m *CSC) CloneFrom(a Matrix) {
+	if a == m {
+		return
+	}
+	aU, trans := untranspose(a)
+	switch aU := aU.(type) {
+	case *CSC:
+		if !trans {
+			m.mat = aU.mat.clone()
+			return
+		}
+	case *CSR:
+		// The row-major storage of a CSR matrix is the
+		// column-major storage of its transpose.
+		if trans {
+			m.mat = aU.mat.clone()
+			return
+		}
+	}
+	m.mat = compressedOf(a, false)
+}
+
+// ToDense stores the receiver into dst. If dst is empty it is resized to
+// the dimensions of the receiver, otherwise it must have the same shape.
+func (m *CSC) ToDense(dst *Dense) {
+	dst.reuseAsZeroed(m.mat.minor, m.mat.major)
+	m.mat.doNonZero(func(j, i int, v float64) {
+		dst.set(i, j, v)
+	})
+}
+
+// Mul takes the matrix product of a and b, placing the result in the receiver.
+// Operands that are not *CSC are converted to compressed sparse column format
+// before multiplication; a *CSR operand is converted in linear time. If the
+// number of columns in a does not equal the number of rows in b, Mul will
+// panic. If the receiver is not empty it must have the shape of the result.
+func (m *CSC) Mul(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(ErrShape)
+	}
+	m.checkReuse(ar, bc)
+	// The column-major storage of a⋅b is the row-major storage of bᵀ⋅aᵀ.
+	m.mat = mulCompressed(compressedOf(b, false), compressedOf(a, false))
+}
+
+// Add adds a and b element-wise, placing the result in the receiver. Add will
+// panic if the two matrices do not have the same shape. If the receiver is
+// not empty it must have the shape of the result.
+func (m *CSC) Add(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		panic(ErrShape)
+	}
+	m.checkReuse(ar, ac)
+	m.mat = addCompressed(compressedOf(a, false), compressedOf(b, false))
+}
+
+// Scale multiplies the elements of a by f, placing the result in the receiver.
+// If the receiver is not empty it must have the shape of a.
+func (m *CSC) Scale(f float64, a Matrix) {
+	r, c := a.Dims()
+	m.checkReuse(r, c)
+	m.mat = compressedOf(a, false).scale(f)
+}
+
+// MulVecTo computes A⋅x or Aᵀ⋅x storing the result into dst.
+func (m *CSC) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	r, c := m.Dims()
+	if trans {
+		if x.Len() != r {
+			panic(ErrShape)
+		}
+		dst.reuseAsNonZeroed(c)
+		m.mat.mulVecMajor(dst, x)
+		return
+	}
+	if x.Len() != c {
+		panic(ErrShape)
+	}
+	dst.reuseAsNonZeroed(r)
+	m.mat.mulVecMinor(dst, x)
+}
+
+// DoNonZero calls the function fn for each of the non-zero elements of the
+// receiver. The function fn takes a row/column index and the element value
+// of the receiver at (i, j).
+func (m *CSC) DoNonZero(fn func(i, j int, v float64)) {
+	m.mat.doNonZero(func(j, i int, v float64) {
+		fn(i, j, v)
+	})
+}
+
+// DoColNonZero calls the function fn for each of the non-zero elements of
+// column j of the receiver. The function fn takes a row/column index and the
+// element value of the receiver at (i, j).
+func (m *CSC) DoColNonZero(j int, fn func(i, j int, v float64)) {
+	if uint(j) >= uint(m.mat.major) {
+		panic(ErrColAccess)
+	}
+	m.mat.doLineNonZero(j, func(j, i int, v float64) {
+		fn(i, j, v)
+	})
+}
+
+func (m *CSC) checkReuse(r, c int) {
+	if !m.IsEmpty() && (r != m.mat.minor || c != m.mat.major) {
+		panic(ErrShape)
+	}
+}
+
+func checkSparseDims(r, c int) {
+	if r <= 0 || c <= 0 {
+		if r == 0 || c == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+}
+
+// compressed is the storage shared by CSR and CSC matrices. It holds a
+// major×minor matrix in row-major compressed form; the elements of major line
+// i have minor indices ind[indptr[i]:indptr[i+1]] in strictly increasing order
+// and values data[indptr[i]:indptr[i+1]].
+type compressed struct {
+	major, minor int
+	indptr       []int
+	ind          []int
+	data         []float64
+}
+
+func newCompressed(major, minor int, indptr, ind []int, data []float64) compressed {
+	if indptr == nil && ind == nil && data == nil {
+		return compressed{major: major, minor: minor, indptr: make([]int, major+1)}
+	}
+	if len(indptr) != major+1 || indptr[0] != 0 {
+		panic(ErrShape)
+	}
+	nnz := indptr[major]
+	if len(ind) != nnz || len(data) != nnz {
+		panic(ErrSliceLengthMismatch)
+	}
+	for i := 0; i < major; i++ {
+		lo, hi := indptr[i], indptr[i+1]
+		if hi < lo {
+			panic(ErrShape)
+		}
+		for k := lo; k < hi; k++ {
+			if uint(ind[k]) >= uint(minor) || (k > lo && ind[k] <= ind[k-1]) {
+				panic(ErrIndexOutOfRange)
+			}
+		}
+	}
+	return compressed{major: major, minor: minor, indptr: indptr, ind: ind, data: data}
+}
+
+func (s *compressed) reset() {
+	s.major, s.minor = 0, 0
+	s.indptr = s.indptr[:0]
+	s.ind = s.ind[:0]
+	s.data = s.data[:0]
+}
+
+func (s compressed) clone() compressed {
+	return compressed{
+		major:  s.major,
+		minor:  s.minor,
+		indptr: append([]int(nil), s.indptr...),
+		ind:    append([]int(nil), s.ind...),
+		data:   append([]float64(nil), s.data...),
+	}
+}
+
+func (s *compressed) at(i, j int) float64 {
+	lo, hi := s.indptr[i], s.indptr[i+1]
+	k := lo + sort.SearchInts(s.ind[lo:hi], j)
+	if k < hi && s.ind[k] == j {
+		return s.data[k]
+	}
+	return 0
+}
+
+// doNonZero calls fn with the major and minor index and the value of each
+// non-zero stored element.
+func (s *compressed) doNonZero(fn func(i, j int, v float64)) {
+	for i := 0; i < s.major; i++ {
+		s.doLineNonZero(i, fn)
+	}
+}
+
+func (s *compressed) doLineNonZero(i int, fn func(i, j int, v float64)) {
+	for k := s.indptr[i]; k < s.indptr[i+1]; k++ {
+		if v := s.data[k]; v != 0 {
+			fn(i, s.ind[k], v)
+		}
+	}
+}
+
+// transpose returns the compressed form of the transpose of s. The minor
+// indices of the result are in increasing order since s is traversed in
+// major order.
+func (s compressed) transpose() compressed {
+	t := compressed{
+		major:  s.minor,
+		minor:  s.major,
+		indptr: make([]int, s.minor+1),
+		ind:    make([]int, len(s.ind)),
+		data:   make([]float64, len(s.data)),
+	}
+	for _, j := range s.ind {
+		t.indptr[j+1]++
+	}
+	for j := 0; j < t.major; j++ {
+		t.indptr[j+1] += t.indptr[j]
+	}
+	next := getInts(t.major, false)
+	copy(next, t.indptr[:t.major])
+	for i := 0; i < s.major; i++ {
+		for k := s.indptr[i]; k < s.indptr[i+1]; k++ {
+			j := s.ind[k]
+			t.ind[next[j]] = i
+			t.data[next[j]] = s.data[k]
+			next[j]++
+		}
+	}
+	putInts(next)
+	return t
+}
+
+// compressedFromTriplets returns the compressed form of the major×minor
+// matrix with elements data[k] at (maj[k], min[k]). Duplicate entries are
+// summed and zero-valued results are not stored.
+func compressedFromTriplets(major, minor int, maj, min []int, data []float64) compressed {
+	// Bucket by minor index and then stably by major index so that the
+	// minor indices in each major line are sorted.
+	byMinor := compressed{
+		major:  minor,
+		minor:  major,
+		indptr: make([]int, minor+1),
+		ind:    make([]int, len(data)),
+		data:   make([]float64, len(data)),
+	}
+	for _, j := range min {
+		byMinor.indptr[j+1]++
+	}
+	for j := 0; j < minor; j++ {
+		byMinor.indptr[j+1] += byMinor.indptr[j]
+	}
+	next := getInts(minor, false)
+	copy(next, byMinor.indptr[:minor])
+	for k, j := range min {
+		byMinor.ind[next[j]] = maj[k]
+		byMinor.data[next[j]] = data[k]
+		next[j]++
+	}
+	putInts(next)
+	s := byMinor.transpose()
+
+	// Merge duplicates and drop zeros in place.
+	var n int
+	start := 0
+	for i := 0; i < s.major; i++ {
+		end := s.indptr[i+1]
+		for k := start; k < end; {
+			j := s.ind[k]
+			v := s.data[k]
+			for k++; k < end && s.ind[k] == j; k++ {
+				v += s.data[k]
+			}
+			if v != 0 {
+				s.ind[n] = j
+				s.data[n] = v
+				n++
+			}
+		}
+		start = end
+		s.indptr[i+1] = n
+	}
+	s.ind = s.ind[:n]
+	s.data = s.data[:n]
+	return s
+}
+
+// compressedOf returns the compressed form of a when rowMajor is true and of
+// aᵀ otherwise. The returned value may share backing data with a.
+func compressedOf(a Matrix, rowMajor bool) compressed {
+	switch a := a.(type) {
+	case *CSR:
+		if rowMajor {
+			return a.mat
+		}
+		return a.mat.transpose()
+	case *CSC:
+		if rowMajor {
+			return a.mat.transpose()
+		}
+		return a.mat
+	case *COO:
+		if rowMajor {
+			return compressedFromTriplets(a.r, a.c, a.rows, a.cols, a.data)
+		}
+		return compressedFromTriplets(a.c, a.r, a.cols, a.rows, a.data)
+	case Untransposer:
+		return compressedOf(a.Untranspose(), !rowMajor)
+	}
+
+	r, c := a.Dims()
+	var (
+		rows, cols []int
+		data       []float64
+	)
+	if nz, ok := a.(NonZeroDoer); ok {
+		nz.DoNonZero(func(i, j int, v float64) {
+			rows = append(rows, i)
+			cols = append(cols, j)
+			data = append(data, v)
+		})
+	} else {
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				if v := a.At(i, j); v != 0 {
+					rows = append(rows, i)
+					cols = append(cols, j)
+					data = append(data, v)
+				}
+			}
+		}
+	}
+	if rowMajor {
+		return compressedFromTriplets(r, c, rows, cols, data)
+	}
+	return compressedFromTriplets(c, r, cols, rows, data)
+}
+
+func (s compressed) scale(f float64) compressed {
+	t := compressed{
+		major:  s.major,
+		minor:  s.minor,
+		indptr: append([]int(nil), s.indptr...),
+		ind:    append([]int(nil), s.ind...),
+		data:   make([]float64, len(s.data)),
+	}
+	for k, v := range s.data {
+		t.data[k] = f * v
+	}
+	return t
+}
+
+// addCompressed returns a+b, merging the sorted minor indices of each line.
+func addCompressed(a, b compressed) compressed {
+	s := compressed{
+		major:  a.major,
+		minor:  a.minor,
+		indptr: make([]int, a.major+1),
+		ind:    make([]int, 0, len(a.ind)+len(b.ind)),
+		data:   make([]float64, 0, len(a.data)+len(b.data)),
+	}
+	for i := 0; i < a.major; i++ {
+		ka, kb := a.indptr[i], b.indptr[i]
+		ea, eb := a.indptr[i+1], b.indptr[i+1]
+		for ka < ea || kb < eb {
+			var (
+				j int
+				v float64
+			)
+			switch {
+			case kb == eb || (ka < ea && a.ind[ka] < b.ind[kb]):
+				j, v = a.ind[ka], a.data[ka]
+				ka++
+			case ka == ea || b.ind[kb] < a.ind[ka]:
+				j, v = b.ind[kb], b.data[kb]
+				kb++
+			default:
+				j, v = a.ind[ka], a.data[ka]+b.data[kb]
+				ka++
+				kb++
+			}
+			if v != 0 {
+				s.ind = append(s.ind, j)
+				s.data = append(s.data, v)
+			}
+		}
+		s.indptr[i+1] = len(s.ind)
+	}
+	return s
+}
+
+// mulCompressed returns a⋅b using Gustavson's row-by-row algorithm.
+func mulCompressed(a, b compressed) compressed {
+	s := compressed{
+		major:  a.major,
+		minor:  b.minor,
+		indptr: make([]int, a.major+1),
+	}
+	acc := getFloat64s(b.minor, true)
+	mark := getInts(b.minor, false)
+	for j := range mark {
+		mark[j] = -1
+	}
+	var cols []int
+	for i := 0; i < a.major; i++ {
+		cols = cols[:0]
+		for ka := a.indptr[i]; ka < a.indptr[i+1]; ka++ {
+			l, av := a.ind[ka], a.data[ka]
+			for kb := b.indptr[l]; kb < b.indptr[l+1]; kb++ {
+				j := b.ind[kb]
+				if mark[j] != i {
+					mark[j] = i
+					acc[j] = 0
+					cols = append(cols, j)
+				}
+				acc[j] += av * b.data[kb]
+			}
+		}
+		sort.Ints(cols)
+		for _, j := range cols {
+			if v := acc[j]; v != 0 {
+				s.ind = append(s.ind, j)
+				s.data = append(s.data, v)
+			}
+		}
+		s.indptr[i+1] = len(s.ind)
+	}
+	putInts(mark)
+	putFloat64s(acc)
+	return s
+}
+
+// mulVecMajor computes dst = S⋅x.
+func (s *compressed) mulVecMajor(dst *VecDense, x Vector) {
+	xv := getFloat64s(s.minor, false)
+	for j := range xv {
+		xv[j] = x.AtVec(j)
+	}
+	for i := 0; i < s.major; i++ {
+		var v float64
+		for k := s.indptr[i]; k < s.indptr[i+1]; k++ {
+			v += s.data[k] * xv[s.ind[k]]
+		}
+		dst.setVec(i, v)
+	}
+	putFloat64s(xv)
+}
+
+// mulVecMinor computes dst = Sᵀ⋅x.
+func (s *compressed) mulVecMinor(dst *VecDense, x Vector) {
+	xv := getFloat64s(s.major, false)
+	for i := range xv {
+		xv[i] = x.AtVec(i)
+	}
+	y := getFloat64s(s.minor, true)
+	for i, xi := range xv {
+		if xi == 0 {
+			continue
+		}
+		for k := s.indptr[i]; k < s.indptr[i+1]; k++ {
+			y[s.ind[k]] += s.data[k] * xi
+		}
+	}
+	for j, v := range y {
+		dst.setVec(j, v)
+	}
+	putFloat64s(y)
+	putFloat64s(xv)
+}
@@ -0,0 +1,305 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// randSparseDense returns an r×c Dense with approximately density×r×c
+// non-zero elements.
+func randSparseDense(r, c int, density float64, rnd *rand.Rand) *Dense {
+	m := NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if rnd.Float64() < density {
+				m.Set(i, j, rnd.NormFloat64())
+			}
+		}
+	}
+	return m
+}
+
+func TestNewCSR(t *testing.T) {
+	t.Parallel()
+	// 1 0 2
+	// 0 0 3
+	// 4 5 0
+	a := NewCSR(3, 3, []int{0, 2, 3, 5}, []int{0, 2, 2, 0, 1}, []float64{1, 2, 3, 4, 5})
+	want := NewDense(3, 3, []float64{1, 0, 2, 0, 0, 3, 4, 5, 0})
+	if !Equal(a, want) {
+		t.Errorf("unexpected CSR matrix:\ngot:\n%v\nwant:\n%v", Formatted(a), Formatted(want))
+	}
+	if a.NNZ() != 5 {
+		t.Errorf("unexpected number of non-zeros: got:%d want:5", a.NNZ())
+	}
+	c := NewCSC(3, 3, []int{0, 2, 3, 5}, []int{0, 2, 2, 0, 1}, []float64{1, 2, 3, 4, 5})
+	if !Equal(c, want.T()) {
+		t.Errorf("unexpected CSC matrix:\ngot:\n%v\nwant:\n%v", Formatted(c), Formatted(want.T()))
+	}
+
+	for _, test := range []struct {
+		name   string
+		r, c   int
+		indptr []int
+		ind    []int
+		data   []float64
+	}{
+		{name: "zero rows", r: 0, c: 3},
+		{name: "short indptr", r: 3, c: 3, indptr: []int{0, 1}, ind: []int{0}, data: []float64{1}},
+		{name: "length mismatch", r: 1, c: 3, indptr: []int{0, 2}, ind: []int{0, 1}, data: []float64{1}},
+		{name: "decreasing indptr", r: 2, c: 3, indptr: []int{0, 2, 1}, ind: []int{0}, data: []float64{1}},
+		{name: "unsorted index", r: 1, c: 3, indptr: []int{0, 2}, ind: []int{1, 0}, data: []float64{1, 2}},
+		{name: "duplicate index", r: 1, c: 3, indptr: []int{0, 2}, ind: []int{1, 1}, data: []float64{1, 2}},
+		{name: "index out of range", r: 1, c: 3, indptr: []int{0, 1}, ind: []int{3}, data: []float64{1}},
+	} {
+		panicked, _ := panics(func() { NewCSR(test.r, test.c, test.indptr, test.ind, test.data) })
+		if !panicked {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func TestCOO(t *testing.T) {
+	t.Parallel()
+	m := NewCOO(3, 4, nil, nil, nil)
+	m.Append(0, 1, 1)
+	m.Append(2, 3, 2)
+	m.Append(0, 1, 3)
+	m.Append(1, 0, 0)
+	want := NewDense(3, 4, []float64{
+		0, 4, 0, 0,
+		0, 0, 0, 0,
+		0, 0, 0, 2,
+	})
+	if !Equal(m, want) {
+		t.Errorf("unexpected COO matrix:\ngot:\n%v\nwant:\n%v", Formatted(m), Formatted(want))
+	}
+	if !Equal(m.T(), want.T()) {
+		t.Errorf("unexpected COO transpose")
+	}
+
+	var csr CSR
+	csr.CloneFrom(m)
+	if !Equal(&csr, want) {
+		t.Errorf("unexpected CSR from COO:\ngot:\n%v\nwant:\n%v", Formatted(&csr), Formatted(want))
+	}
+	if csr.NNZ() != 2 {
+		t.Errorf("duplicates and zeros not compacted: got:%d want:2", csr.NNZ())
+	}
+
+	m.Set(0, 1, -1)
+	want.Set(0, 1, -1)
+	if !Equal(m, want) {
+		t.Errorf("unexpected COO matrix after Set:\ngot:\n%v\nwant:\n%v", Formatted(m), Formatted(want))
+	}
+	var d Dense
+	m.ToDense(&d)
+	if !Equal(&d, want) {
+		t.Errorf("unexpected dense conversion:\ngot:\n%v\nwant:\n%v", Formatted(&d), Formatted(want))
+	}
+}
+
+func TestSparseConversion(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ r, c int }{{1, 1}, {1, 5}, {5, 1}, {4, 7}, {10, 10}} {
+		a := randSparseDense(test.r, test.c, 0.3, rnd)
+		name := fmt.Sprintf("%d×%d", test.r, test.c)
+
+		var csr CSR
+		csr.CloneFrom(a)
+		var csc CSC
+		csc.CloneFrom(a)
+		for _, m := range []Matrix{&csr, &csc, csr.T().T(), csc.T().T()} {
+			if !Equal(m, a) {
+				t.Errorf("%s: unexpected %T:\ngot:\n%v\nwant:\n%v", name, m, Formatted(m), Formatted(a))
+			}
+		}
+		for _, m := range []Matrix{csr.T(), csc.T()} {
+			if !Equal(m, a.T()) {
+				t.Errorf("%s: unexpected transpose %T", name, m)
+			}
+		}
+
+		var fromCSC CSR
+		fromCSC.CloneFrom(&csc)
+		var fromT CSC
+		fromT.CloneFrom(a.T().T())
+		var fromCSRT CSC
+		fromCSRT.CloneFrom(csr.T())
+		if !Equal(&fromCSC, a) || !Equal(&fromT, a) || !Equal(&fromCSRT, a.T()) {
+			t.Errorf("%s: unexpected sparse to sparse conversion", name)
+		}
+
+		// Clones do not share backing data with their source.
+		var srcCSR CSR
+		srcCSR.CloneFrom(a)
+		var srcCSC CSC
+		srcCSC.CloneFrom(a)
+		var csrFromCSR, csrFromCSCT CSR
+		csrFromCSR.CloneFrom(&srcCSR)
+		csrFromCSCT.CloneFrom(Transpose{&srcCSC})
+		var cscFromCSC, cscFromCSRT CSC
+		cscFromCSC.CloneFrom(&srcCSC)
+		cscFromCSRT.CloneFrom(Transpose{&srcCSR})
+		srcCSR.Zero()
+		srcCSC.Zero()
+		if !Equal(&csrFromCSR, a) || !Equal(&csrFromCSCT, a.T()) ||
+			!Equal(&cscFromCSC, a) || !Equal(&cscFromCSRT, a.T()) {
+			t.Errorf("%s: clone shares backing data with its source", name)
+		}
+
+		var d1, d2 Dense
+		csr.ToDense(&d1)
+		csc.ToDense(&d2)
+		if !Equal(&d1, a) || !Equal(&d2, a) {
+			t.Errorf("%s: unexpected dense conversion", name)
+		}
+
+		nnz := 0
+		csr.DoNonZero(func(i, j int, v float64) {
+			nnz++
+			if a.At(i, j) != v {
+				t.Errorf("%s: unexpected value at (%d,%d)", name, i, j)
+			}
+		})
+		if nnz != csr.NNZ() {
+			t.Errorf("%s: unexpected number of non-zero calls: got:%d want:%d", name, nnz, csr.NNZ())
+		}
+		for i := 0; i < test.r; i++ {
+			csr.DoRowNonZero(i, func(i2, j int, v float64) {
+				if i2 != i || a.At(i, j) != v {
+					t.Errorf("%s: unexpected row element at (%d,%d)", name, i2, j)
+				}
+			})
+		}
+		for j := 0; j < test.c; j++ {
+			csc.DoColNonZero(j, func(i, j2 int, v float64) {
+				if j2 != j || a.At(i, j) != v {
+					t.Errorf("%s: unexpected column element at (%d,%d)", name, i, j2)
+				}
+			})
+		}
+	}
+}
+
+func TestSparseArithmetic(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-14
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ m, k, n int }{{1, 1, 1}, {3, 4, 5}, {7, 2, 6}, {20, 20, 20}} {
+		a := randSparseDense(test.m, test.k, 0.3, rnd)
+		b := randSparseDense(test.k, test.n, 0.3, rnd)
+		c := randSparseDense(test.m, test.k, 0.3, rnd)
+		name := fmt.Sprintf("%d×%d×%d", test.m, test.k, test.n)
+
+		var aCSR, cCSR CSR
+		aCSR.CloneFrom(a)
+		cCSR.CloneFrom(c)
+		var bCSC CSC
+		bCSC.CloneFrom(b)
+
+		var wantMul Dense
+		wantMul.Mul(a, b)
+		var gotCSR CSR
+		gotCSR.Mul(&aCSR, &bCSC)
+		if !EqualApprox(&gotCSR, &wantMul, tol) {
+			t.Errorf("%s: unexpected CSR product:\ngot:\n%v\nwant:\n%v", name, Formatted(&gotCSR), Formatted(&wantMul))
+		}
+		var gotCSC CSC
+		gotCSC.Mul(&aCSR, b)
+		if !EqualApprox(&gotCSC, &wantMul, tol) {
+			t.Errorf("%s: unexpected CSC product:\ngot:\n%v\nwant:\n%v", name, Formatted(&gotCSC), Formatted(&wantMul))
+		}
+		var wantMulT Dense
+		wantMulT.Mul(b.T(), a.T())
+		var gotT CSR
+		gotT.Mul(bCSC.T(), aCSR.T())
+		if !EqualApprox(&gotT, &wantMulT, tol) {
+			t.Errorf("%s: unexpected transposed product", name)
+		}
+
+		var wantAdd Dense
+		wantAdd.Add(a, c)
+		var gotAdd CSR
+		gotAdd.Add(&aCSR, &cCSR)
+		if !EqualApprox(&gotAdd, &wantAdd, tol) {
+			t.Errorf("%s: unexpected CSR sum", name)
+		}
+		var gotAddCSC CSC
+		gotAddCSC.Add(&aCSR, c)
+		if !EqualApprox(&gotAddCSC, &wantAdd, tol) {
+			t.Errorf("%s: unexpected CSC sum", name)
+		}
+		var gotSub CSR
+		var negA CSR
+		negA.Scale(-1, &aCSR)
+		gotSub.Add(&aCSR, &negA)
+		if gotSub.NNZ() != 0 {
+			t.Errorf("%s: cancelled elements stored: got:%d", name, gotSub.NNZ())
+		}
+
+		var wantScale Dense
+		wantScale.Scale(2.5, a)
+		var gotScale CSC
+		gotScale.Scale(2.5, &aCSR)
+		if !EqualApprox(&gotScale, &wantScale, tol) {
+			t.Errorf("%s: unexpected scaled matrix", name)
+		}
+
+		x := NewVecDense(test.k, nil)
+		y := NewVecDense(test.m, nil)
+		for i := 0; i < test.k; i++ {
+			x.SetVec(i, rnd.NormFloat64())
+		}
+		for i := 0; i < test.m; i++ {
+			y.SetVec(i, rnd.NormFloat64())
+		}
+		var aCSC CSC
+		aCSC.CloneFrom(a)
+		for _, s := range []interface {
+			Matrix
+			MulVecTo(*VecDense, bool, Vector)
+		}{&aCSR, &aCSC} {
+			var want, got VecDense
+			want.MulVec(a, x)
+			s.MulVecTo(&got, false, x)
+			if !EqualApprox(&got, &want, tol) {
+				t.Errorf("%s: unexpected %T MulVecTo", name, s)
+			}
+			want.Reset()
+			got.Reset()
+			want.MulVec(a.T(), y)
+			s.MulVecTo(&got, true, y)
+			if !EqualApprox(&got, &want, tol) {
+				t.Errorf("%s: unexpected %T transposed MulVecTo", name, s)
+			}
+		}
+	}
+}
+
+func TestSparseReuse(t *testing.T) {
+	t.Parallel()
+	a := NewCSR(2, 3, nil, nil, nil)
+	b := NewCSR(3, 2, nil, nil, nil)
+	m := NewCSR(3, 3, nil, nil, nil)
+	panicked, message := panics(func() { m.Mul(a, b) })
+	if !panicked || message != ErrShape.Error() {
+		t.Errorf("expected shape panic for non-empty receiver, got %q", message)
+	}
+	m.Reset()
+	m.Mul(a, b)
+	if r, c := m.Dims(); r != 2 || c != 2 {
+		t.Errorf("unexpected dimensions: got:%d×%d want:2×2", r, c)
+	}
+	panicked, _ = panics(func() { m.Mul(a, a) })
+	if !panicked {
+		t.Error("expected panic for mismatched operands")
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+var (
+	_ Method      = (*AugmentedLagrangian)(nil)
+	_ localMethod = (*AugmentedLagrangian)(nil)
+)
+
+const (
+	defaultAugmentedPenalty             = 10
+	defaultAugmentedConstraintTolerance = 1e-8
+	defaultAugmentedGradientTolerance   = 1e-6
+)
+
+// AugmentedLagrangian implements the augmented Lagrangian method for
+// gradient-based minimization subject to the equality and inequality
+// constraints and the bounds of the Problem.
+//
+// AugmentedLagrangian solves a sequence of bound-constrained subproblems in
+// which the constraints are replaced by multiplier and penalty terms,
+//
+//	L(x; λ, μ, ρ) = f(x) + Σ_i (ρ/2 c_i(x)² - λ_i c_i(x))
+//	              + Σ_j (max(0, μ_j - ρ d_j(x))² - μ_j²) / (2ρ)
+//
+// where c_i are the equality constraints and d_j are the inequality
+// constraints. After each subproblem the multipliers are updated by
+// λ_i ← λ_i - ρ c_i(x) and μ_j ← max(0, μ_j - ρ d_j(x)), and the penalty
+// parameter ρ is increased if the constraint violation has not decreased
+// sufficiently. Each MajorIteration of AugmentedLagrangian is the solution
+// of a subproblem, and the Location reports the objective function and its
+// gradient.
+//
+// AugmentedLagrangian terminates with MethodConverge status when the
+// infinity norm of the constraint violation is less than ConstraintTolerance
+// and the infinity norm of the projected gradient of the Lagrangian is less
+// than GradientTolerance. Since the gradient of the objective function does
+// not vanish at a constrained minimum, the GradientThreshold setting should
+// not be used.
+//
+// The gradients of all constraints must be provided.
+//
+// References:
+//   - Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer
+//     (2006), chapter 17.
+//   - Birgin, E.G., Martínez, J.M. (2014). Practical augmented Lagrangian
+//     methods for constrained optimization. SIAM.
+type AugmentedLagrangian struct {
+	// Subproblem is the method used to minimize the augmented Lagrangian
+	// subject to the bounds. The GradStopThreshold of Subproblem is set by
+	// AugmentedLagrangian. If Subproblem is nil, a default LBFGSB is used.
+	Subproblem *LBFGSB
+	// Penalty is the initial value of the penalty parameter.
+	// If Penalty is 0, it is defaulted to 10.
+	Penalty float64
+	// ConstraintTolerance is the tolerance on the constraint violation
+	// at convergence. If ConstraintTolerance is 0, it is defaulted to 1e-8.
+	ConstraintTolerance float64
+	// GradientTolerance is the tolerance on the projected gradient of the
+	// Lagrangian at convergence. If GradientTolerance is 0, it is defaulted
+	// to 1e-6.
+	GradientTolerance float64
+
+	status Status
+	err    error
+
+	bounds []Bound
+	eq     []Constraint
+	ineq   []Constraint
+
+	lambda []float64 // Multipliers of the equality constraints
+	mu     []float64 // Multipliers of the inequality constraints
+	ceq    []float64 // Equality constraint values at the last evaluated location
+	cineq  []float64 // Inequality constraint values at the last evaluated location
+
+	rho       float64 // Penalty parameter
+	omega     float64 // Gradient tolerance of the current subproblem
+	violation float64 // Constraint violation at the last major iteration
+
+	inner  Location  // Location of the subproblem
+	xc     []float64 // Copy of x passed to the constraints
+	grad   []float64 // Constraint gradient
+	lastOp Operation // Operation returned from the previous call to iterateLocal
+
+	restore  bool // The subproblem failed and the last major location is being re-evaluated.
+	solved   bool // The subproblem converged to its gradient tolerance.
+	finished bool // The constraints and the subproblem have converged.
+}
+
+func (a *AugmentedLagrangian) Status() (Status, error) {
+	return a.status, a.err
+}
+
+func (*AugmentedLagrangian) Uses(has Available) (uses Available, err error) {
+	return has.constrainedGradient()
+}
+
+func (a *AugmentedLagrangian) setConstraints(p *Problem) {
+	a.bounds = p.Bounds
+	a.eq = p.Equality
+	a.ineq = p.Inequality
+	for _, c := range a.eq {
+		if c.Grad == nil {
+			panic("optimize: equality constraint gradient is nil")
+		}
+	}
+	for _, c := range a.ineq {
+		if c.Grad == nil {
+			panic("optimize: inequality constraint gradient is nil")
+		}
+	}
+}
+
+// Multipliers returns the estimates of the Lagrange multipliers of the
+// equality and inequality constraints at the end of the last run.
+func (a *AugmentedLagrangian) Multipliers() (eq, ineq []float64) {
+	return append([]float64(nil), a.lambda...), append([]float64(nil), a.mu...)
+}
+
+func (a *AugmentedLagrangian) Init(dim, tasks int) int {
+	a.status = NotTerminated
+	a.err = nil
+	return 1
+}
+
+func (a *AugmentedLagrangian) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	status, err := localOptimizer{}.run(a, math.NaN(), operation, result, tasks)
+	if a.status == NotTerminated {
+		a.status, a.err = status, err
+	}
+	close(operation)
+}
+
+func (a *AugmentedLagrangian) initLocal(loc *Location) (Operation, error) {
+	if a.Subproblem == nil {
+		a.Subproblem = &LBFGSB{}
+	}
+	if a.Penalty == 0 {
+		a.Penalty = defaultAugmentedPenalty
+	}
+	if a.ConstraintTolerance == 0 {
+		a.ConstraintTolerance = defaultAugmentedConstraintTolerance
+	}
+	if a.GradientTolerance == 0 {
+		a.GradientTolerance = defaultAugmentedGradientTolerance
+	}
+	if a.Penalty < 0 || a.ConstraintTolerance < 0 || a.GradientTolerance < 0 {
+		panic("augmented: negative Penalty or tolerance")
+	}
+
+	dim := len(loc.X)
+	a.lambda = resize(a.lambda, len(a.eq))
+	for i := range a.lambda {
+		a.lambda[i] = 0
+	}
+	a.mu = resize(a.mu, len(a.ineq))
+	for i := range a.mu {
+		a.mu[i] = 0
+	}
+	a.ceq = resize(a.ceq, len(a.eq))
+	a.cineq = resize(a.cineq, len(a.ineq))
+	a.xc = resize(a.xc, dim)
+	a.grad = resize(a.grad, dim)
+	a.inner.X = resize(a.inner.X, dim)
+	a.inner.Gradient = resize(a.inner.Gradient, dim)
+
+	a.rho = a.Penalty
+	a.omega = math.Max(a.GradientTolerance, 1/a.rho)
+	a.Subproblem.bounds = a.bounds
+	a.restore = false
+	a.finished = false
+
+	a.evaluateConstraints(loc.X)
+	a.violation = a.constraintViolation()
+	return a.startSubproblem(loc)
+}
+
+func (a *AugmentedLagrangian) iterateLocal(loc *Location) (Operation, error) {
+	switch {
+	case a.lastOp == MajorIteration:
+		if a.finished {
+			a.status = MethodConverge
+			a.lastOp = MethodDone
+			return a.lastOp, nil
+		}
+		return a.startSubproblem(loc)
+	case a.restore:
+		a.restore = false
+		a.evaluateConstraints(loc.X)
+		return a.update()
+	}
+
+	// An evaluation requested by the subproblem has been performed.
+	a.evaluateConstraints(loc.X)
+	if a.lastOp&FuncEvaluation != 0 {
+		a.inner.F = a.lagrangian(loc.F)
+	}
+	if a.lastOp&GradEvaluation != 0 {
+		a.lagrangianGrad(a.inner.Gradient, loc.X, loc.Gradient)
+	}
+	op, err := a.Subproblem.iterateLocal(&a.inner)
+	return a.forward(loc, op, err)
+}
+
+// startSubproblem starts the minimization of the augmented Lagrangian with
+// the current multipliers and penalty parameter from loc, which must have
+// valid objective function and gradient values, and constraint values
+// stored in the receiver.
+func (a *AugmentedLagrangian) startSubproblem(loc *Location) (Operation, error) {
+	copy(a.inner.X, loc.X)
+	a.inner.F = a.lagrangian(loc.F)
+	a.lagrangianGrad(a.inner.Gradient, loc.X, loc.Gradient)
+	a.Subproblem.GradStopThreshold = a.omega
+	a.Subproblem.Init(len(loc.X), 1)
+	op, err := a.Subproblem.initLocal(&a.inner)
+	return a.forward(loc, op, err)
+}
+
+// forward processes the operation returned by the subproblem.
+func (a *AugmentedLagrangian) forward(loc *Location, op Operation, err error) (Operation, error) {
+	for {
+		switch {
+		case err != nil:
+			// The subproblem cannot make further progress. Re-evaluate
+			// the location of its last major iteration and update the
+			// multipliers from there.
+			a.solved = false
+			a.restore = true
+			copy(loc.X, a.Subproblem.x)
+			a.lastOp = FuncEvaluation | GradEvaluation
+			return a.lastOp, nil
+		case op.isEvaluation():
+			copy(loc.X, a.inner.X)
+			a.lastOp = op
+			return a.lastOp, nil
+		case op == MajorIteration:
+			op, err = a.Subproblem.iterateLocal(&a.inner)
+		case op == MethodDone:
+			// The values in loc correspond to the last evaluated location,
+			// which is the last major iteration of the subproblem.
+			a.solved = true
+			return a.update()
+		default:
+			panic("augmented: unexpected operation from subproblem")
+		}
+	}
+}
+
+// update updates the multipliers and the penalty parameter after the
+// solution of a subproblem and declares a MajorIteration.
+func (a *AugmentedLagrangian) update() (Operation, error) {
+	violation := a.constraintViolation()
+	if a.solved && violation < a.ConstraintTolerance && a.omega <= a.GradientTolerance {
+		a.finished = true
+		a.lastOp = MajorIteration
+		return a.lastOp, nil
+	}
+	for i, c := range a.ceq {
+		a.lambda[i] -= a.rho * c
+	}
+	for j, c := range a.cineq {
+		a.mu[j] = math.Max(0, a.mu[j]-a.rho*c)
+	}
+	if violation >= a.ConstraintTolerance && violation > 0.25*a.violation {
+		a.rho *= 10
+	}
+	a.violation = violation
+	a.omega = math.Max(a.GradientTolerance, 0.1*a.omega)
+	a.lastOp = MajorIteration
+	return a.lastOp, nil
+}
+
+// evaluateConstraints stores the values of the constraints at x.
+func (a *AugmentedLagrangian) evaluateConstraints(x []float64) {
+	for i, c := range a.eq {
+		copy(a.xc, x)
+		a.ceq[i] = c.Func(a.xc)
+	}
+	for j, c := range a.ineq {
+		copy(a.xc, x)
+		a.cineq[j] = c.Func(a.xc)
+	}
+}
+
+// constraintViolation returns the infinity norm of the constraint violation
+// at the last evaluated location.
+func (a *AugmentedLagrangian) constraintViolation() float64 {
+	var v float64
+	for _, c := range a.ceq {
+		v = math.Max(v, math.Abs(c))
+	}
+	for _, c := range a.cineq {
+		v = math.Max(v, -c)
+	}
+	return v
+}
+
+// lagrangian returns the value of the augmented Lagrangian given the value
+// of the objective function and the stored constraint values.
+func (a *AugmentedLagrangian) lagrangian(f float64) float64 {
+	for i, c := range a.ceq {
+		f += (0.5*a.rho*c - a.lambda[i]) * c
+	}
+	for j, c := range a.cineq {
+		m := math.Max(0, a.mu[j]-a.rho*c)
+		f += (m*m - a.mu[j]*a.mu[j]) / (2 * a.rho)
+	}
+	return f
+}
+
+// lagrangianGrad stores in dst the gradient of the augmented Lagrangian at x
+// given the gradient of the objective function and the stored constraint
+// values.
+func (a *AugmentedLagrangian) lagrangianGrad(dst, x, grad []float64) {
+	copy(dst, grad)
+	for i, c := range a.eq {
+		if coef := a.rho*a.ceq[i] - a.lambda[i]; coef != 0 {
+			copy(a.xc, x)
+			c.Grad(a.grad, a.xc)
+			floats.AddScaled(dst, coef, a.grad)
+		}
+	}
+	for j, c := range a.ineq {
+		if m := math.Max(0, a.mu[j]-a.rho*a.cineq[j]); m != 0 {
+			copy(a.xc, x)
+			c.Grad(a.grad, a.xc)
+			floats.AddScaled(dst, -m, a.grad)
+		}
+	}
+}
+
+func (*AugmentedLagrangian) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, false}
+}
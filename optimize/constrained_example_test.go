@@ -0,0 +1,63 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize_test
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"gonum.org/v1/gonum/optimize"
+)
+
+func ExampleAugmentedLagrangian() {
+	// Minimize (x-2)² + (y-1)² subject to x² ≤ y and x + y ≤ 2,
+	// with y in [0, 1.5].
+	p := optimize.Problem{
+		Func: func(x []float64) float64 {
+			return (x[0]-2)*(x[0]-2) + (x[1]-1)*(x[1]-1)
+		},
+		Grad: func(grad, x []float64) {
+			grad[0] = 2 * (x[0] - 2)
+			grad[1] = 2 * (x[1] - 1)
+		},
+		Bounds: []optimize.Bound{{Min: math.Inf(-1), Max: math.Inf(1)}, {Min: 0, Max: 1.5}},
+		Inequality: []optimize.Constraint{
+			{
+				Func: func(x []float64) float64 { return x[1] - x[0]*x[0] },
+				Grad: func(grad, x []float64) {
+					grad[0] = -2 * x[0]
+					grad[1] = 1
+				},
+			},
+			{
+				Func: func(x []float64) float64 { return 2 - x[0] - x[1] },
+				Grad: func(grad, x []float64) {
+					grad[0] = -1
+					grad[1] = -1
+				},
+			},
+		},
+	}
+
+	method := &optimize.AugmentedLagrangian{}
+	result, err := optimize.Minimize(p, []float64{0, 0}, nil, method)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = result.Status.Err(); err != nil {
+		log.Fatal(err)
+	}
+	_, ineq := method.Multipliers()
+	fmt.Printf("result.Status: %v\n", result.Status)
+	fmt.Printf("result.X: %.6f\n", result.X)
+	fmt.Printf("result.F: %.6f\n", result.F)
+	fmt.Printf("multipliers: %.6f\n", ineq)
+	// Output:
+	// result.Status: MethodConverge
+	// result.X: [1.000000 1.000000]
+	// result.F: 1.000000
+	// multipliers: [0.666667 0.666667]
+}
@@ -0,0 +1,421 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestLBFGSBUnconstrained(t *testing.T) {
+	t.Parallel()
+	testLocal(t, gradientDescentTests, &LBFGSB{})
+}
+
+// projectedGradNorm returns the infinity norm of the projected gradient of
+// f at x for the given bounds.
+func projectedGradNorm(p Problem, x []float64) float64 {
+	g := make([]float64, len(x))
+	p.Grad(g, x)
+	var norm float64
+	for i, b := range p.Bounds {
+		v := math.Max(b.Min, math.Min(x[i]-g[i], b.Max))
+		norm = math.Max(norm, math.Abs(v-x[i]))
+	}
+	return norm
+}
+
+func TestLBFGSB(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	inf := math.Inf(1)
+
+	type boundedTest struct {
+		name   string
+		p      Problem
+		x      []float64
+		want   []float64 // If nil, only optimality is checked.
+		method Method
+	}
+	var tests []boundedTest
+
+	// A separable quadratic with its minimum outside the bounds.
+	center := []float64{-3, 0.5, 4, 10}
+	sep := Problem{
+		Func: func(x []float64) float64 {
+			var f float64
+			for i, v := range x {
+				f += float64(i+1) * (v - center[i]) * (v - center[i])
+			}
+			return f
+		},
+		Grad: func(grad, x []float64) {
+			for i, v := range x {
+				grad[i] = 2 * float64(i+1) * (v - center[i])
+			}
+		},
+		Bounds: []Bound{{-1, 1}, {-1, 1}, {-inf, 2}, {0, inf}},
+	}
+	tests = append(tests, boundedTest{
+		name: "Separable",
+		p:    sep,
+		x:    []float64{0, 0, 0, 0},
+		want: []float64{-1, 0.5, 2, 10},
+	})
+
+	// Rosenbrock with bounds excluding and including the minimum.
+	rosen := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	rosen.Bounds = []Bound{{-2, 0.5}, {-2, 2}, {-2, 2}, {-2, 2}}
+	tests = append(tests, boundedTest{
+		name: "RosenbrockActive",
+		p:    rosen,
+		x:    []float64{-1.2, 1, -1.2, 1},
+	})
+	rosenFree := rosen
+	rosenFree.Bounds = []Bound{{-2, 2}, {-2, 2}, {-2, 2}, {-2, 2}}
+	tests = append(tests, boundedTest{
+		name: "RosenbrockInactive",
+		p:    rosenFree,
+		x:    []float64{-1.2, 1, -1.2, 1},
+		want: []float64{1, 1, 1, 1},
+	})
+	// The initial location is projected onto the bounds.
+	tests = append(tests, boundedTest{
+		name: "RosenbrockOutside",
+		p:    rosen,
+		x:    []float64{3, -3, 3, -3},
+	})
+
+	// Random convex quadratics with random bounds.
+	for i := 0; i < 10; i++ {
+		n := 2 + rnd.Intn(8)
+		a := mat.NewDense(n, n, nil)
+		for j := 0; j < n; j++ {
+			for k := 0; k < n; k++ {
+				a.Set(j, k, rnd.NormFloat64())
+			}
+		}
+		var q mat.SymDense
+		q.SymOuterK(1, a)
+		for j := 0; j < n; j++ {
+			q.SetSym(j, j, q.At(j, j)+0.1)
+		}
+		c := make([]float64, n)
+		bounds := make([]Bound, n)
+		x := make([]float64, n)
+		for j := range c {
+			c[j] = 10 * rnd.NormFloat64()
+			bounds[j] = Bound{Min: -1 - rnd.Float64(), Max: 1 + rnd.Float64()}
+			if rnd.Intn(4) == 0 {
+				bounds[j].Min = -inf
+			}
+		}
+		tests = append(tests, boundedTest{
+			name: "Quadratic",
+			p: Problem{
+				Func: func(x []float64) float64 {
+					xv := mat.NewVecDense(n, x)
+					return 0.5*mat.Inner(xv, &q, xv) + floats.Dot(c, x)
+				},
+				Grad: func(grad, x []float64) {
+					g := mat.NewVecDense(n, grad)
+					g.MulVec(&q, mat.NewVecDense(n, x))
+					floats.Add(grad, c)
+				},
+				Bounds: bounds,
+			},
+			x: x,
+		})
+	}
+
+	// AugmentedLagrangian handles bound-constrained problems directly.
+	tests = append(tests, boundedTest{
+		name:   "SeparableAugmented",
+		p:      sep,
+		x:      []float64{0, 0, 0, 0},
+		want:   []float64{-1, 0.5, 2, 10},
+		method: &AugmentedLagrangian{},
+	})
+
+	for _, test := range tests {
+		method := test.method
+		if method == nil {
+			method = &LBFGSB{GradStopThreshold: 1e-8}
+		}
+		x0 := append([]float64(nil), test.x...)
+		result, err := Minimize(test.p, test.x, nil, method)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !floats.Equal(test.x, x0) {
+			t.Errorf("%s: initial location modified", test.name)
+		}
+		for i, b := range test.p.Bounds {
+			if result.X[i] < b.Min || b.Max < result.X[i] {
+				t.Errorf("%s: solution outside bounds: x[%d]=%v not in [%v,%v]", test.name, i, result.X[i], b.Min, b.Max)
+			}
+		}
+		if test.method == nil && result.Status != GradientThreshold {
+			t.Errorf("%s: unexpected status: got:%v want:%v", test.name, result.Status, GradientThreshold)
+		}
+		if norm := projectedGradNorm(test.p, result.X); norm > 1e-6 {
+			t.Errorf("%s: projected gradient norm too large: %v", test.name, norm)
+		}
+		if f := test.p.Func(result.X); f != result.F {
+			t.Errorf("%s: function value mismatch: got:%v want:%v", test.name, result.F, f)
+		}
+		if test.want != nil && !floats.EqualApprox(result.X, test.want, 1e-6) {
+			t.Errorf("%s: unexpected solution: got:%v want:%v", test.name, result.X, test.want)
+		}
+	}
+}
+
+func TestLBFGSBDefaultThreshold(t *testing.T) {
+	t.Parallel()
+	// The default gradient threshold may not be reachable, in which
+	// case the linesearch stalls close to a stationary location. The
+	// zero value LBFGSB, which is also the default method for bounded
+	// problems, must report convergence there.
+	for _, dim := range []int{4, 10} {
+		for _, active := range []bool{false, true} {
+			p := Problem{
+				Func:   functions.ExtendedRosenbrock{}.Func,
+				Grad:   functions.ExtendedRosenbrock{}.Grad,
+				Bounds: make([]Bound, dim),
+			}
+			x := make([]float64, dim)
+			for i := range x {
+				p.Bounds[i] = Bound{Min: -2, Max: 2}
+				x[i] = 1
+				if i%2 == 0 {
+					x[i] = -1.2
+				}
+			}
+			if active {
+				p.Bounds[0].Max = 0.5
+			}
+			for _, method := range []Method{nil, &LBFGSB{}} {
+				result, err := Minimize(p, x, nil, method)
+				if err != nil {
+					t.Errorf("dim=%d active=%t method=%T: unexpected error: %v", dim, active, method, err)
+					continue
+				}
+				if result.Status != GradientThreshold && result.Status != MethodConverge {
+					t.Errorf("dim=%d active=%t method=%T: unexpected status: got:%v want:%v or %v",
+						dim, active, method, result.Status, GradientThreshold, MethodConverge)
+				}
+				if norm := projectedGradNorm(p, result.X); norm > 1e-6 {
+					t.Errorf("dim=%d active=%t method=%T: projected gradient norm too large: %v",
+						dim, active, method, norm)
+				}
+			}
+		}
+	}
+}
+
+func TestLBFGSBStallError(t *testing.T) {
+	t.Parallel()
+	// The gradient points away from the minimum at x = (1, 1), so the
+	// linesearch fails far from a stationary location. The failure
+	// must be reported regardless of the magnitude of the function.
+	for _, f0 := range []float64{0, 1e10} {
+		p := Problem{
+			Func: func(x []float64) float64 {
+				return f0 + math.Abs(x[0]-1) + math.Abs(x[1]-1)
+			},
+			Grad: func(grad, x []float64) {
+				for i := range grad {
+					grad[i] = 10
+				}
+			},
+			Bounds: []Bound{{Min: -100, Max: 100}, {Min: -100, Max: 100}},
+		}
+		result, err := Minimize(p, []float64{1, 1}, nil, &LBFGSB{})
+		if err == nil {
+			t.Errorf("f0=%v: expected error for linesearch failure, got status %v", f0, result.Status)
+		}
+	}
+}
+
+func TestAugmentedLagrangian(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name   string
+		p      Problem
+		x      []float64
+		want   []float64
+		wantF  float64
+		method *AugmentedLagrangian
+	}{
+		{
+			// Minimize x+y on the circle of radius √2.
+			name: "Circle",
+			p: Problem{
+				Func: func(x []float64) float64 { return x[0] + x[1] },
+				Grad: func(grad, x []float64) {
+					grad[0] = 1
+					grad[1] = 1
+				},
+				Equality: []Constraint{{
+					Func: func(x []float64) float64 { return x[0]*x[0] + x[1]*x[1] - 2 },
+					Grad: func(grad, x []float64) {
+						grad[0] = 2 * x[0]
+						grad[1] = 2 * x[1]
+					},
+				}},
+			},
+			x:     []float64{1, 0},
+			want:  []float64{-1, -1},
+			wantF: -2,
+		},
+		{
+			// Project (2, 1) onto the half-plane x+y ≤ 2.
+			name: "HalfPlane",
+			p: Problem{
+				Func: func(x []float64) float64 {
+					return (x[0]-2)*(x[0]-2) + (x[1]-1)*(x[1]-1)
+				},
+				Grad: func(grad, x []float64) {
+					grad[0] = 2 * (x[0] - 2)
+					grad[1] = 2 * (x[1] - 1)
+				},
+				Inequality: []Constraint{{
+					Func: func(x []float64) float64 { return 2 - x[0] - x[1] },
+					Grad: func(grad, x []float64) {
+						grad[0] = -1
+						grad[1] = -1
+					},
+				}},
+			},
+			x:     []float64{0, 0},
+			want:  []float64{1.5, 0.5},
+			wantF: 0.5,
+		},
+		{
+			// Inactive inequality constraint.
+			name: "Inactive",
+			p: Problem{
+				Func: func(x []float64) float64 {
+					return (x[0]-0.5)*(x[0]-0.5) + (x[1]+0.5)*(x[1]+0.5)
+				},
+				Grad: func(grad, x []float64) {
+					grad[0] = 2 * (x[0] - 0.5)
+					grad[1] = 2 * (x[1] + 0.5)
+				},
+				Inequality: []Constraint{{
+					Func: func(x []float64) float64 { return 2 - x[0] - x[1] },
+					Grad: func(grad, x []float64) {
+						grad[0] = -1
+						grad[1] = -1
+					},
+				}},
+			},
+			x:     []float64{3, 3},
+			want:  []float64{0.5, -0.5},
+			wantF: 0,
+		},
+		{
+			// Problem 71 of Hock and Schittkowski with bounds, an
+			// equality and an inequality constraint.
+			name: "HS071",
+			p: Problem{
+				Func: func(x []float64) float64 {
+					return x[0]*x[3]*(x[0]+x[1]+x[2]) + x[2]
+				},
+				Grad: func(grad, x []float64) {
+					grad[0] = x[3]*(x[0]+x[1]+x[2]) + x[0]*x[3]
+					grad[1] = x[0] * x[3]
+					grad[2] = x[0]*x[3] + 1
+					grad[3] = x[0] * (x[0] + x[1] + x[2])
+				},
+				Bounds: []Bound{{1, 5}, {1, 5}, {1, 5}, {1, 5}},
+				Equality: []Constraint{{
+					Func: func(x []float64) float64 { return floats.Dot(x, x) - 40 },
+					Grad: func(grad, x []float64) {
+						copy(grad, x)
+						floats.Scale(2, grad)
+					},
+				}},
+				Inequality: []Constraint{{
+					Func: func(x []float64) float64 { return x[0]*x[1]*x[2]*x[3] - 25 },
+					Grad: func(grad, x []float64) {
+						grad[0] = x[1] * x[2] * x[3]
+						grad[1] = x[0] * x[2] * x[3]
+						grad[2] = x[0] * x[1] * x[3]
+						grad[3] = x[0] * x[1] * x[2]
+					},
+				}},
+			},
+			x:     []float64{1, 5, 5, 1},
+			want:  []float64{1, 4.74299963, 3.82114998, 1.37940829},
+			wantF: 17.0140173,
+		},
+	} {
+		for _, method := range []*AugmentedLagrangian{nil, {}} {
+			var m Method
+			if method != nil {
+				m = method
+			}
+			result, err := Minimize(test.p, test.x, nil, m)
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+				continue
+			}
+			if result.Status != MethodConverge {
+				t.Errorf("%s: unexpected status: got:%v want:%v", test.name, result.Status, MethodConverge)
+			}
+			if !floats.EqualApprox(result.X, test.want, 1e-6) {
+				t.Errorf("%s: unexpected solution: got:%v want:%v", test.name, result.X, test.want)
+			}
+			if !scalar.EqualWithinAbs(result.F, test.wantF, 1e-6) {
+				t.Errorf("%s: unexpected function value: got:%v want:%v", test.name, result.F, test.wantF)
+			}
+			for _, c := range test.p.Equality {
+				if v := c.Func(result.X); math.Abs(v) > 1e-8 {
+					t.Errorf("%s: equality constraint violated: %v", test.name, v)
+				}
+			}
+			for _, c := range test.p.Inequality {
+				if v := c.Func(result.X); v < -1e-8 {
+					t.Errorf("%s: inequality constraint violated: %v", test.name, v)
+				}
+			}
+		}
+	}
+}
+
+func TestUsesConstraints(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		method Method
+		has    Available
+		want   error
+	}{
+		{method: &LBFGS{}, has: Available{Grad: true, Bounds: true}, want: ErrUnsupportedConstraints},
+		{method: &NelderMead{}, has: Available{Constraints: true}, want: ErrUnsupportedConstraints},
+		{method: &Newton{}, has: Available{Grad: true, Hess: true, Bounds: true}, want: ErrUnsupportedConstraints},
+		{method: &LBFGSB{}, has: Available{Grad: true, Bounds: true}},
+		{method: &LBFGSB{}, has: Available{Grad: true, Constraints: true}, want: ErrUnsupportedConstraints},
+		{method: &LBFGSB{}, has: Available{Bounds: true}, want: ErrMissingGrad},
+		{method: &AugmentedLagrangian{}, has: Available{Grad: true, Bounds: true, Constraints: true}},
+		{method: &AugmentedLagrangian{}, has: Available{Constraints: true}, want: ErrMissingGrad},
+	} {
+		_, err := test.method.Uses(test.has)
+		if err != test.want {
+			t.Errorf("unexpected error for %T with %+v: got:%v want:%v", test.method, test.has, err, test.want)
+		}
+	}
+}
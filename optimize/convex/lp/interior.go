@@ -0,0 +1,524 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrNoConvergence is returned by InteriorPoint when the iterates fail to
+// converge to the required tolerance.
+var ErrNoConvergence = errors.New("lp: interior point method failed to converge")
+
+const (
+	// ipDefaultTol is the default relative convergence tolerance of
+	// InteriorPoint.
+	ipDefaultTol = 1e-8
+	// ipMaxIter is the maximum number of iterations of InteriorPoint.
+	ipMaxIter = 200
+	// ipStepFactor is the fraction of the step to the boundary of the
+	// positive orthant taken at each iteration.
+	ipStepFactor = 0.99
+)
+
+// InteriorPoint solves a linear program in standard form using a primal-dual
+// interior-point method with Mehrotra's predictor-corrector. The standard
+// form of a linear program is:
+//
+//	minimize	cᵀ x
+//	s.t. 		A*x = b
+//				x >= 0 .
+//
+// The method solves the homogeneous self-dual embedding of the problem, so
+// no initial feasible point needs to be supplied and infeasibility and
+// unboundedness are detected from the iterates. The input tol sets the
+// relative tolerance on the primal and dual residuals and the duality gap
+// at which the solution is considered optimal. If tol is zero, a default of
+// 1e-8 is used.
+//
+// A may be any mat.Matrix. The non-zero elements of A are copied into
+// compressed sparse column form once, in time linear in the number of
+// non-zero elements when A is a *mat.CSC, *mat.CSR or *mat.COO, and the
+// products with A in each iteration use only the non-zero elements. Each
+// iteration solves the normal equations A*D*Aᵀ with a dense Cholesky
+// factorization, so the memory and time used grow with the square and the
+// cube of the number of rows of A respectively. Linearly dependent rows of
+// A are handled by regularizing the factorization. Rows of A with all zeros
+// are removed if the corresponding element of b is zero.
+//
+// ErrInfeasible is returned if the problem is infeasible and ErrUnbounded
+// is returned if the problem is unbounded. If both the problem and its dual
+// are infeasible, either error may be returned. ErrNoConvergence is returned
+// if the method fails to converge within the iteration limit.
+//
+// The Convert function can be used to transform a general LP into standard form.
+//
+// len(c) must equal the number of columns of A, and len(b) must equal the
+// number of rows of A or InteriorPoint will panic.
+//
+// References:
+//   - Mehrotra, S. (1992). On the implementation of a primal-dual interior
+//     point method. SIAM Journal on Optimization, 2(4), 575-601.
+//   - Wright, S.J. (1997). Primal-Dual Interior-Point Methods. SIAM, chapter 10.
+//   - Andersen, E.D., Andersen, K.D. (2000). The MOSEK interior point
+//     optimizer for linear programming: an implementation of the
+//     homogeneous algorithm. High Performance Optimization, 197-232.
+func InteriorPoint(c []float64, A mat.Matrix, b []float64, tol float64) (optF float64, optX []float64, err error) {
+	m, n := A.Dims()
+	if len(c) != n {
+		panic("lp: c vector incorrect length")
+	}
+	if len(b) != m {
+		panic("lp: b vector incorrect length")
+	}
+	if tol == 0 {
+		tol = ipDefaultTol
+	}
+
+	// The non-zero elements of A are found from its compressed
+	// sparse column form, which is linear in the number of non-zero
+	// elements for the sparse matrix types of mat.
+	var csc mat.CSC
+	csc.CloneFrom(A)
+
+	// Remove rows of all zeros.
+	nonZero := make([]bool, m)
+	csc.DoNonZero(func(i, _ int, _ float64) { nonZero[i] = true })
+	var rows []int
+	for i, ok := range nonZero {
+		if ok {
+			rows = append(rows, i)
+			continue
+		}
+		if b[i] != 0 {
+			return math.NaN(), nil, ErrInfeasible
+		}
+	}
+	if len(rows) == 0 {
+		// There are no constraints other than non-negativity.
+		x := make([]float64, n)
+		for _, v := range c {
+			if v < 0 {
+				return math.Inf(-1), nil, ErrUnbounded
+			}
+		}
+		return 0, x, nil
+	}
+	br := make([]float64, len(rows))
+	for i, r := range rows {
+		br[i] = b[r]
+	}
+
+	ip := newInteriorPoint(c, newColumns(&csc, rows), br)
+	x, err := ip.solve(tol)
+	switch err {
+	case nil:
+		return floats.Dot(c, x), x, nil
+	case ErrUnbounded:
+		return math.Inf(-1), nil, err
+	default:
+		return math.NaN(), nil, err
+	}
+}
+
+// columns is a column-compressed representation of a matrix.
+type columns struct {
+	m   int
+	idx [][]int
+	val [][]float64
+}
+
+// newColumns returns the non-zero elements of the given rows of a.
+func newColumns(a *mat.CSC, rows []int) columns {
+	m, n := a.Dims()
+	// pos maps the rows of a to the rows of the result.
+	pos := make([]int, m)
+	for i := range pos {
+		pos[i] = -1
+	}
+	for k, r := range rows {
+		pos[r] = k
+	}
+	cols := columns{
+		m:   len(rows),
+		idx: make([][]int, n),
+		val: make([][]float64, n),
+	}
+	for j := 0; j < n; j++ {
+		a.DoColNonZero(j, func(i, j int, v float64) {
+			if pos[i] < 0 {
+				return
+			}
+			cols.idx[j] = append(cols.idx[j], pos[i])
+			cols.val[j] = append(cols.val[j], v)
+		})
+	}
+	return cols
+}
+
+// mulVec computes dst = A*x.
+func (a columns) mulVec(dst, x []float64) {
+	for i := range dst {
+		dst[i] = 0
+	}
+	for j, idx := range a.idx {
+		if x[j] == 0 {
+			continue
+		}
+		for k, i := range idx {
+			dst[i] += a.val[j][k] * x[j]
+		}
+	}
+}
+
+// mulTransVec computes dst = Aᵀ*y.
+func (a columns) mulTransVec(dst, y []float64) {
+	for j, idx := range a.idx {
+		var v float64
+		for k, i := range idx {
+			v += a.val[j][k] * y[i]
+		}
+		dst[j] = v
+	}
+}
+
+// normal computes dst = A*diag(d)*Aᵀ.
+func (a columns) normal(dst *mat.SymDense, d []float64) {
+	raw := dst.RawSymmetric()
+	for i := 0; i < a.m; i++ {
+		row := raw.Data[i*raw.Stride+i : i*raw.Stride+a.m]
+		for k := range row {
+			row[k] = 0
+		}
+	}
+	for j, idx := range a.idx {
+		val := a.val[j]
+		for k, p := range idx {
+			v := d[j] * val[k]
+			row := raw.Data[p*raw.Stride:]
+			for l := k; l < len(idx); l++ {
+				row[idx[l]] += v * val[l]
+			}
+		}
+	}
+}
+
+// interiorPoint holds the state of the homogeneous self-dual interior-point
+// method.
+type interiorPoint struct {
+	a    columns
+	b, c []float64
+	m, n int
+
+	// The iterate of the homogeneous self-dual embedding.
+	x, y, s    []float64
+	tau, kappa float64
+
+	// The search direction.
+	dx, dy, ds   []float64
+	dtau, dkappa float64
+
+	// The residuals of the embedding and of the complementarity conditions.
+	rp, rd []float64
+	rg     float64
+	rxs    []float64
+	rtk    float64
+
+	// The scaling D = X S⁻¹ and the normal equations matrix A*D*Aᵀ.
+	d      []float64
+	normal *mat.SymDense
+	chol   mat.Cholesky
+
+	// Intermediate quantities of the search direction.
+	p, q, u, v []float64
+	bq, cv     float64
+
+	tmpN, tmpM []float64
+}
+
+func newInteriorPoint(c []float64, a columns, b []float64) *interiorPoint {
+	m, n := a.m, len(c)
+	return &interiorPoint{
+		a: a,
+		b: b,
+		c: c,
+		m: m,
+		n: n,
+
+		x:  make([]float64, n),
+		y:  make([]float64, m),
+		s:  make([]float64, n),
+		dx: make([]float64, n),
+		dy: make([]float64, m),
+		ds: make([]float64, n),
+
+		rp:  make([]float64, m),
+		rd:  make([]float64, n),
+		rxs: make([]float64, n),
+		d:   make([]float64, n),
+		p:   make([]float64, m),
+		q:   make([]float64, m),
+		u:   make([]float64, n),
+		v:   make([]float64, n),
+
+		normal: mat.NewSymDense(m, nil),
+
+		tmpN: make([]float64, n),
+		tmpM: make([]float64, m),
+	}
+}
+
+// factorize computes the Cholesky factorization of A*diag(d)*Aᵀ. If the
+// matrix is not positive definite, the factorization is regularized by
+// adding increasing multiples of the identity.
+func (ip *interiorPoint) factorize(d []float64) bool {
+	ip.a.normal(ip.normal, d)
+	if ip.chol.Factorize(ip.normal) {
+		return true
+	}
+	var maxDiag float64
+	for i := 0; i < ip.m; i++ {
+		maxDiag = math.Max(maxDiag, ip.normal.At(i, i))
+	}
+	if maxDiag == 0 {
+		maxDiag = 1
+	}
+	var prev float64
+	for reg := 1e-14 * maxDiag; reg < maxDiag; reg *= 100 {
+		for i := 0; i < ip.m; i++ {
+			ip.normal.SetSym(i, i, ip.normal.At(i, i)+reg-prev)
+		}
+		prev = reg
+		if ip.chol.Factorize(ip.normal) {
+			return true
+		}
+	}
+	return false
+}
+
+// solveNormal solves A*D*Aᵀ*dst = rhs using the current factorization.
+func (ip *interiorPoint) solveNormal(dst, rhs []float64) bool {
+	err := ip.chol.SolveVecTo(mat.NewVecDense(len(dst), dst), mat.NewVecDense(len(rhs), rhs))
+	if err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			return false
+		}
+	}
+	for _, v := range dst {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// solve runs the interior-point iterations and returns the primal solution.
+//
+// The method solves the homogeneous self-dual embedding of the linear
+// program
+//
+//	A x - b τ = 0
+//	Aᵀ y + s - c τ = 0
+//	bᵀ y - cᵀ x - κ = 0
+//	x, s, τ, κ ≥ 0
+//
+// whose solutions with τ > 0 give an optimal solution x/τ of the linear
+// program and whose solutions with κ > 0 give a certificate of primal or
+// dual infeasibility.
+func (ip *interiorPoint) solve(tol float64) ([]float64, error) {
+	x, y, s := ip.x, ip.y, ip.s
+	for i := range x {
+		x[i] = 1
+		s[i] = 1
+	}
+	ip.tau = 1
+	ip.kappa = 1
+	n1 := float64(ip.n + 1)
+
+	ip.residuals()
+	rp0 := math.Max(1, floats.Norm(ip.rp, 2))
+	rd0 := math.Max(1, floats.Norm(ip.rd, 2))
+	rg0 := math.Max(1, math.Abs(ip.rg))
+	mu0 := (floats.Dot(x, s) + ip.tau*ip.kappa) / n1
+
+	for iter := 0; iter < ipMaxIter; iter++ {
+		mu := (floats.Dot(x, s) + ip.tau*ip.kappa) / n1
+		primalObj := floats.Dot(ip.c, x)
+		dualObj := floats.Dot(ip.b, y)
+
+		// Check convergence.
+		rhoP := floats.Norm(ip.rp, 2) / rp0
+		rhoD := floats.Norm(ip.rd, 2) / rd0
+		rhoG := math.Abs(ip.rg) / rg0
+		rhoA := math.Abs(primalObj-dualObj) / (ip.tau + math.Abs(dualObj))
+		if rhoP <= tol && rhoD <= tol && rhoA <= tol {
+			floats.Scale(1/ip.tau, x)
+			return x, nil
+		}
+		if (rhoP <= tol && rhoD <= tol && rhoG <= tol && ip.tau <= tol*math.Max(1, ip.kappa)) ||
+			(mu/mu0 <= tol && ip.tau <= tol*math.Min(1, ip.kappa)) {
+			// τ vanishes, so the iterates approach a certificate of
+			// infeasibility of the primal problem, bᵀy > 0 with Aᵀy ≤ 0,
+			// or of the dual problem, cᵀx < 0 with A x = 0 and x ≥ 0.
+			// If the primal is feasible bᵀy ≤ 0 in the limit, and if the
+			// dual is feasible cᵀx ≥ 0.
+			if dualObj > -primalObj {
+				return nil, ErrInfeasible
+			}
+			return nil, ErrUnbounded
+		}
+
+		for i := range ip.d {
+			ip.d[i] = x[i] / s[i]
+		}
+		if !ip.factorize(ip.d) {
+			return nil, ErrLinSolve
+		}
+		if !ip.prepare() {
+			return nil, ErrLinSolve
+		}
+
+		// Predictor (affine scaling) step.
+		for i := range ip.rxs {
+			ip.rxs[i] = -x[i] * s[i]
+		}
+		ip.rtk = -ip.tau * ip.kappa
+		if !ip.direction(1) {
+			return nil, ErrLinSolve
+		}
+		alpha := math.Min(1, ip.maxStep())
+		muAff := ip.tau*ip.kappa + alpha*(ip.tau*ip.dkappa+ip.kappa*ip.dtau) + alpha*alpha*ip.dtau*ip.dkappa
+		for i := range x {
+			muAff += (x[i] + alpha*ip.dx[i]) * (s[i] + alpha*ip.ds[i])
+		}
+		muAff /= n1
+		gamma := math.Pow(muAff/mu, 3)
+
+		// Corrector step with centering.
+		for i := range ip.rxs {
+			ip.rxs[i] = gamma*mu - x[i]*s[i] - ip.dx[i]*ip.ds[i]
+		}
+		ip.rtk = gamma*mu - ip.tau*ip.kappa - ip.dtau*ip.dkappa
+		if !ip.direction(1 - gamma) {
+			return nil, ErrLinSolve
+		}
+		alpha = math.Min(1, ipStepFactor*ip.maxStep())
+		floats.AddScaled(x, alpha, ip.dx)
+		floats.AddScaled(y, alpha, ip.dy)
+		floats.AddScaled(s, alpha, ip.ds)
+		ip.tau += alpha * ip.dtau
+		ip.kappa += alpha * ip.dkappa
+		ip.residuals()
+	}
+	return nil, ErrNoConvergence
+}
+
+// residuals computes the residuals of the homogeneous self-dual embedding,
+//
+//	rp = b τ - A x
+//	rd = c τ - Aᵀ y - s
+//	rg = cᵀ x - bᵀ y + κ
+func (ip *interiorPoint) residuals() {
+	ip.a.mulVec(ip.rp, ip.x)
+	for i, v := range ip.rp {
+		ip.rp[i] = ip.b[i]*ip.tau - v
+	}
+	ip.a.mulTransVec(ip.rd, ip.y)
+	for i, v := range ip.rd {
+		ip.rd[i] = ip.c[i]*ip.tau - v - ip.s[i]
+	}
+	ip.rg = floats.Dot(ip.c, ip.x) - floats.Dot(ip.b, ip.y) + ip.kappa
+}
+
+// prepare computes the parts of the search direction that depend only on
+// the current scaling, q = (A*D*Aᵀ)⁻¹ (A*D*c + b) and v = D (Aᵀ q - c).
+func (ip *interiorPoint) prepare() bool {
+	for i, c := range ip.c {
+		ip.tmpN[i] = ip.d[i] * c
+	}
+	ip.a.mulVec(ip.tmpM, ip.tmpN)
+	floats.Add(ip.tmpM, ip.b)
+	if !ip.solveNormal(ip.q, ip.tmpM) {
+		return false
+	}
+	ip.a.mulTransVec(ip.v, ip.q)
+	for i, c := range ip.c {
+		ip.v[i] = ip.d[i] * (ip.v[i] - c)
+	}
+	ip.bq = floats.Dot(ip.b, ip.q)
+	ip.cv = floats.Dot(ip.c, ip.v)
+	return true
+}
+
+// direction computes the search direction from the linearized system
+//
+//	A dx - b dτ = η rp
+//	Aᵀ dy + ds - c dτ = η rd
+//	bᵀ dy - cᵀ dx - dκ = η rg
+//	S dx + X ds = rxs
+//	κ dτ + τ dκ = rtk
+//
+// by eliminating ds and dκ and solving the normal equations for dy, which
+// is written as dy = p + q dτ.
+func (ip *interiorPoint) direction(eta float64) bool {
+	x, s := ip.x, ip.s
+	// p = (A*D*Aᵀ)⁻¹ (η rp + A D (η rd - X⁻¹ rxs)).
+	w := ip.tmpN
+	for i := range w {
+		w[i] = ip.d[i] * (eta*ip.rd[i] - ip.rxs[i]/x[i])
+	}
+	ip.a.mulVec(ip.tmpM, w)
+	floats.AddScaled(ip.tmpM, eta, ip.rp)
+	if !ip.solveNormal(ip.p, ip.tmpM) {
+		return false
+	}
+	// u = D (Aᵀ p - η rd + X⁻¹ rxs).
+	ip.a.mulTransVec(ip.u, ip.p)
+	for i := range ip.u {
+		ip.u[i] = ip.d[i] * (ip.u[i] - eta*ip.rd[i] + ip.rxs[i]/x[i])
+	}
+
+	denom := ip.bq - ip.cv + ip.kappa/ip.tau
+	ip.dtau = (eta*ip.rg + floats.Dot(ip.c, ip.u) - floats.Dot(ip.b, ip.p) + ip.rtk/ip.tau) / denom
+	ip.dkappa = (ip.rtk - ip.kappa*ip.dtau) / ip.tau
+	for i := range ip.dy {
+		ip.dy[i] = ip.p[i] + ip.q[i]*ip.dtau
+	}
+	for i := range ip.dx {
+		ip.dx[i] = ip.u[i] + ip.v[i]*ip.dtau
+		ip.ds[i] = (ip.rxs[i] - s[i]*ip.dx[i]) / x[i]
+	}
+	return !math.IsNaN(ip.dtau) && !math.IsInf(ip.dtau, 0)
+}
+
+// maxStep returns the largest step α such that the iterate remains in the
+// non-negative orthant.
+func (ip *interiorPoint) maxStep() float64 {
+	alpha := math.Inf(1)
+	alpha = math.Min(alpha, maxStep(ip.x, ip.dx))
+	alpha = math.Min(alpha, maxStep(ip.s, ip.ds))
+	if ip.dtau < 0 {
+		alpha = math.Min(alpha, -ip.tau/ip.dtau)
+	}
+	if ip.dkappa < 0 {
+		alpha = math.Min(alpha, -ip.kappa/ip.dkappa)
+	}
+	return alpha
+}
+
+// maxStep returns the largest step α such that v + α dv ≥ 0.
+func maxStep(v, dv []float64) float64 {
+	alpha := math.Inf(1)
+	for i, d := range dv {
+		if d < 0 {
+			alpha = math.Min(alpha, -v[i]/d)
+		}
+	}
+	return alpha
+}
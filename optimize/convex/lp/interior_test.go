@@ -0,0 +1,213 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInteriorPoint(t *testing.T) {
+	t.Parallel()
+	for i, test := range []struct {
+		A    mat.Matrix
+		b    []float64
+		c    []float64
+		opt  float64
+		xOpt []float64
+		err  error
+	}{
+		{
+			A: mat.NewDense(2, 4, []float64{
+				-1, 2, 1, 0,
+				3, 1, 0, 1,
+			}),
+			b:    []float64{4, 9},
+			c:    []float64{-1, -2, 0, 0},
+			opt:  -8,
+			xOpt: []float64{2, 3, 0, 0},
+		},
+		{
+			// Linearly dependent rows and a zero row.
+			A: mat.NewDense(4, 4, []float64{
+				-1, 2, 1, 0,
+				3, 1, 0, 1,
+				0, 0, 0, 0,
+				2, 3, 1, 1,
+			}),
+			b:    []float64{4, 9, 0, 13},
+			c:    []float64{-1, -2, 0, 0},
+			opt:  -8,
+			xOpt: []float64{2, 3, 0, 0},
+		},
+		{
+			// Zero row with non-zero right-hand side.
+			A:   mat.NewDense(2, 2, []float64{1, 1, 0, 0}),
+			b:   []float64{1, 1},
+			c:   []float64{1, 1},
+			err: ErrInfeasible,
+		},
+		{
+			// x0 + x1 = -1 has no non-negative solution.
+			A:   mat.NewDense(1, 3, []float64{1, 1, 0}),
+			b:   []float64{-1},
+			c:   []float64{1, 1, 1},
+			err: ErrInfeasible,
+		},
+		{
+			// x0 - x1 = 1 with x0 decreasing the objective without bound.
+			A:   mat.NewDense(1, 2, []float64{1, -1}),
+			b:   []float64{1},
+			c:   []float64{-1, 0},
+			err: ErrUnbounded,
+		},
+	} {
+		opt, x, err := InteriorPoint(test.c, test.A, test.b, 0)
+		if err != test.err {
+			t.Errorf("case %d: unexpected error: got %v, want %v", i, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !scalar.EqualWithinAbsOrRel(opt, test.opt, 1e-7, 1e-7) {
+			t.Errorf("case %d: unexpected optimum: got %v, want %v", i, opt, test.opt)
+		}
+		if !floats.EqualApprox(x, test.xOpt, 1e-7) {
+			t.Errorf("case %d: unexpected solution: got %v, want %v", i, x, test.xOpt)
+		}
+	}
+}
+
+func TestInteriorPointRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		n := rnd.Intn(60) + 2
+		m := rnd.Intn(n-1) + 1
+		pZero := 0.5 * rnd.Float64()
+
+		// Construct a problem with a feasible primal and a feasible dual
+		// so that an optimal solution exists.
+		a := mat.NewDense(m, n, nil)
+		for i := 0; i < m; i++ {
+			for j := 0; j < n; j++ {
+				if rnd.Float64() >= pZero {
+					a.Set(i, j, rnd.NormFloat64())
+				}
+			}
+		}
+		x0 := make([]float64, n)
+		s0 := make([]float64, n)
+		for j := range x0 {
+			if rnd.Float64() < 0.5 {
+				x0[j] = rnd.Float64()
+			} else {
+				s0[j] = rnd.Float64()
+			}
+		}
+		y0 := make([]float64, m)
+		for i := range y0 {
+			y0[i] = rnd.NormFloat64()
+		}
+		b := make([]float64, m)
+		bVec := mat.NewVecDense(m, b)
+		bVec.MulVec(a, mat.NewVecDense(n, x0))
+		c := make([]float64, n)
+		cVec := mat.NewVecDense(n, c)
+		cVec.MulVec(a.T(), mat.NewVecDense(m, y0))
+		floats.Add(c, s0)
+
+		// x0 and (y0, s0) are complementary, so they are optimal.
+		want := floats.Dot(c, x0)
+
+		opt, x, err := InteriorPoint(c, a, b, convergenceTol)
+		if err != nil {
+			t.Errorf("case %d (m=%d, n=%d): unexpected error: %v", i, m, n, err)
+			continue
+		}
+		if !scalar.EqualWithinAbsOrRel(opt, want, 1e-7, 1e-7) {
+			t.Errorf("case %d (m=%d, n=%d): unexpected optimum: got %v, want %v", i, m, n, opt, want)
+		}
+		if floats.Min(x) < 0 {
+			t.Errorf("case %d: solution not non-negative", i)
+		}
+		var ax mat.VecDense
+		ax.MulVec(a, mat.NewVecDense(n, x))
+		if !mat.EqualApprox(&ax, bVec, 1e-7) {
+			t.Errorf("case %d: solution infeasible", i)
+		}
+
+		// The solution does not depend on the representation of A.
+		var csr mat.CSR
+		csr.CloneFrom(a)
+		var csc mat.CSC
+		csc.CloneFrom(a)
+		for _, sa := range []mat.Matrix{&csr, &csc} {
+			sopt, sx, err := InteriorPoint(c, sa, b, convergenceTol)
+			if err != nil {
+				t.Errorf("case %d: unexpected error for %T: %v", i, sa, err)
+				continue
+			}
+			if sopt != opt || !floats.Equal(sx, x) {
+				t.Errorf("case %d: unexpected solution for %T: got %v, want %v", i, sa, sx, x)
+			}
+		}
+	}
+}
+
+func TestInteriorPointSimplex(t *testing.T) {
+	t.Parallel()
+	// Compare against Simplex on random problems.
+	rnd := rand.New(rand.NewSource(1))
+	var compared int
+	for i := 0; i < 500; i++ {
+		n := rnd.Intn(20) + 2
+		m := rnd.Intn(n-1) + 1
+		a := mat.NewDense(m, n, nil)
+		for i := 0; i < m; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		b := make([]float64, m)
+		for i := range b {
+			b[i] = rnd.NormFloat64()
+		}
+		c := make([]float64, n)
+		for i := range c {
+			c[i] = rnd.NormFloat64()
+		}
+		want, _, errSimplex := Simplex(c, a, b, convergenceTol, nil)
+		opt, _, err := InteriorPoint(c, a, b, convergenceTol)
+		if errSimplex == ErrInfeasible || errSimplex == ErrUnbounded {
+			// Problems where both the primal and the dual are infeasible
+			// may be reported as either.
+			if err != ErrInfeasible && err != ErrUnbounded {
+				t.Errorf("case %d: unexpected error: got %v, want %v", i, err, errSimplex)
+			}
+			continue
+		}
+		if errSimplex != nil {
+			continue
+		}
+		compared++
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if !scalar.EqualWithinAbsOrRel(opt, want, 1e-6, 1e-6) {
+			t.Errorf("case %d: optimum mismatch: got %v, want %v", i, opt, want)
+		}
+	}
+	if compared == 0 {
+		t.Errorf("no feasible problems generated")
+	}
+}
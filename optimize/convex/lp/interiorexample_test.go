@@ -0,0 +1,46 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp_test
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"gonum.org/v1/gonum/optimize/convex/lp"
+)
+
+func ExampleInteriorPoint() {
+	const model = `
+Maximize
+ profit: 3 chairs + 5 tables
+Subject To
+ wood:   chairs + 2 tables <= 14
+ labor:  3 chairs - tables >= 0
+ demand: chairs - tables <= 2
+End
+`
+	m, err := lp.ReadLP(strings.NewReader(model))
+	if err != nil {
+		log.Fatal(err)
+	}
+	s, err := m.StandardForm()
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, xs, err := lp.InteriorPoint(s.C, s.A, s.B, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	x := s.Solution(xs)
+	fmt.Printf("profit: %.3f\n", m.Value(x))
+	for j, name := range m.ColNames {
+		fmt.Printf("%s: %.3f\n", name, x[j])
+	}
+	// Output:
+	// profit: 38.000
+	// chairs: 6.000
+	// tables: 4.000
+}
@@ -0,0 +1,600 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ReadLP reads a linear program in CPLEX LP format from r.
+//
+// The objective section, the constraints section following "subject to",
+// and the bounds, general and binary sections are supported, as are ranged
+// constraints of the form "l <= expression <= u". Section keywords are not
+// case sensitive. Variables have the default bounds [0, +Inf) unless
+// changed in the bounds section. Variables listed in the general and binary
+// sections are marked in Model.Integer, and binary variables have bounds
+// [0, 1]. Unnamed constraints are named c1, c2, … in order. Quadratic
+// terms, semi-continuous variables, special ordered sets and indicator
+// constraints are not supported.
+func ReadLP(r io.Reader) (*Model, error) {
+	p := lpParser{b: newModelBuilder()}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	var (
+		line int
+		sect lpSection
+		toks []lpToken
+		done bool
+	)
+	flush := func() error {
+		err := p.parse(sect, toks)
+		toks = toks[:0]
+		return err
+	}
+	for sc.Scan() && !done {
+		line++
+		text := sc.Text()
+		if i := strings.IndexByte(text, '\\'); i >= 0 {
+			text = text[:i]
+		}
+		if s, rest, ok := lpKeyword(text); ok {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			if s == lpEnd {
+				done = true
+				break
+			}
+			if s <= sect && s != lpGeneral && s != lpBinary {
+				return nil, fmt.Errorf("lp: lp line %d: unexpected section", line)
+			}
+			if s == lpMinimize || s == lpMaximize {
+				if sect != lpNone {
+					return nil, fmt.Errorf("lp: lp line %d: unexpected objective section", line)
+				}
+			} else if sect == lpNone {
+				return nil, fmt.Errorf("lp: lp line %d: missing objective section", line)
+			}
+			sect = s
+			text = rest
+		}
+		if sect == lpNone && strings.TrimSpace(text) != "" {
+			return nil, fmt.Errorf("lp: lp line %d: missing objective section", line)
+		}
+		t, err := lpTokenize(text, line)
+		if err != nil {
+			return nil, err
+		}
+		toks = append(toks, t...)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if !done {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	if sect == lpNone {
+		return nil, fmt.Errorf("lp: lp: missing objective section")
+	}
+	return p.b.model(), nil
+}
+
+// lpSection is a section of an LP file, in the order the sections must
+// appear.
+type lpSection int
+
+const (
+	lpNone lpSection = iota
+	lpMinimize
+	lpMaximize
+	lpConstraints
+	lpBounds
+	lpGeneral
+	lpBinary
+	lpEnd
+)
+
+// lpKeywords maps the section keywords to sections.
+var lpKeywords = map[string]lpSection{
+	"minimize":   lpMinimize,
+	"minimum":    lpMinimize,
+	"min":        lpMinimize,
+	"maximize":   lpMaximize,
+	"maximum":    lpMaximize,
+	"max":        lpMaximize,
+	"subject to": lpConstraints,
+	"such that":  lpConstraints,
+	"st":         lpConstraints,
+	"s.t.":       lpConstraints,
+	"st.":        lpConstraints,
+	"bound":      lpBounds,
+	"bounds":     lpBounds,
+	"general":    lpGeneral,
+	"generals":   lpGeneral,
+	"gen":        lpGeneral,
+	"integer":    lpGeneral,
+	"integers":   lpGeneral,
+	"binary":     lpBinary,
+	"binaries":   lpBinary,
+	"bin":        lpBinary,
+	"end":        lpEnd,
+}
+
+// lpUnsupported lists the keywords of unsupported sections.
+var lpUnsupported = []string{"semi-continuous", "semis", "semi", "sos"}
+
+// lpKeyword returns the section started by the line and the remainder of
+// the line if the line starts with a section keyword.
+func lpKeyword(line string) (lpSection, string, bool) {
+	trimmed := strings.TrimSpace(line)
+	fields := strings.Fields(strings.ToLower(trimmed))
+	if len(fields) == 0 {
+		return lpNone, "", false
+	}
+	word := fields[0]
+	n := 1
+	if len(fields) > 1 && (word == "subject" && fields[1] == "to" || word == "such" && fields[1] == "that") {
+		word += " " + fields[1]
+		n = 2
+	}
+	s, ok := lpKeywords[word]
+	if !ok {
+		for _, u := range lpUnsupported {
+			if word == u {
+				// Return a section that will fail to parse.
+				return lpEnd + 1, "", true
+			}
+		}
+		return lpNone, "", false
+	}
+	rest := trimmed
+	for i := 0; i < n; i++ {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		k := strings.IndexFunc(rest, unicode.IsSpace)
+		if k < 0 {
+			rest = ""
+			break
+		}
+		rest = rest[k:]
+	}
+	return s, rest, true
+}
+
+// lpTokenKind is the kind of an LP file token.
+type lpTokenKind int
+
+const (
+	lpName lpTokenKind = iota
+	lpNumber
+	lpSign
+	lpCompare
+	lpColon
+)
+
+// lpToken is a token of an LP file.
+type lpToken struct {
+	kind lpTokenKind
+	text string
+	num  float64
+	line int
+}
+
+// lpTokenize splits a line of an LP file into tokens.
+func lpTokenize(s string, line int) ([]lpToken, error) {
+	var toks []lpToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '+' || c == '-':
+			toks = append(toks, lpToken{kind: lpSign, text: s[i : i+1], line: line})
+			i++
+		case c == ':':
+			toks = append(toks, lpToken{kind: lpColon, text: ":", line: line})
+			i++
+		case c == '<' || c == '>' || c == '=':
+			j := i + 1
+			if j < len(s) && (s[j] == '=' || s[j] == '<' || s[j] == '>') {
+				j++
+			}
+			op := s[i:j]
+			switch op {
+			case "<", "<=", "=<":
+				op = "<="
+			case ">", ">=", "=>":
+				op = ">="
+			case "=", "==":
+				op = "="
+			default:
+				return nil, fmt.Errorf("lp: lp line %d: invalid operator %q", line, op)
+			}
+			toks = append(toks, lpToken{kind: lpCompare, text: op, line: line})
+			i = j
+		case '0' <= c && c <= '9' || c == '.':
+			j := i
+			for j < len(s) && ('0' <= s[j] && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			if j < len(s) && (s[j] == 'e' || s[j] == 'E') {
+				k := j + 1
+				if k < len(s) && (s[k] == '+' || s[k] == '-') {
+					k++
+				}
+				if k < len(s) && '0' <= s[k] && s[k] <= '9' {
+					for k < len(s) && '0' <= s[k] && s[k] <= '9' {
+						k++
+					}
+					j = k
+				}
+			}
+			v, err := strconv.ParseFloat(s[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("lp: lp line %d: invalid number %q", line, s[i:j])
+			}
+			toks = append(toks, lpToken{kind: lpNumber, text: s[i:j], num: v, line: line})
+			i = j
+		case c == '[' || c == ']' || c == '^' || c == '*':
+			return nil, fmt.Errorf("lp: lp line %d: quadratic terms are not supported", line)
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t\r+-:<>=[]^*", rune(s[j])) {
+				j++
+			}
+			name := s[i:j]
+			tok := lpToken{kind: lpName, text: name, line: line}
+			switch strings.ToLower(name) {
+			case "inf", "infinity":
+				tok.kind = lpNumber
+				tok.num = math.Inf(1)
+			}
+			toks = append(toks, tok)
+			i = j
+		}
+	}
+	return toks, nil
+}
+
+// lpParser holds the state of ReadLP.
+type lpParser struct {
+	b    *modelBuilder
+	toks []lpToken
+	pos  int
+}
+
+// lpExpr is a linear expression.
+type lpExpr struct {
+	cols     []int
+	coef     []float64
+	constant float64
+}
+
+func (p *lpParser) errorf(format string, args ...interface{}) error {
+	line := 0
+	switch {
+	case p.pos < len(p.toks):
+		line = p.toks[p.pos].line
+	case len(p.toks) > 0:
+		line = p.toks[len(p.toks)-1].line
+	}
+	return fmt.Errorf("lp: lp line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *lpParser) peek(k int) (lpToken, bool) {
+	if p.pos+k < len(p.toks) {
+		return p.toks[p.pos+k], true
+	}
+	return lpToken{}, false
+}
+
+// parse parses the tokens of a section.
+func (p *lpParser) parse(sect lpSection, toks []lpToken) error {
+	p.toks = toks
+	p.pos = 0
+	switch sect {
+	case lpNone:
+		return nil
+	case lpMinimize, lpMaximize:
+		return p.objective(sect == lpMaximize)
+	case lpConstraints:
+		return p.constraints()
+	case lpBounds:
+		return p.bounds()
+	case lpGeneral, lpBinary:
+		for _, t := range p.toks {
+			if t.kind != lpName {
+				return fmt.Errorf("lp: lp line %d: unexpected %q", t.line, t.text)
+			}
+			j := p.b.col(t.text)
+			p.b.m.Integer[j] = true
+			if sect == lpBinary {
+				p.b.m.ColLower[j] = 0
+				p.b.m.ColUpper[j] = 1
+			}
+		}
+		return nil
+	default:
+		return p.errorf("unsupported section")
+	}
+}
+
+// label parses an optional "name:" label.
+func (p *lpParser) label() (string, bool) {
+	t, ok := p.peek(0)
+	if !ok || t.kind != lpName {
+		return "", false
+	}
+	c, ok := p.peek(1)
+	if !ok || c.kind != lpColon {
+		return "", false
+	}
+	p.pos += 2
+	return t.text, true
+}
+
+func (p *lpParser) objective(maximize bool) error {
+	p.b.m.Maximize = maximize
+	p.b.m.Objective = "obj"
+	if name, ok := p.label(); ok {
+		p.b.m.Objective = name
+	}
+	e, err := p.expr()
+	if err != nil {
+		return err
+	}
+	if p.pos != len(p.toks) {
+		return p.errorf("unexpected %q in objective", p.toks[p.pos].text)
+	}
+	for k, j := range e.cols {
+		p.b.m.C[j] += e.coef[k]
+	}
+	p.b.m.Offset = e.constant
+	return nil
+}
+
+// expr parses a linear expression of terms with optional signs and
+// coefficients. The expression ends at a comparison operator or at a
+// label.
+func (p *lpParser) expr() (lpExpr, error) {
+	var e lpExpr
+	first := true
+	for p.pos < len(p.toks) {
+		t := p.toks[p.pos]
+		if t.kind == lpCompare {
+			break
+		}
+		if _, ok := p.peek(1); ok && t.kind == lpName && p.toks[p.pos+1].kind == lpColon {
+			break
+		}
+		sign := 1.0
+		hasSign := false
+		for t.kind == lpSign {
+			hasSign = true
+			if t.text == "-" {
+				sign = -sign
+			}
+			p.pos++
+			var ok bool
+			t, ok = p.peek(0)
+			if !ok {
+				return e, p.errorf("incomplete expression")
+			}
+		}
+		if !first && !hasSign {
+			break
+		}
+		first = false
+		coef := 1.0
+		if t.kind == lpNumber {
+			coef = t.num
+			p.pos++
+			n, ok := p.peek(0)
+			if !ok || n.kind != lpName {
+				e.constant += sign * coef
+				continue
+			}
+			if nn, ok := p.peek(1); ok && nn.kind == lpColon {
+				// The number is a constant and the name labels the next
+				// constraint.
+				e.constant += sign * coef
+				continue
+			}
+			t = n
+		}
+		if t.kind != lpName {
+			return e, p.errorf("unexpected %q in expression", t.text)
+		}
+		p.pos++
+		e.cols = append(e.cols, p.b.col(t.text))
+		e.coef = append(e.coef, sign*coef)
+	}
+	return e, nil
+}
+
+// number parses an optionally signed number.
+func (p *lpParser) number() (float64, bool) {
+	sign := 1.0
+	pos := p.pos
+	for pos < len(p.toks) && p.toks[pos].kind == lpSign {
+		if p.toks[pos].text == "-" {
+			sign = -sign
+		}
+		pos++
+	}
+	if pos >= len(p.toks) || p.toks[pos].kind != lpNumber {
+		return 0, false
+	}
+	p.pos = pos + 1
+	return sign * p.toks[pos].num, true
+}
+
+// compare parses a comparison operator.
+func (p *lpParser) compare() (string, bool) {
+	t, ok := p.peek(0)
+	if !ok || t.kind != lpCompare {
+		return "", false
+	}
+	p.pos++
+	return t.text, true
+}
+
+// isRange returns whether the next tokens are a signed number followed by
+// a comparison operator.
+func (p *lpParser) isRange() bool {
+	pos := p.pos
+	for pos < len(p.toks) && p.toks[pos].kind == lpSign {
+		pos++
+	}
+	return pos+1 < len(p.toks) && p.toks[pos].kind == lpNumber && p.toks[pos+1].kind == lpCompare
+}
+
+func (p *lpParser) constraints() error {
+	for p.pos < len(p.toks) {
+		name, ok := p.label()
+		if !ok {
+			name = fmt.Sprintf("c%d", len(p.b.m.RowNames)+1)
+		}
+		lo, up := math.Inf(-1), math.Inf(1)
+		var e lpExpr
+		var err error
+		if p.isRange() {
+			// l <= expr <= u or u >= expr >= l.
+			v, _ := p.number()
+			op1, _ := p.compare()
+			e, err = p.expr()
+			if err != nil {
+				return err
+			}
+			op2, ok := p.compare()
+			if !ok || op1 != op2 || op1 == "=" {
+				return p.errorf("invalid ranged constraint %q", name)
+			}
+			w, ok := p.number()
+			if !ok {
+				return p.errorf("missing bound in constraint %q", name)
+			}
+			lo, up = v, w
+			if op1 == ">=" {
+				lo, up = w, v
+			}
+		} else {
+			e, err = p.expr()
+			if err != nil {
+				return err
+			}
+			op, ok := p.compare()
+			if !ok {
+				return p.errorf("missing operator in constraint %q", name)
+			}
+			v, ok := p.number()
+			if !ok {
+				return p.errorf("missing right-hand side in constraint %q", name)
+			}
+			switch op {
+			case "<=":
+				up = v
+			case ">=":
+				lo = v
+			case "=":
+				lo, up = v, v
+			}
+		}
+		if len(e.cols) == 0 {
+			return p.errorf("constraint %q has no variables", name)
+		}
+		i, ok := p.b.addRow(name, lo-e.constant, up-e.constant)
+		if !ok {
+			return p.errorf("duplicate constraint %q", name)
+		}
+		for k, j := range e.cols {
+			p.b.add(i, j, e.coef[k])
+		}
+	}
+	return nil
+}
+
+func (p *lpParser) bounds() error {
+	m := &p.b.m
+	for p.pos < len(p.toks) {
+		t := p.toks[p.pos]
+		if t.kind == lpName {
+			// x free, x op v.
+			j := p.b.col(t.text)
+			p.pos++
+			if n, ok := p.peek(0); ok && n.kind == lpName && strings.ToLower(n.text) == "free" {
+				p.pos++
+				m.ColLower[j] = math.Inf(-1)
+				m.ColUpper[j] = math.Inf(1)
+				continue
+			}
+			op, ok := p.compare()
+			if !ok {
+				return p.errorf("missing operator in bound on %q", t.text)
+			}
+			v, ok := p.number()
+			if !ok {
+				return p.errorf("missing value in bound on %q", t.text)
+			}
+			switch op {
+			case "<=":
+				m.ColUpper[j] = v
+			case ">=":
+				m.ColLower[j] = v
+			case "=":
+				m.ColLower[j] = v
+				m.ColUpper[j] = v
+			}
+			continue
+		}
+		// v op x [op w].
+		v, ok := p.number()
+		if !ok {
+			return p.errorf("unexpected %q in bounds", t.text)
+		}
+		op1, ok := p.compare()
+		if !ok {
+			return p.errorf("missing operator in bounds")
+		}
+		n, ok := p.peek(0)
+		if !ok || n.kind != lpName {
+			return p.errorf("missing variable in bounds")
+		}
+		p.pos++
+		j := p.b.col(n.text)
+		switch op1 {
+		case "<=":
+			m.ColLower[j] = v
+		case ">=":
+			m.ColUpper[j] = v
+		case "=":
+			m.ColLower[j] = v
+			m.ColUpper[j] = v
+		}
+		if op2, ok := p.compare(); ok {
+			if op2 != op1 || op1 == "=" {
+				return p.errorf("invalid bound on %q", n.text)
+			}
+			w, ok := p.number()
+			if !ok {
+				return p.errorf("missing value in bound on %q", n.text)
+			}
+			if op2 == "<=" {
+				m.ColUpper[j] = w
+			} else {
+				m.ColLower[j] = w
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,361 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Model is a linear program in general form,
+//
+//	minimize (or maximize)	cᵀ x + c₀
+//	s.t.					l_r <= A*x <= u_r
+//							l_x <= x <= u_x
+//
+// as read from a model file. Infinite bounds are represented by ±Inf, and
+// equality constraints have equal lower and upper bounds.
+type Model struct {
+	// Name is the name of the model.
+	Name string
+
+	// Maximize specifies whether the objective is maximized.
+	Maximize bool
+	// Objective is the name of the objective function.
+	Objective string
+	// C holds the objective coefficients c.
+	C []float64
+	// Offset is the constant term c₀ of the objective.
+	Offset float64
+
+	// A is the constraint matrix. A is nil if the model has no
+	// constraints. The models read from files have a *mat.CSC
+	// constraint matrix.
+	A mat.Matrix
+	// RowNames, RowLower and RowUpper hold the names and bounds of the
+	// constraints.
+	RowNames           []string
+	RowLower, RowUpper []float64
+
+	// ColNames, ColLower and ColUpper hold the names and bounds of the
+	// variables.
+	ColNames           []string
+	ColLower, ColUpper []float64
+
+	// Integer specifies which variables are marked as integer in the
+	// model file. Integrality is not enforced by the solvers in this
+	// package.
+	Integer []bool
+}
+
+// Value returns the value of the objective function of the model at x.
+func (m *Model) Value(x []float64) float64 {
+	if len(x) != len(m.C) {
+		panic(badShape)
+	}
+	f := m.Offset
+	for i, v := range x {
+		f += m.C[i] * v
+	}
+	return f
+}
+
+// StandardForm is a linear program in standard form obtained from a Model.
+// See Simplex for a description of the standard form.
+type StandardForm struct {
+	C []float64
+	// A is a *mat.CSC, or nil if the standard form problem has no
+	// constraints.
+	A mat.Matrix
+	B []float64
+
+	// cols describes how to recover the variables of the model.
+	cols []stdCol
+}
+
+// stdCol describes a model variable as a combination of standard form
+// variables,
+//
+//	x = shift + sign*x[pos] - x[neg]
+//
+// where pos and neg are negative if absent.
+type stdCol struct {
+	shift    float64
+	sign     float64
+	pos, neg int
+}
+
+// StandardForm converts the model into a linear program in standard form.
+// Variables with finite lower bounds are shifted, variables with only a
+// finite upper bound are negated and free variables are split into their
+// positive and negative parts. Fixed variables are substituted. Slack
+// variables are added for inequality constraints and for finite upper
+// bounds. Constraints without finite bounds are removed. If the model is a
+// maximization problem, the objective of the standard form is negated.
+//
+// StandardForm returns ErrInfeasible if a lower bound of the model is
+// greater than the corresponding upper bound, or if a constraint without
+// variables does not hold.
+func (m *Model) StandardForm() (*StandardForm, error) {
+	nVar := len(m.C)
+	nRow := len(m.RowLower)
+	for j := 0; j < nVar; j++ {
+		if m.ColLower[j] > m.ColUpper[j] {
+			return nil, ErrInfeasible
+		}
+	}
+	for i := 0; i < nRow; i++ {
+		if m.RowLower[i] > m.RowUpper[i] {
+			return nil, ErrInfeasible
+		}
+	}
+	sign := 1.0
+	if m.Maximize {
+		sign = -1
+	}
+
+	// Map the model variables to non-negative variables.
+	cols := make([]stdCol, nVar)
+	var nStd int
+	var ubRows []int // Standard form variables with upper bound u-l.
+	var ubVals []float64
+	for j := range cols {
+		lo, up := m.ColLower[j], m.ColUpper[j]
+		col := stdCol{sign: 1, pos: -1, neg: -1}
+		switch {
+		case lo == up:
+			col.shift = lo
+		case !math.IsInf(lo, -1):
+			col.shift = lo
+			col.pos = nStd
+			nStd++
+			if !math.IsInf(up, 1) {
+				ubRows = append(ubRows, col.pos)
+				ubVals = append(ubVals, up-lo)
+			}
+		case !math.IsInf(up, 1):
+			col.shift = up
+			col.sign = -1
+			col.pos = nStd
+			nStd++
+		default:
+			col.pos = nStd
+			col.neg = nStd + 1
+			nStd += 2
+		}
+		cols[j] = col
+	}
+
+	// Substitute the variables into the constraints.
+	type stdRow struct {
+		row   int
+		slack float64 // Coefficient of the slack variable, if any.
+		rhs   float64
+		rng   float64 // Upper bound of the slack variable, if finite.
+	}
+	// The constraint matrix is traversed by rows in compressed
+	// form so that the conversion is linear in the number of
+	// non-zero elements.
+	var a mat.CSR
+	if m.A != nil {
+		a.CloneFrom(m.A)
+	}
+	doRow := func(i int, fn func(j int, v float64)) {
+		if m.A == nil {
+			return
+		}
+		a.DoRowNonZero(i, func(_, j int, v float64) { fn(j, v) })
+	}
+	var rows []stdRow
+	for i := 0; i < nRow; i++ {
+		lo, up := m.RowLower[i], m.RowUpper[i]
+		var shift float64
+		empty := true
+		doRow(i, func(j int, v float64) {
+			col := cols[j]
+			shift += v * col.shift
+			if col.pos >= 0 {
+				empty = false
+			}
+		})
+		if empty {
+			if shift < lo || up < shift {
+				return nil, ErrInfeasible
+			}
+			continue
+		}
+		r := stdRow{row: i, rng: math.Inf(1)}
+		switch {
+		case lo == up:
+			r.rhs = lo - shift
+		case math.IsInf(lo, -1) && math.IsInf(up, 1):
+			continue
+		case math.IsInf(lo, -1):
+			// a x + s = u.
+			r.slack = 1
+			r.rhs = up - shift
+		default:
+			// a x - s = l, with s <= u - l if u is finite.
+			r.slack = -1
+			r.rhs = lo - shift
+			r.rng = up - lo
+		}
+		rows = append(rows, r)
+	}
+	var nSlack int
+	for _, r := range rows {
+		if r.slack != 0 {
+			nSlack++
+		}
+		if !math.IsInf(r.rng, 1) {
+			ubVals = append(ubVals, r.rng)
+		}
+	}
+	nUB := len(ubVals)
+	n := nStd + nSlack + nUB
+	nEq := len(rows) + nUB
+
+	s := &StandardForm{
+		C:    make([]float64, n),
+		B:    make([]float64, nEq),
+		cols: cols,
+	}
+	for j, col := range cols {
+		if col.pos >= 0 {
+			s.C[col.pos] = sign * col.sign * m.C[j]
+		}
+		if col.neg >= 0 {
+			s.C[col.neg] = -sign * m.C[j]
+		}
+	}
+	if nEq == 0 {
+		return s, nil
+	}
+	sa := mat.NewCOO(nEq, n, nil, nil, nil)
+	slack := nStd
+	ub := nStd + nSlack
+	for k, r := range rows {
+		doRow(r.row, func(j int, v float64) {
+			col := cols[j]
+			if col.pos >= 0 {
+				sa.Append(k, col.pos, col.sign*v)
+			}
+			if col.neg >= 0 {
+				sa.Append(k, col.neg, -v)
+			}
+		})
+		s.B[k] = r.rhs
+		if r.slack != 0 {
+			sa.Append(k, slack, r.slack)
+			if !math.IsInf(r.rng, 1) {
+				ubRows = append(ubRows, slack)
+			}
+			slack++
+		}
+	}
+	// Upper bounds x + w = u with the surplus variable w.
+	for k, v := range ubRows {
+		row := len(rows) + k
+		sa.Append(row, v, 1)
+		sa.Append(row, ub+k, 1)
+		s.B[row] = ubVals[k]
+	}
+	var csc mat.CSC
+	csc.CloneFrom(sa)
+	s.A = &csc
+	return s, nil
+}
+
+// Solution returns the variables of the model corresponding to the solution
+// x of the standard form problem.
+func (s *StandardForm) Solution(x []float64) []float64 {
+	if len(x) != len(s.C) {
+		panic(badShape)
+	}
+	dst := make([]float64, len(s.cols))
+	for j, col := range s.cols {
+		v := col.shift
+		if col.pos >= 0 {
+			v += col.sign * x[col.pos]
+		}
+		if col.neg >= 0 {
+			v -= x[col.neg]
+		}
+		dst[j] = v
+	}
+	return dst
+}
+
+// modelBuilder assembles a Model from named rows, columns and coefficients.
+type modelBuilder struct {
+	m      Model
+	rowIdx map[string]int
+	colIdx map[string]int
+	coef   []coefficient
+}
+
+// coefficient is an element of the constraint matrix of a Model.
+type coefficient struct {
+	row, col int
+	v        float64
+}
+
+func newModelBuilder() *modelBuilder {
+	return &modelBuilder{
+		rowIdx: make(map[string]int),
+		colIdx: make(map[string]int),
+	}
+}
+
+// addRow adds a constraint with the given name and bounds and returns its
+// index. addRow returns false if a constraint with the name already exists.
+func (b *modelBuilder) addRow(name string, lo, up float64) (int, bool) {
+	if _, ok := b.rowIdx[name]; ok {
+		return -1, false
+	}
+	i := len(b.m.RowNames)
+	b.rowIdx[name] = i
+	b.m.RowNames = append(b.m.RowNames, name)
+	b.m.RowLower = append(b.m.RowLower, lo)
+	b.m.RowUpper = append(b.m.RowUpper, up)
+	return i, true
+}
+
+// col returns the index of the variable with the given name, adding the
+// variable with bounds [0, +Inf) if it does not exist.
+func (b *modelBuilder) col(name string) int {
+	if j, ok := b.colIdx[name]; ok {
+		return j
+	}
+	j := len(b.m.ColNames)
+	b.colIdx[name] = j
+	b.m.ColNames = append(b.m.ColNames, name)
+	b.m.ColLower = append(b.m.ColLower, 0)
+	b.m.ColUpper = append(b.m.ColUpper, math.Inf(1))
+	b.m.Integer = append(b.m.Integer, false)
+	b.m.C = append(b.m.C, 0)
+	return j
+}
+
+// add adds v to the coefficient of variable col in constraint row.
+func (b *modelBuilder) add(row, col int, v float64) {
+	b.coef = append(b.coef, coefficient{row: row, col: col, v: v})
+}
+
+// model returns the assembled Model.
+func (b *modelBuilder) model() *Model {
+	m := b.m
+	if len(m.RowNames) != 0 && len(m.ColNames) != 0 {
+		a := mat.NewCOO(len(m.RowNames), len(m.ColNames), nil, nil, nil)
+		for _, c := range b.coef {
+			a.Append(c.row, c.col, c.v)
+		}
+		var csc mat.CSC
+		csc.CloneFrom(a)
+		m.A = &csc
+	}
+	return &m
+}
@@ -0,0 +1,398 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+const testProbMPS = `NAME          TESTPROB
+* Example from the MPS format specification.
+ROWS
+ N  COST
+ L  LIM1
+ G  LIM2
+ E  MYEQN
+COLUMNS
+    XONE      COST         1   LIM1         1
+    XONE      LIM2         1
+    YTWO      COST         2   LIM1         1
+    YTWO      MYEQN       -1
+    ZTHREE    COST         3   LIM2         1
+    ZTHREE    MYEQN        1
+RHS
+    RHS1      LIM1         4   LIM2         1
+    RHS1      MYEQN        7
+BOUNDS
+ UP BND1      XONE         4
+ LO BND1      YTWO        -1
+ UP BND1      YTWO         1
+ENDATA
+`
+
+const testProbLP = `\ Example from the MPS format specification.
+Minimize
+ COST: XONE + 2 YTWO + 3 ZTHREE
+Subject To
+ LIM1: XONE + YTWO <= 4
+ LIM2: XONE + ZTHREE >= 1
+ MYEQN: - YTWO + ZTHREE = 7
+Bounds
+ 0 <= XONE <= 4
+ -1 <= YTWO <= 1
+End
+`
+
+func testProbModel() *Model {
+	inf := math.Inf(1)
+	return &Model{
+		Name:      "TESTPROB",
+		Objective: "COST",
+		C:         []float64{1, 2, 3},
+		A: mat.NewDense(3, 3, []float64{
+			1, 1, 0,
+			1, 0, 1,
+			0, -1, 1,
+		}),
+		RowNames: []string{"LIM1", "LIM2", "MYEQN"},
+		RowLower: []float64{-inf, 1, 7},
+		RowUpper: []float64{4, inf, 7},
+		ColNames: []string{"XONE", "YTWO", "ZTHREE"},
+		ColLower: []float64{0, -1, 0},
+		ColUpper: []float64{4, 1, inf},
+		Integer:  []bool{false, false, false},
+	}
+}
+
+func TestReadMPS(t *testing.T) {
+	t.Parallel()
+	inf := math.Inf(1)
+	for _, test := range []struct {
+		name string
+		src  string
+		want *Model
+	}{
+		{
+			name: "TESTPROB",
+			src:  testProbMPS,
+			want: testProbModel(),
+		},
+		{
+			name: "free",
+			src: `NAME features
+OBJSENSE
+    MAX
+ROWS
+ N obj
+ E eq
+ E eqneg
+ L le
+ G ge
+ N unused
+COLUMNS
+ x obj 1 eq 1
+ x unused 5
+ MARKER 'MARKER' 'INTORG'
+ y obj -2 le 1
+ y ge 1
+ MARKER 'MARKER' 'INTEND'
+ z obj 3 eqneg 1
+ z le 1 ge 2
+ w eq 1
+ v obj 1
+ u ge 1
+RHS
+ rhs obj 1.5 eq 2
+ rhs eqneg 3 le 4
+ rhs ge 5
+ other eq 100
+RANGES
+ rng eq 1 eqneg -2
+ rng le 3 ge -4
+BOUNDS
+ UP bnd x -1
+ FR bnd y
+ MI bnd z
+ FX bnd w 2.5
+ BV bnd v
+ UP bnd u 1e30
+ENDATA
+`,
+			want: &Model{
+				Name:      "features",
+				Maximize:  true,
+				Objective: "obj",
+				C:         []float64{1, -2, 3, 0, 1, 0},
+				Offset:    -1.5,
+				A: mat.NewDense(4, 6, []float64{
+					1, 0, 0, 1, 0, 0,
+					0, 0, 1, 0, 0, 0,
+					0, 1, 1, 0, 0, 0,
+					0, 1, 2, 0, 0, 1,
+				}),
+				RowNames: []string{"eq", "eqneg", "le", "ge"},
+				RowLower: []float64{2, 1, 1, 5},
+				RowUpper: []float64{3, 3, 4, 9},
+				ColNames: []string{"x", "y", "z", "w", "v", "u"},
+				ColLower: []float64{-inf, -inf, -inf, 2.5, 0, 0},
+				ColUpper: []float64{-1, inf, inf, 2.5, 1, inf},
+				Integer:  []bool{false, true, false, false, true, false},
+			},
+		},
+	} {
+		got, err := ReadMPS(strings.NewReader(test.src))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !equalModel(got, test.want) {
+			t.Errorf("%s: unexpected model:\ngot: %+v\nwant:%+v", test.name, got, test.want)
+		}
+		if _, ok := got.A.(*mat.CSC); !ok && got.A != nil {
+			t.Errorf("%s: unexpected constraint matrix type: %T", test.name, got.A)
+		}
+	}
+}
+
+// equalModel returns whether the models a and b are equal, comparing
+// their constraint matrices by value.
+func equalModel(a, b *Model) bool {
+	if (a.A == nil) != (b.A == nil) {
+		return false
+	}
+	if a.A != nil && !mat.Equal(a.A, b.A) {
+		return false
+	}
+	ac, bc := *a, *b
+	ac.A, bc.A = nil, nil
+	return reflect.DeepEqual(ac, bc)
+}
+
+func TestReadMPSErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		src  string
+	}{
+		{name: "missing ENDATA", src: "NAME x\nROWS\n N obj\n"},
+		{name: "unknown section", src: "NAME x\nQUADOBJ\nENDATA\n"},
+		{name: "unknown row type", src: "ROWS\n X obj\nENDATA\n"},
+		{name: "duplicate row", src: "ROWS\n N obj\n L r\n G r\nENDATA\n"},
+		{name: "unknown row", src: "ROWS\n N obj\nCOLUMNS\n x r 1\nENDATA\n"},
+		{name: "invalid value", src: "ROWS\n N obj\nCOLUMNS\n x obj one\nENDATA\n"},
+		{name: "unknown column", src: "ROWS\n N obj\nCOLUMNS\n x obj 1\nBOUNDS\n UP b y 1\nENDATA\n"},
+		{name: "semi-continuous", src: "ROWS\n N obj\nCOLUMNS\n x obj 1\nBOUNDS\n SC b x 1\nENDATA\n"},
+		{name: "data before section", src: " x obj 1\nENDATA\n"},
+	} {
+		_, err := ReadMPS(strings.NewReader(test.src))
+		if err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+}
+
+func TestReadLP(t *testing.T) {
+	t.Parallel()
+	inf := math.Inf(1)
+	for _, test := range []struct {
+		name string
+		src  string
+		want *Model
+	}{
+		{
+			name: "TESTPROB",
+			src:  testProbLP,
+			want: func() *Model {
+				m := testProbModel()
+				m.Name = ""
+				return m
+			}(),
+		},
+		{
+			name: "features",
+			src: `\ A model using most features of the format.
+MAXIMIZE
+  profit: 3 x + 2.5e0 y
+     - z + 1.5 \ The objective spans two lines.
+subject to
+  x + y <= 4
+  cap: 2 x
+     + 3 y >= -inf
+  -2 <= x - z <= 2
+  3 >= y + z >= 1
+  x + 2 y - 1 = 0
+bounds
+  x <= 10
+  -inf <= y <= 1
+  z free
+  w = 3
+generals
+  x
+binaries
+  v
+end
+`,
+			want: &Model{
+				Maximize:  true,
+				Objective: "profit",
+				C:         []float64{3, 2.5, -1, 0, 0},
+				Offset:    1.5,
+				A: mat.NewDense(5, 5, []float64{
+					1, 1, 0, 0, 0,
+					2, 3, 0, 0, 0,
+					1, 0, -1, 0, 0,
+					0, 1, 1, 0, 0,
+					1, 2, 0, 0, 0,
+				}),
+				RowNames: []string{"c1", "cap", "c3", "c4", "c5"},
+				RowLower: []float64{-inf, -inf, -2, 1, 1},
+				RowUpper: []float64{4, inf, 2, 3, 1},
+				ColNames: []string{"x", "y", "z", "w", "v"},
+				ColLower: []float64{0, -inf, -inf, 3, 0},
+				ColUpper: []float64{10, 1, inf, 3, 1},
+				Integer:  []bool{true, false, false, false, true},
+			},
+		},
+	} {
+		got, err := ReadLP(strings.NewReader(test.src))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !equalModel(got, test.want) {
+			t.Errorf("%s: unexpected model:\ngot: %+v\nwant:%+v", test.name, got, test.want)
+		}
+		if _, ok := got.A.(*mat.CSC); !ok && got.A != nil {
+			t.Errorf("%s: unexpected constraint matrix type: %T", test.name, got.A)
+		}
+	}
+}
+
+func TestReadLPErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		src  string
+	}{
+		{name: "missing objective", src: "subject to\n x >= 1\nend\n"},
+		{name: "data before objective", src: "x + y\nminimize\n x\nend\n"},
+		{name: "quadratic", src: "minimize\n x + [ x ^ 2 ]\nend\n"},
+		{name: "missing rhs", src: "minimize\n x\nst\n x + y <=\nend\n"},
+		{name: "missing operator", src: "minimize\n x\nst\n c: x + y\nend\n"},
+		{name: "duplicate constraint", src: "minimize\n x\nst\n c: x >= 1\n c: x <= 2\nend\n"},
+		{name: "invalid range", src: "minimize\n x\nst\n 1 <= x >= 2\nend\n"},
+		{name: "invalid bound", src: "minimize\n x\nbounds\n x <=\nend\n"},
+		{name: "sections out of order", src: "minimize\n x\nbounds\n x <= 1\nst\n x >= 0\nend\n"},
+		{name: "semi-continuous", src: "minimize\n x\nsemi-continuous\n x\nend\n"},
+	} {
+		_, err := ReadLP(strings.NewReader(test.src))
+		if err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+}
+
+func TestStandardForm(t *testing.T) {
+	t.Parallel()
+	inf := math.Inf(1)
+	for _, test := range []struct {
+		name  string
+		model *Model
+		want  float64
+	}{
+		{
+			name:  "TESTPROB",
+			model: testProbModel(),
+			want:  16,
+		},
+		{
+			// maximize x1 + x2 - x3 + 2 x4 + 1
+			// s.t. x1 + x2 <= 6
+			//      1 <= x2 - x3 <= 2
+			//      x3 >= -4
+			//      -2 <= x1 <= 3, x2 <= 5, x3 free, x4 = 1.
+			name: "bounds",
+			model: &Model{
+				Maximize: true,
+				C:        []float64{1, 1, -1, 2},
+				Offset:   1,
+				A: mat.NewDense(3, 4, []float64{
+					1, 1, 0, 0,
+					0, 1, -1, 0,
+					0, 0, 1, 0,
+				}),
+				RowLower: []float64{-inf, 1, -4},
+				RowUpper: []float64{6, 2, inf},
+				ColLower: []float64{-2, -inf, -inf, 1},
+				ColUpper: []float64{3, 5, inf, 1},
+			},
+			want: 8,
+		},
+	} {
+		s, err := test.model.StandardForm()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		for _, solver := range []struct {
+			name  string
+			solve func(c []float64, a mat.Matrix, b []float64) ([]float64, error)
+		}{
+			{
+				name: "Simplex",
+				solve: func(c []float64, a mat.Matrix, b []float64) ([]float64, error) {
+					_, x, err := Simplex(c, a, b, 0, nil)
+					return x, err
+				},
+			},
+			{
+				name: "InteriorPoint",
+				solve: func(c []float64, a mat.Matrix, b []float64) ([]float64, error) {
+					_, x, err := InteriorPoint(c, a, b, 0)
+					return x, err
+				},
+			},
+		} {
+			xs, err := solver.solve(s.C, s.A, s.B)
+			if err != nil {
+				t.Errorf("%s %s: unexpected error: %v", test.name, solver.name, err)
+				continue
+			}
+			x := s.Solution(xs)
+			got := test.model.Value(x)
+			if !scalar.EqualWithinAbsOrRel(got, test.want, 1e-7, 1e-7) {
+				t.Errorf("%s %s: unexpected optimum: got %v, want %v", test.name, solver.name, got, test.want)
+			}
+			const tol = 1e-7
+			m := test.model
+			for j, v := range x {
+				if v < m.ColLower[j]-tol || m.ColUpper[j]+tol < v {
+					t.Errorf("%s %s: variable %d out of bounds: %v", test.name, solver.name, j, v)
+				}
+			}
+			for i := range m.RowLower {
+				v := floats.Dot(mat.Row(nil, i, m.A), x)
+				if v < m.RowLower[i]-tol || m.RowUpper[i]+tol < v {
+					t.Errorf("%s %s: constraint %d violated: %v", test.name, solver.name, i, v)
+				}
+			}
+		}
+	}
+
+	// Inconsistent bounds.
+	m := testProbModel()
+	m.ColLower[0] = 5
+	_, err := m.StandardForm()
+	if err != ErrInfeasible {
+		t.Errorf("unexpected error for inconsistent bounds: got %v, want %v", err, ErrInfeasible)
+	}
+}
@@ -0,0 +1,379 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// mpsInf is the magnitude at and above which bounds in MPS files are
+// treated as infinite.
+const mpsInf = 1e30
+
+// ReadMPS reads a linear program in MPS format from r. Both the fixed and
+// the free MPS formats are accepted, provided that names do not contain
+// spaces.
+//
+// The first N row in the ROWS section is the objective, further N rows are
+// ignored. A right-hand side given for the objective row is the negated
+// constant term of the objective. Only the first RHS, RANGES and BOUNDS
+// vectors are used. The OBJSENSE section of the free MPS format is
+// supported. Variables have the default bounds [0, +Inf), and following
+// common practice an UP bound with a negative value on a variable with a
+// zero lower bound sets the lower bound to -Inf. Bounds with a magnitude of
+// at least 1e30 are treated as infinite. Variables within integer markers
+// or with BV, LI or UI bounds are marked in Model.Integer. Semi-continuous
+// variables, quadratic objectives and other extensions are not supported.
+func ReadMPS(r io.Reader) (*Model, error) {
+	p := mpsParser{
+		b:     newModelBuilder(),
+		types: make(map[string]byte),
+		free:  make(map[string]bool),
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	var line int
+	for sc.Scan() {
+		line++
+		text := sc.Text()
+		if strings.TrimSpace(text) == "" || text[0] == '*' {
+			continue
+		}
+		fields := strings.Fields(text)
+		var err error
+		if text[0] != ' ' && text[0] != '\t' {
+			err = p.section(fields)
+		} else {
+			err = p.data(fields)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("lp: mps line %d: %v", line, err)
+		}
+		if p.sect == "ENDATA" {
+			break
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if p.sect != "ENDATA" {
+		return nil, fmt.Errorf("lp: mps: missing ENDATA")
+	}
+	return p.model(), nil
+}
+
+// mpsParser holds the state of ReadMPS.
+type mpsParser struct {
+	b    *modelBuilder
+	sect string
+
+	types map[string]byte // Types of the constraint rows.
+	free  map[string]bool // Ignored N rows.
+	rhs   []float64
+	rng   []float64
+	hasR  []bool
+
+	integer bool
+
+	rhsSet, rangeSet, boundSet string
+}
+
+// section handles a section header line.
+func (p *mpsParser) section(fields []string) error {
+	p.sect = strings.ToUpper(fields[0])
+	switch p.sect {
+	case "NAME":
+		if len(fields) > 1 {
+			p.b.m.Name = fields[1]
+		}
+	case "OBJSENSE":
+		if len(fields) > 1 {
+			return p.objsense(fields[1])
+		}
+	case "ROWS", "COLUMNS", "RHS", "RANGES", "BOUNDS", "ENDATA":
+		if len(fields) > 1 {
+			return fmt.Errorf("unexpected data after %s", p.sect)
+		}
+	default:
+		return fmt.Errorf("unsupported section %q", fields[0])
+	}
+	return nil
+}
+
+// objsense sets the optimization direction.
+func (p *mpsParser) objsense(sense string) error {
+	switch strings.ToUpper(sense) {
+	case "MAX", "MAXIMIZE":
+		p.b.m.Maximize = true
+	case "MIN", "MINIMIZE":
+		p.b.m.Maximize = false
+	default:
+		return fmt.Errorf("unknown objective sense %q", sense)
+	}
+	return nil
+}
+
+// data handles a data line in the current section.
+func (p *mpsParser) data(fields []string) error {
+	switch p.sect {
+	case "OBJSENSE":
+		return p.objsense(fields[0])
+	case "ROWS":
+		return p.row(fields)
+	case "COLUMNS":
+		return p.column(fields)
+	case "RHS":
+		return p.vector(fields, &p.rhsSet, p.setRHS)
+	case "RANGES":
+		return p.vector(fields, &p.rangeSet, p.setRange)
+	case "BOUNDS":
+		return p.bound(fields)
+	case "":
+		return fmt.Errorf("data before section header")
+	default:
+		return fmt.Errorf("unexpected data in section %s", p.sect)
+	}
+}
+
+func (p *mpsParser) row(fields []string) error {
+	if len(fields) != 2 {
+		return fmt.Errorf("invalid row definition")
+	}
+	typ := strings.ToUpper(fields[0])
+	name := fields[1]
+	if _, ok := p.types[name]; ok || name == p.b.m.Objective || p.free[name] {
+		return fmt.Errorf("duplicate row %q", name)
+	}
+	switch typ {
+	case "N":
+		if p.b.m.Objective == "" {
+			p.b.m.Objective = name
+		} else {
+			p.free[name] = true
+		}
+		return nil
+	case "L", "G", "E":
+	default:
+		return fmt.Errorf("unknown row type %q", fields[0])
+	}
+	p.types[name] = typ[0]
+	p.b.addRow(name, 0, 0)
+	p.rhs = append(p.rhs, 0)
+	p.rng = append(p.rng, 0)
+	p.hasR = append(p.hasR, false)
+	return nil
+}
+
+func (p *mpsParser) column(fields []string) error {
+	if len(fields) >= 3 && strings.Trim(fields[1], "'") == "MARKER" {
+		switch strings.Trim(fields[len(fields)-1], "'") {
+		case "INTORG":
+			p.integer = true
+		case "INTEND":
+			p.integer = false
+		default:
+			return fmt.Errorf("unknown marker %q", fields[len(fields)-1])
+		}
+		return nil
+	}
+	if len(fields) != 3 && len(fields) != 5 {
+		return fmt.Errorf("invalid column entry")
+	}
+	j := p.b.col(fields[0])
+	if p.integer {
+		p.b.m.Integer[j] = true
+	}
+	for k := 1; k < len(fields); k += 2 {
+		v, err := strconv.ParseFloat(fields[k+1], 64)
+		if err != nil {
+			return err
+		}
+		row := fields[k]
+		switch {
+		case row == p.b.m.Objective:
+			p.b.m.C[j] += v
+		case p.free[row]:
+		default:
+			i, ok := p.b.rowIdx[row]
+			if !ok {
+				return fmt.Errorf("unknown row %q", row)
+			}
+			p.b.add(i, j, v)
+		}
+	}
+	return nil
+}
+
+// vector handles a line of the RHS or RANGES sections, which consist of an
+// optional vector name followed by one or two pairs of row names and values.
+func (p *mpsParser) vector(fields []string, set *string, fn func(row string, v float64) error) error {
+	if len(fields)%2 == 1 {
+		if *set == "" {
+			*set = fields[0]
+		}
+		if fields[0] != *set {
+			return nil
+		}
+		fields = fields[1:]
+	}
+	if len(fields) != 2 && len(fields) != 4 {
+		return fmt.Errorf("invalid %s entry", p.sect)
+	}
+	for k := 0; k < len(fields); k += 2 {
+		v, err := strconv.ParseFloat(fields[k+1], 64)
+		if err != nil {
+			return err
+		}
+		if err := fn(fields[k], v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *mpsParser) setRHS(row string, v float64) error {
+	switch {
+	case row == p.b.m.Objective:
+		p.b.m.Offset = -v
+	case p.free[row]:
+	default:
+		i, ok := p.b.rowIdx[row]
+		if !ok {
+			return fmt.Errorf("unknown row %q", row)
+		}
+		p.rhs[i] = v
+	}
+	return nil
+}
+
+func (p *mpsParser) setRange(row string, v float64) error {
+	i, ok := p.b.rowIdx[row]
+	if !ok {
+		if row == p.b.m.Objective || p.free[row] {
+			return fmt.Errorf("range on free row %q", row)
+		}
+		return fmt.Errorf("unknown row %q", row)
+	}
+	p.rng[i] = v
+	p.hasR[i] = true
+	return nil
+}
+
+func (p *mpsParser) bound(fields []string) error {
+	if len(fields) < 2 {
+		return fmt.Errorf("invalid bound entry")
+	}
+	typ := strings.ToUpper(fields[0])
+	var hasValue bool
+	switch typ {
+	case "UP", "LO", "FX", "LI", "UI":
+		hasValue = true
+	case "FR", "MI", "PL", "BV":
+	case "SC":
+		return fmt.Errorf("semi-continuous variables are not supported")
+	default:
+		return fmt.Errorf("unknown bound type %q", fields[0])
+	}
+	want := 2
+	if hasValue {
+		want++
+	}
+	switch {
+	case len(fields) == want:
+	case len(fields) == want+1, !hasValue && len(fields) == want+2:
+		// The second field is the name of the bound vector. Some writers
+		// also include a value for bound types without one.
+		if p.boundSet == "" {
+			p.boundSet = fields[1]
+		}
+		if fields[1] != p.boundSet {
+			return nil
+		}
+		fields = fields[1:]
+	default:
+		return fmt.Errorf("invalid bound entry")
+	}
+	j, ok := p.b.colIdx[fields[1]]
+	if !ok {
+		return fmt.Errorf("unknown column %q", fields[1])
+	}
+	var v float64
+	if hasValue {
+		var err error
+		v, err = strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return err
+		}
+		if v >= mpsInf {
+			v = math.Inf(1)
+		} else if v <= -mpsInf {
+			v = math.Inf(-1)
+		}
+	}
+	m := &p.b.m
+	switch typ {
+	case "UP", "UI":
+		m.ColUpper[j] = v
+		if v < 0 && m.ColLower[j] == 0 {
+			m.ColLower[j] = math.Inf(-1)
+		}
+	case "LO", "LI":
+		m.ColLower[j] = v
+	case "FX":
+		m.ColLower[j] = v
+		m.ColUpper[j] = v
+	case "FR":
+		m.ColLower[j] = math.Inf(-1)
+		m.ColUpper[j] = math.Inf(1)
+	case "MI":
+		m.ColLower[j] = math.Inf(-1)
+	case "PL":
+		m.ColUpper[j] = math.Inf(1)
+	case "BV":
+		m.ColLower[j] = 0
+		m.ColUpper[j] = 1
+	}
+	if typ == "BV" || typ == "LI" || typ == "UI" {
+		m.Integer[j] = true
+	}
+	return nil
+}
+
+// model sets the constraint bounds and returns the Model.
+func (p *mpsParser) model() *Model {
+	m := &p.b.m
+	for i, name := range m.RowNames {
+		rhs := p.rhs[i]
+		lo, up := rhs, rhs
+		switch p.types[name] {
+		case 'L':
+			lo = math.Inf(-1)
+			if p.hasR[i] {
+				lo = rhs - math.Abs(p.rng[i])
+			}
+		case 'G':
+			up = math.Inf(1)
+			if p.hasR[i] {
+				up = rhs + math.Abs(p.rng[i])
+			}
+		case 'E':
+			if p.hasR[i] {
+				if p.rng[i] > 0 {
+					up = rhs + p.rng[i]
+				} else {
+					lo = rhs + p.rng[i]
+				}
+			}
+		}
+		m.RowLower[i] = lo
+		m.RowUpper[i] = up
+	}
+	return p.b.model()
+}
@@ -0,0 +1,471 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package qp
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	ErrInfeasible          = errors.New("qp: problem is infeasible")
+	ErrIterationLimit      = errors.New("qp: iteration limit reached")
+	ErrNotPositiveDefinite = errors.New("qp: Q is not positive definite")
+	ErrSingular            = errors.New("qp: equality constraints are linearly dependent")
+)
+
+const badShape = "qp: size mismatch"
+
+const (
+	// defaultTol is the default tolerance on constraint violations.
+	defaultTol = 1e-10
+	// depTol is the relative tolerance for deciding that a constraint
+	// normal is linearly dependent on the normals of the working set.
+	depTol = 1e-10
+	// maxCond is the largest condition number of the working set
+	// matrix for which the working set is considered linearly independent.
+	maxCond = 1e12
+)
+
+// Problem is a convex quadratic program,
+//
+//	minimize	½ xᵀ Q x + cᵀ x
+//	s.t.		A x = b
+//				G x <= h
+//
+// where Q is positive definite.
+type Problem struct {
+	Q mat.Symmetric
+	C []float64
+
+	// A and B specify the equality constraints. A may be nil if
+	// there are no equality constraints.
+	A mat.Matrix
+	B []float64
+
+	// G and H specify the inequality constraints. G may be nil if
+	// there are no inequality constraints.
+	G mat.Matrix
+	H []float64
+}
+
+// Settings holds the settings for ActiveSet.
+type Settings struct {
+	// Tolerance is the tolerance on the violation of the inequality
+	// constraints, relative to max(1, |h_i|). If Tolerance is zero, a
+	// default value of 1e-10 is used.
+	Tolerance float64
+
+	// MaxIterations is the maximum number of changes to the active set.
+	// If MaxIterations is zero, a default value of
+	// max(100, 10*(len(C)+len(H))) is used.
+	MaxIterations int
+
+	// Active is an initial guess of the indices of the active inequality
+	// constraints, typically the Active field of the Result of a related
+	// problem. Constraints in Active that are linearly dependent on the
+	// equality constraints and preceding constraints in Active, or whose
+	// multipliers are negative, are removed from the initial active set.
+	Active []int
+}
+
+// Result is the solution of a quadratic program.
+type Result struct {
+	// X is the optimal solution and F is the optimal value of
+	// the objective function.
+	X []float64
+	F float64
+
+	// Lambda and Mu are the Lagrange multipliers of the equality and
+	// the inequality constraints such that
+	//
+	//	Q x + c + Aᵀ λ + Gᵀ μ = 0.
+	//
+	// The multipliers of inactive constraints are zero.
+	Lambda []float64
+	Mu     []float64
+
+	// Active holds the indices of the active inequality constraints
+	// in increasing order.
+	Active []int
+
+	// Iterations is the number of changes to the active set.
+	Iterations int
+}
+
+// ActiveSet solves a strictly convex quadratic program using the dual
+// active set method of Goldfarb and Idnani. The method starts from the
+// unconstrained minimum of the objective, or from the minimum subject to
+// the constraints in settings.Active, and adds violated constraints to the
+// active set one at a time while keeping the Lagrange multipliers of the
+// active constraints non-negative. The linear systems are solved using the
+// Cholesky factorizations of Q and of the active constraints projected
+// onto Q⁻¹.
+//
+// A good guess of the active set, such as the active set of the solution
+// of a problem with slightly different data, substantially reduces the
+// number of iterations. This is useful when a sequence of similar problems
+// is solved as in model predictive control.
+//
+// ActiveSet returns ErrNotPositiveDefinite if Q is not positive definite,
+// ErrSingular if the rows of A are linearly dependent, and ErrInfeasible if
+// the constraints cannot be satisfied. If the iteration limit is reached,
+// ErrIterationLimit is returned along with the current, possibly
+// infeasible, point.
+//
+// ActiveSet panics if the dimensions of the problem do not match, or if an
+// element of settings.Active is out of range. If settings is nil, default
+// settings are used.
+//
+// References:
+//   - Goldfarb, D., Idnani, A. (1983). A numerically stable dual method for
+//     solving strictly convex quadratic programs. Mathematical Programming
+//     27, 1-33.
+//   - Nocedal, J., Wright, S.J. (2006). Numerical Optimization, 2nd ed.
+//     Springer, chapter 16.
+func ActiveSet(p *Problem, settings *Settings) (*Result, error) {
+	n := len(p.C)
+	if p.Q.SymmetricDim() != n {
+		panic(badShape)
+	}
+	var nEq, nIneq int
+	if p.A != nil {
+		r, c := p.A.Dims()
+		if c != n {
+			panic(badShape)
+		}
+		nEq = r
+	}
+	if len(p.B) != nEq {
+		panic(badShape)
+	}
+	if p.G != nil {
+		r, c := p.G.Dims()
+		if c != n {
+			panic(badShape)
+		}
+		nIneq = r
+	}
+	if len(p.H) != nIneq {
+		panic(badShape)
+	}
+
+	var s Settings
+	if settings != nil {
+		s = *settings
+	}
+	if s.Tolerance == 0 {
+		s.Tolerance = defaultTol
+	}
+	if s.MaxIterations == 0 {
+		s.MaxIterations = 10 * (n + nIneq)
+		if s.MaxIterations < 100 {
+			s.MaxIterations = 100
+		}
+	}
+	for _, i := range s.Active {
+		if i < 0 || nIneq <= i {
+			panic("qp: active constraint index out of range")
+		}
+	}
+
+	a := &activeSet{
+		n:    n,
+		c:    p.C,
+		nEq:  nEq,
+		rows: make([][]float64, nEq+nIneq),
+		rhs:  make([]float64, nEq+nIneq),
+		qinv: make([][]float64, nEq+nIneq),
+	}
+	if !a.chol.Factorize(p.Q) {
+		return nil, ErrNotPositiveDefinite
+	}
+	for i := 0; i < nEq; i++ {
+		a.rows[i] = mat.Row(nil, i, p.A)
+		a.rhs[i] = p.B[i]
+	}
+	for i := 0; i < nIneq; i++ {
+		a.rows[nEq+i] = mat.Row(nil, i, p.G)
+		a.rhs[nEq+i] = p.H[i]
+	}
+
+	// Initialize with the equality constraints and the independent
+	// constraints of the guessed active set.
+	for i := 0; i < nEq; i++ {
+		a.work = append(a.work, i)
+	}
+	if !a.factorize() {
+		return nil, ErrSingular
+	}
+	inWork := make([]bool, nEq+nIneq)
+	for _, i := range s.Active {
+		k := nEq + i
+		if inWork[k] {
+			continue
+		}
+		a.work = append(a.work, k)
+		if !a.factorize() {
+			a.work = a.work[:len(a.work)-1]
+			continue
+		}
+		inWork[k] = true
+	}
+	// Refactorize in case the last guessed constraint was rejected.
+	a.factorize()
+
+	// Remove guessed constraints with negative multipliers until the
+	// current point is dual feasible.
+	var iter int
+	for {
+		a.solve()
+		k := -1
+		min := 0.0
+		for l := nEq; l < len(a.work); l++ {
+			if a.mult[l] < min {
+				k = l
+				min = a.mult[l]
+			}
+		}
+		if k < 0 {
+			break
+		}
+		inWork[a.work[k]] = false
+		a.remove(k)
+		iter++
+	}
+
+	var err error
+	z := make([]float64, n)
+	var r []float64
+outer:
+	for {
+		// Find the most violated inequality constraint.
+		add := -1
+		var worst float64
+		for i := nEq; i < len(a.rows); i++ {
+			if inWork[i] {
+				continue
+			}
+			v := a.violation(i)
+			if v > s.Tolerance*math.Max(1, math.Abs(a.rhs[i])) && v > worst {
+				add = i
+				worst = v
+			}
+		}
+		if add < 0 {
+			break
+		}
+
+		// Increase the multiplier of constraint add until either the
+		// constraint holds or the multiplier of an active inequality
+		// constraint becomes zero.
+		var muAdd float64
+		for {
+			if iter >= s.MaxIterations {
+				err = ErrIterationLimit
+				break outer
+			}
+			iter++
+			r = a.direction(z, r, add)
+			u := a.qInv(add)
+			gz := floats.Dot(a.rows[add], z)
+			gu := floats.Dot(a.rows[add], u)
+
+			// Full step that satisfies constraint add.
+			t1 := math.Inf(1)
+			if gz > depTol*gu {
+				t1 = a.violation(add) / gz
+			}
+			// Partial step limited by the multipliers.
+			t2 := math.Inf(1)
+			k := -1
+			for l := nEq; l < len(a.work); l++ {
+				if r[l] > 0 {
+					t := a.mult[l] / r[l]
+					if t < t2 {
+						t2 = t
+						k = l
+					}
+				}
+			}
+			t := math.Min(t1, t2)
+			if math.IsInf(t, 1) {
+				return nil, ErrInfeasible
+			}
+
+			if !math.IsInf(t1, 1) {
+				floats.AddScaled(a.x, -t, z)
+			}
+			floats.AddScaled(a.mult, -t, r)
+			muAdd += t
+			if t1 <= t2 {
+				a.work = append(a.work, add)
+				a.mult = append(a.mult, muAdd)
+				inWork[add] = true
+				if !a.factorize() {
+					// The step guarantees that the normal of add is
+					// independent of the working set, so this can
+					// only happen through loss of precision.
+					a.work = a.work[:len(a.work)-1]
+					a.mult = a.mult[:len(a.mult)-1]
+					inWork[add] = false
+					err = ErrIterationLimit
+					break outer
+				}
+				break
+			}
+			a.mult[k] = 0
+			inWork[a.work[k]] = false
+			a.remove(k)
+		}
+	}
+	if err == nil {
+		// Remove accumulated rounding errors by solving for the final
+		// working set directly.
+		a.solve()
+	}
+
+	res := &Result{
+		X:          a.x,
+		Lambda:     make([]float64, nEq),
+		Mu:         make([]float64, nIneq),
+		Iterations: iter,
+	}
+	for l, i := range a.work {
+		if i < nEq {
+			res.Lambda[i] = a.mult[l]
+			continue
+		}
+		res.Mu[i-nEq] = a.mult[l]
+		res.Active = append(res.Active, i-nEq)
+	}
+	sort.Ints(res.Active)
+	qx := mat.NewVecDense(n, nil)
+	qx.MulVec(p.Q, mat.NewVecDense(n, a.x))
+	res.F = 0.5*floats.Dot(a.x, qx.RawVector().Data) + floats.Dot(p.C, a.x)
+	return res, err
+}
+
+// activeSet holds the state of the dual active set method. The equality
+// and inequality constraints are stored together as rows, with the
+// equality constraints first.
+type activeSet struct {
+	n    int
+	c    []float64
+	chol mat.Cholesky
+
+	nEq  int
+	rows [][]float64
+	rhs  []float64
+	qinv [][]float64 // Q⁻¹ rows[i], computed on demand.
+
+	// work holds the indices of the constraints in the working set and
+	// mult their multipliers. The equality constraints are always the
+	// first nEq elements.
+	work []int
+	mult []float64
+	// s is the factorization of N Q⁻¹ Nᵀ where N holds the normals of
+	// the working set.
+	s mat.Cholesky
+
+	x []float64
+}
+
+// qInv returns Q⁻¹ rows[i].
+func (a *activeSet) qInv(i int) []float64 {
+	if a.qinv[i] == nil {
+		u := mat.NewVecDense(a.n, nil)
+		_ = a.chol.SolveVecTo(u, mat.NewVecDense(a.n, a.rows[i]))
+		a.qinv[i] = u.RawVector().Data
+	}
+	return a.qinv[i]
+}
+
+// factorize factorizes N Q⁻¹ Nᵀ for the current working set and returns
+// whether the normals of the working set are linearly independent.
+func (a *activeSet) factorize() bool {
+	k := len(a.work)
+	if k == 0 {
+		a.s.Reset()
+		return true
+	}
+	m := mat.NewSymDense(k, nil)
+	for i, wi := range a.work {
+		for j := i; j < k; j++ {
+			m.SetSym(i, j, floats.Dot(a.rows[wi], a.qInv(a.work[j])))
+		}
+	}
+	return a.s.Factorize(m) && a.s.Cond() < maxCond
+}
+
+// remove removes the l-th element of the working set.
+func (a *activeSet) remove(l int) {
+	a.work = append(a.work[:l], a.work[l+1:]...)
+	a.mult = append(a.mult[:l], a.mult[l+1:]...)
+	if !a.factorize() {
+		// A subset of linearly independent vectors is linearly
+		// independent.
+		panic("qp: inconsistent working set")
+	}
+}
+
+// solve sets x and the multipliers to the minimum of the objective with
+// the constraints of the working set holding as equalities.
+func (a *activeSet) solve() {
+	x := mat.NewVecDense(a.n, nil)
+	_ = a.chol.SolveVecTo(x, mat.NewVecDense(a.n, a.c))
+	x.ScaleVec(-1, x)
+	a.x = x.RawVector().Data
+	k := len(a.work)
+	a.mult = a.mult[:0]
+	if k == 0 {
+		return
+	}
+	d := make([]float64, k)
+	for l, i := range a.work {
+		d[l] = floats.Dot(a.rows[i], a.x) - a.rhs[i]
+	}
+	lambda := mat.NewVecDense(k, nil)
+	_ = a.s.SolveVecTo(lambda, mat.NewVecDense(k, d))
+	a.mult = append(a.mult, lambda.RawVector().Data...)
+	for l, i := range a.work {
+		floats.AddScaled(a.x, -a.mult[l], a.qInv(i))
+	}
+}
+
+// direction computes the change z in x and r in the multipliers of the
+// working set per unit increase of the multiplier of constraint p, such
+// that x decreases by z and the multipliers decrease by r. The result r
+// is stored in dst if it is large enough.
+func (a *activeSet) direction(z, dst []float64, p int) []float64 {
+	u := a.qInv(p)
+	copy(z, u)
+	k := len(a.work)
+	if cap(dst) < k {
+		dst = make([]float64, k)
+	}
+	dst = dst[:k]
+	if k == 0 {
+		return dst
+	}
+	for l, i := range a.work {
+		dst[l] = floats.Dot(a.rows[i], u)
+	}
+	r := mat.NewVecDense(k, dst)
+	_ = a.s.SolveVecTo(r, r)
+	for l, i := range a.work {
+		floats.AddScaled(z, -dst[l], a.qInv(i))
+	}
+	return dst
+}
+
+// violation returns the violation of the inequality constraint i at x.
+func (a *activeSet) violation(i int) float64 {
+	return floats.Dot(a.rows[i], a.x) - a.rhs[i]
+}
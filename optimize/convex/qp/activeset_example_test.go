@@ -0,0 +1,63 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package qp_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/convex/qp"
+)
+
+func ExampleActiveSet() {
+	// Find the portfolio of three assets with the smallest variance
+	// that has an expected return of at least 8%, without short selling.
+	cov := mat.NewSymDense(3, []float64{
+		0.04, 0.006, 0.002,
+		0.006, 0.09, 0.009,
+		0.002, 0.009, 0.01,
+	})
+	p := &qp.Problem{
+		Q: cov,
+		C: make([]float64, 3),
+		// The weights sum to one.
+		A: mat.NewDense(1, 3, []float64{1, 1, 1}),
+		B: []float64{1},
+		G: mat.NewDense(4, 3, []float64{
+			// The expected return is at least 8%.
+			-0.10, -0.15, -0.05,
+			// The weights are non-negative.
+			-1, 0, 0,
+			0, -1, 0,
+			0, 0, -1,
+		}),
+		H: []float64{-0.08, 0, 0, 0},
+	}
+	res, err := qp.ActiveSet(p, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("weights: %.3f\n", res.X)
+	fmt.Printf("variance: %.4f\n", 2*res.F)
+	fmt.Printf("active constraints: %v\n", res.Active)
+
+	// Re-solve for a higher return starting from the previous active set.
+	p.H[0] = -0.09
+	res, err = qp.ActiveSet(p, &qp.Settings{Active: res.Active})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("weights: %.3f\n", res.X)
+	fmt.Printf("variance: %.4f\n", 2*res.F)
+	fmt.Printf("iterations: %d\n", res.Iterations)
+	// Output:
+	// weights: [0.298 0.151 0.551]
+	// variance: 0.0113
+	// active constraints: [0]
+	// weights: [0.356 0.222 0.422]
+	// variance: 0.0145
+	// iterations: 0
+}
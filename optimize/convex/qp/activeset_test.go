@@ -0,0 +1,292 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package qp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestActiveSet(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		p    Problem
+		x    []float64
+		f    float64
+	}{
+		{
+			name: "unconstrained",
+			p: Problem{
+				Q: mat.NewSymDense(2, []float64{2, 0, 0, 4}),
+				C: []float64{-2, -8},
+			},
+			x: []float64{1, 2},
+			f: -9,
+		},
+		{
+			// Example 16.2 of Nocedal and Wright.
+			name: "equality",
+			p: Problem{
+				Q: mat.NewSymDense(3, []float64{
+					6, 2, 1,
+					2, 5, 2,
+					1, 2, 4,
+				}),
+				C: []float64{-8, -3, -3},
+				A: mat.NewDense(2, 3, []float64{
+					1, 0, 1,
+					0, 1, 1,
+				}),
+				B: []float64{3, 0},
+			},
+			x: []float64{2, -1, 1},
+			f: -3.5,
+		},
+		{
+			// Example 16.4 of Nocedal and Wright.
+			name: "inequality",
+			p: Problem{
+				Q: mat.NewSymDense(2, []float64{2, 0, 0, 2}),
+				C: []float64{-2, -5},
+				G: mat.NewDense(5, 2, []float64{
+					-1, 2,
+					1, 2,
+					1, -2,
+					-1, 0,
+					0, -1,
+				}),
+				H: []float64{2, 6, 2, 0, 0},
+			},
+			x: []float64{1.4, 1.7},
+			f: -6.45,
+		},
+		{
+			name: "mixed",
+			p: Problem{
+				Q: mat.NewSymDense(2, []float64{1, 0, 0, 1}),
+				C: []float64{0, 0},
+				A: mat.NewDense(1, 2, []float64{1, 1}),
+				B: []float64{2},
+				G: mat.NewDense(2, 2, []float64{
+					-1, 0,
+					1, 0,
+				}),
+				H: []float64{-1.5, 10},
+			},
+			x: []float64{1.5, 0.5},
+			f: 1.25,
+		},
+	} {
+		res, err := ActiveSet(&test.p, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !floats.EqualApprox(res.X, test.x, 1e-10) {
+			t.Errorf("%s: unexpected solution: got %v, want %v", test.name, res.X, test.x)
+		}
+		if !scalar.EqualWithinAbsOrRel(res.F, test.f, 1e-10, 1e-10) {
+			t.Errorf("%s: unexpected optimum: got %v, want %v", test.name, res.F, test.f)
+		}
+		checkKKT(t, test.name, &test.p, res, 1e-9)
+	}
+}
+
+func TestActiveSetErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		p    Problem
+		err  error
+	}{
+		{
+			name: "not positive definite",
+			p: Problem{
+				Q: mat.NewSymDense(2, []float64{1, 0, 0, 0}),
+				C: []float64{1, 1},
+			},
+			err: ErrNotPositiveDefinite,
+		},
+		{
+			name: "dependent equalities",
+			p: Problem{
+				Q: mat.NewSymDense(2, []float64{1, 0, 0, 1}),
+				C: []float64{1, 1},
+				A: mat.NewDense(2, 2, []float64{1, 1, 2, 2}),
+				B: []float64{1, 2},
+			},
+			err: ErrSingular,
+		},
+		{
+			name: "infeasible inequalities",
+			p: Problem{
+				Q: mat.NewSymDense(2, []float64{1, 0, 0, 1}),
+				C: []float64{1, 1},
+				G: mat.NewDense(2, 2, []float64{
+					1, 1,
+					-1, -1,
+				}),
+				H: []float64{1, -2},
+			},
+			err: ErrInfeasible,
+		},
+		{
+			name: "infeasible mixed",
+			p: Problem{
+				Q: mat.NewSymDense(2, []float64{1, 0, 0, 1}),
+				C: []float64{1, 1},
+				A: mat.NewDense(1, 2, []float64{1, 0}),
+				B: []float64{3},
+				G: mat.NewDense(2, 2, []float64{
+					1, 1,
+					0, -1,
+				}),
+				H: []float64{1, -1},
+			},
+			err: ErrInfeasible,
+		},
+	} {
+		_, err := ActiveSet(&test.p, nil)
+		if err != test.err {
+			t.Errorf("%s: unexpected error: got %v, want %v", test.name, err, test.err)
+		}
+	}
+}
+
+func TestActiveSetRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		n := rnd.Intn(20) + 1
+		nEq := rnd.Intn(n)
+		nIneq := rnd.Intn(3 * n)
+		p := randomProblem(rnd, n, nEq, nIneq)
+		res, err := ActiveSet(p, nil)
+		if err != nil {
+			t.Errorf("case %d (n=%d, nEq=%d, nIneq=%d): unexpected error: %v", i, n, nEq, nIneq, err)
+			continue
+		}
+		checkKKT(t, "random", p, res, 1e-8)
+
+		// Solving a perturbed problem starting from the active set of
+		// the original problem must give the same result as solving
+		// it from scratch.
+		for j := range p.C {
+			p.C[j] += 0.01 * rnd.NormFloat64()
+		}
+		cold, err := ActiveSet(p, nil)
+		if err != nil {
+			t.Errorf("case %d: unexpected error for perturbed problem: %v", i, err)
+			continue
+		}
+		warm, err := ActiveSet(p, &Settings{Active: res.Active})
+		if err != nil {
+			t.Errorf("case %d: unexpected error with warm start: %v", i, err)
+			continue
+		}
+		checkKKT(t, "warm", p, warm, 1e-8)
+		if !floats.EqualApprox(warm.X, cold.X, 1e-7) {
+			t.Errorf("case %d: warm start solution mismatch: got %v, want %v", i, warm.X, cold.X)
+		}
+		if warm.Iterations > cold.Iterations {
+			t.Errorf("case %d: warm start slower than cold start: %d > %d iterations", i, warm.Iterations, cold.Iterations)
+		}
+	}
+}
+
+// randomProblem returns a random feasible quadratic program.
+func randomProblem(rnd *rand.Rand, n, nEq, nIneq int) *Problem {
+	l := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			l.Set(i, j, rnd.NormFloat64())
+		}
+	}
+	q := mat.NewSymDense(n, nil)
+	q.SymOuterK(1, l)
+	for i := 0; i < n; i++ {
+		q.SetSym(i, i, q.At(i, i)+0.1)
+	}
+	c := make([]float64, n)
+	for i := range c {
+		c[i] = 10 * rnd.NormFloat64()
+	}
+	// Constraints are constructed to hold at a random point x0.
+	x0 := make([]float64, n)
+	for i := range x0 {
+		x0[i] = rnd.NormFloat64()
+	}
+	p := &Problem{Q: q, C: c}
+	if nEq > 0 {
+		a := mat.NewDense(nEq, n, nil)
+		b := make([]float64, nEq)
+		for i := 0; i < nEq; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+			b[i] = floats.Dot(a.RawRowView(i), x0)
+		}
+		p.A, p.B = a, b
+	}
+	if nIneq > 0 {
+		g := mat.NewDense(nIneq, n, nil)
+		h := make([]float64, nIneq)
+		for i := 0; i < nIneq; i++ {
+			for j := 0; j < n; j++ {
+				g.Set(i, j, rnd.NormFloat64())
+			}
+			h[i] = floats.Dot(g.RawRowView(i), x0) + rnd.Float64()
+		}
+		p.G, p.H = g, h
+	}
+	return p
+}
+
+// checkKKT checks that res satisfies the optimality conditions of p.
+func checkKKT(t *testing.T, name string, p *Problem, res *Result, tol float64) {
+	t.Helper()
+	n := len(p.C)
+	x := mat.NewVecDense(n, res.X)
+	grad := mat.NewVecDense(n, nil)
+	grad.MulVec(p.Q, x)
+	grad.AddVec(grad, mat.NewVecDense(n, p.C))
+	scale := 1 + floats.Norm(p.C, math.Inf(1))
+	if p.A != nil {
+		var ax, atl mat.VecDense
+		ax.MulVec(p.A, x)
+		if !floats.EqualApprox(ax.RawVector().Data, p.B, tol) {
+			t.Errorf("%s: equality constraints violated", name)
+		}
+		atl.MulVec(p.A.T(), mat.NewVecDense(len(p.B), res.Lambda))
+		grad.AddVec(grad, &atl)
+	}
+	if p.G != nil {
+		var gx, gtm mat.VecDense
+		gx.MulVec(p.G, x)
+		for i, v := range gx.RawVector().Data {
+			if v > p.H[i]+tol*math.Max(1, math.Abs(p.H[i])) {
+				t.Errorf("%s: inequality constraint %d violated by %v", name, i, v-p.H[i])
+			}
+			if res.Mu[i] < -tol*scale {
+				t.Errorf("%s: negative multiplier %d: %v", name, i, res.Mu[i])
+			}
+			if math.Abs(res.Mu[i]*(v-p.H[i])) > tol*scale*math.Max(1, math.Abs(p.H[i])) {
+				t.Errorf("%s: complementarity violated for constraint %d", name, i)
+			}
+		}
+		gtm.MulVec(p.G.T(), mat.NewVecDense(len(p.H), res.Mu))
+		grad.AddVec(grad, &gtm)
+	}
+	if mat.Norm(grad, math.Inf(1)) > tol*scale {
+		t.Errorf("%s: stationarity violated: %v", name, mat.Formatted(grad.T()))
+	}
+}
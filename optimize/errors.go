@@ -39,6 +39,10 @@ var (
 	// ErrMissingHess signifies that a Method requires a Hessian function that
 	// is not supplied by Problem.
 	ErrMissingHess = errors.New("optimize: problem does not provide needed Hess function")
+
+	// ErrUnsupportedConstraints signifies that a Method does not support the
+	// bounds or constraints specified by Problem.
+	ErrUnsupportedConstraints = errors.New("optimize: method does not support problem bounds or constraints")
 )
 
 // ErrFunc is returned when an initial function value is invalid. The error
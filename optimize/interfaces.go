@@ -4,6 +4,13 @@
 
 package optimize
 
+// constrainer is a Method that handles the bounds or constraints of a Problem.
+type constrainer interface {
+	// setConstraints is called by Minimize before Run with the Problem
+	// being optimized.
+	setConstraints(p *Problem)
+}
+
 // A localMethod can optimize an objective function.
 //
 // It uses a reverse-communication interface between the optimization method
@@ -0,0 +1,628 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Method          = (*LBFGSB)(nil)
+	_ localMethod     = (*LBFGSB)(nil)
+	_ NextDirectioner = (*LBFGSB)(nil)
+)
+
+const (
+	// lbfgsbEps is the relative tolerance used by LBFGSB to reject
+	// curvature pairs and to safeguard the second derivative along
+	// the Cauchy path.
+	lbfgsbEps = 2.220446049250313e-16
+
+	// lbfgsbStallFactor is the factor of the gradient threshold below
+	// which the projected gradient norm must be for a linesearch
+	// failure to be reported as convergence.
+	lbfgsbStallFactor = 1e4
+)
+
+// LBFGSB implements the limited-memory BFGS method for gradient-based
+// minimization subject to bounds on the variables, L-BFGS-B.
+//
+// Like LBFGS, it stores a limited-memory approximation of the Hessian from
+// the last Store iterations. At each iteration LBFGSB finds the generalized
+// Cauchy point, the first local minimizer of the quadratic model of the
+// objective function along the projected steepest descent path, and then
+// minimizes the model over the variables that are not at their bounds. The
+// search direction points from the current location to the resulting point,
+// which lies within the bounds.
+//
+// LBFGSB terminates with GradientThreshold status when the infinity norm
+// of the projected gradient, P(x - ∇f(x)) - x where P is the projection
+// onto the bounds, is less than GradStopThreshold. Since the threshold may
+// be smaller than the projected gradient that can be resolved in floating
+// point arithmetic, LBFGSB terminates with MethodConverge status when the
+// linesearch cannot make progress from a location at which the projected
+// gradient norm is less than 1e4 times GradStopThreshold. Other linesearch
+// failures are returned as errors.
+//
+// The bounds are given by the Bounds field of Problem. If the Problem does
+// not have bounds, LBFGSB is an unconstrained limited-memory BFGS method.
+//
+// References:
+//   - Byrd, R.H., Lu, P., Nocedal, J., Zhu, C. (1995). A limited memory
+//     algorithm for bound constrained optimization. SIAM Journal on
+//     Scientific Computing 16(5), 1190-1208.
+type LBFGSB struct {
+	// Linesearcher selects suitable steps along the search direction.
+	// Steps longer than the initial step may leave the bounds, in which
+	// case the location is projected onto the bounds. If Linesearcher is
+	// nil, a MoreThuente linesearch limited to the largest step within
+	// the bounds is used.
+	Linesearcher Linesearcher
+	// Store is the size of the limited-memory storage.
+	// If Store is 0, it will be defaulted to 15.
+	Store int
+	// GradStopThreshold sets the threshold for stopping if the projected
+	// gradient norm gets too small. If GradStopThreshold is 0 it is
+	// defaulted to 1e-12, and if it is NaN the setting is not used.
+	GradStopThreshold float64
+
+	status Status
+	err    error
+
+	ls     *LinesearchMethod
+	mt     *MoreThuente // Default Linesearcher
+	lastOp Operation    // Operation returned from the previous call to iterateLocal.
+
+	bounds []Bound   // Bounds of the Problem, nil if unbounded.
+	lower  []float64 // Lower bounds of the variables.
+	upper  []float64 // Upper bounds of the variables.
+
+	dim  int       // Dimension of the problem
+	x    []float64 // Location at the last major iteration
+	grad []float64 // Gradient at the last major iteration
+
+	// History, ordered from the oldest to the most recent pair.
+	s, y  [][]float64
+	theta float64 // Scaling of the initial Hessian approximation
+
+	// Workspace
+	sNew, yNew []float64
+	breaks     []int
+	free       []int
+	t, d       []float64
+}
+
+func (l *LBFGSB) Status() (Status, error) {
+	return l.status, l.err
+}
+
+func (*LBFGSB) Uses(has Available) (uses Available, err error) {
+	return has.boundedGradient()
+}
+
+func (l *LBFGSB) setConstraints(p *Problem) {
+	l.bounds = p.Bounds
+}
+
+func (l *LBFGSB) Init(dim, tasks int) int {
+	l.status = NotTerminated
+	l.err = nil
+	return 1
+}
+
+func (l *LBFGSB) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	// The gradient check of localOptimizer is disabled, LBFGSB checks
+	// the projected gradient and reports convergence with MethodDone.
+	status, err := localOptimizer{}.run(l, math.NaN(), operation, result, tasks)
+	if l.status == NotTerminated {
+		l.status, l.err = status, err
+	}
+	close(operation)
+}
+
+func (l *LBFGSB) initLocal(loc *Location) (Operation, error) {
+	if l.Store == 0 {
+		l.Store = 15
+	}
+
+	dim := len(loc.X)
+	l.lower = resize(l.lower, dim)
+	l.upper = resize(l.upper, dim)
+	for i := range l.lower {
+		if l.bounds == nil {
+			l.lower[i] = math.Inf(-1)
+			l.upper[i] = math.Inf(1)
+		} else {
+			l.lower[i] = l.bounds[i].Min
+			l.upper[i] = l.bounds[i].Max
+		}
+	}
+
+	if l.ls == nil {
+		l.ls = &LinesearchMethod{}
+	}
+	l.ls.Linesearcher = l.Linesearcher
+	if l.Linesearcher == nil {
+		if l.mt == nil {
+			l.mt = &MoreThuente{DecreaseFactor: 1e-3}
+		}
+		l.ls.Linesearcher = l.mt
+	}
+	l.ls.NextDirectioner = l
+
+	if l.converged(loc) {
+		l.lastOp = MethodDone
+		return l.lastOp, nil
+	}
+	op, err := l.ls.Init(loc)
+	l.project(op, loc.X)
+	l.lastOp = op
+	return op, err
+}
+
+func (l *LBFGSB) iterateLocal(loc *Location) (Operation, error) {
+	if l.lastOp == MajorIteration && l.converged(loc) {
+		l.lastOp = MethodDone
+		return l.lastOp, nil
+	}
+	op, err := l.ls.Iterate(loc)
+	if err == ErrLinesearcherBound && complementEval(loc, l.ls.eval) == NoOperation {
+		// The Linesearcher reached the largest step within the bounds
+		// with a sufficient decrease of the function, and loc is complete.
+		// Accept the step and continue with the next linesearch.
+		l.ls.lastOp = MajorIteration
+		op, err = MajorIteration, nil
+	}
+	if err != nil && l.stalled() {
+		l.status = MethodConverge
+		l.lastOp = MethodDone
+		return l.lastOp, nil
+	}
+	l.project(op, loc.X)
+	l.lastOp = op
+	return op, err
+}
+
+// project guards against rounding errors moving the location x of an
+// evaluation outside the bounds.
+func (l *LBFGSB) project(op Operation, x []float64) {
+	if !op.isEvaluation() {
+		return
+	}
+	for i, v := range x {
+		x[i] = math.Max(l.lower[i], math.Min(v, l.upper[i]))
+	}
+}
+
+// converged returns whether the infinity norm of the projected gradient at
+// loc is below the threshold, and sets the status if it is.
+func (l *LBFGSB) converged(loc *Location) bool {
+	thresh := l.gradThreshold()
+	if math.IsNaN(thresh) {
+		return false
+	}
+	if l.projectedGradNorm(loc.X, loc.Gradient) < thresh {
+		l.status = GradientThreshold
+		return true
+	}
+	return false
+}
+
+// stalled returns whether the projected gradient norm at the location of
+// the last major iteration is close enough to the threshold for a
+// linesearch failure from that location to be convergence.
+func (l *LBFGSB) stalled() bool {
+	thresh := l.gradThreshold()
+	if math.IsNaN(thresh) || l.x == nil {
+		return false
+	}
+	return l.projectedGradNorm(l.x, l.grad) < lbfgsbStallFactor*thresh
+}
+
+// gradThreshold returns the projected gradient threshold, which is NaN if
+// the threshold is not used.
+func (l *LBFGSB) gradThreshold() float64 {
+	if l.GradStopThreshold == 0 {
+		return defaultGradientAbsTol
+	}
+	return l.GradStopThreshold
+}
+
+// projectedGradNorm returns the infinity norm of the projected gradient at
+// x with the gradient grad.
+func (l *LBFGSB) projectedGradNorm(x, grad []float64) float64 {
+	var norm float64
+	for i, g := range grad {
+		p := math.Max(l.lower[i], math.Min(x[i]-g, l.upper[i]))
+		norm = math.Max(norm, math.Abs(p-x[i]))
+	}
+	return norm
+}
+
+func (l *LBFGSB) InitDirection(loc *Location, dir []float64) (stepSize float64) {
+	dim := len(loc.X)
+	l.dim = dim
+
+	l.x = resize(l.x, dim)
+	copy(l.x, loc.X)
+	l.grad = resize(l.grad, dim)
+	copy(l.grad, loc.Gradient)
+
+	l.s = l.s[:0]
+	l.y = l.y[:0]
+	l.theta = 1
+
+	l.direction(dir)
+	return math.Min(1, 1/floats.Norm(dir, 2))
+}
+
+// setMaximumStep limits the steps of the default Linesearcher along dir
+// to the bounds.
+func (l *LBFGSB) setMaximumStep(dir []float64) {
+	if l.Linesearcher != nil {
+		return
+	}
+	maxStep := math.Inf(1)
+	for i, d := range dir {
+		switch {
+		case d > 0:
+			maxStep = math.Min(maxStep, (l.upper[i]-l.x[i])/d)
+		case d < 0:
+			maxStep = math.Min(maxStep, (l.lower[i]-l.x[i])/d)
+		}
+	}
+	if math.IsInf(maxStep, 1) {
+		// Use the default of MoreThuente.
+		maxStep = 0
+	}
+	l.mt.MaximumStep = maxStep
+}
+
+func (l *LBFGSB) NextDirection(loc *Location, dir []float64) (stepSize float64) {
+	if len(loc.X) != l.dim {
+		panic("lbfgsb: unexpected size mismatch")
+	}
+	if len(loc.Gradient) != l.dim {
+		panic("lbfgsb: unexpected size mismatch")
+	}
+	if len(dir) != l.dim {
+		panic("lbfgsb: unexpected size mismatch")
+	}
+
+	l.sNew = resize(l.sNew, l.dim)
+	l.yNew = resize(l.yNew, l.dim)
+	floats.SubTo(l.sNew, loc.X, l.x)
+	floats.SubTo(l.yNew, loc.Gradient, l.grad)
+	sDotY := floats.Dot(l.sNew, l.yNew)
+	yDotY := floats.Dot(l.yNew, l.yNew)
+	// Only keep pairs that maintain a positive definite approximation.
+	if sDotY > lbfgsbEps*yDotY {
+		var s, y []float64
+		if len(l.s) == l.Store {
+			s, y = l.s[0], l.y[0]
+			l.s = append(l.s[:0], l.s[1:]...)
+			l.y = append(l.y[:0], l.y[1:]...)
+		} else {
+			s = make([]float64, l.dim)
+			y = make([]float64, l.dim)
+		}
+		copy(s, l.sNew)
+		copy(y, l.yNew)
+		l.s = append(l.s, s)
+		l.y = append(l.y, y)
+		l.theta = yDotY / sDotY
+	}
+
+	copy(l.x, loc.X)
+	copy(l.grad, loc.Gradient)
+
+	l.direction(dir)
+	return 1
+}
+
+// direction stores in dir the step from the location of the last major
+// iteration to the minimizer of the quadratic model found by the subspace
+// minimization. If the model does not provide a descent direction, the
+// limited-memory storage is discarded.
+func (l *LBFGSB) direction(dir []float64) {
+	if !l.modelStep(dir) {
+		l.s = l.s[:0]
+		l.y = l.y[:0]
+		l.theta = 1
+		l.modelStep(dir)
+	}
+	l.setMaximumStep(dir)
+}
+
+// middle represents the middle matrix of the compact representation of
+// the Hessian approximation,
+//
+//	M = [-D  Lᵀ ]⁻¹
+//	    [ L θSᵀS]
+//
+// where D is the diagonal and L the strictly lower triangle of SᵀY.
+// Products with M are computed by block elimination using the Cholesky
+// factorization of θSᵀS + L D⁻¹ Lᵀ.
+type middle struct {
+	k    int
+	d    []float64
+	l    *mat.Dense
+	chol mat.Cholesky
+	rhs  *mat.VecDense
+	b    *mat.VecDense
+}
+
+// factorize factorizes the middle matrix for the pairs in s and y, and
+// returns whether the factorization was successful.
+func (m *middle) factorize(s, y [][]float64, theta float64) bool {
+	k := len(s)
+	m.k = k
+	m.d = make([]float64, k)
+	m.l = mat.NewDense(k, k, nil)
+	for i := 0; i < k; i++ {
+		m.d[i] = floats.Dot(s[i], y[i])
+		for j := 0; j < i; j++ {
+			m.l.Set(i, j, floats.Dot(s[i], y[j]))
+		}
+	}
+	a := mat.NewSymDense(k, nil)
+	for i := 0; i < k; i++ {
+		for j := i; j < k; j++ {
+			v := theta * floats.Dot(s[i], s[j])
+			for p := 0; p < i; p++ {
+				v += m.l.At(i, p) * m.l.At(j, p) / m.d[p]
+			}
+			a.SetSym(i, j, v)
+		}
+	}
+	m.rhs = mat.NewVecDense(k, nil)
+	m.b = mat.NewVecDense(k, nil)
+	return m.chol.Factorize(a)
+}
+
+// mulVec stores M v in dst. dst and v must not overlap.
+func (m *middle) mulVec(dst, v []float64) {
+	k := m.k
+	v1, v2 := v[:k], v[k:]
+	// Solve (θSᵀS + L D⁻¹ Lᵀ) b = v2 + L D⁻¹ v1.
+	for i := 0; i < k; i++ {
+		r := v2[i]
+		for j := 0; j < i; j++ {
+			r += m.l.At(i, j) * v1[j] / m.d[j]
+		}
+		m.rhs.SetVec(i, r)
+	}
+	err := m.chol.SolveVecTo(m.b, m.rhs)
+	if err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			panic(err)
+		}
+	}
+	// a = D⁻¹ (Lᵀ b - v1).
+	for j := 0; j < k; j++ {
+		var r float64
+		for i := j + 1; i < k; i++ {
+			r += m.l.At(i, j) * m.b.AtVec(i)
+		}
+		dst[j] = (r - v1[j]) / m.d[j]
+	}
+	for i := 0; i < k; i++ {
+		dst[k+i] = m.b.AtVec(i)
+	}
+}
+
+// modelStep computes the generalized Cauchy point of the quadratic model
+// and the subspace minimizer over the free variables as described in
+// sections 4 and 5.1 of Byrd et al. It stores the step to the minimizer
+// in dir and returns whether the step is a descent direction.
+func (l *LBFGSB) modelStep(dir []float64) bool {
+	n := l.dim
+	k := len(l.s)
+	x, g := l.x, l.grad
+	lo, up := l.lower, l.upper
+	theta := l.theta
+
+	// Form the compact representation of the Hessian approximation,
+	// B = θI - W M Wᵀ with W = [Y θS].
+	var (
+		w *mat.Dense
+		m middle
+	)
+	if k > 0 {
+		w = mat.NewDense(n, 2*k, nil)
+		for j := 0; j < k; j++ {
+			for i := 0; i < n; i++ {
+				w.Set(i, j, l.y[j][i])
+				w.Set(i, k+j, theta*l.s[j][i])
+			}
+		}
+		if !m.factorize(l.s, l.y, theta) {
+			return false
+		}
+	}
+	tmp := make([]float64, 2*k)
+	// mDot returns aᵀ M b.
+	mDot := func(a, b []float64) float64 {
+		if k == 0 {
+			return 0
+		}
+		m.mulVec(tmp, b)
+		return floats.Dot(a, tmp)
+	}
+
+	// Compute the generalized Cauchy point, xcp, stored in dir.
+	xcp := dir
+	copy(xcp, x)
+	l.t = resize(l.t, n)
+	l.d = resize(l.d, n)
+	t, d := l.t, l.d
+	l.breaks = l.breaks[:0]
+	for i, gi := range g {
+		switch {
+		case gi < 0:
+			t[i] = (x[i] - up[i]) / gi
+		case gi > 0:
+			t[i] = (x[i] - lo[i]) / gi
+		default:
+			t[i] = math.Inf(1)
+		}
+		if t[i] == 0 {
+			d[i] = 0
+			continue
+		}
+		d[i] = -gi
+		if !math.IsInf(t[i], 1) {
+			l.breaks = append(l.breaks, i)
+		}
+	}
+	sort.Slice(l.breaks, func(a, b int) bool { return t[l.breaks[a]] < t[l.breaks[b]] })
+
+	p := make([]float64, 2*k)
+	c := make([]float64, 2*k)
+	if k > 0 {
+		pv := mat.NewVecDense(2*k, p)
+		pv.MulVec(w.T(), mat.NewVecDense(n, d))
+	}
+
+	fp := -floats.Dot(d, d)
+	fpp := -theta*fp - mDot(p, p)
+	fpp0 := fpp
+	var dtMin float64
+	if fp < 0 {
+		if !(fpp > 0) {
+			return false
+		}
+		dtMin = -fp / fpp
+	}
+	var tOld float64
+	for _, b := range l.breaks {
+		dt := t[b] - tOld
+		if dtMin < dt {
+			break
+		}
+		if d[b] > 0 {
+			xcp[b] = up[b]
+		} else {
+			xcp[b] = lo[b]
+		}
+		zb := xcp[b] - x[b]
+		gb := g[b]
+		floats.AddScaled(c, dt, p)
+		fp += dt*fpp + gb*gb + theta*gb*zb
+		fpp -= theta * gb * gb
+		if k > 0 {
+			wb := w.RawRowView(b)
+			fp -= gb * mDot(wb, c)
+			fpp -= 2*gb*mDot(wb, p) + gb*gb*mDot(wb, wb)
+			floats.AddScaled(p, gb, wb)
+		}
+		fpp = math.Max(lbfgsbEps*fpp0, fpp)
+		d[b] = 0
+		dtMin = -fp / fpp
+		tOld = t[b]
+	}
+	dtMin = math.Max(dtMin, 0)
+	tOld += dtMin
+	for i, di := range d {
+		if di != 0 {
+			xcp[i] = x[i] + tOld*di
+		}
+	}
+	floats.AddScaled(c, dtMin, p)
+
+	// Minimize the model over the free variables starting from the
+	// Cauchy point, keeping the result within the bounds.
+	l.free = l.free[:0]
+	for i, v := range xcp {
+		if lo[i] < v && v < up[i] {
+			l.free = append(l.free, i)
+		}
+	}
+	if nf := len(l.free); nf != 0 {
+		var wmc mat.VecDense
+		if k > 0 {
+			m.mulVec(tmp, c)
+			wmc.MulVec(w, mat.NewVecDense(2*k, tmp))
+		}
+		// The reduced gradient of the model at the Cauchy point.
+		r := make([]float64, nf)
+		for j, i := range l.free {
+			r[j] = g[i] + theta*(xcp[i]-x[i])
+			if k > 0 {
+				r[j] -= wmc.AtVec(i)
+			}
+		}
+		du := make([]float64, nf)
+		for j, v := range r {
+			du[j] = -v / theta
+		}
+		if k > 0 {
+			// Use the Sherman-Morrison-Woodbury formula for the inverse
+			// of the reduced Hessian approximation,
+			//  du = -r/θ - 1/θ² Wz N⁻¹ M Wzᵀ r
+			// with N = I - 1/θ M Wzᵀ Wz.
+			wz := mat.NewDense(nf, 2*k, nil)
+			for j, i := range l.free {
+				wz.SetRow(j, w.RawRowView(i))
+			}
+			var wr mat.VecDense
+			wr.MulVec(wz.T(), mat.NewVecDense(nf, r))
+			v := mat.NewVecDense(2*k, nil)
+			m.mulVec(v.RawVector().Data, wr.RawVector().Data)
+			var wtw mat.Dense
+			wtw.Mul(wz.T(), wz)
+			nm := mat.NewDense(2*k, 2*k, nil)
+			col := make([]float64, 2*k)
+			for j := 0; j < 2*k; j++ {
+				mat.Col(col, j, &wtw)
+				m.mulVec(tmp, col)
+				for i, v := range tmp {
+					nm.Set(i, j, -v/theta)
+				}
+				nm.Set(j, j, nm.At(j, j)+1)
+			}
+			var sol mat.VecDense
+			if err := sol.SolveVec(nm, v); err != nil {
+				if _, ok := err.(mat.Condition); !ok {
+					return false
+				}
+			}
+			var wzs mat.VecDense
+			wzs.MulVec(wz, &sol)
+			for j := range du {
+				du[j] -= wzs.AtVec(j) / (theta * theta)
+			}
+		}
+		alpha := 1.0
+		for j, i := range l.free {
+			switch {
+			case du[j] > 0:
+				alpha = math.Min(alpha, (up[i]-xcp[i])/du[j])
+			case du[j] < 0:
+				alpha = math.Min(alpha, (lo[i]-xcp[i])/du[j])
+			}
+		}
+		for j, i := range l.free {
+			xcp[i] += alpha * du[j]
+		}
+	}
+
+	floats.Sub(dir, x)
+	return floats.Dot(dir, g) < 0
+}
+
+func (*LBFGSB) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, false}
+}
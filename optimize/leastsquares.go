@@ -0,0 +1,607 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// LeastSquaresProblem describes a nonlinear least-squares problem,
+//
+//	minimize ½ ‖r(x)‖²
+//
+// where r is a vector of residuals.
+type LeastSquaresProblem struct {
+	// Func evaluates the residuals at x and stores the result in dst which
+	// will have length Residuals. Func must not modify x.
+	Func func(dst, x []float64)
+
+	// Jac evaluates the Jacobian of the residuals at x and stores the result
+	// in-place in dst which will be a Residuals×len(x) matrix. Jac must not
+	// modify x. If Jac is nil, the Jacobian is approximated by finite
+	// differences using fd.Jacobian.
+	Jac func(dst *mat.Dense, x []float64)
+
+	// Residuals is the number of residuals returned by Func.
+	Residuals int
+
+	// Bounds specifies the lower and upper bounds on each element of x.
+	// If Bounds is not nil, its length must match the length of x.
+	Bounds []Bound
+}
+
+// LeastSquaresMethod specifies the algorithm used by LeastSquares.
+type LeastSquaresMethod int
+
+const (
+	// LevenbergMarquardt computes each step by solving the damped
+	// Gauss-Newton equations (JᵀJ + λDᵀD) p = -Jᵀr where D is a diagonal
+	// scaling and the damping λ is updated according to the agreement
+	// between the actual and the predicted reduction of the cost.
+	LevenbergMarquardt LeastSquaresMethod = iota
+	// Dogleg computes each step along the dogleg path from the Cauchy point
+	// to the Gauss-Newton step within a scaled trust region.
+	Dogleg
+)
+
+// LeastSquaresSettings represents the settings of LeastSquares. See the
+// field comments for default values.
+type LeastSquaresSettings struct {
+	// Method is the algorithm used to compute the steps.
+	Method LeastSquaresMethod
+
+	// FunctionTolerance stops the optimization with FunctionConvergence
+	// status when both the actual and the predicted relative reductions of
+	// the cost are less than FunctionTolerance. If it is zero, it is
+	// defaulted to 1e-8.
+	FunctionTolerance float64
+	// StepTolerance stops the optimization with StepConvergence status when
+	// the scaled step length is less than StepTolerance relative to the
+	// scaled length of x. If it is zero, it is defaulted to 1e-8.
+	StepTolerance float64
+	// GradientTolerance stops the optimization with GradientThreshold status
+	// when the infinity norm of the projected gradient of the cost is less
+	// than GradientTolerance. If it is zero, it is defaulted to 1e-8.
+	GradientTolerance float64
+
+	// FuncEvaluations is the maximum number of evaluations of the residuals,
+	// excluding evaluations for finite difference Jacobians. If it is zero,
+	// it is defaulted to 100*(n+1) where n is the length of x.
+	FuncEvaluations int
+
+	// FiniteDifference specifies the settings of the finite difference
+	// approximation of the Jacobian when Jac is nil. If it is nil, the
+	// default settings of fd.Jacobian are used.
+	FiniteDifference *fd.JacobianSettings
+}
+
+// LeastSquaresResult represents the result of LeastSquares.
+type LeastSquaresResult struct {
+	// X is the location of the minimum found.
+	X []float64
+	// Residuals holds the residuals at X.
+	Residuals []float64
+	// Cost is half the sum of squares of the residuals at X.
+	Cost float64
+	// Jacobian is the Jacobian of the residuals at X.
+	Jacobian *mat.Dense
+	// Covariance is the estimated covariance matrix of the parameters,
+	//
+	//	s² (JᵀJ)⁻¹
+	//
+	// with s² = ‖r‖²/(m-n) the estimated variance of the residuals,
+	// computed from the QR factorization of the Jacobian at X. Covariance
+	// is nil if there are no more residuals than parameters or if the
+	// Jacobian is rank deficient. Bounds are not taken into account.
+	Covariance *mat.SymDense
+
+	// Stats holds the statistics of the run. GradEvaluations counts the
+	// evaluations of the Jacobian.
+	Stats
+	Status Status
+}
+
+// LeastSquares minimizes half the sum of squares of the residuals of p,
+// starting from initX, using a trust-region method that exploits the
+// Gauss-Newton structure of the problem. If settings is nil, the zero value
+// is used, see the documentation of LeastSquaresSettings for default values.
+//
+// If p.Bounds is not nil, the initial location is projected onto the bounds
+// and steps are restricted to the bounds. Variables at a bound at which the
+// gradient points out of the feasible region are held fixed when the step is
+// computed. Finite difference approximations of the Jacobian may evaluate
+// p.Func outside of the bounds.
+//
+// LeastSquares returns an error if the residuals at initX are not finite.
+//
+// References:
+//   - Moré, J.J. (1978). The Levenberg-Marquardt algorithm: implementation and
+//     theory. Numerical Analysis, Lecture Notes in Mathematics 630, 105-116.
+//   - Nielsen, H.B. (1999). Damping parameter in Marquardt's method.
+//     Technical report IMM-REP-1999-05, Technical University of Denmark.
+//   - Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006),
+//     chapters 4 and 10.
+func LeastSquares(p LeastSquaresProblem, initX []float64, settings *LeastSquaresSettings) (*LeastSquaresResult, error) {
+	startTime := time.Now()
+	if p.Func == nil {
+		panic("optimize: residual function is undefined")
+	}
+	n := len(initX)
+	if n == 0 {
+		panic("optimize: impossible problem dimension")
+	}
+	m := p.Residuals
+	if m <= 0 {
+		panic("optimize: non-positive number of residuals")
+	}
+	if p.Bounds != nil {
+		if len(p.Bounds) != n {
+			panic("optimize: bounds do not match problem dimension")
+		}
+		for _, b := range p.Bounds {
+			if !(b.Min <= b.Max) {
+				panic("optimize: invalid bounds")
+			}
+		}
+	}
+	if settings == nil {
+		settings = &LeastSquaresSettings{}
+	}
+	s := newLeastSquares(p, initX, settings)
+	status, err := s.run()
+	s.stats.Runtime = time.Since(startTime)
+
+	res := &LeastSquaresResult{
+		X:         s.x,
+		Residuals: s.r,
+		Cost:      s.cost,
+		Stats:     s.stats,
+		Status:    status,
+	}
+	if err != nil {
+		return res, err
+	}
+	if !s.jacValid {
+		s.evalJac()
+	}
+	res.Jacobian = s.jac
+	res.Covariance = covariance(s.jac, s.r)
+	res.Stats.Runtime = time.Since(startTime)
+	return res, nil
+}
+
+// leastSquares holds the state of a LeastSquares run.
+type leastSquares struct {
+	p        LeastSquaresProblem
+	settings LeastSquaresSettings
+	method   LeastSquaresMethod
+	m, n     int
+
+	lower, upper []float64
+
+	x, r     []float64 // Current location and residuals.
+	cost     float64   // Current cost, ½‖r‖².
+	jac      *mat.Dense
+	jacValid bool // Whether jac holds the Jacobian at x.
+	grad     []float64
+	scale    []float64 // Diagonal scaling of the variables.
+
+	xNew, rNew, xc []float64
+	step           []float64
+
+	lambda, nu float64 // Damping parameter and its growth factor.
+	radius     float64 // Trust region radius.
+
+	stats Stats
+}
+
+func newLeastSquares(p LeastSquaresProblem, initX []float64, settings *LeastSquaresSettings) *leastSquares {
+	m, n := p.Residuals, len(initX)
+	s := &leastSquares{
+		p:        p,
+		settings: *settings,
+		method:   settings.Method,
+		m:        m,
+		n:        n,
+		lower:    make([]float64, n),
+		upper:    make([]float64, n),
+		x:        make([]float64, n),
+		r:        make([]float64, m),
+		jac:      mat.NewDense(m, n, nil),
+		grad:     make([]float64, n),
+		scale:    make([]float64, n),
+		xNew:     make([]float64, n),
+		rNew:     make([]float64, m),
+		xc:       make([]float64, n),
+		step:     make([]float64, n),
+	}
+	if s.settings.FunctionTolerance == 0 {
+		s.settings.FunctionTolerance = 1e-8
+	}
+	if s.settings.StepTolerance == 0 {
+		s.settings.StepTolerance = 1e-8
+	}
+	if s.settings.GradientTolerance == 0 {
+		s.settings.GradientTolerance = 1e-8
+	}
+	if s.settings.FuncEvaluations == 0 {
+		s.settings.FuncEvaluations = 100 * (n + 1)
+	}
+	for i := range s.lower {
+		if p.Bounds == nil {
+			s.lower[i] = math.Inf(-1)
+			s.upper[i] = math.Inf(1)
+		} else {
+			s.lower[i] = p.Bounds[i].Min
+			s.upper[i] = p.Bounds[i].Max
+		}
+	}
+	copy(s.x, initX)
+	s.project(s.x)
+	return s
+}
+
+// project projects x onto the bounds.
+func (s *leastSquares) project(x []float64) {
+	for i, v := range x {
+		x[i] = math.Max(s.lower[i], math.Min(v, s.upper[i]))
+	}
+}
+
+// evalFunc evaluates the residuals at x into dst and returns the cost.
+func (s *leastSquares) evalFunc(dst, x []float64) float64 {
+	copy(s.xc, x)
+	s.p.Func(dst, s.xc)
+	s.stats.FuncEvaluations++
+	return 0.5 * floats.Dot(dst, dst)
+}
+
+// evalJac evaluates the Jacobian at the current location.
+func (s *leastSquares) evalJac() {
+	copy(s.xc, s.x)
+	if s.p.Jac != nil {
+		s.p.Jac(s.jac, s.xc)
+	} else {
+		var settings fd.JacobianSettings
+		if s.settings.FiniteDifference != nil {
+			settings = *s.settings.FiniteDifference
+		}
+		settings.OriginValue = s.r
+		fd.Jacobian(s.jac, s.p.Func, s.xc, &settings)
+	}
+	s.stats.GradEvaluations++
+	s.jacValid = true
+}
+
+func (s *leastSquares) run() (Status, error) {
+	s.cost = s.evalFunc(s.r, s.x)
+	if math.IsInf(s.cost, 0) || math.IsNaN(s.cost) {
+		return Failure, ErrFunc(s.cost)
+	}
+	const (
+		acceptRatio  = 1e-4
+		initDamping  = 1e-3
+		radiusFactor = 100
+	)
+	var free []int
+	for {
+		if s.cost == 0 {
+			return FunctionConvergence, nil
+		}
+		s.evalJac()
+		s.stats.MajorIterations++
+		gv := mat.NewVecDense(s.n, s.grad)
+		gv.MulVec(s.jac.T(), mat.NewVecDense(s.m, s.r))
+
+		// Check convergence of the projected gradient and find the
+		// variables that are not held at their bounds.
+		free = free[:0]
+		var pgNorm float64
+		for i, g := range s.grad {
+			x := s.x[i]
+			pgNorm = math.Max(pgNorm, math.Abs(math.Max(s.lower[i], math.Min(x-g, s.upper[i]))-x))
+			if (x <= s.lower[i] && g > 0) || (x >= s.upper[i] && g < 0) {
+				continue
+			}
+			free = append(free, i)
+		}
+		if pgNorm < s.settings.GradientTolerance {
+			return GradientThreshold, nil
+		}
+
+		// Update the scaling from the column norms of the Jacobian.
+		var maxDiag float64
+		for j := range s.scale {
+			norm := mat.Norm(s.jac.ColView(j), 2)
+			s.scale[j] = math.Max(s.scale[j], norm)
+			if s.scale[j] == 0 {
+				s.scale[j] = 1
+			}
+			maxDiag = math.Max(maxDiag, norm*norm)
+		}
+		if s.stats.MajorIterations == 1 {
+			s.lambda = initDamping * maxDiag
+			s.nu = 2
+			s.radius = radiusFactor * s.scaledNorm(s.x)
+			if s.radius == 0 {
+				s.radius = radiusFactor
+			}
+		}
+
+		// Find an acceptable step.
+		for {
+			var ok bool
+			switch s.method {
+			case LevenbergMarquardt:
+				ok = s.levenbergMarquardtStep(free)
+			case Dogleg:
+				ok = s.doglegStep(free)
+			default:
+				panic("optimize: unknown least squares method")
+			}
+			var (
+				stepNorm float64
+				ratio    = -1.0
+				costNew  float64
+				pred     float64
+			)
+			if ok {
+				floats.AddTo(s.xNew, s.x, s.step)
+				s.project(s.xNew)
+				floats.SubTo(s.step, s.xNew, s.x)
+				stepNorm = s.scaledNorm(s.step)
+
+				// The predicted reduction of the linearized model.
+				var jp mat.VecDense
+				jp.MulVec(s.jac, mat.NewVecDense(s.n, s.step))
+				var lin float64
+				for i, v := range s.r {
+					d := v + jp.AtVec(i)
+					lin += d * d
+				}
+				pred = s.cost - 0.5*lin
+
+				if pred > 0 {
+					costNew = s.evalFunc(s.rNew, s.xNew)
+					if !math.IsInf(costNew, 0) && !math.IsNaN(costNew) {
+						ratio = (s.cost - costNew) / pred
+					}
+				}
+			}
+			accepted := ratio > acceptRatio
+			s.updateTrustRegion(ratio, stepNorm)
+			if accepted {
+				actual := s.cost - costNew
+				copy(s.x, s.xNew)
+				copy(s.r, s.rNew)
+				s.cost = costNew
+				s.jacValid = false
+				if actual <= s.settings.FunctionTolerance*(s.cost+actual) && pred <= s.settings.FunctionTolerance*(s.cost+actual) {
+					return FunctionConvergence, nil
+				}
+			}
+			if stepNorm <= s.settings.StepTolerance*(s.scaledNorm(s.x)+s.settings.StepTolerance) {
+				return StepConvergence, nil
+			}
+			if s.stats.FuncEvaluations >= s.settings.FuncEvaluations {
+				return FunctionEvaluationLimit, nil
+			}
+			if accepted {
+				break
+			}
+		}
+	}
+}
+
+// scaledNorm returns the Euclidean norm of D v.
+func (s *leastSquares) scaledNorm(v []float64) float64 {
+	var norm float64
+	for i, x := range v {
+		norm = math.Hypot(norm, s.scale[i]*x)
+	}
+	return norm
+}
+
+// updateTrustRegion updates the damping parameter or the trust region
+// radius based on the ratio of the actual to the predicted reduction.
+func (s *leastSquares) updateTrustRegion(ratio, stepNorm float64) {
+	switch s.method {
+	case LevenbergMarquardt:
+		if ratio > 1e-4 {
+			t := 2*ratio - 1
+			s.lambda *= math.Max(1.0/3, 1-t*t*t)
+			s.nu = 2
+		} else {
+			s.lambda *= s.nu
+			s.nu *= 2
+		}
+		if s.lambda == 0 {
+			s.lambda = math.SmallestNonzeroFloat64
+		}
+	case Dogleg:
+		switch {
+		case ratio < 0.25:
+			s.radius = 0.25 * math.Min(s.radius, stepNorm)
+		case ratio > 0.75:
+			s.radius = math.Max(s.radius, 2*stepNorm)
+		}
+	}
+}
+
+// freeJacobian returns the columns of the Jacobian for the free variables.
+func (s *leastSquares) freeJacobian(free []int, rows int) *mat.Dense {
+	jf := mat.NewDense(rows, len(free), nil)
+	for k, j := range free {
+		for i := 0; i < s.m; i++ {
+			jf.Set(i, k, s.jac.At(i, j))
+		}
+	}
+	return jf
+}
+
+// levenbergMarquardtStep computes the damped Gauss-Newton step for the free
+// variables by solving the least-squares problem
+//
+//	minimize ‖[J; √λ D] p + [r; 0]‖
+//
+// with a QR factorization, and stores it in s.step. It returns whether the
+// step could be computed.
+func (s *leastSquares) levenbergMarquardtStep(free []int) bool {
+	nf := len(free)
+	if nf == 0 {
+		return false
+	}
+	a := s.freeJacobian(free, s.m+nf)
+	sqrtLambda := math.Sqrt(s.lambda)
+	for k, j := range free {
+		a.Set(s.m+k, k, sqrtLambda*s.scale[j])
+	}
+	b := mat.NewVecDense(s.m+nf, nil)
+	for i, v := range s.r {
+		b.SetVec(i, -v)
+	}
+	var qr mat.QR
+	qr.Factorize(a)
+	var p mat.VecDense
+	err := qr.SolveVecTo(&p, false, b)
+	if err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			return false
+		}
+	}
+	s.scatter(free, &p)
+	return true
+}
+
+// doglegStep computes the dogleg step for the free variables within the
+// scaled trust region and stores it in s.step. It returns whether the step
+// could be computed.
+func (s *leastSquares) doglegStep(free []int) bool {
+	nf := len(free)
+	if nf == 0 {
+		return false
+	}
+	jf := s.freeJacobian(free, s.m)
+
+	// The steepest descent direction in scaled variables, gz = -D⁻¹ Jᵀr,
+	// and the Cauchy point along it.
+	gz := make([]float64, nf)
+	dg := mat.NewVecDense(nf, nil)
+	for k, j := range free {
+		gz[k] = -s.grad[j] / s.scale[j]
+		dg.SetVec(k, gz[k]/s.scale[j])
+	}
+	gNorm := floats.Norm(gz, 2)
+	var jdg mat.VecDense
+	jdg.MulVec(jf, dg)
+	curv := mat.Dot(&jdg, &jdg)
+	if gNorm == 0 || curv == 0 {
+		return false
+	}
+	alpha := gNorm * gNorm / curv
+	cauchyNorm := alpha * gNorm
+
+	// The Gauss-Newton step in scaled variables. When there are fewer
+	// residuals than free variables the system J D⁻¹ z = -r is
+	// underdetermined and the step is its minimum norm solution.
+	b := mat.NewVecDense(s.m, nil)
+	for i, v := range s.r {
+		b.SetVec(i, -v)
+	}
+	z := mat.NewVecDense(nf, nil)
+	var gnOK bool
+	if s.m < nf {
+		for k, j := range free {
+			for i := 0; i < s.m; i++ {
+				jf.Set(i, k, jf.At(i, k)/s.scale[j])
+			}
+		}
+		var lq mat.LQ
+		lq.Factorize(jf)
+		gnOK = lq.SolveVecTo(z, false, b) == nil
+	} else {
+		var qr mat.QR
+		qr.Factorize(jf)
+		var gn mat.VecDense
+		gnOK = qr.SolveVecTo(&gn, false, b) == nil
+		if gnOK {
+			for k, j := range free {
+				z.SetVec(k, s.scale[j]*gn.AtVec(k))
+			}
+		}
+	}
+
+	switch {
+	case gnOK && mat.Norm(z, 2) <= s.radius:
+		// The Gauss-Newton step lies within the trust region.
+	case !gnOK || cauchyNorm >= s.radius:
+		// Step to the boundary along the steepest descent direction.
+		t := math.Min(alpha, s.radius/gNorm)
+		for k := range gz {
+			z.SetVec(k, t*gz[k])
+		}
+	default:
+		// Step to the boundary along the segment from the Cauchy point
+		// to the Gauss-Newton step.
+		var d, c mat.VecDense
+		c.ScaleVec(alpha, mat.NewVecDense(nf, gz))
+		d.SubVec(z, &c)
+		dd := mat.Dot(&d, &d)
+		cd := mat.Dot(&c, &d)
+		cc := mat.Dot(&c, &c)
+		disc := cd*cd - dd*(cc-s.radius*s.radius)
+		tau := (-cd + math.Sqrt(math.Max(disc, 0))) / dd
+		z.AddScaledVec(&c, tau, &d)
+	}
+	for k, j := range free {
+		z.SetVec(k, z.AtVec(k)/s.scale[j])
+	}
+	s.scatter(free, z)
+	return true
+}
+
+// scatter stores the step p for the free variables in s.step.
+func (s *leastSquares) scatter(free []int, p mat.Vector) {
+	for i := range s.step {
+		s.step[i] = 0
+	}
+	for k, j := range free {
+		s.step[j] = p.AtVec(k)
+	}
+}
+
+// covariance returns the estimated covariance matrix of the parameters
+// from the Jacobian and the residuals at the solution, or nil if it cannot
+// be computed.
+func covariance(jac *mat.Dense, r []float64) *mat.SymDense {
+	m, n := jac.Dims()
+	if m <= n {
+		return nil
+	}
+	var qr mat.QR
+	qr.Factorize(jac)
+	var rFull mat.Dense
+	qr.RTo(&rFull)
+	rTri := mat.NewTriDense(n, mat.Upper, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			rTri.SetTri(i, j, rFull.At(i, j))
+		}
+	}
+	var rInv mat.TriDense
+	if err := rInv.InverseTri(rTri); err != nil {
+		return nil
+	}
+	// (JᵀJ)⁻¹ = R⁻¹ R⁻ᵀ.
+	variance := floats.Dot(r, r) / float64(m-n)
+	cov := mat.NewSymDense(n, nil)
+	cov.SymOuterK(variance, &rInv)
+	return cov
+}
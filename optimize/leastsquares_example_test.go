@@ -0,0 +1,49 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize_test
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+func ExampleLeastSquares() {
+	// Fit the model y = a exp(-b t) to observations.
+	t := []float64{0, 0.5, 1, 1.5, 2, 2.5, 3, 3.5}
+	y := []float64{2.02, 1.19, 0.76, 0.45, 0.26, 0.18, 0.09, 0.06}
+
+	p := optimize.LeastSquaresProblem{
+		Func: func(dst, x []float64) {
+			for i, ti := range t {
+				dst[i] = x[0]*math.Exp(-x[1]*ti) - y[i]
+			}
+		},
+		// The Jacobian is approximated by finite differences
+		// when Jac is nil.
+		Jac: func(dst *mat.Dense, x []float64) {
+			for i, ti := range t {
+				e := math.Exp(-x[1] * ti)
+				dst.Set(i, 0, e)
+				dst.Set(i, 1, -x[0]*ti*e)
+			}
+		},
+		Residuals: len(t),
+	}
+
+	res, err := optimize.LeastSquares(p, []float64{1, 0.1}, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("a = %.3f ± %.3f\n", res.X[0], math.Sqrt(res.Covariance.At(0, 0)))
+	fmt.Printf("b = %.3f ± %.3f\n", res.X[1], math.Sqrt(res.Covariance.At(1, 1)))
+
+	// Output:
+	// a = 2.011 ± 0.016
+	// b = 1.003 ± 0.015
+}
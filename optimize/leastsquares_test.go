@@ -0,0 +1,246 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+// expDecay returns a least-squares problem fitting y = a exp(-b t) + c to
+// noisy data generated with a=5, b=0.7 and c=1.
+func expDecay(withJac bool) LeastSquaresProblem {
+	rnd := rand.New(rand.NewSource(1))
+	const m = 40
+	ts := make([]float64, m)
+	ys := make([]float64, m)
+	for i := range ts {
+		ts[i] = 0.25 * float64(i)
+		ys[i] = 5*math.Exp(-0.7*ts[i]) + 1 + 0.01*rnd.NormFloat64()
+	}
+	p := LeastSquaresProblem{
+		Func: func(dst, x []float64) {
+			for i, t := range ts {
+				dst[i] = x[0]*math.Exp(-x[1]*t) + x[2] - ys[i]
+			}
+		},
+		Residuals: m,
+	}
+	if withJac {
+		p.Jac = func(dst *mat.Dense, x []float64) {
+			for i, t := range ts {
+				e := math.Exp(-x[1] * t)
+				dst.Set(i, 0, e)
+				dst.Set(i, 1, -x[0]*t*e)
+				dst.Set(i, 2, 1)
+			}
+		}
+	}
+	return p
+}
+
+func rosenbrockResiduals() LeastSquaresProblem {
+	return LeastSquaresProblem{
+		Func: func(dst, x []float64) {
+			dst[0] = 10 * (x[1] - x[0]*x[0])
+			dst[1] = 1 - x[0]
+		},
+		Jac: func(dst *mat.Dense, x []float64) {
+			dst.Set(0, 0, -20*x[0])
+			dst.Set(0, 1, 10)
+			dst.Set(1, 0, -1)
+			dst.Set(1, 1, 0)
+		},
+		Residuals: 2,
+	}
+}
+
+func TestLeastSquares(t *testing.T) {
+	t.Parallel()
+	for _, method := range []LeastSquaresMethod{LevenbergMarquardt, Dogleg} {
+		for _, test := range []struct {
+			name string
+			p    LeastSquaresProblem
+			x0   []float64
+			want []float64
+			tol  float64
+		}{
+			{
+				name: "Rosenbrock",
+				p:    rosenbrockResiduals(),
+				x0:   []float64{-1.2, 1},
+				want: []float64{1, 1},
+				tol:  1e-8,
+			},
+			{
+				name: "ExpDecay",
+				p:    expDecay(true),
+				x0:   []float64{1, 1, 0},
+				want: []float64{5, 0.7, 1},
+				tol:  2e-2,
+			},
+			{
+				name: "ExpDecayNumJac",
+				p:    expDecay(false),
+				x0:   []float64{1, 1, 0},
+				want: []float64{5, 0.7, 1},
+				tol:  2e-2,
+			},
+			{
+				name: "RosenbrockBounded",
+				p: func() LeastSquaresProblem {
+					p := rosenbrockResiduals()
+					p.Bounds = []Bound{{-2, 0.5}, {-2, 2}}
+					return p
+				}(),
+				x0:   []float64{-1.2, 1},
+				want: []float64{0.5, 0.25},
+				tol:  1e-8,
+			},
+			{
+				name: "ExpDecayBounded",
+				p: func() LeastSquaresProblem {
+					p := expDecay(true)
+					p.Bounds = []Bound{{0, 10}, {0, 10}, {1.5, 10}}
+					return p
+				}(),
+				x0:   []float64{1, 1, 0},
+				want: []float64{math.NaN(), math.NaN(), 1.5},
+				tol:  1e-12,
+			},
+		} {
+			name := fmt.Sprintf("%s/method=%d", test.name, method)
+			res, err := LeastSquares(test.p, test.x0, &LeastSquaresSettings{Method: method})
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", name, err)
+				continue
+			}
+			switch res.Status {
+			case GradientThreshold, FunctionConvergence, StepConvergence:
+			default:
+				t.Errorf("%s: unexpected status %v", name, res.Status)
+			}
+			for i, w := range test.want {
+				if math.IsNaN(w) {
+					continue
+				}
+				if !scalar.EqualWithinAbs(res.X[i], w, test.tol) {
+					t.Errorf("%s: unexpected solution: got %v, want %v", name, res.X, test.want)
+					break
+				}
+			}
+			for i, b := range test.p.Bounds {
+				if res.X[i] < b.Min || b.Max < res.X[i] {
+					t.Errorf("%s: solution outside bounds: %v", name, res.X)
+				}
+			}
+
+			r := make([]float64, test.p.Residuals)
+			test.p.Func(r, res.X)
+			if !floats.EqualApprox(r, res.Residuals, 1e-14) {
+				t.Errorf("%s: residuals mismatch", name)
+			}
+			if !scalar.EqualWithinAbsOrRel(res.Cost, 0.5*floats.Dot(r, r), 1e-14, 1e-14) {
+				t.Errorf("%s: cost mismatch", name)
+			}
+		}
+	}
+}
+
+func TestLeastSquaresCovariance(t *testing.T) {
+	t.Parallel()
+	for _, withJac := range []bool{true, false} {
+		p := expDecay(withJac)
+		res, err := LeastSquares(p, []float64{1, 1, 0}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Covariance == nil {
+			t.Fatalf("withJac=%t: unexpected nil covariance", withJac)
+		}
+		m, n := res.Jacobian.Dims()
+		if m != p.Residuals || n != 3 {
+			t.Errorf("withJac=%t: unexpected Jacobian dimensions %d×%d", withJac, m, n)
+		}
+
+		// Compare with s² (JᵀJ)⁻¹ computed from the normal equations.
+		var jtj mat.SymDense
+		jtj.SymOuterK(1, res.Jacobian.T())
+		var want mat.Dense
+		err = want.Inverse(&jtj)
+		if err != nil {
+			t.Fatalf("unexpected error inverting JᵀJ: %v", err)
+		}
+		want.Scale(floats.Dot(res.Residuals, res.Residuals)/float64(m-n), &want)
+		if !mat.EqualApprox(res.Covariance, &want, 1e-10) {
+			t.Errorf("withJac=%t: unexpected covariance:\ngot:\n%v\nwant:\n%v",
+				withJac, mat.Formatted(res.Covariance), mat.Formatted(&want))
+		}
+	}
+
+	// No covariance for a square Jacobian.
+	res, err := LeastSquares(rosenbrockResiduals(), []float64{-1.2, 1}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Covariance != nil {
+		t.Errorf("unexpected covariance for square problem")
+	}
+}
+
+func TestLeastSquaresEvaluationLimit(t *testing.T) {
+	t.Parallel()
+	res, err := LeastSquares(rosenbrockResiduals(), []float64{-1.2, 1}, &LeastSquaresSettings{FuncEvaluations: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != FunctionEvaluationLimit {
+		t.Errorf("unexpected status: got %v, want %v", res.Status, FunctionEvaluationLimit)
+	}
+	if res.FuncEvaluations > 3 {
+		t.Errorf("too many function evaluations: %d", res.FuncEvaluations)
+	}
+}
+
+func TestLeastSquaresUnderdetermined(t *testing.T) {
+	t.Parallel()
+	// Two residuals in three parameters have a manifold of zero
+	// residual solutions.
+	p := LeastSquaresProblem{
+		Func: func(dst, x []float64) {
+			dst[0] = x[0] + x[1] - 2
+			dst[1] = x[0]*x[2] - 1
+		},
+		Jac: func(dst *mat.Dense, x []float64) {
+			dst.Set(0, 0, 1)
+			dst.Set(0, 1, 1)
+			dst.Set(0, 2, 0)
+			dst.Set(1, 0, x[2])
+			dst.Set(1, 1, 0)
+			dst.Set(1, 2, x[0])
+		},
+		Residuals: 2,
+	}
+	for _, method := range []LeastSquaresMethod{LevenbergMarquardt, Dogleg} {
+		res, err := LeastSquares(p, []float64{0.5, 0.5, 0.5}, &LeastSquaresSettings{Method: method})
+		if err != nil {
+			t.Errorf("method=%d: unexpected error: %v", method, err)
+			continue
+		}
+		if res.Cost > 1e-20 {
+			t.Errorf("method=%d: unexpected cost: got %v, want 0 at %v", method, res.Cost, res.X)
+		}
+		if res.Covariance != nil {
+			t.Errorf("method=%d: unexpected covariance for underdetermined problem", method)
+		}
+	}
+}
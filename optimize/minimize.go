@@ -131,6 +131,10 @@ type Method interface {
 // returned Status is other than NotTerminated or if the error is not nil, the
 // optimization run is terminated.
 //
+// If p.Bounds is not nil, the initial location is projected onto the bounds.
+// Minimize panics if the initial location lies outside the bounds and
+// settings.InitValues is not nil.
+//
 // The second argument specifies the initial location for the optimization.
 // Some Methods do not require an initial location, but initX must still be
 // specified for the dimension of the optimization problem.
@@ -176,6 +180,9 @@ func Minimize(p Problem, initX []float64, settings *Settings, method Method) (*R
 	optLoc.F = math.Inf(1)
 
 	initOp, initLoc := getInitLocation(dim, initX, settings.InitValues)
+	if p.Bounds != nil && projectBounds(initLoc.X, p.Bounds) && initOp != NoOperation {
+		panic("optimize: initial location outside bounds with InitValues")
+	}
 
 	converger := settings.Converger
 	if converger == nil {
@@ -210,6 +217,12 @@ func Minimize(p Problem, initX []float64, settings *Settings, method Method) (*R
 }
 
 func getDefaultMethod(p *Problem) Method {
+	if len(p.Equality) != 0 || len(p.Inequality) != 0 {
+		return &AugmentedLagrangian{}
+	}
+	if p.Bounds != nil {
+		return &LBFGSB{}
+	}
 	if p.Grad != nil {
 		return &LBFGS{}
 	}
@@ -234,6 +247,9 @@ func minimize(prob *Problem, method Method, settings *Settings, converger Conver
 		panic("optimize: too many tasks returned by Method")
 	}
 	nTasks = newNTasks
+	if c, ok := method.(constrainer); ok {
+		c.setConstraints(prob)
+	}
 
 	// Launch the method. The method communicates tasks using the operations
 	// channel, and results is used to return the evaluated results.
@@ -463,6 +479,26 @@ func checkOptimization(p Problem, dim int, recorder Recorder) error {
 	if dim <= 0 {
 		panic("optimize: impossible problem dimension")
 	}
+	if p.Bounds != nil {
+		if len(p.Bounds) != dim {
+			panic("optimize: bounds do not match problem dimension")
+		}
+		for _, b := range p.Bounds {
+			if !(b.Min <= b.Max) {
+				panic("optimize: invalid bounds")
+			}
+		}
+	}
+	for _, c := range p.Equality {
+		if c.Func == nil {
+			panic("optimize: equality constraint function is nil")
+		}
+	}
+	for _, c := range p.Inequality {
+		if c.Func == nil {
+			panic("optimize: inequality constraint function is nil")
+		}
+	}
 	if p.Status != nil {
 		_, err := p.Status()
 		if err != nil {
@@ -478,6 +514,22 @@ func checkOptimization(p Problem, dim int, recorder Recorder) error {
 	return nil
 }
 
+// projectBounds projects x onto the bounds in place and returns whether
+// x was modified.
+func projectBounds(x []float64, bounds []Bound) (changed bool) {
+	for i, b := range bounds {
+		switch {
+		case x[i] < b.Min:
+			x[i] = b.Min
+			changed = true
+		case x[i] > b.Max:
+			x[i] = b.Max
+			changed = true
+		}
+	}
+	return changed
+}
+
 // evaluate evaluates the routines specified by the Operation at loc.X, and stores
 // the answer into loc. loc.X is copied into x before evaluating in order to
 // prevent the routines from modifying it.
@@ -136,42 +136,110 @@ type Problem struct {
 	// not able to evaluate itself. The user can use one of the pre-provided Status
 	// constants, or may call NewStatus to create a custom Status value.
 	Status func() (Status, error)
+
+	// Bounds specifies the lower and upper bounds on each element of the
+	// optimization variable. If Bounds is not nil, its length must match the
+	// dimension of the problem. Infinite bounds may be used for unbounded
+	// elements. Only Methods that support bounds, such as LBFGSB and
+	// AugmentedLagrangian, may be used with a bounded Problem.
+	Bounds []Bound
+
+	// Equality specifies constraints of the form c(x) = 0.
+	// Only Methods that support general constraints, such as
+	// AugmentedLagrangian, may be used with a constrained Problem.
+	Equality []Constraint
+
+	// Inequality specifies constraints of the form c(x) ≥ 0.
+	// Only Methods that support general constraints, such as
+	// AugmentedLagrangian, may be used with a constrained Problem.
+	Inequality []Constraint
+}
+
+// Bound represents the lower and upper bound on an element of the
+// optimization variable, Min ≤ x_i ≤ Max.
+type Bound struct {
+	Min, Max float64
+}
+
+// Constraint represents a nonlinear constraint function.
+type Constraint struct {
+	// Func evaluates the constraint function at x. Func must not modify x.
+	Func func(x []float64) float64
+
+	// Grad evaluates the gradient of the constraint function at x and
+	// stores the result in grad which will be the same length as x.
+	// Grad must not modify x.
+	Grad func(grad, x []float64)
 }
 
 // Available describes the functions available to call in Problem.
 type Available struct {
 	Grad bool
 	Hess bool
+
+	// Bounds and Constraints indicate that the Problem
+	// has bounds and general constraints respectively.
+	Bounds      bool
+	Constraints bool
 }
 
 func availFromProblem(prob Problem) Available {
-	return Available{Grad: prob.Grad != nil, Hess: prob.Hess != nil}
+	return Available{
+		Grad:        prob.Grad != nil,
+		Hess:        prob.Hess != nil,
+		Bounds:      prob.Bounds != nil,
+		Constraints: len(prob.Equality) != 0 || len(prob.Inequality) != 0,
+	}
 }
 
 // function tests if the Problem described by the receiver is suitable for an
 // unconstrained Method that only calls the function, and returns the result.
 func (has Available) function() (uses Available, err error) {
-	// TODO(btracey): This needs to be modified when optimize supports
-	// constrained optimization.
+	if has.Bounds || has.Constraints {
+		return Available{}, ErrUnsupportedConstraints
+	}
 	return Available{}, nil
 }
 
 // gradient tests if the Problem described by the receiver is suitable for an
 // unconstrained gradient-based Method, and returns the result.
 func (has Available) gradient() (uses Available, err error) {
-	// TODO(btracey): This needs to be modified when optimize supports
-	// constrained optimization.
+	if has.Bounds || has.Constraints {
+		return Available{}, ErrUnsupportedConstraints
+	}
 	if !has.Grad {
 		return Available{}, ErrMissingGrad
 	}
 	return Available{Grad: true}, nil
 }
 
+// boundedGradient tests if the Problem described by the receiver is suitable
+// for a bound-constrained gradient-based Method, and returns the result.
+func (has Available) boundedGradient() (uses Available, err error) {
+	if has.Constraints {
+		return Available{}, ErrUnsupportedConstraints
+	}
+	if !has.Grad {
+		return Available{}, ErrMissingGrad
+	}
+	return Available{Grad: true, Bounds: has.Bounds}, nil
+}
+
+// constrainedGradient tests if the Problem described by the receiver is
+// suitable for a constrained gradient-based Method, and returns the result.
+func (has Available) constrainedGradient() (uses Available, err error) {
+	if !has.Grad {
+		return Available{}, ErrMissingGrad
+	}
+	return Available{Grad: true, Bounds: has.Bounds, Constraints: has.Constraints}, nil
+}
+
 // hessian tests if the Problem described by the receiver is suitable for an
 // unconstrained Hessian-based Method, and returns the result.
 func (has Available) hessian() (uses Available, err error) {
-	// TODO(btracey): This needs to be modified when optimize supports
-	// constrained optimization.
+	if has.Bounds || has.Constraints {
+		return Available{}, ErrUnsupportedConstraints
+	}
 	if !has.Grad {
 		return Available{}, ErrMissingGrad
 	}
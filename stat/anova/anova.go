@@ -0,0 +1,283 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package anova
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/gonum/stat/regression"
+)
+
+// Term is a row of an analysis of variance table, holding the sum of
+// squares attributed to a source of variation.
+type Term struct {
+	// Name is the name of the source of variation.
+	Name string
+
+	// DF is the number of degrees of freedom of the term.
+	DF float64
+
+	// SS is the sum of squares of the term and MS is
+	// the mean square, SS/DF.
+	SS, MS float64
+
+	// F is the F-statistic of the term, the ratio of its
+	// mean square to the residual mean square, and P is
+	// the p-value of the F-test of the null hypothesis
+	// that the term has no effect. F and P are NaN for
+	// terms that are not tested.
+	F, P float64
+}
+
+// newTable returns the analysis of variance table for the terms with the
+// given names, degrees of freedom and sums of squares, followed by the
+// residual term. The terms in test are tested against the residual.
+func newTable(names []string, df, ss []float64, test []bool) []Term {
+	n := len(names) - 1
+	rdf, rss := df[n], ss[n]
+	if rdf <= 0 {
+		panic("anova: no residual degrees of freedom")
+	}
+	rms := rss / rdf
+	t := make([]Term, len(names))
+	for i, name := range names {
+		t[i] = Term{Name: name, DF: df[i], SS: ss[i], MS: ss[i] / df[i], F: math.NaN(), P: math.NaN()}
+		if i < n && test[i] {
+			t[i].F = t[i].MS / rms
+			t[i].P = distuv.F{D1: df[i], D2: rdf}.Survival(t[i].F)
+		}
+	}
+	return t
+}
+
+// OneWay performs a one-way analysis of variance of the observations in
+// groups, testing the null hypothesis that the means of the populations
+// from which the groups are drawn are equal. OneWay returns the table
+// with the terms "Groups" and "Residuals".
+//
+// OneWay will panic if there are fewer than two groups, if any group is
+// empty or if there are no residual degrees of freedom.
+func OneWay(groups [][]float64) []Term {
+	checkGroups(groups)
+	var n int
+	var sum float64
+	for _, g := range groups {
+		n += len(g)
+		sum += floats.Sum(g)
+	}
+	grand := sum / float64(n)
+	var ssb, ssw float64
+	for _, g := range groups {
+		mean := stat.Mean(g, nil)
+		d := mean - grand
+		ssb += float64(len(g)) * d * d
+		for _, v := range g {
+			ssw += (v - mean) * (v - mean)
+		}
+	}
+	k := float64(len(groups))
+	return newTable(
+		[]string{"Groups", "Residuals"},
+		[]float64{k - 1, float64(n) - k},
+		[]float64{ssb, ssw},
+		[]bool{true},
+	)
+}
+
+// TwoWay performs a two-way analysis of variance of the observations in y,
+// with the levels of the two factors of each observation given by a and b.
+// The levels of each factor are numbered from zero. If interaction is true
+// the model includes the interaction of the factors. TwoWay returns the
+// table with the terms "A", "B", "A:B" if interaction is true, and
+// "Residuals".
+//
+// The sums of squares are sequential, so the sum of squares of each term is
+// the reduction in the residual sum of squares on adding the term to a
+// model with the preceding terms. For unbalanced designs the sums of
+// squares of A and B depend on their order. For balanced designs they do
+// not, and the sums of squares are the classical ones.
+//
+// TwoWay will panic if the lengths of y, a and b differ, if either factor
+// has fewer than two levels or an unobserved level, if interaction is true
+// and a combination of levels is unobserved, or if there are no residual
+// degrees of freedom.
+func TwoWay(y []float64, a, b []int, interaction bool) []Term {
+	if len(a) != len(y) || len(b) != len(y) {
+		panic("anova: slice length mismatch")
+	}
+	na := levels(a)
+	nb := levels(b)
+	if interaction {
+		seen := make([]bool, na*nb)
+		for i := range y {
+			seen[a[i]*nb+b[i]] = true
+		}
+		for _, ok := range seen {
+			if !ok {
+				panic("anova: unobserved combination of levels")
+			}
+		}
+	}
+
+	// Fit the sequence of nested models with treatment coded
+	// factors.
+	n := len(y)
+	cols := (na - 1) + (nb - 1)
+	if interaction {
+		cols += (na - 1) * (nb - 1)
+	}
+	x := mat.NewDense(n, cols, nil)
+	for i := range y {
+		if a[i] > 0 {
+			x.Set(i, a[i]-1, 1)
+		}
+		if b[i] > 0 {
+			x.Set(i, na-1+b[i]-1, 1)
+		}
+		if interaction && a[i] > 0 && b[i] > 0 {
+			x.Set(i, na-1+nb-1+(a[i]-1)*(nb-1)+b[i]-1, 1)
+		}
+	}
+	dfs := []float64{float64(na - 1), float64(nb - 1)}
+	if interaction {
+		dfs = append(dfs, float64((na-1)*(nb-1)))
+	}
+	rss := make([]float64, len(dfs)+1)
+	mean := stat.Mean(y, nil)
+	for _, v := range y {
+		rss[0] += (v - mean) * (v - mean)
+	}
+	var c int
+	for i, df := range dfs {
+		c += int(df)
+		if n <= c+1 {
+			panic("anova: no residual degrees of freedom")
+		}
+		rss[i+1] = residualSS(x.Slice(0, n, 0, c), y)
+	}
+
+	names := []string{"A", "B"}
+	if interaction {
+		names = append(names, "A:B")
+	}
+	names = append(names, "Residuals")
+	ss := make([]float64, len(dfs)+1)
+	for i := range dfs {
+		ss[i] = rss[i] - rss[i+1]
+	}
+	ss[len(dfs)] = rss[len(dfs)]
+	dfs = append(dfs, float64(n-1-c))
+	return newTable(names, dfs, ss, []bool{true, true, true})
+}
+
+// RepeatedMeasures performs a one-way repeated measures analysis of
+// variance of the observations in data, where each row holds the
+// observations of a subject under each of the conditions in the columns.
+// It tests the null hypothesis that the means of the conditions are equal,
+// treating subjects as blocks. RepeatedMeasures returns the table with the
+// terms "Subjects", "Conditions" and "Residuals", where only Conditions is
+// tested.
+//
+// The F-test assumes sphericity, that the variances of the differences
+// between all pairs of conditions are equal.
+//
+// RepeatedMeasures will panic if data has fewer than two rows or columns.
+func RepeatedMeasures(data mat.Matrix) []Term {
+	n, k := data.Dims()
+	if n < 2 || k < 2 {
+		panic("anova: too few subjects or conditions")
+	}
+	rowMeans := make([]float64, n)
+	colMeans := make([]float64, k)
+	var grand float64
+	for i := 0; i < n; i++ {
+		for j := 0; j < k; j++ {
+			v := data.At(i, j)
+			rowMeans[i] += v
+			colMeans[j] += v
+			grand += v
+		}
+	}
+	floats.Scale(1/float64(k), rowMeans)
+	floats.Scale(1/float64(n), colMeans)
+	grand /= float64(n * k)
+
+	var sss, ssc, sse float64
+	for _, m := range rowMeans {
+		sss += float64(k) * (m - grand) * (m - grand)
+	}
+	for _, m := range colMeans {
+		ssc += float64(n) * (m - grand) * (m - grand)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < k; j++ {
+			e := data.At(i, j) - rowMeans[i] - colMeans[j] + grand
+			sse += e * e
+		}
+	}
+	return newTable(
+		[]string{"Subjects", "Conditions", "Residuals"},
+		[]float64{float64(n - 1), float64(k - 1), float64((n - 1) * (k - 1))},
+		[]float64{sss, ssc, sse},
+		[]bool{false, true},
+	)
+}
+
+// residualSS returns the residual sum of squares of the least squares fit
+// of y on x with an intercept.
+func residualSS(x mat.Matrix, y []float64) float64 {
+	var l regression.Linear
+	err := l.Fit(x, y, nil, true)
+	if err != nil {
+		panic("anova: " + err.Error())
+	}
+	e := l.Residuals(nil)
+	return floats.Dot(e, e)
+}
+
+// levels returns the number of levels of the factor f, and panics if the
+// factor has fewer than two levels, a negative level or an unobserved
+// level.
+func levels(f []int) int {
+	var n int
+	for _, v := range f {
+		if v < 0 {
+			panic("anova: negative factor level")
+		}
+		if v >= n {
+			n = v + 1
+		}
+	}
+	if n < 2 {
+		panic("anova: factor has fewer than two levels")
+	}
+	seen := make([]bool, n)
+	for _, v := range f {
+		seen[v] = true
+	}
+	for _, ok := range seen {
+		if !ok {
+			panic("anova: unobserved factor level")
+		}
+	}
+	return n
+}
+
+// checkGroups panics if there are fewer than two groups or any group is
+// empty.
+func checkGroups(groups [][]float64) {
+	if len(groups) < 2 {
+		panic("anova: fewer than two groups")
+	}
+	for _, g := range groups {
+		if len(g) == 0 {
+			panic("anova: empty group")
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package anova
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// The dried weights of plants under a control and two treatment
+// conditions, the PlantGrowth data set of R, from Dobson (1983).
+var plantGrowth = [][]float64{
+	{4.17, 5.58, 5.18, 6.11, 4.50, 4.61, 5.17, 4.53, 5.33, 5.14},
+	{4.81, 4.17, 4.41, 3.59, 5.87, 3.83, 6.03, 4.89, 4.32, 4.69},
+	{6.31, 5.12, 5.54, 5.50, 5.37, 5.29, 4.92, 6.15, 5.80, 5.26},
+}
+
+func TestOneWay(t *testing.T) {
+	t.Parallel()
+	// Reference values from anova(lm(weight ~ group, PlantGrowth))
+	// in R.
+	got := OneWay(plantGrowth)
+	want := []Term{
+		{Name: "Groups", DF: 2, SS: 3.7663, MS: 1.8832, F: 4.8461, P: 0.01591},
+		{Name: "Residuals", DF: 27, SS: 10.4921, MS: 0.3886, F: math.NaN(), P: math.NaN()},
+	}
+	checkTable(t, "PlantGrowth", got, want, 5e-5, 5e-4)
+}
+
+func checkTable(t *testing.T, name string, got, want []Term, tol, ptol float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("unexpected number of terms for %s: got:%d want:%d", name, len(got), len(want))
+	}
+	for i, g := range got {
+		w := want[i]
+		same := func(a, b, tol float64) bool {
+			return (math.IsNaN(a) && math.IsNaN(b)) || scalar.EqualWithinAbsOrRel(a, b, tol, tol)
+		}
+		if g.Name != w.Name || g.DF != w.DF || !same(g.SS, w.SS, tol) || !same(g.MS, w.MS, tol) ||
+			!same(g.F, w.F, tol) || !same(g.P, w.P, ptol) {
+			t.Errorf("unexpected term %d for %s:\ngot: %+v\nwant:%+v", i, name, g, w)
+		}
+	}
+}
+
+// twoWayData returns observations of a two-way design with na and nb levels
+// and the given number of replicates per cell, with the first drop
+// observations removed.
+func twoWayData(rnd *rand.Rand, na, nb, reps, drop int) (y []float64, a, b []int) {
+	for i := 0; i < na; i++ {
+		for j := 0; j < nb; j++ {
+			for r := 0; r < reps; r++ {
+				a = append(a, i)
+				b = append(b, j)
+				y = append(y, float64(i)+0.5*float64(j)+0.3*float64(i*j)+rnd.NormFloat64())
+			}
+		}
+	}
+	return y[drop:], a[drop:], b[drop:]
+}
+
+func TestTwoWayBalanced(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const na, nb, reps = 3, 4, 3
+	y, a, b := twoWayData(rnd, na, nb, reps, 0)
+
+	// The classical sums of squares of a balanced design.
+	grand := stat.Mean(y, nil)
+	am := make([]float64, na)
+	bm := make([]float64, nb)
+	cm := make([]float64, na*nb)
+	for i, v := range y {
+		am[a[i]] += v / (nb * reps)
+		bm[b[i]] += v / (na * reps)
+		cm[a[i]*nb+b[i]] += v / reps
+	}
+	var ssa, ssb, ssab, sse float64
+	for _, m := range am {
+		ssa += nb * reps * (m - grand) * (m - grand)
+	}
+	for _, m := range bm {
+		ssb += na * reps * (m - grand) * (m - grand)
+	}
+	for i := 0; i < na; i++ {
+		for j := 0; j < nb; j++ {
+			d := cm[i*nb+j] - am[i] - bm[j] + grand
+			ssab += reps * d * d
+		}
+	}
+	for i, v := range y {
+		d := v - cm[a[i]*nb+b[i]]
+		sse += d * d
+	}
+
+	got := TwoWay(y, a, b, true)
+	wantSS := []float64{ssa, ssb, ssab, sse}
+	wantDF := []float64{na - 1, nb - 1, (na - 1) * (nb - 1), na * nb * (reps - 1)}
+	for i, term := range got {
+		if !scalar.EqualWithinRel(term.SS, wantSS[i], 1e-10) || term.DF != wantDF[i] {
+			t.Errorf("unexpected term %s: got SS:%v DF:%v want SS:%v DF:%v", term.Name, term.SS, term.DF, wantSS[i], wantDF[i])
+		}
+	}
+	ms := sse / wantDF[3]
+	for i, term := range got[:3] {
+		if f := wantSS[i] / wantDF[i] / ms; !scalar.EqualWithinRel(term.F, f, 1e-10) {
+			t.Errorf("unexpected F-statistic for %s: got:%v want:%v", term.Name, term.F, f)
+		}
+	}
+
+	// For a balanced design the order of the factors does not
+	// affect the sums of squares.
+	swapped := TwoWay(y, b, a, true)
+	if !scalar.EqualWithinRel(swapped[0].SS, ssb, 1e-10) || !scalar.EqualWithinRel(swapped[1].SS, ssa, 1e-10) {
+		t.Errorf("sums of squares depend on order of factors for balanced design")
+	}
+
+	// Without interaction, the interaction sum of squares is
+	// pooled with the residual.
+	got = TwoWay(y, a, b, false)
+	if len(got) != 3 || !scalar.EqualWithinRel(got[2].SS, ssab+sse, 1e-10) || got[2].DF != wantDF[2]+wantDF[3] {
+		t.Errorf("unexpected residual term without interaction: %+v", got[len(got)-1])
+	}
+}
+
+func TestTwoWayUnbalanced(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	y, a, b := twoWayData(rnd, 3, 3, 3, 2)
+	for _, interaction := range []bool{false, true} {
+		got := TwoWay(y, a, b, interaction)
+		var total float64
+		mean := stat.Mean(y, nil)
+		for _, v := range y {
+			total += (v - mean) * (v - mean)
+		}
+		var sum, df float64
+		for _, term := range got {
+			sum += term.SS
+			df += term.DF
+		}
+		if !scalar.EqualWithinRel(sum, total, 1e-10) || df != float64(len(y)-1) {
+			t.Errorf("sums of squares do not partition the total for interaction=%t: got:%v on %v df want:%v on %v df",
+				interaction, sum, df, total, len(y)-1)
+		}
+		// The sum of squares of the first factor is that
+		// of the one-way analysis.
+		groups := make([][]float64, 3)
+		for i, v := range y {
+			groups[a[i]] = append(groups[a[i]], v)
+		}
+		if ow := OneWay(groups); !scalar.EqualWithinRel(got[0].SS, ow[0].SS, 1e-10) {
+			t.Errorf("unexpected sum of squares of first factor for interaction=%t: got:%v want:%v", interaction, got[0].SS, ow[0].SS)
+		}
+	}
+}
+
+func TestRepeatedMeasures(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n, k = 8, 4
+	data := mat.NewDense(n, k, nil)
+	var y []float64
+	var subject, condition []int
+	for i := 0; i < n; i++ {
+		s := rnd.NormFloat64()
+		for j := 0; j < k; j++ {
+			v := s + 0.3*float64(j) + 0.5*rnd.NormFloat64()
+			data.Set(i, j, v)
+			y = append(y, v)
+			subject = append(subject, i)
+			condition = append(condition, j)
+		}
+	}
+	// The repeated measures analysis is the two-way analysis
+	// with subjects as a factor and no interaction.
+	got := RepeatedMeasures(data)
+	want := TwoWay(y, subject, condition, false)
+	want[0].Name = "Subjects"
+	want[0].F = math.NaN()
+	want[0].P = math.NaN()
+	want[1].Name = "Conditions"
+	checkTable(t, "repeated measures", got, want, 1e-10, 1e-10)
+}
+
+func TestPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "one group", fn: func() { OneWay([][]float64{{1, 2}}) }},
+		{name: "empty group", fn: func() { OneWay([][]float64{{1, 2}, {}}) }},
+		{name: "no residual", fn: func() { OneWay([][]float64{{1}, {2}}) }},
+		{name: "length mismatch", fn: func() { TwoWay([]float64{1, 2}, []int{0, 1}, []int{0}, false) }},
+		{name: "unobserved level", fn: func() { TwoWay([]float64{1, 2, 3}, []int{0, 2, 0}, []int{0, 1, 1}, false) }},
+		{name: "unobserved cell", fn: func() {
+			TwoWay([]float64{1, 2, 3, 4, 5}, []int{0, 0, 1, 1, 1}, []int{0, 0, 1, 1, 1}, true)
+		}},
+		{name: "level", fn: func() { TukeyHSD(plantGrowth, 1) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return panicked
+}
@@ -0,0 +1,11 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package anova provides analysis of variance and post-hoc comparisons of
+// group means.
+//
+// The non-parametric alternatives to one-way and repeated measures analysis
+// of variance, the Kruskal-Wallis and Friedman tests, are provided by the
+// hypothesis package.
+package anova // import "gonum.org/v1/gonum/stat/anova"
@@ -0,0 +1,46 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package anova_test
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/stat/anova"
+)
+
+func ExampleOneWay() {
+	// The dried weights of plants grown under a control
+	// condition and two treatments.
+	groups := [][]float64{
+		{4.17, 5.58, 5.18, 6.11, 4.50, 4.61, 5.17, 4.53, 5.33, 5.14},
+		{4.81, 4.17, 4.41, 3.59, 5.87, 3.83, 6.03, 4.89, 4.32, 4.69},
+		{6.31, 5.12, 5.54, 5.50, 5.37, 5.29, 4.92, 6.15, 5.80, 5.26},
+	}
+	names := []string{"ctrl", "trt1", "trt2"}
+
+	fmt.Printf("%-9s %2s %7s %6s %6s %6s\n", "", "DF", "SS", "MS", "F", "P")
+	for _, t := range anova.OneWay(groups) {
+		fmt.Printf("%-9s %2.0f %7.4f %6.4f %6.4f %6.4f\n", t.Name, t.DF, t.SS, t.MS, t.F, t.P)
+	}
+
+	// The treatments differ in their effect on the weights, so
+	// compare all pairs of groups keeping the family-wise
+	// confidence level at 95%.
+	fmt.Println("\nTukey's honestly significant differences:")
+	for _, c := range anova.TukeyHSD(groups, 0.95) {
+		fmt.Printf("%s-%s: diff=%6.3f CI=[%6.3f, %6.3f] P=%.4f\n",
+			names[c.J], names[c.I], c.Diff, c.Lower, c.Upper, c.P)
+	}
+
+	// Output:
+	//           DF      SS     MS      F      P
+	// Groups     2  3.7663 1.8832 4.8461 0.0159
+	// Residuals 27 10.4921 0.3886    NaN    NaN
+	//
+	// Tukey's honestly significant differences:
+	// trt1-ctrl: diff=-0.371 CI=[-1.062,  0.320] P=0.3909
+	// trt2-ctrl: diff= 0.494 CI=[-0.197,  1.185] P=0.1980
+	// trt2-trt1: diff= 0.865 CI=[ 0.174,  1.556] P=0.0120
+}
@@ -0,0 +1,125 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package anova
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Comparison is a comparison of the means of two groups.
+type Comparison struct {
+	// I and J are the indices of the compared groups,
+	// with I < J.
+	I, J int
+
+	// Diff is the difference between the means of the
+	// groups, the mean of group J less the mean of
+	// group I.
+	Diff float64
+
+	// Lower and Upper are the bounds of the simultaneous
+	// confidence interval for the difference.
+	Lower, Upper float64
+
+	// P is the p-value of the test of the null hypothesis
+	// that the means of the groups are equal, adjusted
+	// for the number of comparisons.
+	P float64
+}
+
+// TukeyHSD performs Tukey's honestly significant difference test of all
+// pairwise differences between the means of the groups, with simultaneous
+// confidence intervals at the given level. The comparisons are ordered
+// with I varying slowest. For groups of different sizes the Tukey-Kramer
+// approximation is used.
+//
+// TukeyHSD will panic if there are fewer than two groups, if any group is
+// empty, if there are no residual degrees of freedom or if level is not
+// in (0, 1).
+//
+// References:
+//   - Tukey, J. W. (1949). Comparing individual means in the analysis of
+//     variance. Biometrics, 5(2), 99-114.
+//   - Kramer, C. Y. (1956). Extension of multiple range tests to group means
+//     with unequal numbers of replications. Biometrics, 12(3), 307-310.
+func TukeyHSD(groups [][]float64, level float64) []Comparison {
+	means, mse, df := pooled(groups, level)
+	k := len(groups)
+	q := rangeQuantile(level, k, df)
+	var c []Comparison
+	for i := 0; i < k; i++ {
+		for j := i + 1; j < k; j++ {
+			d := means[j] - means[i]
+			se := math.Sqrt(mse / 2 * (1/float64(len(groups[i])) + 1/float64(len(groups[j]))))
+			c = append(c, Comparison{
+				I: i, J: j,
+				Diff:  d,
+				Lower: d - q*se,
+				Upper: d + q*se,
+				P:     1 - rangeCDF(math.Abs(d)/se, k, df),
+			})
+		}
+	}
+	return c
+}
+
+// Bonferroni performs pairwise t-tests of the differences between the
+// means of the groups, using the pooled residual variance of all the
+// groups, with the p-values and confidence intervals at the given level
+// adjusted for the number of comparisons by the Bonferroni correction. The
+// comparisons are ordered with I varying slowest.
+//
+// Bonferroni will panic if there are fewer than two groups, if any group
+// is empty, if there are no residual degrees of freedom or if level is not
+// in (0, 1).
+func Bonferroni(groups [][]float64, level float64) []Comparison {
+	means, mse, df := pooled(groups, level)
+	k := len(groups)
+	m := float64(k * (k - 1) / 2)
+	dist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}
+	q := dist.Quantile(1 - (1-level)/(2*m))
+	var c []Comparison
+	for i := 0; i < k; i++ {
+		for j := i + 1; j < k; j++ {
+			d := means[j] - means[i]
+			se := math.Sqrt(mse * (1/float64(len(groups[i])) + 1/float64(len(groups[j]))))
+			c = append(c, Comparison{
+				I: i, J: j,
+				Diff:  d,
+				Lower: d - q*se,
+				Upper: d + q*se,
+				P:     math.Min(1, 2*m*dist.Survival(math.Abs(d)/se)),
+			})
+		}
+	}
+	return c
+}
+
+// pooled returns the means of the groups and the pooled residual variance
+// and its degrees of freedom.
+func pooled(groups [][]float64, level float64) (means []float64, mse, df float64) {
+	checkGroups(groups)
+	if !(0 < level && level < 1) {
+		panic("anova: confidence level out of range")
+	}
+	means = make([]float64, len(groups))
+	var ss float64
+	var n int
+	for i, g := range groups {
+		means[i] = stat.Mean(g, nil)
+		for _, v := range g {
+			ss += (v - means[i]) * (v - means[i])
+		}
+		n += len(g)
+	}
+	df = float64(n - len(groups))
+	if df <= 0 {
+		panic("anova: no residual degrees of freedom")
+	}
+	return means, ss / df, df
+}
@@ -0,0 +1,58 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package anova
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestTukeyHSD(t *testing.T) {
+	t.Parallel()
+	// Reference values from TukeyHSD(aov(weight ~ group, PlantGrowth))
+	// in R.
+	got := TukeyHSD(plantGrowth, 0.95)
+	want := []Comparison{
+		{I: 0, J: 1, Diff: -0.371, Lower: -1.0622161, Upper: 0.3202161, P: 0.3908711},
+		{I: 0, J: 2, Diff: 0.494, Lower: -0.1972161, Upper: 1.1852161, P: 0.1979960},
+		{I: 1, J: 2, Diff: 0.865, Lower: 0.1737839, Upper: 1.5562161, P: 0.0120064},
+	}
+	for i, g := range got {
+		w := want[i]
+		if g.I != w.I || g.J != w.J || !scalar.EqualWithinAbs(g.Diff, w.Diff, 1e-12) ||
+			!scalar.EqualWithinAbs(g.Lower, w.Lower, 1e-6) || !scalar.EqualWithinAbs(g.Upper, w.Upper, 1e-6) ||
+			!scalar.EqualWithinAbs(g.P, w.P, 1e-6) {
+			t.Errorf("unexpected comparison %d:\ngot: %+v\nwant:%+v", i, g, w)
+		}
+	}
+}
+
+func TestBonferroni(t *testing.T) {
+	t.Parallel()
+	// Reference p-values from pairwise.t.test(weight, group,
+	// p.adjust.method = "bonferroni") with PlantGrowth in R.
+	got := Bonferroni(plantGrowth, 0.95)
+	for i, want := range []float64{0.583, 0.263, 0.013} {
+		if !scalar.EqualWithinAbs(got[i].P, want, 5e-4) {
+			t.Errorf("unexpected p-value for comparison %d: got:%v want:%v", i, got[i].P, want)
+		}
+	}
+	// The difference is on the boundary of the confidence
+	// interval when the unadjusted p-value is the adjusted
+	// significance level.
+	mse := OneWay(plantGrowth)[1].MS
+	dist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: 27}
+	for _, c := range got {
+		se := math.Sqrt(mse * 2 / 10)
+		for _, b := range []float64{c.Lower, c.Upper} {
+			if p := 2 * dist.Survival(math.Abs(c.Diff-b)/se); !scalar.EqualWithinAbs(p, 0.05/3, 1e-10) {
+				t.Errorf("unexpected confidence bound for comparison %d-%d: p-value at bound %v", c.I, c.J, p)
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package anova
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestRangeCDFTwoGroups(t *testing.T) {
+	t.Parallel()
+	// The studentized range of two groups is √2 times the
+	// absolute value of a Student's t variable.
+	for _, df := range []float64{2, 5, 10, 30, 120, 1000, 1e5} {
+		for _, q := range []float64{0.1, 0.5, 1, 2, 3, 5, 8} {
+			got := rangeCDF(q, 2, df)
+			want := 1 - 2*distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}.Survival(q/math.Sqrt2)
+			if !scalar.EqualWithinAbs(got, want, 1e-8) {
+				t.Errorf("unexpected CDF for q=%v df=%v: got:%v want:%v", q, df, got, want)
+			}
+		}
+	}
+}
+
+func TestRangeQuantile(t *testing.T) {
+	t.Parallel()
+	// Upper 5% critical values of the studentized range from
+	// published tables.
+	for _, test := range []struct {
+		k    int
+		df   float64
+		want float64
+	}{
+		{k: 2, df: math.Inf(1), want: 2.77},
+		{k: 3, df: 10, want: 3.88},
+		{k: 4, df: 20, want: 3.96},
+		{k: 5, df: 30, want: 4.10},
+		{k: 10, df: 60, want: 4.65},
+		{k: 20, df: 120, want: 5.13},
+	} {
+		got := rangeQuantile(0.95, test.k, test.df)
+		if !scalar.EqualWithinAbs(got, test.want, 5e-3) {
+			t.Errorf("unexpected critical value for k=%d df=%v: got:%v want:%v", test.k, test.df, got, test.want)
+		}
+		if p := rangeCDF(got, test.k, test.df); !scalar.EqualWithinAbs(p, 0.95, 1e-10) {
+			t.Errorf("quantile does not invert CDF for k=%d df=%v: %v", test.k, test.df, p)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package glm provides generalized linear models.
+//
+// A generalized linear model relates the mean μ of a response from an
+// exponential dispersion family to a linear predictor η = X β through a
+// link function, g(μ) = η. The Family and Link interfaces describe the
+// distribution of the response and the link function, and the GLM type
+// fits the coefficients β by maximum likelihood, using iteratively
+// reweighted least squares, or by penalized maximum likelihood using the
+// methods of the optimize package.
+package glm // import "gonum.org/v1/gonum/stat/glm"
@@ -0,0 +1,81 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glm_test
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/glm"
+)
+
+func ExampleGLM_logistic() {
+	// The hours that students spent studying for an exam
+	// and whether they passed.
+	hours := []float64{
+		0.50, 0.75, 1.00, 1.25, 1.50, 1.75, 1.75, 2.00, 2.25, 2.50,
+		2.75, 3.00, 3.25, 3.50, 4.00, 4.25, 4.50, 4.75, 5.00, 5.50,
+	}
+	pass := []float64{0, 0, 0, 0, 0, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 1, 1, 1, 1, 1}
+
+	m := glm.GLM{Family: glm.Binomial{}}
+	err := m.Fit(mat.NewDense(len(hours), 1, hours), pass, nil, true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	coef := m.Coefficients(nil)
+	se := m.StdErrs(nil)
+	p := m.PValues(nil)
+	for i, name := range []string{"intercept", "hours"} {
+		fmt.Printf("%-9s  %6.3f  (%.3f)  p = %.3f\n", name, coef[i], se[i], p[i])
+	}
+	fmt.Printf("odds ratio per hour = %.2f\n", math.Exp(coef[1]))
+	fmt.Printf("deviance = %.3f, null deviance = %.3f, AIC = %.3f\n", m.Deviance(), m.NullDeviance(), m.AIC())
+
+	prob := m.Predict(nil, mat.NewDense(2, 1, []float64{2, 4}))
+	fmt.Printf("probability of passing after 2 hours = %.3f and after 4 hours = %.3f\n", prob[0], prob[1])
+
+	// Output:
+	// intercept  -4.078  (1.761)  p = 0.021
+	// hours       1.505  (0.629)  p = 0.017
+	// odds ratio per hour = 4.50
+	// deviance = 16.060, null deviance = 27.726, AIC = 20.060
+	// probability of passing after 2 hours = 0.256 and after 4 hours = 0.874
+}
+
+func ExampleGLM_penalized() {
+	// Counts of events under five conditions, of which only
+	// the first affects the rate.
+	x := mat.NewDense(12, 5, []float64{
+		1, 0, 0, 1, 0,
+		0, 1, 0, 0, 1,
+		1, 1, 1, 0, 0,
+		0, 0, 1, 1, 1,
+		1, 0, 1, 0, 1,
+		0, 1, 1, 1, 0,
+		1, 1, 0, 1, 1,
+		0, 0, 0, 0, 0,
+		1, 0, 0, 0, 1,
+		0, 1, 0, 1, 0,
+		1, 1, 1, 1, 1,
+		0, 0, 1, 0, 0,
+	})
+	counts := []float64{15, 4, 13, 6, 17, 5, 14, 3, 16, 6, 12, 4}
+
+	for _, l1 := range []float64{0, 4} {
+		m := glm.GLM{Family: glm.Poisson{}, L1: l1}
+		err := m.Fit(x, counts, nil, true)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("L1 = %v: %.3f\n", l1, m.Coefficients(nil))
+	}
+
+	// Output:
+	// L1 = 0: [1.545 1.112 -0.150 0.017 0.072 0.067]
+	// L1 = 4: [1.674 0.953 0.000 0.000 0.000 0.000]
+}
@@ -0,0 +1,201 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glm
+
+import "math"
+
+// Family is an exponential dispersion family of distributions of the
+// response of a generalized linear model. The variance of a response with
+// mean μ and weight w is φ V(μ) / w, where φ is the dispersion parameter.
+type Family interface {
+	// CanonicalLink returns the canonical link function
+	// of the family.
+	CanonicalLink() Link
+
+	// ValidResponse returns whether y is in the support
+	// of the family.
+	ValidResponse(y float64) bool
+
+	// ValidMean returns whether mu is a valid mean of the
+	// family.
+	ValidMean(mu float64) bool
+
+	// InitialMean returns the starting value of the mean
+	// for fitting a response y with weight w.
+	InitialMean(y, w float64) float64
+
+	// Variance returns the variance function V(μ).
+	Variance(mu float64) float64
+
+	// UnitDeviance returns the unit deviance d(y, μ), twice
+	// the difference between the log-likelihoods of the
+	// saturated model and of the mean μ at unit dispersion
+	// and weight.
+	UnitDeviance(y, mu float64) float64
+
+	// LogLikelihood returns the log-likelihood of the
+	// response y with weight w, given the mean mu and the
+	// dispersion phi.
+	LogLikelihood(y, mu, w, phi float64) float64
+
+	// FixedDispersion returns whether the dispersion of
+	// the family is fixed at one.
+	FixedDispersion() bool
+}
+
+// Gaussian is the Gaussian family, with V(μ) = 1 and the dispersion equal
+// to the variance of the errors. Its canonical link is Identity.
+type Gaussian struct{}
+
+// CanonicalLink returns Identity.
+func (Gaussian) CanonicalLink() Link { return Identity{} }
+
+// ValidResponse returns whether y is finite.
+func (Gaussian) ValidResponse(y float64) bool { return !math.IsInf(y, 0) && !math.IsNaN(y) }
+
+// ValidMean returns whether mu is finite.
+func (Gaussian) ValidMean(mu float64) bool { return !math.IsInf(mu, 0) && !math.IsNaN(mu) }
+
+// InitialMean returns y.
+func (Gaussian) InitialMean(y, w float64) float64 { return y }
+
+// Variance returns 1.
+func (Gaussian) Variance(mu float64) float64 { return 1 }
+
+// UnitDeviance returns (y-μ)².
+func (Gaussian) UnitDeviance(y, mu float64) float64 { return (y - mu) * (y - mu) }
+
+// LogLikelihood returns the log-likelihood of y with mean mu and variance
+// phi/w.
+func (Gaussian) LogLikelihood(y, mu, w, phi float64) float64 {
+	d := y - mu
+	return -0.5 * (math.Log(2*math.Pi*phi/w) + w*d*d/phi)
+}
+
+// FixedDispersion returns false.
+func (Gaussian) FixedDispersion() bool { return false }
+
+// Binomial is the binomial family for proportions of successes, with
+// V(μ) = μ(1-μ). The weight of a response is the number of trials, and the
+// response is the proportion of the trials that are successes. Its
+// canonical link is Logit.
+type Binomial struct{}
+
+// CanonicalLink returns Logit.
+func (Binomial) CanonicalLink() Link { return Logit{} }
+
+// ValidResponse returns whether y is in [0, 1].
+func (Binomial) ValidResponse(y float64) bool { return 0 <= y && y <= 1 }
+
+// ValidMean returns whether mu is in (0, 1).
+func (Binomial) ValidMean(mu float64) bool { return 0 < mu && mu < 1 }
+
+// InitialMean returns (w y + 0.5)/(w + 1).
+func (Binomial) InitialMean(y, w float64) float64 { return (w*y + 0.5) / (w + 1) }
+
+// Variance returns μ(1-μ).
+func (Binomial) Variance(mu float64) float64 { return mu * (1 - mu) }
+
+// UnitDeviance returns 2 (y log(y/μ) + (1-y) log((1-y)/(1-μ))).
+func (Binomial) UnitDeviance(y, mu float64) float64 {
+	return 2 * (xlogy(y, y/mu) + xlogy(1-y, (1-y)/(1-mu)))
+}
+
+// LogLikelihood returns the log-probability of w y successes in w trials
+// with success probability mu. The dispersion is ignored.
+func (Binomial) LogLikelihood(y, mu, w, phi float64) float64 {
+	k := w * y
+	return lchoose(w, k) + xlogy(k, mu) + xlogy(w-k, 1-mu)
+}
+
+// FixedDispersion returns true.
+func (Binomial) FixedDispersion() bool { return true }
+
+// Poisson is the Poisson family for counts, with V(μ) = μ. Its canonical
+// link is Log.
+type Poisson struct{}
+
+// CanonicalLink returns Log.
+func (Poisson) CanonicalLink() Link { return Log{} }
+
+// ValidResponse returns whether y is non-negative and finite.
+func (Poisson) ValidResponse(y float64) bool { return 0 <= y && !math.IsInf(y, 1) }
+
+// ValidMean returns whether mu is positive and finite.
+func (Poisson) ValidMean(mu float64) bool { return 0 < mu && !math.IsInf(mu, 1) }
+
+// InitialMean returns y + 0.1.
+func (Poisson) InitialMean(y, w float64) float64 { return y + 0.1 }
+
+// Variance returns μ.
+func (Poisson) Variance(mu float64) float64 { return mu }
+
+// UnitDeviance returns 2 (y log(y/μ) - (y-μ)).
+func (Poisson) UnitDeviance(y, mu float64) float64 {
+	return 2 * (xlogy(y, y/mu) - (y - mu))
+}
+
+// LogLikelihood returns w times the log-probability of the count y with
+// mean mu. The dispersion is ignored.
+func (Poisson) LogLikelihood(y, mu, w, phi float64) float64 {
+	lg, _ := math.Lgamma(y + 1)
+	return w * (xlogy(y, mu) - mu - lg)
+}
+
+// FixedDispersion returns true.
+func (Poisson) FixedDispersion() bool { return true }
+
+// Gamma is the gamma family for positive responses, with V(μ) = μ² and the
+// dispersion equal to the reciprocal of the shape parameter. Its canonical
+// link is Inverse.
+type Gamma struct{}
+
+// CanonicalLink returns Inverse.
+func (Gamma) CanonicalLink() Link { return Inverse{} }
+
+// ValidResponse returns whether y is positive and finite.
+func (Gamma) ValidResponse(y float64) bool { return 0 < y && !math.IsInf(y, 1) }
+
+// ValidMean returns whether mu is positive and finite.
+func (Gamma) ValidMean(mu float64) bool { return 0 < mu && !math.IsInf(mu, 1) }
+
+// InitialMean returns y.
+func (Gamma) InitialMean(y, w float64) float64 { return y }
+
+// Variance returns μ².
+func (Gamma) Variance(mu float64) float64 { return mu * mu }
+
+// UnitDeviance returns 2 ((y-μ)/μ - log(y/μ)).
+func (Gamma) UnitDeviance(y, mu float64) float64 {
+	return 2 * ((y-mu)/mu - math.Log(y/mu))
+}
+
+// LogLikelihood returns w times the log-density of y under the gamma
+// distribution with mean mu and shape 1/phi.
+func (Gamma) LogLikelihood(y, mu, w, phi float64) float64 {
+	shape := 1 / phi
+	scale := mu * phi
+	lg, _ := math.Lgamma(shape)
+	return w * (-lg - shape*math.Log(scale) + (shape-1)*math.Log(y) - y/scale)
+}
+
+// FixedDispersion returns false.
+func (Gamma) FixedDispersion() bool { return false }
+
+// xlogy returns x log(y), which is zero when x is zero.
+func xlogy(x, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	return x * math.Log(y)
+}
+
+// lchoose returns the log of the generalized binomial coefficient.
+func lchoose(n, k float64) float64 {
+	a, _ := math.Lgamma(n + 1)
+	b, _ := math.Lgamma(k + 1)
+	c, _ := math.Lgamma(n - k + 1)
+	return a - b - c
+}
@@ -0,0 +1,760 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glm
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// GLM is a generalized linear model. The fields of GLM specify the model
+// and how it is fitted, and the results of the fit are only valid if the
+// call to Fit was successful.
+//
+// References:
+//   - McCullagh, P. and Nelder, J. A. (1989). Generalized Linear Models,
+//     2nd edition. Chapman and Hall.
+type GLM struct {
+	// Family is the distribution family of the response.
+	// Family must not be nil.
+	Family Family
+
+	// Link is the link function of the model. If Link is
+	// nil, the canonical link of the family is used.
+	Link Link
+
+	// L1 and L2 are the weights of the L1 and L2 penalties on
+	// the coefficients, excluding the intercept. If either is
+	// non-zero, the coefficients minimize
+	//  D(β)/2 + L1 Σ|βⱼ| + L2/2 Σβⱼ²
+	// where D is the deviance of the model. L1 and L2 must not
+	// be negative.
+	L1, L2 float64
+
+	// Method is the optimization method used to fit penalized
+	// models. Method must support bounds if L1 is non-zero.
+	// If Method is nil, optimize.LBFGSB is used with a gradient
+	// threshold of 1e-6 times the sum of the weights if L1 is
+	// non-zero, and optimize.Newton is used with a threshold of
+	// 1e-8 times the sum of the weights otherwise.
+	// The Hessian provided to the method is the Fisher
+	// information of the penalized problem. Method is not used
+	// for unpenalized models, which are fitted by iteratively
+	// reweighted least squares.
+	Method optimize.Method
+
+	// Settings are the settings of the optimization of a
+	// penalized model. If Settings is nil, the default settings
+	// are used.
+	Settings *optimize.Settings
+
+	// MaxIterations is the maximum number of iterations of
+	// iteratively reweighted least squares. If MaxIterations
+	// is zero, it is defaulted to 25.
+	MaxIterations int
+
+	// Tolerance is the relative change in the deviance at which
+	// iteratively reweighted least squares is considered to
+	// have converged. If Tolerance is zero, it is defaulted
+	// to 1e-8.
+	Tolerance float64
+
+	// n is the number of rows of the design and
+	// nobs is the number of observations with a
+	// non-zero weight.
+	n, nobs int
+	// k is the number of columns of the design
+	// without the intercept and p is the number
+	// of coefficients.
+	k, p      int
+	intercept bool
+	link      Link
+
+	coef []float64
+	mu   []float64
+	// cov is the inverse of the Fisher information
+	// at unit dispersion.
+	cov *mat.SymDense
+
+	deviance, nullDeviance float64
+	dispersion             float64
+	aic                    float64
+	iterations             int
+
+	ok bool
+}
+
+// Fit fits the generalized linear model g(E[y]) = X β by maximum likelihood,
+// where X is the n×k design matrix x, and the model has an additional
+// intercept coefficient if intercept is true. The intercept is the first
+// coefficient of the fitted model.
+//
+// Unpenalized models are fitted by iteratively reweighted least squares,
+// solving each weighted least squares problem by a QR decomposition.
+// Penalized models are fitted by minimizing the penalized deviance with
+// m.Method. When L1 is non-zero each penalized coefficient is represented
+// as the difference of two non-negative variables, so that the penalty is
+// differentiable within the bounds.
+//
+// The weights slice is used to weight the observations. The weights are
+// prior weights, so the variance of the response with weight w is φ V(μ)/w,
+// and for the binomial family the weights are the numbers of trials. If
+// weights is nil, each weight is considered to have a value of one,
+// otherwise the length of weights must match the number of observations or
+// Fit will panic. Fit will also panic if the length of y does not match the
+// number of observations, if any weight is negative or if a response with
+// non-zero weight is not valid for the family. Observations with zero weight
+// do not contribute to the fit or to the degrees of freedom.
+//
+// Fit returns an error if there are not more observations with non-zero
+// weight than coefficients, if the weighted design matrix is rank deficient
+// or if the fit does not converge.
+func (m *GLM) Fit(x mat.Matrix, y, weights []float64, intercept bool) error {
+	if m.Family == nil {
+		panic("glm: nil family")
+	}
+	if m.L1 < 0 || m.L2 < 0 {
+		panic("glm: negative penalty")
+	}
+	n, k := x.Dims()
+	if len(y) != n {
+		panic("glm: len(y) != observations")
+	}
+	if weights != nil && len(weights) != n {
+		panic("glm: len(weights) != observations")
+	}
+	p := k
+	if intercept {
+		p++
+	}
+	w := make([]float64, n)
+	nobs := 0
+	for i := range w {
+		w[i] = 1
+		if weights != nil {
+			w[i] = weights[i]
+			if w[i] < 0 {
+				panic("glm: negative weight")
+			}
+		}
+		if w[i] != 0 {
+			if !m.Family.ValidResponse(y[i]) {
+				panic("glm: invalid response for family")
+			}
+			nobs++
+		}
+	}
+	m.ok = false
+	if nobs <= p {
+		return errors.New("glm: too few observations")
+	}
+
+	design := mat.NewDense(n, p, nil)
+	for i := 0; i < n; i++ {
+		j := 0
+		if intercept {
+			design.Set(i, 0, 1)
+			j = 1
+		}
+		for c := 0; c < k; c++ {
+			design.Set(i, j+c, x.At(i, c))
+		}
+	}
+
+	m.n, m.nobs, m.k, m.p = n, nobs, k, p
+	m.intercept = intercept
+	m.link = m.Link
+	if m.link == nil {
+		m.link = m.Family.CanonicalLink()
+	}
+	m.mu = resize(m.mu, n)
+
+	var err error
+	if m.L1 == 0 && m.L2 == 0 {
+		m.coef, m.iterations, err = m.irls(design, y, w)
+	} else {
+		m.coef, m.iterations, err = m.penalized(design, y, w)
+	}
+	if err != nil {
+		return err
+	}
+	m.means(m.mu, design, m.coef)
+	for i, mu := range m.mu {
+		if w[i] != 0 && !m.Family.ValidMean(mu) {
+			return errors.New("glm: invalid fitted mean")
+		}
+	}
+
+	// The inverse of the Fisher information is computed
+	// from the working weights at the fitted means.
+	sw := make([]float64, n)
+	m.workingWeights(sw, m.mu, w)
+	for i, v := range sw {
+		sw[i] = math.Sqrt(v)
+	}
+	var xw mat.Dense
+	xw.Mul(mat.NewDiagDense(n, sw), design)
+	var qr mat.QR
+	qr.Factorize(&xw)
+	var r mat.Dense
+	r.ReuseAs(p, p)
+	qr.RTo(&r)
+	rt := mat.NewTriDense(p, mat.Upper, nil)
+	for i := 0; i < p; i++ {
+		for j := i; j < p; j++ {
+			rt.SetTri(i, j, r.At(i, j))
+		}
+	}
+	var rinv mat.TriDense
+	err = rinv.InverseTri(rt)
+	if err != nil {
+		return err
+	}
+	m.cov = mat.NewSymDense(p, nil)
+	m.cov.SymOuterK(1, &rinv)
+
+	m.deviance = m.deviances(y, w, m.mu)
+	mu0 := m.link.Inverse(0)
+	if intercept {
+		mu0 = floats.Dot(w, y) / floats.Sum(w)
+	}
+	null := make([]float64, n)
+	for i := range null {
+		null[i] = mu0
+	}
+	m.nullDeviance = m.deviances(y, w, null)
+
+	// The dispersion is estimated by the Pearson statistic
+	// divided by the residual degrees of freedom, and the
+	// maximum likelihood dispersion used for the AIC is
+	// approximated by the deviance divided by the number of
+	// observations.
+	m.dispersion = 1
+	phi := 1.0
+	params := float64(p)
+	if !m.Family.FixedDispersion() {
+		var pearson float64
+		for i, v := range y {
+			if w[i] != 0 {
+				d := v - m.mu[i]
+				pearson += w[i] * d * d / m.Family.Variance(m.mu[i])
+			}
+		}
+		m.dispersion = pearson / float64(nobs-p)
+		phi = m.deviance / float64(nobs)
+		params++
+	}
+	var ll float64
+	for i, v := range y {
+		if w[i] != 0 {
+			ll += m.Family.LogLikelihood(v, m.mu[i], w[i], phi)
+		}
+	}
+	m.aic = -2*ll + 2*params
+
+	m.ok = true
+	return nil
+}
+
+// irls fits the unpenalized model by iteratively reweighted least squares.
+func (m *GLM) irls(design *mat.Dense, y, w []float64) (beta []float64, iter int, err error) {
+	maxIter := m.MaxIterations
+	if maxIter == 0 {
+		maxIter = 25
+	}
+	tol := m.Tolerance
+	if tol == 0 {
+		tol = 1e-8
+	}
+	n, p := design.Dims()
+	fam := m.Family
+	link := m.link
+
+	// Observations with zero weight may have an invalid
+	// response, so they start at the mean response.
+	ybar := floats.Dot(w, y) / floats.Sum(w)
+	mu := make([]float64, n)
+	eta := make([]float64, n)
+	for i, v := range y {
+		if w[i] == 0 {
+			mu[i] = fam.InitialMean(ybar, 1)
+		} else {
+			mu[i] = fam.InitialMean(v, w[i])
+		}
+		eta[i] = link.Func(mu[i])
+	}
+	dev := m.deviances(y, w, mu)
+
+	z := make([]float64, n)
+	sw := make([]float64, n)
+	xw := mat.NewDense(n, p, nil)
+	var prev []float64
+	for iter = 1; iter <= maxIter; iter++ {
+		// Solve the weighted least squares problem for the
+		// working response.
+		m.workingWeights(sw, mu, w)
+		for i := range sw {
+			g := link.Deriv(mu[i])
+			s := math.Sqrt(sw[i])
+			z[i] = s * (eta[i] + (y[i]-mu[i])*g)
+			floats.ScaleTo(xw.RawRowView(i), s, design.RawRowView(i))
+		}
+		var qr mat.QR
+		qr.Factorize(xw)
+		var b mat.VecDense
+		err = qr.SolveVecTo(&b, false, mat.NewVecDense(n, z))
+		if err != nil {
+			return nil, iter, err
+		}
+		beta = mat.Col(beta, 0, &b)
+
+		// Halve the step while the fitted means are not valid.
+		var newDev float64
+		for {
+			m.means(mu, design, beta)
+			valid := true
+			for i, v := range mu {
+				if w[i] != 0 && !fam.ValidMean(v) {
+					valid = false
+					break
+				}
+			}
+			newDev = m.deviances(y, w, mu)
+			if valid && !math.IsInf(newDev, 0) && !math.IsNaN(newDev) {
+				break
+			}
+			if prev == nil {
+				return nil, iter, errors.New("glm: no valid coefficients found")
+			}
+			for j := range beta {
+				beta[j] = (beta[j] + prev[j]) / 2
+			}
+		}
+		for i := range eta {
+			eta[i] = link.Func(mu[i])
+		}
+		if math.Abs(newDev-dev)/(math.Abs(newDev)+0.1) < tol {
+			return beta, iter, nil
+		}
+		dev = newDev
+		prev = append(prev[:0], beta...)
+	}
+	return nil, maxIter, errors.New("glm: iteratively reweighted least squares did not converge")
+}
+
+// penalized fits the penalized model by minimizing the penalized
+// deviance.
+func (m *GLM) penalized(design *mat.Dense, y, w []float64) (beta []float64, iter int, err error) {
+	problem, x0, coef := m.penalizedProblem(design, y, w)
+	method := m.Method
+	if method == nil {
+		// The gradient of the deviance is a sum over the
+		// observations, so the threshold is scaled by the
+		// total weight. The quasi-Newton method cannot reach
+		// as small a gradient as Newton's method before the
+		// changes in the function value are lost to rounding.
+		sumw := floats.Sum(w)
+		if m.L1 == 0 {
+			method = &optimize.Newton{GradStopThreshold: 1e-8 * sumw}
+		} else {
+			method = &optimize.LBFGSB{GradStopThreshold: 1e-6 * sumw}
+		}
+	}
+	res, err := optimize.Minimize(problem, x0, m.Settings, method)
+	if err != nil {
+		return nil, 0, err
+	}
+	_, p := design.Dims()
+	beta = make([]float64, p)
+	coef(beta, res.X)
+	return beta, res.MajorIterations, nil
+}
+
+// penalizedProblem returns the minimization problem of the penalized
+// deviance, its starting location and a function that computes the
+// coefficients from a location.
+func (m *GLM) penalizedProblem(design *mat.Dense, y, w []float64) (problem optimize.Problem, x0 []float64, coef func(dst, x []float64)) {
+	n, p := design.Dims()
+	fam := m.Family
+	link := m.link
+	first := 0
+	if m.intercept {
+		first = 1
+	}
+	split := m.L1 != 0
+
+	// The optimization variables are the coefficients, and when
+	// the L1 penalty is used, the penalized coefficients are
+	// represented by their positive and negative parts that
+	// follow the unpenalized coefficients.
+	dim := p
+	if split {
+		dim = first + 2*(p-first)
+	}
+	coef = func(dst, x []float64) {
+		if !split {
+			copy(dst, x)
+			return
+		}
+		copy(dst[:first], x[:first])
+		q := p - first
+		for j := 0; j < q; j++ {
+			dst[first+j] = x[first+j] - x[first+q+j]
+		}
+	}
+
+	mu := make([]float64, n)
+	b := make([]float64, p)
+	score := make([]float64, n)
+	problem = optimize.Problem{
+		Func: func(x []float64) float64 {
+			coef(b, x)
+			m.means(mu, design, b)
+			for i, v := range mu {
+				if w[i] != 0 && !fam.ValidMean(v) {
+					return math.Inf(1)
+				}
+			}
+			f := m.deviances(y, w, mu) / 2
+			for j := first; j < p; j++ {
+				f += m.L2 / 2 * b[j] * b[j]
+			}
+			if split {
+				for _, v := range x[first:] {
+					f += m.L1 * v
+				}
+			}
+			return f
+		},
+		Grad: func(grad, x []float64) {
+			coef(b, x)
+			m.means(mu, design, b)
+			// The derivative of half the deviance with respect
+			// to the linear predictor.
+			for i, v := range mu {
+				if w[i] == 0 {
+					score[i] = 0
+					continue
+				}
+				score[i] = -w[i] * (y[i] - v) / (fam.Variance(v) * link.Deriv(v))
+			}
+			g := make([]float64, p)
+			mat.NewVecDense(p, g).MulVec(design.T(), mat.NewVecDense(n, score))
+			for j := first; j < p; j++ {
+				g[j] += m.L2 * b[j]
+			}
+			if !split {
+				copy(grad, g)
+				return
+			}
+			copy(grad[:first], g[:first])
+			q := p - first
+			for j := 0; j < q; j++ {
+				grad[first+j] = g[first+j] + m.L1
+				grad[first+q+j] = -g[first+j] + m.L1
+			}
+		},
+		Hess: func(hess *mat.SymDense, x []float64) {
+			// The Hessian is approximated by the Fisher
+			// information, which is exact for canonical links.
+			coef(b, x)
+			m.means(mu, design, b)
+			h := mat.NewSymDense(p, nil)
+			for i, v := range mu {
+				if w[i] == 0 {
+					continue
+				}
+				g := link.Deriv(v)
+				h.SymRankOne(h, w[i]/(fam.Variance(v)*g*g), mat.NewVecDense(p, design.RawRowView(i)))
+			}
+			for j := first; j < p; j++ {
+				h.SetSym(j, j, h.At(j, j)+m.L2)
+			}
+			if !split {
+				hess.CopySym(h)
+				return
+			}
+			// The Hessian with respect to the positive and
+			// negative parts of the penalized coefficients.
+			q := p - first
+			sign := func(j int) (int, float64) {
+				if j < first+q {
+					return j, 1
+				}
+				return j - q, -1
+			}
+			for i := 0; i < dim; i++ {
+				ci, si := sign(i)
+				for j := i; j < dim; j++ {
+					cj, sj := sign(j)
+					hess.SetSym(i, j, si*sj*h.At(ci, cj))
+				}
+			}
+		},
+	}
+	if split {
+		problem.Bounds = make([]optimize.Bound, dim)
+		for j := range problem.Bounds {
+			problem.Bounds[j] = optimize.Bound{Min: 0, Max: math.Inf(1)}
+			if j < first {
+				problem.Bounds[j].Min = math.Inf(-1)
+			}
+		}
+	}
+
+	// Start from the mean response for the intercept and
+	// zero for the penalized coefficients.
+	x0 = make([]float64, dim)
+	if m.intercept {
+		x0[0] = link.Func(floats.Dot(w, y) / floats.Sum(w))
+	}
+	return problem, x0, coef
+}
+
+// means computes the means of the observations for the coefficients beta.
+func (m *GLM) means(dst []float64, design *mat.Dense, beta []float64) {
+	for i := range dst {
+		dst[i] = m.link.Inverse(floats.Dot(design.RawRowView(i), beta))
+	}
+}
+
+// workingWeights computes the working weights of iteratively reweighted
+// least squares at the means mu.
+func (m *GLM) workingWeights(dst, mu, w []float64) {
+	for i, mu := range mu {
+		if w[i] == 0 {
+			dst[i] = 0
+			continue
+		}
+		g := m.link.Deriv(mu)
+		dst[i] = w[i] / (m.Family.Variance(mu) * g * g)
+	}
+}
+
+// deviances returns the deviance of the observations with means mu.
+func (m *GLM) deviances(y, w, mu []float64) float64 {
+	var dev float64
+	for i, v := range y {
+		if w[i] != 0 {
+			dev += w[i] * m.Family.UnitDeviance(v, mu[i])
+		}
+	}
+	return dev
+}
+
+func (m *GLM) checkOK() {
+	if !m.ok {
+		panic("glm: use of unsuccessful fit")
+	}
+}
+
+// Coefficients returns the estimated coefficients of the model. If the
+// model has an intercept it is the first coefficient.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients,
+// Coefficients will panic. Coefficients will also panic if the receiver
+// does not contain a successful fit.
+func (m *GLM) Coefficients(dst []float64) []float64 {
+	m.checkOK()
+	dst = useSlice(dst, m.p)
+	copy(dst, m.coef)
+	return dst
+}
+
+// Fitted returns the fitted means of the observations.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of observations, Fitted
+// will panic. Fitted will also panic if the receiver does not contain a
+// successful fit.
+func (m *GLM) Fitted(dst []float64) []float64 {
+	m.checkOK()
+	dst = useSlice(dst, m.n)
+	copy(dst, m.mu)
+	return dst
+}
+
+// Predict returns the predicted means of the model for the rows of the
+// design matrix x, which must have the same number of columns as the design
+// matrix used in the call to Fit.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of rows of x, Predict will
+// panic. Predict will also panic if the receiver does not contain a
+// successful fit.
+func (m *GLM) Predict(dst []float64, x mat.Matrix) []float64 {
+	m.checkOK()
+	r, c := x.Dims()
+	if c != m.k {
+		panic(mat.ErrShape)
+	}
+	dst = useSlice(dst, r)
+	for i := range dst {
+		var eta float64
+		j := 0
+		if m.intercept {
+			eta = m.coef[0]
+			j = 1
+		}
+		for c := 0; c < m.k; c++ {
+			eta += m.coef[j+c] * x.At(i, c)
+		}
+		dst[i] = m.link.Inverse(eta)
+	}
+	return dst
+}
+
+// Iterations returns the number of iterations of iteratively reweighted
+// least squares, or the number of major iterations of the optimization of
+// a penalized model, used by the fit. Iterations will panic if the receiver
+// does not contain a successful fit.
+func (m *GLM) Iterations() int {
+	m.checkOK()
+	return m.iterations
+}
+
+// Deviance returns the deviance of the fitted model, Σ wᵢ d(yᵢ, μᵢ).
+// Deviance will panic if the receiver does not contain a successful fit.
+func (m *GLM) Deviance() float64 {
+	m.checkOK()
+	return m.deviance
+}
+
+// NullDeviance returns the deviance of the null model. If the model has an
+// intercept, the null model has only an intercept, otherwise the linear
+// predictor of the null model is zero. NullDeviance will panic if the
+// receiver does not contain a successful fit.
+func (m *GLM) NullDeviance() float64 {
+	m.checkOK()
+	return m.nullDeviance
+}
+
+// ResidualDF returns the residual degrees of freedom of the model, the
+// number of observations with non-zero weight less the number of
+// coefficients. ResidualDF will panic if the receiver does not contain a
+// successful fit.
+func (m *GLM) ResidualDF() float64 {
+	m.checkOK()
+	return float64(m.nobs - m.p)
+}
+
+// Dispersion returns the estimate of the dispersion parameter φ of the
+// model. For families with a fixed dispersion Dispersion returns one,
+// otherwise it returns the Pearson statistic Σ wᵢ (yᵢ-μᵢ)²/V(μᵢ) divided by
+// the residual degrees of freedom. Dispersion will panic if the receiver
+// does not contain a successful fit.
+func (m *GLM) Dispersion() float64 {
+	m.checkOK()
+	return m.dispersion
+}
+
+// AIC returns Akaike's information criterion of the model, -2 log L + 2 k,
+// where L is the likelihood of the fitted model and k is the number of
+// coefficients, plus one for families without a fixed dispersion. The
+// likelihood of families without a fixed dispersion is evaluated at the
+// dispersion D/n, where D is the deviance and n is the number of
+// observations with non-zero weight. AIC will panic if the receiver does
+// not contain a successful fit.
+func (m *GLM) AIC() float64 {
+	m.checkOK()
+	return m.aic
+}
+
+// CovarianceMatrix stores the estimate of the covariance matrix of the
+// coefficients, φ (Xᵀ W X)⁻¹, into dst, where W is the diagonal matrix of
+// the working weights at the fitted means and φ is the estimated
+// dispersion. For penalized models the covariance matrix does not account
+// for the penalty.
+//
+// The dst matrix must either be empty or have the same number of rows as
+// the number of coefficients. CovarianceMatrix will panic if the receiver
+// does not contain a successful fit.
+func (m *GLM) CovarianceMatrix(dst *mat.SymDense) {
+	m.checkOK()
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(m.p)
+	} else if dst.SymmetricDim() != m.p {
+		panic(mat.ErrShape)
+	}
+	dst.ScaleSym(m.dispersion, m.cov)
+}
+
+// StdErrs returns the standard errors of the coefficients of the model.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients, StdErrs
+// will panic. StdErrs will also panic if the receiver does not contain a
+// successful fit.
+func (m *GLM) StdErrs(dst []float64) []float64 {
+	m.checkOK()
+	dst = useSlice(dst, m.p)
+	for i := range dst {
+		dst[i] = math.Sqrt(m.dispersion * m.cov.At(i, i))
+	}
+	return dst
+}
+
+// WaldStats returns the Wald statistics of the coefficients of the model,
+// the ratios of the coefficients to their standard errors.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients,
+// WaldStats will panic. WaldStats will also panic if the receiver does not
+// contain a successful fit.
+func (m *GLM) WaldStats(dst []float64) []float64 {
+	dst = m.StdErrs(dst)
+	for i, se := range dst {
+		dst[i] = m.coef[i] / se
+	}
+	return dst
+}
+
+// PValues returns the two-sided p-values of the Wald tests of the null
+// hypotheses that each coefficient of the model is zero. For families with
+// a fixed dispersion the null distribution of the Wald statistics is the
+// standard normal distribution, otherwise it is Student's t distribution
+// with the residual degrees of freedom of the model.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients, PValues
+// will panic. PValues will also panic if the receiver does not contain a
+// successful fit.
+func (m *GLM) PValues(dst []float64) []float64 {
+	dst = m.WaldStats(dst)
+	var dist interface{ Survival(float64) float64 } = distuv.UnitNormal
+	if !m.Family.FixedDispersion() {
+		dist = distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(m.nobs - m.p)}
+	}
+	for i, v := range dst {
+		dst[i] = 2 * dist.Survival(math.Abs(v))
+	}
+	return dst
+}
+
+// useSlice returns a slice of length n, allocating if dst is nil and
+// panicking if dst is not nil and has a different length.
+func useSlice(dst []float64, n int) []float64 {
+	if dst == nil {
+		return make([]float64, n)
+	}
+	if len(dst) != n {
+		panic("glm: destination length mismatch")
+	}
+	return dst
+}
+
+// resize returns a slice of length n, reusing the storage of s if possible.
+func resize(s []float64, n int) []float64 {
+	if cap(s) < n {
+		return make([]float64, n)
+	}
+	return s[:n]
+}
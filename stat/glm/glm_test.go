@@ -0,0 +1,426 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glm
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/gonum/stat/regression"
+)
+
+func TestPoissonDobson(t *testing.T) {
+	t.Parallel()
+	// The randomized controlled trial of Dobson (1990), with
+	// reference values from the examples of R's glm.
+	counts := []float64{18, 17, 15, 20, 10, 20, 25, 13, 12}
+	// Indicators of outcomes 2 and 3 and of treatments 2 and 3.
+	x := mat.NewDense(9, 4, []float64{
+		0, 0, 0, 0,
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		1, 0, 1, 0,
+		0, 1, 1, 0,
+		0, 0, 0, 1,
+		1, 0, 0, 1,
+		0, 1, 0, 1,
+	})
+	m := GLM{Family: Poisson{}}
+	err := m.Fit(x, counts, nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The treatment totals are equal, so the coefficients are
+	// the logs of the ratios of the outcome means.
+	want := []float64{math.Log(21), math.Log(40.0 / 63), math.Log(47.0 / 63), 0, 0}
+	if got := m.Coefficients(nil); !floats.EqualApprox(got, want, 1e-10) {
+		t.Errorf("unexpected coefficients: got:%v want:%v", got, want)
+	}
+	if got := m.StdErrs(nil); !floats.EqualApprox(got, []float64{0.1709, 0.2022, 0.1927, 0.2000, 0.2000}, 5e-5) {
+		t.Errorf("unexpected standard errors: got:%v want:[0.1709 0.2022 0.1927 0.2000 0.2000]", got)
+	}
+	if got := m.WaldStats(nil); !scalar.EqualWithinAbs(got[1], -2.247, 5e-4) || !scalar.EqualWithinAbs(got[2], -1.520, 5e-4) {
+		t.Errorf("unexpected Wald statistics: got:%v want:[_ -2.247 -1.520 0 0]", got)
+	}
+	if got := m.PValues(nil); !scalar.EqualWithinAbs(got[1], 0.0246, 5e-5) || !scalar.EqualWithinAbs(got[2], 0.1285, 5e-5) {
+		t.Errorf("unexpected p-values: got:%v want:[_ 0.0246 0.1285 1 1]", got)
+	}
+	if got := m.NullDeviance(); !scalar.EqualWithinAbs(got, 10.5814, 5e-5) {
+		t.Errorf("unexpected null deviance: got:%v want:10.5814", got)
+	}
+	if got := m.Deviance(); !scalar.EqualWithinAbs(got, 5.1291, 5e-5) || m.ResidualDF() != 4 {
+		t.Errorf("unexpected deviance: got:%v on %v df want:5.1291 on 4 df", got, m.ResidualDF())
+	}
+	if got := m.AIC(); !scalar.EqualWithinAbs(got, 56.761, 5e-4) {
+		t.Errorf("unexpected AIC: got:%v want:56.761", got)
+	}
+	if got := m.Iterations(); got != 4 {
+		t.Errorf("unexpected number of iterations: got:%d want:4", got)
+	}
+	if m.Dispersion() != 1 {
+		t.Errorf("unexpected dispersion: got:%v want:1", m.Dispersion())
+	}
+}
+
+func TestBinomialTable(t *testing.T) {
+	t.Parallel()
+	// Logistic regression on a binary regressor, with the
+	// responses given as proportions of the numbers of trials.
+	// The coefficients are the log odds and log odds ratio of
+	// the 2×2 table of successes and failures.
+	const a, b, c, d = 12.0, 8.0, 5.0, 15.0
+	x := mat.NewDense(2, 1, []float64{0, 1})
+	y := []float64{a / (a + b), c / (c + d)}
+	w := []float64{a + b, c + d}
+	m := GLM{Family: Binomial{}}
+	err := m.Fit(x, y, w, true)
+	if err == nil {
+		t.Fatal("expected error for saturated model")
+	}
+
+	// Expand the table into Bernoulli observations.
+	var xs, ys []float64
+	for _, cell := range []struct {
+		x, y  float64
+		count float64
+	}{{0, 1, a}, {0, 0, b}, {1, 1, c}, {1, 0, d}} {
+		for i := 0; i < int(cell.count); i++ {
+			xs = append(xs, cell.x)
+			ys = append(ys, cell.y)
+		}
+	}
+	err = m.Fit(mat.NewDense(len(xs), 1, xs), ys, nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []float64{math.Log(a / b), math.Log(b * c / (a * d))}
+	if got := m.Coefficients(nil); !floats.EqualApprox(got, want, 1e-10) {
+		t.Errorf("unexpected coefficients: got:%v want:%v", got, want)
+	}
+	wantSE := []float64{math.Sqrt(1/a + 1/b), math.Sqrt(1/a + 1/b + 1/c + 1/d)}
+	if got := m.StdErrs(nil); !floats.EqualApprox(got, wantSE, 1e-8) {
+		t.Errorf("unexpected standard errors: got:%v want:%v", got, wantSE)
+	}
+	// The log-likelihood of the saturated model of Bernoulli
+	// observations is zero.
+	if got := m.AIC(); !scalar.EqualWithinAbs(got, m.Deviance()+4, 1e-10) {
+		t.Errorf("unexpected AIC: got:%v want:%v", got, m.Deviance()+4)
+	}
+}
+
+func TestGaussianLinear(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	n, k := 30, 3
+	x, y, w := randomProblem(rnd, Gaussian{}, Identity{}, n, k)
+	m := GLM{Family: Gaussian{}}
+	err := m.Fit(x, y, w, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var l regression.Linear
+	err = l.Fit(x, y, w, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := m.Coefficients(nil), l.Coefficients(nil); !floats.EqualApprox(got, want, 1e-10) {
+		t.Errorf("unexpected coefficients: got:%v want:%v", got, want)
+	}
+	if got, want := m.StdErrs(nil), l.StdErrs(nil, regression.Classical); !floats.EqualApprox(got, want, 1e-10) {
+		t.Errorf("unexpected standard errors: got:%v want:%v", got, want)
+	}
+	if got, want := m.PValues(nil), l.PValues(nil, regression.Classical); !floats.EqualApprox(got, want, 1e-10) {
+		t.Errorf("unexpected p-values: got:%v want:%v", got, want)
+	}
+	if !scalar.EqualWithinRel(m.Dispersion(), l.ResidualVariance(), 1e-10) {
+		t.Errorf("unexpected dispersion: got:%v want:%v", m.Dispersion(), l.ResidualVariance())
+	}
+	if m.Iterations() != 2 {
+		t.Errorf("unexpected number of iterations: got:%d want:2", m.Iterations())
+	}
+
+	// The AIC of the Gaussian model with weights.
+	var nobs, sumlogw float64
+	for _, v := range w {
+		if v != 0 {
+			nobs++
+			sumlogw += math.Log(v)
+		}
+	}
+	dev := m.Deviance()
+	want := nobs*(math.Log(2*math.Pi*dev/nobs)+1) - sumlogw + 2*float64(k+2)
+	if !scalar.EqualWithinAbs(m.AIC(), want, 1e-10) {
+		t.Errorf("unexpected AIC: got:%v want:%v", m.AIC(), want)
+	}
+}
+
+// randomProblem returns a random weighted problem with n observations and
+// k regressors with responses from the given family and link. The first
+// observation has zero weight.
+func randomProblem(rnd *rand.Rand, fam Family, link Link, n, k int) (x *mat.Dense, y, w []float64) {
+	x = mat.NewDense(n, k, nil)
+	y = make([]float64, n)
+	w = make([]float64, n)
+	src := rand.NewSource(rnd.Uint64())
+	for i := 0; i < n; i++ {
+		eta := 0.0
+		for j := 0; j < k; j++ {
+			v := rnd.Float64()
+			x.Set(i, j, v)
+			eta += float64(j+1) / float64(k) * v
+		}
+		w[i] = 1 + float64(rnd.Intn(3))
+		switch fam.(type) {
+		case Gaussian:
+			y[i] = link.Inverse(eta) + 0.1*rnd.NormFloat64()
+		case Binomial:
+			// The mean is centred on one half.
+			mu := link.Inverse(eta - float64(k+1)/4)
+			y[i] = distuv.Binomial{N: w[i], P: mu, Src: src}.Rand() / w[i]
+		case Poisson:
+			y[i] = distuv.Poisson{Lambda: 2 * link.Inverse(eta+1), Src: src}.Rand()
+		case Gamma:
+			mu := link.Inverse(eta + 1)
+			y[i] = distuv.Gamma{Alpha: 2, Beta: 2 / mu, Src: src}.Rand()
+		}
+	}
+	w[0] = 0
+	y[0] = -1 // An invalid response is ignored.
+	return x, y, w
+}
+
+// score returns the derivative of half the deviance of the model with
+// respect to its coefficients.
+func score(m *GLM, x mat.Matrix, y, w []float64, intercept bool) []float64 {
+	link := m.Link
+	if link == nil {
+		link = m.Family.CanonicalLink()
+	}
+	mu := m.Fitted(nil)
+	n, k := x.Dims()
+	p := k
+	if intercept {
+		p++
+	}
+	g := make([]float64, p)
+	for i := 0; i < n; i++ {
+		if w[i] == 0 {
+			continue
+		}
+		s := -w[i] * (y[i] - mu[i]) / (m.Family.Variance(mu[i]) * link.Deriv(mu[i]))
+		j := 0
+		if intercept {
+			g[0] += s
+			j = 1
+		}
+		for c := 0; c < k; c++ {
+			g[j+c] += s * x.At(i, c)
+		}
+	}
+	return g
+}
+
+var familyTests = []struct {
+	family Family
+	link   Link
+}{
+	{family: Gaussian{}},
+	{family: Gaussian{}, link: Log{}},
+	{family: Binomial{}},
+	{family: Poisson{}},
+	{family: Poisson{}, link: Identity{}},
+	{family: Gamma{}},
+	{family: Gamma{}, link: Log{}},
+}
+
+func TestGLMScore(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range familyTests {
+		for _, intercept := range []bool{true, false} {
+			name := fmt.Sprintf("%T/%T intercept=%t", test.family, test.link, intercept)
+			link := test.link
+			if link == nil {
+				link = test.family.CanonicalLink()
+			}
+			x, y, w := randomProblem(rnd, test.family, link, 100, 2)
+			m := GLM{Family: test.family, Link: test.link, Tolerance: 1e-12}
+			err := m.Fit(x, y, w, intercept)
+			if err != nil {
+				t.Errorf("unexpected error for %s: %v", name, err)
+				continue
+			}
+			// The maximum likelihood estimates solve the score
+			// equations.
+			if g := score(&m, x, y, w, intercept); floats.Norm(g, math.Inf(1)) > 1e-5 {
+				t.Errorf("score not zero at estimate for %s: %v", name, g)
+			}
+			if !(m.Deviance() <= m.NullDeviance()+1e-10) && intercept {
+				t.Errorf("deviance greater than null deviance for %s: %v > %v", name, m.Deviance(), m.NullDeviance())
+			}
+			if got := m.Predict(nil, x); !floats.EqualApprox(got, m.Fitted(nil), 1e-12) {
+				t.Errorf("prediction does not match fitted values for %s", name)
+			}
+
+			// The covariance matrix is the inverse of the Fisher
+			// information scaled by the dispersion.
+			mu := m.Fitted(nil)
+			n, k := x.Dims()
+			p := k
+			if intercept {
+				p++
+			}
+			info := mat.NewSymDense(p, nil)
+			row := make([]float64, p)
+			for i := 0; i < n; i++ {
+				if w[i] == 0 {
+					continue
+				}
+				j := 0
+				if intercept {
+					row[0] = 1
+					j = 1
+				}
+				for c := 0; c < k; c++ {
+					row[j+c] = x.At(i, c)
+				}
+				g := link.Deriv(mu[i])
+				info.SymRankOne(info, w[i]/(test.family.Variance(mu[i])*g*g), mat.NewVecDense(p, row))
+			}
+			var inv mat.Dense
+			err = inv.Inverse(info)
+			if err != nil {
+				t.Fatalf("unexpected error inverting information for %s: %v", name, err)
+			}
+			inv.Scale(m.Dispersion(), &inv)
+			var cov mat.SymDense
+			m.CovarianceMatrix(&cov)
+			if !mat.EqualApprox(&cov, &inv, 1e-8) {
+				t.Errorf("unexpected covariance matrix for %s", name)
+			}
+		}
+	}
+}
+
+func TestGLMPenalized(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range familyTests {
+		if test.link != nil {
+			continue
+		}
+		x, y, w := randomProblem(rnd, test.family, test.family.CanonicalLink(), 200, 4)
+		for _, pen := range []struct{ l1, l2 float64 }{
+			{l1: 0, l2: 5},
+			{l1: 2, l2: 0},
+			{l1: 2, l2: 5},
+			{l1: 1e4, l2: 0},
+		} {
+			name := fmt.Sprintf("%T L1=%v L2=%v", test.family, pen.l1, pen.l2)
+			m := GLM{
+				Family: test.family,
+				L1:     pen.l1,
+				L2:     pen.l2,
+			}
+			err := m.Fit(x, y, w, true)
+			if err != nil {
+				t.Errorf("unexpected error for %s: %v", name, err)
+				continue
+			}
+			// The estimates satisfy the optimality conditions of
+			// the penalized problem.
+			coef := m.Coefficients(nil)
+			g := score(&m, x, y, w, true)
+			if math.Abs(g[0]) > 1e-6*floats.Sum(w) {
+				t.Errorf("intercept score not zero for %s: %v", name, g[0])
+			}
+			for j := 1; j < len(g); j++ {
+				g[j] += pen.l2 * coef[j]
+				switch {
+				case coef[j] > 0:
+					g[j] += pen.l1
+				case coef[j] < 0:
+					g[j] -= pen.l1
+				default:
+					if math.Abs(g[j]) <= pen.l1 {
+						g[j] = 0
+					}
+				}
+				if math.Abs(g[j]) > 1e-6*floats.Sum(w) {
+					t.Errorf("optimality condition not satisfied for coefficient %d for %s: %v", j, name, g[j])
+				}
+			}
+			if pen.l1 == 1e4 {
+				for _, v := range coef[1:] {
+					if v != 0 {
+						t.Errorf("expected zero coefficients for %s: %v", name, coef)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGaussianRidge(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, y, _ := randomProblem(rnd, Gaussian{}, Identity{}, 50, 3)
+	y[0] = 0
+	const lambda = 3.0
+	m := GLM{Family: Gaussian{}, L2: lambda, Method: &optimize.Newton{}}
+	err := m.Fit(x, y, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The ridge estimate solves (XᵀX + λI) β = Xᵀy.
+	var a mat.Dense
+	a.Mul(x.T(), x)
+	for i := 0; i < 3; i++ {
+		a.Set(i, i, a.At(i, i)+lambda)
+	}
+	var b, want mat.VecDense
+	b.MulVec(x.T(), mat.NewVecDense(len(y), y))
+	err = want.SolveVec(&a, &b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := m.Coefficients(nil); !floats.EqualApprox(got, want.RawVector().Data, 1e-8) {
+		t.Errorf("unexpected ridge coefficients: got:%v want:%v", got, want.RawVector().Data)
+	}
+}
+
+func TestGLMFailure(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(4, 2, []float64{1, 2, 2, 4, 3, 6, 4, 8})
+	m := GLM{Family: Binomial{}}
+	if err := m.Fit(x, []float64{0, 1, 0, 1}, nil, true); err == nil {
+		t.Error("expected error for rank deficient design")
+	}
+	if !panics(func() { m.Coefficients(nil) }) {
+		t.Error("expected panic for use of unsuccessful fit")
+	}
+	if !panics(func() { _ = m.Fit(x, []float64{0, 2, 1, 1}, nil, false) }) {
+		t.Error("expected panic for invalid response")
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return panicked
+}
@@ -0,0 +1,81 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glm
+
+import "math"
+
+// Link is a link function g relating the mean μ of the response to the
+// linear predictor η = g(μ).
+type Link interface {
+	// Func returns the linear predictor g(μ).
+	Func(mu float64) float64
+
+	// Inverse returns the mean g⁻¹(η).
+	Inverse(eta float64) float64
+
+	// Deriv returns the derivative of the link function,
+	// dη/dμ = g'(μ).
+	Deriv(mu float64) float64
+}
+
+// Identity is the identity link function, g(μ) = μ. It is the canonical
+// link of the Gaussian family.
+type Identity struct{}
+
+// Func returns μ.
+func (Identity) Func(mu float64) float64 { return mu }
+
+// Inverse returns η.
+func (Identity) Inverse(eta float64) float64 { return eta }
+
+// Deriv returns 1.
+func (Identity) Deriv(mu float64) float64 { return 1 }
+
+// Log is the log link function, g(μ) = log(μ). It is the canonical link
+// of the Poisson family.
+type Log struct{}
+
+// Func returns log(μ).
+func (Log) Func(mu float64) float64 { return math.Log(mu) }
+
+// Inverse returns exp(η), bounded below by the machine epsilon.
+func (Log) Inverse(eta float64) float64 { return math.Max(math.Exp(eta), eps) }
+
+// Deriv returns 1/μ.
+func (Log) Deriv(mu float64) float64 { return 1 / mu }
+
+// Logit is the logit link function, g(μ) = log(μ/(1-μ)). It is the
+// canonical link of the binomial family.
+type Logit struct{}
+
+// Func returns log(μ/(1-μ)).
+func (Logit) Func(mu float64) float64 { return math.Log(mu / (1 - mu)) }
+
+// Inverse returns 1/(1+exp(-η)), bounded within the machine epsilon of
+// zero and one.
+func (Logit) Inverse(eta float64) float64 {
+	mu := 1 / (1 + math.Exp(-eta))
+	return math.Min(math.Max(mu, eps), 1-eps)
+}
+
+// Deriv returns 1/(μ(1-μ)).
+func (Logit) Deriv(mu float64) float64 { return 1 / (mu * (1 - mu)) }
+
+// Inverse is the inverse link function, g(μ) = 1/μ. It is the canonical
+// link of the gamma family.
+type Inverse struct{}
+
+// Func returns 1/μ.
+func (Inverse) Func(mu float64) float64 { return 1 / mu }
+
+// Inverse returns 1/η.
+func (Inverse) Inverse(eta float64) float64 { return 1 / eta }
+
+// Deriv returns -1/μ².
+func (Inverse) Deriv(mu float64) float64 { return -1 / (mu * mu) }
+
+// eps is the machine epsilon used to keep means away from the boundary
+// of their domain.
+const eps = 2.220446049250313e-16
@@ -0,0 +1,211 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"sort"
+)
+
+// Bonferroni returns the p-values adjusted for multiple comparisons by the
+// Bonferroni correction, min(1, m p[i]) for m p-values, controlling the
+// family-wise error rate.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of p, Bonferroni will
+// panic. dst and p may be the same slice. Bonferroni will panic if any
+// p-value is not in [0, 1].
+func Bonferroni(dst, p []float64) []float64 {
+	dst = adjustDst(dst, p)
+	m := float64(len(p))
+	for i, v := range p {
+		dst[i] = math.Min(1, m*v)
+	}
+	return dst
+}
+
+// Holm returns the p-values adjusted for multiple comparisons by Holm's
+// step-down procedure, controlling the family-wise error rate. The i-th
+// smallest of m p-values is multiplied by m-i+1, and the adjusted p-values
+// are made monotone in the order of the p-values. Holm's procedure is
+// uniformly more powerful than the Bonferroni correction.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of p, Holm will panic.
+// dst and p may be the same slice. Holm will panic if any p-value is not
+// in [0, 1].
+//
+// References:
+//   - Holm, S. (1979). A simple sequentially rejective multiple test
+//     procedure. Scandinavian Journal of Statistics, 6(2), 65-70.
+func Holm(dst, p []float64) []float64 {
+	m := float64(len(p))
+	return stepDown(adjustDst(dst, p), p, func(i int) float64 { return m - float64(i) + 1 })
+}
+
+// Hochberg returns the p-values adjusted for multiple comparisons by
+// Hochberg's step-up procedure, controlling the family-wise error rate
+// when the tests are independent. The i-th smallest of m p-values is
+// multiplied by m-i+1, and the adjusted p-values are made monotone in the
+// order of the p-values starting from the largest.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of p, Hochberg will
+// panic. dst and p may be the same slice. Hochberg will panic if any
+// p-value is not in [0, 1].
+//
+// References:
+//   - Hochberg, Y. (1988). A sharper Bonferroni procedure for multiple
+//     tests of significance. Biometrika, 75(4), 800-802.
+func Hochberg(dst, p []float64) []float64 {
+	m := float64(len(p))
+	return stepUp(adjustDst(dst, p), p, func(i int) float64 { return m - float64(i) + 1 })
+}
+
+// BenjaminiHochberg returns the p-values adjusted for multiple comparisons
+// by the Benjamini-Hochberg step-up procedure, controlling the false
+// discovery rate when the tests are independent or positively dependent.
+// The i-th smallest of m p-values is multiplied by m/i, and the adjusted
+// p-values are made monotone in the order of the p-values starting from
+// the largest.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of p, BenjaminiHochberg
+// will panic. dst and p may be the same slice. BenjaminiHochberg will panic
+// if any p-value is not in [0, 1].
+//
+// References:
+//   - Benjamini, Y. and Hochberg, Y. (1995). Controlling the false discovery
+//     rate: a practical and powerful approach to multiple testing. Journal
+//     of the Royal Statistical Society, Series B, 57(1), 289-300.
+func BenjaminiHochberg(dst, p []float64) []float64 {
+	m := float64(len(p))
+	return stepUp(adjustDst(dst, p), p, func(i int) float64 { return m / float64(i) })
+}
+
+// BenjaminiYekutieli returns the p-values adjusted for multiple
+// comparisons by the Benjamini-Yekutieli step-up procedure, controlling the
+// false discovery rate under arbitrary dependence between the tests. The
+// adjustment is that of BenjaminiHochberg with the p-values multiplied by
+// the harmonic number 1 + 1/2 + ... + 1/m.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of p, BenjaminiYekutieli
+// will panic. dst and p may be the same slice. BenjaminiYekutieli will
+// panic if any p-value is not in [0, 1].
+//
+// References:
+//   - Benjamini, Y. and Yekutieli, D. (2001). The control of the false
+//     discovery rate in multiple testing under dependency. The Annals of
+//     Statistics, 29(4), 1165-1188.
+func BenjaminiYekutieli(dst, p []float64) []float64 {
+	m := float64(len(p))
+	var h float64
+	for i := len(p); i > 0; i-- {
+		h += 1 / float64(i)
+	}
+	return stepUp(adjustDst(dst, p), p, func(i int) float64 { return h * m / float64(i) })
+}
+
+// StoreyQ returns Storey's q-values of the p-values, the minimum false
+// discovery rate at which each test is called significant. The q-values
+// are the Benjamini-Hochberg adjusted p-values multiplied by the estimated
+// proportion of true null hypotheses,
+//
+//	π₀ = min(1, (#{p[i] > lambda} + 1) / (m (1-lambda))),
+//
+// which is returned as pi0. The count is increased by one so that π₀ is
+// positive even if no p-value exceeds lambda, which would otherwise give
+// q-values of zero, and so that the procedure controls the false discovery
+// rate in finite samples. A lambda of 0.5 is a common choice. With a lambda
+// of zero, π₀ is one and the q-values are the Benjamini-Hochberg adjusted
+// p-values.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the length of p, StoreyQ will panic.
+// dst and p may be the same slice. StoreyQ will panic if any p-value is
+// not in [0, 1], or if lambda is not in [0, 1).
+//
+// References:
+//   - Storey, J. D. (2002). A direct approach to false discovery rates.
+//     Journal of the Royal Statistical Society, Series B, 64(3), 479-498.
+//   - Storey, J. D. and Tibshirani, R. (2003). Statistical significance for
+//     genomewide studies. Proceedings of the National Academy of Sciences,
+//     100(16), 9440-9445.
+//   - Storey, J. D., Taylor, J. E. and Siegmund, D. (2004). Strong control,
+//     conservative point estimation and simultaneous conservative
+//     consistency of false discovery rates: a unified approach. Journal of
+//     the Royal Statistical Society, Series B, 66(1), 187-205.
+func StoreyQ(dst, p []float64, lambda float64) (q []float64, pi0 float64) {
+	if !(0 <= lambda && lambda < 1) {
+		panic("hypothesis: lambda out of range")
+	}
+	dst = adjustDst(dst, p)
+	if len(p) == 0 {
+		return dst, 1
+	}
+	var n int
+	for _, v := range p {
+		if v > lambda {
+			n++
+		}
+	}
+	m := float64(len(p))
+	pi0 = math.Min(1, float64(n+1)/(m*(1-lambda)))
+	return stepUp(dst, p, func(i int) float64 { return pi0 * m / float64(i) }), pi0
+}
+
+// adjustDst checks the p-values and returns the destination for their
+// adjustment.
+func adjustDst(dst, p []float64) []float64 {
+	for _, v := range p {
+		if !(0 <= v && v <= 1) {
+			panic("hypothesis: p-value out of range")
+		}
+	}
+	if dst == nil {
+		return make([]float64, len(p))
+	}
+	if len(dst) != len(p) {
+		panic("hypothesis: slice length mismatch")
+	}
+	return dst
+}
+
+// order returns the indices of p in increasing order of the p-values.
+func order(p []float64) []int {
+	idx := make([]int, len(p))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return p[idx[i]] < p[idx[j]] })
+	return idx
+}
+
+// stepDown stores in dst the p-values multiplied by factor(i) for the i-th
+// smallest p-value, counting from one, made non-decreasing from the
+// smallest p-value upwards and truncated at one.
+func stepDown(dst, p []float64, factor func(i int) float64) []float64 {
+	var max float64
+	for i, k := range order(p) {
+		max = math.Max(max, math.Min(1, factor(i+1)*p[k]))
+		dst[k] = max
+	}
+	return dst
+}
+
+// stepUp stores in dst the p-values multiplied by factor(i) for the i-th
+// smallest p-value, counting from one, made non-increasing from the
+// largest p-value downwards and truncated at one.
+func stepUp(dst, p []float64, factor func(i int) float64) []float64 {
+	idx := order(p)
+	min := 1.0
+	for i := len(idx) - 1; i >= 0; i-- {
+		k := idx[i]
+		min = math.Min(min, factor(i+1)*p[k])
+		dst[k] = min
+	}
+	return dst
+}
@@ -0,0 +1,150 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestAdjust(t *testing.T) {
+	t.Parallel()
+	p := []float64{0.01, 0.04, 0.03, 0.005, 0.2}
+	const h = 137.0 / 60 // 1 + 1/2 + 1/3 + 1/4 + 1/5
+	for _, test := range []struct {
+		name   string
+		adjust func(dst, p []float64) []float64
+		want   []float64
+	}{
+		{name: "Bonferroni", adjust: Bonferroni, want: []float64{0.05, 0.2, 0.15, 0.025, 1}},
+		{name: "Holm", adjust: Holm, want: []float64{0.04, 0.09, 0.09, 0.025, 0.2}},
+		{name: "Hochberg", adjust: Hochberg, want: []float64{0.04, 0.08, 0.08, 0.025, 0.2}},
+		{name: "BenjaminiHochberg", adjust: BenjaminiHochberg, want: []float64{0.025, 0.05, 0.05, 0.025, 0.2}},
+		{name: "BenjaminiYekutieli", adjust: BenjaminiYekutieli, want: []float64{h * 0.025, h * 0.05, h * 0.05, h * 0.025, h * 0.2}},
+	} {
+		got := test.adjust(nil, p)
+		if !floats.EqualApprox(got, test.want, 1e-14) {
+			t.Errorf("unexpected %s adjustment: got:%v want:%v", test.name, got, test.want)
+		}
+		dst := append([]float64(nil), p...)
+		test.adjust(dst, dst)
+		if !floats.Equal(dst, got) {
+			t.Errorf("unexpected in place %s adjustment: got:%v want:%v", test.name, dst, got)
+		}
+		if got := test.adjust(nil, nil); len(got) != 0 {
+			t.Errorf("unexpected %s adjustment of no p-values: %v", test.name, got)
+		}
+	}
+}
+
+func TestAdjustOrdering(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, m := range []int{1, 2, 10, 100} {
+		p := make([]float64, m)
+		for i := range p {
+			// Mix small p-values from false nulls with
+			// uniform p-values from true nulls, with ties.
+			if i%3 == 0 {
+				p[i] = rnd.Float64() * 1e-3
+			} else {
+				p[i] = float64(rnd.Intn(100)) / 100
+			}
+		}
+		bonf := Bonferroni(nil, p)
+		holm := Holm(nil, p)
+		hoch := Hochberg(nil, p)
+		bh := BenjaminiHochberg(nil, p)
+		by := BenjaminiYekutieli(nil, p)
+		name := fmt.Sprintf("m=%d", m)
+		for i, v := range p {
+			if !(v <= bh[i] && bh[i] <= hoch[i] && hoch[i] <= holm[i] && holm[i] <= bonf[i] && bh[i] <= by[i]) {
+				t.Errorf("unexpected order of adjusted p-values for %s: p:%v BH:%v Hochberg:%v Holm:%v Bonferroni:%v BY:%v",
+					name, v, bh[i], hoch[i], holm[i], bonf[i], by[i])
+			}
+		}
+		for _, adj := range [][]float64{holm, hoch, bh, by} {
+			idx := make([]int, m)
+			for i := range idx {
+				idx[i] = i
+			}
+			sort.Slice(idx, func(i, j int) bool { return p[idx[i]] < p[idx[j]] })
+			for i := 1; i < m; i++ {
+				if adj[idx[i]] < adj[idx[i-1]] {
+					t.Errorf("adjusted p-values not monotone for %s", name)
+					break
+				}
+			}
+		}
+
+		q, pi0 := StoreyQ(nil, p, 0)
+		if pi0 != 1 || !floats.Equal(q, bh) {
+			t.Errorf("unexpected q-values for lambda=0 for %s: got:%v pi0:%v want:%v", name, q, pi0, bh)
+		}
+		q, pi0 = StoreyQ(nil, p, 0.5)
+		var n int
+		for _, v := range p {
+			if v > 0.5 {
+				n++
+			}
+		}
+		want := float64(n+1) / (float64(m) * 0.5)
+		if want > 1 {
+			want = 1
+		}
+		if pi0 != want {
+			t.Errorf("unexpected pi0 for %s: got:%v want:%v", name, pi0, want)
+		}
+		for i := range q {
+			if !scalar.EqualWithinAbsOrRel(q[i], pi0*bh[i], 1e-14, 1e-14) {
+				t.Errorf("unexpected q-value for %s: got:%v want:%v", name, q[i], pi0*bh[i])
+			}
+		}
+	}
+}
+
+func TestStoreyQSmallPValues(t *testing.T) {
+	t.Parallel()
+	// When no p-value exceeds lambda the estimate of π₀ is bounded
+	// away from zero, so the q-values are not all zero.
+	p := []float64{0.001, 0.01, 0.02, 0.04, 0.3, 0.45}
+	q, pi0 := StoreyQ(nil, p, 0.5)
+	if want := 1 / (6 * 0.5); !scalar.EqualWithinAbsOrRel(pi0, want, 1e-15, 1e-15) {
+		t.Errorf("unexpected pi0: got:%v want:%v", pi0, want)
+	}
+	bh := BenjaminiHochberg(nil, p)
+	for i := range q {
+		if q[i] == 0 {
+			t.Errorf("unexpected zero q-value for p-value %v", p[i])
+		}
+		if !scalar.EqualWithinAbsOrRel(q[i], pi0*bh[i], 1e-14, 1e-14) {
+			t.Errorf("unexpected q-value: got:%v want:%v", q[i], pi0*bh[i])
+		}
+	}
+}
+
+func TestAdjustPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "p-value out of range", fn: func() { Holm(nil, []float64{0.1, 1.1}) }},
+		{name: "NaN p-value", fn: func() { BenjaminiHochberg(nil, []float64{0.1, math.NaN()}) }},
+		{name: "dst length", fn: func() { Bonferroni(make([]float64, 1), []float64{0.1, 0.2}) }},
+		{name: "lambda", fn: func() { StoreyQ(nil, []float64{0.1, 0.2}, 1) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// ChiSquareGoodnessOfFit performs Pearson's chi-square goodness-of-fit test
+// of the null hypothesis that the observed counts are drawn from the
+// distribution with the expected counts. If expected is nil, the counts
+// are expected to be equal. The statistic is computed by stat.ChiSquare
+// and has len(observed)-1-ddof degrees of freedom, where ddof is the
+// number of parameters of the distribution estimated from the data. The
+// Estimate, Lower and Upper fields of the result are NaN.
+//
+// ChiSquareGoodnessOfFit will panic if expected is not nil and has a
+// different length to observed, or if the number of degrees of freedom is
+// less than one.
+func ChiSquareGoodnessOfFit(observed, expected []float64, ddof int) Result {
+	if expected == nil {
+		var sum float64
+		for _, v := range observed {
+			sum += v
+		}
+		expected = make([]float64, len(observed))
+		for i := range expected {
+			expected[i] = sum / float64(len(observed))
+		}
+	}
+	if len(expected) != len(observed) {
+		panic("hypothesis: slice length mismatch")
+	}
+	df := len(observed) - 1 - ddof
+	if df < 1 {
+		panic("hypothesis: degrees of freedom less than one")
+	}
+	chi2 := stat.ChiSquare(observed, expected)
+	return Result{
+		Statistic: chi2,
+		DF:        float64(df),
+		P:         distuv.ChiSquared{K: float64(df)}.Survival(chi2),
+		Estimate:  math.NaN(),
+		Lower:     math.NaN(),
+		Upper:     math.NaN(),
+	}
+}
+
+// ChiSquareIndependence performs Pearson's chi-square test of the null
+// hypothesis that the row and column classifications of the contingency
+// table of counts are independent. The statistic has (r-1)(c-1) degrees
+// of freedom for a table with r rows and c columns. If correction is true
+// and the table has one degree of freedom, Yates' continuity correction is
+// applied, reducing each absolute difference between the observed and
+// expected counts by at most one half. The Estimate, Lower and Upper fields
+// of the result are NaN.
+//
+// ChiSquareIndependence will panic if the table has fewer than two rows or
+// columns, or if any row or column sum is zero.
+func ChiSquareIndependence(table mat.Matrix, correction bool) Result {
+	r, c := table.Dims()
+	if r < 2 || c < 2 {
+		panic("hypothesis: table too small")
+	}
+	rows := make([]float64, r)
+	cols := make([]float64, c)
+	var total float64
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			v := table.At(i, j)
+			rows[i] += v
+			cols[j] += v
+			total += v
+		}
+	}
+	for _, v := range rows {
+		if v == 0 {
+			panic("hypothesis: zero row sum")
+		}
+	}
+	for _, v := range cols {
+		if v == 0 {
+			panic("hypothesis: zero column sum")
+		}
+	}
+	df := (r - 1) * (c - 1)
+	yates := correction && df == 1
+	var chi2 float64
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			e := rows[i] * cols[j] / total
+			d := math.Abs(table.At(i, j) - e)
+			if yates {
+				d -= math.Min(0.5, d)
+			}
+			chi2 += d * d / e
+		}
+	}
+	return Result{
+		Statistic: chi2,
+		DF:        float64(df),
+		P:         distuv.ChiSquared{K: float64(df)}.Survival(chi2),
+		Estimate:  math.NaN(),
+		Lower:     math.NaN(),
+		Upper:     math.NaN(),
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestChiSquareGoodnessOfFit(t *testing.T) {
+	t.Parallel()
+	// Reference values from the examples of R's chisq.test.
+	obs := []float64{89, 37, 30, 28, 2}
+	for _, test := range []struct {
+		p       []float64
+		ddof    int
+		stat, P float64
+		df      float64
+	}{
+		{p: []float64{0.40, 0.20, 0.20, 0.19, 0.01}, stat: 5.7947, df: 4, P: 0.215},
+		{p: []float64{0.40, 0.20, 0.20, 0.15, 0.05}, stat: 9.9901, df: 4, P: 0.04059},
+	} {
+		exp := make([]float64, len(test.p))
+		for i, p := range test.p {
+			exp[i] = 186 * p
+		}
+		got := ChiSquareGoodnessOfFit(obs, exp, test.ddof)
+		if !scalar.EqualWithinAbs(got.Statistic, test.stat, 5e-5) || got.DF != test.df || !scalar.EqualWithinRel(got.P, test.P, 5e-4) {
+			t.Errorf("unexpected result: got:%+v want statistic:%v df:%v p:%v", got, test.stat, test.df, test.P)
+		}
+		if !math.IsNaN(got.Estimate) || !math.IsNaN(got.Lower) || !math.IsNaN(got.Upper) {
+			t.Errorf("unexpected estimate or confidence interval: %+v", got)
+		}
+	}
+
+	// Uniform expected counts.
+	got := ChiSquareGoodnessOfFit([]float64{10, 20, 30}, nil, 1)
+	if got.Statistic != 10 || got.DF != 1 {
+		t.Errorf("unexpected result for uniform expected counts: got:%+v want statistic:10 df:1", got)
+	}
+}
+
+func TestChiSquareIndependence(t *testing.T) {
+	t.Parallel()
+	// Reference values from the examples of R's chisq.test.
+	m := mat.NewDense(2, 3, []float64{
+		762, 327, 468,
+		484, 239, 477,
+	})
+	got := ChiSquareIndependence(m, true)
+	if !scalar.EqualWithinAbs(got.Statistic, 30.07, 5e-3) || got.DF != 2 || !scalar.EqualWithinRel(got.P, 2.954e-07, 5e-4) {
+		t.Errorf("unexpected result: got:%+v want statistic:30.07 df:2 p:2.954e-07", got)
+	}
+
+	// Yates' correction for a 2×2 table, with one cell
+	// where the difference is less than one half.
+	m = mat.NewDense(2, 2, []float64{
+		12, 5,
+		7, 9,
+	})
+	var want float64
+	for i, o := range []float64{12, 5, 7, 9} {
+		e := []float64{17 * 19, 17 * 14, 16 * 19, 16 * 14}[i] / 33.0
+		d := math.Abs(o-e) - 0.5
+		want += d * d / e
+	}
+	got = ChiSquareIndependence(m, true)
+	if !scalar.EqualWithinRel(got.Statistic, want, 1e-14) || got.DF != 1 {
+		t.Errorf("unexpected corrected statistic: got:%v want:%v", got.Statistic, want)
+	}
+	uncorrected := ChiSquareIndependence(m, false)
+	if !(uncorrected.Statistic > got.Statistic && uncorrected.P < got.P) {
+		t.Errorf("correction did not reduce the statistic: corrected:%v uncorrected:%v", got.Statistic, uncorrected.Statistic)
+	}
+	m = mat.NewDense(2, 2, []float64{
+		10, 10,
+		10, 10.2,
+	})
+	if got := ChiSquareIndependence(m, true); got.Statistic != 0 {
+		t.Errorf("unexpected corrected statistic for small differences: got:%v want:0", got.Statistic)
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package hypothesis provides statistical hypothesis tests.
+//
+// Each test returns a Result holding the test statistic, the degrees of
+// freedom of its null distribution and the p-value of the test, and for
+// tests of a location or association parameter, an estimate of the
+// parameter and a confidence interval for it. The p-values are computed
+// from the distributions in the distuv package, or from the exact null
+// distribution of the statistic.
+//
+// The p-values of a family of tests may be adjusted for multiple
+// comparisons to control the family-wise error rate, by the Bonferroni,
+// Holm and Hochberg procedures, or the false discovery rate, by the
+// Benjamini-Hochberg and Benjamini-Yekutieli procedures and Storey's
+// q-values.
+package hypothesis // import "gonum.org/v1/gonum/stat/hypothesis"
@@ -0,0 +1,81 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis_test
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/hypothesis"
+)
+
+func ExampleWelchT() {
+	// The increase in hours of sleep of two groups of
+	// patients given different soporific drugs, from
+	// Student (1908).
+	x := []float64{0.7, -1.6, -0.2, -1.2, -0.1, 3.4, 3.7, 0.8, 0.0, 2.0}
+	y := []float64{1.9, 0.8, 1.1, 0.1, -0.1, 4.4, 5.5, 1.6, 4.6, 3.4}
+
+	r := hypothesis.WelchT(x, y, 0, hypothesis.TwoSided, 0.95)
+	fmt.Printf("t = %.4f, df = %.4f, p = %.4f\n", r.Statistic, r.DF, r.P)
+	fmt.Printf("difference in means = %.2f\n", r.Estimate)
+	fmt.Printf("95%% confidence interval: [%.4f, %.4f]\n", r.Lower, r.Upper)
+
+	// The same patients were given both drugs, so
+	// the paired test is more appropriate.
+	r = hypothesis.PairedT(x, y, 0, hypothesis.TwoSided, 0.95)
+	fmt.Printf("paired t = %.4f, df = %v, p = %.4f\n", r.Statistic, r.DF, r.P)
+
+	// Output:
+	// t = -1.8608, df = 17.7765, p = 0.0794
+	// difference in means = -1.58
+	// 95% confidence interval: [-3.3655, 0.2055]
+	// paired t = -4.0621, df = 9, p = 0.0028
+}
+
+func ExampleFisherExact() {
+	// Fisher's lady tasting tea: of eight cups, four had the
+	// milk poured first, and the lady was to identify them.
+	//
+	//                guess milk  guess tea
+	//  milk first        3           1
+	//  tea first         1           3
+	tea := mat.NewDense(2, 2, []float64{
+		3, 1,
+		1, 3,
+	})
+	r := hypothesis.FisherExact(tea, hypothesis.Greater, 0.95)
+	fmt.Printf("odds ratio = %.4f, p = %.4f\n", r.Estimate, r.P)
+
+	// Output:
+	// odds ratio = 6.4083, p = 0.2429
+}
+
+func ExampleBenjaminiHochberg() {
+	// The p-values of tests of differential expression
+	// of ten genes.
+	p := []float64{0.0001, 0.0004, 0.0019, 0.0095, 0.0201, 0.0278, 0.0298, 0.0344, 0.0459, 0.3240}
+
+	// Controlling the family-wise error rate at 5% finds
+	// fewer significant genes than controlling the false
+	// discovery rate at 5%.
+	holm := hypothesis.Holm(nil, p)
+	bh := hypothesis.BenjaminiHochberg(nil, p)
+	for i := range p {
+		fmt.Printf("p = %.4f  Holm = %.4f  BH = %.4f\n", p[i], holm[i], bh[i])
+	}
+
+	// Output:
+	// p = 0.0001  Holm = 0.0010  BH = 0.0010
+	// p = 0.0004  Holm = 0.0036  BH = 0.0020
+	// p = 0.0019  Holm = 0.0152  BH = 0.0063
+	// p = 0.0095  Holm = 0.0665  BH = 0.0238
+	// p = 0.0201  Holm = 0.1206  BH = 0.0402
+	// p = 0.0278  Holm = 0.1390  BH = 0.0426
+	// p = 0.0298  Holm = 0.1390  BH = 0.0426
+	// p = 0.0344  Holm = 0.1390  BH = 0.0430
+	// p = 0.0459  Holm = 0.1390  BH = 0.0510
+	// p = 0.3240  Holm = 0.3240  BH = 0.3240
+}
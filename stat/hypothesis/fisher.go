@@ -0,0 +1,222 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/combin"
+)
+
+// FisherExact performs Fisher's exact test of the null hypothesis that the
+// odds ratio of the 2×2 contingency table of counts is one, conditional on
+// the row and column sums of the table. For the table
+//
+//	a b
+//	c d
+//
+// the Statistic of the result is the sample odds ratio ad/bc, and the
+// p-value is computed from the hypergeometric distribution of a. The
+// two-sided p-value is the sum of the probabilities of the tables that
+// are no more likely than the observed table.
+//
+// The Estimate of the result is the conditional maximum likelihood
+// estimate of the odds ratio, and Lower and Upper bound the conditional
+// confidence interval for the odds ratio at the given confidence level,
+// obtained from the noncentral hypergeometric distribution of a. The DF
+// field of the result is NaN.
+//
+// FisherExact will panic if table is not 2×2, if its elements are not
+// non-negative integers, or if level is not in (0, 1).
+//
+// References:
+//   - Agresti, A. (2002). Categorical Data Analysis, 2nd edition. Wiley.
+func FisherExact(table mat.Matrix, alt Alternative, level float64) Result {
+	r, c := table.Dims()
+	if r != 2 || c != 2 {
+		panic("hypothesis: table is not 2×2")
+	}
+	checkAlternative(alt)
+	checkLevel(level)
+	var counts [2][2]int
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			v := table.At(i, j)
+			if v < 0 || v != math.Trunc(v) {
+				panic("hypothesis: table element is not a count")
+			}
+			counts[i][j] = int(v)
+		}
+	}
+	a, b := counts[0][0], counts[0][1]
+	cc, d := counts[1][0], counts[1][1]
+	h := newHypergeometric(a+cc, b+d, a+b)
+
+	res := Result{
+		Statistic: float64(a*d) / float64(b*cc),
+		DF:        math.NaN(),
+	}
+	switch alt {
+	case Less:
+		res.P = h.cdf(a, 1)
+	case Greater:
+		res.P = h.survival(a, 1)
+	default:
+		// Allow for rounding in the comparison of the
+		// probabilities of the tables.
+		const relErr = 1 + 1e-7
+		p := h.pmf(1)
+		pa := p[a-h.lo] * relErr
+		for _, v := range p {
+			if v <= pa {
+				res.P += v
+			}
+		}
+		res.P = math.Min(res.P, 1)
+	}
+
+	res.Estimate = h.mle(a)
+	switch alt {
+	case Less:
+		res.Lower, res.Upper = 0, h.upperBound(a, 1-level)
+	case Greater:
+		res.Lower, res.Upper = h.lowerBound(a, 1-level), math.Inf(1)
+	default:
+		alpha := (1 - level) / 2
+		res.Lower, res.Upper = h.lowerBound(a, alpha), h.upperBound(a, alpha)
+	}
+	return res
+}
+
+// hypergeometric is the noncentral hypergeometric distribution of the
+// number of white balls in a sample of k balls from an urn with m white
+// and n black balls, conditional on the odds ratio.
+type hypergeometric struct {
+	lo, hi int
+	// logc holds the logarithms of the central
+	// hypergeometric probabilities of lo through hi.
+	logc []float64
+}
+
+func newHypergeometric(m, n, k int) hypergeometric {
+	lo := k - n
+	if lo < 0 {
+		lo = 0
+	}
+	hi := k
+	if m < hi {
+		hi = m
+	}
+	h := hypergeometric{lo: lo, hi: hi, logc: make([]float64, hi-lo+1)}
+	norm := combin.LogGeneralizedBinomial(float64(m+n), float64(k))
+	for x := lo; x <= hi; x++ {
+		h.logc[x-lo] = combin.LogGeneralizedBinomial(float64(m), float64(x)) +
+			combin.LogGeneralizedBinomial(float64(n), float64(k-x)) - norm
+	}
+	return h
+}
+
+// pmf returns the probabilities of lo through hi for the odds ratio psi.
+func (h hypergeometric) pmf(psi float64) []float64 {
+	p := make([]float64, len(h.logc))
+	logPsi := math.Log(psi)
+	max := math.Inf(-1)
+	for i, v := range h.logc {
+		p[i] = v
+		if psi != 1 {
+			p[i] += logPsi * float64(h.lo+i)
+		}
+		max = math.Max(max, p[i])
+	}
+	var sum float64
+	for i, v := range p {
+		p[i] = math.Exp(v - max)
+		sum += p[i]
+	}
+	for i := range p {
+		p[i] /= sum
+	}
+	return p
+}
+
+// mean returns the mean for the odds ratio psi.
+func (h hypergeometric) mean(psi float64) float64 {
+	if psi == 0 {
+		return float64(h.lo)
+	}
+	if math.IsInf(psi, 1) {
+		return float64(h.hi)
+	}
+	var mu float64
+	for i, v := range h.pmf(psi) {
+		mu += float64(h.lo+i) * v
+	}
+	return mu
+}
+
+// cdf returns the probability of at most x for the odds ratio psi.
+func (h hypergeometric) cdf(x int, psi float64) float64 {
+	var p float64
+	for _, v := range h.pmf(psi)[:x-h.lo+1] {
+		p += v
+	}
+	return math.Min(p, 1)
+}
+
+// survival returns the probability of at least x for the odds ratio psi.
+func (h hypergeometric) survival(x int, psi float64) float64 {
+	var p float64
+	for _, v := range h.pmf(psi)[x-h.lo:] {
+		p += v
+	}
+	return math.Min(p, 1)
+}
+
+// mle returns the conditional maximum likelihood estimate of the odds
+// ratio given the observation x, the odds ratio at which the mean is x.
+func (h hypergeometric) mle(x int) float64 {
+	switch {
+	case x == h.lo && x == h.hi:
+		return math.NaN()
+	case x == h.lo:
+		return 0
+	case x == h.hi:
+		return math.Inf(1)
+	}
+	return solveOdds(func(psi float64) float64 { return h.mean(psi) - float64(x) })
+}
+
+// upperBound returns the odds ratio at which the probability of at most
+// x is alpha.
+func (h hypergeometric) upperBound(x int, alpha float64) float64 {
+	if x == h.hi {
+		return math.Inf(1)
+	}
+	return solveOdds(func(psi float64) float64 { return alpha - h.cdf(x, psi) })
+}
+
+// lowerBound returns the odds ratio at which the probability of at least
+// x is alpha.
+func (h hypergeometric) lowerBound(x int, alpha float64) float64 {
+	if x == h.lo {
+		return 0
+	}
+	return solveOdds(func(psi float64) float64 { return h.survival(x, psi) - alpha })
+}
+
+// solveOdds returns the root of the increasing function f of the odds
+// ratio. The root is found on the log scale.
+func solveOdds(f func(psi float64) float64) float64 {
+	g := func(t float64) float64 { return f(math.Exp(t)) }
+	lo, hi := -1.0, 1.0
+	for g(lo) > 0 && lo > -700 {
+		lo *= 2
+	}
+	for g(hi) < 0 && hi < 700 {
+		hi *= 2
+	}
+	return math.Exp(bisect(g, lo, hi))
+}
@@ -0,0 +1,139 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/combin"
+)
+
+func TestFisherExactTea(t *testing.T) {
+	t.Parallel()
+	// Fisher's tea tasting experiment. Reference values from
+	// the examples of R's fisher.test. The estimate and the
+	// confidence bounds are found by R with a root finding
+	// tolerance of about 1e-4, which is coarse for the large
+	// upper bound.
+	tea := mat.NewDense(2, 2, []float64{3, 1, 1, 3})
+	got := FisherExact(tea, TwoSided, 0.95)
+	if got.Statistic != 9 || !scalar.EqualWithinAbs(got.P, 0.4857, 5e-5) {
+		t.Errorf("unexpected result: got statistic:%v p:%v want statistic:9 p:0.4857", got.Statistic, got.P)
+	}
+	if !scalar.EqualWithinRel(got.Estimate, 6.408309, 1e-3) ||
+		!scalar.EqualWithinRel(got.Lower, 0.2117329, 1e-3) ||
+		!scalar.EqualWithinRel(got.Upper, 621.9337505, 5e-2) {
+		t.Errorf("unexpected estimate and confidence interval: got:%v [%v, %v] want:6.408309 [0.2117329, 621.9337505]",
+			got.Estimate, got.Lower, got.Upper)
+	}
+	got = FisherExact(tea, Greater, 0.95)
+	if !scalar.EqualWithinAbs(got.P, 0.2429, 5e-5) || !scalar.EqualWithinRel(got.Lower, 0.3135693, 1e-3) || !math.IsInf(got.Upper, 1) {
+		t.Errorf("unexpected one-sided result: got:%+v want p:0.2429 interval:[0.3135693, +Inf]", got)
+	}
+}
+
+func TestFisherExact(t *testing.T) {
+	t.Parallel()
+	for _, tab := range [][4]int{
+		{3, 1, 1, 3},
+		{10, 2, 3, 15},
+		{0, 5, 6, 2},
+		{7, 0, 2, 9},
+		{1, 9, 11, 3},
+		{20, 15, 12, 25},
+	} {
+		name := fmt.Sprint(tab)
+		a, b, c, d := tab[0], tab[1], tab[2], tab[3]
+		m := mat.NewDense(2, 2, []float64{float64(a), float64(b), float64(c), float64(d)})
+
+		// Compute the p-values from the hypergeometric
+		// distribution directly.
+		r1, c1, n := a+b, a+c, a+b+c+d
+		prob := func(x int) float64 {
+			return math.Exp(logBinomial(c1, x) + logBinomial(n-c1, r1-x) - logBinomial(n, r1))
+		}
+		var less, greater, two float64
+		lo, hi := r1-(n-c1), r1
+		if lo < 0 {
+			lo = 0
+		}
+		if c1 < hi {
+			hi = c1
+		}
+		for x := lo; x <= hi; x++ {
+			p := prob(x)
+			if x <= a {
+				less += p
+			}
+			if x >= a {
+				greater += p
+			}
+			if p <= prob(a)*(1+1e-7) {
+				two += p
+			}
+		}
+		for _, test := range []struct {
+			alt  Alternative
+			want float64
+		}{
+			{alt: TwoSided, want: two},
+			{alt: Less, want: less},
+			{alt: Greater, want: greater},
+		} {
+			got := FisherExact(m, test.alt, 0.9)
+			if !scalar.EqualWithinAbsOrRel(got.P, test.want, 1e-12, 1e-12) {
+				t.Errorf("unexpected p-value for %s alternative=%d: got:%v want:%v", name, test.alt, got.P, test.want)
+			}
+
+			// The confidence bounds are the odds ratios at
+			// which the tail probabilities of a are alpha.
+			h := newHypergeometric(c1, n-c1, r1)
+			alpha := 0.1
+			if test.alt == TwoSided {
+				alpha /= 2
+			}
+			if test.alt != Less {
+				if a == lo {
+					if got.Lower != 0 {
+						t.Errorf("unexpected lower bound for %s: got:%v want:0", name, got.Lower)
+					}
+				} else if p := h.survival(a, got.Lower); !scalar.EqualWithinAbs(p, alpha, 1e-9) {
+					t.Errorf("unexpected tail probability at lower bound for %s: got:%v want:%v", name, p, alpha)
+				}
+			}
+			if test.alt != Greater {
+				if a == hi {
+					if !math.IsInf(got.Upper, 1) {
+						t.Errorf("unexpected upper bound for %s: got:%v want:+Inf", name, got.Upper)
+					}
+				} else if p := h.cdf(a, got.Upper); !scalar.EqualWithinAbs(p, alpha, 1e-9) {
+					t.Errorf("unexpected tail probability at upper bound for %s: got:%v want:%v", name, p, alpha)
+				}
+			}
+			switch {
+			case a == lo:
+				if got.Estimate != 0 {
+					t.Errorf("unexpected estimate for %s: got:%v want:0", name, got.Estimate)
+				}
+			case a == hi:
+				if !math.IsInf(got.Estimate, 1) {
+					t.Errorf("unexpected estimate for %s: got:%v want:+Inf", name, got.Estimate)
+				}
+			default:
+				if mu := h.mean(got.Estimate); !scalar.EqualWithinAbs(mu, float64(a), 1e-9) {
+					t.Errorf("unexpected conditional mean at estimate for %s: got:%v want:%v", name, mu, a)
+				}
+			}
+		}
+	}
+}
+
+func logBinomial(n, k int) float64 {
+	return combin.LogGeneralizedBinomial(float64(n), float64(k))
+}
@@ -0,0 +1,162 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"sort"
+)
+
+// Alternative specifies the alternative hypothesis of a test.
+type Alternative int
+
+const (
+	// TwoSided is the alternative hypothesis that the parameter
+	// differs from its value under the null hypothesis.
+	TwoSided Alternative = iota
+	// Less is the alternative hypothesis that the parameter is less
+	// than its value under the null hypothesis.
+	Less
+	// Greater is the alternative hypothesis that the parameter is
+	// greater than its value under the null hypothesis.
+	Greater
+)
+
+// Method specifies how the p-value of a test with an exact null
+// distribution is computed.
+type Method int
+
+const (
+	// Auto uses the exact null distribution for small samples and
+	// the asymptotic distribution otherwise, as described by each
+	// test.
+	Auto Method = iota
+	// Exact uses the exact null distribution of the statistic.
+	// Computing the exact distribution may be expensive for large
+	// samples.
+	Exact
+	// Asymptotic uses the asymptotic null distribution of the
+	// statistic.
+	Asymptotic
+)
+
+// Result is the result of a hypothesis test.
+type Result struct {
+	// Statistic is the value of the test statistic.
+	Statistic float64
+
+	// DF is the number of degrees of freedom of the null
+	// distribution of the statistic. DF is NaN if the
+	// null distribution has no degrees of freedom.
+	DF float64
+
+	// P is the p-value of the test, the probability under the
+	// null hypothesis of a statistic at least as extreme as the
+	// observed statistic.
+	P float64
+
+	// Estimate is the estimate of the parameter of the test, such
+	// as a difference in location. Estimate is NaN if the test has
+	// no parameter.
+	Estimate float64
+
+	// Lower and Upper are the bounds of the confidence interval
+	// for the parameter of the test. For one-sided alternatives one
+	// of the bounds is infinite. Lower and Upper are NaN if the test
+	// has no parameter.
+	Lower, Upper float64
+}
+
+// checkLevel panics if level is not a valid confidence level.
+func checkLevel(level float64) {
+	if !(0 < level && level < 1) {
+		panic("hypothesis: confidence level out of range")
+	}
+}
+
+// checkAlternative panics if alt is not a valid alternative.
+func checkAlternative(alt Alternative) {
+	if alt < TwoSided || Greater < alt {
+		panic("hypothesis: invalid alternative")
+	}
+}
+
+// tails returns the p-value for the alternative given the lower and upper
+// tail probabilities of the observed statistic.
+func tails(alt Alternative, lower, upper float64) float64 {
+	switch alt {
+	case Less:
+		return lower
+	case Greater:
+		return upper
+	default:
+		return math.Min(1, 2*math.Min(lower, upper))
+	}
+}
+
+// ranks returns the ranks of x, starting from one, with tied values
+// given the mean of their ranks, and the sizes of the groups of tied
+// values.
+func ranks(x []float64) (r []float64, ties []int) {
+	idx := make([]int, len(x))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return x[idx[i]] < x[idx[j]] })
+	r = make([]float64, len(x))
+	for i := 0; i < len(idx); {
+		j := i + 1
+		for j < len(idx) && x[idx[j]] == x[idx[i]] {
+			j++
+		}
+		mid := float64(i+j+1) / 2
+		for _, k := range idx[i:j] {
+			r[k] = mid
+		}
+		if j-i > 1 {
+			ties = append(ties, j-i)
+		}
+		i = j
+	}
+	return r, ties
+}
+
+// tieCorrection returns the sum of t³-t over the tie group sizes t.
+func tieCorrection(ties []int) float64 {
+	var s float64
+	for _, t := range ties {
+		f := float64(t)
+		s += f*f*f - f
+	}
+	return s
+}
+
+// median returns the median of the sorted values in x.
+func median(x []float64) float64 {
+	n := len(x)
+	if n%2 == 1 {
+		return x[n/2]
+	}
+	return (x[n/2-1] + x[n/2]) / 2
+}
+
+// bisect returns the root of the increasing or decreasing function f in
+// the interval [a, b], at which f changes sign.
+func bisect(f func(float64) float64, a, b float64) float64 {
+	fa := f(a)
+	for i := 0; i < 200; i++ {
+		m := a + (b-a)/2
+		if m == a || m == b {
+			break
+		}
+		fm := f(m)
+		if (fm < 0) == (fa < 0) {
+			a, fa = m, fm
+		} else {
+			b = m
+		}
+	}
+	return a + (b-a)/2
+}
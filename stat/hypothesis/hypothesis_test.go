@@ -0,0 +1,86 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+// Student's sleep data, the increase in hours of sleep of
+// ten patients given two soporific drugs.
+var (
+	sleep1 = []float64{0.7, -1.6, -0.2, -1.2, -0.1, 3.4, 3.7, 0.8, 0.0, 2.0}
+	sleep2 = []float64{1.9, 0.8, 1.1, 0.1, -0.1, 4.4, 5.5, 1.6, 4.6, 3.4}
+)
+
+// sameResult returns whether got and want agree to within the relative
+// tolerance tol, treating NaN values as equal.
+func sameResult(got, want Result, tol float64) bool {
+	same := func(a, b float64) bool {
+		if math.IsNaN(b) {
+			return math.IsNaN(a)
+		}
+		if math.IsInf(b, 0) {
+			return a == b
+		}
+		return scalar.EqualWithinAbsOrRel(a, b, tol, tol)
+	}
+	return same(got.Statistic, want.Statistic) && same(got.DF, want.DF) && same(got.P, want.P) &&
+		same(got.Estimate, want.Estimate) && same(got.Lower, want.Lower) && same(got.Upper, want.Upper)
+}
+
+func TestRanks(t *testing.T) {
+	t.Parallel()
+	r, ties := ranks([]float64{3, 1, 4, 1, 5, 9, 2, 6, 5, 3, 5})
+	wantR := []float64{4.5, 1.5, 6, 1.5, 8, 11, 3, 10, 8, 4.5, 8}
+	if !floats.Equal(r, wantR) {
+		t.Errorf("unexpected ranks: got:%v want:%v", r, wantR)
+	}
+	wantTies := []int{2, 2, 3}
+	if len(ties) != len(wantTies) {
+		t.Fatalf("unexpected ties: got:%v want:%v", ties, wantTies)
+	}
+	for i, v := range ties {
+		if v != wantTies[i] {
+			t.Errorf("unexpected ties: got:%v want:%v", ties, wantTies)
+			break
+		}
+	}
+}
+
+func TestPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "one sample t", fn: func() { OneSampleT([]float64{1}, 0, TwoSided, 0.95) }},
+		{name: "level", fn: func() { OneSampleT([]float64{1, 2}, 0, TwoSided, 1) }},
+		{name: "alternative", fn: func() { OneSampleT([]float64{1, 2}, 0, Greater+1, 0.95) }},
+		{name: "paired t", fn: func() { PairedT([]float64{1, 2}, []float64{1, 2, 3}, 0, TwoSided, 0.95) }},
+		{name: "Mann-Whitney", fn: func() { MannWhitneyU(nil, []float64{1}, 0, TwoSided, Auto, 0.95) }},
+		{name: "Wilcoxon zeros", fn: func() { WilcoxonSignedRank([]float64{1, 1}, []float64{1, 1}, 0, TwoSided, Auto, 0.95) }},
+		{name: "chi-square df", fn: func() { ChiSquareGoodnessOfFit([]float64{1}, nil, 0) }},
+		{name: "Shapiro-Wilk size", fn: func() { ShapiroWilk([]float64{1, 2}) }},
+		{name: "Shapiro-Wilk range", fn: func() { ShapiroWilk([]float64{1, 1, 1}) }},
+		{name: "Kolmogorov-Smirnov", fn: func() { KolmogorovSmirnov([]float64{1}, nil, TwoSided, Auto) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
@@ -0,0 +1,129 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// KruskalWallis performs the Kruskal-Wallis rank-sum test of the null
+// hypothesis that the populations from which the groups are drawn have the
+// same distribution, against the alternative that at least one of them is
+// shifted relative to the others. The statistic is
+//
+//	H = 12/(N(N+1)) Σ_i R_i²/n_i - 3(N+1),
+//
+// where R_i is the sum of the ranks of group i in the combined sample of N
+// values, divided by 1 - Σ(t³-t)/(N³-N) to correct for groups of t tied
+// values. The p-value is computed from the asymptotic chi-square
+// distribution of H with k-1 degrees of freedom for k groups. The
+// Estimate, Lower and Upper fields of the result are NaN.
+//
+// KruskalWallis will panic if there are fewer than two groups, if any
+// group is empty, or if all the values are equal.
+//
+// References:
+//   - Kruskal, W. H. and Wallis, W. A. (1952). Use of ranks in
+//     one-criterion variance analysis. Journal of the American
+//     Statistical Association, 47(260), 583-621.
+func KruskalWallis(groups [][]float64) Result {
+	if len(groups) < 2 {
+		panic("hypothesis: too few groups")
+	}
+	var z []float64
+	for _, g := range groups {
+		if len(g) == 0 {
+			panic("hypothesis: too few samples")
+		}
+		z = append(z, g...)
+	}
+	r, ties := ranks(z)
+	n := float64(len(z))
+	correction := 1 - tieCorrection(ties)/(n*n*n-n)
+	if correction == 0 {
+		panic("hypothesis: all values equal")
+	}
+	var h float64
+	for _, g := range groups {
+		var sum float64
+		for _, v := range r[:len(g)] {
+			sum += v
+		}
+		r = r[len(g):]
+		h += sum * sum / float64(len(g))
+	}
+	h = (12/(n*(n+1))*h - 3*(n+1)) / correction
+	df := float64(len(groups) - 1)
+	return Result{
+		Statistic: h,
+		DF:        df,
+		P:         distuv.ChiSquared{K: df}.Survival(h),
+		Estimate:  math.NaN(),
+		Lower:     math.NaN(),
+		Upper:     math.NaN(),
+	}
+}
+
+// Friedman performs Friedman's rank-sum test of the null hypothesis that
+// there is no difference between the treatments in the columns of data,
+// with the rows of data holding the blocks of an unreplicated complete
+// block design, such as the measurements of each subject under each
+// treatment. The values are ranked within each block and the statistic is
+//
+//	Q = 12 Σ_j (R_j - n(k+1)/2)² / (nk(k+1) - Σ(t³-t)/(k-1)),
+//
+// where R_j is the sum of the ranks of treatment j over the n blocks, k is
+// the number of treatments and the sum of t³-t is over the groups of t
+// tied values within each block. The p-value is computed from the
+// asymptotic chi-square distribution of Q with k-1 degrees of freedom.
+// The Estimate, Lower and Upper fields of the result are NaN.
+//
+// Friedman will panic if data has fewer than two rows or columns, or if
+// the values in every block are all equal.
+//
+// References:
+//   - Friedman, M. (1937). The use of ranks to avoid the assumption of
+//     normality implicit in the analysis of variance. Journal of the
+//     American Statistical Association, 32(200), 675-701.
+func Friedman(data mat.Matrix) Result {
+	n, k := data.Dims()
+	if n < 2 || k < 2 {
+		panic("hypothesis: too few samples")
+	}
+	sums := make([]float64, k)
+	row := make([]float64, k)
+	var ties float64
+	for i := 0; i < n; i++ {
+		mat.Row(row, i, data)
+		r, t := ranks(row)
+		for j, v := range r {
+			sums[j] += v
+		}
+		ties += tieCorrection(t)
+	}
+	nf, kf := float64(n), float64(k)
+	denom := nf*kf*(kf+1) - ties/(kf-1)
+	if denom == 0 {
+		panic("hypothesis: all values equal")
+	}
+	var q float64
+	for _, s := range sums {
+		d := s - nf*(kf+1)/2
+		q += d * d
+	}
+	q *= 12 / denom
+	df := kf - 1
+	return Result{
+		Statistic: q,
+		DF:        df,
+		P:         distuv.ChiSquared{K: df}.Survival(q),
+		Estimate:  math.NaN(),
+		Lower:     math.NaN(),
+		Upper:     math.NaN(),
+	}
+}
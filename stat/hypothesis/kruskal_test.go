@@ -0,0 +1,110 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestKruskalWallis(t *testing.T) {
+	t.Parallel()
+	// Data from Hollander and Wolfe (1973), p. 116, with reference
+	// values from kruskal.test in R.
+	got := KruskalWallis([][]float64{
+		{2.9, 3.0, 2.5, 2.6, 3.2},
+		{3.8, 2.7, 4.0, 2.4},
+		{2.8, 3.4, 3.7, 2.2, 2.0},
+	})
+	if !scalar.EqualWithinAbs(got.Statistic, 0.77143, 5e-6) || got.DF != 2 || !scalar.EqualWithinAbs(got.P, 0.68, 5e-3) {
+		t.Errorf("unexpected result: got:%+v", got)
+	}
+	if !math.IsNaN(got.Estimate) || !math.IsNaN(got.Lower) || !math.IsNaN(got.Upper) {
+		t.Errorf("unexpected estimate: got:%+v", got)
+	}
+
+	// With two groups the statistic is the square of the
+	// normal approximation of the Mann-Whitney U test without
+	// continuity correction.
+	x := []float64{1.83, 0.50, 1.62, 2.48, 1.68, 1.88, 1.55, 3.06, 1.30}
+	y := []float64{0.878, 0.647, 0.598, 2.05, 1.06, 1.29, 1.06, 3.14, 1.29}
+	got = KruskalWallis([][]float64{x, y})
+	r, ties := ranks(append(append([]float64(nil), x...), y...))
+	var rx float64
+	for _, v := range r[:len(x)] {
+		rx += v
+	}
+	nx, ny := float64(len(x)), float64(len(y))
+	n := nx + ny
+	u := rx - nx*(nx+1)/2
+	variance := nx * ny / 12 * ((n + 1) - tieCorrection(ties)/(n*(n-1)))
+	want := (u - nx*ny/2) * (u - nx*ny/2) / variance
+	if !scalar.EqualWithinRel(got.Statistic, want, 1e-12) {
+		t.Errorf("unexpected statistic for two groups: got:%v want:%v", got.Statistic, want)
+	}
+}
+
+func TestFriedman(t *testing.T) {
+	t.Parallel()
+	// The RoundingTimes data from Hollander and Wolfe (1973),
+	// p. 140, with reference values from friedman.test in R.
+	data := mat.NewDense(22, 3, []float64{
+		5.40, 5.50, 5.55,
+		5.85, 5.70, 5.75,
+		5.20, 5.60, 5.50,
+		5.55, 5.50, 5.40,
+		5.90, 5.85, 5.70,
+		5.45, 5.55, 5.60,
+		5.40, 5.40, 5.35,
+		5.45, 5.50, 5.35,
+		5.25, 5.15, 5.00,
+		5.85, 5.80, 5.70,
+		5.25, 5.20, 5.10,
+		5.65, 5.55, 5.45,
+		5.60, 5.35, 5.45,
+		5.05, 5.00, 4.95,
+		5.50, 5.50, 5.40,
+		5.45, 5.55, 5.50,
+		5.55, 5.55, 5.35,
+		5.45, 5.50, 5.55,
+		5.50, 5.45, 5.25,
+		5.65, 5.60, 5.40,
+		5.70, 5.65, 5.55,
+		6.30, 6.30, 6.25,
+	})
+	got := Friedman(data)
+	if !scalar.EqualWithinAbs(got.Statistic, 11.143, 5e-4) || got.DF != 2 || !scalar.EqualWithinAbs(got.P, 0.003805, 5e-7) {
+		t.Errorf("unexpected result: got:%+v", got)
+	}
+
+	// The statistic is invariant to monotone transformations
+	// within each block.
+	trans := mat.NewDense(22, 3, nil)
+	trans.Apply(func(i, _ int, v float64) float64 { return math.Exp(v) * float64(i+1) }, data)
+	if q := Friedman(trans).Statistic; !scalar.EqualWithinRel(q, got.Statistic, 1e-12) {
+		t.Errorf("statistic not invariant to monotone transformation: got:%v want:%v", q, got.Statistic)
+	}
+}
+
+func TestKruskalFriedmanPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "KruskalWallis one group", fn: func() { KruskalWallis([][]float64{{1, 2}}) }},
+		{name: "KruskalWallis empty group", fn: func() { KruskalWallis([][]float64{{1, 2}, {}}) }},
+		{name: "KruskalWallis all equal", fn: func() { KruskalWallis([][]float64{{1, 1}, {1}}) }},
+		{name: "Friedman one block", fn: func() { Friedman(mat.NewDense(1, 3, []float64{1, 2, 3})) }},
+		{name: "Friedman all equal", fn: func() { Friedman(mat.NewDense(2, 2, []float64{1, 1, 2, 2})) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"sort"
+)
+
+// KolmogorovSmirnov performs the two-sample Kolmogorov-Smirnov test of the
+// null hypothesis that x and y are drawn from the same continuous
+// distribution. For the TwoSided alternative the statistic is the maximum
+// absolute difference between the empirical distribution functions of x
+// and y, F_x and F_y. For the Greater alternative, that F_x lies above F_y,
+// the statistic is the maximum of F_x - F_y, and for the Less alternative
+// it is the maximum of F_y - F_x.
+//
+// If method is Exact, the p-value is computed from the exact permutation
+// distribution of the statistic, conditional on any ties. If method is
+// Asymptotic, the p-value is computed from the limiting Kolmogorov
+// distribution for the TwoSided alternative and from exp(-2λ²) for the
+// one-sided alternatives, where λ is the statistic scaled by
+// sqrt(nm/(n+m)) for samples of sizes n and m. If method is Auto, the exact
+// distribution is used when nm is less than 10000.
+//
+// The DF, Estimate, Lower and Upper fields of the result are NaN.
+// KolmogorovSmirnov will panic if x or y is empty.
+//
+// References:
+//   - Schröer, G. and Trenkler, D. (1995). Exact and randomization
+//     distributions of Kolmogorov-Smirnov tests two or three samples.
+//     Computational Statistics & Data Analysis, 20(2), 185-202.
+func KolmogorovSmirnov(x, y []float64, alt Alternative, method Method) Result {
+	if len(x) == 0 || len(y) == 0 {
+		panic("hypothesis: too few samples")
+	}
+	checkAlternative(alt)
+	m, n := len(x), len(y)
+	xs := append([]float64(nil), x...)
+	ys := append([]float64(nil), y...)
+	sort.Float64s(xs)
+	sort.Float64s(ys)
+
+	// Merge the samples, recording after each element of the
+	// combined sample whether the following element differs from
+	// it, so that the empirical distribution functions can be
+	// compared only at the ends of runs of tied values.
+	fm, fn := float64(m), float64(n)
+	var dPlus, dMinus float64
+	check := make([]bool, m+n+1)
+	var i, j int
+	for i < m || j < n {
+		var v float64
+		if j == n || (i < m && xs[i] <= ys[j]) {
+			v = xs[i]
+			i++
+		} else {
+			v = ys[j]
+			j++
+		}
+		next := math.Inf(1)
+		if i < m {
+			next = xs[i]
+		}
+		if j < n && ys[j] < next {
+			next = ys[j]
+		}
+		if next != v {
+			check[i+j] = true
+			d := float64(i)/fm - float64(j)/fn
+			dPlus = math.Max(dPlus, d)
+			dMinus = math.Max(dMinus, -d)
+		}
+	}
+	var d float64
+	switch alt {
+	case Greater:
+		d = dPlus
+	case Less:
+		d = dMinus
+	default:
+		d = math.Max(dPlus, dMinus)
+	}
+	res := Result{
+		Statistic: d,
+		DF:        math.NaN(),
+		Estimate:  math.NaN(),
+		Lower:     math.NaN(),
+		Upper:     math.NaN(),
+	}
+	if method == Exact || (method == Auto && m*n < 10000) {
+		res.P = smirnovExact(d, m, n, check, alt)
+	} else {
+		lambda := d * math.Sqrt(fm*fn/(fm+fn))
+		if alt == TwoSided {
+			res.P = kolmogorovSurvival(lambda)
+		} else {
+			res.P = math.Exp(-2 * lambda * lambda)
+		}
+	}
+	return res
+}
+
+// smirnovExact returns the probability that the two-sample
+// Kolmogorov-Smirnov statistic for samples of sizes m and n is at least d,
+// with the difference between the empirical distribution functions
+// evaluated at positions k of the merged sample where check[k] is true.
+func smirnovExact(d float64, m, n int, check []bool, alt Alternative) float64 {
+	// Allow for rounding in the differences of the empirical
+	// distribution functions.
+	d -= 1e-7 / float64(m*n)
+	fm, fn := float64(m), float64(n)
+	reached := func(i, j int) bool {
+		if !check[i+j] {
+			return false
+		}
+		diff := float64(i)/fm - float64(j)/fn
+		switch alt {
+		case Greater:
+			return diff >= d
+		case Less:
+			return -diff >= d
+		default:
+			return math.Abs(diff) >= d
+		}
+	}
+	// u[j] is the probability that a random path from (0, 0) to
+	// (i, j) does not reach the statistic, where a step in i takes
+	// an element of the first sample and a step in j an element of
+	// the second.
+	u := make([]float64, n+1)
+	u[0] = 1
+	for j := 1; j <= n; j++ {
+		u[j] = u[j-1]
+		if reached(0, j) {
+			u[j] = 0
+		}
+	}
+	for i := 1; i <= m; i++ {
+		if reached(i, 0) {
+			u[0] = 0
+		}
+		for j := 1; j <= n; j++ {
+			if reached(i, j) {
+				u[j] = 0
+				continue
+			}
+			fi, fj := float64(i), float64(j)
+			u[j] = (fi*u[j] + fj*u[j-1]) / (fi + fj)
+		}
+	}
+	return math.Max(0, math.Min(1, 1-u[n]))
+}
+
+// kolmogorovSurvival returns the probability that the Kolmogorov
+// distribution exceeds lambda.
+func kolmogorovSurvival(lambda float64) float64 {
+	if lambda <= 0 {
+		return 1
+	}
+	if lambda < 1 {
+		// Use the series for the distribution function, which
+		// converges rapidly for small lambda.
+		var s float64
+		f := -math.Pi * math.Pi / (8 * lambda * lambda)
+		for k := 1; k < 20; k += 2 {
+			s += math.Exp(float64(k*k) * f)
+		}
+		return 1 - math.Sqrt(2*math.Pi)/lambda*s
+	}
+	var s float64
+	sign := 1.0
+	for k := 1; k <= 100; k++ {
+		t := math.Exp(-2 * float64(k*k) * lambda * lambda)
+		s += sign * t
+		if t < 1e-17 {
+			break
+		}
+		sign = -sign
+	}
+	return math.Max(0, math.Min(1, 2*s))
+}
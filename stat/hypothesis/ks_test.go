@@ -0,0 +1,125 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestKolmogorovSmirnovStatistic(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, ties := range []bool{false, true} {
+		x := make([]float64, 20)
+		y := make([]float64, 13)
+		for i := range x {
+			x[i] = rnd.NormFloat64()
+			if ties {
+				x[i] = float64(rnd.Intn(5))
+			}
+		}
+		for i := range y {
+			y[i] = rnd.NormFloat64() + 0.5
+			if ties {
+				y[i] = float64(rnd.Intn(5))
+			}
+		}
+		got := KolmogorovSmirnov(x, y, TwoSided, Auto).Statistic
+		xs := append([]float64(nil), x...)
+		ys := append([]float64(nil), y...)
+		stat.SortWeighted(xs, nil)
+		stat.SortWeighted(ys, nil)
+		want := stat.KolmogorovSmirnov(xs, nil, ys, nil)
+		if !scalar.EqualWithinAbs(got, want, 1e-14) {
+			t.Errorf("unexpected statistic with ties=%t: got:%v want:%v", ties, got, want)
+		}
+		plus := KolmogorovSmirnov(x, y, Greater, Auto).Statistic
+		minus := KolmogorovSmirnov(x, y, Less, Auto).Statistic
+		if got != plus && got != minus {
+			t.Errorf("two-sided statistic is neither one-sided statistic: %v %v %v", got, plus, minus)
+		}
+	}
+}
+
+func TestKolmogorovSmirnovExact(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, size := range [][2]int{{1, 1}, {2, 3}, {4, 4}, {5, 7}, {3, 10}} {
+		for _, ties := range []bool{false, true} {
+			z := make([]float64, size[0]+size[1])
+			for i := range z {
+				if ties {
+					z[i] = float64(rnd.Intn(4))
+				} else {
+					z[i] = rnd.NormFloat64()
+				}
+			}
+			x, y := z[:size[0]], z[size[0]:]
+			for _, alt := range []Alternative{TwoSided, Less, Greater} {
+				name := fmt.Sprintf("n=%d m=%d ties=%t alternative=%d", size[0], size[1], ties, alt)
+				_, want := permutationP(z, size[0], func(x, y []float64) float64 {
+					return KolmogorovSmirnov(x, y, alt, Asymptotic).Statistic
+				})
+				got := KolmogorovSmirnov(x, y, alt, Exact)
+				if !scalar.EqualWithinAbs(got.P, want, 1e-12) {
+					t.Errorf("unexpected p-value for %s: got:%v want:%v", name, got.P, want)
+				}
+			}
+		}
+	}
+}
+
+func TestKolmogorovSurvival(t *testing.T) {
+	t.Parallel()
+	// Critical values of the Kolmogorov distribution.
+	for _, test := range []struct {
+		lambda, want float64
+	}{
+		{lambda: 0, want: 1},
+		{lambda: 0.5, want: 0.963945},
+		{lambda: 1, want: 0.2699996},
+		{lambda: 1.22385, want: 0.1},
+		{lambda: 1.35810, want: 0.05},
+		{lambda: 1.62762, want: 0.01},
+	} {
+		got := kolmogorovSurvival(test.lambda)
+		if !scalar.EqualWithinAbs(got, test.want, 1e-5) {
+			t.Errorf("unexpected survival at %v: got:%v want:%v", test.lambda, got, test.want)
+		}
+	}
+	// The two series agree where they meet.
+	below := kolmogorovSurvival(1 - 1e-12)
+	above := kolmogorovSurvival(1)
+	if !scalar.EqualWithinAbs(below, above, 1e-10) {
+		t.Errorf("discontinuity at one: %v %v", below, above)
+	}
+}
+
+func TestKolmogorovSmirnovAsymptotic(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, 80)
+	y := make([]float64, 70)
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+	}
+	for i := range y {
+		y[i] = rnd.NormFloat64() + 0.3
+	}
+	// The asymptotic p-value approximates the exact p-value.
+	for _, alt := range []Alternative{TwoSided, Less, Greater} {
+		exact := KolmogorovSmirnov(x, y, alt, Exact).P
+		asymptotic := KolmogorovSmirnov(x, y, alt, Asymptotic).P
+		if !scalar.EqualWithinAbs(exact, asymptotic, 0.02) {
+			t.Errorf("asymptotic p-value far from exact for alternative=%d: exact:%v asymptotic:%v", alt, exact, asymptotic)
+		}
+	}
+}
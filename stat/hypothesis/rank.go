@@ -0,0 +1,363 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// MannWhitneyU performs the Mann-Whitney U test, also known as the
+// Wilcoxon rank-sum test, of the null hypothesis that the distributions of
+// the populations from which x-mu and y are drawn are equal, against the
+// alternative that the distribution of x-mu is shifted relative to that of
+// y. The statistic is U = R - n(n+1)/2, where R is the sum of the ranks of
+// x-mu in the combined sample and n is the length of x, with tied values
+// given their mean rank.
+//
+// If method is Exact, the p-value is computed from the exact permutation
+// distribution of U conditional on the ties. If method is Asymptotic, the
+// p-value is computed from the normal approximation with a continuity and
+// tie correction. If method is Auto, the exact distribution is used when x
+// and y have fewer than 50 elements each and there are no ties.
+//
+// The Estimate of the result is the Hodges-Lehmann estimate of the
+// location shift, the median of the differences x[i]-y[j], and Lower and
+// Upper bound the distribution-free confidence interval for the shift at
+// the given confidence level, obtained from order statistics of the
+// differences using the exact distribution of U without ties, or its
+// normal approximation.
+//
+// MannWhitneyU will panic if x or y is empty, or if level is not in
+// (0, 1).
+//
+// References:
+//   - Hollander, M. and Wolfe, D. A. (1999). Nonparametric Statistical
+//     Methods, 2nd edition. Wiley.
+func MannWhitneyU(x, y []float64, mu float64, alt Alternative, method Method, level float64) Result {
+	if len(x) == 0 || len(y) == 0 {
+		panic("hypothesis: too few samples")
+	}
+	checkAlternative(alt)
+	checkLevel(level)
+	nx, ny := len(x), len(y)
+	z := make([]float64, 0, nx+ny)
+	for _, v := range x {
+		z = append(z, v-mu)
+	}
+	z = append(z, y...)
+	r, ties := ranks(z)
+	var rx float64
+	for _, v := range r[:nx] {
+		rx += v
+	}
+	u := rx - float64(nx*(nx+1))/2
+	res := Result{Statistic: u, DF: math.NaN()}
+
+	exact := method == Exact || (method == Auto && nx < 50 && ny < 50 && len(ties) == 0)
+	var untied []float64
+	if exact {
+		r2 := make([]int, len(r))
+		for i, v := range r {
+			r2[i] = int(2 * v)
+		}
+		dist := rankSumDist(r2, nx)
+		if len(ties) == 0 {
+			untied = dist
+		}
+		lower, upper := cdfTails(dist, int(math.Round(2*rx)))
+		res.P = tails(alt, lower, upper)
+	} else {
+		n := float64(nx + ny)
+		m := float64(nx) * float64(ny)
+		sigma := math.Sqrt(m / 12 * ((n + 1) - tieCorrection(ties)/(n*(n-1))))
+		res.P = normalTails(alt, u-m/2, sigma)
+	}
+
+	d := make([]float64, 0, nx*ny)
+	for _, a := range x {
+		for _, b := range y {
+			d = append(d, a-b)
+		}
+	}
+	sort.Float64s(d)
+	res.Estimate = median(d)
+	var q func(p float64) int
+	if exact {
+		if untied == nil {
+			r2 := make([]int, nx+ny)
+			for i := range r2 {
+				r2[i] = 2 * (i + 1)
+			}
+			untied = rankSumDist(r2, nx)
+		}
+		// Convert from the doubled rank sum to U.
+		off := nx * (nx + 1)
+		q = func(p float64) int { return (quantile(untied, p) - off) / 2 }
+	} else {
+		m := float64(nx) * float64(ny)
+		sigma := math.Sqrt(m * float64(nx+ny+1) / 12)
+		q = normalQuantile(m/2, sigma, len(d))
+	}
+	res.Lower, res.Upper = orderInterval(d, q, alt, level)
+	return res
+}
+
+// WilcoxonSignedRank performs the Wilcoxon signed-rank test of the null
+// hypothesis that the distribution of x-mu is symmetric about zero. If y is
+// not nil, the test is performed on the paired differences x[i]-y[i]-mu.
+// The differences that are zero are discarded and the remaining
+// differences are ranked by magnitude, with tied values given their mean
+// rank. The statistic is the sum of the ranks of the positive differences.
+//
+// If method is Exact, the p-value is computed from the exact distribution
+// of the statistic conditional on the ties. If method is Asymptotic, the
+// p-value is computed from the normal approximation with a continuity and
+// tie correction. If method is Auto, the exact distribution is used when
+// there are fewer than 50 differences and there are no ties or zeros.
+//
+// The Estimate of the result is the Hodges-Lehmann estimate of the center
+// of symmetry of x (or x-y), the median of the Walsh averages of the
+// non-zero differences shifted back by mu, and Lower and Upper bound the
+// distribution-free confidence interval for the center at the given
+// confidence level, obtained from order statistics of the Walsh averages
+// using the exact distribution of the statistic without ties, or its
+// normal approximation.
+//
+// WilcoxonSignedRank will panic if y is not nil and has a different length
+// to x, if all the differences are zero, or if level is not in (0, 1).
+func WilcoxonSignedRank(x, y []float64, mu float64, alt Alternative, method Method, level float64) Result {
+	if y != nil && len(y) != len(x) {
+		panic("hypothesis: slice length mismatch")
+	}
+	checkAlternative(alt)
+	checkLevel(level)
+	d := make([]float64, 0, len(x))
+	for i, v := range x {
+		if y != nil {
+			v -= y[i]
+		}
+		v -= mu
+		if v != 0 {
+			d = append(d, v)
+		}
+	}
+	zeros := len(d) != len(x)
+	n := len(d)
+	if n == 0 {
+		panic("hypothesis: no non-zero differences")
+	}
+	abs := make([]float64, n)
+	for i, v := range d {
+		abs[i] = math.Abs(v)
+	}
+	r, ties := ranks(abs)
+	var v float64
+	for i, di := range d {
+		if di > 0 {
+			v += r[i]
+		}
+	}
+	res := Result{Statistic: v, DF: math.NaN()}
+
+	exact := method == Exact || (method == Auto && n < 50 && len(ties) == 0 && !zeros)
+	var untied []float64
+	if exact {
+		r2 := make([]int, n)
+		for i, v := range r {
+			r2[i] = int(2 * v)
+		}
+		dist := signRankDist(r2)
+		if len(ties) == 0 {
+			untied = dist
+		}
+		lower, upper := cdfTails(dist, int(math.Round(2*v)))
+		res.P = tails(alt, lower, upper)
+	} else {
+		fn := float64(n)
+		sigma := math.Sqrt(fn*(fn+1)*(2*fn+1)/24 - tieCorrection(ties)/48)
+		res.P = normalTails(alt, v-fn*(fn+1)/4, sigma)
+	}
+
+	walsh := make([]float64, 0, n*(n+1)/2)
+	for i, a := range d {
+		for _, b := range d[i:] {
+			walsh = append(walsh, (a+b)/2+mu)
+		}
+	}
+	sort.Float64s(walsh)
+	res.Estimate = median(walsh)
+	var q func(p float64) int
+	if exact {
+		if untied == nil {
+			r2 := make([]int, n)
+			for i := range r2 {
+				r2[i] = 2 * (i + 1)
+			}
+			untied = signRankDist(r2)
+		}
+		q = func(p float64) int { return quantile(untied, p) / 2 }
+	} else {
+		fn := float64(n)
+		q = normalQuantile(fn*(fn+1)/4, math.Sqrt(fn*(fn+1)*(2*fn+1)/24), len(walsh))
+	}
+	res.Lower, res.Upper = orderInterval(walsh, q, alt, level)
+	return res
+}
+
+// rankSumDist returns the distribution of the sum of n of the values in
+// r, chosen uniformly at random without replacement. Element s of the
+// returned slice is the probability that the sum is s.
+func rankSumDist(r []int, n int) []float64 {
+	var total int
+	for _, v := range r {
+		total += v
+	}
+	// count[k][s] is the number of subsets of size k
+	// of the values considered so far that sum to s.
+	count := make([][]float64, n+1)
+	for k := range count {
+		count[k] = make([]float64, total+1)
+	}
+	count[0][0] = 1
+	var sum int
+	for i, v := range r {
+		sum += v
+		hi := i + 1
+		if hi > n {
+			hi = n
+		}
+		for k := hi; k > 0; k-- {
+			prev, cur := count[k-1], count[k]
+			for s := sum; s >= v; s-- {
+				cur[s] += prev[s-v]
+			}
+		}
+	}
+	dist := count[n]
+	var norm float64
+	for _, c := range dist {
+		norm += c
+	}
+	for s := range dist {
+		dist[s] /= norm
+	}
+	return dist
+}
+
+// signRankDist returns the distribution of the sum of the values in r
+// that are included independently with probability one half. Element s
+// of the returned slice is the probability that the sum is s.
+func signRankDist(r []int) []float64 {
+	var total int
+	for _, v := range r {
+		total += v
+	}
+	dist := make([]float64, total+1)
+	dist[0] = 1
+	var sum int
+	for _, v := range r {
+		sum += v
+		for s := sum; s >= 0; s-- {
+			p := dist[s] / 2
+			if s >= v {
+				p += dist[s-v] / 2
+			}
+			dist[s] = p
+		}
+	}
+	return dist
+}
+
+// cdfTails returns the probabilities that a statistic with the discrete
+// distribution dist is at most s and at least s.
+func cdfTails(dist []float64, s int) (lower, upper float64) {
+	for k, p := range dist {
+		if k <= s {
+			lower += p
+		}
+		if k >= s {
+			upper += p
+		}
+	}
+	return math.Min(lower, 1), math.Min(upper, 1)
+}
+
+// quantile returns the smallest s such that the probability that a
+// statistic with the discrete distribution dist is at most s is at least
+// p.
+func quantile(dist []float64, p float64) int {
+	// Allow for rounding in the accumulated probabilities.
+	p *= 1 - 64*eps
+	var cdf float64
+	for s, v := range dist {
+		cdf += v
+		if cdf >= p {
+			return s
+		}
+	}
+	return len(dist) - 1
+}
+
+const eps = 1.0 / (1 << 52)
+
+// normalTails returns the p-value of the centered statistic z with
+// standard deviation sigma from the normal approximation with a continuity
+// correction.
+func normalTails(alt Alternative, z, sigma float64) float64 {
+	var corr float64
+	switch alt {
+	case Less:
+		corr = -0.5
+	case Greater:
+		corr = 0.5
+	default:
+		if z > 0 {
+			corr = 0.5
+		} else if z < 0 {
+			corr = -0.5
+		}
+	}
+	z = (z - corr) / sigma
+	return tails(alt, distuv.UnitNormal.CDF(z), distuv.UnitNormal.Survival(z))
+}
+
+// normalQuantile returns a function that approximates the quantiles of
+// a discrete statistic on [0, n] with mean mu and standard deviation
+// sigma using the normal approximation with a continuity correction.
+func normalQuantile(mu, sigma float64, n int) func(p float64) int {
+	return func(p float64) int {
+		s := math.Ceil(mu + distuv.UnitNormal.Quantile(p)*sigma - 0.5)
+		return int(math.Max(0, math.Min(float64(n), s)))
+	}
+}
+
+// orderInterval returns the confidence interval for a location parameter
+// at the given level from the sorted values d using the quantile function
+// q of the null distribution of the corresponding rank statistic.
+func orderInterval(d []float64, q func(p float64) int, alt Alternative, level float64) (lower, upper float64) {
+	m := len(d)
+	alpha := 1 - level
+	if alt == TwoSided {
+		alpha /= 2
+	}
+	k := q(alpha)
+	if k == 0 {
+		k = 1
+	}
+	if k > m {
+		k = m
+	}
+	lower, upper = d[k-1], d[m-k]
+	switch alt {
+	case Less:
+		lower = math.Inf(-1)
+	case Greater:
+		upper = math.Inf(1)
+	}
+	return lower, upper
+}
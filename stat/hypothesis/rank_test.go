@@ -0,0 +1,227 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/stat/combin"
+)
+
+func TestRankTestsSleep(t *testing.T) {
+	t.Parallel()
+	// Reference values from R's wilcox.test. The data
+	// have ties, so the normal approximation is used.
+	got := MannWhitneyU(sleep1, sleep2, 0, TwoSided, Auto, 0.95)
+	if !scalar.EqualWithinAbs(got.Statistic, 25.5, 1e-12) || !scalar.EqualWithinAbs(got.P, 0.06933, 5e-6) {
+		t.Errorf("unexpected Mann-Whitney result: got statistic:%v p:%v want statistic:25.5 p:0.06933", got.Statistic, got.P)
+	}
+	got = WilcoxonSignedRank(sleep1, sleep2, 0, TwoSided, Auto, 0.95)
+	if !scalar.EqualWithinAbs(got.Statistic, 0, 1e-12) || !scalar.EqualWithinAbs(got.P, 0.009091, 5e-7) {
+		t.Errorf("unexpected Wilcoxon result: got statistic:%v p:%v want statistic:0 p:0.009091", got.Statistic, got.P)
+	}
+}
+
+// subsets calls fn with each subset of size k of n elements,
+// represented as a membership mask.
+func subsets(n, k int, fn func(in []bool)) {
+	in := make([]bool, n)
+	for _, c := range combin.Combinations(n, k) {
+		for i := range in {
+			in[i] = false
+		}
+		for _, i := range c {
+			in[i] = true
+		}
+		fn(in)
+	}
+}
+
+// permutationP returns the lower and upper tail probabilities of the
+// observed statistic over all assignments of n of the values in z to the
+// first sample.
+func permutationP(z []float64, n int, statistic func(x, y []float64) float64) (lower, upper float64) {
+	obs := statistic(z[:n], z[n:])
+	var count int
+	subsets(len(z), n, func(in []bool) {
+		var x, y []float64
+		for i, v := range z {
+			if in[i] {
+				x = append(x, v)
+			} else {
+				y = append(y, v)
+			}
+		}
+		s := statistic(x, y)
+		if s <= obs+1e-9 {
+			lower++
+		}
+		if s >= obs-1e-9 {
+			upper++
+		}
+		count++
+	})
+	return lower / float64(count), upper / float64(count)
+}
+
+func TestMannWhitneyExact(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	u := func(x, y []float64) float64 {
+		return MannWhitneyU(x, y, 0, TwoSided, Asymptotic, 0.95).Statistic
+	}
+	for _, size := range [][2]int{{1, 1}, {2, 3}, {4, 4}, {5, 7}, {3, 9}} {
+		for _, ties := range []bool{false, true} {
+			z := make([]float64, size[0]+size[1])
+			for i := range z {
+				if ties {
+					z[i] = float64(rnd.Intn(4))
+				} else {
+					z[i] = rnd.NormFloat64()
+				}
+			}
+			x, y := z[:size[0]], z[size[0]:]
+			lower, upper := permutationP(z, size[0], u)
+			for _, alt := range []Alternative{TwoSided, Less, Greater} {
+				name := fmt.Sprintf("n=%d m=%d ties=%t alternative=%d", size[0], size[1], ties, alt)
+				got := MannWhitneyU(x, y, 0, alt, Exact, 0.95)
+				want := tails(alt, lower, upper)
+				if !scalar.EqualWithinAbs(got.P, want, 1e-12) {
+					t.Errorf("unexpected p-value for %s: got:%v want:%v", name, got.P, want)
+				}
+			}
+		}
+	}
+}
+
+func TestWilcoxonExact(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 9, 12} {
+		for _, ties := range []bool{false, true} {
+			d := make([]float64, n)
+			for i := range d {
+				if ties {
+					d[i] = float64(rnd.Intn(7) - 3)
+				} else {
+					d[i] = rnd.NormFloat64()
+				}
+			}
+			if ties && n > 1 {
+				d[0] = 1 // Ensure a non-zero difference.
+			}
+			var nz []float64
+			for _, v := range d {
+				if v != 0 {
+					nz = append(nz, v)
+				}
+			}
+			if len(nz) == 0 {
+				continue
+			}
+			abs := make([]float64, len(nz))
+			for i, v := range nz {
+				abs[i] = math.Abs(v)
+			}
+			r, _ := ranks(abs)
+			obs := WilcoxonSignedRank(d, nil, 0, TwoSided, Asymptotic, 0.95).Statistic
+			var lower, upper float64
+			for mask := 0; mask < 1<<len(nz); mask++ {
+				var s float64
+				for i, v := range r {
+					if mask&(1<<i) != 0 {
+						s += v
+					}
+				}
+				if s <= obs+1e-9 {
+					lower++
+				}
+				if s >= obs-1e-9 {
+					upper++
+				}
+			}
+			lower /= float64(int(1) << len(nz))
+			upper /= float64(int(1) << len(nz))
+			for _, alt := range []Alternative{TwoSided, Less, Greater} {
+				got := WilcoxonSignedRank(d, nil, 0, alt, Exact, 0.95)
+				want := tails(alt, lower, upper)
+				if !scalar.EqualWithinAbs(got.P, want, 1e-12) {
+					t.Errorf("unexpected p-value for n=%d ties=%t alternative=%d: got:%v want:%v", n, ties, alt, got.P, want)
+				}
+			}
+		}
+	}
+}
+
+func TestRankConfidenceIntervals(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, 12)
+	y := make([]float64, 15)
+	for i := range x {
+		x[i] = rnd.NormFloat64() + 1
+	}
+	for i := range y {
+		y[i] = rnd.NormFloat64()
+	}
+	for _, method := range []Method{Exact, Asymptotic} {
+		for _, alt := range []Alternative{TwoSided, Less, Greater} {
+			// The estimate of the shift is the median of the
+			// pairwise differences, and the confidence bounds
+			// are shifts for which the test is just rejected at
+			// the given level.
+			r := MannWhitneyU(x, y, 0, alt, method, 0.9)
+			var d []float64
+			for _, a := range x {
+				for _, b := range y {
+					d = append(d, a-b)
+				}
+			}
+			sort.Float64s(d)
+			if r.Estimate != median(d) {
+				t.Errorf("unexpected Mann-Whitney estimate: got:%v want:%v", r.Estimate, median(d))
+			}
+			checkBounds(t, fmt.Sprintf("Mann-Whitney method=%d alternative=%d", method, alt), r, func(mu float64, alt Alternative) float64 {
+				return MannWhitneyU(x, y, mu, alt, method, 0.9).P
+			}, alt, 0.1)
+
+			r = WilcoxonSignedRank(x, nil, 0, alt, method, 0.9)
+			checkBounds(t, fmt.Sprintf("Wilcoxon method=%d alternative=%d", method, alt), r, func(mu float64, alt Alternative) float64 {
+				return WilcoxonSignedRank(x, nil, mu, alt, method, 0.9).P
+			}, alt, 0.1)
+		}
+	}
+}
+
+// checkBounds checks that the test with the null value just inside the
+// confidence interval of r is not rejected at the level alpha, and that
+// the interval contains the estimate.
+func checkBounds(t *testing.T, name string, r Result, p func(mu float64, alt Alternative) float64, alt Alternative, alpha float64) {
+	t.Helper()
+	if !(r.Lower <= r.Estimate && r.Estimate <= r.Upper) {
+		t.Errorf("estimate not within confidence interval for %s: %v not in [%v, %v]", name, r.Estimate, r.Lower, r.Upper)
+	}
+	const delta = 1e-9
+	// The exact quantiles are conservative and the normal
+	// approximation of the interval differs from that of the
+	// p-value by the continuity and tie corrections, so allow
+	// a margin.
+	if !math.IsInf(r.Lower, 0) {
+		if got := p(r.Lower+delta, alt); got < alpha*0.8 {
+			t.Errorf("test rejected inside lower confidence bound for %s: p=%v", name, got)
+		}
+	}
+	if !math.IsInf(r.Upper, 0) {
+		if got := p(r.Upper-delta, alt); got < alpha*0.8 {
+			t.Errorf("test rejected inside upper confidence bound for %s: p=%v", name, got)
+		}
+	}
+}
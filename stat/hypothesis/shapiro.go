@@ -0,0 +1,140 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// ShapiroWilk performs the Shapiro-Wilk test of the null hypothesis that x
+// is drawn from a normal distribution. The statistic W is the squared
+// correlation between the ordered sample and the approximate expected
+// normal order statistics, and small values of W indicate departure from
+// normality. The coefficients of W and its p-value are computed by the
+// approximations of Royston, which are valid for samples of 3 to 5000
+// elements. The p-value is exact for samples of three elements. The DF,
+// Estimate, Lower and Upper fields of the result are NaN.
+//
+// ShapiroWilk will panic if x has fewer than 3 or more than 5000 elements,
+// or if all the elements of x are equal.
+//
+// References:
+//   - Shapiro, S. S. and Wilk, M. B. (1965). An analysis of variance test
+//     for normality (complete samples). Biometrika, 52(3-4), 591-611.
+//   - Royston, P. (1995). Remark AS R94: A remark on algorithm AS 181: The
+//     W-test for normality. Applied Statistics, 44(4), 547-551.
+func ShapiroWilk(x []float64) Result {
+	n := len(x)
+	if n < 3 || 5000 < n {
+		panic("hypothesis: sample size out of range")
+	}
+	x = append([]float64(nil), x...)
+	sort.Float64s(x)
+	if x[n-1]-x[0] == 0 {
+		panic("hypothesis: all values equal")
+	}
+
+	a := swilkCoeffs(n)
+	var mean float64
+	for _, v := range x {
+		mean += v
+	}
+	mean /= float64(n)
+	var num, ss float64
+	for i, c := range a {
+		num += c * (x[n-1-i] - x[i])
+	}
+	for _, v := range x {
+		d := v - mean
+		ss += d * d
+	}
+	w := num * num / ss
+	if w > 1 {
+		w = 1
+	}
+	res := Result{
+		Statistic: w,
+		DF:        math.NaN(),
+		Estimate:  math.NaN(),
+		Lower:     math.NaN(),
+		Upper:     math.NaN(),
+	}
+
+	if n == 3 {
+		// The exact p-value.
+		const stqr = math.Pi / 3 // asin(sqrt(3/4))
+		res.P = math.Max(0, 6/math.Pi*(math.Asin(math.Sqrt(w))-stqr))
+		return res
+	}
+	an := float64(n)
+	y := math.Log(1 - w)
+	var m, s float64
+	if n <= 11 {
+		gamma := poly([]float64{-2.273, 0.459}, an)
+		if y >= gamma {
+			res.P = 0
+			return res
+		}
+		y = -math.Log(gamma - y)
+		m = poly([]float64{0.544, -0.39978, 0.025054, -6.714e-4}, an)
+		s = math.Exp(poly([]float64{1.3822, -0.77857, 0.062767, -0.0020322}, an))
+	} else {
+		xx := math.Log(an)
+		m = poly([]float64{-1.5861, -0.31082, -0.083751, 0.0038915}, xx)
+		s = math.Exp(poly([]float64{-0.4803, -0.082676, 0.0030302}, xx))
+	}
+	res.P = distuv.Normal{Mu: m, Sigma: s}.Survival(y)
+	return res
+}
+
+// swilkCoeffs returns the first n/2 coefficients of the Shapiro-Wilk
+// statistic for a sample of size n, with the remaining coefficients given
+// by antisymmetry.
+func swilkCoeffs(n int) []float64 {
+	a := make([]float64, n/2)
+	if n == 3 {
+		a[0] = math.Sqrt(0.5)
+		return a
+	}
+	an := float64(n)
+	var summ2 float64
+	for i := range a {
+		m := distuv.UnitNormal.Quantile((float64(i+1) - 0.375) / (an + 0.25))
+		a[i] = -m
+		summ2 += m * m
+	}
+	summ2 *= 2
+	ssumm2 := math.Sqrt(summ2)
+	rsn := 1 / math.Sqrt(an)
+	a1 := poly([]float64{0, 0.221157, -0.147981, -2.07119, 4.434685, -2.706056}, rsn) + a[0]/ssumm2
+	var fac float64
+	i1 := 1
+	if n > 5 {
+		i1 = 2
+		a2 := a[1]/ssumm2 + poly([]float64{0, 0.042981, -0.293762, -1.752461, 5.682633, -3.582633}, rsn)
+		fac = math.Sqrt((summ2 - 2*a[0]*a[0] - 2*a[1]*a[1]) / (1 - 2*a1*a1 - 2*a2*a2))
+		a[1] = a2
+	} else {
+		fac = math.Sqrt((summ2 - 2*a[0]*a[0]) / (1 - 2*a1*a1))
+	}
+	a[0] = a1
+	for i := i1; i < len(a); i++ {
+		a[i] /= fac
+	}
+	return a
+}
+
+// poly returns the value of the polynomial with coefficients c in order
+// of increasing degree at x.
+func poly(c []float64, x float64) float64 {
+	var p float64
+	for i := len(c) - 1; i >= 0; i-- {
+		p = p*x + c[i]
+	}
+	return p
+}
@@ -0,0 +1,109 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestShapiroWilk(t *testing.T) {
+	t.Parallel()
+	// The weights of eleven men from the example of
+	// Shapiro and Wilk (1965), who report W = 0.79.
+	weights := []float64{148, 154, 158, 160, 161, 162, 166, 170, 182, 195, 236}
+	got := ShapiroWilk(weights)
+	if !scalar.EqualWithinAbs(got.Statistic, 0.79, 5e-3) || !(got.P < 0.01) {
+		t.Errorf("unexpected result: got statistic:%v p:%v want statistic:0.79 p<0.01", got.Statistic, got.P)
+	}
+
+	// For three elements W is the exact squared correlation
+	// with the expected order statistics (-1, 0, 1).
+	x := []float64{1, 2, 4}
+	mean := 7.0 / 3
+	var ss float64
+	for _, v := range x {
+		ss += (v - mean) * (v - mean)
+	}
+	w := 4.5 / ss // (a·x)² with a = (-1/√2, 0, 1/√2).
+	got = ShapiroWilk(x)
+	if !scalar.EqualWithinAbs(got.Statistic, w, 1e-14) {
+		t.Errorf("unexpected statistic for three elements: got:%v want:%v", got.Statistic, w)
+	}
+	if p := 6 / math.Pi * (math.Asin(math.Sqrt(w)) - math.Pi/3); !scalar.EqualWithinAbs(got.P, p, 1e-14) {
+		t.Errorf("unexpected p-value for three elements: got:%v want:%v", got.P, p)
+	}
+}
+
+func TestShapiroWilkCoefficients(t *testing.T) {
+	t.Parallel()
+	for n := 3; n <= 5000; n = n*3/2 + 1 {
+		a := swilkCoeffs(n)
+		var ss float64
+		for i, v := range a {
+			ss += 2 * v * v
+			if i > 0 && v > a[i-1] {
+				t.Errorf("coefficients not decreasing for n=%d", n)
+			}
+		}
+		if !scalar.EqualWithinAbs(ss, 1, 1e-12) {
+			t.Errorf("coefficients not normalized for n=%d: sum of squares %v", n, ss)
+		}
+	}
+	// Coefficients tabulated by Shapiro and Wilk (1965).
+	for _, test := range []struct {
+		n    int
+		want []float64
+	}{
+		{n: 10, want: []float64{0.5739, 0.3291, 0.2141, 0.1224, 0.0399}},
+		{n: 20, want: []float64{0.4734, 0.3211, 0.2565, 0.2085, 0.1686, 0.1334, 0.1013, 0.0711, 0.0422, 0.0140}},
+	} {
+		a := swilkCoeffs(test.n)
+		for i, v := range test.want {
+			if !scalar.EqualWithinAbs(a[i], v, 2e-3) {
+				t.Errorf("unexpected coefficient %d for n=%d: got:%v want:%v", i, test.n, a[i], v)
+			}
+		}
+	}
+}
+
+func TestShapiroWilkLevel(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const trials = 2000
+	for _, n := range []int{5, 11, 12, 50, 200} {
+		// For normal samples the p-value is uniformly
+		// distributed, and for uniform samples of sufficient
+		// size normality is rejected.
+		var rejected, rejectedUniform int
+		x := make([]float64, n)
+		for i := 0; i < trials; i++ {
+			for j := range x {
+				x[j] = rnd.NormFloat64()
+			}
+			if ShapiroWilk(x).P < 0.05 {
+				rejected++
+			}
+			for j := range x {
+				x[j] = rnd.Float64()
+			}
+			if ShapiroWilk(x).P < 0.05 {
+				rejectedUniform++
+			}
+		}
+		if rate := float64(rejected) / trials; math.Abs(rate-0.05) > 0.015 {
+			t.Errorf("unexpected rejection rate for normal samples of size %d: got:%v want:0.05", n, rate)
+		}
+		if n >= 50 {
+			if rate := float64(rejectedUniform) / trials; rate < 0.5 {
+				t.Errorf("low rejection rate for uniform samples of size %d: %v", n, rate)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// OneSampleT performs Student's one-sample t-test of the null hypothesis
+// that the mean of the population from which x is drawn is mu. The
+// Estimate of the result is the sample mean, and Lower and Upper bound the
+// confidence interval for the mean at the given confidence level.
+//
+// OneSampleT will panic if x has fewer than two elements, or if level is
+// not in (0, 1).
+func OneSampleT(x []float64, mu float64, alt Alternative, level float64) Result {
+	if len(x) < 2 {
+		panic("hypothesis: too few samples")
+	}
+	mean, variance := stat.MeanVariance(x, nil)
+	n := float64(len(x))
+	return tTest(mean, math.Sqrt(variance/n), n-1, mu, alt, level)
+}
+
+// PairedT performs Student's paired t-test of the null hypothesis that the
+// mean difference between the paired samples x and y is mu, by performing
+// a one-sample t-test on the differences x[i]-y[i]. The Estimate of the
+// result is the mean difference, and Lower and Upper bound the confidence
+// interval for the mean difference at the given confidence level.
+//
+// PairedT will panic if x and y have different lengths or fewer than two
+// elements, or if level is not in (0, 1).
+func PairedT(x, y []float64, mu float64, alt Alternative, level float64) Result {
+	if len(x) != len(y) {
+		panic("hypothesis: slice length mismatch")
+	}
+	d := make([]float64, len(x))
+	for i, v := range x {
+		d[i] = v - y[i]
+	}
+	return OneSampleT(d, mu, alt, level)
+}
+
+// TwoSampleT performs Student's two-sample t-test of the null hypothesis
+// that the difference between the means of the populations from which x
+// and y are drawn is mu, assuming that the populations have the same
+// variance. The Estimate of the result is the difference between the
+// sample means, and Lower and Upper bound the confidence interval for the
+// difference at the given confidence level.
+//
+// TwoSampleT will panic if x or y has fewer than two elements, or if level
+// is not in (0, 1).
+func TwoSampleT(x, y []float64, mu float64, alt Alternative, level float64) Result {
+	if len(x) < 2 || len(y) < 2 {
+		panic("hypothesis: too few samples")
+	}
+	mx, vx := stat.MeanVariance(x, nil)
+	my, vy := stat.MeanVariance(y, nil)
+	nx, ny := float64(len(x)), float64(len(y))
+	df := nx + ny - 2
+	pooled := ((nx-1)*vx + (ny-1)*vy) / df
+	return tTest(mx-my, math.Sqrt(pooled*(1/nx+1/ny)), df, mu, alt, level)
+}
+
+// WelchT performs Welch's two-sample t-test of the null hypothesis that
+// the difference between the means of the populations from which x and y
+// are drawn is mu, without assuming that the populations have the same
+// variance. The degrees of freedom are given by the Welch-Satterthwaite
+// equation. The Estimate of the result is the difference between the
+// sample means, and Lower and Upper bound the confidence interval for the
+// difference at the given confidence level.
+//
+// WelchT will panic if x or y has fewer than two elements, or if level is
+// not in (0, 1).
+//
+// References:
+//   - Welch, B. L. (1947). The generalization of "Student's" problem when
+//     several different population variances are involved. Biometrika,
+//     34(1-2), 28-35.
+func WelchT(x, y []float64, mu float64, alt Alternative, level float64) Result {
+	if len(x) < 2 || len(y) < 2 {
+		panic("hypothesis: too few samples")
+	}
+	mx, vx := stat.MeanVariance(x, nil)
+	my, vy := stat.MeanVariance(y, nil)
+	sx := vx / float64(len(x))
+	sy := vy / float64(len(y))
+	df := (sx + sy) * (sx + sy) / (sx*sx/float64(len(x)-1) + sy*sy/float64(len(y)-1))
+	return tTest(mx-my, math.Sqrt(sx+sy), df, mu, alt, level)
+}
+
+// tTest returns the result of a t-test of the estimate est with the
+// standard error se and df degrees of freedom against the null value mu.
+func tTest(est, se, df, mu float64, alt Alternative, level float64) Result {
+	checkAlternative(alt)
+	checkLevel(level)
+	t := (est - mu) / se
+	dist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}
+	r := Result{
+		Statistic: t,
+		DF:        df,
+		P:         tails(alt, dist.CDF(t), dist.Survival(t)),
+		Estimate:  est,
+	}
+	switch alt {
+	case Less:
+		r.Lower = math.Inf(-1)
+		r.Upper = est + dist.Quantile(level)*se
+	case Greater:
+		r.Lower = est - dist.Quantile(level)*se
+		r.Upper = math.Inf(1)
+	default:
+		q := dist.Quantile(1 - (1-level)/2)
+		r.Lower = est - q*se
+		r.Upper = est + q*se
+	}
+	return r
+}
@@ -0,0 +1,85 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTTests(t *testing.T) {
+	t.Parallel()
+	// Reference values from R's t.test.
+	for _, test := range []struct {
+		name string
+		fn   func() Result
+		want Result
+	}{
+		{
+			name: "one sample",
+			fn:   func() Result { return OneSampleT(sleep1, 0, TwoSided, 0.95) },
+			want: Result{Statistic: 1.325710, DF: 9, P: 0.2175978, Estimate: 0.75, Lower: -0.5297804, Upper: 2.0297804},
+		},
+		{
+			name: "two sample",
+			fn:   func() Result { return TwoSampleT(sleep1, sleep2, 0, TwoSided, 0.95) },
+			want: Result{Statistic: -1.860813, DF: 18, P: 0.07918671, Estimate: -1.58, Lower: -3.363874, Upper: 0.203874},
+		},
+		{
+			name: "Welch",
+			fn:   func() Result { return WelchT(sleep1, sleep2, 0, TwoSided, 0.95) },
+			want: Result{Statistic: -1.860813, DF: 17.77647, P: 0.07939414, Estimate: -1.58, Lower: -3.3654832, Upper: 0.2054832},
+		},
+		{
+			name: "paired",
+			fn:   func() Result { return PairedT(sleep1, sleep2, 0, TwoSided, 0.95) },
+			want: Result{Statistic: -4.062128, DF: 9, P: 0.002832890, Estimate: -1.58, Lower: -2.4598858, Upper: -0.7001142},
+		},
+	} {
+		got := test.fn()
+		if !sameResult(got, test.want, 1e-4) {
+			t.Errorf("unexpected result for %s:\ngot: %+v\nwant:%+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestTTestDuality(t *testing.T) {
+	t.Parallel()
+	// The p-value at a bound of the confidence interval
+	// is one minus the confidence level.
+	for _, alt := range []Alternative{TwoSided, Less, Greater} {
+		r := WelchT(sleep1, sleep2, 0, alt, 0.9)
+		for _, bound := range []float64{r.Lower, r.Upper} {
+			if math.IsInf(bound, 0) {
+				continue
+			}
+			got := WelchT(sleep1, sleep2, bound, alt, 0.9).P
+			if math.Abs(got-0.1) > 1e-10 {
+				t.Errorf("unexpected p-value at confidence bound for alternative %d: got:%v want:0.1", alt, got)
+			}
+		}
+	}
+}
+
+func TestTTestAlternatives(t *testing.T) {
+	t.Parallel()
+	for _, mu := range []float64{-3, -1.58, 0, 1} {
+		two := WelchT(sleep1, sleep2, mu, TwoSided, 0.95)
+		less := WelchT(sleep1, sleep2, mu, Less, 0.95)
+		greater := WelchT(sleep1, sleep2, mu, Greater, 0.95)
+		if math.Abs(less.P+greater.P-1) > 1e-14 {
+			t.Errorf("one-sided p-values do not sum to one for mu=%v: %v+%v", mu, less.P, greater.P)
+		}
+		if math.Abs(two.P-2*math.Min(less.P, greater.P)) > 1e-14 {
+			t.Errorf("two-sided p-value is not twice the smaller one-sided p-value for mu=%v", mu)
+		}
+		if !math.IsInf(less.Lower, -1) || !math.IsInf(greater.Upper, 1) {
+			t.Errorf("one-sided confidence intervals are not unbounded for mu=%v", mu)
+		}
+		if !(two.Lower < greater.Lower && less.Upper < two.Upper) {
+			t.Errorf("one-sided confidence bounds are not within the two-sided interval for mu=%v", mu)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// Silverman returns Silverman's rule of thumb bandwidth for a kernel
+// density estimate of the weighted sample x,
+//
+//	h = 0.9 min(σ, IQR/1.34) n^(-1/5),
+//
+// where σ is the standard deviation and IQR is the interquartile range of
+// the sample, and n is the sum of the weights. If weights is nil, all
+// weights are one. The rule is optimal for Gaussian data with a Gaussian
+// kernel, and is robust to moderate departures from normality.
+//
+// Silverman will panic if x has fewer than two elements or if the length
+// of weights does not equal the length of x.
+//
+// References:
+//   - Silverman, B. W. (1986). Density Estimation for Statistics and Data
+//     Analysis. Chapman & Hall.
+func Silverman(x, weights []float64) float64 {
+	return 0.9 * spread(x, weights) * math.Pow(sumWeights(x, weights), -0.2)
+}
+
+// Scott returns Scott's rule of thumb bandwidth for a kernel density
+// estimate of the weighted sample x,
+//
+//	h = 1.06 min(σ, IQR/1.34) n^(-1/5),
+//
+// where σ is the standard deviation and IQR is the interquartile range of
+// the sample, and n is the sum of the weights. If weights is nil, all
+// weights are one.
+//
+// Scott will panic if x has fewer than two elements or if the length of
+// weights does not equal the length of x.
+//
+// References:
+//   - Scott, D. W. (1992). Multivariate Density Estimation: Theory,
+//     Practice, and Visualization. Wiley.
+func Scott(x, weights []float64) float64 {
+	return 1.06 * spread(x, weights) * math.Pow(sumWeights(x, weights), -0.2)
+}
+
+// CrossValidation returns the bandwidth for a kernel density estimate of
+// the weighted sample x with the given kernel that minimizes the
+// least-squares cross-validation criterion,
+//
+//	∫ f̂² - 2 Σ_i p_i f̂_{-i}(x_i),
+//
+// an unbiased estimate of the integrated squared error of the estimate up
+// to a constant, where f̂_{-i} is the estimate with x_i left out and p_i is
+// the normalized weight of x_i. If weights is nil, all weights are one.
+// The bandwidth is sought between one tenth of and the oversmoothed
+// bandwidth 1.144 σ n^(-1/5), and the bound is returned if the criterion is
+// minimized there. The cost of each evaluation of the criterion is
+// quadratic in the length of x.
+//
+// CrossValidation will panic if x has fewer than two elements, if the
+// length of weights does not equal the length of x, or if any weight is
+// negative.
+//
+// References:
+//   - Rudemo, M. (1982). Empirical choice of histograms and kernel density
+//     estimators. Scandinavian Journal of Statistics, 9(2), 65-78.
+//   - Bowman, A. W. (1984). An alternative method of cross-validation for
+//     the smoothing of density estimates. Biometrika, 71(2), 353-360.
+func CrossValidation(x, weights []float64, kernel Kernel) float64 {
+	n := sumWeights(x, weights)
+	xs := append([]float64(nil), x...)
+	var p []float64
+	if weights == nil {
+		sort.Float64s(xs)
+	} else {
+		p = append([]float64(nil), weights...)
+		stat.SortWeighted(xs, p)
+	}
+	hmax := 1.144 * math.Sqrt(stat.Variance(xs, p)) * math.Pow(n, -0.2)
+	if hmax == 0 {
+		panic("kde: sample has zero variance")
+	}
+	if p == nil {
+		p = make([]float64, len(xs))
+		for i := range p {
+			p[i] = 1
+		}
+	}
+	floats.Scale(1/n, p)
+
+	// Kernels with unbounded support are truncated for the
+	// search for neighbouring values, which is negligible for
+	// the provided Gaussian kernel.
+	a := kernel.Support()
+	if math.IsInf(a, 1) {
+		a = unboundedSupport
+	}
+	lscv := func(h float64) float64 {
+		var sq, loo float64
+		for i, xi := range xs {
+			var s float64
+			for j := i + 1; j < len(xs) && xs[j]-xi < 2*a*h; j++ {
+				d := (xs[j] - xi) / h
+				sq += 2 * p[i] * p[j] * convolution(kernel, d)
+				s += p[j] * kernel.Prob(d)
+			}
+			for j := i - 1; j >= 0 && xi-xs[j] < a*h; j-- {
+				s += p[j] * kernel.Prob((xi-xs[j])/h)
+			}
+			sq += p[i] * p[i] * convolution(kernel, 0)
+			if p[i] < 1 {
+				loo += p[i] * s / (1 - p[i])
+			}
+		}
+		return (sq - 2*loo) / h
+	}
+
+	// Minimize the criterion by golden section search on the
+	// logarithm of the bandwidth.
+	const invPhi = 0.61803398874989484820458683436563811772030917980576286213545
+	lo, hi := math.Log(hmax/10), math.Log(hmax)
+	c := hi - invPhi*(hi-lo)
+	d := lo + invPhi*(hi-lo)
+	fc, fd := lscv(math.Exp(c)), lscv(math.Exp(d))
+	for hi-lo > 1e-8 {
+		if fc <= fd {
+			hi, d, fd = d, c, fc
+			c = hi - invPhi*(hi-lo)
+			fc = lscv(math.Exp(c))
+		} else {
+			lo, c, fc = c, d, fd
+			d = lo + invPhi*(hi-lo)
+			fd = lscv(math.Exp(d))
+		}
+	}
+	return math.Exp((lo + hi) / 2)
+}
+
+// SilvermanMultivariate returns Silverman's rule of thumb bandwidths for a
+// multivariate kernel density estimate of the weighted sample in the rows
+// of x with a product kernel,
+//
+//	h_j = (4/(d+2))^(1/(d+4)) σ_j n^(-1/(d+4)),
+//
+// where σ_j is the standard deviation of the j-th column of x, d is the
+// number of columns and n is the sum of the weights. If weights is nil,
+// all weights are one.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of columns of x,
+// SilvermanMultivariate will panic. SilvermanMultivariate will also panic
+// if x has fewer than two rows or if the length of weights does not equal
+// the number of rows of x.
+func SilvermanMultivariate(dst []float64, x mat.Matrix, weights []float64) []float64 {
+	_, d := x.Dims()
+	c := math.Pow(4/float64(d+2), 1/float64(d+4))
+	return multivariateRule(dst, x, weights, c)
+}
+
+// ScottMultivariate returns Scott's rule of thumb bandwidths for a
+// multivariate kernel density estimate of the weighted sample in the rows
+// of x with a product kernel,
+//
+//	h_j = σ_j n^(-1/(d+4)),
+//
+// where σ_j is the standard deviation of the j-th column of x, d is the
+// number of columns and n is the sum of the weights. If weights is nil,
+// all weights are one.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of columns of x,
+// ScottMultivariate will panic. ScottMultivariate will also panic if x has
+// fewer than two rows or if the length of weights does not equal the
+// number of rows of x.
+func ScottMultivariate(dst []float64, x mat.Matrix, weights []float64) []float64 {
+	return multivariateRule(dst, x, weights, 1)
+}
+
+// multivariateRule returns the bandwidths c σ_j n^(-1/(d+4)).
+func multivariateRule(dst []float64, x mat.Matrix, weights []float64, c float64) []float64 {
+	r, d := x.Dims()
+	if dst == nil {
+		dst = make([]float64, d)
+	} else if len(dst) != d {
+		panic("kde: slice length mismatch")
+	}
+	col := make([]float64, r)
+	n := sumWeights(col, weights)
+	f := c * math.Pow(n, -1/float64(d+4))
+	for j := range dst {
+		mat.Col(col, j, x)
+		dst[j] = f * math.Sqrt(stat.Variance(col, weights))
+	}
+	return dst
+}
+
+// spread returns min(σ, IQR/1.34) for the weighted sample, falling back
+// to σ, |x[0]| or one if the spread is zero.
+func spread(x, weights []float64) float64 {
+	sumWeights(x, weights)
+	xs := append([]float64(nil), x...)
+	var w []float64
+	if weights == nil {
+		sort.Float64s(xs)
+	} else {
+		w = append([]float64(nil), weights...)
+		stat.SortWeighted(xs, w)
+	}
+	sd := math.Sqrt(stat.Variance(xs, w))
+	iqr := stat.Quantile(0.75, stat.LinInterp, xs, w) - stat.Quantile(0.25, stat.LinInterp, xs, w)
+	s := math.Min(sd, iqr/1.34)
+	switch {
+	case s > 0:
+		return s
+	case sd > 0:
+		return sd
+	case x[0] != 0:
+		return math.Abs(x[0])
+	default:
+		return 1
+	}
+}
+
+// sumWeights returns the sum of the weights of the sample x, and panics if
+// x has fewer than two elements, the lengths of x and weights differ or a
+// weight is negative.
+func sumWeights(x, weights []float64) float64 {
+	if len(x) < 2 {
+		panic("kde: too few samples")
+	}
+	if weights == nil {
+		return float64(len(x))
+	}
+	if len(weights) != len(x) {
+		panic("kde: slice length mismatch")
+	}
+	var sum float64
+	for _, w := range weights {
+		if w < 0 {
+			panic("kde: negative weight")
+		}
+		sum += w
+	}
+	return sum
+}
@@ -0,0 +1,153 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestRulesOfThumb(t *testing.T) {
+	t.Parallel()
+	// The standard deviation of 1, ..., 10 is √(55/6),
+	// less than the interquartile range of 5 divided by 1.34.
+	x := []float64{3, 1, 4, 10, 5, 9, 2, 6, 8, 7}
+	sd := math.Sqrt(55.0 / 6)
+	want := 0.9 * sd * math.Pow(10, -0.2)
+	if got := Silverman(x, nil); !scalar.EqualWithinRel(got, want, 1e-14) {
+		t.Errorf("unexpected Silverman bandwidth: got:%v want:%v", got, want)
+	}
+	want = 1.06 * sd * math.Pow(10, -0.2)
+	if got := Scott(x, nil); !scalar.EqualWithinRel(got, want, 1e-14) {
+		t.Errorf("unexpected Scott bandwidth: got:%v want:%v", got, want)
+	}
+
+	// An outlier inflates the standard deviation but not
+	// the interquartile range of 7.5-2.5 interpolated
+	// between sample values.
+	x = []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 1000}
+	want = 0.9 * 5 / 1.34 * math.Pow(10, -0.2)
+	if got := Silverman(x, nil); !scalar.EqualWithinRel(got, want, 1e-14) {
+		t.Errorf("unexpected Silverman bandwidth with outlier: got:%v want:%v", got, want)
+	}
+
+	// Integer weights are equivalent to repeated values.
+	x = []float64{0.5, 1.5, 2, 3.5, 7}
+	w := []float64{2, 1, 3, 1, 2}
+	var rep []float64
+	for i, v := range x {
+		for j := 0; j < int(w[i]); j++ {
+			rep = append(rep, v)
+		}
+	}
+	if got, want := Scott(x, w), Scott(rep, nil); !scalar.EqualWithinRel(got, want, 1e-14) {
+		t.Errorf("unexpected weighted Scott bandwidth: got:%v want:%v", got, want)
+	}
+
+	// A sample with no spread falls back to its value.
+	if got := Silverman([]float64{-2, -2, -2}, nil); !scalar.EqualWithinRel(got, 0.9*2*math.Pow(3, -0.2), 1e-14) {
+		t.Errorf("unexpected bandwidth for constant sample: got:%v", got)
+	}
+}
+
+func TestMultivariateRules(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n, d = 40, 3
+	x := mat.NewDense(n, d, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < d; j++ {
+			x.Set(i, j, float64(j+1)*rnd.NormFloat64())
+		}
+	}
+	col := make([]float64, n)
+	scott := ScottMultivariate(nil, x, nil)
+	silverman := SilvermanMultivariate(make([]float64, d), x, nil)
+	for j := 0; j < d; j++ {
+		mat.Col(col, j, x)
+		var mean, ss float64
+		mean = floats.Sum(col) / n
+		for _, v := range col {
+			ss += (v - mean) * (v - mean)
+		}
+		sd := math.Sqrt(ss / (n - 1))
+		want := sd * math.Pow(n, -1.0/(d+4))
+		if !scalar.EqualWithinRel(scott[j], want, 1e-12) {
+			t.Errorf("unexpected Scott bandwidth for dimension %d: got:%v want:%v", j, scott[j], want)
+		}
+		want *= math.Pow(4.0/(d+2), 1.0/(d+4))
+		if !scalar.EqualWithinRel(silverman[j], want, 1e-12) {
+			t.Errorf("unexpected Silverman bandwidth for dimension %d: got:%v want:%v", j, silverman[j], want)
+		}
+	}
+}
+
+// lscv returns the least-squares cross-validation criterion computed by
+// numerical integration.
+func lscv(x, w []float64, k Kernel, h float64) float64 {
+	u := NewUnivariate(x, w, k, h, nil)
+	lo, hi := floats.Min(x)-support(k)*h, floats.Max(x)+support(k)*h
+	sq := integrate(func(v float64) float64 { p := u.Prob(v); return p * p }, lo, hi, 2000)
+	sum := floats.Sum(w)
+	var loo float64
+	for i, xi := range x {
+		var s float64
+		for j, xj := range x {
+			if j != i {
+				s += w[j] * k.Prob((xi-xj)/h) / h
+			}
+		}
+		loo += w[i] / sum * s / (sum - w[i])
+	}
+	return sq - 2*loo
+}
+
+func TestCrossValidation(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, w := bimodal(rnd, 40)
+	for _, k := range kernels {
+		name := fmt.Sprintf("%T", k)
+		h := CrossValidation(x, w, k)
+		hmax := 1.144 * math.Sqrt(stat.Variance(x, w)) * math.Pow(floats.Sum(w), -0.2)
+		if h <= hmax/10*1.001 || h >= hmax/1.001 {
+			t.Errorf("bandwidth for %s on boundary of search: %v", name, h)
+			continue
+		}
+		// The criterion is minimized at the bandwidth.
+		f := lscv(x, w, k, h)
+		for _, s := range []float64{0.97, 1.03} {
+			if g := lscv(x, w, k, s*h); g < f {
+				t.Errorf("bandwidth for %s does not minimize criterion: f(%v)=%v f(%v)=%v", name, h, f, s*h, g)
+			}
+		}
+	}
+}
+
+func TestBandwidthPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "too few", fn: func() { Silverman([]float64{1}, nil) }},
+		{name: "weights length", fn: func() { Scott([]float64{1, 2}, []float64{1}) }},
+		{name: "negative weight", fn: func() { Scott([]float64{1, 2}, []float64{1, -1}) }},
+		{name: "zero variance", fn: func() { CrossValidation([]float64{1, 1}, nil, Gaussian{}) }},
+		{name: "dst length", fn: func() { ScottMultivariate(make([]float64, 1), mat.NewDense(3, 2, nil), nil) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package kde provides univariate and multivariate kernel density
+// estimation.
+//
+// A kernel density estimate smooths the empirical distribution of a sample
+// by placing a scaled copy of a kernel, a symmetric probability density,
+// at each of the sample values. The scale of the kernel is the bandwidth
+// of the estimate, which may be chosen by one of the rules of thumb or by
+// cross-validation provided by the package.
+package kde // import "gonum.org/v1/gonum/stat/kde"
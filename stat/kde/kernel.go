@@ -0,0 +1,272 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/integrate/quad"
+)
+
+// Kernel is a smoothing kernel of a kernel density estimate. A Kernel must
+// be a probability density that is symmetric about zero and has unit
+// variance, so that the bandwidth of an estimate is the standard deviation
+// of the scaled kernel.
+type Kernel interface {
+	// Prob returns the value of the kernel density at x.
+	Prob(x float64) float64
+
+	// CDF returns the value of the cumulative distribution
+	// function of the kernel at x.
+	CDF(x float64) float64
+
+	// Rand returns a random sample drawn from the kernel
+	// using src, or the global source if src is nil.
+	Rand(src rand.Source) float64
+
+	// Support returns the half-width of the support of
+	// the kernel, which is +Inf if the support is unbounded.
+	Support() float64
+}
+
+// Gaussian is the standard normal kernel.
+type Gaussian struct{}
+
+// Prob returns the value of the kernel density at x.
+func (Gaussian) Prob(x float64) float64 { return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi) }
+
+// CDF returns the value of the cumulative distribution function at x.
+func (Gaussian) CDF(x float64) float64 { return 0.5 * math.Erfc(-x/math.Sqrt2) }
+
+// Rand returns a random sample drawn from the kernel.
+func (Gaussian) Rand(src rand.Source) float64 {
+	if src == nil {
+		return rand.NormFloat64()
+	}
+	return rand.New(src).NormFloat64()
+}
+
+// Support returns +Inf.
+func (Gaussian) Support() float64 { return math.Inf(1) }
+
+// convolution returns the value of the convolution of the kernel
+// with itself at x, the density of N(0, 2).
+func (Gaussian) convolution(x float64) float64 { return math.Exp(-x*x/4) / (2 * math.Sqrt(math.Pi)) }
+
+// Epanechnikov is the Epanechnikov kernel,
+//
+//	K(x) = 3/(4a) (1 - (x/a)²) for |x| ≤ a,
+//
+// with a = √5.
+type Epanechnikov struct{}
+
+const epanechnikovSupport = 2.23606797749978969640917366873127623544061835961152572427089 // √5
+
+// Prob returns the value of the kernel density at x.
+func (Epanechnikov) Prob(x float64) float64 {
+	t := x / epanechnikovSupport
+	if math.Abs(t) > 1 {
+		return 0
+	}
+	return 0.75 * (1 - t*t) / epanechnikovSupport
+}
+
+// CDF returns the value of the cumulative distribution function at x.
+func (Epanechnikov) CDF(x float64) float64 {
+	t := clamp(x / epanechnikovSupport)
+	return (1 + t) * (1 + t) * (2 - t) / 4
+}
+
+// Rand returns a random sample drawn from the kernel.
+func (Epanechnikov) Rand(src rand.Source) float64 {
+	// The scaled kernel is the Beta(2, 2) distribution, that
+	// of the median of three uniform random variables.
+	return epanechnikovSupport * (2*medianUniform(3, src) - 1)
+}
+
+// Support returns √5.
+func (Epanechnikov) Support() float64 { return epanechnikovSupport }
+
+// Uniform is the rectangular kernel,
+//
+//	K(x) = 1/(2a) for |x| ≤ a,
+//
+// with a = √3.
+type Uniform struct{}
+
+const uniformSupport = 1.73205080756887729352744634150587236694280525381038062805581 // √3
+
+// Prob returns the value of the kernel density at x.
+func (Uniform) Prob(x float64) float64 {
+	if math.Abs(x) > uniformSupport {
+		return 0
+	}
+	return 0.5 / uniformSupport
+}
+
+// CDF returns the value of the cumulative distribution function at x.
+func (Uniform) CDF(x float64) float64 { return (clamp(x/uniformSupport) + 1) / 2 }
+
+// Rand returns a random sample drawn from the kernel.
+func (Uniform) Rand(src rand.Source) float64 {
+	var u [1]float64
+	uniforms(u[:], src)
+	return uniformSupport * (2*u[0] - 1)
+}
+
+// Support returns √3.
+func (Uniform) Support() float64 { return uniformSupport }
+
+// Triangular is the triangular kernel,
+//
+//	K(x) = (1 - |x|/a)/a for |x| ≤ a,
+//
+// with a = √6.
+type Triangular struct{}
+
+const triangularSupport = 2.44948974278317809819728407470589139196594748065667012843269 // √6
+
+// Prob returns the value of the kernel density at x.
+func (Triangular) Prob(x float64) float64 {
+	t := math.Abs(x / triangularSupport)
+	if t > 1 {
+		return 0
+	}
+	return (1 - t) / triangularSupport
+}
+
+// CDF returns the value of the cumulative distribution function at x.
+func (Triangular) CDF(x float64) float64 {
+	t := clamp(x / triangularSupport)
+	if t < 0 {
+		return (1 + t) * (1 + t) / 2
+	}
+	return 1 - (1-t)*(1-t)/2
+}
+
+// Rand returns a random sample drawn from the kernel.
+func (Triangular) Rand(src rand.Source) float64 {
+	// The scaled kernel is the distribution of the sum
+	// of two uniform random variables.
+	var u [2]float64
+	uniforms(u[:], src)
+	return triangularSupport * (u[0] + u[1] - 1)
+}
+
+// Support returns √6.
+func (Triangular) Support() float64 { return triangularSupport }
+
+// Biweight is the biweight, or quartic, kernel,
+//
+//	K(x) = 15/(16a) (1 - (x/a)²)² for |x| ≤ a,
+//
+// with a = √7.
+type Biweight struct{}
+
+const biweightSupport = 2.64575131106459059050161575363926042571025918308245018036833 // √7
+
+// Prob returns the value of the kernel density at x.
+func (Biweight) Prob(x float64) float64 {
+	t := x / biweightSupport
+	if math.Abs(t) > 1 {
+		return 0
+	}
+	s := 1 - t*t
+	return 15 * s * s / (16 * biweightSupport)
+}
+
+// CDF returns the value of the cumulative distribution function at x.
+func (Biweight) CDF(x float64) float64 {
+	t := clamp(x / biweightSupport)
+	return (1 + t) * (1 + t) * (1 + t) * (8 - 9*t + 3*t*t) / 16
+}
+
+// Rand returns a random sample drawn from the kernel.
+func (Biweight) Rand(src rand.Source) float64 {
+	// The scaled kernel is the Beta(3, 3) distribution, that
+	// of the median of five uniform random variables.
+	return biweightSupport * (2*medianUniform(5, src) - 1)
+}
+
+// Support returns √7.
+func (Biweight) Support() float64 { return biweightSupport }
+
+// clamp returns t clamped to [-1, 1].
+func clamp(t float64) float64 {
+	return math.Max(-1, math.Min(1, t))
+}
+
+// uniforms fills dst with uniform random values in [0, 1) drawn from src,
+// or the global source if src is nil.
+func uniforms(dst []float64, src rand.Source) {
+	if src == nil {
+		for i := range dst {
+			dst[i] = rand.Float64()
+		}
+		return
+	}
+	rnd := rand.New(src)
+	for i := range dst {
+		dst[i] = rnd.Float64()
+	}
+}
+
+// medianUniform returns the median of n uniform random values, for odd n.
+func medianUniform(n int, src rand.Source) float64 {
+	u := make([]float64, n)
+	uniforms(u, src)
+	sort.Float64s(u)
+	return u[n/2]
+}
+
+// unboundedSupport is the half-width at which kernels with unbounded
+// support are truncated where truncation is required.
+const unboundedSupport = 10
+
+// convolver is a kernel that can compute the convolution of the kernel with
+// itself.
+type convolver interface {
+	convolution(x float64) float64
+}
+
+// convolution returns the value of the convolution of the kernel k with
+// itself at x,
+//
+//	∫ K(u) K(x-u) du.
+func convolution(k Kernel, x float64) float64 {
+	if c, ok := k.(convolver); ok {
+		return c.convolution(x)
+	}
+	x = math.Abs(x)
+	a := k.Support()
+	pieces := 1
+	if math.IsInf(a, 1) {
+		a = unboundedSupport
+		pieces = 32
+	}
+	if x >= 2*a {
+		return 0
+	}
+	// The integrand is non-zero on [x-a, a]. Split the interval at
+	// the kinks of kernels that are not smooth at the origin, so that
+	// the integral is exact for piecewise polynomial kernels.
+	f := func(u float64) float64 { return k.Prob(u) * k.Prob(x-u) }
+	breaks := []float64{x - a, 0, x, a}
+	if x > a {
+		breaks = []float64{x - a, a}
+	}
+	var sum float64
+	for i, lo := range breaks[:len(breaks)-1] {
+		hi := breaks[i+1]
+		w := (hi - lo) / float64(pieces)
+		for j := 0; j < pieces; j++ {
+			sum += quad.Fixed(f, lo+float64(j)*w, lo+float64(j+1)*w, 8, quad.Legendre{}, 0)
+		}
+	}
+	return sum
+}
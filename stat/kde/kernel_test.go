@@ -0,0 +1,152 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/integrate/quad"
+	"gonum.org/v1/gonum/stat"
+)
+
+var kernels = []Kernel{Gaussian{}, Epanechnikov{}, Uniform{}, Triangular{}, Biweight{}}
+
+// kernelOnly hides the unexported methods of a kernel.
+type kernelOnly struct {
+	Kernel
+}
+
+// integrate returns the integral of f over [lo, hi] split into n pieces.
+func integrate(f func(float64) float64, lo, hi float64, n int) float64 {
+	var sum float64
+	w := (hi - lo) / float64(n)
+	for i := 0; i < n; i++ {
+		sum += quad.Fixed(f, lo+float64(i)*w, lo+float64(i+1)*w, 16, quad.Legendre{}, 0)
+	}
+	return sum
+}
+
+func support(k Kernel) float64 {
+	a := k.Support()
+	if math.IsInf(a, 1) {
+		return unboundedSupport
+	}
+	return a
+}
+
+func TestKernels(t *testing.T) {
+	t.Parallel()
+	for _, k := range kernels {
+		name := fmt.Sprintf("%T", k)
+		a := support(k)
+		// Split at the origin for kernels that are not
+		// smooth there.
+		mass := integrate(k.Prob, -a, 0, 64) + integrate(k.Prob, 0, a, 64)
+		if !scalar.EqualWithinAbs(mass, 1, 1e-12) {
+			t.Errorf("unexpected total probability for %s: got:%v want:1", name, mass)
+		}
+		x2 := func(x float64) float64 { return x * x * k.Prob(x) }
+		variance := integrate(x2, -a, 0, 64) + integrate(x2, 0, a, 64)
+		if !scalar.EqualWithinAbs(variance, 1, 1e-12) {
+			t.Errorf("unexpected variance for %s: got:%v want:1", name, variance)
+		}
+		for _, x := range []float64{-3, -2, -1.5, -0.5, -0.1, 0, 0.3, 1, 1.7, 2.5, 3} {
+			var want float64
+			switch {
+			case x > 0:
+				want = 0.5 + integrate(k.Prob, 0, math.Min(x, a), 64)
+			case x > -a:
+				want = integrate(k.Prob, -a, x, 64)
+			}
+			if got := k.CDF(x); !scalar.EqualWithinAbs(got, want, 1e-12) {
+				t.Errorf("unexpected CDF for %s at %v: got:%v want:%v", name, x, got, want)
+			}
+			if got := k.CDF(x) + k.CDF(-x); !scalar.EqualWithinAbs(got, 1, 1e-14) {
+				t.Errorf("CDF of %s not symmetric at %v", name, x)
+			}
+			if k.Prob(x) != k.Prob(-x) {
+				t.Errorf("density of %s not symmetric at %v", name, x)
+			}
+		}
+		if got := k.CDF(-a); got != 0 && !math.IsInf(k.Support(), 1) {
+			t.Errorf("unexpected CDF for %s at lower end of support: got:%v", name, got)
+		}
+	}
+}
+
+func TestKernelRand(t *testing.T) {
+	t.Parallel()
+	const n = 20000
+	for i, k := range kernels {
+		name := fmt.Sprintf("%T", k)
+		src := rand.NewSource(uint64(i))
+		x := make([]float64, n)
+		for j := range x {
+			x[j] = k.Rand(src)
+		}
+		mean, variance := stat.MeanVariance(x, nil)
+		if !scalar.EqualWithinAbs(mean, 0, 0.03) || !scalar.EqualWithinAbs(variance, 1, 0.03) {
+			t.Errorf("unexpected moments of samples of %s: mean:%v variance:%v", name, mean, variance)
+		}
+		sort.Float64s(x)
+		var d float64
+		for j, v := range x {
+			c := k.CDF(v)
+			d = math.Max(d, math.Max(c-float64(j)/n, float64(j+1)/n-c))
+		}
+		// The 0.001 critical value of the Kolmogorov-Smirnov
+		// statistic is about 1.95/√n.
+		if d > 1.95/math.Sqrt(n) {
+			t.Errorf("samples of %s do not follow the kernel: KS statistic %v", name, d)
+		}
+	}
+}
+
+func TestConvolution(t *testing.T) {
+	t.Parallel()
+	// The self-convolution of the Epanechnikov kernel with
+	// unit support.
+	epa := func(x float64) float64 {
+		x = math.Abs(x)
+		if x >= 2 {
+			return 0
+		}
+		return 3 * math.Pow(2-x, 3) * (x*x + 6*x + 4) / 160
+	}
+	for _, x := range []float64{0, 0.1, 0.5, 1, 1.5, 2, 2.2, 3, 4, 4.4, 5, 6} {
+		for _, k := range kernels {
+			name := fmt.Sprintf("%T", k)
+			got := convolution(kernelOnly{k}, x)
+			a := support(k)
+			f := func(u float64) float64 { return k.Prob(u) * k.Prob(x-u) }
+			var want float64
+			switch {
+			case x <= a:
+				want = integrate(f, x-a, 0, 256) + integrate(f, 0, x, 256) + integrate(f, x, a, 256)
+			case x < 2*a:
+				want = integrate(f, x-a, a, 256)
+			}
+			if !scalar.EqualWithinAbs(got, want, 1e-10) {
+				t.Errorf("unexpected convolution of %s at %v: got:%v want:%v", name, x, got, want)
+			}
+			if _, ok := k.(convolver); ok {
+				if c := convolution(k, x); !scalar.EqualWithinAbs(c, got, 1e-12) {
+					t.Errorf("unexpected closed form convolution of %s at %v: got:%v want:%v", name, x, c, got)
+				}
+			}
+		}
+		a := epanechnikovSupport
+		want := epa(x/a) / a
+		if got := convolution(Epanechnikov{}, x); !scalar.EqualWithinAbs(got, want, 1e-14) {
+			t.Errorf("unexpected convolution of Epanechnikov kernel at %v: got:%v want:%v", x, got, want)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/kdtree"
+)
+
+// Multivariate is a multivariate kernel density estimate with a product
+// kernel,
+//
+//	f̂(x) = Σ_i p_i Π_j K((x_j - x_ij)/h_j) / h_j,
+//
+// for the sample points x_i with normalized weights p_i, the kernel K and
+// the bandwidths h_j of each dimension. The sample points are held in a k-d
+// tree so that the density is evaluated from the sample points near x.
+type Multivariate struct {
+	tree   *kdtree.Tree
+	points points // Sample points in the order held by the tree.
+	cum    []float64
+	kernel Kernel
+	h      []float64
+
+	// logNorm is the log of the product of the bandwidths.
+	logNorm float64
+
+	src rand.Source
+}
+
+// NewMultivariate returns a kernel density estimate of the weighted sample
+// in the rows of x with the given kernel and the bandwidth of each
+// dimension, the columns of x. If weights is nil, all weights are one. The
+// random samples of the estimate are drawn using src, or the global source
+// if src is nil.
+//
+// NewMultivariate will panic if x has no rows, if the length of weights
+// does not equal the number of rows of x, if any weight is negative or the
+// weights sum to zero, if the length of bandwidth does not equal the
+// number of columns of x, or if any bandwidth is not positive and finite.
+func NewMultivariate(x mat.Matrix, weights []float64, kernel Kernel, bandwidth []float64, src rand.Source) *Multivariate {
+	r, d := x.Dims()
+	if r == 0 {
+		panic("kde: too few samples")
+	}
+	if weights != nil && len(weights) != r {
+		panic("kde: slice length mismatch")
+	}
+	if len(bandwidth) != d {
+		panic("kde: bandwidth length mismatch")
+	}
+	var logNorm float64
+	for _, h := range bandwidth {
+		if !(0 < h && h < math.Inf(1)) {
+			panic("kde: bandwidth not positive and finite")
+		}
+		logNorm += math.Log(h)
+	}
+	w := make([]float64, r)
+	for i := range w {
+		w[i] = 1
+	}
+	if weights != nil {
+		copy(w, weights)
+	}
+	_, sum := cumulative(w)
+	pts := make(points, r)
+	for i := range pts {
+		z := make(kdtree.Point, d)
+		for j := range z {
+			z[j] = x.At(i, j) / bandwidth[j]
+		}
+		pts[i] = point{z: z, w: w[i] / sum}
+	}
+	tree := kdtree.New(pts, false)
+	for i, p := range pts {
+		w[i] = p.w
+	}
+	cum, _ := cumulative(w)
+	return &Multivariate{
+		tree:    tree,
+		points:  pts,
+		cum:     cum,
+		kernel:  kernel,
+		h:       append([]float64(nil), bandwidth...),
+		logNorm: logNorm,
+		src:     src,
+	}
+}
+
+// Dim returns the dimension of the estimate.
+func (m *Multivariate) Dim() int { return len(m.h) }
+
+// Bandwidth returns the bandwidths of the estimate.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the dimension of the estimate,
+// Bandwidth will panic.
+func (m *Multivariate) Bandwidth(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(m.h))
+	} else if len(dst) != len(m.h) {
+		panic("kde: slice length mismatch")
+	}
+	copy(dst, m.h)
+	return dst
+}
+
+// Prob returns the value of the density estimate at x.
+//
+// Prob will panic if the length of x does not equal the dimension of the
+// estimate.
+func (m *Multivariate) Prob(x []float64) float64 {
+	return math.Exp(m.LogProb(x))
+}
+
+// LogProb returns the log of the value of the density estimate at x. For
+// the Gaussian kernel, LogProb is computed without underflow far from the
+// sample points, neglecting the sample points whose contributions relative
+// to that of the nearest sample point are less than e^-40.
+//
+// LogProb will panic if the length of x does not equal the dimension of
+// the estimate.
+func (m *Multivariate) LogProb(x []float64) float64 {
+	if len(x) != len(m.h) {
+		panic("kde: dimension mismatch")
+	}
+	q := point{z: make(kdtree.Point, len(x))}
+	for j, v := range x {
+		q.z[j] = v / m.h[j]
+	}
+
+	if _, ok := m.kernel.(Gaussian); ok {
+		// The product Gaussian kernel is radial, so find the
+		// nearest sample point and the points whose terms are
+		// within a factor of e^-40 of its term.
+		const cut = 40
+		_, d0 := m.tree.Nearest(q)
+		keep := kdtree.NewDistKeeper(d0 + 2*cut)
+		m.tree.NearestSet(keep, q)
+		var sum float64
+		for _, c := range keep.Heap {
+			sum += c.Comparable.(point).w * math.Exp(-(c.Dist-d0)/2)
+		}
+		return math.Log(sum) - d0/2 - float64(len(x))*math.Log(2*math.Pi)/2 - m.logNorm
+	}
+
+	var sum float64
+	term := func(c kdtree.Comparable, _ *kdtree.Bounding, _ int) bool {
+		p := c.(point)
+		v := p.w
+		for j, z := range p.z {
+			v *= m.kernel.Prob(q.z[j] - z)
+			if v == 0 {
+				break
+			}
+		}
+		sum += v
+		return false
+	}
+	a := m.kernel.Support()
+	if math.IsInf(a, 1) {
+		m.tree.Do(term)
+	} else {
+		lo := make(kdtree.Point, len(x))
+		hi := make(kdtree.Point, len(x))
+		for j, z := range q.z {
+			lo[j] = z - a
+			hi[j] = z + a
+		}
+		m.tree.DoBounded(&kdtree.Bounding{Min: point{z: lo}, Max: point{z: hi}}, term)
+	}
+	return math.Log(sum) - m.logNorm
+}
+
+// Rand returns a random sample drawn from the estimate, a sample point
+// chosen with probability given by its weight, displaced in each dimension
+// by a sample of the kernel scaled by the bandwidth.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the dimension of the estimate, Rand
+// will panic.
+func (m *Multivariate) Rand(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(m.h))
+	} else if len(dst) != len(m.h) {
+		panic("kde: slice length mismatch")
+	}
+	p := m.points[choose(m.cum, m.src)]
+	for j, z := range p.z {
+		dst[j] = m.h[j] * (z + m.kernel.Rand(m.src))
+	}
+	return dst
+}
+
+// point is a sample point of a multivariate estimate, scaled by the
+// bandwidths, and its weight.
+type point struct {
+	z kdtree.Point
+	w float64
+}
+
+func (p point) Compare(c kdtree.Comparable, d kdtree.Dim) float64 { return p.z[d] - c.(point).z[d] }
+func (p point) Dims() int                                         { return len(p.z) }
+func (p point) Distance(c kdtree.Comparable) float64              { return p.z.Distance(c.(point).z) }
+
+// points is a collection of sample points that satisfies kdtree.Interface.
+type points []point
+
+func (p points) Index(i int) kdtree.Comparable         { return p[i] }
+func (p points) Len() int                              { return len(p) }
+func (p points) Pivot(d kdtree.Dim) int                { return plane{Dim: d, points: p}.Pivot() }
+func (p points) Slice(start, end int) kdtree.Interface { return p[start:end] }
+
+// plane is a wrapping type that allows points to be pivoted on a dimension.
+type plane struct {
+	kdtree.Dim
+	points
+}
+
+func (p plane) Less(i, j int) bool { return p.points[i].z[p.Dim] < p.points[j].z[p.Dim] }
+func (p plane) Pivot() int         { return kdtree.Partition(p, kdtree.MedianOfRandoms(p, 100)) }
+func (p plane) Slice(start, end int) kdtree.SortSlicer {
+	p.points = p.points[start:end]
+	return p
+}
+func (p plane) Swap(i, j int) { p.points[i], p.points[j] = p.points[j], p.points[i] }
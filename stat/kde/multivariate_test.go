@@ -0,0 +1,167 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+var _ distmv.RandLogProber = (*Multivariate)(nil)
+
+// bruteLogProb returns the log of the multivariate kernel density estimate
+// at q computed directly.
+func bruteLogProb(q []float64, x mat.Matrix, w []float64, k Kernel, h []float64) float64 {
+	r, d := x.Dims()
+	var sum float64
+	for i := 0; i < r; i++ {
+		v := w[i] / floats.Sum(w)
+		for j := 0; j < d; j++ {
+			v *= k.Prob((q[j]-x.At(i, j))/h[j]) / h[j]
+		}
+		sum += v
+	}
+	return math.Log(sum)
+}
+
+func TestMultivariateLogProb(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, d := range []int{1, 2, 3} {
+		const n = 200
+		x := mat.NewDense(n, d, nil)
+		w := make([]float64, n)
+		for i := 0; i < n; i++ {
+			for j := 0; j < d; j++ {
+				x.Set(i, j, float64(j+1)*rnd.NormFloat64())
+			}
+			w[i] = rnd.Float64()
+		}
+		h := ScottMultivariate(nil, x, w)
+		for _, k := range kernels {
+			name := fmt.Sprintf("%T d=%d", k, d)
+			m := NewMultivariate(x, w, k, h, nil)
+			if m.Dim() != d || !floats.Equal(m.Bandwidth(nil), h) {
+				t.Errorf("unexpected dimension or bandwidth for %s", name)
+			}
+			for i := 0; i < 20; i++ {
+				q := make([]float64, d)
+				for j := range q {
+					q[j] = 1.5 * float64(j+1) * rnd.NormFloat64()
+				}
+				want := bruteLogProb(q, x, w, k, h)
+				got := m.LogProb(q)
+				if math.IsInf(want, -1) {
+					if !math.IsInf(got, -1) {
+						t.Errorf("unexpected log density for %s at %v: got:%v want:-Inf", name, q, got)
+					}
+					continue
+				}
+				if !scalar.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+					t.Errorf("unexpected log density for %s at %v: got:%v want:%v", name, q, got, want)
+				}
+				if p := m.Prob(q); !scalar.EqualWithinRel(p, math.Exp(want), 1e-10) {
+					t.Errorf("unexpected density for %s at %v: got:%v want:%v", name, q, p, math.Exp(want))
+				}
+			}
+
+			// In one dimension the estimate is the univariate
+			// estimate.
+			if d == 1 {
+				u := NewUnivariate(mat.Col(nil, 0, x), w, k, h[0], nil)
+				for _, q := range []float64{-3, -1, 0, 0.5, 2} {
+					if got, want := m.LogProb([]float64{q}), u.LogProb(q); !scalar.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+						t.Errorf("unexpected one-dimensional log density for %s at %v: got:%v want:%v", name, q, got, want)
+					}
+				}
+			}
+		}
+	}
+
+	// Far from the sample the Gaussian density underflows, but
+	// its log is dominated by the nearest sample point.
+	x := mat.NewDense(2, 2, []float64{0, 0, 1, 1})
+	m := NewMultivariate(x, nil, Gaussian{}, []float64{1, 2}, nil)
+	q := []float64{60, 80}
+	d0 := 59*59 + 79*79/4.0
+	want := -d0/2 - math.Log(2) - math.Log(2*math.Pi) - math.Log(2)
+	if got := m.LogProb(q); !scalar.EqualWithinRel(got, want, 1e-12) {
+		t.Errorf("unexpected log density far from sample: got:%v want:%v", got, want)
+	}
+}
+
+func TestMultivariateRand(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(4, 2, []float64{
+		0, 0,
+		1, 2,
+		-1, 3,
+		1e6, 1e6,
+	})
+	w := []float64{1, 2, 1, 0}
+	h := []float64{0.5, 0.25}
+	for i, k := range kernels {
+		name := fmt.Sprintf("%T", k)
+		m := NewMultivariate(x, w, k, h, rand.NewSource(uint64(i)))
+		const n = 50000
+		var mean, sq [2]float64
+		s := make([]float64, 2)
+		for j := 0; j < n; j++ {
+			m.Rand(s)
+			if s[0] > 1e5 {
+				t.Fatalf("sample drawn from point with zero weight for %s", name)
+			}
+			for l, v := range s {
+				mean[l] += v / n
+				sq[l] += v * v / n
+			}
+		}
+		// The mean of the estimate is the weighted mean of
+		// the sample and its variance in each dimension is
+		// the weighted variance plus the square of the
+		// bandwidth.
+		wantMean := [2]float64{0.25, 1.75}
+		wantVar := [2]float64{
+			(0+2+1)/4.0 - 0.25*0.25 + h[0]*h[0],
+			(0+8+9)/4.0 - 1.75*1.75 + h[1]*h[1],
+		}
+		for l := range mean {
+			variance := sq[l] - mean[l]*mean[l]
+			if !scalar.EqualWithinAbs(mean[l], wantMean[l], 0.02) || !scalar.EqualWithinRel(variance, wantVar[l], 0.03) {
+				t.Errorf("unexpected moments of samples for %s in dimension %d: got mean:%v variance:%v want mean:%v variance:%v",
+					name, l, mean[l], variance, wantMean[l], wantVar[l])
+			}
+		}
+	}
+}
+
+func TestMultivariatePanics(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6})
+	h := []float64{1, 1}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "weights length", fn: func() { NewMultivariate(x, []float64{1}, Gaussian{}, h, nil) }},
+		{name: "bandwidth length", fn: func() { NewMultivariate(x, nil, Gaussian{}, []float64{1}, nil) }},
+		{name: "bandwidth", fn: func() { NewMultivariate(x, nil, Gaussian{}, []float64{1, math.Inf(1)}, nil) }},
+		{name: "zero weight", fn: func() { NewMultivariate(x, []float64{0, 0, 0}, Gaussian{}, h, nil) }},
+		{name: "dimension", fn: func() { NewMultivariate(x, nil, Gaussian{}, h, nil).LogProb([]float64{1}) }},
+		{name: "rand length", fn: func() { NewMultivariate(x, nil, Gaussian{}, h, nil).Rand(make([]float64, 3)) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+	"gonum.org/v1/gonum/stat"
+)
+
+// Univariate is a univariate kernel density estimate,
+//
+//	f̂(x) = Σ_i p_i K((x - x_i)/h) / h,
+//
+// for the sample values x_i with normalized weights p_i, the kernel K and
+// the bandwidth h.
+type Univariate struct {
+	x      []float64 // Sorted sample values.
+	p      []float64 // Normalized weights.
+	cum    []float64 // Cumulative sums of p.
+	kernel Kernel
+	h      float64
+	src    rand.Source
+}
+
+// NewUnivariate returns a kernel density estimate of the weighted sample x
+// with the given kernel and bandwidth. If weights is nil, all weights are
+// one. The random samples of the estimate are drawn using src, or the
+// global source if src is nil.
+//
+// NewUnivariate will panic if x is empty, if the length of weights does not
+// equal the length of x, if any weight is negative or the weights sum to
+// zero, or if the bandwidth is not positive and finite.
+func NewUnivariate(x, weights []float64, kernel Kernel, bandwidth float64, src rand.Source) *Univariate {
+	if len(x) == 0 {
+		panic("kde: too few samples")
+	}
+	if weights != nil && len(weights) != len(x) {
+		panic("kde: slice length mismatch")
+	}
+	if !(0 < bandwidth && bandwidth < math.Inf(1)) {
+		panic("kde: bandwidth not positive and finite")
+	}
+	xs := append([]float64(nil), x...)
+	p := make([]float64, len(x))
+	if weights == nil {
+		sort.Float64s(xs)
+		for i := range p {
+			p[i] = 1
+		}
+	} else {
+		copy(p, weights)
+		stat.SortWeighted(xs, p)
+	}
+	cum, sum := cumulative(p)
+	for i := range p {
+		p[i] /= sum
+		cum[i] /= sum
+	}
+	return &Univariate{x: xs, p: p, cum: cum, kernel: kernel, h: bandwidth, src: src}
+}
+
+// cumulative returns the cumulative sums of the weights and their sum, and
+// panics if any weight is negative or the sum is zero.
+func cumulative(w []float64) (cum []float64, sum float64) {
+	cum = make([]float64, len(w))
+	for i, v := range w {
+		if v < 0 {
+			panic("kde: negative weight")
+		}
+		sum += v
+		cum[i] = sum
+	}
+	if sum == 0 {
+		panic("kde: zero total weight")
+	}
+	return cum, sum
+}
+
+// Bandwidth returns the bandwidth of the estimate.
+func (u *Univariate) Bandwidth() float64 { return u.h }
+
+// window returns the range of indices of the sample values within the
+// support of the kernel centered at x.
+func (u *Univariate) window(x float64) (lo, hi int) {
+	r := u.h * u.kernel.Support()
+	if math.IsInf(r, 1) {
+		return 0, len(u.x)
+	}
+	lo = sort.SearchFloat64s(u.x, x-r)
+	hi = lo + sort.Search(len(u.x)-lo, func(i int) bool { return u.x[lo+i] > x+r })
+	return lo, hi
+}
+
+// Prob returns the value of the density estimate at x.
+func (u *Univariate) Prob(x float64) float64 {
+	lo, hi := u.window(x)
+	var sum float64
+	for i, v := range u.x[lo:hi] {
+		sum += u.p[lo+i] * u.kernel.Prob((x-v)/u.h)
+	}
+	return sum / u.h
+}
+
+// LogProb returns the log of the value of the density estimate at x. For
+// the Gaussian kernel, LogProb is computed without underflow far from the
+// sample values.
+func (u *Univariate) LogProb(x float64) float64 {
+	if _, ok := u.kernel.(Gaussian); !ok {
+		return math.Log(u.Prob(x))
+	}
+	// Scale the sum by the largest term, that of the
+	// nearest sample value.
+	i := sort.SearchFloat64s(u.x, x)
+	d0 := math.Inf(1)
+	if i < len(u.x) {
+		d0 = u.x[i] - x
+	}
+	if i > 0 {
+		d0 = math.Min(d0, x-u.x[i-1])
+	}
+	z0 := d0 / u.h
+	var sum float64
+	for i, v := range u.x {
+		z := (x - v) / u.h
+		sum += u.p[i] * math.Exp(-(z*z-z0*z0)/2)
+	}
+	return math.Log(sum) - z0*z0/2 - math.Log(u.h*math.Sqrt(2*math.Pi))
+}
+
+// CDF returns the value of the cumulative distribution function of the
+// estimate at x.
+func (u *Univariate) CDF(x float64) float64 {
+	lo, hi := u.window(x)
+	var sum float64
+	if lo > 0 {
+		sum = u.cum[lo-1]
+	}
+	for i, v := range u.x[lo:hi] {
+		sum += u.p[lo+i] * u.kernel.CDF((x-v)/u.h)
+	}
+	return math.Min(1, sum)
+}
+
+// Survival returns the value of the survival function of the estimate at
+// x, one minus the CDF.
+func (u *Univariate) Survival(x float64) float64 {
+	lo, hi := u.window(x)
+	sum := 1.0
+	if hi > 0 {
+		sum -= u.cum[hi-1]
+	}
+	for i, v := range u.x[lo:hi] {
+		sum += u.p[lo+i] * u.kernel.CDF((v-x)/u.h)
+	}
+	return math.Max(0, math.Min(1, sum))
+}
+
+// Quantile returns the value of x at which the CDF of the estimate is p.
+//
+// Quantile will panic if p is not in [0, 1].
+func (u *Univariate) Quantile(p float64) float64 {
+	if !(0 <= p && p <= 1) {
+		panic("kde: probability out of range")
+	}
+	r := u.h * u.kernel.Support()
+	switch p {
+	case 0:
+		return u.x[0] - r
+	case 1:
+		return u.x[len(u.x)-1] + r
+	}
+	lo, hi := u.x[0]-u.h, u.x[len(u.x)-1]+u.h
+	for w := u.h; u.CDF(lo) > p; w *= 2 {
+		lo -= w
+	}
+	for w := u.h; u.CDF(hi) < p; w *= 2 {
+		hi += w
+	}
+	for {
+		m := lo + (hi-lo)/2
+		if m == lo || m == hi {
+			return m
+		}
+		if u.CDF(m) < p {
+			lo = m
+		} else {
+			hi = m
+		}
+	}
+}
+
+// Rand returns a random sample drawn from the estimate, a sample value
+// chosen with probability given by its weight, displaced by a sample of the
+// kernel scaled by the bandwidth.
+func (u *Univariate) Rand() float64 {
+	i := choose(u.cum, u.src)
+	return u.x[i] + u.h*u.kernel.Rand(u.src)
+}
+
+// choose returns an index chosen with probability proportional to the
+// differences of the cumulative weights in cum.
+func choose(cum []float64, src rand.Source) int {
+	var r [1]float64
+	uniforms(r[:], src)
+	target := r[0] * cum[len(cum)-1]
+	return sort.Search(len(cum), func(i int) bool { return cum[i] > target })
+}
+
+// Mean returns the mean of the estimate, the weighted mean of the sample.
+func (u *Univariate) Mean() float64 {
+	var m float64
+	for i, v := range u.x {
+		m += u.p[i] * v
+	}
+	return m
+}
+
+// Variance returns the variance of the estimate, the weighted variance of
+// the sample with the normalized weights as probabilities plus the square
+// of the bandwidth.
+func (u *Univariate) Variance() float64 {
+	m := u.Mean()
+	var v float64
+	for i, x := range u.x {
+		v += u.p[i] * (x - m) * (x - m)
+	}
+	return v + u.h*u.h
+}
+
+// Grid places in dst the values of the density estimate at len(dst)
+// equally spaced points from lo to hi inclusive, and returns it. The values
+// are approximated by binning the sample values linearly onto a grid with
+// the same spacing and convolving the binned weights with the kernel by
+// fast Fourier transform, at a cost that grows as the number of grid points
+// and the number of grid points spanned by the kernel rather than with the
+// size of the sample. For continuous kernels the approximation error
+// decreases with the square of the ratio of the grid spacing to the
+// bandwidth. Kernels with unbounded support are truncated at ten
+// bandwidths.
+//
+// Grid will panic if len(dst) is less than two or if lo is not less than hi.
+func (u *Univariate) Grid(dst []float64, lo, hi float64) []float64 {
+	m := len(dst)
+	if m < 2 {
+		panic("kde: too few grid points")
+	}
+	if !(lo < hi) {
+		panic("kde: invalid grid range")
+	}
+	delta := (hi - lo) / float64(m-1)
+	a := u.kernel.Support()
+	if math.IsInf(a, 1) {
+		a = unboundedSupport
+	}
+	// Bin the sample values onto the grid extended by the
+	// support of the kernel on either side.
+	ext := int(math.Ceil(a * u.h / delta))
+	bins := make([]float64, m+2*ext)
+	for i, v := range u.x {
+		pos := (v-lo)/delta + float64(ext)
+		if pos < 0 || float64(len(bins)-1) < pos {
+			continue
+		}
+		j := int(pos)
+		f := pos - float64(j)
+		bins[j] += u.p[i] * (1 - f)
+		if j+1 < len(bins) {
+			bins[j+1] += u.p[i] * f
+		}
+	}
+	kern := make([]float64, 2*ext+1)
+	for i := range kern {
+		kern[i] = u.kernel.Prob(float64(i-ext)*delta/u.h) / u.h
+	}
+	full := fourier.Convolve(nil, bins, kern, fourier.Full)
+	for i := range dst {
+		dst[i] = math.Max(0, full[i+2*ext])
+	}
+	return dst
+}
@@ -0,0 +1,245 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+var (
+	_ distuv.RandLogProber = (*Univariate)(nil)
+	_ distuv.Quantiler     = (*Univariate)(nil)
+)
+
+// bimodal returns a sample from a mixture of two normal distributions
+// and random weights.
+func bimodal(rnd *rand.Rand, n int) (x, w []float64) {
+	x = make([]float64, n)
+	w = make([]float64, n)
+	for i := range x {
+		if i%3 == 0 {
+			x[i] = 4 + 0.5*rnd.NormFloat64()
+		} else {
+			x[i] = rnd.NormFloat64()
+		}
+		w[i] = rnd.Float64()
+	}
+	return x, w
+}
+
+// bruteProb returns the kernel density estimate at x computed directly.
+func bruteProb(x float64, data, weights []float64, k Kernel, h float64) float64 {
+	var sum, wsum float64
+	for i, v := range data {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		sum += w * k.Prob((x-v)/h) / h
+		wsum += w
+	}
+	return sum / wsum
+}
+
+func TestUnivariateProb(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, w := bimodal(rnd, 50)
+	for _, k := range kernels {
+		for _, weights := range [][]float64{nil, w} {
+			name := fmt.Sprintf("%T weighted=%t", k, weights != nil)
+			u := NewUnivariate(x, weights, k, 0.4, nil)
+			if u.Bandwidth() != 0.4 {
+				t.Errorf("unexpected bandwidth for %s: got:%v want:0.4", name, u.Bandwidth())
+			}
+			for _, q := range []float64{-5, -2, -0.5, 0, 0.1, 1, 2, 3.5, 4, 5, 8} {
+				want := bruteProb(q, x, weights, k, 0.4)
+				if got := u.Prob(q); !scalar.EqualWithinAbsOrRel(got, want, 1e-14, 1e-12) {
+					t.Errorf("unexpected density for %s at %v: got:%v want:%v", name, q, got, want)
+				}
+				if want == 0 {
+					continue
+				}
+				if got := u.LogProb(q); !scalar.EqualWithinAbsOrRel(got, math.Log(want), 1e-12, 1e-12) {
+					t.Errorf("unexpected log density for %s at %v: got:%v want:%v", name, q, got, math.Log(want))
+				}
+			}
+		}
+	}
+
+	// Far from the sample the Gaussian density underflows, but
+	// its log is dominated by the nearest sample value.
+	u := NewUnivariate([]float64{0, 1}, []float64{1, 3}, Gaussian{}, 1, nil)
+	for _, q := range []float64{-100, 100} {
+		d0 := math.Min(math.Abs(q), math.Abs(q-1))
+		d1 := math.Max(math.Abs(q), math.Abs(q-1))
+		w0, w1 := 0.75, 0.25
+		if q < 0 {
+			w0, w1 = w1, w0
+		}
+		want := math.Log(w0+w1*math.Exp(-(d1*d1-d0*d0)/2)) - d0*d0/2 - math.Log(math.Sqrt(2*math.Pi))
+		if got := u.LogProb(q); !scalar.EqualWithinRel(got, want, 1e-14) {
+			t.Errorf("unexpected log density at %v: got:%v want:%v", q, got, want)
+		}
+	}
+}
+
+func TestUnivariateCDF(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, w := bimodal(rnd, 30)
+	for _, k := range kernels {
+		name := fmt.Sprintf("%T", k)
+		u := NewUnivariate(x, w, k, 0.5, nil)
+		for _, q := range []float64{-10, -3, -1, 0, 0.5, 2, 4, 6, 15} {
+			var want float64
+			for i, v := range x {
+				want += w[i] * k.CDF((q-v)/0.5)
+			}
+			want /= floats.Sum(w)
+			got := u.CDF(q)
+			if !scalar.EqualWithinAbs(got, want, 1e-14) {
+				t.Errorf("unexpected CDF for %s at %v: got:%v want:%v", name, q, got, want)
+			}
+			if s := u.Survival(q); !scalar.EqualWithinAbs(s, 1-want, 1e-14) {
+				t.Errorf("unexpected survival for %s at %v: got:%v want:%v", name, q, s, 1-want)
+			}
+		}
+		for _, p := range []float64{1e-6, 0.01, 0.3, 0.5, 0.9, 0.999} {
+			q := u.Quantile(p)
+			if c := u.CDF(q); !scalar.EqualWithinAbs(c, p, 1e-12) {
+				t.Errorf("unexpected CDF at quantile %v for %s: got:%v", p, name, c)
+			}
+		}
+		if !math.IsInf(k.Support(), 1) {
+			lo, hi := u.Quantile(0), u.Quantile(1)
+			if u.CDF(lo) > 1e-15 || u.Survival(hi) > 1e-15 || u.Prob(lo-1e-12) != 0 || u.Prob(hi+1e-12) != 0 {
+				t.Errorf("unexpected support for %s: [%v, %v] CDF:%v survival:%v", name, lo, hi, u.CDF(lo), u.Survival(hi))
+			}
+		}
+	}
+}
+
+func TestUnivariateRand(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, w := bimodal(rnd, 20)
+	// Values with zero weight must never be drawn.
+	x = append(x, 1e6)
+	w = append(w, 0)
+	for i, k := range kernels {
+		name := fmt.Sprintf("%T", k)
+		u := NewUnivariate(x, w, k, 0.3, rand.NewSource(uint64(i)))
+		const n = 50000
+		s := make([]float64, n)
+		for j := range s {
+			s[j] = u.Rand()
+			if s[j] > 1e5 {
+				t.Fatalf("sample drawn from value with zero weight for %s", name)
+			}
+		}
+		mean, variance := stat.MeanVariance(s, nil)
+		if !scalar.EqualWithinAbs(mean, u.Mean(), 0.05) || !scalar.EqualWithinRel(variance, u.Variance(), 0.03) {
+			t.Errorf("unexpected moments of samples for %s: got mean:%v variance:%v want mean:%v variance:%v",
+				name, mean, variance, u.Mean(), u.Variance())
+		}
+	}
+
+	// The moments of the estimate are those of a mixture of
+	// kernels.
+	u := NewUnivariate([]float64{1, 2, 6}, []float64{1, 2, 1}, Epanechnikov{}, 2, nil)
+	if m := u.Mean(); !scalar.EqualWithinAbs(m, 11.0/4, 1e-14) {
+		t.Errorf("unexpected mean: got:%v want:%v", m, 11.0/4)
+	}
+	want := (1*1+2*4+1*36)/4.0 - (11.0/4)*(11.0/4) + 4
+	if v := u.Variance(); !scalar.EqualWithinAbs(v, want, 1e-14) {
+		t.Errorf("unexpected variance: got:%v want:%v", v, want)
+	}
+}
+
+func TestUnivariateGrid(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, w := bimodal(rnd, 200)
+	for _, k := range kernels {
+		for _, test := range []struct {
+			lo, hi float64
+			n      int
+		}{
+			{lo: -4, hi: 7, n: 512},
+			// The grid covers part of the sample.
+			{lo: 0, hi: 3, n: 301},
+			{lo: -1, hi: 1, n: 2},
+		} {
+			name := fmt.Sprintf("%T [%v, %v]", k, test.lo, test.hi)
+			u := NewUnivariate(x, w, k, 0.4, nil)
+			got := u.Grid(make([]float64, test.n), test.lo, test.hi)
+			delta := (test.hi - test.lo) / float64(test.n-1)
+			// The error of linear binning is of the order of
+			// the square of the ratio of the grid spacing to
+			// the bandwidth times the peak density, or of the
+			// ratio for discontinuous kernels.
+			tol := 0.5 * delta / 0.4 * delta / 0.4
+			if _, ok := k.(Uniform); ok {
+				tol = 0.5 * delta / 0.4
+			}
+			for i, v := range got {
+				q := test.lo + float64(i)*delta
+				if want := u.Prob(q); !scalar.EqualWithinAbs(v, want, tol) {
+					t.Errorf("unexpected grid density for %s at %v: got:%v want:%v", name, q, v, want)
+				}
+			}
+		}
+	}
+
+	// Sample values on the grid points are binned exactly.
+	u := NewUnivariate([]float64{0, 0.25, 1}, nil, Triangular{}, 0.2, nil)
+	got := u.Grid(make([]float64, 9), -0.5, 1.5)
+	for i, v := range got {
+		q := -0.5 + float64(i)*0.25
+		if want := u.Prob(q); !scalar.EqualWithinAbs(v, want, 1e-14) {
+			t.Errorf("unexpected grid density at %v: got:%v want:%v", q, v, want)
+		}
+	}
+}
+
+func TestUnivariatePanics(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2, 3}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "empty", fn: func() { NewUnivariate(nil, nil, Gaussian{}, 1, nil) }},
+		{name: "weights length", fn: func() { NewUnivariate(x, []float64{1}, Gaussian{}, 1, nil) }},
+		{name: "negative weight", fn: func() { NewUnivariate(x, []float64{1, -1, 1}, Gaussian{}, 1, nil) }},
+		{name: "zero weight", fn: func() { NewUnivariate(x, []float64{0, 0, 0}, Gaussian{}, 1, nil) }},
+		{name: "bandwidth", fn: func() { NewUnivariate(x, nil, Gaussian{}, 0, nil) }},
+		{name: "quantile", fn: func() { NewUnivariate(x, nil, Gaussian{}, 1, nil).Quantile(1.5) }},
+		{name: "grid points", fn: func() { NewUnivariate(x, nil, Gaussian{}, 1, nil).Grid(make([]float64, 1), 0, 1) }},
+		{name: "grid range", fn: func() { NewUnivariate(x, nil, Gaussian{}, 1, nil).Grid(make([]float64, 4), 1, 0) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return panicked
+}
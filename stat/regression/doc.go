@@ -0,0 +1,7 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package regression provides regression models and inference on their
+// parameters.
+package regression // import "gonum.org/v1/gonum/stat/regression"
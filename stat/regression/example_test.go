@@ -0,0 +1,49 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/regression"
+)
+
+func ExampleLinear() {
+	// The fuel consumption in litres per 100 km of a car
+	// driven at different speeds in km/h, with and without
+	// a roof box.
+	speed := []float64{60, 70, 80, 90, 100, 110, 120, 60, 70, 80, 90, 100, 110, 120}
+	box := []float64{0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 1, 1, 1}
+	fuel := []float64{5.1, 5.3, 5.8, 6.1, 6.7, 7.2, 7.9, 5.6, 6.0, 6.5, 7.1, 7.8, 8.5, 9.4}
+
+	x := mat.NewDense(len(speed), 2, nil)
+	x.SetCol(0, speed)
+	x.SetCol(1, box)
+
+	var l regression.Linear
+	err := l.Fit(x, fuel, nil, true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	coef := l.Coefficients(nil)
+	se := l.StdErrs(nil, regression.HC3)
+	p := l.PValues(nil, regression.HC3)
+	for i, name := range []string{"intercept", "speed", "roof box"} {
+		fmt.Printf("%-9s  %7.4f  (%.4f)  p = %.2g\n", name, coef[i], se[i], p[i])
+	}
+	fmt.Printf("R² = %.3f\n", l.RSquared())
+
+	lower, upper := l.PredictionIntervals(nil, nil, mat.NewDense(1, 2, []float64{130, 1}), nil, 0.95)
+	fmt.Printf("95%% prediction interval at 130 km/h with a roof box: [%.2f, %.2f]\n", lower[0], upper[0])
+
+	// Output:
+	// intercept   1.3500  (0.5387)  p = 0.029
+	// speed       0.0550  (0.0053)  p = 5e-07
+	// roof box    0.9714  (0.1598)  p = 8e-05
+	// R² = 0.968
+	// 95% prediction interval at 130 km/h with a roof box: [8.82, 10.12]
+}
@@ -0,0 +1,586 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Covariance specifies an estimator of the covariance matrix of the
+// coefficients of a linear regression.
+type Covariance int
+
+const (
+	// Classical is the estimator σ² (Xᵀ W X)⁻¹ that assumes
+	// homoscedastic errors, where σ² is the residual variance.
+	Classical Covariance = iota
+
+	// HC0 to HC3 are the heteroscedasticity consistent sandwich
+	// estimators (Xᵀ W X)⁻¹ Xᵀ W Ω X (Xᵀ W X)⁻¹ where Ω is
+	// diagonal with elements ωᵢ computed from the residuals eᵢ,
+	// the leverages hᵢ, the number of observations n and the
+	// number of coefficients p.
+	//
+	// HC0 is White's estimator with ωᵢ = eᵢ².
+	HC0
+	// HC1 scales HC0 by n/(n-p), with ωᵢ = n/(n-p) eᵢ².
+	HC1
+	// HC2 uses ωᵢ = eᵢ²/(1-hᵢ).
+	HC2
+	// HC3 uses ωᵢ = eᵢ²/(1-hᵢ)², and approximates the jackknife
+	// estimator.
+	HC3
+)
+
+// Linear is a linear regression model fitted by weighted least squares.
+// The results of the regression are only valid if the call to Fit was
+// successful.
+//
+// References:
+//   - White, H. (1980). A heteroskedasticity-consistent covariance matrix
+//     estimator and a direct test for heteroskedasticity. Econometrica,
+//     48(4), 817-838.
+//   - MacKinnon, J. G. and White, H. (1985). Some heteroskedasticity-consistent
+//     covariance matrix estimators with improved finite sample properties.
+//     Journal of Econometrics, 29(3), 305-325.
+type Linear struct {
+	// n is the number of rows of the design and
+	// nobs is the number of observations with a
+	// non-zero weight.
+	n, nobs int
+	// k is the number of columns of the design
+	// without the intercept and p is the number
+	// of coefficients.
+	k, p      int
+	intercept bool
+
+	weights []float64
+	coef    []float64
+	fitted  []float64
+	resid   []float64
+
+	// q is the thin orthonormal factor of the
+	// weighted design, and rinv is the inverse
+	// of its triangular factor.
+	q    *mat.Dense
+	rinv *mat.TriDense
+
+	// rss and mss are the weighted residual and
+	// model sums of squares.
+	rss, mss float64
+
+	ok bool
+}
+
+// Fit fits the linear model y = X β + ε by weighted least squares, where
+// X is the n×k design matrix x, and the model has an additional intercept
+// coefficient if intercept is true. The intercept is the first coefficient
+// of the fitted model. The least squares problem is solved by a QR
+// decomposition of the weighted design matrix.
+//
+// The weights slice is used to weight the observations, so that the weighted
+// sum of squared residuals Σ wᵢ eᵢ² is minimized. The weights are inversely
+// proportional to the variance of the errors. If weights is nil, each weight
+// is considered to have a value of one, otherwise the length of weights must
+// match the number of observations or Fit will panic. Fit will also panic
+// if the length of y does not match the number of observations or if any
+// weight is negative. Observations with zero weight do not contribute to
+// the fit or to the degrees of freedom.
+//
+// Fit returns an error if there are not more observations with non-zero
+// weight than coefficients or if the weighted design matrix is rank
+// deficient.
+func (l *Linear) Fit(x mat.Matrix, y, weights []float64, intercept bool) error {
+	n, k := x.Dims()
+	if len(y) != n {
+		panic("regression: len(y) != observations")
+	}
+	if weights != nil && len(weights) != n {
+		panic("regression: len(weights) != observations")
+	}
+	p := k
+	if intercept {
+		p++
+	}
+	nobs := n
+	if weights != nil {
+		nobs = 0
+		for _, w := range weights {
+			if w < 0 {
+				panic("regression: negative weight")
+			}
+			if w != 0 {
+				nobs++
+			}
+		}
+	}
+	l.ok = false
+	if nobs <= p {
+		return errors.New("regression: too few observations")
+	}
+
+	// Construct the weighted design and response.
+	xw := mat.NewDense(n, p, nil)
+	yw := make([]float64, n)
+	for i := 0; i < n; i++ {
+		s := 1.0
+		if weights != nil {
+			s = math.Sqrt(weights[i])
+		}
+		j := 0
+		if intercept {
+			xw.Set(i, 0, s)
+			j = 1
+		}
+		for c := 0; c < k; c++ {
+			xw.Set(i, j+c, s*x.At(i, c))
+		}
+		yw[i] = s * y[i]
+	}
+
+	var qr mat.QR
+	qr.Factorize(xw)
+	var beta mat.VecDense
+	err := qr.SolveVecTo(&beta, false, mat.NewVecDense(n, yw))
+	if err != nil {
+		return err
+	}
+
+	var r mat.Dense
+	r.ReuseAs(p, p)
+	qr.RTo(&r)
+	rt := mat.NewTriDense(p, mat.Upper, nil)
+	for i := 0; i < p; i++ {
+		for j := i; j < p; j++ {
+			rt.SetTri(i, j, r.At(i, j))
+		}
+	}
+	rinv := mat.NewTriDense(p, mat.Upper, nil)
+	err = rinv.InverseTri(rt)
+	if err != nil {
+		return err
+	}
+	q := mat.NewDense(n, p, nil)
+	q.Mul(xw, rinv)
+
+	l.n, l.nobs, l.k, l.p = n, nobs, k, p
+	l.intercept = intercept
+	l.weights = append(l.weights[:0], weights...)
+	if weights == nil {
+		l.weights = nil
+	}
+	l.coef = mat.Col(resize(l.coef, p), 0, &beta)
+	l.q = q
+	l.rinv = rinv
+	l.fitted = l.predict(resize(l.fitted, n), x)
+	l.resid = resize(l.resid, n)
+	for i, v := range y {
+		l.resid[i] = v - l.fitted[i]
+	}
+
+	// Compute the sums of squares. With an intercept the
+	// model sum of squares is about the weighted mean of the
+	// fitted values, otherwise it is about zero.
+	var mean float64
+	if intercept {
+		var sumw float64
+		for i, f := range l.fitted {
+			w := l.weight(i)
+			mean += w * f
+			sumw += w
+		}
+		mean /= sumw
+	}
+	l.rss, l.mss = 0, 0
+	for i, e := range l.resid {
+		w := l.weight(i)
+		l.rss += w * e * e
+		d := l.fitted[i] - mean
+		l.mss += w * d * d
+	}
+
+	l.ok = true
+	return nil
+}
+
+func (l *Linear) weight(i int) float64 {
+	if l.weights == nil {
+		return 1
+	}
+	return l.weights[i]
+}
+
+func (l *Linear) checkOK() {
+	if !l.ok {
+		panic("regression: use of unsuccessful linear regression")
+	}
+}
+
+// Coefficients returns the estimated coefficients of the model. If the
+// model has an intercept it is the first coefficient.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients,
+// Coefficients will panic. Coefficients will also panic if the receiver
+// does not contain a successful fit.
+func (l *Linear) Coefficients(dst []float64) []float64 {
+	l.checkOK()
+	dst = useSlice(dst, l.p)
+	copy(dst, l.coef)
+	return dst
+}
+
+// Fitted returns the fitted values of the observations.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of observations, Fitted
+// will panic. Fitted will also panic if the receiver does not contain a
+// successful fit.
+func (l *Linear) Fitted(dst []float64) []float64 {
+	l.checkOK()
+	dst = useSlice(dst, l.n)
+	copy(dst, l.fitted)
+	return dst
+}
+
+// Residuals returns the unweighted residuals of the observations, the
+// differences between the responses and the fitted values.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of observations, Residuals
+// will panic. Residuals will also panic if the receiver does not contain a
+// successful fit.
+func (l *Linear) Residuals(dst []float64) []float64 {
+	l.checkOK()
+	dst = useSlice(dst, l.n)
+	copy(dst, l.resid)
+	return dst
+}
+
+// Leverages returns the leverages of the observations, the diagonal
+// elements of the hat matrix of the weighted design.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of observations, Leverages
+// will panic. Leverages will also panic if the receiver does not contain a
+// successful fit.
+func (l *Linear) Leverages(dst []float64) []float64 {
+	l.checkOK()
+	dst = useSlice(dst, l.n)
+	for i := range dst {
+		row := l.q.RawRowView(i)
+		dst[i] = floats.Dot(row, row)
+	}
+	return dst
+}
+
+// ResidualDF returns the residual degrees of freedom of the model, the
+// number of observations with non-zero weight less the number of
+// coefficients. ResidualDF will panic if the receiver does not contain a
+// successful fit.
+func (l *Linear) ResidualDF() float64 {
+	l.checkOK()
+	return float64(l.nobs - l.p)
+}
+
+// ResidualVariance returns the estimate of the variance of the errors of
+// an observation with unit weight, the weighted residual sum of squares
+// divided by the residual degrees of freedom. ResidualVariance will panic
+// if the receiver does not contain a successful fit.
+func (l *Linear) ResidualVariance() float64 {
+	l.checkOK()
+	return l.rss / float64(l.nobs-l.p)
+}
+
+// RSquared returns the coefficient of determination of the model, the
+// proportion of the weighted sum of squares of the responses explained by
+// the model. If the model has an intercept the sums of squares are about
+// the weighted mean, otherwise they are about zero. RSquared will panic if
+// the receiver does not contain a successful fit.
+func (l *Linear) RSquared() float64 {
+	l.checkOK()
+	return l.mss / (l.mss + l.rss)
+}
+
+// AdjustedRSquared returns the coefficient of determination of the model
+// adjusted for the number of coefficients. AdjustedRSquared will panic if
+// the receiver does not contain a successful fit.
+func (l *Linear) AdjustedRSquared() float64 {
+	l.checkOK()
+	n := float64(l.nobs)
+	if l.intercept {
+		n--
+	}
+	return 1 - (1-l.RSquared())*n/float64(l.nobs-l.p)
+}
+
+// FTest returns the F-statistic of the model and its p-value, testing the
+// null hypothesis that all the coefficients other than the intercept are
+// zero, and the degrees of freedom of the F distribution of the statistic
+// under the null hypothesis. The test assumes homoscedastic errors. FTest
+// will panic if the receiver does not contain a successful fit.
+func (l *Linear) FTest() (f, p, df1, df2 float64) {
+	l.checkOK()
+	df1 = float64(l.k)
+	df2 = float64(l.nobs - l.p)
+	if l.k == 0 {
+		return math.NaN(), math.NaN(), df1, df2
+	}
+	f = (l.mss / df1) / (l.rss / df2)
+	return f, distuv.F{D1: df1, D2: df2}.Survival(f), df1, df2
+}
+
+// CovarianceMatrix stores the estimate of the covariance matrix of the
+// coefficients of the model computed by the estimator cov into dst.
+//
+// The dst matrix must either be empty or have the same number of rows as
+// the number of coefficients. CovarianceMatrix will panic if the receiver
+// does not contain a successful fit.
+func (l *Linear) CovarianceMatrix(dst *mat.SymDense, cov Covariance) {
+	l.checkOK()
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(l.p)
+	} else if dst.SymmetricDim() != l.p {
+		panic(mat.ErrShape)
+	}
+	if cov == Classical {
+		dst.SymOuterK(l.rss/float64(l.nobs-l.p), l.rinv)
+		return
+	}
+
+	// The sandwich estimator is Rinv Qᵀ Ω Q Rinvᵀ where X = Q R
+	// is the weighted design.
+	b := mat.NewDense(l.n, l.p, nil)
+	for i := 0; i < l.n; i++ {
+		w := l.weight(i)
+		if w == 0 {
+			continue
+		}
+		e2 := w * l.resid[i] * l.resid[i]
+		row := l.q.RawRowView(i)
+		h := floats.Dot(row, row)
+		var omega float64
+		switch cov {
+		case HC0:
+			omega = e2
+		case HC1:
+			omega = e2 * float64(l.nobs) / float64(l.nobs-l.p)
+		case HC2:
+			if h < 1 {
+				omega = e2 / (1 - h)
+			}
+		case HC3:
+			if h < 1 {
+				omega = e2 / ((1 - h) * (1 - h))
+			}
+		default:
+			panic("regression: unknown covariance estimator")
+		}
+		floats.ScaleTo(b.RawRowView(i), math.Sqrt(omega), row)
+	}
+	var a mat.Dense
+	a.Mul(b, l.rinv.T())
+	dst.SymOuterK(1, a.T())
+}
+
+// StdErrs returns the standard errors of the coefficients of the model
+// computed from the covariance estimator cov.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients, StdErrs
+// will panic. StdErrs will also panic if the receiver does not contain a
+// successful fit.
+func (l *Linear) StdErrs(dst []float64, cov Covariance) []float64 {
+	l.checkOK()
+	dst = useSlice(dst, l.p)
+	var c mat.SymDense
+	l.CovarianceMatrix(&c, cov)
+	for i := range dst {
+		dst[i] = math.Sqrt(c.At(i, i))
+	}
+	return dst
+}
+
+// TStats returns the t-statistics of the coefficients of the model, the
+// ratios of the coefficients to their standard errors computed from the
+// covariance estimator cov.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients, TStats
+// will panic. TStats will also panic if the receiver does not contain a
+// successful fit.
+func (l *Linear) TStats(dst []float64, cov Covariance) []float64 {
+	dst = l.StdErrs(dst, cov)
+	for i, se := range dst {
+		dst[i] = l.coef[i] / se
+	}
+	return dst
+}
+
+// PValues returns the two-sided p-values of the t-tests of the null
+// hypotheses that each coefficient of the model is zero, using standard
+// errors computed from the covariance estimator cov. The null distribution
+// of the t-statistics is taken to be Student's t distribution with the
+// residual degrees of freedom of the model.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients, PValues
+// will panic. PValues will also panic if the receiver does not contain a
+// successful fit.
+func (l *Linear) PValues(dst []float64, cov Covariance) []float64 {
+	dst = l.TStats(dst, cov)
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(l.nobs - l.p)}
+	for i, v := range dst {
+		dst[i] = 2 * t.Survival(math.Abs(v))
+	}
+	return dst
+}
+
+// ConfidenceIntervals returns the bounds of the confidence intervals at
+// the given level for the coefficients of the model, using standard errors
+// computed from the covariance estimator cov.
+//
+// If lower or upper are nil, new slices are allocated and returned. If they
+// are not nil and their length does not equal the number of coefficients,
+// ConfidenceIntervals will panic. ConfidenceIntervals will also panic if
+// level is not in (0, 1) or if the receiver does not contain a successful
+// fit.
+func (l *Linear) ConfidenceIntervals(lower, upper []float64, cov Covariance, level float64) ([]float64, []float64) {
+	checkLevel(level)
+	se := l.StdErrs(nil, cov)
+	lower = useSlice(lower, l.p)
+	upper = useSlice(upper, l.p)
+	q := l.tQuantile(level)
+	for i, b := range l.coef {
+		lower[i] = b - q*se[i]
+		upper[i] = b + q*se[i]
+	}
+	return lower, upper
+}
+
+// Predict returns the predicted responses of the model for the rows of the
+// design matrix x, which must have the same number of columns as the design
+// matrix used in the call to Fit.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of rows of x, Predict will
+// panic. Predict will also panic if the receiver does not contain a
+// successful fit.
+func (l *Linear) Predict(dst []float64, x mat.Matrix) []float64 {
+	l.checkOK()
+	r, c := x.Dims()
+	if c != l.k {
+		panic(mat.ErrShape)
+	}
+	return l.predict(useSlice(dst, r), x)
+}
+
+func (l *Linear) predict(dst []float64, x mat.Matrix) []float64 {
+	for i := range dst {
+		var v float64
+		j := 0
+		if l.intercept {
+			v = l.coef[0]
+			j = 1
+		}
+		for c := 0; c < l.k; c++ {
+			v += l.coef[j+c] * x.At(i, c)
+		}
+		dst[i] = v
+	}
+	return dst
+}
+
+// PredictionIntervals returns the bounds of the prediction intervals at the
+// given level for new observations at the rows of the design matrix x,
+// which must have the same number of columns as the design matrix used in
+// the call to Fit. The prediction intervals account for the uncertainty in
+// the estimated coefficients and for the variance of the errors of the new
+// observations, which is the residual variance divided by the observation
+// weight. If weights is nil, each weight is considered to have a value of
+// one, otherwise the length of weights must match the number of rows of x
+// or PredictionIntervals will panic. The intervals assume homoscedastic
+// errors.
+//
+// If lower or upper are nil, new slices are allocated and returned. If they
+// are not nil and their length does not equal the number of rows of x,
+// PredictionIntervals will panic. PredictionIntervals will also panic if
+// level is not in (0, 1) or if the receiver does not contain a successful
+// fit.
+func (l *Linear) PredictionIntervals(lower, upper []float64, x mat.Matrix, weights []float64, level float64) ([]float64, []float64) {
+	checkLevel(level)
+	pred := l.Predict(nil, x)
+	m := len(pred)
+	if weights != nil && len(weights) != m {
+		panic("regression: len(weights) != observations")
+	}
+	lower = useSlice(lower, m)
+	upper = useSlice(upper, m)
+
+	sigma2 := l.rss / float64(l.nobs-l.p)
+	q := l.tQuantile(level)
+	row := make([]float64, l.p)
+	u := make([]float64, l.p)
+	for i, y := range pred {
+		j := 0
+		if l.intercept {
+			row[0] = 1
+			j = 1
+		}
+		for c := 0; c < l.k; c++ {
+			row[j+c] = x.At(i, c)
+		}
+		// The variance of the predicted mean is
+		// σ² xᵀ (Xᵀ W X)⁻¹ x = σ² |Rinvᵀ x|².
+		mat.NewVecDense(l.p, u).MulVec(l.rinv.T(), mat.NewVecDense(l.p, row))
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		d := q * math.Sqrt(sigma2*(floats.Dot(u, u)+1/w))
+		lower[i] = y - d
+		upper[i] = y + d
+	}
+	return lower, upper
+}
+
+// tQuantile returns the two-sided critical value of Student's t
+// distribution with the residual degrees of freedom at the given level.
+func (l *Linear) tQuantile(level float64) float64 {
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(l.nobs - l.p)}
+	return t.Quantile(0.5 + level/2)
+}
+
+// checkLevel panics if level is not a valid confidence level.
+func checkLevel(level float64) {
+	if !(0 < level && level < 1) {
+		panic("regression: confidence level out of range")
+	}
+}
+
+// useSlice returns a slice of length n, allocating if dst is nil and
+// panicking if dst is not nil and has a different length.
+func useSlice(dst []float64, n int) []float64 {
+	if dst == nil {
+		return make([]float64, n)
+	}
+	if len(dst) != n {
+		panic("regression: destination length mismatch")
+	}
+	return dst
+}
+
+// resize returns a slice of length n, reusing the storage of s if possible.
+func resize(s []float64, n int) []float64 {
+	if cap(s) < n {
+		return make([]float64, n)
+	}
+	return s[:n]
+}
@@ -0,0 +1,392 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Speed and stopping distances of cars from Ezekiel (1930),
+// the cars data set of R.
+var (
+	carsSpeed = []float64{
+		4, 4, 7, 7, 8, 9, 10, 10, 10, 11, 11, 12, 12, 12, 12, 13, 13,
+		13, 13, 14, 14, 14, 14, 15, 15, 15, 16, 16, 17, 17, 17, 18, 18,
+		18, 18, 19, 19, 19, 20, 20, 20, 20, 20, 22, 23, 24, 24, 24, 24, 25,
+	}
+	carsDist = []float64{
+		2, 10, 4, 22, 16, 10, 18, 26, 34, 17, 28, 14, 20, 24, 28, 26, 34,
+		34, 46, 26, 36, 60, 80, 20, 26, 54, 32, 40, 32, 40, 50, 42, 56,
+		76, 84, 36, 46, 68, 32, 48, 52, 56, 64, 66, 54, 70, 92, 93, 120, 85,
+	}
+)
+
+func TestLinearCars(t *testing.T) {
+	t.Parallel()
+	// Reference values from summary(lm(dist ~ speed, cars)) in R.
+	var l Linear
+	err := l.Fit(mat.NewDense(len(carsSpeed), 1, carsSpeed), carsDist, nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, test := range []struct {
+		name string
+		got  []float64
+		want []float64
+		tol  float64
+	}{
+		{name: "coefficients", got: l.Coefficients(nil), want: []float64{-17.5791, 3.9324}, tol: 5e-5},
+		{name: "standard errors", got: l.StdErrs(nil, Classical), want: []float64{6.7584, 0.4155}, tol: 5e-5},
+		{name: "t-statistics", got: l.TStats(nil, Classical), want: []float64{-2.601, 9.464}, tol: 5e-4},
+		{name: "p-values", got: l.PValues(nil, Classical), want: []float64{0.0123, 1.49e-12}, tol: 5e-3},
+	} {
+		for i := range test.got {
+			if !scalar.EqualWithinAbsOrRel(test.got[i], test.want[i], test.tol, test.tol) {
+				t.Errorf("unexpected %s: got:%v want:%v", test.name, test.got, test.want)
+				break
+			}
+		}
+	}
+	if got := math.Sqrt(l.ResidualVariance()); !scalar.EqualWithinAbs(got, 15.38, 5e-3) || l.ResidualDF() != 48 {
+		t.Errorf("unexpected residual standard error: got:%v on %v df want:15.38 on 48 df", got, l.ResidualDF())
+	}
+	if got := l.RSquared(); !scalar.EqualWithinAbs(got, 0.6511, 5e-5) {
+		t.Errorf("unexpected R²: got:%v want:0.6511", got)
+	}
+	if got := l.AdjustedRSquared(); !scalar.EqualWithinAbs(got, 0.6438, 5e-5) {
+		t.Errorf("unexpected adjusted R²: got:%v want:0.6438", got)
+	}
+	f, p, df1, df2 := l.FTest()
+	if !scalar.EqualWithinAbs(f, 89.57, 5e-3) || df1 != 1 || df2 != 48 || !scalar.EqualWithinRel(p, 1.49e-12, 5e-3) {
+		t.Errorf("unexpected F-test: got F:%v on %v and %v df p:%v want F:89.57 on 1 and 48 df p:1.49e-12", f, df1, df2, p)
+	}
+}
+
+// randomProblem returns a random weighted regression problem with n
+// observations and k regressors.
+func randomProblem(rnd *rand.Rand, n, k int, weighted bool) (x *mat.Dense, y, weights []float64) {
+	x = mat.NewDense(n, k, nil)
+	y = make([]float64, n)
+	if weighted {
+		weights = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		v := 1.0
+		for j := 0; j < k; j++ {
+			x.Set(i, j, rnd.NormFloat64())
+			v += float64(j+1) * x.At(i, j)
+		}
+		// Heteroscedastic errors.
+		y[i] = v + (1+math.Abs(x.At(i, 0)))*rnd.NormFloat64()
+		if weighted {
+			weights[i] = 0.5 + rnd.Float64()
+		}
+	}
+	if weighted {
+		weights[0] = 0
+	}
+	return x, y, weights
+}
+
+func TestLinear(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, k      int
+		weighted  bool
+		intercept bool
+	}{
+		{n: 10, k: 1, weighted: false, intercept: true},
+		{n: 10, k: 1, weighted: true, intercept: true},
+		{n: 25, k: 3, weighted: false, intercept: true},
+		{n: 25, k: 3, weighted: true, intercept: true},
+		{n: 25, k: 3, weighted: false, intercept: false},
+		{n: 40, k: 5, weighted: true, intercept: false},
+	} {
+		name := fmt.Sprintf("n=%d k=%d weighted=%t intercept=%t", test.n, test.k, test.weighted, test.intercept)
+		x, y, weights := randomProblem(rnd, test.n, test.k, test.weighted)
+		var l Linear
+		err := l.Fit(x, y, weights, test.intercept)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", name, err)
+		}
+
+		// Compute the estimates from the normal equations.
+		p := test.k
+		if test.intercept {
+			p++
+		}
+		design := mat.NewDense(test.n, p, nil)
+		w := make([]float64, test.n)
+		nobs := 0
+		for i := 0; i < test.n; i++ {
+			j := 0
+			if test.intercept {
+				design.Set(i, 0, 1)
+				j = 1
+			}
+			for c := 0; c < test.k; c++ {
+				design.Set(i, j+c, x.At(i, c))
+			}
+			w[i] = 1
+			if weights != nil {
+				w[i] = weights[i]
+			}
+			if w[i] != 0 {
+				nobs++
+			}
+		}
+		W := mat.NewDiagDense(test.n, w)
+		var xtw, xtwx, inv mat.Dense
+		xtw.Mul(design.T(), W)
+		xtwx.Mul(&xtw, design)
+		err = inv.Inverse(&xtwx)
+		if err != nil {
+			t.Fatalf("unexpected error inverting normal matrix for %s: %v", name, err)
+		}
+		var beta mat.VecDense
+		beta.MulVec(&xtw, mat.NewVecDense(test.n, y))
+		beta.MulVec(&inv, &beta)
+		coef := l.Coefficients(nil)
+		if !floats.EqualApprox(coef, mat.Col(nil, 0, &beta), 1e-10) {
+			t.Errorf("unexpected coefficients for %s: got:%v want:%v", name, coef, mat.Col(nil, 0, &beta))
+		}
+
+		resid := l.Residuals(nil)
+		fitted := l.Fitted(nil)
+		var rss float64
+		for i := range y {
+			if !scalar.EqualWithinAbs(resid[i]+fitted[i], y[i], 1e-12) {
+				t.Errorf("residual and fitted value do not sum to response for %s", name)
+			}
+			rss += w[i] * resid[i] * resid[i]
+		}
+		rdf := float64(nobs - p)
+		if l.ResidualDF() != rdf {
+			t.Errorf("unexpected residual degrees of freedom for %s: got:%v want:%v", name, l.ResidualDF(), rdf)
+		}
+		sigma2 := rss / rdf
+		if !scalar.EqualWithinRel(l.ResidualVariance(), sigma2, 1e-12) {
+			t.Errorf("unexpected residual variance for %s: got:%v want:%v", name, l.ResidualVariance(), sigma2)
+		}
+		if got := l.Predict(nil, x); !floats.EqualApprox(got, fitted, 1e-12) {
+			t.Errorf("prediction does not match fitted values for %s", name)
+		}
+
+		// The hat matrix of the weighted design.
+		sw := make([]float64, test.n)
+		for i, v := range w {
+			sw[i] = math.Sqrt(v)
+		}
+		var xw, hat, tmp mat.Dense
+		xw.Mul(mat.NewDiagDense(test.n, sw), design)
+		tmp.Mul(&xw, &inv)
+		hat.Mul(&tmp, xw.T())
+		h := l.Leverages(nil)
+		for i := range h {
+			if !scalar.EqualWithinAbs(h[i], hat.At(i, i), 1e-12) {
+				t.Errorf("unexpected leverage for %s: got:%v want:%v", name, h[i], hat.At(i, i))
+			}
+		}
+
+		// The covariance estimators.
+		for _, cov := range []Covariance{Classical, HC0, HC1, HC2, HC3} {
+			var want mat.Dense
+			if cov == Classical {
+				want.Scale(sigma2, &inv)
+			} else {
+				omega := make([]float64, test.n)
+				for i, e := range resid {
+					e2 := w[i] * e * e
+					switch cov {
+					case HC0:
+						omega[i] = e2
+					case HC1:
+						omega[i] = e2 * float64(nobs) / rdf
+					case HC2:
+						omega[i] = e2 / (1 - h[i])
+					case HC3:
+						omega[i] = e2 / ((1 - h[i]) * (1 - h[i]))
+					}
+				}
+				var xto, meat, bread mat.Dense
+				xto.Mul(xw.T(), mat.NewDiagDense(test.n, omega))
+				meat.Mul(&xto, &xw)
+				bread.Mul(&inv, &meat)
+				want.Mul(&bread, &inv)
+			}
+			var got mat.SymDense
+			l.CovarianceMatrix(&got, cov)
+			if !mat.EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("unexpected covariance matrix for %s cov=%d:\ngot: %v\nwant:%v", name, cov,
+					mat.Formatted(&got, mat.Prefix("      ")), mat.Formatted(&want, mat.Prefix("      ")))
+			}
+
+			se := l.StdErrs(nil, cov)
+			tstat := l.TStats(nil, cov)
+			pval := l.PValues(nil, cov)
+			lower, upper := l.ConfidenceIntervals(nil, nil, cov, 0.9)
+			dist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: rdf}
+			for i := range se {
+				if !scalar.EqualWithinRel(se[i], math.Sqrt(want.At(i, i)), 1e-10) {
+					t.Errorf("unexpected standard error for %s cov=%d", name, cov)
+				}
+				if !scalar.EqualWithinRel(tstat[i], coef[i]/se[i], 1e-14) {
+					t.Errorf("unexpected t-statistic for %s cov=%d", name, cov)
+				}
+				if !scalar.EqualWithinAbs(pval[i], 2*dist.CDF(-math.Abs(tstat[i])), 1e-14) {
+					t.Errorf("unexpected p-value for %s cov=%d", name, cov)
+				}
+				// A coefficient is outside its 90% confidence
+				// interval exactly when its p-value is less than 0.1.
+				for _, b := range []float64{lower[i], upper[i]} {
+					if pb := 2 * dist.Survival(math.Abs(coef[i]-b)/se[i]); !scalar.EqualWithinAbs(pb, 0.1, 1e-10) {
+						t.Errorf("unexpected confidence bound for %s cov=%d: p-value at bound %v", name, cov, pb)
+					}
+				}
+			}
+		}
+
+		// The coefficient of determination and F-test.
+		var mean float64
+		if test.intercept {
+			mean = stat.Mean(y, w)
+		}
+		var tss float64
+		for i, v := range y {
+			tss += w[i] * (v - mean) * (v - mean)
+		}
+		r2 := 1 - rss/tss
+		if !scalar.EqualWithinAbs(l.RSquared(), r2, 1e-12) {
+			t.Errorf("unexpected R² for %s: got:%v want:%v", name, l.RSquared(), r2)
+		}
+		f, pf, df1, df2 := l.FTest()
+		wantF := (r2 / float64(test.k)) / ((1 - r2) / rdf)
+		if df1 != float64(test.k) || df2 != rdf || !scalar.EqualWithinRel(f, wantF, 1e-10) {
+			t.Errorf("unexpected F-statistic for %s: got:%v on %v and %v df want:%v", name, f, df1, df2, wantF)
+		}
+		if want := (distuv.F{D1: df1, D2: df2}).Survival(wantF); !scalar.EqualWithinAbs(pf, want, 1e-12) {
+			t.Errorf("unexpected F-test p-value for %s: got:%v want:%v", name, pf, want)
+		}
+
+		// The prediction intervals.
+		xn, _, wn := randomProblem(rnd, 5, test.k, true)
+		pred := l.Predict(nil, xn)
+		lower, upper := l.PredictionIntervals(nil, nil, xn, wn, 0.95)
+		q := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: rdf}.Quantile(0.975)
+		for i := range pred {
+			row := make([]float64, p)
+			j := 0
+			if test.intercept {
+				row[0] = 1
+				j = 1
+			}
+			for c := 0; c < test.k; c++ {
+				row[j+c] = xn.At(i, c)
+			}
+			v := mat.NewVecDense(p, row)
+			variance := sigma2 * (mat.Inner(v, &inv, v) + 1/wn[i])
+			if !scalar.EqualWithinAbs(upper[i]-pred[i], q*math.Sqrt(variance), 1e-10) ||
+				!scalar.EqualWithinAbs(pred[i]-lower[i], q*math.Sqrt(variance), 1e-10) {
+				t.Errorf("unexpected prediction interval for %s: got:[%v, %v] around %v", name, lower[i], upper[i], pred[i])
+			}
+		}
+	}
+}
+
+func TestLinearSimple(t *testing.T) {
+	t.Parallel()
+	// A single regressor with an intercept agrees with stat.LinearRegression.
+	rnd := rand.New(rand.NewSource(1))
+	x, y, weights := randomProblem(rnd, 30, 1, true)
+	col := mat.Col(nil, 0, x)
+	alpha, beta := stat.LinearRegression(col, y, weights, false)
+	var l Linear
+	err := l.Fit(x, y, weights, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	coef := l.Coefficients(nil)
+	if !scalar.EqualWithinAbs(coef[0], alpha, 1e-12) || !scalar.EqualWithinAbs(coef[1], beta, 1e-12) {
+		t.Errorf("unexpected coefficients: got:%v want:[%v %v]", coef, alpha, beta)
+	}
+	if want := stat.RSquared(col, y, weights, alpha, beta); !scalar.EqualWithinAbs(l.RSquared(), want, 1e-12) {
+		t.Errorf("unexpected R²: got:%v want:%v", l.RSquared(), want)
+	}
+}
+
+func TestLinearRefit(t *testing.T) {
+	t.Parallel()
+	// Refitting a Linear with a different number of coefficients
+	// gives the same results as fitting a new Linear.
+	rnd := rand.New(rand.NewSource(1))
+	var l Linear
+	for _, test := range []struct {
+		n, k      int
+		weighted  bool
+		intercept bool
+	}{
+		{n: 20, k: 3, intercept: true},
+		{n: 15, k: 1, weighted: true},
+		{n: 25, k: 4, intercept: true},
+	} {
+		x, y, weights := randomProblem(rnd, test.n, test.k, test.weighted)
+		err := l.Fit(x, y, weights, test.intercept)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var want Linear
+		err = want.Fit(x, y, weights, test.intercept)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, w := l.Coefficients(nil), want.Coefficients(nil); !floats.Equal(got, w) {
+			t.Errorf("unexpected coefficients for k=%d: got:%v want:%v", test.k, got, w)
+		}
+		if got, w := l.StdErrs(nil, Classical), want.StdErrs(nil, Classical); !floats.Equal(got, w) {
+			t.Errorf("unexpected standard errors for k=%d: got:%v want:%v", test.k, got, w)
+		}
+		if got, w := l.Residuals(nil), want.Residuals(nil); !floats.Equal(got, w) {
+			t.Errorf("unexpected residuals for k=%d: got:%v want:%v", test.k, got, w)
+		}
+		if l.RSquared() != want.RSquared() {
+			t.Errorf("unexpected R² for k=%d: got:%v want:%v", test.k, l.RSquared(), want.RSquared())
+		}
+	}
+}
+
+func TestLinearFailure(t *testing.T) {
+	t.Parallel()
+	var l Linear
+	x := mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6})
+	if err := l.Fit(x, []float64{1, 2, 3}, nil, true); err == nil {
+		t.Error("expected error for too few observations")
+	}
+	x = mat.NewDense(5, 2, []float64{1, 2, 2, 4, 3, 6, 4, 8, 5, 10})
+	if err := l.Fit(x, []float64{1, 2, 3, 4, 6}, nil, true); err == nil {
+		t.Error("expected error for rank deficient design")
+	}
+	if !panics(func() { l.Coefficients(nil) }) {
+		t.Error("expected panic for use of unsuccessful fit")
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return panicked
+}
@@ -0,0 +1,332 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Settings holds the settings of a resampling computation.
+type Settings struct {
+	// Replicates is the number of resamples. If Replicates
+	// is zero, a default of 1999 is used for bootstraps and
+	// 9999 for permutation tests.
+	Replicates int
+
+	// Src is the source of the seeds of the random sources
+	// of the replicates. If Src is nil, the global source
+	// is used.
+	Src rand.Source
+
+	// Concurrent is the number of goroutines used to compute
+	// the replicates. If Concurrent is zero, the number of
+	// goroutines is runtime.GOMAXPROCS(0).
+	Concurrent int
+}
+
+// replicate calls fn concurrently for each of n replicates with the index
+// of the replicate, a source seeded for the replicate, and the goroutine's
+// own buffers of the given length for a resample and its weights. The
+// weights buffer is nil if weighted is false.
+func replicate(n, size int, weighted bool, settings *Settings, fn func(i int, src rand.Source, x, w []float64)) {
+	var seed func() uint64
+	if settings.Src == nil {
+		seed = rand.Uint64
+	} else {
+		seed = rand.New(settings.Src).Uint64
+	}
+	seeds := make([]uint64, n)
+	for i := range seeds {
+		seeds[i] = seed()
+	}
+
+	workers := settings.Concurrent
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for k := 0; k < workers; k++ {
+		go func() {
+			defer wg.Done()
+			x := make([]float64, size)
+			var w []float64
+			if weighted {
+				w = make([]float64, size)
+			}
+			src := rand.NewSource(0)
+			for i := range jobs {
+				src.Seed(seeds[i])
+				fn(i, src, x, w)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// settingsOrDefault returns the settings with the default number of
+// replicates filled in.
+func settingsOrDefault(settings *Settings, replicates int) *Settings {
+	s := Settings{}
+	if settings != nil {
+		s = *settings
+	}
+	if s.Replicates < 0 {
+		panic("resample: negative number of replicates")
+	}
+	if s.Replicates == 0 {
+		s.Replicates = replicates
+	}
+	return &s
+}
+
+// Replicates holds the bootstrap replicates of a statistic.
+type Replicates struct {
+	x, weights []float64
+	fn         Statistic
+
+	estimate float64
+	values   []float64
+
+	// stdErr and stdErrs are the estimated standard errors
+	// of the statistic for the sample and the resamples, for
+	// studentized intervals.
+	stdErr  float64
+	stdErrs []float64
+}
+
+// Bootstrap returns the bootstrap replicates of the statistic fn for
+// resamples of the weighted sample x drawn by the resampler r. If weights
+// is nil, all weights are one. If r is nil, the nonparametric bootstrap is
+// used. If settings is nil, the default settings are used.
+//
+// If stdErr is not nil, it must estimate the standard error of fn for a
+// sample, and is evaluated for the sample and each resample to compute the
+// studentized confidence interval.
+//
+// Bootstrap will panic if x has fewer than two elements or if weights is
+// not nil and its length does not equal the length of x.
+//
+// References:
+//   - Efron, B. and Tibshirani, R. J. (1993). An Introduction to the
+//     Bootstrap. Chapman & Hall.
+//   - Davison, A. C. and Hinkley, D. V. (1997). Bootstrap Methods and their
+//     Application. Cambridge University Press.
+func Bootstrap(x, weights []float64, fn, stdErr Statistic, r Resampler, settings *Settings) *Replicates {
+	if len(x) < 2 {
+		panic("resample: too few samples")
+	}
+	if weights != nil && len(weights) != len(x) {
+		panic("resample: slice length mismatch")
+	}
+	if r == nil {
+		r = Nonparametric{}
+	}
+	s := settingsOrDefault(settings, 1999)
+	b := &Replicates{
+		x:        append([]float64(nil), x...),
+		weights:  append([]float64(nil), weights...),
+		fn:       fn,
+		estimate: fn(x, weights),
+		values:   make([]float64, s.Replicates),
+	}
+	if stdErr != nil {
+		b.stdErr = stdErr(x, weights)
+		b.stdErrs = make([]float64, s.Replicates)
+	}
+	replicate(s.Replicates, len(x), weights != nil, s, func(i int, src rand.Source, xr, wr []float64) {
+		r.Resample(xr, wr, x, weights, src)
+		b.values[i] = fn(xr, wr)
+		if stdErr != nil {
+			b.stdErrs[i] = stdErr(xr, wr)
+		}
+	})
+	return b
+}
+
+// Estimate returns the value of the statistic for the sample.
+func (b *Replicates) Estimate() float64 { return b.estimate }
+
+// Values returns the values of the statistic for the resamples.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of replicates, Values
+// will panic.
+func (b *Replicates) Values(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(b.values))
+	} else if len(dst) != len(b.values) {
+		panic("resample: slice length mismatch")
+	}
+	copy(dst, b.values)
+	return dst
+}
+
+// StdErr returns the bootstrap estimate of the standard error of the
+// statistic, the standard deviation of the replicates.
+func (b *Replicates) StdErr() float64 {
+	return stat.StdDev(b.values, nil)
+}
+
+// Bias returns the bootstrap estimate of the bias of the statistic, the
+// mean of the replicates less the estimate.
+func (b *Replicates) Bias() float64 {
+	return stat.Mean(b.values, nil) - b.estimate
+}
+
+// Percentile returns the bootstrap percentile confidence interval for the
+// statistic at the given level, bounded by the empirical (1-level)/2 and
+// (1+level)/2 quantiles of the replicates.
+//
+// Percentile will panic if level is not in (0, 1).
+func (b *Replicates) Percentile(level float64) (lower, upper float64) {
+	checkLevel(level)
+	sorted := sortedCopy(b.values)
+	alpha := (1 - level) / 2
+	return quantile(alpha, sorted), quantile(1-alpha, sorted)
+}
+
+// BCa returns the bias-corrected and accelerated bootstrap confidence
+// interval for the statistic at the given level. The quantiles of the
+// percentile interval are adjusted for the median bias of the replicates
+// and for the rate of change of the standard error of the statistic with
+// its value, the acceleration, which is estimated from the jackknife
+// values of the statistic with each sample value left out in turn. The
+// jackknife ignores the structure of block and stratified resamples. The
+// bounds are NaN if all the replicates lie on the same side of the
+// estimate.
+//
+// BCa will panic if level is not in (0, 1).
+//
+// References:
+//   - Efron, B. (1987). Better bootstrap confidence intervals. Journal of
+//     the American Statistical Association, 82(397), 171-185.
+func (b *Replicates) BCa(level float64) (lower, upper float64) {
+	checkLevel(level)
+	var below float64
+	for _, v := range b.values {
+		switch {
+		case v < b.estimate:
+			below++
+		case v == b.estimate:
+			below += 0.5
+		}
+	}
+	std := distuv.UnitNormal
+	z0 := std.Quantile(below / float64(len(b.values)))
+	a := acceleration(jackknife(b.x, b.weights, b.fn))
+	alpha := (1 - level) / 2
+	adjust := func(p float64) float64 {
+		z := z0 + std.Quantile(p)
+		return std.CDF(z0 + z/(1-a*z))
+	}
+	sorted := sortedCopy(b.values)
+	return quantile(adjust(alpha), sorted), quantile(adjust(1-alpha), sorted)
+}
+
+// Studentized returns the studentized, or bootstrap-t, confidence interval
+// for the statistic at the given level,
+//
+//	[θ̂ - σ̂ t_{(1+level)/2}, θ̂ - σ̂ t_{(1-level)/2}],
+//
+// where θ̂ and σ̂ are the estimate and its standard error for the sample,
+// and t_p is the empirical p quantile of the studentized replicates
+// (θ*-θ̂)/σ*.
+//
+// Studentized will panic if level is not in (0, 1) or if the replicates
+// were computed without a standard error function.
+func (b *Replicates) Studentized(level float64) (lower, upper float64) {
+	checkLevel(level)
+	if b.stdErrs == nil {
+		panic("resample: no standard error function")
+	}
+	t := make([]float64, len(b.values))
+	for i, v := range b.values {
+		t[i] = (v - b.estimate) / b.stdErrs[i]
+	}
+	sort.Float64s(t)
+	alpha := (1 - level) / 2
+	return b.estimate - b.stdErr*quantile(1-alpha, t), b.estimate - b.stdErr*quantile(alpha, t)
+}
+
+// jackknife returns the values of the statistic for the sample with each
+// value left out in turn.
+func jackknife(x, weights []float64, fn Statistic) []float64 {
+	n := len(x)
+	xr := make([]float64, n-1)
+	var wr []float64
+	if weights != nil {
+		wr = make([]float64, n-1)
+	}
+	jack := make([]float64, n)
+	for i := range jack {
+		copy(xr, x[:i])
+		copy(xr[i:], x[i+1:])
+		if weights != nil {
+			copy(wr, weights[:i])
+			copy(wr[i:], weights[i+1:])
+		}
+		jack[i] = fn(xr, wr)
+	}
+	return jack
+}
+
+// acceleration returns the jackknife estimate of the acceleration of the
+// BCa interval,
+//
+//	a = Σ(θ̄-θ_i)³ / (6 (Σ(θ̄-θ_i)²)^(3/2)),
+//
+// where θ_i are the jackknife values and θ̄ is their mean.
+func acceleration(jack []float64) float64 {
+	mean := stat.Mean(jack, nil)
+	var s2, s3 float64
+	for _, v := range jack {
+		d := mean - v
+		s2 += d * d
+		s3 += d * d * d
+	}
+	if s2 == 0 {
+		return 0
+	}
+	return s3 / (6 * math.Pow(s2, 1.5))
+}
+
+// quantile returns the empirical p quantile of the sorted values in x, or
+// NaN if p is NaN.
+func quantile(p float64, x []float64) float64 {
+	if math.IsNaN(p) {
+		return math.NaN()
+	}
+	return stat.Quantile(p, stat.Empirical, x, nil)
+}
+
+func sortedCopy(x []float64) []float64 {
+	s := append([]float64(nil), x...)
+	sort.Float64s(s)
+	return s
+}
+
+// checkLevel panics if level is not a valid confidence level.
+func checkLevel(level float64) {
+	if !(0 < level && level < 1) {
+		panic("resample: confidence level out of range")
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/gonum/stat/hypothesis"
+)
+
+func normalSample(seed uint64, n int) []float64 {
+	rnd := rand.New(rand.NewSource(seed))
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = 10 + 2*rnd.NormFloat64()
+	}
+	return x
+}
+
+func stdErrMean(x, weights []float64) float64 {
+	return stat.StdDev(x, weights) / math.Sqrt(float64(len(x)))
+}
+
+func TestBootstrapDeterministic(t *testing.T) {
+	t.Parallel()
+	x := normalSample(1, 30)
+	w := make([]float64, len(x))
+	for i := range w {
+		w[i] = float64(i%4 + 1)
+	}
+	for _, r := range []Resampler{nil, Block{Length: 5}, Stratified{Strata: make([]int, len(x))}} {
+		var want []float64
+		for _, concurrent := range []int{1, 2, 7} {
+			b := Bootstrap(x, w, stat.Mean, nil, r, &Settings{Replicates: 200, Src: rand.NewSource(1), Concurrent: concurrent})
+			got := b.Values(nil)
+			if want == nil {
+				want = got
+				continue
+			}
+			if !floats.Equal(got, want) {
+				t.Errorf("replicates depend on concurrency for %T", r)
+			}
+		}
+	}
+}
+
+func TestBootstrapMean(t *testing.T) {
+	t.Parallel()
+	const n = 50
+	x := normalSample(2, n)
+	b := Bootstrap(x, nil, stat.Mean, stdErrMean, nil, &Settings{Replicates: 4000, Src: rand.NewSource(1)})
+	if b.Estimate() != stat.Mean(x, nil) {
+		t.Errorf("unexpected estimate: got:%v want:%v", b.Estimate(), stat.Mean(x, nil))
+	}
+	// The bootstrap standard error of the mean is that of the
+	// empirical distribution, √((n-1)/n) s/√n.
+	se := math.Sqrt(float64(n-1)/n) * stat.StdDev(x, nil) / math.Sqrt(n)
+	if !scalar.EqualWithinRel(b.StdErr(), se, 0.05) {
+		t.Errorf("unexpected standard error: got:%v want:%v", b.StdErr(), se)
+	}
+	if math.Abs(b.Bias()) > 4*se/math.Sqrt(4000) {
+		t.Errorf("unexpected bias: got:%v want:0", b.Bias())
+	}
+
+	// The intervals are close to the t interval.
+	ref := hypothesis.OneSampleT(x, 0, hypothesis.TwoSided, 0.9)
+	width := ref.Upper - ref.Lower
+	for _, test := range []struct {
+		name         string
+		lower, upper float64
+	}{
+		{name: "percentile", lower: first(b.Percentile(0.9)), upper: second(b.Percentile(0.9))},
+		{name: "BCa", lower: first(b.BCa(0.9)), upper: second(b.BCa(0.9))},
+		{name: "studentized", lower: first(b.Studentized(0.9)), upper: second(b.Studentized(0.9))},
+	} {
+		if math.Abs(test.lower-ref.Lower) > 0.1*width || math.Abs(test.upper-ref.Upper) > 0.1*width {
+			t.Errorf("unexpected %s interval: got:[%v, %v] want approximately:[%v, %v]",
+				test.name, test.lower, test.upper, ref.Lower, ref.Upper)
+		}
+	}
+}
+
+func first(a, _ float64) float64  { return a }
+func second(_, b float64) float64 { return b }
+
+func TestAcceleration(t *testing.T) {
+	t.Parallel()
+	// The jackknife acceleration of the mean is a sixth of the
+	// skewness of the sample divided by √n.
+	x := []float64{1, 2, 2, 3, 5, 8, 13, 21}
+	mean := stat.Mean(x, nil)
+	var s2, s3 float64
+	for _, v := range x {
+		d := v - mean
+		s2 += d * d
+		s3 += d * d * d
+	}
+	want := s3 / (6 * math.Pow(s2, 1.5))
+	got := acceleration(jackknife(x, nil, stat.Mean))
+	if !scalar.EqualWithinRel(got, want, 1e-12) {
+		t.Errorf("unexpected acceleration: got:%v want:%v", got, want)
+	}
+
+	// Weights are left out with their values.
+	w := []float64{1, 2, 1, 1, 3, 1, 1, 2}
+	jack := jackknife(x, w, stat.Mean)
+	for i := range x {
+		xr := append(append([]float64(nil), x[:i]...), x[i+1:]...)
+		wr := append(append([]float64(nil), w[:i]...), w[i+1:]...)
+		if want := stat.Mean(xr, wr); !scalar.EqualWithinRel(jack[i], want, 1e-14) {
+			t.Errorf("unexpected jackknife value %d: got:%v want:%v", i, jack[i], want)
+		}
+	}
+}
+
+func TestBCa(t *testing.T) {
+	t.Parallel()
+	values := make([]float64, 100)
+	for i := range values {
+		values[i] = float64(i)
+	}
+	// A statistic with constant jackknife values has no
+	// acceleration.
+	constant := func(x, _ []float64) float64 { return 1 }
+	x := []float64{1, 2, 3}
+
+	// With no bias and no acceleration the interval is the
+	// percentile interval.
+	b := &Replicates{x: x, fn: constant, estimate: 49.5, values: values}
+	lo, hi := b.BCa(0.9)
+	plo, phi := b.Percentile(0.9)
+	if lo != plo || hi != phi {
+		t.Errorf("unexpected unbiased BCa interval: got:[%v, %v] want:[%v, %v]", lo, hi, plo, phi)
+	}
+
+	// Bias shifts the quantiles of the interval.
+	b.estimate = 29.5
+	std := distuv.UnitNormal
+	z0 := std.Quantile(0.3)
+	lo, hi = b.BCa(0.9)
+	wlo := stat.Quantile(std.CDF(2*z0+std.Quantile(0.05)), stat.Empirical, values, nil)
+	whi := stat.Quantile(std.CDF(2*z0+std.Quantile(0.95)), stat.Empirical, values, nil)
+	if lo != wlo || hi != whi {
+		t.Errorf("unexpected biased BCa interval: got:[%v, %v] want:[%v, %v]", lo, hi, wlo, whi)
+	}
+	if !(lo < plo && hi < phi) {
+		t.Errorf("interval not shifted towards estimate: got:[%v, %v] percentile:[%v, %v]", lo, hi, plo, phi)
+	}
+
+	// All replicates above the estimate give no interval.
+	b.estimate = -1
+	lo, hi = b.BCa(0.9)
+	if !math.IsNaN(lo) || !math.IsNaN(hi) {
+		t.Errorf("unexpected interval for replicates above estimate: [%v, %v]", lo, hi)
+	}
+}
+
+func TestBootstrapPanics(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2, 3}
+	b := Bootstrap(x, nil, stat.Mean, nil, nil, &Settings{Replicates: 10})
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "too few", fn: func() { Bootstrap(x[:1], nil, stat.Mean, nil, nil, nil) }},
+		{name: "weights length", fn: func() { Bootstrap(x, []float64{1}, stat.Mean, nil, nil, nil) }},
+		{name: "negative replicates", fn: func() { Bootstrap(x, nil, stat.Mean, nil, nil, &Settings{Replicates: -1}) }},
+		{name: "level", fn: func() { b.Percentile(1) }},
+		{name: "studentized", fn: func() { b.Studentized(0.95) }},
+		{name: "values length", fn: func() { b.Values(make([]float64, 1)) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
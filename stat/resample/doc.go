@@ -0,0 +1,20 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package resample provides bootstrap and permutation resampling methods
+// for estimating the sampling distribution of a statistic.
+//
+// A statistic is a function of a weighted sample with the signature of
+// stat.Mean, so that functions of the stat package may be used directly.
+// The bootstrap replicates of a statistic are computed from resamples of
+// the data drawn by a Resampler, and provide its standard error, bias and
+// percentile, bias-corrected and accelerated, and studentized confidence
+// intervals. Permutation tests compare a statistic with its values for
+// random permutations of the data.
+//
+// Replicates are computed concurrently. Each replicate draws its random
+// numbers from a source seeded from the source in the Settings, so the
+// results are reproducible for a given seed regardless of the number of
+// goroutines used.
+package resample // import "gonum.org/v1/gonum/stat/resample"
@@ -0,0 +1,68 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample_test
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/hypothesis"
+	"gonum.org/v1/gonum/stat/resample"
+)
+
+func ExampleBootstrap() {
+	// The response times of a service in milliseconds.
+	x := []float64{
+		112, 98, 143, 87, 230, 105, 99, 121, 460, 101,
+		95, 133, 118, 91, 176, 108, 127, 89, 310, 115,
+	}
+
+	// The median has no simple closed-form standard error.
+	median := func(x, weights []float64) float64 {
+		s := append([]float64(nil), x...)
+		w := append([]float64(nil), weights...)
+		stat.SortWeighted(s, w)
+		return stat.Quantile(0.5, stat.Empirical, s, w)
+	}
+
+	b := resample.Bootstrap(x, nil, median, nil, nil, &resample.Settings{
+		Replicates: 2000,
+		Src:        rand.NewSource(1),
+	})
+	fmt.Printf("median = %v, standard error = %.2f\n", b.Estimate(), b.StdErr())
+	lo, hi := b.Percentile(0.95)
+	fmt.Printf("95%% percentile interval: [%v, %v]\n", lo, hi)
+	lo, hi = b.BCa(0.95)
+	fmt.Printf("95%% BCa interval:        [%v, %v]\n", lo, hi)
+
+	// Output:
+	// median = 112, standard error = 7.66
+	// 95% percentile interval: [99, 127]
+	// 95% BCa interval:        [99, 127]
+}
+
+func ExamplePermutation() {
+	// The response times of a service before and after a
+	// change, concatenated.
+	before := []float64{112, 98, 143, 87, 230, 105, 99, 121}
+	after := []float64{91, 85, 102, 80, 95, 110, 84, 90}
+	x := append(append([]float64(nil), before...), after...)
+
+	// Test whether the change reduced the mean response time.
+	n := len(before)
+	diff := func(x, weights []float64) float64 {
+		return stat.Mean(x[:n], nil) - stat.Mean(x[n:], nil)
+	}
+	r := resample.Permutation(x, nil, diff, hypothesis.Greater, &resample.Settings{
+		Replicates: 9999,
+		Src:        rand.NewSource(1),
+	})
+	fmt.Printf("difference = %.2f, p = %.4f\n", r.Statistic, r.P)
+
+	// Output:
+	// difference = 32.25, p = 0.0090
+}
@@ -0,0 +1,99 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat/hypothesis"
+)
+
+// Permutation performs a Monte Carlo permutation test of the null
+// hypothesis that the values of the weighted sample x are exchangeable,
+// using the statistic fn. The statistic of the sample is compared with its
+// values for random permutations of x, with each weight permuted together
+// with its value. The statistic encodes the hypothesis through the
+// positions of the values; for example a two-sample test of location
+// concatenates the samples and compares the mean of the first part of x
+// with that of the rest, and a test of association with a fixed variable
+// correlates x with it. If weights is nil, all weights are one. If
+// settings is nil, the default settings are used.
+//
+// The p-value for the alternative that the statistic is greater than
+// under the null hypothesis is (1+m)/(1+R), where m of the R permuted
+// statistics are at least the statistic of the sample, and similarly for
+// the alternative that it is less. The two-sided p-value is twice the
+// smaller of these, at most one. The result has the statistic of the
+// sample and the p-value, and the other fields are NaN.
+//
+// Permutation will panic if x has fewer than two elements, if weights is
+// not nil and its length does not equal the length of x, or if alt is not
+// a valid alternative.
+//
+// References:
+//   - Phipson, B. and Smyth, G. K. (2010). Permutation p-values should
+//     never be zero: calculating exact p-values when permutations are
+//     randomly drawn. Statistical Applications in Genetics and Molecular
+//     Biology, 9(1), 39.
+func Permutation(x, weights []float64, fn Statistic, alt hypothesis.Alternative, settings *Settings) hypothesis.Result {
+	if len(x) < 2 {
+		panic("resample: too few samples")
+	}
+	if weights != nil && len(weights) != len(x) {
+		panic("resample: slice length mismatch")
+	}
+	if alt < hypothesis.TwoSided || hypothesis.Greater < alt {
+		panic("resample: invalid alternative")
+	}
+	s := settingsOrDefault(settings, 9999)
+	t := fn(x, weights)
+	values := make([]float64, s.Replicates)
+	replicate(s.Replicates, len(x), weights != nil, s, func(i int, src rand.Source, xr, wr []float64) {
+		copy(xr, x)
+		copy(wr, weights)
+		rand.New(src).Shuffle(len(xr), func(i, j int) {
+			xr[i], xr[j] = xr[j], xr[i]
+			if wr != nil {
+				wr[i], wr[j] = wr[j], wr[i]
+			}
+		})
+		values[i] = fn(xr, wr)
+	})
+
+	// Permutations giving the same statistic as the sample
+	// may differ from it by rounding error.
+	tol := 1e-12 * math.Max(1, math.Abs(t))
+	var ge, le float64
+	for _, v := range values {
+		if v >= t-tol {
+			ge++
+		}
+		if v <= t+tol {
+			le++
+		}
+	}
+	r := float64(len(values))
+	upper := (1 + ge) / (1 + r)
+	lower := (1 + le) / (1 + r)
+	var p float64
+	switch alt {
+	case hypothesis.Less:
+		p = lower
+	case hypothesis.Greater:
+		p = upper
+	default:
+		p = math.Min(1, 2*math.Min(lower, upper))
+	}
+	return hypothesis.Result{
+		Statistic: t,
+		DF:        math.NaN(),
+		P:         p,
+		Estimate:  math.NaN(),
+		Lower:     math.NaN(),
+		Upper:     math.NaN(),
+	}
+}
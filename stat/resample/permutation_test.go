@@ -0,0 +1,145 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/combin"
+	"gonum.org/v1/gonum/stat/hypothesis"
+)
+
+func TestPermutation(t *testing.T) {
+	t.Parallel()
+	// Two samples concatenated, with the difference in means
+	// of the first five values and the rest as statistic.
+	x := []float64{4.2, 5.1, 6.3, 5.8, 7.0, 3.9, 4.4, 3.1, 5.0, 4.1}
+	const nx = 5
+	diff := func(x, w []float64) float64 {
+		return stat.Mean(x[:nx], nil) - stat.Mean(x[nx:], nil)
+	}
+
+	// The exact permutation distribution over all splits.
+	obs := diff(x, nil)
+	var ge, le, total float64
+	gen := combin.NewCombinationGenerator(len(x), nx)
+	comb := make([]int, nx)
+	perm := make([]float64, len(x))
+	for gen.Next() {
+		gen.Combination(comb)
+		in := make([]bool, len(x))
+		for _, i := range comb {
+			in[i] = true
+		}
+		k, l := 0, nx
+		for i, v := range x {
+			if in[i] {
+				perm[k] = v
+				k++
+			} else {
+				perm[l] = v
+				l++
+			}
+		}
+		d := diff(perm, nil)
+		if d >= obs-1e-12 {
+			ge++
+		}
+		if d <= obs+1e-12 {
+			le++
+		}
+		total++
+	}
+
+	const reps = 20000
+	settings := &Settings{Replicates: reps, Src: rand.NewSource(1)}
+	for _, test := range []struct {
+		alt  hypothesis.Alternative
+		want float64
+	}{
+		{alt: hypothesis.Greater, want: ge / total},
+		{alt: hypothesis.Less, want: le / total},
+		{alt: hypothesis.TwoSided, want: math.Min(1, 2*math.Min(ge, le)/total)},
+	} {
+		got := Permutation(x, nil, diff, test.alt, settings)
+		if got.Statistic != obs {
+			t.Errorf("unexpected statistic: got:%v want:%v", got.Statistic, obs)
+		}
+		// Allow four standard errors of the Monte Carlo
+		// estimate.
+		tol := 4 * math.Sqrt(test.want*(1-test.want)/reps)
+		if !scalar.EqualWithinAbs(got.P, test.want, tol) {
+			t.Errorf("unexpected p-value for alternative %v: got:%v want:%v", test.alt, got.P, test.want)
+		}
+		if !math.IsNaN(got.DF) || !math.IsNaN(got.Estimate) || !math.IsNaN(got.Lower) || !math.IsNaN(got.Upper) {
+			t.Errorf("unexpected result fields: %+v", got)
+		}
+	}
+}
+
+func TestPermutationWeights(t *testing.T) {
+	t.Parallel()
+	// A test of association between x and a fixed variable y,
+	// with weights permuted with their values.
+	x := []float64{1, 3, 2, 5, 4, 7, 6, 8}
+	y := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	w := []float64{1, 2, 1, 2, 1, 2, 1, 2}
+	corr := func(x, w []float64) float64 { return stat.Correlation(x, y, w) }
+	var want float64
+	for _, concurrent := range []int{1, 3} {
+		got := Permutation(x, w, corr, hypothesis.Greater, &Settings{Replicates: 2000, Src: rand.NewSource(1), Concurrent: concurrent})
+		if got.Statistic != stat.Correlation(x, y, w) {
+			t.Errorf("unexpected statistic: got:%v want:%v", got.Statistic, stat.Correlation(x, y, w))
+		}
+		if want == 0 {
+			want = got.P
+			continue
+		}
+		if got.P != want {
+			t.Errorf("p-value depends on concurrency: got:%v want:%v", got.P, want)
+		}
+	}
+	// The strong association is significant, and the p-value
+	// is never zero.
+	if !(0 < want && want < 0.01) {
+		t.Errorf("unexpected p-value: %v", want)
+	}
+
+	// The weight in the first position is equally likely to
+	// be any of the weights.
+	c := []float64{1, 1, 1, 1}
+	wc := []float64{1, 2, 3, 4}
+	first := func(x, w []float64) float64 { return x[0] * w[0] }
+	got := Permutation(c, wc, first, hypothesis.Less, &Settings{Replicates: 4000, Src: rand.NewSource(1)})
+	if !scalar.EqualWithinAbs(got.P, 0.25, 0.03) {
+		t.Errorf("unexpected p-value for smallest weight first: got:%v want:0.25", got.P)
+	}
+	got = Permutation(c, wc, first, hypothesis.Greater, &Settings{Replicates: 4000, Src: rand.NewSource(1)})
+	if got.P != 1 {
+		t.Errorf("unexpected p-value for smallest weight first: got:%v want:1", got.P)
+	}
+}
+
+func TestPermutationPanics(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2, 3}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "too few", fn: func() { Permutation(x[:1], nil, stat.Mean, hypothesis.TwoSided, nil) }},
+		{name: "weights length", fn: func() { Permutation(x, []float64{1}, stat.Mean, hypothesis.TwoSided, nil) }},
+		{name: "alternative", fn: func() { Permutation(x, nil, stat.Mean, -1, nil) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
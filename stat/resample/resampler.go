@@ -0,0 +1,131 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"golang.org/x/exp/rand"
+)
+
+// Statistic computes a statistic of the sample x with the given weights.
+// If weights is nil, all weights are one. A Statistic must be safe for
+// concurrent use by multiple goroutines.
+type Statistic func(x, weights []float64) float64
+
+// Resampler draws resamples of a weighted sample.
+type Resampler interface {
+	// Resample places in dst a resample of x, and in dstWeights
+	// the corresponding weights, drawing random numbers from src.
+	// The lengths of dst and x are equal. If weights is nil,
+	// dstWeights is nil, and otherwise their lengths equal that
+	// of x.
+	Resample(dst, dstWeights, x, weights []float64, src rand.Source)
+}
+
+// Nonparametric is the nonparametric bootstrap resampler, drawing sample
+// values uniformly with replacement. The weight of each drawn value is
+// drawn with it.
+type Nonparametric struct{}
+
+// Resample places in dst a resample of x drawn with replacement.
+func (Nonparametric) Resample(dst, dstWeights, x, weights []float64, src rand.Source) {
+	rnd := rand.New(src)
+	for i := range dst {
+		j := rnd.Intn(len(x))
+		dst[i] = x[j]
+		if weights != nil {
+			dstWeights[i] = weights[j]
+		}
+	}
+}
+
+// Block is the moving block bootstrap resampler for stationary time
+// series. A resample is built by concatenating blocks of Length
+// consecutive sample values starting at uniformly drawn positions, and
+// truncating the final block, preserving the dependence between nearby
+// values. If Circular is true, blocks wrap around the end of the series,
+// so that every value is equally likely to be drawn.
+//
+// Resample will panic if Length is not positive or, when Circular is
+// false, exceeds the length of x.
+//
+// References:
+//   - Künsch, H. R. (1989). The jackknife and the bootstrap for general
+//     stationary observations. The Annals of Statistics, 17(3), 1217-1241.
+//   - Politis, D. N. and Romano, J. P. (1992). A circular block-resampling
+//     procedure for stationary data. In Exploring the Limits of Bootstrap,
+//     263-270. Wiley.
+type Block struct {
+	Length   int
+	Circular bool
+}
+
+// Resample places in dst a block resample of x.
+func (b Block) Resample(dst, dstWeights, x, weights []float64, src rand.Source) {
+	n := len(x)
+	if b.Length < 1 || (!b.Circular && b.Length > n) {
+		panic("resample: invalid block length")
+	}
+	starts := n - b.Length + 1
+	if b.Circular {
+		starts = n
+	}
+	rnd := rand.New(src)
+	for i := 0; i < len(dst); {
+		s := rnd.Intn(starts)
+		for k := 0; k < b.Length && i < len(dst); k++ {
+			j := (s + k) % n
+			dst[i] = x[j]
+			if weights != nil {
+				dstWeights[i] = weights[j]
+			}
+			i++
+		}
+	}
+}
+
+// Stratified is the stratified bootstrap resampler. Strata holds the
+// stratum of each sample value, and each value of a resample is drawn
+// with replacement from the values of the stratum of the value at the same
+// position, so the resample keeps the positions and sizes of the strata.
+//
+// Resample will panic if the length of Strata does not equal the length
+// of x.
+type Stratified struct {
+	Strata []int
+}
+
+// Resample places in dst a stratified resample of x.
+func (s Stratified) Resample(dst, dstWeights, x, weights []float64, src rand.Source) {
+	if len(s.Strata) != len(x) {
+		panic("resample: strata length mismatch")
+	}
+	members := make(map[int][]int)
+	for i, k := range s.Strata {
+		members[k] = append(members[k], i)
+	}
+	rnd := rand.New(src)
+	for i, k := range s.Strata {
+		m := members[k]
+		j := m[rnd.Intn(len(m))]
+		dst[i] = x[j]
+		if weights != nil {
+			dstWeights[i] = weights[j]
+		}
+	}
+}
+
+// Parametric is the parametric bootstrap resampler. Sample fills dst with
+// values drawn from a model fitted to the sample using src, for example by
+// the Rand method of a distuv distribution with the Src field set to src.
+// The weights of the sample are kept.
+type Parametric struct {
+	Sample func(dst []float64, src rand.Source)
+}
+
+// Resample places in dst a sample drawn from the fitted model.
+func (p Parametric) Resample(dst, dstWeights, x, weights []float64, src rand.Source) {
+	p.Sample(dst, src)
+	copy(dstWeights, weights)
+}
@@ -0,0 +1,137 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestNonparametric(t *testing.T) {
+	t.Parallel()
+	x := []float64{0, 1, 2, 3, 4}
+	w := []float64{10, 11, 12, 13, 14}
+	src := rand.NewSource(1)
+	counts := make([]int, len(x))
+	dst := make([]float64, len(x))
+	dstW := make([]float64, len(x))
+	const n = 20000
+	for i := 0; i < n; i++ {
+		Nonparametric{}.Resample(dst, dstW, x, w, src)
+		for j, v := range dst {
+			if dstW[j] != v+10 {
+				t.Fatalf("weight not drawn with value: value:%v weight:%v", v, dstW[j])
+			}
+			counts[int(v)]++
+		}
+	}
+	for i, c := range counts {
+		if p := float64(c) / (n * 5); math.Abs(p-0.2) > 0.01 {
+			t.Errorf("unexpected frequency of value %d: got:%v want:0.2", i, p)
+		}
+	}
+}
+
+func TestBlock(t *testing.T) {
+	t.Parallel()
+	const n = 11
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = float64(i)
+	}
+	src := rand.NewSource(1)
+	dst := make([]float64, n)
+	for _, circular := range []bool{false, true} {
+		for _, l := range []int{1, 3, 4, n} {
+			b := Block{Length: l, Circular: circular}
+			counts := make([]int, n)
+			for k := 0; k < 2000; k++ {
+				b.Resample(dst, nil, x, nil, src)
+				// Values are consecutive within blocks.
+				for i := 0; i < n; i += l {
+					for j := i + 1; j < i+l && j < n; j++ {
+						want := math.Mod(dst[j-1]+1, n)
+						if dst[j] != want || (!circular && dst[j] == 0) {
+							t.Fatalf("unexpected block for length %d circular=%t: %v", l, circular, dst)
+						}
+					}
+				}
+				for _, v := range dst {
+					counts[int(v)]++
+				}
+			}
+			if circular {
+				// Every value is equally likely.
+				for i, c := range counts {
+					if p := float64(c) / (2000 * n); math.Abs(p-1.0/n) > 0.015 {
+						t.Errorf("unexpected frequency of value %d for length %d: got:%v want:%v", i, l, p, 1.0/n)
+					}
+				}
+			}
+		}
+	}
+	if !panics(func() { Block{Length: n + 1}.Resample(dst, nil, x, nil, src) }) {
+		t.Errorf("expected panic for block longer than sample")
+	}
+}
+
+func TestStratified(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2, 10, 20, 30, 3, 100}
+	strata := []int{0, 0, 1, 1, 1, 0, 2}
+	s := Stratified{Strata: strata}
+	src := rand.NewSource(1)
+	dst := make([]float64, len(x))
+	for k := 0; k < 100; k++ {
+		s.Resample(dst, nil, x, nil, src)
+		for i, v := range dst {
+			var stratum int
+			switch {
+			case v < 10:
+				stratum = 0
+			case v < 100:
+				stratum = 1
+			default:
+				stratum = 2
+			}
+			if stratum != strata[i] {
+				t.Fatalf("value drawn from wrong stratum: %v", dst)
+			}
+		}
+	}
+	if !panics(func() { Stratified{Strata: strata[1:]}.Resample(dst, nil, x, nil, src) }) {
+		t.Errorf("expected panic for strata length mismatch")
+	}
+}
+
+func TestParametric(t *testing.T) {
+	t.Parallel()
+	p := Parametric{Sample: func(dst []float64, src rand.Source) {
+		rnd := rand.New(src)
+		for i := range dst {
+			dst[i] = 5 + rnd.NormFloat64()
+		}
+	}}
+	w := []float64{1, 2, 3}
+	dst := make([]float64, 3)
+	dstW := make([]float64, 3)
+	p.Resample(dst, dstW, []float64{0, 0, 0}, w, rand.NewSource(1))
+	for i, v := range dst {
+		if v == 0 || dstW[i] != w[i] {
+			t.Errorf("unexpected parametric resample: %v weights:%v", dst, dstW)
+			break
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return panicked
+}
@@ -0,0 +1,400 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package survival
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Distribution specifies the distribution of the event times of an
+// accelerated failure time model.
+type Distribution int
+
+const (
+	// Weibull specifies Weibull distributed event times, so that
+	// the errors of the log times have the extreme value
+	// distribution with scale σ, and the event times have the
+	// distribution distuv.Weibull{K: 1/σ, Lambda: exp(xᵀβ)}.
+	Weibull Distribution = iota
+	// Exponential specifies exponentially distributed event times,
+	// the Weibull distribution with σ fixed at one, so that the
+	// event times have the distribution
+	// distuv.Exponential{Rate: exp(-xᵀβ)}.
+	Exponential
+)
+
+// AFT is a parametric accelerated failure time regression model, in which
+// the logarithm of the event time of an observation with covariates x is
+//
+//	log T = β₀ + xᵀβ + σ ε,
+//
+// where ε has the standard minimum extreme value distribution, so that
+// exp(βⱼ) is the factor by which the event times are multiplied for
+// observations that differ by one in the jth covariate. The Weibull
+// accelerated failure time model is also a proportional hazards model,
+// with the coefficients of the hazard ratios equal to -βⱼ/σ. The
+// coefficients and σ are estimated by maximum likelihood. The fields of
+// AFT specify the model and how it is fitted, and the results of the fit
+// are only valid if the call to Fit was successful.
+//
+// References:
+//   - Kalbfleisch, J. D. and Prentice, R. L. (2002). The Statistical
+//     Analysis of Failure Time Data, 2nd edition. Wiley.
+type AFT struct {
+	// Distribution is the distribution of the event times.
+	Distribution Distribution
+
+	// Method is the optimization method used to maximize the
+	// likelihood. If Method is nil, optimize.Newton is used with
+	// a gradient threshold of 1e-6 times the sum of the weights.
+	// The Hessian provided to the method is the observed
+	// information of the likelihood.
+	Method optimize.Method
+
+	// Settings are the settings of the optimization. If Settings
+	// is nil, the default settings are used.
+	Settings *optimize.Settings
+
+	// p is the number of coefficients and q is the number
+	// of parameters, including the log scale of the Weibull
+	// distribution.
+	p, q  int
+	coef  []float64
+	scale float64
+	// cov is the inverse of the observed information.
+	cov *mat.SymDense
+
+	loglik     float64
+	iterations int
+
+	ok bool
+}
+
+// Fit fits the accelerated failure time model to the right censored times
+// with the n×k design matrix of covariates x. The model has an intercept,
+// which is the first coefficient of the fitted model. The event of
+// observation i was observed at times[i] if events[i] is true, and the
+// observation was censored at times[i] otherwise.
+//
+// The weights slice is used to weight the observations. If weights is nil,
+// each weight is considered to have a value of one, otherwise the length of
+// weights must match the number of observations or Fit will panic. Fit will
+// also panic if the lengths of times and events do not match the number of
+// observations, if any time with non-zero weight is not positive, if any
+// weight is negative or if Distribution is not valid.
+//
+// Fit returns an error if there are no events, if the information matrix
+// at the estimate is singular or if the optimization does not converge.
+func (m *AFT) Fit(x mat.Matrix, times []float64, events []bool, weights []float64) error {
+	if m.Distribution < Weibull || Exponential < m.Distribution {
+		panic("survival: invalid distribution")
+	}
+	n, k := x.Dims()
+	if len(times) != n {
+		panic("survival: len(times) != observations")
+	}
+	checkSample(times, events, weights)
+	for i, t := range times {
+		if !(t > 0) && weight(weights, i) != 0 {
+			panic("survival: non-positive time")
+		}
+	}
+	m.ok = false
+
+	p := k + 1
+	q := p
+	if m.Distribution == Weibull {
+		q++
+	}
+	a := &aftModel{
+		x:            x,
+		times:        times,
+		events:       events,
+		weights:      weights,
+		p:            p,
+		distribution: m.Distribution,
+	}
+
+	var sumw, sumt, d float64
+	for i, t := range times {
+		w := weight(weights, i)
+		sumw += w
+		sumt += w * t
+		if events[i] {
+			d += w
+		}
+	}
+	if d == 0 {
+		return errors.New("survival: no events")
+	}
+
+	info := mat.NewSymDense(q, nil)
+	problem := optimize.Problem{
+		Func: func(theta []float64) float64 {
+			return -a.logLikelihood(theta, nil, nil)
+		},
+		Grad: func(grad, theta []float64) {
+			a.logLikelihood(theta, grad, nil)
+			for j := range grad {
+				grad[j] = -grad[j]
+			}
+		},
+		Hess: func(hess *mat.SymDense, theta []float64) {
+			a.logLikelihood(theta, nil, hess)
+		},
+	}
+	method := m.Method
+	if method == nil {
+		// The gradient of the likelihood is a sum over the
+		// observations, so the threshold is scaled by the
+		// total weight.
+		method = &optimize.Newton{GradStopThreshold: 1e-6 * sumw}
+	}
+	// The optimization starts from the exponential model
+	// without covariates, which has a closed form estimate.
+	x0 := make([]float64, q)
+	x0[0] = math.Log(sumt / d)
+	res, err := optimize.Minimize(problem, x0, m.Settings, method)
+	if err != nil {
+		return err
+	}
+
+	m.loglik = a.logLikelihood(res.X, nil, info)
+	var chol mat.Cholesky
+	if !chol.Factorize(info) {
+		return errors.New("survival: singular information matrix")
+	}
+	cov := mat.NewSymDense(q, nil)
+	err = chol.InverseTo(cov)
+	if err != nil {
+		return err
+	}
+
+	m.p = p
+	m.q = q
+	m.coef = append(m.coef[:0], res.X[:p]...)
+	m.scale = 1
+	if m.Distribution == Weibull {
+		m.scale = math.Exp(res.X[p])
+	}
+	m.cov = cov
+	m.iterations = res.MajorIterations
+	m.ok = true
+	return nil
+}
+
+// aftModel holds the data of an accelerated failure time model.
+type aftModel struct {
+	x            mat.Matrix
+	times        []float64
+	events       []bool
+	weights      []float64
+	p            int
+	distribution Distribution
+}
+
+// logLikelihood returns the log likelihood at the parameters theta, the
+// coefficients followed by the log scale for the Weibull distribution. If
+// grad is not nil, the gradient of the log likelihood is stored in grad,
+// and if info is not nil, the observed information, the negative of the
+// Hessian of the log likelihood, is stored in info.
+func (a *aftModel) logLikelihood(theta, grad []float64, info *mat.SymDense) float64 {
+	p := a.p
+	weibull := a.distribution == Weibull
+	sigma := 1.0
+	if weibull {
+		sigma = math.Exp(theta[p])
+	}
+	if grad != nil {
+		for j := range grad {
+			grad[j] = 0
+		}
+	}
+	if info != nil {
+		info.Zero()
+	}
+
+	q := len(theta)
+	row := make([]float64, p)
+	var ll float64
+	for i, t := range a.times {
+		w := weight(a.weights, i)
+		if w == 0 {
+			continue
+		}
+		row[0] = 1
+		eta := theta[0]
+		for j := 1; j < p; j++ {
+			row[j] = a.x.At(i, j-1)
+			eta += theta[j] * row[j]
+		}
+
+		if weibull {
+			dist := distuv.Weibull{K: 1 / sigma, Lambda: math.Exp(eta)}
+			if a.events[i] {
+				ll += w * dist.LogProb(t)
+			} else {
+				ll += w * dist.LogSurvival(t)
+			}
+		} else {
+			dist := distuv.Exponential{Rate: math.Exp(-eta)}
+			if a.events[i] {
+				ll += w * dist.LogProb(t)
+			} else {
+				ll += w * -dist.Rate * t
+			}
+		}
+		if grad == nil && info == nil {
+			continue
+		}
+
+		// The derivatives are with respect to the linear
+		// predictor η and the log scale s of the standardized
+		// log time z = (log t - η)/σ.
+		var delta float64
+		if a.events[i] {
+			delta = 1
+		}
+		z := (math.Log(t) - eta) / sigma
+		ez := math.Exp(z)
+		if grad != nil {
+			dEta := (ez - delta) / sigma
+			for j, v := range row {
+				grad[j] += w * dEta * v
+			}
+			if weibull {
+				grad[p] += w * (z*(ez-delta) - delta)
+			}
+		}
+		if info != nil {
+			etaEta := ez / (sigma * sigma)
+			for j := 0; j < p; j++ {
+				for k := j; k < p; k++ {
+					info.SetSym(j, k, info.At(j, k)+w*etaEta*row[j]*row[k])
+				}
+			}
+			if weibull {
+				etaS := (z*ez + ez - delta) / sigma
+				for j := 0; j < p; j++ {
+					info.SetSym(j, q-1, info.At(j, q-1)+w*etaS*row[j])
+				}
+				sS := z*(ez-delta) + z*z*ez
+				info.SetSym(q-1, q-1, info.At(q-1, q-1)+w*sS)
+			}
+		}
+	}
+	return ll
+}
+
+func (m *AFT) checkOK() {
+	if !m.ok {
+		panic("survival: use of unsuccessful fit")
+	}
+}
+
+// Coefficients returns the estimated coefficients of the model. The
+// intercept is the first coefficient.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients,
+// Coefficients will panic. Coefficients will also panic if the receiver
+// does not contain a successful fit.
+func (m *AFT) Coefficients(dst []float64) []float64 {
+	m.checkOK()
+	return copySlice(dst, m.coef)
+}
+
+// Scale returns the estimated scale σ of the errors of the log times of
+// the model. Scale returns one for the Exponential distribution. Scale will
+// panic if the receiver does not contain a successful fit.
+func (m *AFT) Scale() float64 {
+	m.checkOK()
+	return m.scale
+}
+
+// LogLikelihood returns the log likelihood of the fitted model.
+// LogLikelihood will panic if the receiver does not contain a successful
+// fit.
+func (m *AFT) LogLikelihood() float64 {
+	m.checkOK()
+	return m.loglik
+}
+
+// Iterations returns the number of major iterations of the optimization
+// used by the fit. Iterations will panic if the receiver does not contain
+// a successful fit.
+func (m *AFT) Iterations() int {
+	m.checkOK()
+	return m.iterations
+}
+
+// CovarianceMatrix stores the estimate of the covariance matrix of the
+// parameters of the model, the inverse of the observed information, into
+// dst. The parameters are the coefficients followed, for the Weibull
+// distribution, by the logarithm of the scale.
+//
+// The dst matrix must either be empty or have the same number of rows as
+// the number of parameters. CovarianceMatrix will panic if the receiver
+// does not contain a successful fit.
+func (m *AFT) CovarianceMatrix(dst *mat.SymDense) {
+	m.checkOK()
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(m.q)
+	} else if dst.SymmetricDim() != m.q {
+		panic(mat.ErrShape)
+	}
+	dst.CopySym(m.cov)
+}
+
+// StdErrs returns the standard errors of the coefficients of the model.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients, StdErrs
+// will panic. StdErrs will also panic if the receiver does not contain a
+// successful fit.
+func (m *AFT) StdErrs(dst []float64) []float64 {
+	m.checkOK()
+	dst = useSlice(dst, m.p)
+	for i := range dst {
+		dst[i] = math.Sqrt(m.cov.At(i, i))
+	}
+	return dst
+}
+
+// WaldStats returns the Wald statistics of the coefficients of the model,
+// the ratios of the coefficients to their standard errors.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients,
+// WaldStats will panic. WaldStats will also panic if the receiver does not
+// contain a successful fit.
+func (m *AFT) WaldStats(dst []float64) []float64 {
+	dst = m.StdErrs(dst)
+	for i, se := range dst {
+		dst[i] = m.coef[i] / se
+	}
+	return dst
+}
+
+// PValues returns the two-sided p-values of the Wald tests of the null
+// hypotheses that each coefficient of the model is zero, using the standard
+// normal distribution of the Wald statistics.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients, PValues
+// will panic. PValues will also panic if the receiver does not contain a
+// successful fit.
+func (m *AFT) PValues(dst []float64) []float64 {
+	dst = m.WaldStats(dst)
+	for i, v := range dst {
+		dst[i] = 2 * distuv.UnitNormal.Survival(math.Abs(v))
+	}
+	return dst
+}
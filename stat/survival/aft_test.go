@@ -0,0 +1,266 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package survival
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestAFTExponential(t *testing.T) {
+	t.Parallel()
+	// With a single binary covariate the exponential model has
+	// the closed form estimates of the rates dᵍ/Tᵍ, where dᵍ is
+	// the number of events and Tᵍ is the total time in group g.
+	x := mat.NewDense(len(aml.group), 1, nil)
+	var d, total [2]float64
+	for i, g := range aml.group {
+		x.Set(i, 0, float64(g))
+		total[g] += aml.times[i]
+		if aml.events[i] {
+			d[g]++
+		}
+	}
+	m := AFT{Distribution: Exponential}
+	err := m.Fit(x, aml.times, aml.events, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantCoef := []float64{
+		math.Log(total[0] / d[0]),
+		math.Log(total[1]/d[1]) - math.Log(total[0]/d[0]),
+	}
+	wantSE := []float64{
+		math.Sqrt(1 / d[0]),
+		math.Sqrt(1/d[0] + 1/d[1]),
+	}
+	if got := m.Coefficients(nil); !floats.EqualApprox(got, wantCoef, 1e-8) {
+		t.Errorf("unexpected coefficients: got:%v want:%v", got, wantCoef)
+	}
+	if got := m.StdErrs(nil); !floats.EqualApprox(got, wantSE, 1e-8) {
+		t.Errorf("unexpected standard errors: got:%v want:%v", got, wantSE)
+	}
+	if got := m.Scale(); got != 1 {
+		t.Errorf("unexpected scale: got:%v want:1", got)
+	}
+	var ll float64
+	for g := range d {
+		rate := d[g] / total[g]
+		ll += d[g]*math.Log(rate) - rate*total[g]
+	}
+	if got := m.LogLikelihood(); !scalar.EqualWithinAbsOrRel(got, ll, 1e-10, 1e-10) {
+		t.Errorf("unexpected log likelihood: got:%v want:%v", got, ll)
+	}
+	var cov mat.SymDense
+	m.CovarianceMatrix(&cov)
+	if r := cov.SymmetricDim(); r != 2 {
+		t.Errorf("unexpected covariance matrix dimension: got:%d want:2", r)
+	}
+
+	// The Weibull model nests the exponential model.
+	var w AFT
+	err = w.Fit(x, aml.times, aml.events, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w.LogLikelihood() < m.LogLikelihood() {
+		t.Errorf("Weibull log likelihood less than exponential: got:%v exponential:%v", w.LogLikelihood(), m.LogLikelihood())
+	}
+	var wcov mat.SymDense
+	w.CovarianceMatrix(&wcov)
+	if r := wcov.SymmetricDim(); r != 3 {
+		t.Errorf("unexpected covariance matrix dimension: got:%d want:3", r)
+	}
+}
+
+func TestAFTAML(t *testing.T) {
+	t.Parallel()
+	// Reference values from survreg in R with the nonmaintained
+	// group as the covariate.
+	x := mat.NewDense(len(aml.group), 1, nil)
+	for i, g := range aml.group {
+		x.Set(i, 0, float64(g))
+	}
+	var m AFT
+	err := m.Fit(x, aml.times, aml.events, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := m.Coefficients(nil), []float64{4.109, -0.929}; !floats.EqualApprox(got, want, 5e-4) {
+		t.Errorf("unexpected coefficients: got:%v want:%v", got, want)
+	}
+	if got, want := m.StdErrs(nil), []float64{0.300, 0.383}; !floats.EqualApprox(got, want, 5e-4) {
+		t.Errorf("unexpected standard errors: got:%v want:%v", got, want)
+	}
+	if got := m.Scale(); !scalar.EqualWithinAbs(got, 0.791, 5e-4) {
+		t.Errorf("unexpected scale: got:%v want:0.791", got)
+	}
+	if got := m.LogLikelihood(); !scalar.EqualWithinAbs(got, -80.5, 0.05) {
+		t.Errorf("unexpected log likelihood: got:%v want:-80.5", got)
+	}
+}
+
+// aftData returns a sample from a Weibull accelerated failure time model
+// with random censoring.
+func aftData(rnd *rand.Rand, n int, beta []float64, sigma float64) (x *mat.Dense, times []float64, events []bool) {
+	k := len(beta) - 1
+	x = mat.NewDense(n, k, nil)
+	times = make([]float64, n)
+	events = make([]bool, n)
+	for i := 0; i < n; i++ {
+		eta := beta[0]
+		for j := 0; j < k; j++ {
+			v := rnd.NormFloat64()
+			x.Set(i, j, v)
+			eta += beta[j+1] * v
+		}
+		// The log of a unit exponential variable has the
+		// standard minimum extreme value distribution.
+		tt := math.Exp(eta + sigma*math.Log(rnd.ExpFloat64()))
+		c := 3 * math.Exp(beta[0]) * rnd.ExpFloat64()
+		times[i] = math.Min(tt, c)
+		events[i] = tt <= c
+	}
+	return x, times, events
+}
+
+func TestAFTDerivatives(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, times, events := aftData(rnd, 40, []float64{1, 0.5, -0.5}, 0.7)
+	weights := make([]float64, len(times))
+	for i := range weights {
+		weights[i] = rnd.Float64()
+	}
+	for _, dist := range []Distribution{Weibull, Exponential} {
+		a := &aftModel{x: x, times: times, events: events, weights: weights, p: 3, distribution: dist}
+		q := 3
+		if dist == Weibull {
+			q++
+		}
+		for trial := 0; trial < 5; trial++ {
+			theta := make([]float64, q)
+			for j := range theta {
+				theta[j] = 0.5 * rnd.NormFloat64()
+			}
+			grad := make([]float64, q)
+			info := mat.NewSymDense(q, nil)
+			a.logLikelihood(theta, grad, info)
+			want := fd.Gradient(nil, func(theta []float64) float64 {
+				return a.logLikelihood(theta, nil, nil)
+			}, theta, &fd.Settings{Formula: fd.Central})
+			if !floats.EqualApprox(grad, want, 1e-6) {
+				t.Errorf("unexpected gradient for distribution %d: got:%v want:%v", dist, grad, want)
+			}
+			hess := mat.NewDense(q, q, nil)
+			fd.Jacobian(hess, func(dst, theta []float64) {
+				a.logLikelihood(theta, dst, nil)
+				floats.Scale(-1, dst)
+			}, theta, &fd.JacobianSettings{Formula: fd.Central})
+			if !mat.EqualApprox(info, hess, 1e-5) {
+				t.Errorf("unexpected information for distribution %d:\ngot: %v\nwant:%v",
+					dist, mat.Formatted(info), mat.Formatted(hess))
+			}
+		}
+	}
+}
+
+func TestAFTWeibull(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	beta := []float64{1, 0.5, -0.5}
+	const sigma = 0.7
+	x, times, events := aftData(rnd, 2000, beta, sigma)
+
+	var m AFT
+	err := m.Fit(x, times, events, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	coef := m.Coefficients(nil)
+	se := m.StdErrs(nil)
+	for j, b := range beta {
+		if math.Abs(coef[j]-b) > 4*se[j] {
+			t.Errorf("unexpected coefficient %d: got:%v±%v want:%v", j, coef[j], se[j], b)
+		}
+	}
+	var cov mat.SymDense
+	m.CovarianceMatrix(&cov)
+	if s := math.Sqrt(cov.At(3, 3)); math.Abs(math.Log(m.Scale())-math.Log(sigma)) > 4*s {
+		t.Errorf("unexpected scale: got:%v want:%v", m.Scale(), sigma)
+	}
+
+	// The Weibull model is a proportional hazards model with
+	// coefficients -β/σ, which the Cox model estimates without
+	// the parametric baseline hazard.
+	var c Cox
+	err = c.Fit(x, times, events, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hr := c.Coefficients(nil)
+	cse := c.StdErrs(nil)
+	for j, v := range hr {
+		want := -coef[j+1] / m.Scale()
+		if math.Abs(v-want) > cse[j] {
+			t.Errorf("unexpected Cox coefficient %d: got:%v±%v want:%v", j, v, cse[j], want)
+		}
+	}
+
+	// Scaling the times shifts the intercept.
+	scaled := make([]float64, len(times))
+	floats.ScaleTo(scaled, 10, times)
+	var s AFT
+	err = s.Fit(x, scaled, events, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := m.Coefficients(nil)
+	want[0] += math.Log(10)
+	if got := s.Coefficients(nil); !floats.EqualApprox(got, want, 1e-6) {
+		t.Errorf("unexpected coefficients for scaled times: got:%v want:%v", got, want)
+	}
+	if !scalar.EqualWithinAbsOrRel(s.Scale(), m.Scale(), 1e-6, 1e-6) {
+		t.Errorf("unexpected scale for scaled times: got:%v want:%v", s.Scale(), m.Scale())
+	}
+}
+
+func TestAFTErrors(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(4, 1, []float64{1, 2, 3, 4})
+	var m AFT
+	err := m.Fit(x, []float64{1, 2, 3, 4}, []bool{false, false, false, false}, nil)
+	if err == nil {
+		t.Error("expected error for no events")
+	}
+	if !panics(func() { m.Scale() }) {
+		t.Error("expected panic for use of unsuccessful fit")
+	}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "distribution", fn: func() {
+			(&AFT{Distribution: Exponential + 1}).Fit(x, []float64{1, 2, 3, 4}, make([]bool, 4), nil)
+		}},
+		{name: "times length", fn: func() { m.Fit(x, make([]float64, 3), make([]bool, 3), nil) }},
+		{name: "non-positive time", fn: func() { m.Fit(x, []float64{1, 0, 3, 4}, make([]bool, 4), nil) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+	// A zero time is allowed with zero weight.
+	if panics(func() { m.Fit(x, []float64{1, 0, 3, 4}, []bool{true, true, true, false}, []float64{1, 0, 1, 1}) }) {
+		t.Error("unexpected panic for zero time with zero weight")
+	}
+}
@@ -0,0 +1,548 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package survival
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Ties specifies the approximation to the partial likelihood of the Cox
+// model used for tied event times.
+type Ties int
+
+const (
+	// Efron uses Efron's approximation, in which the sum of the
+	// risks of the observations with tied events is removed from
+	// the risk set in equal parts for each of the events.
+	Efron Ties = iota
+	// Breslow uses Breslow's approximation, in which each of the
+	// tied events has the full risk set.
+	Breslow
+)
+
+// Cox is the Cox proportional hazards regression model, in which the
+// hazard function of an observation with covariates x is
+//
+//	h(t | x) = h₀(t) exp(xᵀβ),
+//
+// for an unspecified baseline hazard function h₀. The coefficients β are
+// estimated by maximizing the partial likelihood, and exp(βⱼ) is the ratio
+// of the hazards of observations that differ by one in the jth covariate.
+// The fields of Cox specify how the model is fitted, and the results of
+// the fit are only valid if the call to Fit was successful.
+//
+// References:
+//   - Cox, D. R. (1972). Regression models and life-tables. Journal of the
+//     Royal Statistical Society, Series B, 34(2), 187-220.
+//   - Efron, B. (1977). The efficiency of Cox's likelihood function for
+//     censored data. Journal of the American Statistical Association,
+//     72(359), 557-565.
+type Cox struct {
+	// Ties is the approximation used for tied event times.
+	Ties Ties
+
+	// Method is the optimization method used to maximize the
+	// partial likelihood. If Method is nil, optimize.Newton is
+	// used with a gradient threshold of 1e-6 times the weighted
+	// number of events. The Hessian provided to the method is
+	// the observed information of the partial likelihood. The
+	// parameters of the optimization are the coefficients
+	// multiplied by the weighted mean absolute deviations of
+	// the covariates.
+	Method optimize.Method
+
+	// Settings are the settings of the optimization. If Settings
+	// is nil, the default settings are used.
+	Settings *optimize.Settings
+
+	p    int
+	coef []float64
+	// cov is the inverse of the observed information.
+	cov *mat.SymDense
+
+	loglik, nullLoglik float64
+	iterations         int
+
+	ok bool
+}
+
+// Fit fits the Cox proportional hazards model to the right censored times
+// with the n×p design matrix of covariates x. The event of observation i
+// was observed at times[i] if events[i] is true, and the observation was
+// censored at times[i] otherwise. The model has no intercept, since it
+// would be absorbed by the baseline hazard.
+//
+// The weights slice is used to weight the observations. If weights is nil,
+// each weight is considered to have a value of one, otherwise the length of
+// weights must match the number of observations or Fit will panic. Fit will
+// also panic if the lengths of times and events do not match the number of
+// observations, if any time is NaN, if any weight is negative or if Ties is
+// not valid.
+//
+// Fit returns an error if there are no events, if the information matrix
+// at the estimate is singular, if the optimization does not converge or if
+// the partial likelihood has no maximum, which happens when a combination
+// of the covariates orders the event times perfectly.
+func (c *Cox) Fit(x mat.Matrix, times []float64, events []bool, weights []float64) error {
+	if c.Ties < Efron || Breslow < c.Ties {
+		panic("survival: invalid ties")
+	}
+	n, p := x.Dims()
+	if len(times) != n {
+		panic("survival: len(times) != observations")
+	}
+	checkSample(times, events, weights)
+	c.ok = false
+
+	m := newCoxModel(x, times, events, weights, c.Ties)
+	if m.events == 0 {
+		return errors.New("survival: no events")
+	}
+
+	// The optimization is over the coefficients of the standardized
+	// covariates, θⱼ = sⱼβⱼ, so that the gradient threshold does not
+	// depend on the scale of the covariates.
+	beta := make([]float64, p)
+	toBeta := func(theta []float64) []float64 {
+		for j, v := range theta {
+			beta[j] = v / m.scale[j]
+		}
+		return beta
+	}
+	problem := optimize.Problem{
+		Func: func(theta []float64) float64 {
+			return -m.logLikelihood(toBeta(theta), nil, nil)
+		},
+		Grad: func(grad, theta []float64) {
+			m.logLikelihood(toBeta(theta), grad, nil)
+			for j, s := range m.scale {
+				grad[j] = -grad[j] / s
+			}
+		},
+		Hess: func(hess *mat.SymDense, theta []float64) {
+			m.logLikelihood(toBeta(theta), nil, hess)
+			for j, sj := range m.scale {
+				for k := j; k < p; k++ {
+					hess.SetSym(j, k, hess.At(j, k)/(sj*m.scale[k]))
+				}
+			}
+		},
+	}
+	method := c.Method
+	if method == nil {
+		// The gradient of the partial likelihood is a sum
+		// over the events, so the threshold is scaled by the
+		// number of events. Smaller gradients may not be
+		// reachable before the changes in the partial
+		// likelihood are lost to rounding.
+		method = &optimize.Newton{GradStopThreshold: 1e-6 * m.events}
+	}
+	x0 := make([]float64, p)
+	res, err := optimize.Minimize(problem, x0, c.Settings, method)
+	if err != nil {
+		return err
+	}
+
+	coef := toBeta(res.X)
+	info := mat.NewSymDense(p, nil)
+	c.loglik = m.logLikelihood(coef, nil, info)
+	var chol mat.Cholesky
+	if !chol.Factorize(info) {
+		return errors.New("survival: singular information matrix")
+	}
+	cov := mat.NewSymDense(p, nil)
+	err = chol.InverseTo(cov)
+	if err != nil {
+		return err
+	}
+	// The partial likelihood has no maximum if it increases towards
+	// an asymptote along some direction of the coefficients, and the
+	// information in that direction vanishes as the optimization
+	// follows it.
+	null := mat.NewSymDense(p, nil)
+	c.nullLoglik = m.logLikelihood(x0, nil, null)
+	if minInfoRatio(info, null) < coxSeparationTol {
+		return errors.New("survival: partial likelihood has no maximum")
+	}
+
+	c.p = p
+	c.coef = append(c.coef[:0], coef...)
+	c.cov = cov
+	c.iterations = res.MajorIterations
+	c.ok = true
+	return nil
+}
+
+// coxSeparationTol is the smallest ratio of the information at the
+// estimate to the information at zero, in any direction of the
+// coefficients, for which the partial likelihood is considered to have
+// a maximum.
+const coxSeparationTol = 1e-4
+
+// minInfoRatio returns the smallest ratio vᵀ info v / vᵀ null v over the
+// non-zero vectors v, the smallest eigenvalue of null⁻¹ info. If null is
+// not positive definite, minInfoRatio returns zero.
+func minInfoRatio(info, null *mat.SymDense) float64 {
+	var chol mat.Cholesky
+	if !chol.Factorize(null) {
+		return 0
+	}
+	// The eigenvalues of null⁻¹ info are those of L⁻¹ info L⁻ᵀ, where
+	// null = L Lᵀ.
+	var l mat.TriDense
+	chol.LTo(&l)
+	var tmp, m mat.Dense
+	err := tmp.Solve(&l, info)
+	if err != nil {
+		return 0
+	}
+	err = m.Solve(&l, tmp.T())
+	if err != nil {
+		return 0
+	}
+	p := info.SymmetricDim()
+	sym := mat.NewSymDense(p, nil)
+	for i := 0; i < p; i++ {
+		for j := i; j < p; j++ {
+			sym.SetSym(i, j, (m.At(i, j)+m.At(j, i))/2)
+		}
+	}
+	var eig mat.EigenSym
+	if !eig.Factorize(sym, false) {
+		return 0
+	}
+	return eig.Values(nil)[0]
+}
+
+// coxModel holds the data of a Cox model in decreasing order of time.
+type coxModel struct {
+	n, p int
+	// x is the design matrix with the weighted means
+	// of the columns subtracted.
+	x      *mat.Dense
+	w      []float64
+	event  []bool
+	starts []int // starts of the groups of tied times
+	ties   Ties
+
+	// events is the weighted number of events.
+	events float64
+	// scale holds the weighted mean absolute values
+	// of the columns of x, or one for columns of zeros.
+	scale []float64
+
+	eta []float64
+}
+
+// newCoxModel returns the Cox model of the data with the given
+// approximation for tied event times.
+func newCoxModel(x mat.Matrix, times []float64, events []bool, weights []float64, ties Ties) *coxModel {
+	n, p := x.Dims()
+	idx := sortedIndices(times)
+	m := &coxModel{
+		n:     n,
+		p:     p,
+		x:     mat.NewDense(n, p, nil),
+		w:     make([]float64, n),
+		event: make([]bool, n),
+		ties:  ties,
+		eta:   make([]float64, n),
+	}
+	var sumw float64
+	for i := range idx {
+		k := idx[n-1-i]
+		if i == 0 || times[k] != times[idx[n-i]] {
+			m.starts = append(m.starts, i)
+		}
+		m.w[i] = weight(weights, k)
+		m.event[i] = events[k]
+		if events[k] {
+			m.events += m.w[i]
+		}
+		sumw += m.w[i]
+		for j := 0; j < p; j++ {
+			m.x.Set(i, j, x.At(k, j))
+		}
+	}
+	m.starts = append(m.starts, n)
+
+	// Centering the covariates does not change the
+	// partial likelihood but reduces the range of the
+	// risks.
+	m.scale = make([]float64, p)
+	for j := range m.scale {
+		m.scale[j] = 1
+	}
+	if sumw > 0 {
+		for j := 0; j < p; j++ {
+			var mean float64
+			for i, w := range m.w {
+				mean += w * m.x.At(i, j)
+			}
+			mean /= sumw
+			var dev float64
+			for i, w := range m.w {
+				v := m.x.At(i, j) - mean
+				m.x.Set(i, j, v)
+				dev += w * math.Abs(v)
+			}
+			if dev > 0 {
+				m.scale[j] = dev / sumw
+			}
+		}
+	}
+	return m
+}
+
+// logLikelihood returns the log partial likelihood at beta. If grad is
+// not nil, the gradient of the log partial likelihood is stored in grad,
+// and if info is not nil, the observed information, the negative of the
+// Hessian of the log partial likelihood, is stored in info.
+func (m *coxModel) logLikelihood(beta, grad []float64, info *mat.SymDense) float64 {
+	p := m.p
+	eta := mat.NewVecDense(m.n, m.eta)
+	eta.MulVec(m.x, mat.NewVecDense(p, beta))
+
+	// The partial likelihood is unchanged by adding a
+	// constant to the linear predictors, so the largest
+	// is subtracted to avoid overflow of the risks.
+	shift := math.Inf(-1)
+	for i, v := range m.eta {
+		if m.w[i] != 0 {
+			shift = math.Max(shift, v)
+		}
+	}
+
+	if grad != nil {
+		for j := range grad {
+			grad[j] = 0
+		}
+	}
+	if info != nil {
+		info.Zero()
+	}
+
+	// s0, s1 and s2 are the sums of the risks over the
+	// risk set, and a0, a1 and a2 over the tied events,
+	// weighted by one, the covariates and the outer
+	// products of the covariates.
+	var s0, a0 float64
+	s1 := make([]float64, p)
+	a1 := make([]float64, p)
+	var s2, a2 []float64
+	if info != nil {
+		s2 = make([]float64, p*p)
+		a2 = make([]float64, p*p)
+	}
+	z := make([]float64, p)
+
+	var ll float64
+	for g := 0; g < len(m.starts)-1; g++ {
+		a0 = 0
+		for j := range a1 {
+			a1[j] = 0
+		}
+		for j := range a2 {
+			a2[j] = 0
+		}
+		var (
+			dw    float64
+			count int
+		)
+		for i := m.starts[g]; i < m.starts[g+1]; i++ {
+			w := m.w[i]
+			if w == 0 {
+				continue
+			}
+			r := w * math.Exp(m.eta[i]-shift)
+			row := m.x.RawRowView(i)
+			s0 += r
+			for j, v := range row {
+				s1[j] += r * v
+			}
+			for j := range s2 {
+				s2[j] += r * row[j/p] * row[j%p]
+			}
+			if !m.event[i] {
+				continue
+			}
+			count++
+			dw += w
+			ll += w * (m.eta[i] - shift)
+			a0 += r
+			for j, v := range row {
+				a1[j] += r * v
+				if grad != nil {
+					grad[j] += w * v
+				}
+			}
+			for j := range a2 {
+				a2[j] += r * row[j/p] * row[j%p]
+			}
+		}
+		if count == 0 {
+			continue
+		}
+
+		// With Breslow's approximation each tied event
+		// has the full risk set, and with Efron's the
+		// lth event has the risks of the tied events
+		// reduced by the fraction l/count.
+		terms := count
+		if m.ties == Breslow {
+			terms = 1
+		}
+		scale := dw / float64(terms)
+		for l := 0; l < terms; l++ {
+			f := float64(l) / float64(count)
+			den := s0 - f*a0
+			ll -= scale * math.Log(den)
+			for j := range z {
+				z[j] = (s1[j] - f*a1[j]) / den
+			}
+			if grad != nil {
+				for j, v := range z {
+					grad[j] -= scale * v
+				}
+			}
+			if info != nil {
+				for j := 0; j < p; j++ {
+					for k := j; k < p; k++ {
+						v := (s2[j*p+k]-f*a2[j*p+k])/den - z[j]*z[k]
+						info.SetSym(j, k, info.At(j, k)+scale*v)
+					}
+				}
+			}
+		}
+	}
+	return ll
+}
+
+func (c *Cox) checkOK() {
+	if !c.ok {
+		panic("survival: use of unsuccessful fit")
+	}
+}
+
+// Coefficients returns the estimated coefficients of the model.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients,
+// Coefficients will panic. Coefficients will also panic if the receiver
+// does not contain a successful fit.
+func (c *Cox) Coefficients(dst []float64) []float64 {
+	c.checkOK()
+	return copySlice(dst, c.coef)
+}
+
+// HazardRatios returns the estimated hazard ratios of the model, exp(βⱼ),
+// the ratios of the hazards of observations that differ by one in each
+// covariate.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients,
+// HazardRatios will panic. HazardRatios will also panic if the receiver
+// does not contain a successful fit.
+func (c *Cox) HazardRatios(dst []float64) []float64 {
+	dst = c.Coefficients(dst)
+	for i, v := range dst {
+		dst[i] = math.Exp(v)
+	}
+	return dst
+}
+
+// LogLikelihood returns the log partial likelihood of the fitted model.
+// LogLikelihood will panic if the receiver does not contain a successful
+// fit.
+func (c *Cox) LogLikelihood() float64 {
+	c.checkOK()
+	return c.loglik
+}
+
+// NullLogLikelihood returns the log partial likelihood of the model with
+// all coefficients zero. Twice the difference between LogLikelihood and
+// NullLogLikelihood is the likelihood ratio statistic of the null
+// hypothesis that all coefficients are zero. NullLogLikelihood will panic
+// if the receiver does not contain a successful fit.
+func (c *Cox) NullLogLikelihood() float64 {
+	c.checkOK()
+	return c.nullLoglik
+}
+
+// Iterations returns the number of major iterations of the optimization
+// used by the fit. Iterations will panic if the receiver does not contain
+// a successful fit.
+func (c *Cox) Iterations() int {
+	c.checkOK()
+	return c.iterations
+}
+
+// CovarianceMatrix stores the estimate of the covariance matrix of the
+// coefficients, the inverse of the observed information of the partial
+// likelihood, into dst.
+//
+// The dst matrix must either be empty or have the same number of rows as
+// the number of coefficients. CovarianceMatrix will panic if the receiver
+// does not contain a successful fit.
+func (c *Cox) CovarianceMatrix(dst *mat.SymDense) {
+	c.checkOK()
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(c.p)
+	} else if dst.SymmetricDim() != c.p {
+		panic(mat.ErrShape)
+	}
+	dst.CopySym(c.cov)
+}
+
+// StdErrs returns the standard errors of the coefficients of the model.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients, StdErrs
+// will panic. StdErrs will also panic if the receiver does not contain a
+// successful fit.
+func (c *Cox) StdErrs(dst []float64) []float64 {
+	c.checkOK()
+	dst = useSlice(dst, c.p)
+	for i := range dst {
+		dst[i] = math.Sqrt(c.cov.At(i, i))
+	}
+	return dst
+}
+
+// WaldStats returns the Wald statistics of the coefficients of the model,
+// the ratios of the coefficients to their standard errors.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients,
+// WaldStats will panic. WaldStats will also panic if the receiver does not
+// contain a successful fit.
+func (c *Cox) WaldStats(dst []float64) []float64 {
+	dst = c.StdErrs(dst)
+	for i, se := range dst {
+		dst[i] = c.coef[i] / se
+	}
+	return dst
+}
+
+// PValues returns the two-sided p-values of the Wald tests of the null
+// hypotheses that each coefficient of the model is zero, using the standard
+// normal distribution of the Wald statistics.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of coefficients, PValues
+// will panic. PValues will also panic if the receiver does not contain a
+// successful fit.
+func (c *Cox) PValues(dst []float64) []float64 {
+	dst = c.WaldStats(dst)
+	for i, v := range dst {
+		dst[i] = 2 * distuv.UnitNormal.Survival(math.Abs(v))
+	}
+	return dst
+}
@@ -0,0 +1,331 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package survival
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCoxAML(t *testing.T) {
+	t.Parallel()
+	// Reference values from coxph in R with the nonmaintained
+	// group as the covariate.
+	x := mat.NewDense(len(aml.group), 1, nil)
+	for i, g := range aml.group {
+		x.Set(i, 0, float64(g))
+	}
+	var c Cox
+	err := c.Fit(x, aml.times, aml.events, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := c.Coefficients(nil)[0]; !scalar.EqualWithinAbs(got, 0.9155, 5e-5) {
+		t.Errorf("unexpected coefficient: got:%v want:0.9155", got)
+	}
+	if got := c.HazardRatios(nil)[0]; !scalar.EqualWithinAbs(got, 2.498, 5e-4) {
+		t.Errorf("unexpected hazard ratio: got:%v want:2.498", got)
+	}
+	if got := c.StdErrs(nil)[0]; !scalar.EqualWithinAbs(got, 0.5119, 5e-5) {
+		t.Errorf("unexpected standard error: got:%v want:0.5119", got)
+	}
+	if got := c.WaldStats(nil)[0]; !scalar.EqualWithinAbs(got, 1.79, 5e-3) {
+		t.Errorf("unexpected Wald statistic: got:%v want:1.79", got)
+	}
+	if got := c.PValues(nil)[0]; !scalar.EqualWithinAbs(got, 0.074, 5e-4) {
+		t.Errorf("unexpected p-value: got:%v want:0.074", got)
+	}
+	if got := 2 * (c.LogLikelihood() - c.NullLogLikelihood()); !scalar.EqualWithinAbs(got, 3.38, 5e-3) {
+		t.Errorf("unexpected likelihood ratio statistic: got:%v want:3.38", got)
+	}
+}
+
+// coxData returns a sample from a proportional hazards model with
+// exponential baseline hazard and random censoring. If round is true the
+// times are rounded to give ties.
+func coxData(rnd *rand.Rand, n int, beta []float64, round bool) (x *mat.Dense, times []float64, events []bool) {
+	p := len(beta)
+	x = mat.NewDense(n, p, nil)
+	times = make([]float64, n)
+	events = make([]bool, n)
+	for i := 0; i < n; i++ {
+		var eta float64
+		for j := 0; j < p; j++ {
+			v := rnd.NormFloat64()
+			x.Set(i, j, v)
+			eta += beta[j] * v
+		}
+		tt := rnd.ExpFloat64() / math.Exp(eta)
+		c := 2 * rnd.ExpFloat64()
+		times[i] = math.Min(tt, c)
+		events[i] = tt <= c
+		if round {
+			times[i] = math.Ceil(4 * times[i])
+		}
+	}
+	return x, times, events
+}
+
+func TestCoxDerivatives(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	beta := []float64{0.5, -1, 0.25}
+	x, times, events := coxData(rnd, 60, beta, true)
+	weights := make([]float64, len(times))
+	for i := range weights {
+		weights[i] = float64(rnd.Intn(3))
+	}
+	for _, ties := range []Ties{Efron, Breslow} {
+		m := newCoxModel(x, times, events, weights, ties)
+		for trial := 0; trial < 5; trial++ {
+			b := make([]float64, len(beta))
+			for j := range b {
+				b[j] = rnd.NormFloat64()
+			}
+			grad := make([]float64, len(b))
+			info := mat.NewSymDense(len(b), nil)
+			m.logLikelihood(b, grad, info)
+			want := fd.Gradient(nil, func(b []float64) float64 {
+				return m.logLikelihood(b, nil, nil)
+			}, b, &fd.Settings{Formula: fd.Central})
+			if !floats.EqualApprox(grad, want, 1e-6) {
+				t.Errorf("unexpected gradient for ties %d: got:%v want:%v", ties, grad, want)
+			}
+			hess := mat.NewDense(len(b), len(b), nil)
+			fd.Jacobian(hess, func(dst, b []float64) {
+				m.logLikelihood(b, dst, nil)
+				floats.Scale(-1, dst)
+			}, b, &fd.JacobianSettings{Formula: fd.Central})
+			if !mat.EqualApprox(info, hess, 1e-6) {
+				t.Errorf("unexpected information for ties %d:\ngot: %v\nwant:%v",
+					ties, mat.Formatted(info), mat.Formatted(hess))
+			}
+		}
+	}
+}
+
+func TestCoxTies(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	beta := []float64{0.5, -1}
+	x, times, events := coxData(rnd, 100, beta, false)
+
+	// Without ties the approximations are the same partial
+	// likelihood.
+	var efron, breslow Cox
+	breslow.Ties = Breslow
+	if err := efron.Fit(x, times, events, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := breslow.Fit(x, times, events, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(efron.Coefficients(nil), breslow.Coefficients(nil), 1e-10) {
+		t.Errorf("unexpected Breslow coefficients without ties: got:%v want:%v",
+			breslow.Coefficients(nil), efron.Coefficients(nil))
+	}
+
+	// Without ties the score test of a single binary covariate is
+	// the log-rank test.
+	groups := make([]int, len(times))
+	g := mat.NewDense(len(times), 1, nil)
+	for i := range groups {
+		if x.At(i, 0) > 0 {
+			groups[i] = 1
+			g.Set(i, 0, 1)
+		}
+	}
+	m := newCoxModel(g, times, events, nil, Efron)
+	grad := make([]float64, 1)
+	info := mat.NewSymDense(1, nil)
+	m.logLikelihood([]float64{0}, grad, info)
+	score := grad[0] * grad[0] / info.At(0, 0)
+	want := LogRank(times, events, groups, nil).Statistic
+	if !scalar.EqualWithinAbsOrRel(score, want, 1e-12, 1e-12) {
+		t.Errorf("unexpected score statistic: got:%v want:%v", score, want)
+	}
+}
+
+func TestCoxFit(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	beta := []float64{0.5, -1, 0.25}
+	x, times, events := coxData(rnd, 2000, beta, true)
+
+	for _, ties := range []Ties{Efron, Breslow} {
+		c := Cox{Ties: ties}
+		err := c.Fit(x, times, events, nil)
+		if err != nil {
+			t.Fatalf("unexpected error for ties %d: %v", ties, err)
+		}
+		m := newCoxModel(x, times, events, nil, ties)
+		grad := make([]float64, len(beta))
+		coef := c.Coefficients(nil)
+		m.logLikelihood(coef, grad, nil)
+		if floats.Norm(grad, 2) > 1e-6*m.events {
+			t.Errorf("gradient not zero at estimate for ties %d: %v", ties, grad)
+		}
+		se := c.StdErrs(nil)
+		// Breslow's approximation is biased towards zero
+		// when there are many ties.
+		if ties == Efron {
+			for j, b := range beta {
+				if math.Abs(coef[j]-b) > 4*se[j] {
+					t.Errorf("unexpected coefficient %d: got:%v±%v want:%v", j, coef[j], se[j], b)
+				}
+			}
+		}
+		var cov mat.SymDense
+		c.CovarianceMatrix(&cov)
+		for j := range se {
+			if !scalar.EqualWithinAbsOrRel(math.Sqrt(cov.At(j, j)), se[j], 1e-15, 1e-15) {
+				t.Errorf("standard error %d does not match covariance matrix for ties %d", j, ties)
+			}
+		}
+		if c.LogLikelihood() < c.NullLogLikelihood() {
+			t.Errorf("log likelihood less than null log likelihood for ties %d", ties)
+		}
+	}
+}
+
+func TestCoxScale(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, times, events := coxData(rnd, 200, []float64{0.5, -1}, true)
+	var want Cox
+	if err := want.Fit(x, times, events, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Scaling a covariate scales its coefficient inversely.
+	for _, scale := range []float64{1e-6, 1e6} {
+		var scaled mat.Dense
+		scaled.Scale(scale, x)
+		var c Cox
+		if err := c.Fit(&scaled, times, events, nil); err != nil {
+			t.Errorf("unexpected error for scale %v: %v", scale, err)
+			continue
+		}
+		got := c.Coefficients(nil)
+		floats.Scale(scale, got)
+		if !floats.EqualApprox(got, want.Coefficients(nil), 1e-6) {
+			t.Errorf("unexpected scaled coefficients for scale %v: got:%v want:%v", scale, got, want.Coefficients(nil))
+		}
+		if !scalar.EqualWithinAbsOrRel(c.LogLikelihood(), want.LogLikelihood(), 1e-10, 1e-10) {
+			t.Errorf("unexpected log likelihood for scale %v: got:%v want:%v", scale, c.LogLikelihood(), want.LogLikelihood())
+		}
+	}
+}
+
+func TestCoxWeights(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, times, events := coxData(rnd, 50, []float64{1, -0.5}, true)
+	weights := make([]float64, len(times))
+	var rep mat.Dense
+	var repTimes []float64
+	var repEvents []bool
+	var rows []int
+	for i := range weights {
+		weights[i] = float64(rnd.Intn(3))
+		for j := 0; j < int(weights[i]); j++ {
+			rows = append(rows, i)
+			repTimes = append(repTimes, times[i])
+			repEvents = append(repEvents, events[i])
+		}
+	}
+	rep.ReuseAs(len(rows), 2)
+	for r, i := range rows {
+		rep.SetRow(r, x.RawRowView(i))
+	}
+
+	// Integer weights are equivalent to replicated observations
+	// with Breslow's approximation. Efron's approximation with
+	// weights treats the tied events of an observation as a
+	// single event.
+	c := Cox{Ties: Breslow}
+	if err := c.Fit(x, times, events, weights); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Cox{Ties: Breslow}
+	if err := want.Fit(&rep, repTimes, repEvents, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(c.Coefficients(nil), want.Coefficients(nil), 1e-8) {
+		t.Errorf("unexpected coefficients: got:%v want:%v", c.Coefficients(nil), want.Coefficients(nil))
+	}
+	if !floats.EqualApprox(c.StdErrs(nil), want.StdErrs(nil), 1e-8) {
+		t.Errorf("unexpected standard errors: got:%v want:%v", c.StdErrs(nil), want.StdErrs(nil))
+	}
+	if !scalar.EqualWithinAbsOrRel(c.LogLikelihood(), want.LogLikelihood(), 1e-10, 1e-10) {
+		t.Errorf("unexpected log likelihood: got:%v want:%v", c.LogLikelihood(), want.LogLikelihood())
+	}
+}
+
+func TestCoxErrors(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(4, 1, []float64{1, 2, 3, 4})
+	var c Cox
+	err := c.Fit(x, []float64{1, 2, 3, 4}, []bool{false, false, false, false}, nil)
+	if err == nil {
+		t.Error("expected error for no events")
+	}
+	if !panics(func() { c.Coefficients(nil) }) {
+		t.Error("expected panic for use of unsuccessful fit")
+	}
+
+	// A collinear design has a singular information matrix.
+	x = mat.NewDense(6, 2, []float64{1, 2, 2, 4, 0, 0, 3, 6, 1, 2, 2, 4})
+	err = c.Fit(x, []float64{1, 2, 3, 4, 5, 6}, []bool{true, false, true, true, true, true}, nil)
+	if err == nil {
+		t.Error("expected error for collinear design")
+	}
+
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "ties", fn: func() { (&Cox{Ties: Breslow + 1}).Fit(x, make([]float64, 6), make([]bool, 6), nil) }},
+		{name: "times length", fn: func() { c.Fit(x, make([]float64, 5), make([]bool, 5), nil) }},
+		{name: "events length", fn: func() { c.Fit(x, make([]float64, 6), make([]bool, 5), nil) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func TestCoxSeparation(t *testing.T) {
+	t.Parallel()
+	times := []float64{1, 2, 3, 4, 5, 6}
+	events := []bool{true, true, true, true, true, true}
+	for _, test := range []struct {
+		name string
+		x    *mat.Dense
+		want bool
+	}{
+		// The covariate orders the event times perfectly, so the
+		// partial likelihood increases without bound with β.
+		{name: "decreasing", x: mat.NewDense(6, 1, []float64{6, 5, 4, 3, 2, 1}), want: true},
+		{name: "increasing", x: mat.NewDense(6, 1, []float64{1e-6, 2e-6, 3e-6, 4e-6, 5e-6, 6e-6}), want: true},
+		{name: "combination", x: mat.NewDense(6, 2, []float64{3, 3, 3, 2, 1, 2, 2, 0, 0, 1, 0, 0}), want: true},
+		// Exchanging a pair of covariates gives a finite maximum.
+		{name: "exchanged", x: mat.NewDense(6, 1, []float64{6, 5, 3, 4, 2, 1}), want: false},
+	} {
+		for _, ties := range []Ties{Efron, Breslow} {
+			c := Cox{Ties: ties}
+			err := c.Fit(test.x, times, events, nil)
+			if got := err != nil; got != test.want {
+				t.Errorf("unexpected error for %s with ties %d: got:%v want error:%t", test.name, ties, err, test.want)
+			}
+		}
+	}
+}
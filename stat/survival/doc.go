@@ -0,0 +1,15 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package survival provides methods for the analysis of time-to-event data.
+//
+// The observations of a survival analysis are times at which either the
+// event of interest was observed or the observation was right censored,
+// so that the event is only known to occur after that time. The
+// KaplanMeier and NelsonAalen types estimate the survival function and
+// the cumulative hazard function non-parametrically, LogRank tests the
+// equality of the survival functions of several groups, Cox fits the
+// proportional hazards regression model and AFT fits parametric
+// accelerated failure time regression models.
+package survival // import "gonum.org/v1/gonum/stat/survival"
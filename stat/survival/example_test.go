@@ -0,0 +1,111 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package survival_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/survival"
+)
+
+// The weeks of remission of patients with acute myelogenous leukaemia
+// who did and did not receive maintenance chemotherapy, and whether the
+// relapse was observed or the observation was censored.
+var (
+	weeks = []float64{
+		9, 13, 13, 18, 23, 28, 31, 34, 45, 48, 161,
+		5, 5, 8, 8, 12, 16, 23, 27, 30, 33, 43, 45,
+	}
+	relapsed = []bool{
+		true, true, false, true, true, false, true, true, false, true, false,
+		true, true, true, true, true, false, true, true, true, true, true, true,
+	}
+	nonmaintained = []int{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	}
+)
+
+func ExampleKaplanMeier() {
+	// The maintained patients.
+	km := survival.NewKaplanMeier(weeks[:11], relapsed[:11], nil)
+
+	fmt.Println("week  at risk  survival  std err  95% interval")
+	risk := km.AtRisk(nil)
+	for i, t := range km.Times(nil) {
+		lower, upper := km.ConfidenceInterval(t, 0.95, survival.Log)
+		fmt.Printf("%4v  %7v  %8.3f  %7.4f  [%.3f, %.3f]\n",
+			t, risk[i], km.Survival(t), km.StdErr(t), lower, upper)
+	}
+	fmt.Printf("median remission = %v weeks\n", km.Quantile(0.5))
+
+	// Output:
+	// week  at risk  survival  std err  95% interval
+	//    9       11     0.909   0.0867  [0.754, 1.000]
+	//   13       10     0.818   0.1163  [0.619, 1.000]
+	//   18        8     0.716   0.1397  [0.488, 1.000]
+	//   23        7     0.614   0.1526  [0.377, 0.999]
+	//   31        5     0.491   0.1642  [0.255, 0.946]
+	//   34        4     0.368   0.1627  [0.155, 0.875]
+	//   48        2     0.184   0.1535  [0.036, 0.944]
+	// median remission = 31 weeks
+}
+
+func ExampleLogRank() {
+	res := survival.LogRank(weeks, relapsed, nonmaintained, nil)
+	fmt.Printf("chi-square = %.2f on %v degrees of freedom, p = %.3f\n", res.Statistic, res.DF, res.P)
+
+	// Output:
+	// chi-square = 3.40 on 1 degrees of freedom, p = 0.065
+}
+
+func ExampleCox() {
+	x := mat.NewDense(len(nonmaintained), 1, nil)
+	for i, g := range nonmaintained {
+		x.Set(i, 0, float64(g))
+	}
+	var c survival.Cox
+	err := c.Fit(x, weeks, relapsed, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	coef := c.Coefficients(nil)
+	hr := c.HazardRatios(nil)
+	se := c.StdErrs(nil)
+	p := c.PValues(nil)
+	fmt.Printf("coefficient = %.4f, hazard ratio = %.3f, std err = %.4f, p = %.4f\n", coef[0], hr[0], se[0], p[0])
+	fmt.Printf("likelihood ratio = %.2f\n", 2*(c.LogLikelihood()-c.NullLogLikelihood()))
+
+	// Output:
+	// coefficient = 0.9155, hazard ratio = 2.498, std err = 0.5119, p = 0.0737
+	// likelihood ratio = 3.38
+}
+
+func ExampleAFT() {
+	x := mat.NewDense(len(nonmaintained), 1, nil)
+	for i, g := range nonmaintained {
+		x.Set(i, 0, float64(g))
+	}
+	var m survival.AFT
+	err := m.Fit(x, weeks, relapsed, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	coef := m.Coefficients(nil)
+	se := m.StdErrs(nil)
+	for i, name := range []string{"intercept", "nonmaintained"} {
+		fmt.Printf("%-13s  %6.3f  (%.3f)\n", name, coef[i], se[i])
+	}
+	fmt.Printf("scale = %.3f\n", m.Scale())
+	fmt.Printf("log likelihood = %.2f\n", m.LogLikelihood())
+
+	// Output:
+	// intercept       4.109  (0.300)
+	// nonmaintained  -0.929  (0.383)
+	// scale = 0.791
+	// log likelihood = -80.52
+}
@@ -0,0 +1,164 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package survival
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/gonum/stat/hypothesis"
+)
+
+// LogRank performs the log-rank test of the null hypothesis that the
+// survival functions of k groups of right censored times are equal. The
+// event of observation i was observed at times[i] if events[i] is true,
+// and the observation was censored at times[i] otherwise, and the group of
+// the observation is groups[i]. The groups are numbered from zero.
+//
+// At each distinct event time the expected number of events in each group
+// under the null hypothesis is the total number of events at that time
+// shared in proportion to the numbers at risk in the groups. The statistic
+// is the quadratic form of the differences between the observed and
+// expected numbers of events in the first k-1 groups with the inverse of
+// their hypergeometric covariance matrix, and it is compared to the
+// chi-square distribution with k-1 degrees of freedom. The Estimate, Lower
+// and Upper fields of the result are NaN. The Statistic and P fields are
+// NaN if the covariance matrix is singular, which happens if the events
+// provide no information about one of the groups.
+//
+// The weights slice is used to weight the observations. If weights is nil,
+// each weight is considered to have a value of one, otherwise the length of
+// weights must match the length of times or LogRank will panic. LogRank
+// will also panic if the lengths of times, events and groups differ, if any
+// time is NaN, if any weight is negative, or if there are fewer than two
+// groups, a negative group or an unobserved group.
+//
+// References:
+//   - Mantel, N. (1966). Evaluation of survival data and two new rank
+//     order statistics arising in its consideration. Cancer Chemotherapy
+//     Reports, 50(3), 163-170.
+//   - Peto, R. and Peto, J. (1972). Asymptotically efficient rank invariant
+//     test procedures. Journal of the Royal Statistical Society, Series A,
+//     135(2), 185-207.
+func LogRank(times []float64, events []bool, groups []int, weights []float64) hypothesis.Result {
+	checkSample(times, events, weights)
+	if len(groups) != len(times) {
+		panic("survival: slice length mismatch")
+	}
+	k := numGroups(groups)
+	idx := sortedIndices(times)
+
+	// risk holds the numbers at risk in each group and
+	// is reduced as the event times are passed.
+	risk := make([]float64, k)
+	for i, g := range groups {
+		risk[g] += weight(weights, i)
+	}
+	var total float64
+	for _, r := range risk {
+		total += r
+	}
+
+	// u holds the observed less the expected numbers of
+	// events and v their covariance matrix.
+	u := make([]float64, k)
+	v := mat.NewSymDense(k, nil)
+	d := make([]float64, k)
+	c := make([]float64, k)
+	for i := 0; i < len(idx); {
+		t := times[idx[i]]
+		for j := range d {
+			d[j] = 0
+			c[j] = 0
+		}
+		for ; i < len(idx) && times[idx[i]] == t; i++ {
+			g := groups[idx[i]]
+			w := weight(weights, idx[i])
+			if events[idx[i]] {
+				d[g] += w
+			} else {
+				c[g] += w
+			}
+		}
+		var dt float64
+		for _, dg := range d {
+			dt += dg
+		}
+		if dt > 0 && total > 0 {
+			for g := range u {
+				u[g] += d[g] - dt*risk[g]/total
+			}
+			if total > 1 {
+				f := dt * (total - dt) / (total - 1)
+				for g := 0; g < k; g++ {
+					pg := risk[g] / total
+					for h := g; h < k; h++ {
+						ph := risk[h] / total
+						cov := -f * pg * ph
+						if g == h {
+							cov += f * pg
+						}
+						v.SetSym(g, h, v.At(g, h)+cov)
+					}
+				}
+			}
+		}
+		for g := range risk {
+			risk[g] -= d[g] + c[g]
+			total -= d[g] + c[g]
+		}
+	}
+
+	df := k - 1
+	res := hypothesis.Result{
+		Statistic: math.NaN(),
+		DF:        float64(df),
+		P:         math.NaN(),
+		Estimate:  math.NaN(),
+		Lower:     math.NaN(),
+		Upper:     math.NaN(),
+	}
+	var chol mat.Cholesky
+	if !chol.Factorize(v.SliceSym(0, df).(*mat.SymDense)) {
+		return res
+	}
+	var x mat.VecDense
+	ud := mat.NewVecDense(df, u[:df])
+	err := chol.SolveVecTo(&x, ud)
+	if err != nil {
+		return res
+	}
+	res.Statistic = mat.Dot(ud, &x)
+	res.P = distuv.ChiSquared{K: float64(df)}.Survival(res.Statistic)
+	return res
+}
+
+// numGroups returns the number of groups numbered by groups, and panics if
+// there are fewer than two groups, a negative group or an unobserved group.
+func numGroups(groups []int) int {
+	var n int
+	for _, g := range groups {
+		if g < 0 {
+			panic("survival: negative group")
+		}
+		if g >= n {
+			n = g + 1
+		}
+	}
+	if n < 2 {
+		panic("survival: fewer than two groups")
+	}
+	seen := make([]bool, n)
+	for _, g := range groups {
+		seen[g] = true
+	}
+	for _, ok := range seen {
+		if !ok {
+			panic("survival: unobserved group")
+		}
+	}
+	return n
+}
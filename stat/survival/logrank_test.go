@@ -0,0 +1,141 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package survival
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+func TestLogRank(t *testing.T) {
+	t.Parallel()
+	// Reference values from survdiff in R.
+	res := LogRank(aml.times, aml.events, aml.group, nil)
+	if !scalar.EqualWithinAbs(res.Statistic, 3.4, 0.05) {
+		t.Errorf("unexpected statistic: got:%v want:3.4", res.Statistic)
+	}
+	if !scalar.EqualWithinAbs(res.P, 0.07, 0.005) {
+		t.Errorf("unexpected p-value: got:%v want:0.07", res.P)
+	}
+	if res.DF != 1 {
+		t.Errorf("unexpected degrees of freedom: got:%v want:1", res.DF)
+	}
+	if !math.IsNaN(res.Estimate) || !math.IsNaN(res.Lower) || !math.IsNaN(res.Upper) {
+		t.Errorf("unexpected estimate: got:%v [%v, %v] want:NaN [NaN, NaN]", res.Estimate, res.Lower, res.Upper)
+	}
+
+	// The statistic does not depend on the numbering of the groups.
+	swapped := make([]int, len(aml.group))
+	for i, g := range aml.group {
+		swapped[i] = 1 - g
+	}
+	got := LogRank(aml.times, aml.events, swapped, nil)
+	if !scalar.EqualWithinAbsOrRel(got.Statistic, res.Statistic, 1e-12, 1e-12) {
+		t.Errorf("unexpected statistic for swapped groups: got:%v want:%v", got.Statistic, res.Statistic)
+	}
+}
+
+func TestLogRankTwoGroups(t *testing.T) {
+	t.Parallel()
+	// For two groups the statistic is (O-E)²/V with the expected
+	// numbers of events and the hypergeometric variances summed
+	// over the event times.
+	var o, e, v float64
+	risk := [2]float64{11, 12}
+	times := []float64{5, 8, 9, 12, 13, 16, 18, 23, 27, 28, 30, 31, 33, 34, 43, 45, 48, 161}
+	for _, tt := range times {
+		var d, c [2]float64
+		for i, x := range aml.times {
+			if x != tt {
+				continue
+			}
+			if aml.events[i] {
+				d[aml.group[i]]++
+			} else {
+				c[aml.group[i]]++
+			}
+		}
+		n := risk[0] + risk[1]
+		dt := d[0] + d[1]
+		if dt > 0 {
+			o += d[0]
+			e += dt * risk[0] / n
+			v += dt * (risk[0] / n) * (risk[1] / n) * (n - dt) / (n - 1)
+		}
+		for g := range risk {
+			risk[g] -= d[g] + c[g]
+		}
+	}
+	want := (o - e) * (o - e) / v
+	if !scalar.EqualWithinAbs(e, 10.69, 0.005) {
+		t.Errorf("unexpected expected events: got:%v want:10.69", e)
+	}
+	res := LogRank(aml.times, aml.events, aml.group, nil)
+	if !scalar.EqualWithinAbsOrRel(res.Statistic, want, 1e-12, 1e-12) {
+		t.Errorf("unexpected statistic: got:%v want:%v", res.Statistic, want)
+	}
+}
+
+func TestLogRankGroups(t *testing.T) {
+	t.Parallel()
+	times := []float64{6, 6, 6, 7, 10, 13, 16, 22, 23, 6, 9, 10, 11, 17, 19, 20, 25, 32, 1, 1, 2, 2, 3, 4, 4, 5, 5}
+	events := []bool{true, true, true, true, true, true, true, true, true, false, false, true, false, false, false, false, false, false, true, true, true, true, true, true, true, true, true}
+	groups := []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 2, 2, 2, 2, 2}
+	res := LogRank(times, events, groups, nil)
+	if res.DF != 2 {
+		t.Errorf("unexpected degrees of freedom: got:%v want:2", res.DF)
+	}
+	if !(res.Statistic > 0) || !(0 < res.P && res.P < 1) {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	// Weights of two are equivalent to duplicated observations.
+	weights := make([]float64, len(times))
+	for i := range weights {
+		weights[i] = 2
+	}
+	got := LogRank(times, events, groups, weights)
+	want := LogRank(append(times, times...), append(events, events...), append(groups, groups...), nil)
+	if !scalar.EqualWithinAbsOrRel(got.Statistic, want.Statistic, 1e-12, 1e-12) {
+		t.Errorf("unexpected statistic for weights: got:%v want:%v", got.Statistic, want.Statistic)
+	}
+
+	// Permuting the group labels does not change the statistic.
+	perm := []int{2, 0, 1}
+	permuted := make([]int, len(groups))
+	for i, g := range groups {
+		permuted[i] = perm[g]
+	}
+	got = LogRank(times, events, permuted, nil)
+	if !scalar.EqualWithinAbsOrRel(got.Statistic, res.Statistic, 1e-12, 1e-12) {
+		t.Errorf("unexpected statistic for permuted groups: got:%v want:%v", got.Statistic, res.Statistic)
+	}
+
+	// A group with no observations at risk at any event time
+	// gives a singular covariance matrix.
+	got = LogRank([]float64{1, 2, 3, 0.5}, []bool{true, true, false, false}, []int{0, 0, 1, 2}, nil)
+	if !math.IsNaN(got.Statistic) || !math.IsNaN(got.P) {
+		t.Errorf("unexpected result for singular covariance: got:%+v", got)
+	}
+}
+
+func TestLogRankPanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name   string
+		groups []int
+	}{
+		{name: "length", groups: []int{0, 1}},
+		{name: "one group", groups: []int{0, 0, 0}},
+		{name: "negative group", groups: []int{0, -1, 1}},
+		{name: "unobserved group", groups: []int{0, 2, 2}},
+	} {
+		if !panics(func() { LogRank([]float64{1, 2, 3}, []bool{true, true, true}, test.groups, nil) }) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,453 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package survival
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Transform specifies the scale on which the pointwise confidence interval
+// for a survival probability is constructed from its standard error. The
+// intervals are symmetric on the transformed scale and are transformed back
+// to the probability scale.
+type Transform int
+
+const (
+	// Plain constructs the interval S ± z se(S) on the scale of
+	// the survival probability, truncated to [0, 1].
+	Plain Transform = iota
+	// Log constructs the interval on the scale of log S, giving
+	// S exp(±z se(log S)), truncated above at one.
+	Log
+	// LogLog constructs the interval on the scale of log(-log S),
+	// giving S^exp(±z se(log S)/log S). The interval is always
+	// within [0, 1].
+	LogLog
+)
+
+// table holds the numbers at risk and the numbers of events at the
+// distinct event times of a sample.
+type table struct {
+	times  []float64
+	atRisk []float64
+	events []float64
+}
+
+// newTable returns the table of the distinct event times of the sample.
+// Observations censored at an event time are considered to be at risk at
+// that time.
+func newTable(times []float64, events []bool, weights []float64) table {
+	checkSample(times, events, weights)
+	idx := sortedIndices(times)
+
+	// The numbers at risk are accumulated from the largest
+	// time so that they are not affected by the rounding of
+	// repeated subtraction.
+	var (
+		tab  table
+		risk float64
+	)
+	for j := len(idx); j > 0; {
+		t := times[idx[j-1]]
+		var d float64
+		for ; j > 0 && times[idx[j-1]] == t; j-- {
+			w := weight(weights, idx[j-1])
+			risk += w
+			if events[idx[j-1]] {
+				d += w
+			}
+		}
+		if d > 0 {
+			tab.times = append(tab.times, t)
+			tab.atRisk = append(tab.atRisk, risk)
+			tab.events = append(tab.events, d)
+		}
+	}
+	reverse(tab.times)
+	reverse(tab.atRisk)
+	reverse(tab.events)
+	return tab
+}
+
+// index returns the number of event times in the table that are not
+// after t.
+func (tab *table) index(t float64) int {
+	return sort.Search(len(tab.times), func(i int) bool { return tab.times[i] > t })
+}
+
+// KaplanMeier is the Kaplan-Meier product-limit estimate of the survival
+// function of a sample of right censored times,
+//
+//	S(t) = ∏_{tᵢ ≤ t} (1 - dᵢ/nᵢ),
+//
+// where dᵢ is the number of events and nᵢ is the number at risk at the
+// distinct event time tᵢ. The variance of the estimate is estimated by
+// Greenwood's formula,
+//
+//	Var(S(t)) = S(t)² Σ_{tᵢ ≤ t} dᵢ/(nᵢ(nᵢ-dᵢ)).
+//
+// References:
+//   - Kaplan, E. L. and Meier, P. (1958). Nonparametric estimation from
+//     incomplete observations. Journal of the American Statistical
+//     Association, 53(282), 457-481.
+//   - Greenwood, M. (1926). The natural duration of cancer. Reports on
+//     Public Health and Medical Subjects, 33, 1-26.
+type KaplanMeier struct {
+	table
+	surv []float64
+	// greenwood holds the sums of Greenwood's formula,
+	// the variances of log S.
+	greenwood []float64
+}
+
+// NewKaplanMeier returns the Kaplan-Meier estimate of the survival function
+// of the sample of times. The event of observation i was observed at
+// times[i] if events[i] is true, and the observation was censored at
+// times[i] otherwise. Observations censored at an event time are considered
+// to be at risk at that time.
+//
+// The weights slice is used to weight the observations. If weights is nil,
+// each weight is considered to have a value of one, otherwise the length of
+// weights must match the length of times or NewKaplanMeier will panic.
+// NewKaplanMeier will also panic if the lengths of times and events differ,
+// if any time is NaN or if any weight is negative.
+func NewKaplanMeier(times []float64, events []bool, weights []float64) *KaplanMeier {
+	km := &KaplanMeier{table: newTable(times, events, weights)}
+	km.surv = make([]float64, len(km.times))
+	km.greenwood = make([]float64, len(km.times))
+	s := 1.0
+	var g float64
+	for i, n := range km.atRisk {
+		d := km.events[i]
+		s *= 1 - d/n
+		if d == n {
+			s = 0
+			g = math.Inf(1)
+		} else {
+			g += d / (n * (n - d))
+		}
+		km.surv[i] = s
+		km.greenwood[i] = g
+	}
+	return km
+}
+
+// Times returns the distinct event times of the sample in increasing order.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of distinct event times,
+// Times will panic.
+func (km *KaplanMeier) Times(dst []float64) []float64 {
+	return copySlice(dst, km.times)
+}
+
+// AtRisk returns the weighted numbers at risk at the distinct event times of
+// the sample.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of distinct event times,
+// AtRisk will panic.
+func (km *KaplanMeier) AtRisk(dst []float64) []float64 {
+	return copySlice(dst, km.atRisk)
+}
+
+// Events returns the weighted numbers of events at the distinct event times
+// of the sample.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of distinct event times,
+// Events will panic.
+func (km *KaplanMeier) Events(dst []float64) []float64 {
+	return copySlice(dst, km.events)
+}
+
+// Survival returns the estimate of the survival function at t, the
+// probability that the event occurs after t.
+func (km *KaplanMeier) Survival(t float64) float64 {
+	i := km.index(t)
+	if i == 0 {
+		return 1
+	}
+	return km.surv[i-1]
+}
+
+// StdErr returns the Greenwood standard error of the estimate of the
+// survival function at t. StdErr returns NaN if the estimate is zero
+// because all observations at risk at an event time had the event.
+func (km *KaplanMeier) StdErr(t float64) float64 {
+	i := km.index(t)
+	if i == 0 {
+		return 0
+	}
+	return km.surv[i-1] * math.Sqrt(km.greenwood[i-1])
+}
+
+// ConfidenceInterval returns the bounds of the pointwise confidence interval
+// for the survival function at t at the given confidence level, constructed
+// from the Greenwood standard error on the scale specified by tr. The
+// interval is degenerate at the estimate if the estimate is zero or one.
+//
+// ConfidenceInterval will panic if level is not in (0, 1) or tr is not a
+// valid Transform.
+func (km *KaplanMeier) ConfidenceInterval(t, level float64, tr Transform) (lower, upper float64) {
+	z := normalQuantile(level)
+	checkTransform(tr)
+	i := km.index(t)
+	if i == 0 {
+		return 1, 1
+	}
+	return interval(km.surv[i-1], math.Sqrt(km.greenwood[i-1]), z, tr)
+}
+
+// Quantile returns the p quantile of the estimated distribution of the
+// event times, the smallest event time at which the estimate of the
+// survival function is not greater than 1-p. Quantile returns NaN if the
+// estimate of the survival function does not fall to 1-p. The median
+// survival time is the 0.5 quantile.
+//
+// Quantile will panic if p is not in [0, 1].
+func (km *KaplanMeier) Quantile(p float64) float64 {
+	if !(0 <= p && p <= 1) {
+		panic("survival: probability out of range")
+	}
+	for i, s := range km.surv {
+		if s <= 1-p {
+			return km.times[i]
+		}
+	}
+	return math.NaN()
+}
+
+// NelsonAalen is the Nelson-Aalen estimate of the cumulative hazard
+// function of a sample of right censored times,
+//
+//	H(t) = Σ_{tᵢ ≤ t} dᵢ/nᵢ,
+//
+// where dᵢ is the number of events and nᵢ is the number at risk at the
+// distinct event time tᵢ. The variance of the estimate is estimated by
+// Σ_{tᵢ ≤ t} dᵢ/nᵢ².
+//
+// References:
+//   - Nelson, W. (1972). Theory and applications of hazard plotting for
+//     censored failure data. Technometrics, 14(4), 945-966.
+//   - Aalen, O. (1978). Nonparametric inference for a family of counting
+//     processes. The Annals of Statistics, 6(4), 701-726.
+type NelsonAalen struct {
+	table
+	hazard   []float64
+	variance []float64
+}
+
+// NewNelsonAalen returns the Nelson-Aalen estimate of the cumulative hazard
+// function of the sample of times. The event of observation i was observed
+// at times[i] if events[i] is true, and the observation was censored at
+// times[i] otherwise. Observations censored at an event time are considered
+// to be at risk at that time.
+//
+// The weights slice is used to weight the observations. If weights is nil,
+// each weight is considered to have a value of one, otherwise the length of
+// weights must match the length of times or NewNelsonAalen will panic.
+// NewNelsonAalen will also panic if the lengths of times and events differ,
+// if any time is NaN or if any weight is negative.
+func NewNelsonAalen(times []float64, events []bool, weights []float64) *NelsonAalen {
+	na := &NelsonAalen{table: newTable(times, events, weights)}
+	na.hazard = make([]float64, len(na.times))
+	na.variance = make([]float64, len(na.times))
+	var h, v float64
+	for i, n := range na.atRisk {
+		d := na.events[i]
+		h += d / n
+		v += d / (n * n)
+		na.hazard[i] = h
+		na.variance[i] = v
+	}
+	return na
+}
+
+// Times returns the distinct event times of the sample in increasing order.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of distinct event times,
+// Times will panic.
+func (na *NelsonAalen) Times(dst []float64) []float64 {
+	return copySlice(dst, na.times)
+}
+
+// AtRisk returns the weighted numbers at risk at the distinct event times of
+// the sample.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of distinct event times,
+// AtRisk will panic.
+func (na *NelsonAalen) AtRisk(dst []float64) []float64 {
+	return copySlice(dst, na.atRisk)
+}
+
+// Events returns the weighted numbers of events at the distinct event times
+// of the sample.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal the number of distinct event times,
+// Events will panic.
+func (na *NelsonAalen) Events(dst []float64) []float64 {
+	return copySlice(dst, na.events)
+}
+
+// CumulativeHazard returns the estimate of the cumulative hazard function
+// at t.
+func (na *NelsonAalen) CumulativeHazard(t float64) float64 {
+	i := na.index(t)
+	if i == 0 {
+		return 0
+	}
+	return na.hazard[i-1]
+}
+
+// Survival returns the Fleming-Harrington estimate of the survival function
+// at t, exp(-H(t)).
+func (na *NelsonAalen) Survival(t float64) float64 {
+	return math.Exp(-na.CumulativeHazard(t))
+}
+
+// StdErr returns the standard error of the estimate of the cumulative
+// hazard function at t.
+func (na *NelsonAalen) StdErr(t float64) float64 {
+	i := na.index(t)
+	if i == 0 {
+		return 0
+	}
+	return math.Sqrt(na.variance[i-1])
+}
+
+// ConfidenceInterval returns the bounds of the pointwise confidence interval
+// for the cumulative hazard function at t at the given confidence level.
+// The interval is the image under -log of the interval for the survival
+// function exp(-H) on the scale specified by tr, so that Log gives the
+// interval H ± z se(H) truncated below at zero and LogLog gives the interval
+// H exp(±z se(H)/H). The interval is degenerate at the estimate if the
+// estimate is zero.
+//
+// ConfidenceInterval will panic if level is not in (0, 1) or tr is not a
+// valid Transform.
+func (na *NelsonAalen) ConfidenceInterval(t, level float64, tr Transform) (lower, upper float64) {
+	z := normalQuantile(level)
+	checkTransform(tr)
+	i := na.index(t)
+	if i == 0 {
+		return 0, 0
+	}
+	h := na.hazard[i-1]
+	sl, su := interval(math.Exp(-h), math.Sqrt(na.variance[i-1]), z, tr)
+	return -math.Log(su), -math.Log(sl)
+}
+
+// interval returns the bounds of the confidence interval for the survival
+// probability s on the scale specified by tr, where sigma is the standard
+// error of log s and z is the normal quantile of the confidence level.
+func interval(s, sigma, z float64, tr Transform) (lower, upper float64) {
+	if s == 0 || s == 1 || sigma == 0 {
+		return s, s
+	}
+	switch tr {
+	case Plain:
+		se := s * sigma
+		return math.Max(0, s-z*se), math.Min(1, s+z*se)
+	case Log:
+		return s * math.Exp(-z*sigma), math.Min(1, s*math.Exp(z*sigma))
+	case LogLog:
+		logS := math.Log(s)
+		theta := math.Log(-logS)
+		sd := sigma / -logS
+		return math.Exp(-math.Exp(theta + z*sd)), math.Exp(-math.Exp(theta - z*sd))
+	default:
+		panic("survival: invalid transform")
+	}
+}
+
+// normalQuantile returns the upper quantile of the standard normal
+// distribution for a two-sided interval at the confidence level, and
+// panics if level is not in (0, 1).
+func normalQuantile(level float64) float64 {
+	if !(0 < level && level < 1) {
+		panic("survival: confidence level out of range")
+	}
+	return distuv.UnitNormal.Quantile(1 - (1-level)/2)
+}
+
+// checkTransform panics if tr is not a valid transform.
+func checkTransform(tr Transform) {
+	if tr < Plain || LogLog < tr {
+		panic("survival: invalid transform")
+	}
+}
+
+// checkSample panics if the times, events and weights of a sample have
+// mismatched lengths, if a time is NaN or if a weight is negative.
+func checkSample(times []float64, events []bool, weights []float64) {
+	if len(events) != len(times) {
+		panic("survival: slice length mismatch")
+	}
+	if weights != nil && len(weights) != len(times) {
+		panic("survival: slice length mismatch")
+	}
+	for _, t := range times {
+		if math.IsNaN(t) {
+			panic("survival: NaN time")
+		}
+	}
+	for _, w := range weights {
+		if w < 0 {
+			panic("survival: negative weight")
+		}
+	}
+}
+
+// sortedIndices returns the indices of times in increasing order of time.
+func sortedIndices(times []float64) []int {
+	idx := make([]int, len(times))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return times[idx[i]] < times[idx[j]] })
+	return idx
+}
+
+// weight returns the weight of observation i, one if weights is nil.
+func weight(weights []float64, i int) float64 {
+	if weights == nil {
+		return 1
+	}
+	return weights[i]
+}
+
+// reverse reverses the order of the elements of s.
+func reverse(s []float64) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// copySlice copies src into dst, allocating if dst is nil and panicking if
+// dst is not nil and has a different length, and returns dst.
+func copySlice(dst, src []float64) []float64 {
+	dst = useSlice(dst, len(src))
+	copy(dst, src)
+	return dst
+}
+
+// useSlice returns a slice of length n, allocating if dst is nil and
+// panicking if dst is not nil and has a different length.
+func useSlice(dst []float64, n int) []float64 {
+	if dst == nil {
+		return make([]float64, n)
+	}
+	if len(dst) != n {
+		panic("survival: destination length mismatch")
+	}
+	return dst
+}
@@ -0,0 +1,284 @@
+// Copyright ©2023 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package survival
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+// aml is the acute myelogenous leukaemia data of Miller (1981), with the
+// maintained group first.
+var aml = struct {
+	times  []float64
+	events []bool
+	group  []int
+}{
+	times: []float64{
+		9, 13, 13, 18, 23, 28, 31, 34, 45, 48, 161,
+		5, 5, 8, 8, 12, 16, 23, 27, 30, 33, 43, 45,
+	},
+	events: []bool{
+		true, true, false, true, true, false, true, true, false, true, false,
+		true, true, true, true, true, false, true, true, true, true, true, true,
+	},
+	group: []int{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	},
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		r := recover()
+		panicked = r != nil
+	}()
+	fn()
+	return
+}
+
+func TestKaplanMeier(t *testing.T) {
+	t.Parallel()
+	// Reference values of the maintained group from survfit in R.
+	times := aml.times[:11]
+	events := aml.events[:11]
+	km := NewKaplanMeier(times, events, nil)
+
+	wantTimes := []float64{9, 13, 18, 23, 31, 34, 48}
+	wantRisk := []float64{11, 10, 8, 7, 5, 4, 2}
+	wantEvents := []float64{1, 1, 1, 1, 1, 1, 1}
+	if got := km.Times(nil); !floats.Equal(got, wantTimes) {
+		t.Errorf("unexpected times: got:%v want:%v", got, wantTimes)
+	}
+	if got := km.AtRisk(nil); !floats.Equal(got, wantRisk) {
+		t.Errorf("unexpected numbers at risk: got:%v want:%v", got, wantRisk)
+	}
+	if got := km.Events(nil); !floats.Equal(got, wantEvents) {
+		t.Errorf("unexpected numbers of events: got:%v want:%v", got, wantEvents)
+	}
+
+	for _, test := range []struct {
+		t            float64
+		surv, se     float64
+		lower, upper float64
+	}{
+		{t: 9, surv: 0.909, se: 0.0867, lower: 0.7541, upper: 1},
+		{t: 13, surv: 0.818, se: 0.1163, lower: 0.6192, upper: 1},
+		{t: 18, surv: 0.716, se: 0.1397, lower: 0.4884, upper: 1},
+		{t: 23, surv: 0.614, se: 0.1526, lower: 0.3769, upper: 0.999},
+		{t: 31, surv: 0.491, se: 0.1642, lower: 0.2549, upper: 0.946},
+		{t: 34, surv: 0.368, se: 0.1627, lower: 0.1549, upper: 0.875},
+		{t: 48, surv: 0.184, se: 0.1535, lower: 0.0359, upper: 0.944},
+	} {
+		for _, tt := range []float64{test.t, test.t + 0.5} {
+			if got := km.Survival(tt); !scalar.EqualWithinAbs(got, test.surv, 5e-4) {
+				t.Errorf("unexpected survival at %v: got:%v want:%v", tt, got, test.surv)
+			}
+			if got := km.StdErr(tt); !scalar.EqualWithinAbs(got, test.se, 5e-5) {
+				t.Errorf("unexpected standard error at %v: got:%v want:%v", tt, got, test.se)
+			}
+			lower, upper := km.ConfidenceInterval(tt, 0.95, Log)
+			if !scalar.EqualWithinAbs(lower, test.lower, 5e-4) || !scalar.EqualWithinAbs(upper, test.upper, 5e-4) {
+				t.Errorf("unexpected confidence interval at %v: got:[%v, %v] want:[%v, %v]",
+					tt, lower, upper, test.lower, test.upper)
+			}
+		}
+	}
+	if got := km.Survival(8.9); got != 1 {
+		t.Errorf("unexpected survival before first event: got:%v want:1", got)
+	}
+	if got := km.StdErr(8.9); got != 0 {
+		t.Errorf("unexpected standard error before first event: got:%v want:0", got)
+	}
+	if lower, upper := km.ConfidenceInterval(8.9, 0.95, LogLog); lower != 1 || upper != 1 {
+		t.Errorf("unexpected confidence interval before first event: got:[%v, %v] want:[1, 1]", lower, upper)
+	}
+	if got := km.Quantile(0.5); got != 31 {
+		t.Errorf("unexpected median: got:%v want:31", got)
+	}
+	if got := km.Quantile(0.9); !math.IsNaN(got) {
+		t.Errorf("unexpected 0.9 quantile: got:%v want:NaN", got)
+	}
+}
+
+func TestKaplanMeierUncensored(t *testing.T) {
+	t.Parallel()
+	// Without censoring the Kaplan-Meier estimate is the empirical
+	// survival function and the Greenwood variance is the binomial
+	// variance S(1-S)/n.
+	rnd := rand.New(rand.NewSource(1))
+	const n = 50
+	times := make([]float64, n)
+	events := make([]bool, n)
+	for i := range times {
+		times[i] = float64(rnd.Intn(20))
+		events[i] = true
+	}
+	km := NewKaplanMeier(times, events, nil)
+	for _, tt := range km.Times(nil) {
+		var after float64
+		for _, v := range times {
+			if v > tt {
+				after++
+			}
+		}
+		s := after / n
+		if got := km.Survival(tt); !scalar.EqualWithinAbsOrRel(got, s, 1e-14, 1e-14) {
+			t.Errorf("unexpected survival at %v: got:%v want:%v", tt, got, s)
+		}
+		if s == 0 {
+			continue
+		}
+		se := math.Sqrt(s * (1 - s) / n)
+		if got := km.StdErr(tt); !scalar.EqualWithinAbsOrRel(got, se, 1e-14, 1e-12) {
+			t.Errorf("unexpected standard error at %v: got:%v want:%v", tt, got, se)
+		}
+	}
+	last := km.Times(nil)
+	if got := km.Survival(last[len(last)-1]); got != 0 {
+		t.Errorf("unexpected survival after last event: got:%v want:0", got)
+	}
+}
+
+func TestKaplanMeierWeights(t *testing.T) {
+	t.Parallel()
+	// Integer weights are equivalent to replicated observations.
+	times := []float64{3, 1, 4, 1, 5, 9, 2, 6, 5, 3}
+	events := []bool{true, false, true, true, true, false, true, true, false, true}
+	weights := []float64{2, 1, 3, 1, 0, 2, 1, 1, 2, 1}
+	var repTimes []float64
+	var repEvents []bool
+	for i, w := range weights {
+		for j := 0; j < int(w); j++ {
+			repTimes = append(repTimes, times[i])
+			repEvents = append(repEvents, events[i])
+		}
+	}
+	km := NewKaplanMeier(times, events, weights)
+	want := NewKaplanMeier(repTimes, repEvents, nil)
+	na := NewNelsonAalen(times, events, weights)
+	wantNA := NewNelsonAalen(repTimes, repEvents, nil)
+	if !floats.Equal(km.Times(nil), want.Times(nil)) {
+		t.Errorf("unexpected times: got:%v want:%v", km.Times(nil), want.Times(nil))
+	}
+	for tt := 0.0; tt < 10; tt += 0.5 {
+		if got, w := km.Survival(tt), want.Survival(tt); !scalar.EqualWithinAbsOrRel(got, w, 1e-14, 1e-14) {
+			t.Errorf("unexpected survival at %v: got:%v want:%v", tt, got, w)
+		}
+		if got, w := km.StdErr(tt), want.StdErr(tt); !scalar.EqualWithinAbsOrRel(got, w, 1e-14, 1e-14) {
+			t.Errorf("unexpected standard error at %v: got:%v want:%v", tt, got, w)
+		}
+		if got, w := na.CumulativeHazard(tt), wantNA.CumulativeHazard(tt); !scalar.EqualWithinAbsOrRel(got, w, 1e-14, 1e-14) {
+			t.Errorf("unexpected cumulative hazard at %v: got:%v want:%v", tt, got, w)
+		}
+		if got, w := na.StdErr(tt), wantNA.StdErr(tt); !scalar.EqualWithinAbsOrRel(got, w, 1e-14, 1e-14) {
+			t.Errorf("unexpected cumulative hazard standard error at %v: got:%v want:%v", tt, got, w)
+		}
+	}
+}
+
+func TestConfidenceInterval(t *testing.T) {
+	t.Parallel()
+	km := NewKaplanMeier(aml.times, aml.events, nil)
+	na := NewNelsonAalen(aml.times, aml.events, nil)
+	const level = 0.9
+	z := 1.6448536269514722
+	for _, tt := range km.Times(nil) {
+		s := km.Survival(tt)
+		if s == 0 {
+			continue
+		}
+		sigma := km.StdErr(tt) / s
+		for _, test := range []struct {
+			tr           Transform
+			lower, upper float64
+		}{
+			{tr: Plain, lower: math.Max(0, s-z*s*sigma), upper: math.Min(1, s+z*s*sigma)},
+			{tr: Log, lower: s * math.Exp(-z*sigma), upper: math.Min(1, s*math.Exp(z*sigma))},
+			{tr: LogLog, lower: math.Pow(s, math.Exp(-z*sigma/math.Log(s))), upper: math.Pow(s, math.Exp(z*sigma/math.Log(s)))},
+		} {
+			lower, upper := km.ConfidenceInterval(tt, level, test.tr)
+			if !scalar.EqualWithinAbsOrRel(lower, test.lower, 1e-14, 1e-12) || !scalar.EqualWithinAbsOrRel(upper, test.upper, 1e-14, 1e-12) {
+				t.Errorf("unexpected Kaplan-Meier interval at %v for transform %d: got:[%v, %v] want:[%v, %v]",
+					tt, test.tr, lower, upper, test.lower, test.upper)
+			}
+			if !(lower <= s && s <= upper) {
+				t.Errorf("Kaplan-Meier interval at %v for transform %d does not contain estimate", tt, test.tr)
+			}
+		}
+
+		h := na.CumulativeHazard(tt)
+		se := na.StdErr(tt)
+		for _, test := range []struct {
+			tr           Transform
+			lower, upper float64
+		}{
+			{tr: Log, lower: math.Max(0, h-z*se), upper: h + z*se},
+			{tr: LogLog, lower: h * math.Exp(-z*se/h), upper: h * math.Exp(z*se/h)},
+		} {
+			lower, upper := na.ConfidenceInterval(tt, level, test.tr)
+			if !scalar.EqualWithinAbsOrRel(lower, test.lower, 1e-14, 1e-12) || !scalar.EqualWithinAbsOrRel(upper, test.upper, 1e-14, 1e-12) {
+				t.Errorf("unexpected Nelson-Aalen interval at %v for transform %d: got:[%v, %v] want:[%v, %v]",
+					tt, test.tr, lower, upper, test.lower, test.upper)
+			}
+		}
+	}
+}
+
+func TestNelsonAalen(t *testing.T) {
+	t.Parallel()
+	times := aml.times[:11]
+	events := aml.events[:11]
+	na := NewNelsonAalen(times, events, nil)
+	risk := []float64{11, 10, 8, 7, 5, 4, 2}
+	var h, v float64
+	for i, tt := range na.Times(nil) {
+		h += 1 / risk[i]
+		v += 1 / (risk[i] * risk[i])
+		if got := na.CumulativeHazard(tt); !scalar.EqualWithinAbsOrRel(got, h, 1e-15, 1e-15) {
+			t.Errorf("unexpected cumulative hazard at %v: got:%v want:%v", tt, got, h)
+		}
+		if got := na.StdErr(tt); !scalar.EqualWithinAbsOrRel(got, math.Sqrt(v), 1e-15, 1e-15) {
+			t.Errorf("unexpected standard error at %v: got:%v want:%v", tt, got, math.Sqrt(v))
+		}
+		if got := na.Survival(tt); !scalar.EqualWithinAbsOrRel(got, math.Exp(-h), 1e-15, 1e-15) {
+			t.Errorf("unexpected survival at %v: got:%v want:%v", tt, got, math.Exp(-h))
+		}
+	}
+	if got := na.CumulativeHazard(1); got != 0 {
+		t.Errorf("unexpected cumulative hazard before first event: got:%v want:0", got)
+	}
+	if lower, upper := na.ConfidenceInterval(1, 0.95, Log); lower != 0 || upper != 0 {
+		t.Errorf("unexpected confidence interval before first event: got:[%v, %v] want:[0, 0]", lower, upper)
+	}
+}
+
+func TestEstimatorPanics(t *testing.T) {
+	t.Parallel()
+	km := NewKaplanMeier(aml.times, aml.events, nil)
+	na := NewNelsonAalen(aml.times, aml.events, nil)
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "events length", fn: func() { NewKaplanMeier([]float64{1, 2}, []bool{true}, nil) }},
+		{name: "weights length", fn: func() { NewNelsonAalen([]float64{1, 2}, []bool{true, true}, []float64{1}) }},
+		{name: "NaN time", fn: func() { NewKaplanMeier([]float64{1, math.NaN()}, []bool{true, true}, nil) }},
+		{name: "negative weight", fn: func() { NewKaplanMeier([]float64{1, 2}, []bool{true, true}, []float64{1, -1}) }},
+		{name: "level", fn: func() { km.ConfidenceInterval(10, 1, Log) }},
+		{name: "transform", fn: func() { na.ConfidenceInterval(10, 0.95, LogLog+1) }},
+		{name: "quantile", fn: func() { km.Quantile(-0.1) }},
+		{name: "dst length", fn: func() { km.Times(make([]float64, 1)) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}